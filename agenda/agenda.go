@@ -0,0 +1,186 @@
+// Package agenda составляет повестку дня: просроченные задачи, задачи на сегодня
+// и другие разделы на выбор - текстом для писем и чатов или страницей HTML для печати
+package agenda
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"taskmanager/task"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+var pageTemplate = template.Must(template.New("agenda.html").
+	Funcs(template.FuncMap{"priority": task.PriorityText}).
+	ParseFS(templateFS, "templates/agenda.html"))
+
+// emailTemplate - повестка для письма: стили заданы в атрибутах, потому что почтовые
+// программы часто отбрасывают блок <style>
+var emailTemplate = template.Must(template.New("email.html").
+	Funcs(template.FuncMap{"priority": task.PriorityText}).
+	ParseFS(templateFS, "templates/email.html"))
+
+// dateFormat - формат дней в повестке, совпадает с форматом сроков задач
+const dateFormat = "2006-01-02"
+
+// upcomingDays - на сколько дней вперед смотрит раздел «Скоро»
+const upcomingDays = 7
+
+// Section - раздел повестки
+type Section string
+
+const (
+	SectionOverdue  Section = "overdue"  // невыполненные задачи с прошедшим сроком
+	SectionToday    Section = "today"    // невыполненные задачи на сегодня
+	SectionTomorrow Section = "tomorrow" // невыполненные задачи на завтра
+	SectionUpcoming Section = "upcoming" // невыполненные задачи на неделю вперед после завтра
+	SectionDone     Section = "done"     // выполненные сегодня
+	SectionSomeday  Section = "someday"  // невыполненные задачи без срока
+)
+
+// Sections - все разделы в порядке показа
+var Sections = []Section{SectionOverdue, SectionToday, SectionTomorrow, SectionUpcoming, SectionDone, SectionSomeday}
+
+// DefaultSections - разделы повестки по умолчанию
+var DefaultSections = []Section{SectionOverdue, SectionToday}
+
+// Title возвращает название раздела
+func (s Section) Title() string {
+	return map[Section]string{
+		SectionOverdue:  "Просрочено",
+		SectionToday:    "Сегодня",
+		SectionTomorrow: "Завтра",
+		SectionUpcoming: "Скоро",
+		SectionDone:     "Выполнено сегодня",
+		SectionSomeday:  "Когда-нибудь",
+	}[s]
+}
+
+// Group - раздел повестки с задачами
+type Group struct {
+	Section Section
+	Title   string
+	Tasks   []*task.Task
+}
+
+// Agenda - повестка дня
+type Agenda struct {
+	Date   time.Time
+	Groups []Group
+}
+
+// matches проверяет, входит ли задача в раздел. Выполненной сегодня считается задача,
+// которая выполнена и последний раз менялась сегодня
+func (s Section) matches(t *task.Task, today, tomorrow, weekEnd string) bool {
+	due := t.DueDate.Format(dateFormat)
+	switch s {
+	case SectionOverdue:
+		return !t.Completed && !t.DueDate.IsZero() && due < today
+	case SectionToday:
+		return !t.Completed && due == today
+	case SectionTomorrow:
+		return !t.Completed && due == tomorrow
+	case SectionUpcoming:
+		return !t.Completed && due > tomorrow && due <= weekEnd
+	case SectionDone:
+		return t.Completed && t.UpdatedAt.Format(dateFormat) == today
+	case SectionSomeday:
+		return !t.Completed && t.DueDate.IsZero()
+	}
+	return false
+}
+
+// Build составляет повестку на день now из выбранных разделов. Задачи в архиве не попадают
+// в повестку; в разделе сначала задачи с более высоким приоритетом
+func Build(tasks []*task.Task, now time.Time, sections []Section) Agenda {
+	today := now.Format(dateFormat)
+	tomorrow := now.AddDate(0, 0, 1).Format(dateFormat)
+	weekEnd := now.AddDate(0, 0, upcomingDays).Format(dateFormat)
+	tasks = task.SortTasks(tasks, task.SortByDueDate, false)
+	tasks = task.SortTasks(tasks, task.SortByPriority, false)
+
+	agenda := Agenda{Date: now}
+	for _, section := range Sections {
+		if !contains(sections, section) {
+			continue
+		}
+		group := Group{Section: section, Title: section.Title()}
+		for _, t := range tasks {
+			if !t.Archived && section.matches(t, today, tomorrow, weekEnd) {
+				group.Tasks = append(group.Tasks, t)
+			}
+		}
+		agenda.Groups = append(agenda.Groups, group)
+	}
+	return agenda
+}
+
+// Len возвращает число задач в повестке
+func (a Agenda) Len() int {
+	n := 0
+	for _, group := range a.Groups {
+		n += len(group.Tasks)
+	}
+	return n
+}
+
+// Title возвращает заголовок повестки
+func (a Agenda) Title() string {
+	return "Повестка на " + a.Date.Format("02.01.2006")
+}
+
+// Text возвращает повестку простым текстом
+func (a Agenda) Text() string {
+	var b strings.Builder
+	b.WriteString(a.Title() + "\n")
+	for _, group := range a.Groups {
+		fmt.Fprintf(&b, "\n%s (%d)\n", group.Title, len(group.Tasks))
+		if len(group.Tasks) == 0 {
+			b.WriteString("  нет задач\n")
+		}
+		for _, t := range group.Tasks {
+			mark := "☐"
+			if t.Completed {
+				mark = "☑"
+			}
+			fmt.Fprintf(&b, "  %s %s - %s", mark, t.Title, task.PriorityText(t.Priority))
+			if group.Section == SectionOverdue || group.Section == SectionUpcoming {
+				fmt.Fprintf(&b, ", срок %s", t.DueDate.Format("02.01"))
+			}
+			if t.Assignee != "" {
+				fmt.Fprintf(&b, ", %s", t.Assignee)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// WriteHTML записывает повестку страницей HTML. Если print, страница сразу открывает
+// диалог печати браузера
+func (a Agenda) WriteHTML(w io.Writer, print bool) error {
+	return pageTemplate.Execute(w, struct {
+		Agenda
+		Print bool
+	}{a, print})
+}
+
+// WriteEmail записывает повестку письмом HTML
+func (a Agenda) WriteEmail(w io.Writer) error {
+	return emailTemplate.Execute(w, a)
+}
+
+func contains(sections []Section, section Section) bool {
+	for _, s := range sections {
+		if s == section {
+			return true
+		}
+	}
+	return false
+}
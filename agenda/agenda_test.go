@@ -0,0 +1,116 @@
+package agenda
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"taskmanager/task"
+)
+
+func testTasks(now time.Time) []*task.Task {
+	day := func(offset int) time.Time {
+		d := now.AddDate(0, 0, offset)
+		return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return []*task.Task{
+		{ID: 1, Title: "Просроченный отчет", Priority: 2, DueDate: day(-2), Assignee: "Маша"},
+		{ID: 2, Title: "Купить молоко", Priority: 1, DueDate: day(0)},
+		{ID: 3, Title: "Позвонить <боссу>", Priority: 3, DueDate: day(0), Description: "Про отпуск"},
+		{ID: 4, Title: "Записаться к врачу", Priority: 2, DueDate: day(1)},
+		{ID: 5, Title: "Оплатить счет", Priority: 2, DueDate: day(0), Completed: true, UpdatedAt: now},
+		{ID: 6, Title: "Старое в архиве", Priority: 2, DueDate: day(-5), Archived: true},
+		{ID: 7, Title: "Давно выполнено", Priority: 2, DueDate: day(-5), Completed: true, UpdatedAt: now.AddDate(0, 0, -3)},
+		{ID: 8, Title: "Сдать декларацию", Priority: 2, DueDate: day(3)},
+		{ID: 9, Title: "Поменять шины", Priority: 2, DueDate: day(10)},
+		{ID: 10, Title: "Выучить испанский", Priority: 1},
+	}
+}
+
+func ids(group Group) []int {
+	var ids []int
+	for _, t := range group.Tasks {
+		ids = append(ids, t.ID)
+	}
+	return ids
+}
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.Local)
+
+	a := Build(testTasks(now), now, DefaultSections)
+	require.Len(t, a.Groups, 2)
+	assert.Equal(t, "Просрочено", a.Groups[0].Title)
+	assert.Equal(t, []int{1}, ids(a.Groups[0]))
+	// Сначала высокий приоритет
+	assert.Equal(t, []int{3, 2}, ids(a.Groups[1]))
+	assert.Equal(t, 3, a.Len())
+
+	// Разделы идут в постоянном порядке, как бы их ни выбрали
+	a = Build(testTasks(now), now, []Section{SectionDone, SectionTomorrow})
+	require.Len(t, a.Groups, 2)
+	assert.Equal(t, SectionTomorrow, a.Groups[0].Section)
+	assert.Equal(t, []int{4}, ids(a.Groups[0]))
+	assert.Equal(t, []int{5}, ids(a.Groups[1]))
+
+	// «Скоро» - неделя после завтрашнего дня
+	a = Build(testTasks(now), now, []Section{SectionUpcoming})
+	require.Len(t, a.Groups, 1)
+	assert.Equal(t, []int{8}, ids(a.Groups[0]))
+
+	// Задача без срока не считается просроченной и попадает только в «Когда-нибудь»
+	a = Build(testTasks(now), now, []Section{SectionSomeday})
+	require.Len(t, a.Groups, 1)
+	assert.Equal(t, []int{10}, ids(a.Groups[0]))
+}
+
+func TestText(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.Local)
+	a := Build(testTasks(now), now, []Section{SectionOverdue, SectionToday, SectionDone})
+	a.Groups[2].Tasks = nil
+
+	assert.Equal(t, "Повестка на 16.10.2026\n"+
+		"\nПросрочено (1)\n"+
+		"  ☐ Просроченный отчет - средний, срок 14.10, Маша\n"+
+		"\nСегодня (2)\n"+
+		"  ☐ Позвонить <боссу> - высокий\n"+
+		"  ☐ Купить молоко - низкий\n"+
+		"\nВыполнено сегодня (0)\n"+
+		"  нет задач\n", a.Text())
+}
+
+func TestWriteHTML(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.Local)
+	a := Build(testTasks(now), now, DefaultSections)
+
+	var page bytes.Buffer
+	require.NoError(t, a.WriteHTML(&page, false))
+	html := page.String()
+	assert.Contains(t, html, "<title>Повестка на 16.10.2026</title>")
+	assert.Contains(t, html, "Позвонить &lt;боссу&gt;")
+	assert.Contains(t, html, "высокий, срок 16.10.2026")
+	assert.Contains(t, html, `<div class="description">Про отпуск</div>`)
+	assert.NotContains(t, html, "window.print")
+
+	page.Reset()
+	require.NoError(t, a.WriteHTML(&page, true))
+	assert.Contains(t, page.String(), "window.print()")
+}
+
+func TestWriteEmail(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.Local)
+	a := Build(testTasks(now), now, []Section{SectionOverdue, SectionToday, SectionUpcoming})
+
+	var mail bytes.Buffer
+	require.NoError(t, a.WriteEmail(&mail))
+	html := mail.String()
+	assert.Contains(t, html, "<title>Повестка на 16.10.2026</title>")
+	assert.Contains(t, html, "color: #b00020;\">Просрочено (1)</h2>")
+	assert.Contains(t, html, "Скоро (1)</h2>")
+	assert.Contains(t, html, "Позвонить &lt;боссу&gt;")
+	assert.Contains(t, html, "средний, срок 19.10.2026")
+	assert.NotContains(t, html, "<style>")
+}
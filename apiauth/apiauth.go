@@ -0,0 +1,165 @@
+// Package apiauth проверяет ключи доступа к серверам API (HTTP и gRPC).
+// Ключи хранятся в настройках только в виде хеша, сам ключ показывается один раз при создании
+package apiauth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+)
+
+// Scope - права ключа
+type Scope string
+
+const (
+	ScopeRead  Scope = "read"  // только чтение задач и подписка на изменения
+	ScopeWrite Scope = "write" // чтение и изменение
+)
+
+// Allows сообщает, достаточно ли прав s для действия, которому нужны права need
+func (s Scope) Allows(need Scope) bool {
+	return s == ScopeWrite || s == need
+}
+
+var (
+	// ErrUnauthenticated - ключ не передан или неверен
+	ErrUnauthenticated = errors.New("invalid or missing API key")
+	// ErrForbidden - прав ключа недостаточно
+	ErrForbidden = errors.New("API key is read-only")
+)
+
+// Key - ключ доступа в том виде, в котором он хранится в настройках
+type Key struct {
+	Name  string
+	Scope Scope
+	Hash  string // SHA-256 самого ключа в hex
+}
+
+// NewKey создает ключ и возвращает его описание для хранения и сам ключ для клиента
+func NewKey(name string, scope Scope) (Key, string) {
+	secret := make([]byte, 32)
+	rand.Read(secret)
+	encoded := base64.RawURLEncoding.EncodeToString(secret)
+	return Key{Name: name, Scope: scope, Hash: hashSecret(encoded)}, encoded
+}
+
+// String кодирует ключ для хранения в настройках: scope:hash:name
+func (k Key) String() string {
+	return string(k.Scope) + ":" + k.Hash + ":" + k.Name
+}
+
+// ParseKey разбирает ключ, сохраненный через Key.String
+func ParseKey(s string) (Key, error) {
+	parts := strings.SplitN(s, ":", 3)
+	if len(parts) != 3 {
+		return Key{}, fmt.Errorf("malformed API key %q", s)
+	}
+	scope := Scope(parts[0])
+	if scope != ScopeRead && scope != ScopeWrite {
+		return Key{}, fmt.Errorf("unknown API key scope %q", parts[0])
+	}
+	return Key{Name: parts[2], Scope: scope, Hash: parts[1]}, nil
+}
+
+// ParseKeys разбирает список ключей, пропуская испорченные записи
+func ParseKeys(list []string) []Key {
+	var keys []Key
+	for _, s := range list {
+		if key, err := ParseKey(s); err == nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Authenticator проверяет ключи клиентов.
+// Пока ни одного ключа не создано, доступ с полными правами есть только с этого компьютера
+type Authenticator struct {
+	mu   sync.RWMutex
+	keys []Key
+}
+
+// New создает проверку по списку ключей
+func New(keys []Key) *Authenticator {
+	return &Authenticator{keys: keys}
+}
+
+// SetKeys заменяет список ключей, не прерывая работу серверов
+func (a *Authenticator) SetKeys(keys []Key) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.keys = keys
+}
+
+// HasKeys сообщает, созданы ли ключи. Без ключей доступ есть только с этого компьютера
+func (a *Authenticator) HasKeys() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return len(a.keys) > 0
+}
+
+// Authorize возвращает права клиента с адреса remoteAddr (host:port), передавшего ключ secret
+func (a *Authenticator) Authorize(secret, remoteAddr string) (Scope, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if len(a.keys) == 0 {
+		if isLoopback(remoteAddr) {
+			return ScopeWrite, nil
+		}
+		return "", ErrUnauthenticated
+	}
+
+	if secret == "" {
+		return "", ErrUnauthenticated
+	}
+	hash := hashSecret(secret)
+	for _, key := range a.keys {
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(key.Hash)) == 1 {
+			return key.Scope, nil
+		}
+	}
+	return "", ErrUnauthenticated
+}
+
+// Check проверяет, что клиенту с ключом secret доступно действие с правами need
+func (a *Authenticator) Check(secret, remoteAddr string, need Scope) error {
+	scope, err := a.Authorize(secret, remoteAddr)
+	if err != nil {
+		return err
+	}
+	if !scope.Allows(need) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// BearerToken извлекает ключ из заголовка Authorization: Bearer <ключ>
+func BearerToken(header string) string {
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func isLoopback(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,65 @@
+package apiauth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyRoundTrip(t *testing.T) {
+	key, secret := NewKey("phone: Pixel", ScopeRead)
+	assert.NotEmpty(t, secret)
+	assert.NotContains(t, key.String(), secret)
+
+	parsed, err := ParseKey(key.String())
+	assert.NoError(t, err)
+	assert.Equal(t, key, parsed)
+
+	_, err = ParseKey("admin:hash:name")
+	assert.Error(t, err)
+	_, err = ParseKey("read")
+	assert.Error(t, err)
+
+	assert.Equal(t, []Key{key}, ParseKeys([]string{"broken", key.String()}))
+}
+
+func TestAuthorizeWithoutKeys(t *testing.T) {
+	a := New(nil)
+
+	scope, err := a.Authorize("", "127.0.0.1:5000")
+	assert.NoError(t, err)
+	assert.Equal(t, ScopeWrite, scope)
+	_, err = a.Authorize("", "[::1]:5000")
+	assert.NoError(t, err)
+
+	_, err = a.Authorize("", "192.168.1.20:5000")
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+}
+
+func TestAuthorizeWithKeys(t *testing.T) {
+	readKey, readSecret := NewKey("reader", ScopeRead)
+	writeKey, writeSecret := NewKey("writer", ScopeWrite)
+	a := New([]Key{readKey, writeKey})
+
+	// С ключами локальные запросы тоже проверяются
+	_, err := a.Authorize("", "127.0.0.1:5000")
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+	_, err = a.Authorize("wrong", "192.168.1.20:5000")
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+
+	assert.NoError(t, a.Check(readSecret, "192.168.1.20:5000", ScopeRead))
+	assert.ErrorIs(t, a.Check(readSecret, "192.168.1.20:5000", ScopeWrite), ErrForbidden)
+	assert.NoError(t, a.Check(writeSecret, "192.168.1.20:5000", ScopeWrite))
+	assert.NoError(t, a.Check(writeSecret, "192.168.1.20:5000", ScopeRead))
+
+	// Отозванный ключ перестает действовать сразу
+	a.SetKeys([]Key{writeKey})
+	_, err = a.Authorize(readSecret, "192.168.1.20:5000")
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+}
+
+func TestBearerToken(t *testing.T) {
+	assert.Equal(t, "abc", BearerToken("Bearer abc"))
+	assert.Equal(t, "", BearerToken("Basic abc"))
+	assert.Equal(t, "", BearerToken(""))
+}
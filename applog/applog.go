@@ -0,0 +1,152 @@
+// Package applog - журнал приложения: уровни сообщений через log/slog
+// и файл журнала с ротацией по размеру
+package applog
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	// FileName - имя файла журнала в каталоге данных приложения
+	FileName = "taskmanager.log"
+	// DefaultMaxSize - размер файла журнала, после которого он ротируется
+	DefaultMaxSize = 1 << 20
+	// DefaultKeep - сколько старых файлов журнала хранить (taskmanager.log.1, .2, ...)
+	DefaultKeep = 3
+)
+
+// File - файл журнала, который при превышении maxSize переименовывается
+// в path.1 (старые копии сдвигаются, лишние удаляются) и начинается заново
+type File struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	keep    int
+	file    *os.File
+	size    int64
+}
+
+// OpenFile открывает файл журнала для дописывания
+func OpenFile(path string, maxSize int64, keep int) (*File, error) {
+	f := &File{path: path, maxSize: maxSize, keep: keep}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *File) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file, f.size = file, info.Size()
+	return nil
+}
+
+// Path возвращает путь к текущему файлу журнала
+func (f *File) Path() string {
+	return f.path
+}
+
+// Write дописывает данные в журнал, ротируя файл при необходимости
+func (f *File) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// rotate сдвигает старые файлы журнала и начинает новый
+func (f *File) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+
+	os.Remove(fmt.Sprintf("%s.%d", f.path, f.keep))
+	for i := f.keep - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", f.path, i), fmt.Sprintf("%s.%d", f.path, i+1))
+	}
+	if f.keep > 0 {
+		if err := os.Rename(f.path, f.path+".1"); err != nil {
+			return err
+		}
+	} else if err := os.Remove(f.path); err != nil {
+		return err
+	}
+	return f.open()
+}
+
+// Close закрывает файл журнала
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
+
+// Setup направляет журнал slog по умолчанию в файл FileName в каталоге dir и в stderr.
+// Без verbose записываются сообщения от уровня Info, с verbose - и отладочные
+func Setup(dir string, verbose bool) (*File, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	file, err := OpenFile(filepath.Join(dir, FileName), DefaultMaxSize, DefaultKeep)
+	if err != nil {
+		return nil, err
+	}
+
+	level := slog.LevelInfo
+	if verbose {
+		level = slog.LevelDebug
+	}
+	handler := slog.NewTextHandler(io.MultiWriter(file, os.Stderr), &slog.HandlerOptions{Level: level})
+	slog.SetDefault(slog.New(handler))
+	return file, nil
+}
+
+// Tail возвращает последние maxBytes байт файла журнала, начиная с целой строки
+func Tail(path string, maxBytes int64) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+	offset := max(info.Size()-maxBytes, 0)
+	data := make([]byte, info.Size()-offset)
+	if _, err := file.ReadAt(data, offset); err != nil && err != io.EOF {
+		return "", err
+	}
+
+	if offset > 0 {
+		// Первая строка обрезана - пропускаем ее
+		for i, b := range data {
+			if b == '\n' {
+				data = data[i+1:]
+				break
+			}
+		}
+	}
+	return string(data), nil
+}
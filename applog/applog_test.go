@@ -0,0 +1,78 @@
+package applog
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	file, err := OpenFile(path, 10, 2)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer file.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := file.Write([]byte(line))
+		assert.NoError(t, err)
+	}
+
+	// Каждая запись не помещается к предыдущей, старейшая копия удалена
+	current, _ := os.ReadFile(path)
+	assert.Equal(t, "fourth\n", string(current))
+	first, _ := os.ReadFile(path + ".1")
+	assert.Equal(t, "third\n", string(first))
+	second, _ := os.ReadFile(path + ".2")
+	assert.Equal(t, "second\n", string(second))
+	assert.NoFileExists(t, path+".3")
+}
+
+func TestReopenAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	file, err := OpenFile(path, 100, 1)
+	assert.NoError(t, err)
+	file.Write([]byte("before restart\n"))
+	file.Close()
+
+	file, err = OpenFile(path, 100, 1)
+	assert.NoError(t, err)
+	file.Write([]byte("after restart\n"))
+	file.Close()
+
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, "before restart\nafter restart\n", string(data))
+}
+
+func TestSetup(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+	dir := filepath.Join(t.TempDir(), "data")
+
+	file, err := Setup(dir, false)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	slog.Debug("hidden message")
+	slog.Error("failed to load tasks", "err", "broken file")
+	file.Close()
+
+	text, err := Tail(file.Path(), 1<<10)
+	assert.NoError(t, err)
+	assert.NotContains(t, text, "hidden message")
+	assert.Contains(t, text, "level=ERROR")
+	assert.Contains(t, text, "broken file")
+}
+
+func TestTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	os.WriteFile(path, []byte(strings.Repeat("line\n", 10)), 0644)
+
+	text, err := Tail(path, 12)
+	assert.NoError(t, err)
+	assert.Equal(t, "line\nline\n", text)
+}
@@ -0,0 +1,122 @@
+// Сервер синхронизации задач между устройствами.
+//
+// Создать ключ для устройства (ключ выводится один раз, в файл ключей попадает только его хеш):
+//
+//	syncserver -new-key laptop
+//
+// Новые ключи применяются после перезапуска сервера.
+//
+// Запустить сервер:
+//
+//	syncserver -addr :8443 -tls-cert cert.pem -tls-key key.pem
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"taskmanager/apiauth"
+	"taskmanager/storage"
+	"taskmanager/tasksync"
+)
+
+func main() {
+	addr := flag.String("addr", ":8443", "адрес, на котором принимать подключения")
+	dataFile := flag.String("data", "sync-data.json", "файл с задачами всех устройств")
+	keysFile := flag.String("keys", "sync-keys.txt", "файл ключей устройств")
+	newKey := flag.String("new-key", "", "создать ключ для устройства с указанным именем и выйти")
+	certFile := flag.String("tls-cert", "", "сертификат TLS (PEM); без него сервер работает по HTTP")
+	keyFile := flag.String("tls-key", "", "закрытый ключ сертификата TLS (PEM)")
+	flag.Parse()
+
+	if *newKey != "" {
+		secret, err := addKey(*keysFile, *newKey)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "failed to create key:", err)
+			os.Exit(1)
+		}
+		fmt.Println(secret)
+		return
+	}
+
+	keys, err := readKeys(*keysFile)
+	if err != nil {
+		slog.Error("failed to read keys", "file", *keysFile, "err", err)
+		os.Exit(1)
+	}
+	if len(keys) == 0 {
+		slog.Warn("no device keys, only clients on this computer can sync", "file", *keysFile)
+	}
+
+	server, err := tasksync.NewServer(storage.NewFile(*dataFile), apiauth.New(keys))
+	if err != nil {
+		slog.Error("failed to load sync data", "file", *dataFile, "err", err)
+		os.Exit(1)
+	}
+
+	httpServer := &http.Server{Addr: *addr, Handler: server.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	slog.Info("sync server started", "addr", *addr, "tls", *certFile != "")
+	if *certFile != "" {
+		err = httpServer.ListenAndServeTLS(*certFile, *keyFile)
+	} else {
+		err = httpServer.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("sync server failed", "err", err)
+		os.Exit(1)
+	}
+}
+
+// readKeys читает ключи устройств, по одному в строке (scope:hash:name)
+func readKeys(path string) ([]apiauth.Key, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return apiauth.ParseKeys(lines), scanner.Err()
+}
+
+// addKey создает ключ устройства name, дописывает его в файл ключей и возвращает сам ключ
+func addKey(path, name string) (string, error) {
+	key, secret := apiauth.NewKey(name, apiauth.ScopeWrite)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return "", err
+	}
+	if _, err := fmt.Fprintln(file, key.String()); err != nil {
+		file.Close()
+		return "", err
+	}
+	return secret, file.Close()
+}
@@ -0,0 +1,203 @@
+// Package depgraph строит граф зависимостей задач: раскладывает задачи по слоям
+// так, что зависимость всегда левее зависимой задачи, находит циклы и критическую цепочку
+// невыполненных задач, которые блокируют друг друга
+package depgraph
+
+import (
+	"sort"
+
+	"taskmanager/task"
+)
+
+// Edge - зависимость: задачу From нужно выполнить раньше задачи To
+type Edge struct {
+	From, To *task.Task
+}
+
+// Graph - граф зависимостей задач
+type Graph struct {
+	// Layers - задачи по слоям: в первом - задачи без зависимостей, в каждом следующем -
+	// задачи, все зависимости которых левее. Задачи из циклов - в последнем слое
+	Layers [][]*task.Task
+	Edges  []Edge
+	// Cycle - задачи, зависимости которых замыкаются в цикл
+	Cycle map[*task.Task]bool
+	// Critical - самая длинная цепочка невыполненных задач, каждая из которых ждет предыдущую:
+	// от первой, с которой нужно начать, до последней
+	Critical []*task.Task
+}
+
+// Build строит граф из задач, у которых есть зависимости или от которых зависят другие
+func Build(tasks []*task.Task) Graph {
+	byUUID := make(map[string]*task.Task, len(tasks))
+	for _, t := range tasks {
+		byUUID[t.UUID] = t
+	}
+	g := Graph{Cycle: make(map[*task.Task]bool)}
+	deps := make(map[*task.Task][]*task.Task)
+	var nodes []*task.Task
+	inGraph := make(map[*task.Task]bool)
+	add := func(t *task.Task) {
+		if !inGraph[t] {
+			inGraph[t] = true
+			nodes = append(nodes, t)
+		}
+	}
+	for _, t := range tasks {
+		for _, uuid := range t.DependsOn {
+			dep := byUUID[uuid]
+			if dep == nil {
+				continue
+			}
+			add(dep)
+			add(t)
+			deps[t] = append(deps[t], dep)
+			g.Edges = append(g.Edges, Edge{From: dep, To: t})
+		}
+	}
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+
+	for _, component := range stronglyConnected(nodes, deps) {
+		if len(component) > 1 {
+			for _, t := range component {
+				g.Cycle[t] = true
+			}
+		}
+	}
+	for t, list := range deps {
+		for _, dep := range list {
+			if dep == t {
+				g.Cycle[t] = true
+			}
+		}
+	}
+
+	// Слой задачи - длина самой длинной цепочки зависимостей до нее
+	layer := make(map[*task.Task]int)
+	var layerOf func(t *task.Task) int
+	layerOf = func(t *task.Task) int {
+		if l, ok := layer[t]; ok {
+			return l
+		}
+		l := 0
+		for _, dep := range deps[t] {
+			if !g.Cycle[dep] {
+				l = max(l, layerOf(dep)+1)
+			}
+		}
+		layer[t] = l
+		return l
+	}
+	var cyclic []*task.Task
+	for _, t := range nodes {
+		if g.Cycle[t] {
+			cyclic = append(cyclic, t)
+			continue
+		}
+		l := layerOf(t)
+		for len(g.Layers) <= l {
+			g.Layers = append(g.Layers, nil)
+		}
+		g.Layers[l] = append(g.Layers[l], t)
+	}
+	if len(cyclic) > 0 {
+		g.Layers = append(g.Layers, cyclic)
+	}
+
+	g.Critical = criticalChain(nodes, deps, g.Cycle)
+	return g
+}
+
+// criticalChain находит самую длинную цепочку невыполненных задач вне циклов
+func criticalChain(nodes []*task.Task, deps map[*task.Task][]*task.Task, cycle map[*task.Task]bool) []*task.Task {
+	open := func(t *task.Task) bool { return !t.Completed && !cycle[t] }
+	length := make(map[*task.Task]int)
+	prev := make(map[*task.Task]*task.Task)
+	var lengthOf func(t *task.Task) int
+	lengthOf = func(t *task.Task) int {
+		if l, ok := length[t]; ok {
+			return l
+		}
+		l := 1
+		for _, dep := range deps[t] {
+			if open(dep) && lengthOf(dep)+1 > l {
+				l = lengthOf(dep) + 1
+				prev[t] = dep
+			}
+		}
+		length[t] = l
+		return l
+	}
+	var last *task.Task
+	for _, t := range nodes {
+		if open(t) && (last == nil || lengthOf(t) > lengthOf(last)) {
+			last = t
+		}
+	}
+	if last == nil || length[last] < 2 {
+		return nil
+	}
+	chain := make([]*task.Task, length[last])
+	for i, t := len(chain)-1, last; t != nil; i, t = i-1, prev[t] {
+		chain[i] = t
+	}
+	return chain
+}
+
+// stronglyConnected находит сильно связные компоненты графа (алгоритм Тарьяна)
+func stronglyConnected(nodes []*task.Task, deps map[*task.Task][]*task.Task) [][]*task.Task {
+	index := make(map[*task.Task]int)
+	low := make(map[*task.Task]int)
+	onStack := make(map[*task.Task]bool)
+	var stack []*task.Task
+	var components [][]*task.Task
+	var visit func(t *task.Task)
+	visit = func(t *task.Task) {
+		index[t] = len(index)
+		low[t] = index[t]
+		stack = append(stack, t)
+		onStack[t] = true
+		for _, dep := range deps[t] {
+			if _, seen := index[dep]; !seen {
+				visit(dep)
+				low[t] = min(low[t], low[dep])
+			} else if onStack[dep] {
+				low[t] = min(low[t], index[dep])
+			}
+		}
+		if low[t] == index[t] {
+			var component []*task.Task
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == t {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+	for _, t := range nodes {
+		if _, seen := index[t]; !seen {
+			visit(t)
+		}
+	}
+	return components
+}
+
+// Empty сообщает, что зависимостей нет
+func (g Graph) Empty() bool {
+	return len(g.Edges) == 0
+}
+
+// OnCritical сообщает, лежит ли зависимость на критической цепочке
+func (g Graph) OnCritical(e Edge) bool {
+	for i := 1; i < len(g.Critical); i++ {
+		if g.Critical[i-1] == e.From && g.Critical[i] == e.To {
+			return true
+		}
+	}
+	return false
+}
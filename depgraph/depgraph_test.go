@@ -0,0 +1,44 @@
+package depgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+func TestBuild(t *testing.T) {
+	// Фундамент → стены → крыша, стены → окна; забор ни от чего не зависит
+	base := &task.Task{ID: 1, UUID: "base", Completed: true}
+	walls := &task.Task{ID: 2, UUID: "walls", DependsOn: []string{"base"}}
+	roof := &task.Task{ID: 3, UUID: "roof", DependsOn: []string{"walls"}}
+	windows := &task.Task{ID: 4, UUID: "windows", DependsOn: []string{"walls", "base"}}
+	fence := &task.Task{ID: 5, UUID: "fence"}
+
+	g := Build([]*task.Task{roof, windows, walls, base, fence})
+	assert.False(t, g.Empty())
+	assert.Equal(t, [][]*task.Task{{base}, {walls}, {roof, windows}}, g.Layers)
+	assert.Len(t, g.Edges, 4)
+	assert.Empty(t, g.Cycle)
+
+	// Фундамент готов: критическая цепочка начинается со стен
+	assert.Equal(t, []*task.Task{walls, roof}, g.Critical)
+	assert.True(t, g.OnCritical(Edge{From: walls, To: roof}))
+	assert.False(t, g.OnCritical(Edge{From: walls, To: windows}))
+
+	assert.True(t, Build([]*task.Task{fence}).Empty())
+}
+
+func TestBuildCycle(t *testing.T) {
+	// После слияния файлов зависимости могут замкнуться в цикл
+	a := &task.Task{ID: 1, UUID: "a", DependsOn: []string{"c"}}
+	b := &task.Task{ID: 2, UUID: "b", DependsOn: []string{"a"}}
+	c := &task.Task{ID: 3, UUID: "c", DependsOn: []string{"b"}}
+	d := &task.Task{ID: 4, UUID: "d", DependsOn: []string{"c"}}
+
+	g := Build([]*task.Task{a, b, c, d})
+	assert.Equal(t, map[*task.Task]bool{a: true, b: true, c: true}, g.Cycle)
+	assert.Equal(t, [][]*task.Task{{d}, {a, b, c}}, g.Layers)
+	assert.Nil(t, g.Critical)
+}
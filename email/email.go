@@ -0,0 +1,208 @@
+// Package email отправляет повестку дня письмом через SMTP: утром, в заданное время,
+// письмо с просроченными задачами, задачами на сегодня и на ближайшие дни.
+// Письмо содержит текст и HTML, почтовая программа показывает то, что умеет
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"taskmanager/agenda"
+	"taskmanager/task"
+)
+
+// ErrInvalidConfig возвращается для настроек без сервера, с неверным портом или адресом
+var ErrInvalidConfig = errors.New("invalid email settings")
+
+// Sections - разделы повестки в письме
+var Sections = []agenda.Section{agenda.SectionOverdue, agenda.SectionToday, agenda.SectionUpcoming}
+
+// implicitTLSPort - порт, на котором TLS начинается сразу; на остальных портах соединение
+// шифруется командой STARTTLS, если сервер ее поддерживает
+const implicitTLSPort = 465
+
+// Config - сервер SMTP и адреса письма
+type Config struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// ParseAddresses разбирает список адресов через запятую
+func ParseAddresses(text string) []string {
+	var list []string
+	for _, address := range strings.Split(text, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			list = append(list, address)
+		}
+	}
+	return list
+}
+
+// Validate проверяет сервер и адреса
+func (c Config) Validate() error {
+	if c.Host == "" {
+		return fmt.Errorf("%w: smtp server is required", ErrInvalidConfig)
+	}
+	if c.Port <= 0 || c.Port > 65535 {
+		return fmt.Errorf("%w: invalid port %d", ErrInvalidConfig, c.Port)
+	}
+	if _, err := mail.ParseAddress(c.From); err != nil {
+		return fmt.Errorf("%w: sender %q: %v", ErrInvalidConfig, c.From, err)
+	}
+	if len(c.To) == 0 {
+		return fmt.Errorf("%w: recipient is required", ErrInvalidConfig)
+	}
+	for _, to := range c.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return fmt.Errorf("%w: recipient %q: %v", ErrInvalidConfig, to, err)
+		}
+	}
+	return nil
+}
+
+// Due сообщает, пора ли отправить повестку в момент now: раз в день начиная со времени at
+// («07:00»), если в день lastSent она еще не отправлялась
+func Due(now time.Time, at, lastSent string) bool {
+	if lastSent == now.Format(task.DueDateLayout) {
+		return false
+	}
+	t, err := time.Parse("15:04", at)
+	return err == nil && now.Hour()*60+now.Minute() >= t.Hour()*60+t.Minute()
+}
+
+// AgendaMessage собирает письмо с повесткой day
+func AgendaMessage(cfg Config, day agenda.Agenda, now time.Time) ([]byte, error) {
+	var html bytes.Buffer
+	if err := day.WriteEmail(&html); err != nil {
+		return nil, err
+	}
+	return Compose(cfg, day.Title(), day.Text(), html.String(), now)
+}
+
+// Compose собирает письмо из текста и HTML; почтовая программа показывает HTML, если умеет
+func Compose(cfg Config, subject, text, html string, now time.Time) ([]byte, error) {
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("%w: sender %q: %v", ErrInvalidConfig, cfg.From, err)
+	}
+	id := make([]byte, 12)
+	rand.Read(id)
+	domain := from.Address[strings.LastIndex(from.Address, "@")+1:]
+
+	var b bytes.Buffer
+	body := multipart.NewWriter(&b)
+	header := func(name, value string) { fmt.Fprintf(&b, "%s: %s\r\n", name, value) }
+	header("From", from.String())
+	header("To", strings.Join(cfg.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", subject))
+	header("Date", now.Format(time.RFC1123Z))
+	header("Message-ID", "<"+hex.EncodeToString(id)+"@"+domain+">")
+	header("MIME-Version", "1.0")
+	header("Content-Type", "multipart/alternative; boundary="+body.Boundary())
+	b.WriteString("\r\n")
+
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", html},
+	} {
+		w, err := body.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// Send отправляет письмо message получателям из настроек. Пароль передается только
+// по зашифрованному соединению: net/smtp отказывается отправлять его открытым текстом
+func Send(ctx context.Context, cfg Config, message []byte) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	from, _ := mail.ParseAddress(cfg.From)
+	address := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	tlsConfig := &tls.Config{ServerName: cfg.Host}
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	var conn net.Conn
+	var err error
+	if cfg.Port == implicitTLSPort {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp: %w", err)
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok && cfg.Port != implicitTLSPort {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("smtp: %w", err)
+		}
+	}
+	if cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)); err != nil {
+			return fmt.Errorf("smtp: %w", err)
+		}
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	for _, to := range cfg.To {
+		recipient, _ := mail.ParseAddress(to)
+		if err := client.Rcpt(recipient.Address); err != nil {
+			return fmt.Errorf("smtp: %s: %w", recipient.Address, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	if _, err := w.Write(message); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	return client.Quit()
+}
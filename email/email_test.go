@@ -0,0 +1,156 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"taskmanager/agenda"
+	"taskmanager/task"
+)
+
+func testConfig() Config {
+	return Config{Host: "127.0.0.1", Port: 25, From: "Задачи <tasks@example.com>", To: []string{"me@example.com"}}
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, testConfig().Validate())
+
+	for _, broken := range []func(*Config){
+		func(c *Config) { c.Host = "" },
+		func(c *Config) { c.Port = 0 },
+		func(c *Config) { c.From = "не адрес" },
+		func(c *Config) { c.To = nil },
+		func(c *Config) { c.To = []string{"me@example.com", "@"} },
+	} {
+		cfg := testConfig()
+		broken(&cfg)
+		assert.ErrorIs(t, cfg.Validate(), ErrInvalidConfig)
+	}
+	assert.Equal(t, []string{"a@example.com", "b@example.com"}, ParseAddresses(" a@example.com, ,b@example.com"))
+}
+
+func TestDue(t *testing.T) {
+	now := time.Date(2026, 10, 16, 7, 30, 0, 0, time.UTC)
+	assert.True(t, Due(now, "07:00", "2026-10-15"))
+	assert.False(t, Due(now, "07:00", "2026-10-16"))
+	assert.False(t, Due(now, "08:00", ""))
+	assert.False(t, Due(now, "утром", ""))
+}
+
+func TestAgendaMessage(t *testing.T) {
+	now := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	tasks := []*task.Task{{ID: 1, Title: "Позвонить в банк", Priority: 3, DueDate: now}}
+	data, err := AgendaMessage(testConfig(), agenda.Build(tasks, now, Sections), now)
+	require.NoError(t, err)
+
+	message, err := mail.ReadMessage(strings.NewReader(string(data)))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Повестка на 16.10.2026", subject)
+	assert.Equal(t, "me@example.com", message.Header.Get("To"))
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+	parts := multipart.NewReader(message.Body, params["boundary"])
+	var types, bodies []string
+	for {
+		part, err := parts.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		body, err := io.ReadAll(quotedprintable.NewReader(part))
+		require.NoError(t, err)
+		types = append(types, part.Header.Get("Content-Type"))
+		bodies = append(bodies, string(body))
+	}
+	assert.Equal(t, []string{"text/plain; charset=utf-8", "text/html; charset=utf-8"}, types)
+	assert.Contains(t, bodies[0], "☐ Позвонить в банк - высокий")
+	assert.Contains(t, bodies[1], "<h2")
+	assert.Contains(t, bodies[1], "Скоро (0)")
+}
+
+// fakeServer принимает одно письмо по SMTP без шифрования и возвращает его текст
+func fakeServer(t *testing.T) (port int, received <-chan string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	messages := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+		reply("220 localhost ESMTP")
+		var data strings.Builder
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch command := strings.ToUpper(strings.Fields(line)[0]); command {
+			case "EHLO", "HELO", "MAIL", "RCPT":
+				reply("250 OK")
+			case "DATA":
+				reply("354 go ahead")
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil || line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				messages <- data.String()
+				reply("250 queued")
+			case "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("502 not implemented")
+			}
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port, messages
+}
+
+func TestSend(t *testing.T) {
+	port, received := fakeServer(t)
+	cfg := testConfig()
+	cfg.Port = port
+
+	now := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	message, err := Compose(cfg, "Проверка", "текст", "<p>текст</p>", now)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, Send(ctx, cfg, message))
+
+	select {
+	case data := <-received:
+		assert.Contains(t, data, "Subject: =?utf-8?q?")
+		assert.Contains(t, data, "Date: "+now.Format(time.RFC1123Z))
+	case <-time.After(5 * time.Second):
+		t.Fatal("message was not received")
+	}
+
+	cfg.Port = 1
+	assert.Error(t, Send(ctx, cfg, message))
+	assert.ErrorIs(t, Send(ctx, Config{}, message), ErrInvalidConfig)
+}
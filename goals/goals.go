@@ -0,0 +1,217 @@
+// Package goals - цели на квартал или месяц, к которым привязываются задачи: легкий слой OKR
+// над списком. Прогресс цели - доля выполненных задач, риск - по срокам задач и времени,
+// оставшемуся до конца периода. Пакет не зависит от интерфейса
+package goals
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"taskmanager/task"
+)
+
+// Period - срок цели
+type Period string
+
+const (
+	PeriodQuarter Period = "quarter"
+	PeriodMonth   Period = "month"
+)
+
+// ErrInvalidGoal возвращается для цели без названия, с неизвестным периодом или началом
+var ErrInvalidGoal = errors.New("invalid goal")
+
+// Goal - цель на квартал или месяц
+type Goal struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Period Period `json:"period"`
+	Start  string `json:"start"` // первый день периода, 2006-01-02
+}
+
+// NewGoal создает цель с новым идентификатором на период, в который попадает day
+func NewGoal(title string, period Period, day time.Time) Goal {
+	id := make([]byte, 8)
+	rand.Read(id)
+	return Goal{
+		ID:     hex.EncodeToString(id),
+		Title:  strings.TrimSpace(title),
+		Period: period,
+		Start:  PeriodStart(period, day).Format(task.DueDateLayout),
+	}
+}
+
+// PeriodStart возвращает первый день квартала или месяца, в который попадает day
+func PeriodStart(period Period, day time.Time) time.Time {
+	month := day.Month()
+	if period == PeriodQuarter {
+		month -= (month - 1) % 3
+	}
+	return time.Date(day.Year(), month, 1, 0, 0, 0, 0, day.Location())
+}
+
+// Validate проверяет название, период и начало цели
+func (g Goal) Validate() error {
+	if strings.TrimSpace(g.Title) == "" {
+		return fmt.Errorf("%w: title is required", ErrInvalidGoal)
+	}
+	if g.Period != PeriodQuarter && g.Period != PeriodMonth {
+		return fmt.Errorf("%w: unknown period %q", ErrInvalidGoal, g.Period)
+	}
+	start, err := time.Parse(task.DueDateLayout, g.Start)
+	if err != nil {
+		return fmt.Errorf("%w: start must look like 2006-01-02", ErrInvalidGoal)
+	}
+	if !PeriodStart(g.Period, start).Equal(start) {
+		return fmt.Errorf("%w: start must be the first day of the period", ErrInvalidGoal)
+	}
+	return nil
+}
+
+// Bounds возвращает начало периода цели и начало следующего периода в поясе loc
+func (g Goal) Bounds(loc *time.Location) (start, end time.Time) {
+	start, _ = time.ParseInLocation(task.DueDateLayout, g.Start, loc)
+	months := 1
+	if g.Period == PeriodQuarter {
+		months = 3
+	}
+	return start, start.AddDate(0, months, 0)
+}
+
+// quarterNames - римские номера кварталов
+var quarterNames = []string{"I", "II", "III", "IV"}
+
+// monthNames - названия месяцев для периода цели
+var monthNames = []string{"январь", "февраль", "март", "апрель", "май", "июнь",
+	"июль", "август", "сентябрь", "октябрь", "ноябрь", "декабрь"}
+
+// PeriodTitle описывает период цели: «IV квартал 2026» или «октябрь 2026»
+func (g Goal) PeriodTitle() string {
+	start, _ := g.Bounds(time.UTC)
+	if g.Period == PeriodQuarter {
+		return fmt.Sprintf("%s квартал %d", quarterNames[(start.Month()-1)/3], start.Year())
+	}
+	return fmt.Sprintf("%s %d", monthNames[start.Month()-1], start.Year())
+}
+
+// Risk - насколько цель успевает к концу периода
+type Risk int
+
+const (
+	RiskNone     Risk = iota // задач нет или все выполнены
+	RiskOnTrack              // выполнено не меньше, чем прошло времени
+	RiskBehind               // выполнено меньше, чем прошло времени
+	RiskOffTrack             // есть просроченные задачи, задачи со сроком после конца периода или период кончился
+)
+
+// String описывает риск для вида целей
+func (r Risk) String() string {
+	switch r {
+	case RiskOnTrack:
+		return "успевает"
+	case RiskBehind:
+		return "отстает"
+	case RiskOffTrack:
+		return "под угрозой"
+	}
+	return ""
+}
+
+// Progress - состояние задач цели
+type Progress struct {
+	Done    int
+	Total   int
+	Overdue int // невыполненные задачи, срок которых прошел
+	Late    int // невыполненные задачи со сроком после конца периода цели
+	Risk    Risk
+}
+
+// Percent возвращает долю выполненных задач в процентах
+func (p Progress) Percent() int {
+	if p.Total == 0 {
+		return 0
+	}
+	return p.Done * 100 / p.Total
+}
+
+// Progress считает прогресс цели по задачам tasks; now - в поясе сроков.
+// Невыполненные задачи в архиве не учитываются
+func (g Goal) Progress(tasks []*task.Task, now time.Time) Progress {
+	var p Progress
+	start, end := g.Bounds(now.Location())
+	for _, t := range tasks {
+		if t.Goal != g.ID || t.Archived && !t.Completed {
+			continue
+		}
+		p.Total++
+		switch {
+		case t.Completed:
+			p.Done++
+		case t.IsOverdue(now):
+			p.Overdue++
+		case !t.DueDate.IsZero() && t.DueDay() >= end.Format(task.DueDateLayout):
+			p.Late++
+		}
+	}
+
+	switch {
+	case p.Done == p.Total:
+		p.Risk = RiskNone
+	case p.Overdue > 0 || p.Late > 0 || !now.Before(end):
+		p.Risk = RiskOffTrack
+	case now.After(start) && float64(now.Sub(start))/float64(end.Sub(start)) > float64(p.Done)/float64(p.Total):
+		p.Risk = RiskBehind
+	default:
+		p.Risk = RiskOnTrack
+	}
+	return p
+}
+
+// Find возвращает цель с идентификатором id
+func Find(list []Goal, id string) (Goal, bool) {
+	i := slices.IndexFunc(list, func(g Goal) bool { return g.ID == id })
+	if i < 0 {
+		return Goal{}, false
+	}
+	return list[i], true
+}
+
+// Sort упорядочивает цели по началу периода, квартальные раньше месячных, затем по названию
+func Sort(list []Goal) {
+	slices.SortStableFunc(list, func(a, b Goal) int {
+		if c := strings.Compare(a.Start, b.Start); c != 0 {
+			return c
+		}
+		if a.Period != b.Period {
+			if a.Period == PeriodQuarter {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Title, b.Title)
+	})
+}
+
+// Parse читает цели, записанные Marshal; пустая строка - целей нет
+func Parse(data string) ([]Goal, error) {
+	if data == "" {
+		return nil, nil
+	}
+	var list []Goal
+	if err := json.Unmarshal([]byte(data), &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Marshal записывает цели для хранения в настройках
+func Marshal(list []Goal) string {
+	data, _ := json.Marshal(list)
+	return string(data)
+}
@@ -0,0 +1,96 @@
+package goals
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"taskmanager/task"
+)
+
+func TestNewGoal(t *testing.T) {
+	day := time.Date(2026, 11, 20, 0, 0, 0, 0, time.UTC)
+	quarter := NewGoal("  Запустить сайт ", PeriodQuarter, day)
+	assert.Equal(t, "Запустить сайт", quarter.Title)
+	assert.Equal(t, "2026-10-01", quarter.Start)
+	assert.Equal(t, "IV квартал 2026", quarter.PeriodTitle())
+	assert.NoError(t, quarter.Validate())
+	start, end := quarter.Bounds(time.UTC)
+	assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), end)
+
+	month := NewGoal("Прочитать книгу", PeriodMonth, day)
+	assert.Equal(t, "2026-11-01", month.Start)
+	assert.Equal(t, "ноябрь 2026", month.PeriodTitle())
+	assert.NotEqual(t, quarter.ID, month.ID)
+
+	for _, broken := range []func(*Goal){
+		func(g *Goal) { g.Title = " " },
+		func(g *Goal) { g.Period = "year" },
+		func(g *Goal) { g.Start = "1 ноября" },
+		func(g *Goal) { g.Start = "2026-11-15" },
+	} {
+		g := month
+		broken(&g)
+		assert.ErrorIs(t, g.Validate(), ErrInvalidGoal)
+	}
+}
+
+func TestProgress(t *testing.T) {
+	goal := Goal{ID: "q4", Title: "Запуск", Period: PeriodQuarter, Start: "2026-10-01"}
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC) }
+	now := day(10, 16)
+
+	assert.Equal(t, Progress{Risk: RiskNone}, goal.Progress(nil, now))
+
+	tasks := []*task.Task{
+		{Goal: "q4", Completed: true, DueDate: day(10, 10)},
+		{Goal: "q4", DueDate: day(12, 20)},
+		{Goal: "q4"},
+		{Goal: "other", DueDate: day(10, 1)},
+		{Goal: "q4", Archived: true, DueDate: day(10, 1)},
+	}
+	p := goal.Progress(tasks, now)
+	assert.Equal(t, Progress{Done: 1, Total: 3, Risk: RiskOnTrack}, p)
+	assert.Equal(t, 33, p.Percent())
+
+	// Выполнена треть задач, а прошло больше трети квартала
+	assert.Equal(t, RiskBehind, goal.Progress(tasks, day(11, 20)).Risk)
+
+	// Просроченная задача и задача со сроком после конца квартала ставят цель под угрозу
+	assert.Equal(t, Progress{Done: 1, Total: 3, Overdue: 1, Risk: RiskOffTrack}, goal.Progress(tasks, day(12, 21)))
+	tasks[1].DueDate = day(1, 15).AddDate(1, 0, 0)
+	assert.Equal(t, Progress{Done: 1, Total: 3, Late: 1, Risk: RiskOffTrack}, goal.Progress(tasks, now))
+
+	for _, t := range tasks[:3] {
+		t.Completed = true
+	}
+	assert.Equal(t, RiskNone, goal.Progress(tasks, now).Risk)
+	assert.Equal(t, "под угрозой", RiskOffTrack.String())
+}
+
+func TestSortAndMarshal(t *testing.T) {
+	list := []Goal{
+		{ID: "b", Title: "Б", Period: PeriodMonth, Start: "2026-10-01"},
+		{ID: "c", Title: "В", Period: PeriodMonth, Start: "2026-09-01"},
+		{ID: "a", Title: "А", Period: PeriodQuarter, Start: "2026-10-01"},
+	}
+	Sort(list)
+	assert.Equal(t, "c", list[0].ID)
+	assert.Equal(t, "a", list[1].ID)
+
+	parsed, err := Parse(Marshal(list))
+	require.NoError(t, err)
+	assert.Equal(t, list, parsed)
+	found, ok := Find(parsed, "b")
+	assert.True(t, ok)
+	assert.Equal(t, "Б", found.Title)
+	_, ok = Find(parsed, "x")
+	assert.False(t, ok)
+
+	empty, err := Parse("")
+	assert.NoError(t, err)
+	assert.Empty(t, empty)
+}
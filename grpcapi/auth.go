@@ -0,0 +1,70 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"taskmanager/apiauth"
+	"taskmanager/grpcapi/taskpb"
+)
+
+// readMethods - методы, которым достаточно прав на чтение
+var readMethods = map[string]bool{
+	taskpb.TaskService_ListTasks_FullMethodName: true,
+	taskpb.TaskService_GetTask_FullMethodName:   true,
+	taskpb.TaskService_Watch_FullMethodName:     true,
+}
+
+// authorize проверяет ключ API из метаданных вызова (authorization: Bearer <ключ>)
+func authorize(ctx context.Context, auth *apiauth.Authenticator, method string) error {
+	var key string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			key = apiauth.BearerToken(values[0])
+		}
+	}
+	var remoteAddr string
+	if p, ok := peer.FromContext(ctx); ok {
+		remoteAddr = p.Addr.String()
+	}
+
+	need := apiauth.ScopeWrite
+	if readMethods[method] {
+		need = apiauth.ScopeRead
+	}
+
+	err := auth.Check(key, remoteAddr, need)
+	switch {
+	case errors.Is(err, apiauth.ErrUnauthenticated):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case err != nil:
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return nil
+}
+
+// unaryAuth проверяет ключ API перед обычными вызовами
+func unaryAuth(auth *apiauth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := authorize(ctx, auth, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// streamAuth проверяет ключ API перед потоковыми вызовами
+func streamAuth(auth *apiauth.Authenticator) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authorize(ss.Context(), auth, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,50 @@
+package grpcapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"taskmanager/apiauth"
+	"taskmanager/grpcapi/taskpb"
+)
+
+// withKey добавляет ключ API к исходящему вызову
+func withKey(secret string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+secret)
+}
+
+func TestAuthScopes(t *testing.T) {
+	readKey, readSecret := apiauth.NewKey("reader", apiauth.ScopeRead)
+	writeKey, writeSecret := apiauth.NewKey("writer", apiauth.ScopeWrite)
+	client, _, _ := newTestClient(t, readKey, writeKey)
+	create := &taskpb.CreateTaskRequest{Title: "Task", Priority: 1, DueDate: timestamppb.New(time.Now().Add(time.Hour))}
+
+	// Без ключа и с неверным ключом
+	_, err := client.ListTasks(context.Background(), &taskpb.ListTasksRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = client.ListTasks(withKey("wrong"), &taskpb.ListTasksRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// Ключ только для чтения
+	_, err = client.ListTasks(withKey(readSecret), &taskpb.ListTasksRequest{})
+	assert.NoError(t, err)
+	_, err = client.CreateTask(withKey(readSecret), create)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Ключ на запись
+	_, err = client.CreateTask(withKey(writeSecret), create)
+	assert.NoError(t, err)
+
+	// Потоковые вызовы проверяются так же
+	stream, err := client.Watch(context.Background(), &taskpb.WatchRequest{})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,249 @@
+// Package grpcapi - gRPC API менеджера задач для сопутствующих приложений:
+// операции над задачами и поток изменений
+package grpcapi
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"taskmanager/apiauth"
+	"taskmanager/grpcapi/taskpb"
+	"taskmanager/task"
+)
+
+// watchBuffer - сколько событий может накопиться для медленного клиента
+// до того, как его поток будет закрыт
+const watchBuffer = 64
+
+// Server реализует taskpb.TaskServiceServer поверх менеджера задач.
+// Менеджер задач не потокобезопасен, поэтому все обращения к нему
+// выполняются через do (в приложении - в потоке интерфейса)
+type Server struct {
+	taskpb.UnimplementedTaskServiceServer
+
+	tm task.TaskService
+	do func(func())
+}
+
+// NewServer создает обработчик API. do должна выполнить функцию и дождаться ее завершения
+func NewServer(tm task.TaskService, do func(func())) *Server {
+	return &Server{tm: tm, do: do}
+}
+
+// New создает gRPC-сервер с API задач, который проверяет ключи клиентов через auth.
+// Если tlsConfig не nil, соединения шифруются
+func New(tm task.TaskService, do func(func()), auth *apiauth.Authenticator, tlsConfig *tls.Config) *grpc.Server {
+	options := []grpc.ServerOption{
+		grpc.UnaryInterceptor(unaryAuth(auth)),
+		grpc.StreamInterceptor(streamAuth(auth)),
+	}
+	if tlsConfig != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	server := grpc.NewServer(options...)
+	taskpb.RegisterTaskServiceServer(server, NewServer(tm, do))
+	return server
+}
+
+// Start запускает gRPC-сервер на адресе addr в отдельной горутине
+func Start(addr string, tm task.TaskService, do func(func()), auth *apiauth.Authenticator, tlsConfig *tls.Config) (*grpc.Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	server := New(tm, do, auth, tlsConfig)
+	go server.Serve(listener)
+	return server, nil
+}
+
+// ListTasks возвращает задачи с учетом поиска и фильтра
+func (s *Server) ListTasks(ctx context.Context, req *taskpb.ListTasksRequest) (*taskpb.ListTasksResponse, error) {
+	resp := &taskpb.ListTasksResponse{}
+	s.do(func() {
+		tasks := s.tm.Tasks()
+		if req.GetSearch() != "" {
+			tasks = s.tm.SearchTasks(req.GetSearch())
+		}
+		for _, t := range tasks {
+			if req.GetOnlyActive() && t.Completed {
+				continue
+			}
+			resp.Tasks = append(resp.Tasks, toProto(t))
+		}
+	})
+	return resp, nil
+}
+
+// GetTask возвращает задачу по ID
+func (s *Server) GetTask(ctx context.Context, req *taskpb.GetTaskRequest) (*taskpb.Task, error) {
+	var result *taskpb.Task
+	s.do(func() {
+		if t := s.tm.GetTask(int(req.GetId())); t != nil {
+			result = toProto(t)
+		}
+	})
+	if result == nil {
+		return nil, status.Errorf(codes.NotFound, "task %d not found", req.GetId())
+	}
+	return result, nil
+}
+
+// CreateTask добавляет задачу
+func (s *Server) CreateTask(ctx context.Context, req *taskpb.CreateTaskRequest) (*taskpb.Task, error) {
+	var result *taskpb.Task
+	var err error
+	s.do(func() {
+		var added *task.Task
+		added, err = s.tm.AddTask(req.GetTitle(), req.GetDescription(), int(req.GetPriority()), fromTimestamp(req.GetDueDate()))
+		if err != nil {
+			return
+		}
+		if len(req.GetTags()) > 0 {
+			if err = s.tm.SetTaskTags(added.ID, req.GetTags()); err != nil {
+				return
+			}
+		}
+		result = toProto(added)
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return result, nil
+}
+
+// UpdateTask изменяет задачу. Если клиент передал время изменения задачи,
+// которую он видел, а задачу с тех пор изменили, возвращает codes.Aborted
+func (s *Server) UpdateTask(ctx context.Context, req *taskpb.UpdateTaskRequest) (*taskpb.Task, error) {
+	id := int(req.GetId())
+	var result *taskpb.Task
+	var err error
+	s.do(func() {
+		if req.GetExpectedUpdatedAt() != nil {
+			if err = s.tm.CheckUnchanged(id, req.GetExpectedUpdatedAt().AsTime()); err != nil {
+				return
+			}
+		}
+		if err = s.tm.UpdateTask(id, req.GetTitle(), req.GetDescription(), int(req.GetPriority()),
+			fromTimestamp(req.GetDueDate()), req.GetCompleted()); err != nil {
+			return
+		}
+		if err = s.tm.SetTaskTags(id, req.GetTags()); err != nil {
+			return
+		}
+		result = toProto(s.tm.GetTask(id))
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return result, nil
+}
+
+// DeleteTask удаляет задачу
+func (s *Server) DeleteTask(ctx context.Context, req *taskpb.DeleteTaskRequest) (*taskpb.DeleteTaskResponse, error) {
+	var err error
+	s.do(func() {
+		err = s.tm.DeleteTask(int(req.GetId()))
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &taskpb.DeleteTaskResponse{}, nil
+}
+
+// Watch передает клиенту изменения задач, пока он не закроет поток.
+// Клиент, который не успевает читать события, отключается с codes.ResourceExhausted
+// и должен заново запросить список через ListTasks
+func (s *Server) Watch(req *taskpb.WatchRequest, stream grpc.ServerStreamingServer[taskpb.Event]) error {
+	var feed *task.Feed
+	s.do(func() {
+		feed = task.NewFeed(s.tm, watchBuffer)
+	})
+	defer feed.Close()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-feed.Overflow:
+			return status.Error(codes.ResourceExhausted, "client is too slow, reload the task list")
+		case e := <-feed.Events:
+			if err := stream.Send(toProtoEvent(e)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// toStatus переводит ошибки менеджера задач в коды gRPC
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, task.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, task.ErrValidation):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, task.ErrConflict):
+		return status.Error(codes.Aborted, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// toProto переводит задачу в сообщение API
+func toProto(t *task.Task) *taskpb.Task {
+	return &taskpb.Task{
+		Id:          int64(t.ID),
+		Uuid:        t.UUID,
+		Title:       t.Title,
+		Description: t.Description,
+		Priority:    int32(t.Priority),
+		DueDate:     toTimestamp(t.DueDate),
+		CreatedAt:   toTimestamp(t.CreatedAt),
+		UpdatedAt:   toTimestamp(t.UpdatedAt),
+		Completed:   t.Completed,
+		Tags:        append([]string(nil), t.Tags...),
+	}
+}
+
+// eventOps сопоставляет виды изменений менеджера задач и API
+var eventOps = map[task.EventOp]taskpb.Event_Op{
+	task.EventAdded:     taskpb.Event_OP_ADDED,
+	task.EventUpdated:   taskpb.Event_OP_UPDATED,
+	task.EventCompleted: taskpb.Event_OP_COMPLETED,
+	task.EventDeleted:   taskpb.Event_OP_DELETED,
+	task.EventReloaded:  taskpb.Event_OP_RELOADED,
+	// В API нет отдельного вида для отметки повторения: задача просто изменилась
+	task.EventOccurrence: taskpb.Event_OP_UPDATED,
+}
+
+// toProtoEvent переводит событие менеджера задач в сообщение API
+func toProtoEvent(e task.Event) *taskpb.Event {
+	event := &taskpb.Event{Op: eventOps[e.Op]}
+	if e.Task != nil {
+		event.Task = toProto(e.Task)
+	}
+	return event
+}
+
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func fromTimestamp(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
@@ -0,0 +1,151 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"taskmanager/apiauth"
+	"taskmanager/grpcapi/taskpb"
+	"taskmanager/storage"
+	"taskmanager/task"
+)
+
+// newTestClient поднимает сервер с ключами API keys и возвращает клиент к нему.
+// Без ключей сервер доступен тестам, так как они подключаются с этого компьютера
+func newTestClient(t *testing.T, keys ...apiauth.Key) (taskpb.TaskServiceClient, *task.TaskManager, func(func())) {
+	t.Helper()
+	tm := task.NewTaskManager(storage.NewFile(filepath.Join(t.TempDir(), "tasks.json")))
+
+	var mu sync.Mutex
+	do := func(fn func()) {
+		mu.Lock()
+		defer mu.Unlock()
+		fn()
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	server := New(tm, do, apiauth.New(keys), nil)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() { conn.Close() })
+	return taskpb.NewTaskServiceClient(conn), tm, do
+}
+
+func TestCRUD(t *testing.T) {
+	client, _, _ := newTestClient(t)
+	ctx := context.Background()
+	due := timestamppb.New(time.Now().Add(24 * time.Hour))
+
+	created, err := client.CreateTask(ctx, &taskpb.CreateTaskRequest{
+		Title: "Task", Description: "Description", Priority: 2, DueDate: due, Tags: []string{"work"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), created.Id)
+	assert.NotEmpty(t, created.Uuid)
+	assert.Equal(t, []string{"work"}, created.Tags)
+
+	got, err := client.GetTask(ctx, &taskpb.GetTaskRequest{Id: created.Id})
+	assert.NoError(t, err)
+	assert.Equal(t, "Task", got.Title)
+
+	updated, err := client.UpdateTask(ctx, &taskpb.UpdateTaskRequest{
+		Id: created.Id, Title: "Updated", Priority: 3, DueDate: due, Completed: true,
+		ExpectedUpdatedAt: created.UpdatedAt,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Updated", updated.Title)
+	assert.True(t, updated.Completed)
+	assert.Empty(t, updated.Tags)
+
+	list, err := client.ListTasks(ctx, &taskpb.ListTasksRequest{OnlyActive: true})
+	assert.NoError(t, err)
+	assert.Empty(t, list.Tasks)
+
+	list, err = client.ListTasks(ctx, &taskpb.ListTasksRequest{Search: "updated"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(list.Tasks))
+
+	_, err = client.DeleteTask(ctx, &taskpb.DeleteTaskRequest{Id: created.Id})
+	assert.NoError(t, err)
+
+	_, err = client.GetTask(ctx, &taskpb.GetTaskRequest{Id: created.Id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.DeleteTask(ctx, &taskpb.DeleteTaskRequest{Id: created.Id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestErrorCodes(t *testing.T) {
+	client, _, _ := newTestClient(t)
+	ctx := context.Background()
+	due := timestamppb.New(time.Now().Add(24 * time.Hour))
+
+	_, err := client.CreateTask(ctx, &taskpb.CreateTaskRequest{Title: "", Priority: 1, DueDate: due})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	created, err := client.CreateTask(ctx, &taskpb.CreateTaskRequest{Title: "Task", Priority: 1, DueDate: due})
+	assert.NoError(t, err)
+
+	// Задачу изменили после того, как клиент ее прочитал
+	stale := timestamppb.New(created.UpdatedAt.AsTime().Add(-time.Minute))
+	_, err = client.UpdateTask(ctx, &taskpb.UpdateTaskRequest{
+		Id: created.Id, Title: "Updated", Priority: 1, DueDate: due, ExpectedUpdatedAt: stale,
+	})
+	assert.Equal(t, codes.Aborted, status.Code(err))
+}
+
+func TestWatch(t *testing.T) {
+	client, tm, do := newTestClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.Watch(ctx, &taskpb.WatchRequest{})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	// Подписка оформляется на сервере асинхронно: добавляем задачи, пока не придет событие
+	received := make(chan *taskpb.Event)
+	go func() {
+		event, err := stream.Recv()
+		if err == nil {
+			received <- event
+		}
+	}()
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event := <-received:
+			assert.Equal(t, taskpb.Event_OP_ADDED, event.Op)
+			assert.Equal(t, "Task", event.Task.Title)
+			return
+		case <-ticker.C:
+			do(func() {
+				tm.AddTask("Task", "Description", 1, time.Now().Add(time.Hour))
+			})
+		case <-timeout:
+			t.Fatal("no event received")
+		}
+	}
+}
@@ -0,0 +1,171 @@
+// Package holidays - календарь праздников и выходных: встроенные праздники нескольких стран
+// или календарь ICS по ссылке. По нему повторяющиеся задачи и перенос срока пропускают
+// нерабочие дни. Пакет не зависит от интерфейса
+package holidays
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrUnknownCountry возвращается для страны, праздников которой нет во встроенном календаре
+var ErrUnknownCountry = errors.New("unknown holiday country")
+
+// dayLayout - формат дня в календаре
+const dayLayout = "2006-01-02"
+
+// Country - страна встроенного календаря
+type Country struct {
+	Code string // ISO 3166-1: RU, US
+	Name string
+}
+
+// Countries - страны встроенного календаря в порядке показа
+var Countries = []Country{
+	{"RU", "Россия"},
+	{"US", "США"},
+	{"GB", "Великобритания"},
+	{"DE", "Германия"},
+}
+
+// Calendar - праздничные дни с названиями. Нулевой календарь пуст: нерабочие дни - только выходные
+type Calendar struct {
+	days map[string]string
+}
+
+// Add добавляет праздник в день day. Если в этот день уже есть праздник, названия объединяются
+func (c *Calendar) Add(day time.Time, name string) {
+	if c.days == nil {
+		c.days = make(map[string]string)
+	}
+	key := day.Format(dayLayout)
+	if existing, ok := c.days[key]; ok && existing != name {
+		name = existing + ", " + name
+	}
+	c.days[key] = name
+}
+
+// Holiday возвращает название праздника в день day
+func (c *Calendar) Holiday(day time.Time) (name string, ok bool) {
+	if c == nil {
+		return "", false
+	}
+	name, ok = c.days[day.Format(dayLayout)]
+	return name, ok
+}
+
+// Len возвращает число праздничных дней
+func (c *Calendar) Len() int {
+	if c == nil {
+		return 0
+	}
+	return len(c.days)
+}
+
+// DayOff сообщает, нерабочий ли день day: суббота, воскресенье или праздник
+func (c *Calendar) DayOff(day time.Time) bool {
+	if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		return true
+	}
+	_, ok := c.Holiday(day)
+	return ok
+}
+
+// Builtin возвращает праздники страны country с года from по год to включительно.
+// Переносы выходных, которые объявляются каждый год отдельно, в календарь не входят
+func Builtin(country string, from, to int) (*Calendar, error) {
+	rules, ok := countryRules[country]
+	if !ok {
+		return nil, ErrUnknownCountry
+	}
+	c := &Calendar{}
+	for year := from; year <= to; year++ {
+		rules(c, year)
+	}
+	return c, nil
+}
+
+// countryRules добавляет в календарь праздники страны за год
+var countryRules = map[string]func(c *Calendar, year int){
+	"RU": func(c *Calendar, year int) {
+		for day := 1; day <= 8; day++ {
+			if day == 7 {
+				c.Add(date(year, time.January, day), "Рождество Христово")
+				continue
+			}
+			c.Add(date(year, time.January, day), "Новогодние каникулы")
+		}
+		c.Add(date(year, time.February, 23), "День защитника Отечества")
+		c.Add(date(year, time.March, 8), "Международный женский день")
+		c.Add(date(year, time.May, 1), "Праздник Весны и Труда")
+		c.Add(date(year, time.May, 9), "День Победы")
+		c.Add(date(year, time.June, 12), "День России")
+		c.Add(date(year, time.November, 4), "День народного единства")
+	},
+	"US": func(c *Calendar, year int) {
+		c.Add(date(year, time.January, 1), "New Year's Day")
+		c.Add(nthWeekday(year, time.January, time.Monday, 3), "Martin Luther King Jr. Day")
+		c.Add(nthWeekday(year, time.February, time.Monday, 3), "Presidents' Day")
+		c.Add(nthWeekday(year, time.May, time.Monday, -1), "Memorial Day")
+		c.Add(date(year, time.June, 19), "Juneteenth")
+		c.Add(date(year, time.July, 4), "Independence Day")
+		c.Add(nthWeekday(year, time.September, time.Monday, 1), "Labor Day")
+		c.Add(nthWeekday(year, time.October, time.Monday, 2), "Columbus Day")
+		c.Add(date(year, time.November, 11), "Veterans Day")
+		c.Add(nthWeekday(year, time.November, time.Thursday, 4), "Thanksgiving Day")
+		c.Add(date(year, time.December, 25), "Christmas Day")
+	},
+	"GB": func(c *Calendar, year int) {
+		easter := Easter(year)
+		c.Add(date(year, time.January, 1), "New Year's Day")
+		c.Add(easter.AddDate(0, 0, -2), "Good Friday")
+		c.Add(easter.AddDate(0, 0, 1), "Easter Monday")
+		c.Add(nthWeekday(year, time.May, time.Monday, 1), "Early May bank holiday")
+		c.Add(nthWeekday(year, time.May, time.Monday, -1), "Spring bank holiday")
+		c.Add(nthWeekday(year, time.August, time.Monday, -1), "Summer bank holiday")
+		c.Add(date(year, time.December, 25), "Christmas Day")
+		c.Add(date(year, time.December, 26), "Boxing Day")
+	},
+	"DE": func(c *Calendar, year int) {
+		easter := Easter(year)
+		c.Add(date(year, time.January, 1), "Neujahr")
+		c.Add(easter.AddDate(0, 0, -2), "Karfreitag")
+		c.Add(easter.AddDate(0, 0, 1), "Ostermontag")
+		c.Add(date(year, time.May, 1), "Tag der Arbeit")
+		c.Add(easter.AddDate(0, 0, 39), "Christi Himmelfahrt")
+		c.Add(easter.AddDate(0, 0, 50), "Pfingstmontag")
+		c.Add(date(year, time.October, 3), "Tag der Deutschen Einheit")
+		c.Add(date(year, time.December, 25), "1. Weihnachtstag")
+		c.Add(date(year, time.December, 26), "2. Weihnachtstag")
+	},
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// nthWeekday возвращает n-й день недели weekday месяца; n = -1 - последний
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	if n < 0 {
+		last := date(year, month+1, 0)
+		return last.AddDate(0, 0, -((int(last.Weekday()) - int(weekday) + 7) % 7))
+	}
+	first := date(year, month, 1)
+	return first.AddDate(0, 0, (int(weekday)-int(first.Weekday())+7)%7+7*(n-1))
+}
+
+// Easter возвращает день западной Пасхи по григорианскому календарю (алгоритм Meeus/Jones/Butcher)
+func Easter(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return date(year, time.Month(month), day)
+}
@@ -0,0 +1,94 @@
+package holidays
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func day(s string) time.Time {
+	t, err := time.Parse(dayLayout, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestEaster(t *testing.T) {
+	assert.Equal(t, "2024-03-31", Easter(2024).Format(dayLayout))
+	assert.Equal(t, "2026-04-05", Easter(2026).Format(dayLayout))
+	assert.Equal(t, "2027-03-28", Easter(2027).Format(dayLayout))
+}
+
+func TestBuiltin(t *testing.T) {
+	ru, err := Builtin("RU", 2026, 2026)
+	require.NoError(t, err)
+	name, ok := ru.Holiday(day("2026-01-07"))
+	assert.True(t, ok)
+	assert.Equal(t, "Рождество Христово", name)
+	_, ok = ru.Holiday(day("2026-01-09"))
+	assert.False(t, ok)
+	assert.Equal(t, 14, ru.Len())
+
+	us, err := Builtin("US", 2026, 2026)
+	require.NoError(t, err)
+	name, _ = us.Holiday(day("2026-11-26"))
+	assert.Equal(t, "Thanksgiving Day", name)
+	name, _ = us.Holiday(day("2026-05-25"))
+	assert.Equal(t, "Memorial Day", name)
+
+	de, err := Builtin("DE", 2026, 2027)
+	require.NoError(t, err)
+	name, _ = de.Holiday(day("2027-03-29"))
+	assert.Equal(t, "Ostermontag", name)
+
+	_, err = Builtin("XX", 2026, 2026)
+	assert.ErrorIs(t, err, ErrUnknownCountry)
+}
+
+func TestDayOff(t *testing.T) {
+	ru, err := Builtin("RU", 2026, 2026)
+	require.NoError(t, err)
+	assert.True(t, ru.DayOff(day("2026-11-04")))  // среда, праздник
+	assert.True(t, ru.DayOff(day("2026-10-17")))  // суббота
+	assert.False(t, ru.DayOff(day("2026-10-16"))) // пятница
+
+	// Без календаря нерабочие дни - только выходные
+	var none *Calendar
+	assert.True(t, none.DayOff(day("2026-10-18")))
+	assert.False(t, none.DayOff(day("2026-11-04")))
+	assert.Zero(t, none.Len())
+}
+
+func TestParseICS(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VTIMEZONE\r\nTZID:Europe/Moscow\r\nBEGIN:STANDARD\r\nDTSTART:19700101T000000\r\nEND:STANDARD\r\nEND:VTIMEZONE\r\n" +
+		"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20261104\r\nDTEND;VALUE=DATE:20261105\r\nSUMMARY:День народного \r\n единства\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20261231\r\nDTEND;VALUE=DATE:20270103\r\nSUMMARY:Каникулы\\, часть 1\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nDTSTART:20260601T090000Z\r\nSUMMARY:Отпуск\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20260701\r\nDTEND;VALUE=DATE:20260901\r\nSUMMARY:Лето\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	c, err := ParseICS(strings.NewReader(data))
+	require.NoError(t, err)
+	name, ok := c.Holiday(day("2026-11-04"))
+	assert.True(t, ok)
+	assert.Equal(t, "День народного единства", name)
+	name, _ = c.Holiday(day("2027-01-02"))
+	assert.Equal(t, "Каникулы, часть 1", name)
+	_, ok = c.Holiday(day("2027-01-03"))
+	assert.False(t, ok, "DTEND is exclusive")
+	_, ok = c.Holiday(day("2026-06-01"))
+	assert.True(t, ok)
+	// Длинные события праздниками не считаются
+	_, ok = c.Holiday(day("2026-07-15"))
+	assert.False(t, ok)
+	assert.Equal(t, 5, c.Len())
+
+	_, err = ParseICS(strings.NewReader("BEGIN:VEVENT\nDTSTART:2026\nEND:VEVENT\n"))
+	assert.Error(t, err)
+	_, err = ParseICS(strings.NewReader("BEGIN:VEVENT\nSUMMARY:Без даты\nEND:VEVENT\n"))
+	assert.Error(t, err)
+}
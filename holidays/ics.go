@@ -0,0 +1,143 @@
+package holidays
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// fetchTimeout - сколько ждать календарь ICS
+const fetchTimeout = 30 * time.Second
+
+// maxEventDays - самое длинное событие, дни которого попадают в календарь. Длинные события
+// вроде «Отпуск» или «Учебный год» праздниками не считаются
+const maxEventDays = 31
+
+// ParseICS читает праздники из календаря ICS: каждый день событий VEVENT - праздник
+// с названием из SUMMARY. Время событий не учитывается, только их дни
+func ParseICS(r io.Reader) (*Calendar, error) {
+	c := &Calendar{}
+	var inEvent bool
+	var summary string
+	var start, end time.Time
+	for _, line := range unfoldICS(r) {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		// Параметры свойства не нужны: DTSTART;VALUE=DATE:20260101
+		name, _, _ = strings.Cut(name, ";")
+		name = strings.ToUpper(name)
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			inEvent, summary, start, end = true, "", time.Time{}, time.Time{}
+		case !inEvent:
+			// Свойства вне событий, например DTSTART часового пояса, не нужны
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			inEvent = false
+			if start.IsZero() {
+				return nil, fmt.Errorf("ics event %q has no start date", summary)
+			}
+			addEvent(c, summary, start, end)
+		case name == "SUMMARY":
+			summary = unescapeICS(value)
+		case name == "DTSTART" || name == "DTEND":
+			day, err := parseICSDate(value)
+			if err != nil {
+				return nil, err
+			}
+			if name == "DTSTART" {
+				start = day
+			} else {
+				end = day
+			}
+		}
+	}
+	return c, nil
+}
+
+// addEvent добавляет дни события. DTEND событий на весь день не входит в событие
+func addEvent(c *Calendar, summary string, start, end time.Time) {
+	if !end.After(start) {
+		end = start.AddDate(0, 0, 1)
+	}
+	if end.Sub(start) > maxEventDays*24*time.Hour {
+		return
+	}
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		c.Add(day, summary)
+	}
+}
+
+// parseICSDate разбирает день события: 20260101 или 20260101T090000Z
+func parseICSDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid ics date %q", value)
+	}
+	day, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid ics date %q: %w", value, err)
+	}
+	return day, nil
+}
+
+// unfoldICS читает строки календаря, склеивая перенесенные: продолжение строки начинается с пробела
+func unfoldICS(r io.Reader) []string {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// unescapeICS убирает экранирование текста ICS: \, \; \n
+func unescapeICS(value string) string {
+	return strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ", `\N`, " ", `\\`, `\`).Replace(value)
+}
+
+// FetchICS загружает календарь ICS по ссылке url и возвращает его содержимое
+func FetchICS(ctx context.Context, url string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+
+	// Ссылки из Google Календаря и Outlook часто даются со схемой webcal
+	if rest, ok := strings.CutPrefix(url, "webcal://"); ok {
+		url = "https://" + rest
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected response from calendar server: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return "", err
+	}
+	// Проверяем, что это календарь, чтобы не сохранить страницу входа или ошибки
+	if !strings.Contains(string(data), "BEGIN:VCALENDAR") {
+		return "", errors.New("response is not an ics calendar")
+	}
+	if _, err := ParseICS(strings.NewReader(string(data))); err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,109 @@
+package httpapi
+
+import (
+	"errors"
+	"html/template"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"taskmanager/apiauth"
+)
+
+var loginTemplate = template.Must(template.ParseFS(templateFS, "templates/login.html"))
+
+const (
+	// keyCookie - cookie, в которой браузер хранит ключ API после входа
+	keyCookie = "taskmanager_key"
+	// keyCookieMaxAge - сколько браузер помнит ключ
+	keyCookieMaxAge = 90 * 24 * time.Hour
+)
+
+// loginPage - данные страницы входа
+type loginPage struct {
+	Error string
+}
+
+// requestKey возвращает ключ API из заголовка Authorization или cookie
+func requestKey(r *http.Request) string {
+	if key := apiauth.BearerToken(r.Header.Get("Authorization")); key != "" {
+		return key
+	}
+	if cookie, err := r.Cookie(keyCookie); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+// require пропускает запрос, только если у клиента есть права need.
+// Вместо главной страницы неавторизованному клиенту показывается страница входа
+func (s *Server) require(need apiauth.Scope, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Доступ без ключа защищен от подмены DNS (DNS rebinding): страница чужого сайта,
+		// имя которого стало указывать на 127.0.0.1, не получает права этого компьютера
+		if !s.auth.HasKeys() && !isLocalHost(r.Host) {
+			http.Error(w, "unknown host", http.StatusForbidden)
+			return
+		}
+		err := s.auth.Check(requestKey(r), r.RemoteAddr, need)
+		switch {
+		case errors.Is(err, apiauth.ErrUnauthenticated):
+			if r.Method == http.MethodGet && r.URL.Path == "/" {
+				renderLogin(w, http.StatusUnauthorized, "")
+				return
+			}
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			next(w, r)
+		}
+	}
+}
+
+// isLocalHost сообщает, что в заголовке Host - localhost или IP-адрес, с портом или без:
+// такие имена чужой сайт не может направить на этот компьютер
+func isLocalHost(hostport string) bool {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = hostport
+	}
+	host = strings.TrimSuffix(strings.Trim(host, "[]"), ".")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	return net.ParseIP(host) != nil
+}
+
+// readOnly сообщает, что клиенту доступно только чтение
+func (s *Server) readOnly(r *http.Request) bool {
+	scope, err := s.auth.Authorize(requestKey(r), r.RemoteAddr)
+	return err != nil || !scope.Allows(apiauth.ScopeWrite)
+}
+
+// handleLogin запоминает ключ API в cookie браузера
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	key := r.FormValue("key")
+	if _, err := s.auth.Authorize(key, r.RemoteAddr); err != nil {
+		renderLogin(w, http.StatusUnauthorized, "Неверный ключ")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     keyCookie,
+		Value:    key,
+		Path:     "/",
+		MaxAge:   int(keyCookieMaxAge / time.Second),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+func renderLogin(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	loginTemplate.Execute(w, loginPage{Error: message})
+}
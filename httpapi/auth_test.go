@@ -0,0 +1,158 @@
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/apiauth"
+	"taskmanager/storage"
+	"taskmanager/task"
+)
+
+func TestAuthRequiresKey(t *testing.T) {
+	key, _ := apiauth.NewKey("phone", apiauth.ScopeWrite)
+	server, _, _ := newTestServer(t, key)
+
+	// Главная страница предлагает войти, остальные запросы отклоняются
+	resp, err := server.Client().Get(server.URL + "/")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/html")
+
+	resp, err = server.Client().PostForm(server.URL+"/tasks", url.Values{"title": {"Task"}})
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	_, resp, err = websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestKeylessAccessChecksHost(t *testing.T) {
+	server, tm, do := newTestServer(t)
+	client := server.Client()
+	client.CheckRedirect = noRedirect
+
+	post := func(host string) int {
+		req, _ := http.NewRequest(http.MethodPost, server.URL+"/tasks",
+			strings.NewReader(url.Values{"title": {"Task"}, "priority": {"1"}}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Host = host
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	// Без ключей чужое имя, направленное на 127.0.0.1, не получает доступа
+	assert.Equal(t, http.StatusForbidden, post("evil.example:8080"))
+	assert.Equal(t, http.StatusSeeOther, post("localhost:8080"))
+	assert.Equal(t, http.StatusSeeOther, post("[::1]:8080"))
+	assert.Equal(t, http.StatusSeeOther, post(strings.TrimPrefix(server.URL, "http://")))
+	do(func() {
+		assert.Equal(t, 3, len(tm.Tasks()))
+	})
+
+	// С ключами имя сервера может быть любым: доступ дает только ключ
+	key, secret := apiauth.NewKey("phone", apiauth.ScopeRead)
+	server, _, _ = newTestServer(t, key)
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/", nil)
+	req.Host = "tasks.example:8080"
+	req.Header.Set("Authorization", "Bearer "+secret)
+	resp, err := server.Client().Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestAuthScopes(t *testing.T) {
+	readKey, readSecret := apiauth.NewKey("reader", apiauth.ScopeRead)
+	writeKey, writeSecret := apiauth.NewKey("writer", apiauth.ScopeWrite)
+	server, tm, do := newTestServer(t, readKey, writeKey)
+	client := server.Client()
+	client.CheckRedirect = noRedirect
+
+	post := func(secret string) int {
+		req, _ := http.NewRequest(http.MethodPost, server.URL+"/tasks",
+			strings.NewReader(url.Values{"title": {"Task"}, "priority": {"1"}, "due": {"2030-01-02"}}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Authorization", "Bearer "+secret)
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	assert.Equal(t, http.StatusForbidden, post(readSecret))
+	assert.Equal(t, http.StatusSeeOther, post(writeSecret))
+	do(func() {
+		assert.Equal(t, 1, len(tm.Tasks()))
+	})
+
+	header := http.Header{"Authorization": {"Bearer " + readSecret}}
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", header)
+	if assert.NoError(t, err) {
+		conn.Close()
+	}
+}
+
+func TestLogin(t *testing.T) {
+	key, secret := apiauth.NewKey("phone", apiauth.ScopeRead)
+	server, _, _ := newTestServer(t, key)
+	client := server.Client()
+	client.CheckRedirect = noRedirect
+
+	resp, err := client.PostForm(server.URL+"/login", url.Values{"key": {"wrong"}})
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, err = client.PostForm(server.URL+"/login", url.Values{"key": {secret}})
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusSeeOther, resp.StatusCode)
+
+	cookies := resp.Cookies()
+	if !assert.Equal(t, 1, len(cookies)) {
+		t.FailNow()
+	}
+	assert.True(t, cookies[0].HttpOnly)
+
+	// С cookie главная страница открывается, но только для чтения
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/", nil)
+	req.AddCookie(cookies[0])
+	resp, err = client.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestHandleRequiresScope(t *testing.T) {
+	key, secret := apiauth.NewKey("prometheus", apiauth.ScopeRead)
+	tm := task.NewTaskManager(storage.NewFile(filepath.Join(t.TempDir(), "tasks.json")))
+	s := NewServer(tm, func(fn func()) { fn() }, apiauth.New([]apiauth.Key{key}))
+	s.Handle("GET /metrics", apiauth.ScopeRead, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("metrics"))
+	}))
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL + "/metrics")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/metrics", nil)
+	req.Header.Set("Authorization", "Bearer "+secret)
+	resp, err = server.Client().Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
@@ -0,0 +1,53 @@
+package httpapi
+
+import (
+	"bytes"
+	"net/http"
+
+	"taskmanager/apiauth"
+	"taskmanager/plugins"
+	"taskmanager/task"
+)
+
+// calendarName - название календаря задач при подписке
+const calendarName = "Задачи"
+
+// feedKey передает ключ API из адреса (?key=) или пароля HTTP Basic в заголовок Authorization.
+// Программы календарей при подписке не умеют отправлять заголовки, но умеют адрес с паролем
+func feedKey(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if apiauth.BearerToken(r.Header.Get("Authorization")) == "" {
+			key := r.URL.Query().Get("key")
+			if _, password, ok := r.BasicAuth(); ok && key == "" {
+				key = password
+			}
+			if key != "" {
+				r = r.Clone(r.Context())
+				r.Header.Set("Authorization", "Bearer "+key)
+			}
+		}
+		next(w, r)
+	}
+}
+
+// handleCalendar отдает задачи календарем iCalendar для подписки только на чтение: задачи (VTODO)
+// и события на день срока невыполненных задач (VEVENT). Календарь собирается при каждом запросе
+func (s *Server) handleCalendar(w http.ResponseWriter, r *http.Request) {
+	var tasks []*task.Task
+	s.do(func() {
+		for _, t := range task.SortTasks(s.tm.Tasks(), task.SortByDueDate, false) {
+			if !t.Archived {
+				tasks = append(tasks, t.Clone())
+			}
+		}
+	})
+
+	var out bytes.Buffer
+	if err := (plugins.ICalendar{Events: true, Title: calendarName}).Run(r.Context(), tasks, &out); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write(out.Bytes())
+}
@@ -0,0 +1,64 @@
+package httpapi
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"taskmanager/apiauth"
+)
+
+func TestCalendar(t *testing.T) {
+	key, secret := apiauth.NewKey("calendar", apiauth.ScopeRead)
+	server, tm, do := newTestServer(t, key)
+	do(func() {
+		_, err := tm.AddTask("Купить молоко", "", 1, time.Date(2030, 1, 7, 0, 0, 0, 0, time.Local))
+		require.NoError(t, err)
+		done, err := tm.AddTask("Сдать отчет", "", 3, time.Date(2030, 1, 8, 0, 0, 0, 0, time.Local))
+		require.NoError(t, err)
+		require.NoError(t, tm.ToggleTaskCompletion(done.ID))
+	})
+
+	get := func(url string, prepare func(*http.Request)) (int, string) {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		if prepare != nil {
+			prepare(req)
+		}
+		resp, err := server.Client().Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	status, _ := get(server.URL+"/calendar.ics", nil)
+	assert.Equal(t, http.StatusUnauthorized, status)
+	status, _ = get(server.URL+"/calendar.ics?key=wrong", nil)
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	// Календари передают ключ в адресе или паролем
+	status, body := get(server.URL+"/calendar.ics?key="+secret, nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "X-WR-CALNAME:Задачи\r\n")
+	assert.Equal(t, 2, strings.Count(body, "BEGIN:VTODO"))
+	assert.Equal(t, 1, strings.Count(body, "BEGIN:VEVENT"))
+	assert.Contains(t, body, "DTSTART;VALUE=DATE:20300107\r\n")
+
+	status, _ = get(server.URL+"/calendar.ics", func(r *http.Request) { r.SetBasicAuth("", secret) })
+	assert.Equal(t, http.StatusOK, status)
+	status, _ = get(server.URL+"/calendar.ics", func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+secret) })
+	assert.Equal(t, http.StatusOK, status)
+
+	// Календарь собирается при каждом запросе
+	do(func() {
+		_, err := tm.AddTask("Позвонить маме", "", 2, time.Date(2030, 1, 9, 0, 0, 0, 0, time.Local))
+		require.NoError(t, err)
+	})
+	_, body = get(server.URL+"/calendar.ics?key="+secret, nil)
+	assert.Contains(t, body, "SUMMARY:Позвонить маме\r\n")
+}
@@ -0,0 +1,156 @@
+package httpapi
+
+import (
+	"bufio"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// requestRate и requestBurst - сколько запросов в секунду и подряд разрешено одному клиенту
+	requestRate  = 10
+	requestBurst = 30
+	// clientIdleTimeout - через сколько забывать клиента, который не присылает запросов
+	clientIdleTimeout = 10 * time.Minute
+	// healthTimeout - сколько ждать ответа менеджера задач при проверке /healthz
+	healthTimeout = 2 * time.Second
+)
+
+// clientLimiter ограничивает частоту запросов каждого клиента (по IP-адресу)
+type clientLimiter struct {
+	mu        sync.Mutex
+	limit     rate.Limit
+	burst     int
+	clients   map[string]*clientRate
+	lastSweep time.Time
+}
+
+type clientRate struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func newClientLimiter(limit rate.Limit, burst int) *clientLimiter {
+	return &clientLimiter{limit: limit, burst: burst, clients: make(map[string]*clientRate)}
+}
+
+// Allow сообщает, можно ли обработать очередной запрос клиента
+func (l *clientLimiter) Allow(client string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Время от времени забываем неактивных клиентов, чтобы карта не росла бесконечно
+	if now.Sub(l.lastSweep) >= clientIdleTimeout {
+		for id, c := range l.clients {
+			if now.Sub(c.lastSeen) >= clientIdleTimeout {
+				delete(l.clients, id)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	c, ok := l.clients[client]
+	if !ok {
+		c = &clientRate{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.clients[client] = c
+	}
+	c.lastSeen = now
+	return c.limiter.AllowN(now, 1)
+}
+
+// limitRate отвечает 429 Too Many Requests клиентам, которые превысили частоту запросов
+func (s *Server) limitRate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.limiter.Allow(clientIP(r), time.Now()) {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "too many requests", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// logRequests записывает в журнал обработанные запросы
+func (s *Server) logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		// Успешные запросы пишутся только в подробный журнал, чтобы частый опрос
+		// сервера скриптами не вытеснял из журнала остальные сообщения
+		level := slog.LevelDebug
+		if recorder.status >= http.StatusBadRequest {
+			level = slog.LevelInfo
+		}
+		s.logger.Log(r.Context(), level, "http request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", recorder.status,
+			"bytes", recorder.bytes,
+			"duration", time.Since(start),
+			"client", clientIP(r),
+		)
+	})
+}
+
+// handleHealth сообщает, что сервер работает и менеджер задач отвечает.
+// Не требует ключа API, чтобы ее могли опрашивать системы мониторинга
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	done := make(chan struct{})
+	go s.do(func() { close(done) })
+
+	select {
+	case <-done:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte("ok\n"))
+	case <-time.After(healthTimeout):
+		http.Error(w, "task manager is not responding", http.StatusServiceUnavailable)
+	}
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// statusRecorder запоминает код ответа и размер тела для журнала
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(data []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(data)
+	r.bytes += n
+	return n, err
+}
+
+// Hijack нужен для WebSocket: соединение передается обработчику /ws
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("hijacking is not supported")
+	}
+	r.status = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,82 @@
+package httpapi
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/apiauth"
+)
+
+func TestClientLimiter(t *testing.T) {
+	limiter := newClientLimiter(1, 2)
+	now := time.Now()
+
+	assert.True(t, limiter.Allow("10.0.0.1", now))
+	assert.True(t, limiter.Allow("10.0.0.1", now))
+	assert.False(t, limiter.Allow("10.0.0.1", now))
+	// Другие клиенты не страдают из-за одного слишком активного
+	assert.True(t, limiter.Allow("10.0.0.2", now))
+	// Лимит восстанавливается со временем
+	assert.True(t, limiter.Allow("10.0.0.1", now.Add(time.Second)))
+
+	// Неактивные клиенты забываются
+	limiter.Allow("10.0.0.3", now.Add(2*clientIdleTimeout))
+	assert.Equal(t, 1, len(limiter.clients))
+}
+
+func TestRateLimitResponse(t *testing.T) {
+	server, _, _ := newTestServer(t)
+
+	var limited *http.Response
+	for range requestBurst + 1 {
+		resp, err := server.Client().Get(server.URL + "/healthz")
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusTooManyRequests {
+			limited = resp
+			break
+		}
+	}
+	if assert.NotNil(t, limited) {
+		assert.Equal(t, "1", limited.Header.Get("Retry-After"))
+	}
+}
+
+func TestHealthz(t *testing.T) {
+	key, _ := apiauth.NewKey("phone", apiauth.ScopeRead)
+	server, _, _ := newTestServer(t, key)
+
+	// Проверка работоспособности доступна без ключа
+	resp, err := server.Client().Get(server.URL + "/healthz")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewServer(nil, func(fn func()) { fn() }, apiauth.New(nil))
+	s.logger = slog.New(slog.NewJSONHandler(&buf, nil))
+
+	req := httptest.NewRequest(http.MethodGet, "/missing", nil)
+	req.RemoteAddr = "127.0.0.1:5000"
+	s.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Contains(t, buf.String(), `"path":"/missing"`)
+	assert.Contains(t, buf.String(), `"status":404`)
+	assert.Contains(t, buf.String(), `"client":"127.0.0.1"`)
+
+	// Успешные запросы видны только на отладочном уровне
+	buf.Reset()
+	req = httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	s.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Empty(t, buf.String())
+}
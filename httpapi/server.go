@@ -0,0 +1,130 @@
+// Package httpapi - встроенный HTTP-сервер менеджера задач:
+// простой веб-интерфейс, поток изменений для веб-панелей и календарь задач для подписки
+package httpapi
+
+import (
+	"crypto/tls"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"taskmanager/apiauth"
+	"taskmanager/task"
+)
+
+const (
+	// wsBuffer - сколько событий может накопиться для медленного клиента
+	// до того, как соединение будет закрыто
+	wsBuffer = 64
+	// wsWriteTimeout - сколько ждать отправки одного события
+	wsWriteTimeout = 10 * time.Second
+)
+
+// Server обрабатывает HTTP-запросы к менеджеру задач.
+// Менеджер задач не потокобезопасен, поэтому все обращения к нему
+// выполняются через do (в приложении - в потоке интерфейса)
+type Server struct {
+	tm       task.TaskService
+	do       func(func())
+	auth     *apiauth.Authenticator
+	mux      *http.ServeMux
+	handler  http.Handler
+	upgrader websocket.Upgrader
+	limiter  *clientLimiter
+	logger   *slog.Logger
+}
+
+// NewServer создает обработчик HTTP-запросов. do должна выполнить функцию и дождаться ее завершения,
+// auth проверяет ключи API клиентов
+func NewServer(tm task.TaskService, do func(func()), auth *apiauth.Authenticator) *Server {
+	s := &Server{
+		tm:      tm,
+		do:      do,
+		auth:    auth,
+		mux:     http.NewServeMux(),
+		limiter: newClientLimiter(requestRate, requestBurst),
+		logger:  slog.Default(),
+	}
+	s.mux.HandleFunc("GET /healthz", s.handleHealth)
+	s.mux.HandleFunc("GET /{$}", s.require(apiauth.ScopeRead, s.handleIndex))
+	s.mux.HandleFunc("POST /login", sameOrigin(s.handleLogin))
+	s.mux.HandleFunc("POST /tasks", sameOrigin(s.require(apiauth.ScopeWrite, s.handleAdd)))
+	s.mux.HandleFunc("POST /tasks/{id}/toggle", sameOrigin(s.require(apiauth.ScopeWrite, s.handleToggle)))
+	s.mux.HandleFunc("GET /ws", s.require(apiauth.ScopeRead, s.handleWS))
+	s.mux.HandleFunc("GET /calendar.ics", feedKey(s.require(apiauth.ScopeRead, s.handleCalendar)))
+	s.handler = s.logRequests(s.limitRate(s.mux))
+	return s
+}
+
+// Handle добавляет обработчик, доступный клиентам с правами scope (например, /metrics)
+func (s *Server) Handle(pattern string, scope apiauth.Scope, handler http.Handler) {
+	s.mux.HandleFunc(pattern, s.require(scope, handler.ServeHTTP))
+}
+
+// Start запускает HTTP-сервер на адресе addr в отдельной горутине.
+// Если tlsConfig не nil, сервер работает по HTTPS
+func Start(addr string, handler http.Handler, tlsConfig *tls.Config) (*http.Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	server := &http.Server{Handler: handler, TLSConfig: tlsConfig}
+	if tlsConfig != nil {
+		go server.ServeTLS(listener, "", "")
+	} else {
+		go server.Serve(listener)
+	}
+	return server, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
+
+// handleWS передает клиенту изменения задач в виде JSON (task.Event), пока он не закроет соединение.
+// Клиент, который не успевает читать события, отключается и должен заново запросить список
+func (s *Server) handleWS(w http.ResponseWriter, r *http.Request) {
+	// Подписываемся до установки соединения, чтобы не пропустить события сразу после нее
+	var feed *task.Feed
+	s.do(func() {
+		feed = task.NewFeed(s.tm, wsBuffer)
+	})
+	defer feed.Close()
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // Upgrade уже ответил клиенту
+	}
+	defer conn.Close()
+
+	// Клиент ничего не присылает, но читать нужно, чтобы заметить закрытие соединения
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-feed.Overflow:
+			message := websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "client is too slow, reload the task list")
+			conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(wsWriteTimeout))
+			return
+		case e := <-feed.Events:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := conn.WriteJSON(e); err != nil {
+				return
+			}
+		}
+	}
+}
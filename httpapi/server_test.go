@@ -0,0 +1,75 @@
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/apiauth"
+	"taskmanager/storage"
+	"taskmanager/task"
+)
+
+// newTestServer поднимает сервер с ключами API keys и возвращает его вместе с менеджером задач.
+// Без ключей сервер доступен тестам, так как они обращаются к нему с этого компьютера
+func newTestServer(t *testing.T, keys ...apiauth.Key) (*httptest.Server, *task.TaskManager, func(func())) {
+	t.Helper()
+	tm := task.NewTaskManager(storage.NewFile(filepath.Join(t.TempDir(), "tasks.json")))
+
+	var mu sync.Mutex
+	do := func(fn func()) {
+		mu.Lock()
+		defer mu.Unlock()
+		fn()
+	}
+
+	server := httptest.NewServer(NewServer(tm, do, apiauth.New(keys)))
+	t.Cleanup(server.Close)
+	return server, tm, do
+}
+
+func TestWebSocket(t *testing.T) {
+	server, tm, do := newTestServer(t)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer conn.Close()
+
+	var added *task.Task
+	do(func() {
+		added, err = tm.AddTask("Task", "Description", 1, time.Now().Add(time.Hour))
+		assert.NoError(t, err)
+		assert.NoError(t, tm.ToggleTaskCompletion(added.ID))
+		assert.NoError(t, tm.DeleteTask(added.ID))
+	})
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var ops []task.EventOp
+	for range 3 {
+		var e task.Event
+		if !assert.NoError(t, conn.ReadJSON(&e)) {
+			t.FailNow()
+		}
+		assert.Equal(t, added.UUID, e.Task.UUID)
+		ops = append(ops, e.Op)
+	}
+	assert.Equal(t, []task.EventOp{task.EventAdded, task.EventCompleted, task.EventDeleted}, ops)
+}
+
+func TestWebSocketRequiresUpgrade(t *testing.T) {
+	server, _, _ := newTestServer(t)
+
+	resp, err := server.Client().Get(server.URL + "/ws")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,133 @@
+package httpapi
+
+import (
+	"embed"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"taskmanager/task"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+var indexTemplate = template.Must(template.ParseFS(templateFS, "templates/index.html"))
+
+// dateFormat - формат срока выполнения в веб-интерфейсе (как у поля input type=date)
+const dateFormat = "2006-01-02"
+
+// webTask - строка списка задач в веб-интерфейсе
+type webTask struct {
+	ID        int
+	Title     string
+	Priority  string
+	Due       string
+	Completed bool
+	Overdue   bool
+}
+
+// indexPage - данные главной страницы
+type indexPage struct {
+	Tasks      []webTask
+	DefaultDue string
+	Error      string
+	ReadOnly   bool // ключ клиента не дает менять задачи
+}
+
+// handleIndex показывает список задач: сначала невыполненные, по сроку выполнения
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	page := indexPage{
+		Error:    r.URL.Query().Get("error"),
+		ReadOnly: s.readOnly(r),
+	}
+
+	s.do(func() {
+		// Сегодняшний день и срок по умолчанию - в поясе сроков задач
+		now := s.tm.DueZone().Now()
+		page.DefaultDue = now.AddDate(0, 0, 1).Format(dateFormat)
+		tasks := task.SortTasks(s.tm.Tasks(), task.SortByDueDate, false)
+		for _, t := range task.SortTasks(tasks, task.SortByStatus, false) {
+			// У задачи без срока срок не показывается
+			var due string
+			if !t.DueDate.IsZero() {
+				due = t.DueDay()
+			}
+			page.Tasks = append(page.Tasks, webTask{
+				ID:        t.ID,
+				Title:     t.Title,
+				Priority:  task.PriorityText(t.Priority),
+				Due:       due,
+				Completed: t.Completed,
+				Overdue:   t.IsOverdue(now),
+			})
+		}
+	})
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	indexTemplate.Execute(w, page)
+}
+
+// handleAdd добавляет задачу из формы
+func (s *Server) handleAdd(w http.ResponseWriter, r *http.Request) {
+	priority, _ := strconv.Atoi(r.FormValue("priority"))
+	// Срок из формы - день в поясе сроков задач; пустой срок - задача «когда-нибудь»
+	var zone task.DueZone
+	s.do(func() { zone = s.tm.DueZone() })
+	var dueDate time.Time
+	var err error
+	if due := r.FormValue("due"); due != "" {
+		if dueDate, err = zone.ParseDueDate(due); err != nil {
+			redirectWithError(w, r, "Неверный срок выполнения")
+			return
+		}
+	}
+
+	s.do(func() {
+		_, err = s.tm.AddTask(r.FormValue("title"), "", priority, dueDate)
+	})
+	if err != nil {
+		redirectWithError(w, r, err.Error())
+		return
+	}
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// handleToggle меняет статус выполнения задачи
+func (s *Server) handleToggle(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.do(func() {
+		err = s.tm.ToggleTaskCompletion(id)
+	})
+	if err != nil {
+		redirectWithError(w, r, err.Error())
+		return
+	}
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+func redirectWithError(w http.ResponseWriter, r *http.Request, message string) {
+	http.Redirect(w, r, "/?error="+url.QueryEscape(message), http.StatusSeeOther)
+}
+
+// sameOrigin отклоняет формы, отправленные с чужих сайтов: страница
+// в браузере пользователя не должна менять его задачи
+func sameOrigin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if origin := r.Header.Get("Origin"); origin != "" {
+			u, err := url.Parse(origin)
+			if err != nil || u.Host != r.Host {
+				http.Error(w, "cross-origin request", http.StatusForbidden)
+				return
+			}
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,160 @@
+package httpapi
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+// noRedirect не дает клиенту переходить по перенаправлениям, чтобы их можно было проверить
+func noRedirect(req *http.Request, via []*http.Request) error {
+	return http.ErrUseLastResponse
+}
+
+func TestWebIndex(t *testing.T) {
+	server, tm, do := newTestServer(t)
+	do(func() {
+		tm.AddTask("<Done>", "Description", 3, time.Now().Add(time.Hour))
+		tm.ToggleTaskCompletion(1)
+		tm.AddTask("Active", "Description", 1, time.Now().Add(48*time.Hour))
+		tm.AddTask("Someday", "Description", 1, time.Time{})
+	})
+
+	resp, err := server.Client().Get(server.URL + "/")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	page := string(body)
+	// Невыполненные задачи выше выполненных, названия экранируются
+	assert.Less(t, strings.Index(page, "Active"), strings.Index(page, "&lt;Done&gt;"))
+	assert.NotContains(t, page, "<Done>")
+	// Задача без срока не просрочена и показывается без срока
+	assert.NotContains(t, page, "0001-01-01")
+	assert.Equal(t, 0, strings.Count(page, `class="overdue"`))
+
+	resp, err = server.Client().Get(server.URL + "/missing")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestWebAddAndToggle(t *testing.T) {
+	server, tm, do := newTestServer(t)
+	client := server.Client()
+	client.CheckRedirect = noRedirect
+
+	resp, err := client.PostForm(server.URL+"/tasks", url.Values{
+		"title": {"From phone"}, "priority": {"2"}, "due": {"2030-01-02"},
+	})
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusSeeOther, resp.StatusCode)
+	assert.Equal(t, "/", resp.Header.Get("Location"))
+
+	resp, err = client.PostForm(server.URL+"/tasks/1/toggle", nil)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusSeeOther, resp.StatusCode)
+
+	do(func() {
+		added := tm.GetTask(1)
+		if assert.NotNil(t, added) {
+			assert.Equal(t, "From phone", added.Title)
+			assert.Equal(t, 2, added.Priority)
+			assert.Equal(t, "2030-01-02", added.DueDate.Format(dateFormat))
+			assert.True(t, added.Completed)
+		}
+	})
+
+	// Ошибки возвращаются на главную страницу
+	resp, err = client.PostForm(server.URL+"/tasks", url.Values{"title": {""}, "priority": {"2"}, "due": {"2030-01-02"}})
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.True(t, strings.HasPrefix(resp.Header.Get("Location"), "/?error="))
+
+	resp, err = client.PostForm(server.URL+"/tasks/99/toggle", nil)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.True(t, strings.HasPrefix(resp.Header.Get("Location"), "/?error="))
+}
+
+func TestWebAddWithoutDue(t *testing.T) {
+	server, tm, do := newTestServer(t)
+	client := server.Client()
+	client.CheckRedirect = noRedirect
+
+	// Пустой срок - задача без срока, а не ошибка
+	resp, err := client.PostForm(server.URL+"/tasks", url.Values{"title": {"Someday"}, "priority": {"1"}, "due": {""}})
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "/", resp.Header.Get("Location"))
+	do(func() {
+		if added := tm.GetTask(1); assert.NotNil(t, added) {
+			assert.True(t, added.DueDate.IsZero())
+		}
+	})
+
+	resp, err = client.PostForm(server.URL+"/tasks", url.Values{"title": {"Bad"}, "priority": {"1"}, "due": {"someday"}})
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.True(t, strings.HasPrefix(resp.Header.Get("Location"), "/?error="))
+}
+
+func TestWebDueZone(t *testing.T) {
+	server, tm, do := newTestServer(t)
+	client := server.Client()
+	client.CheckRedirect = noRedirect
+	do(func() {
+		tm.SetDueZone(task.DueZoneUTC)
+		yesterday, _ := task.DueZoneUTC.ParseDueDate(task.DueZoneUTC.Now().AddDate(0, 0, -1).Format(task.DueDateLayout))
+		tm.AddTask("Late", "Description", 1, yesterday)
+	})
+
+	// Срок из формы - начало дня в поясе сроков
+	resp, err := client.PostForm(server.URL+"/tasks", url.Values{
+		"title": {"From phone"}, "priority": {"2"}, "due": {"2030-01-02"},
+	})
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "/", resp.Header.Get("Location"))
+	do(func() {
+		assert.Equal(t, time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC), tm.GetTask(2).DueDate)
+	})
+
+	// Просрочка считается по сегодняшнему дню в поясе сроков
+	resp, err = client.Get(server.URL + "/")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, 1, strings.Count(string(body), `class="overdue"`))
+}
+
+func TestWebRejectsCrossOrigin(t *testing.T) {
+	server, tm, do := newTestServer(t)
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/tasks",
+		strings.NewReader(url.Values{"title": {"Evil"}, "priority": {"1"}, "due": {"2030-01-02"}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Origin", "http://evil.example")
+	resp, err := server.Client().Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	do(func() {
+		assert.Empty(t, tm.Tasks())
+	})
+}
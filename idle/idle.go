@@ -0,0 +1,49 @@
+// Package idle сообщает, сколько времени пользователь не трогал клавиатуру и мышь во всей
+// системе, а не только в окне приложения. Способ зависит от платформы: GetLastInputInfo
+// в Windows, счетчик IOHIDSystem в macOS, xprintidle или монитор простоя GNOME в Linux
+package idle
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnsupported возвращается, если на этой платформе или в этом окружении простой узнать нельзя
+var ErrUnsupported = errors.New("idle time is not available")
+
+// Duration возвращает, сколько времени прошло с последнего ввода пользователя
+func Duration() (time.Duration, error) {
+	return systemIdle()
+}
+
+// ioregIdle находит HIDIdleTime в выводе «ioreg -c IOHIDSystem»: время в наносекундах
+var ioregIdle = regexp.MustCompile(`"HIDIdleTime"\s*=\s*(\d+)`)
+
+// parseIoreg читает простой из вывода ioreg в macOS
+func parseIoreg(output string) (time.Duration, error) {
+	match := ioregIdle.FindStringSubmatch(output)
+	if match == nil {
+		return 0, fmt.Errorf("%w: no HIDIdleTime in ioreg output", ErrUnsupported)
+	}
+	ns, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(ns), nil
+}
+
+// parseMilliseconds читает простой в миллисекундах: вывод xprintidle - просто число,
+// а gdbus возвращает его как «(uint64 1234,)»
+func parseMilliseconds(output string) (time.Duration, error) {
+	text := strings.TrimSpace(output)
+	text = strings.TrimSuffix(strings.TrimPrefix(text, "(uint64 "), ",)")
+	ms, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected idle time %q: %w", output, err)
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
@@ -0,0 +1,14 @@
+package idle
+
+import (
+	"os/exec"
+	"time"
+)
+
+func systemIdle() (time.Duration, error) {
+	output, err := exec.Command("ioreg", "-c", "IOHIDSystem", "-d", "4").Output()
+	if err != nil {
+		return 0, err
+	}
+	return parseIoreg(string(output))
+}
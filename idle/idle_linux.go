@@ -0,0 +1,23 @@
+package idle
+
+import (
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+// systemIdle спрашивает xprintidle (X11), а если его нет - монитор простоя GNOME,
+// который работает и в Wayland
+func systemIdle() (time.Duration, error) {
+	if output, err := exec.Command("xprintidle").Output(); err == nil {
+		return parseMilliseconds(string(output))
+	}
+	output, err := exec.Command("gdbus", "call", "--session",
+		"--dest", "org.gnome.Mutter.IdleMonitor",
+		"--object-path", "/org/gnome/Mutter/IdleMonitor/Core",
+		"--method", "org.gnome.Mutter.IdleMonitor.GetIdletime").Output()
+	if err != nil {
+		return 0, fmt.Errorf("%w: neither xprintidle nor GNOME idle monitor answered", ErrUnsupported)
+	}
+	return parseMilliseconds(string(output))
+}
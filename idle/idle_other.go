@@ -0,0 +1,9 @@
+//go:build !windows && !darwin && !linux
+
+package idle
+
+import "time"
+
+func systemIdle() (time.Duration, error) {
+	return 0, ErrUnsupported
+}
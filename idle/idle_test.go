@@ -0,0 +1,33 @@
+package idle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIoreg(t *testing.T) {
+	output := `    | |   "HIDIdleTime" = 125000000000
+    | |   "HIDParameters" = {}`
+	idle, err := parseIoreg(output)
+	require.NoError(t, err)
+	assert.Equal(t, 125*time.Second, idle)
+
+	_, err = parseIoreg("нет счетчика")
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestParseMilliseconds(t *testing.T) {
+	idle, err := parseMilliseconds("61500\n")
+	require.NoError(t, err)
+	assert.Equal(t, 61500*time.Millisecond, idle)
+
+	idle, err = parseMilliseconds("(uint64 3000,)\n")
+	require.NoError(t, err)
+	assert.Equal(t, 3*time.Second, idle)
+
+	_, err = parseMilliseconds("")
+	assert.Error(t, err)
+}
@@ -0,0 +1,30 @@
+package idle
+
+import (
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var (
+	user32           = syscall.NewLazyDLL("user32.dll")
+	kernel32         = syscall.NewLazyDLL("kernel32.dll")
+	getLastInputInfo = user32.NewProc("GetLastInputInfo")
+	getTickCount     = kernel32.NewProc("GetTickCount")
+)
+
+// lastInputInfo - структура LASTINPUTINFO
+type lastInputInfo struct {
+	size uint32
+	time uint32
+}
+
+func systemIdle() (time.Duration, error) {
+	info := lastInputInfo{size: uint32(unsafe.Sizeof(lastInputInfo{}))}
+	if ok, _, err := getLastInputInfo.Call(uintptr(unsafe.Pointer(&info))); ok == 0 {
+		return 0, err
+	}
+	// Счетчики в миллисекундах переполняются раз в 49 дней, разность uint32 это учитывает
+	now, _, _ := getTickCount.Call()
+	return time.Duration(uint32(now)-info.time) * time.Millisecond, nil
+}
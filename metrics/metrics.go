@@ -0,0 +1,126 @@
+// Package metrics - метрики менеджера задач в формате Prometheus:
+// количество задач, выполненные задачи, время и ошибки работы с хранилищем
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"taskmanager/task"
+)
+
+const namespace = "taskmanager"
+
+var (
+	tasksDesc = prometheus.NewDesc(namespace+"_tasks",
+		"Number of tasks.", nil, nil)
+	openTasksDesc = prometheus.NewDesc(namespace+"_tasks_open",
+		"Number of tasks that are not completed.", nil, nil)
+	overdueTasksDesc = prometheus.NewDesc(namespace+"_tasks_overdue",
+		"Number of open tasks whose due date has passed.", nil, nil)
+)
+
+// Metrics собирает метрики менеджера задач
+type Metrics struct {
+	tm       task.TaskService
+	do       func(func())
+	registry *prometheus.Registry
+
+	completed       prometheus.Counter
+	storageDuration *prometheus.HistogramVec
+	storageErrors   *prometheus.CounterVec
+}
+
+// New создает метрики для менеджера задач. Количество задач считается при каждом запросе
+// метрик через do, которая должна выполнить функцию и дождаться ее завершения.
+// Вызывать нужно в потоке, где работает менеджер задач
+func New(tm task.TaskService, do func(func())) *Metrics {
+	m := &Metrics{
+		tm:       tm,
+		do:       do,
+		registry: prometheus.NewRegistry(),
+		completed: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "tasks_completed_total",
+			Help:      "Number of times a task was marked as completed.",
+		}),
+		storageDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "storage_duration_seconds",
+			Help:      "Time spent saving and loading the tasks file.",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 4, 8),
+		}, []string{"op"}),
+		storageErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "storage_errors_total",
+			Help:      "Number of failed saves and loads of the tasks file.",
+		}, []string{"op"}),
+	}
+
+	m.registry.MustRegister(
+		m,
+		m.completed,
+		m.storageDuration,
+		m.storageErrors,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	tm.Subscribe(func(e task.Event) {
+		// Выполнение повторяющейся задачи в очередном периоде тоже считается
+		switch {
+		case e.Op == task.EventCompleted && e.Task.Completed:
+			m.completed.Inc()
+		case e.Op == task.EventOccurrence && e.Task.DoneInPeriod(tm.DueZone().Now()):
+			m.completed.Inc()
+		}
+	})
+	return m
+}
+
+// ObserveStorage учитывает сохранение или загрузку файла задач (см. task.TaskManager.SetStorageObserver)
+func (m *Metrics) ObserveStorage(op task.StorageOp, duration time.Duration, err error) {
+	m.storageDuration.WithLabelValues(string(op)).Observe(duration.Seconds())
+	if err != nil {
+		m.storageErrors.WithLabelValues(string(op)).Inc()
+	}
+}
+
+// Handler отдает метрики в текстовом формате Prometheus
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Describe реализует prometheus.Collector для количества задач
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- tasksDesc
+	ch <- openTasksDesc
+	ch <- overdueTasksDesc
+}
+
+// Collect реализует prometheus.Collector: считает задачи в момент запроса метрик
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	var total, open, overdue int
+	m.do(func() {
+		// Просрочка считается по дню в поясе сроков, задачи из архива не просрочены
+		now := m.tm.DueZone().Now()
+		for _, t := range m.tm.Tasks() {
+			total++
+			if t.Completed {
+				continue
+			}
+			open++
+			if !t.Archived && t.IsOverdue(now) {
+				overdue++
+			}
+		}
+	})
+
+	ch <- prometheus.MustNewConstMetric(tasksDesc, prometheus.GaugeValue, float64(total))
+	ch <- prometheus.MustNewConstMetric(openTasksDesc, prometheus.GaugeValue, float64(open))
+	ch <- prometheus.MustNewConstMetric(overdueTasksDesc, prometheus.GaugeValue, float64(overdue))
+}
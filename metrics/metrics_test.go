@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/storage"
+	"taskmanager/task"
+)
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	return string(body)
+}
+
+func TestMetrics(t *testing.T) {
+	tm := task.NewTaskManager(storage.NewFile(filepath.Join(t.TempDir(), "tasks.json")))
+	m := New(tm, func(fn func()) { fn() })
+	tm.SetStorageObserver(m.ObserveStorage)
+
+	done, err := tm.AddTask("Done", "Description", 1, time.Now())
+	assert.NoError(t, err)
+	assert.NoError(t, tm.ToggleTaskCompletion(done.ID))
+	_, err = tm.AddTask("Overdue", "Description", 1, time.Now().Add(-48*time.Hour))
+	assert.NoError(t, err)
+	_, err = tm.AddTask("Open", "Description", 1, time.Now().Add(48*time.Hour))
+	assert.NoError(t, err)
+	// Задача из архива не считается просроченной
+	archived, err := tm.AddTask("Archived", "Description", 1, time.Now().Add(-48*time.Hour))
+	assert.NoError(t, err)
+	assert.NoError(t, tm.SetTaskArchived(archived.ID, true))
+	assert.NoError(t, tm.SaveToFile())
+	m.ObserveStorage(task.StorageLoad, time.Millisecond, errors.New("broken file"))
+
+	body := scrape(t, m)
+	assert.Contains(t, body, "taskmanager_tasks 4\n")
+	assert.Contains(t, body, "taskmanager_tasks_open 3\n")
+	assert.Contains(t, body, "taskmanager_tasks_overdue 1\n")
+	assert.Contains(t, body, "taskmanager_tasks_completed_total 1\n")
+	assert.Contains(t, body, `taskmanager_storage_duration_seconds_count{op="save"} 1`)
+	assert.Contains(t, body, `taskmanager_storage_errors_total{op="load"} 1`)
+	assert.NotContains(t, body, `taskmanager_storage_errors_total{op="save"}`)
+
+	// Снятие отметки о выполнении не уменьшает счетчик выполненных задач
+	assert.NoError(t, tm.ToggleTaskCompletion(done.ID))
+	assert.Contains(t, scrape(t, m), "taskmanager_tasks_completed_total 1\n")
+
+	// Отметка повторяющейся задачи считается выполнением, ее снятие - нет
+	habit, err := tm.AddTask("Habit", "Description", 1, time.Time{})
+	assert.NoError(t, err)
+	assert.NoError(t, tm.SetTaskRecurrence(habit.ID, task.RecurDaily))
+	assert.NoError(t, tm.ToggleTaskCompletion(habit.ID))
+	assert.Contains(t, scrape(t, m), "taskmanager_tasks_completed_total 2\n")
+	assert.NoError(t, tm.ToggleTaskCompletion(habit.ID))
+	assert.Contains(t, scrape(t, m), "taskmanager_tasks_completed_total 2\n")
+}
+
+func TestMetricsOverdueInDueZone(t *testing.T) {
+	tm := task.NewTaskManager(storage.NewFile(filepath.Join(t.TempDir(), "tasks.json")))
+	tm.SetDueZone(task.DueZoneUTC)
+	m := New(tm, func(fn func()) { fn() })
+
+	// Срок - вчерашний и сегодняшний день в поясе сроков, а не по часам компьютера
+	now := task.DueZoneUTC.Now()
+	yesterday, _ := task.DueZoneUTC.ParseDueDate(now.AddDate(0, 0, -1).Format(task.DueDateLayout))
+	today, _ := task.DueZoneUTC.ParseDueDate(now.Format(task.DueDateLayout))
+	_, err := tm.AddTask("Yesterday", "Description", 1, yesterday)
+	assert.NoError(t, err)
+	_, err = tm.AddTask("Today", "Description", 1, today)
+	assert.NoError(t, err)
+
+	assert.Contains(t, scrape(t, m), "taskmanager_tasks_overdue 1\n")
+}
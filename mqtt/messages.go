@@ -0,0 +1,113 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"taskmanager/task"
+)
+
+// DefaultTopic - тема по умолчанию; события и счетчики публикуются в ее подтемы
+const DefaultTopic = "taskmanager"
+
+// discoveryPrefix - тема обнаружения устройств Home Assistant
+const discoveryPrefix = "homeassistant"
+
+// Counts - счетчики задач для панелей: публикуются в <тема>/counts с флагом retain
+type Counts struct {
+	Open           int `json:"open"`            // невыполненные
+	Overdue        int `json:"overdue"`         // просроченные
+	Today          int `json:"today"`           // невыполненные на сегодня
+	CompletedToday int `json:"completed_today"` // выполненные сегодня
+}
+
+// CountTasks считает задачи на момент now. Задачи в архиве не считаются
+func CountTasks(tasks []*task.Task, now time.Time) Counts {
+	today := now.Format(task.DueDateLayout)
+	var counts Counts
+	for _, t := range tasks {
+		switch {
+		case t.Archived:
+		case t.Completed:
+			if t.DoneAt().In(now.Location()).Format(task.DueDateLayout) == today {
+				counts.CompletedToday++
+			}
+		default:
+			counts.Open++
+			if t.IsOverdue(now) {
+				counts.Overdue++
+			}
+			if t.DueDay() == today {
+				counts.Today++
+			}
+		}
+	}
+	return counts
+}
+
+// CountsMessage возвращает сообщение со счетчиками для темы topic
+func CountsMessage(topic string, counts Counts) Message {
+	payload, _ := json.Marshal(counts)
+	return Message{Topic: topic + "/counts", Payload: payload, Retain: true}
+}
+
+// eventPayload - событие задачи для автоматизаций
+type eventPayload struct {
+	Op        task.EventOp `json:"op"`
+	UUID      string       `json:"uuid,omitempty"`
+	Title     string       `json:"title,omitempty"`
+	Priority  int          `json:"priority,omitempty"`
+	Due       string       `json:"due,omitempty"`
+	Completed bool         `json:"completed"`
+	Tags      []string     `json:"tags,omitempty"`
+}
+
+// EventMessage возвращает сообщение о событии задачи: в <тема>/event и в <тема>/event/<вид>,
+// чтобы автоматизация могла подписаться, например, только на выполнение задач
+func EventMessage(topic string, event task.Event) []Message {
+	payload := eventPayload{Op: event.Op}
+	if t := event.Task; t != nil {
+		payload.UUID, payload.Title, payload.Priority = t.UUID, t.Title, t.Priority
+		payload.Due, payload.Completed, payload.Tags = t.DueDay(), t.Completed, t.Tags
+	}
+	data, _ := json.Marshal(payload)
+	return []Message{
+		{Topic: topic + "/event", Payload: data},
+		{Topic: topic + "/event/" + string(event.Op), Payload: data},
+	}
+}
+
+// discoverySensors - счетчики, которые Home Assistant находит сам
+var discoverySensors = []struct {
+	Key, Name, Icon string
+}{
+	{"open", "Задачи в работе", "mdi:format-list-checks"},
+	{"overdue", "Просроченные задачи", "mdi:alert-circle"},
+	{"today", "Задачи на сегодня", "mdi:calendar-today"},
+	{"completed_today", "Выполнено сегодня", "mdi:check-circle"},
+}
+
+// DiscoveryMessages возвращает настройки датчиков для обнаружения Home Assistant:
+// после их публикации счетчики появляются в Home Assistant без ручной настройки
+func DiscoveryMessages(topic string) []Message {
+	node := strings.NewReplacer("/", "_", "+", "_", "#", "_", " ", "_").Replace(topic)
+	device := map[string]any{"identifiers": []string{node}, "name": "Менеджер задач"}
+	messages := make([]Message, 0, len(discoverySensors))
+	for _, sensor := range discoverySensors {
+		payload, _ := json.Marshal(map[string]any{
+			"name":           sensor.Name,
+			"unique_id":      node + "_" + sensor.Key,
+			"state_topic":    topic + "/counts",
+			"value_template": "{{ value_json." + sensor.Key + " }}",
+			"icon":           sensor.Icon,
+			"device":         device,
+		})
+		messages = append(messages, Message{
+			Topic:   discoveryPrefix + "/sensor/" + node + "/" + sensor.Key + "/config",
+			Payload: payload,
+			Retain:  true,
+		})
+	}
+	return messages
+}
@@ -0,0 +1,208 @@
+// Package mqtt публикует события задач и счетчики в брокер MQTT, чтобы панели Home Assistant
+// показывали «3 дела просрочено», а автоматизации реагировали на выполнение задач.
+// Клиент реализует только нужную часть MQTT 3.1.1: подключение, публикацию с QoS 0 и отключение
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"time"
+)
+
+// ErrRefused возвращается, если брокер отклонил подключение
+var ErrRefused = errors.New("mqtt: connection refused")
+
+// Config - подключение к брокеру
+type Config struct {
+	// Broker - адрес брокера: tcp://host:1883 или mqtts://host:8883 для TLS
+	Broker   string
+	ClientID string
+	Username string
+	Password string
+}
+
+// Message - сообщение для публикации
+type Message struct {
+	Topic   string
+	Payload []byte
+	// Retain - брокер хранит последнее сообщение темы и отдает его новым подписчикам;
+	// так публикуются состояния, например счетчики
+	Retain bool
+}
+
+// Типы пакетов MQTT
+const (
+	packetConnect    = 1
+	packetConnack    = 2
+	packetPublish    = 3
+	packetDisconnect = 14
+)
+
+// connackReasons - причины отказа в подключении по коду CONNACK
+var connackReasons = map[byte]string{
+	1: "unacceptable protocol version",
+	2: "identifier rejected",
+	3: "server unavailable",
+	4: "bad user name or password",
+	5: "not authorized",
+}
+
+// keepAlive - интервал проверки связи, который клиент обещает брокеру. Соединение живет
+// секунды, поэтому проверки не отправляются
+const keepAlive = 60
+
+// brokerAddress возвращает сетевой адрес брокера и нужен ли TLS
+func brokerAddress(broker string) (address string, secure bool, err error) {
+	u, err := url.Parse(broker)
+	if err != nil || u.Host == "" {
+		return "", false, fmt.Errorf("mqtt: invalid broker address %q, use tcp://host:1883", broker)
+	}
+	port := "1883"
+	switch u.Scheme {
+	case "tcp", "mqtt":
+	case "ssl", "tls", "mqtts":
+		secure, port = true, "8883"
+	default:
+		return "", false, fmt.Errorf("mqtt: unsupported scheme %q", u.Scheme)
+	}
+	if u.Port() != "" {
+		port = u.Port()
+	}
+	return net.JoinHostPort(u.Hostname(), port), secure, nil
+}
+
+// ValidateBroker проверяет адрес брокера
+func ValidateBroker(broker string) error {
+	_, _, err := brokerAddress(broker)
+	return err
+}
+
+// Publish подключается к брокеру, публикует сообщения и отключается. Событий немного,
+// поэтому постоянное соединение не держится
+func Publish(ctx context.Context, cfg Config, messages []Message) error {
+	address, secure, err := brokerAddress(cfg.Broker)
+	if err != nil {
+		return err
+	}
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	var conn net.Conn
+	if secure {
+		conn, err = (&tls.Dialer{NetDialer: dialer}).DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return fmt.Errorf("mqtt: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	w := bufio.NewWriter(conn)
+	w.Write(connectPacket(cfg))
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("mqtt: %w", err)
+	}
+	kind, body, err := readPacket(bufio.NewReader(conn))
+	if err != nil {
+		return fmt.Errorf("mqtt: %w", err)
+	}
+	if kind != packetConnack || len(body) != 2 {
+		return fmt.Errorf("mqtt: unexpected packet %d instead of CONNACK", kind)
+	}
+	if code := body[1]; code != 0 {
+		return fmt.Errorf("%w: %s", ErrRefused, connackReasons[code])
+	}
+
+	for _, message := range messages {
+		w.Write(publishPacket(message))
+	}
+	w.Write([]byte{packetDisconnect << 4, 0})
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("mqtt: %w", err)
+	}
+	return nil
+}
+
+// connectPacket собирает пакет CONNECT с чистой сессией
+func connectPacket(cfg Config) []byte {
+	var flags byte = 0x02 // Чистая сессия
+	payload := appendString(nil, cfg.ClientID)
+	if cfg.Username != "" {
+		flags |= 0x80
+		payload = appendString(payload, cfg.Username)
+		if cfg.Password != "" {
+			flags |= 0x40
+			payload = appendString(payload, cfg.Password)
+		}
+	}
+	body := appendString(nil, "MQTT")
+	body = append(body, 4, flags, keepAlive>>8, keepAlive&0xff)
+	return packet(packetConnect<<4, append(body, payload...))
+}
+
+// publishPacket собирает пакет PUBLISH с QoS 0
+func publishPacket(message Message) []byte {
+	var header byte = packetPublish << 4
+	if message.Retain {
+		header |= 0x01
+	}
+	return packet(header, append(appendString(nil, message.Topic), message.Payload...))
+}
+
+// packet добавляет к телу пакета заголовок с длиной
+func packet(header byte, body []byte) []byte {
+	out := []byte{header}
+	for length := len(body); ; {
+		digit := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			digit |= 0x80
+		}
+		out = append(out, digit)
+		if length == 0 {
+			break
+		}
+	}
+	return append(out, body...)
+}
+
+// appendString добавляет строку MQTT: длина в двух байтах и сама строка
+func appendString(b []byte, s string) []byte {
+	return append(append(b, byte(len(s)>>8), byte(len(s))), s...)
+}
+
+// readPacket читает пакет: тип и тело без заголовка
+func readPacket(r *bufio.Reader) (kind byte, body []byte, err error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length, multiplier := 0, 1
+	for i := 0; ; i++ {
+		digit, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(digit&0x7f) * multiplier
+		multiplier *= 128
+		if digit&0x80 == 0 {
+			break
+		}
+		if i == 3 {
+			return 0, nil, errors.New("malformed remaining length")
+		}
+	}
+	body = make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header >> 4, body, nil
+}
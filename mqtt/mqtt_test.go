@@ -0,0 +1,146 @@
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"taskmanager/task"
+)
+
+// publishedPacket - пакет, принятый тестовым брокером
+type publishedPacket struct {
+	Kind byte
+	Body []byte
+}
+
+// fakeBroker принимает одно соединение, отвечает на CONNECT кодом code
+// и возвращает принятые пакеты
+func fakeBroker(t *testing.T, code byte) (address string, packets <-chan []publishedPacket) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	out := make(chan []publishedPacket, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		var received []publishedPacket
+		for {
+			kind, body, err := readPacket(r)
+			if err != nil {
+				break
+			}
+			received = append(received, publishedPacket{kind, body})
+			if kind == packetConnect {
+				conn.Write([]byte{packetConnack << 4, 2, 0, code})
+			}
+			if kind == packetDisconnect {
+				break
+			}
+		}
+		out <- received
+	}()
+	return "tcp://" + listener.Addr().String(), out
+}
+
+func TestPublish(t *testing.T) {
+	broker, packets := fakeBroker(t, 0)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	long := strings.Repeat("я", 100)
+	err := Publish(ctx, Config{Broker: broker, ClientID: "tasks", Username: "home", Password: "secret"}, []Message{
+		{Topic: "taskmanager/counts", Payload: []byte(`{"overdue":3}`), Retain: true},
+		{Topic: "taskmanager/event", Payload: []byte(long)},
+	})
+	require.NoError(t, err)
+
+	received := <-packets
+	require.Len(t, received, 4)
+	connect := received[0]
+	assert.Equal(t, byte(packetConnect), connect.Kind)
+	assert.Equal(t, "\x00\x04MQTT\x04", string(connect.Body[:7]))
+	assert.Equal(t, byte(0xc2), connect.Body[7], "имя, пароль и чистая сессия")
+	assert.True(t, strings.HasSuffix(string(connect.Body), "\x00\x05tasks\x00\x04home\x00\x06secret"))
+
+	assert.Equal(t, "\x00\x12taskmanager/counts{\"overdue\":3}", string(received[1].Body))
+	assert.Equal(t, "\x00\x11taskmanager/event"+long, string(received[2].Body))
+	assert.Equal(t, byte(packetDisconnect), received[3].Kind)
+
+	// Флаг retain - в заголовке пакета
+	assert.Equal(t, byte(0x31), publishPacket(Message{Topic: "t", Retain: true})[0])
+	// Длина больше 127 байт занимает два байта
+	assert.Equal(t, []byte{0x30, 0xcb, 0x01}, publishPacket(Message{Topic: "t", Payload: []byte(long)})[:3])
+}
+
+func TestPublishRefused(t *testing.T) {
+	broker, _ := fakeBroker(t, 4)
+	err := Publish(context.Background(), Config{Broker: broker, ClientID: "tasks"}, nil)
+	assert.ErrorIs(t, err, ErrRefused)
+	assert.ErrorContains(t, err, "bad user name or password")
+}
+
+func TestValidateBroker(t *testing.T) {
+	address, secure, err := brokerAddress("mqtts://broker.local")
+	require.NoError(t, err)
+	assert.Equal(t, "broker.local:8883", address)
+	assert.True(t, secure)
+
+	address, secure, err = brokerAddress("tcp://192.168.1.5:1884")
+	require.NoError(t, err)
+	assert.Equal(t, "192.168.1.5:1884", address)
+	assert.False(t, secure)
+
+	assert.Error(t, ValidateBroker("broker.local"))
+	assert.Error(t, ValidateBroker("http://broker.local"))
+}
+
+func TestCountTasks(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC) }
+	tasks := []*task.Task{
+		{DueDate: day(14)},
+		{DueDate: day(15)},
+		{DueDate: day(16)},
+		{DueDate: day(20)},
+		{DueDate: day(16), Completed: true, CompletedAt: now.Add(-time.Hour)},
+		{DueDate: day(10), Completed: true, CompletedAt: day(11)},
+		{DueDate: day(1), Archived: true},
+	}
+	counts := CountTasks(tasks, now)
+	assert.Equal(t, Counts{Open: 4, Overdue: 2, Today: 1, CompletedToday: 1}, counts)
+	message := CountsMessage("home/tasks", counts)
+	assert.Equal(t, "home/tasks/counts", message.Topic)
+	assert.True(t, message.Retain)
+	assert.JSONEq(t, `{"open":4,"overdue":2,"today":1,"completed_today":1}`, string(message.Payload))
+}
+
+func TestEventAndDiscoveryMessages(t *testing.T) {
+	done := &task.Task{UUID: "u1", Title: "Вынести мусор", Priority: 1, DueDate: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), Completed: true}
+	messages := EventMessage("taskmanager", task.Event{Op: task.EventCompleted, Task: done})
+	require.Len(t, messages, 2)
+	assert.Equal(t, "taskmanager/event", messages[0].Topic)
+	assert.Equal(t, "taskmanager/event/completed", messages[1].Topic)
+	assert.False(t, messages[0].Retain)
+	assert.JSONEq(t, `{"op":"completed","uuid":"u1","title":"Вынести мусор","priority":1,"due":"2026-10-16","completed":true}`, string(messages[0].Payload))
+
+	discovery := DiscoveryMessages("home/tasks")
+	require.Len(t, discovery, 4)
+	assert.Equal(t, "homeassistant/sensor/home_tasks/overdue/config", discovery[1].Topic)
+	var config map[string]any
+	require.NoError(t, json.Unmarshal(discovery[1].Payload, &config))
+	assert.Equal(t, "home/tasks/counts", config["state_topic"])
+	assert.Equal(t, "{{ value_json.overdue }}", config["value_template"])
+	assert.Equal(t, "home_tasks_overdue", config["unique_id"])
+}
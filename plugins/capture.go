@@ -0,0 +1,76 @@
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"unicode"
+)
+
+// ErrEmptyCapture возвращается, если команда захвата ничего не напечатала
+var ErrEmptyCapture = errors.New("capture command printed nothing")
+
+// Capture запускает команду захвата, например программу распознавания речи, и возвращает
+// ее вывод: каждая строка разбирается как строка быстрого ввода. Приложение не работает
+// со звуком само - диктовку записывает и распознает внешняя программа
+func Capture(ctx context.Context, command string) (string, error) {
+	args, err := SplitCommand(command)
+	if err != nil {
+		return "", err
+	}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			err = fmt.Errorf("%w: %s", err, message)
+		}
+		return "", fmt.Errorf("capture command %s: %w", args[0], err)
+	}
+	text := strings.TrimSpace(stdout.String())
+	if text == "" {
+		return "", ErrEmptyCapture
+	}
+	return text, nil
+}
+
+// SplitCommand разбивает командную строку на программу и аргументы по пробелам.
+// Аргумент с пробелами берется в двойные или одинарные кавычки
+func SplitCommand(command string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	var quote rune
+	inArg := false
+	for _, r := range command {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote, inArg = r, true
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in command %q", command)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	if len(args) == 0 {
+		return nil, errors.New("capture command is empty")
+	}
+	return args, nil
+}
@@ -0,0 +1,143 @@
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"taskmanager/task"
+)
+
+// describeTimeout - сколько ждать описания от внешнего плагина
+const describeTimeout = 10 * time.Second
+
+// External - расширение в виде отдельной программы на любом языке. Протокол:
+//
+//	program describe - печатает JSON {"name": ..., "extension": ".org", "options": [{"key", "title", "secret"}]}
+//	program run      - читает JSON {"options": {...}, "tasks": [...]} и печатает содержимое файла
+//
+// Ненулевой код выхода означает ошибку, ее текст программа пишет в stderr
+type External struct {
+	path    string
+	info    externalInfo
+	options map[string]string
+}
+
+// externalInfo - ответ программы на describe
+type externalInfo struct {
+	Name      string   `json:"name"`
+	Extension string   `json:"extension"`
+	Options   []Option `json:"options"`
+}
+
+// externalInput - данные, которые программа получает на run
+type externalInput struct {
+	Options map[string]string `json:"options"`
+	Tasks   []*task.Task      `json:"tasks"`
+}
+
+// LoadExternal запускает describe у каждой программы в папке dir. Программы, которые
+// не ответили, пропускаются, ошибки возвращаются вместе с загруженными плагинами
+func LoadExternal(ctx context.Context, dir string) ([]*External, []error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, []error{err}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var plugins []*External
+	var errs []error
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if !isExecutable(path) {
+			continue
+		}
+		p, err := NewExternal(ctx, path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		plugins = append(plugins, p)
+	}
+	return plugins, errs
+}
+
+// NewExternal загружает программу-плагин path, запрашивая ее описание
+func NewExternal(ctx context.Context, path string) (*External, error) {
+	ctx, cancel := context.WithTimeout(ctx, describeTimeout)
+	defer cancel()
+
+	out, err := runExternal(ctx, path, "describe", nil)
+	if err != nil {
+		return nil, err
+	}
+	var info externalInfo
+	if err := json.Unmarshal(out, &info); err != nil {
+		return nil, fmt.Errorf("plugin %s: invalid description: %w", filepath.Base(path), err)
+	}
+	if strings.TrimSpace(info.Name) == "" {
+		return nil, fmt.Errorf("plugin %s: name is required", filepath.Base(path))
+	}
+	return &External{path: path, info: info}, nil
+}
+
+func (p *External) Name() string      { return p.info.Name }
+func (p *External) Extension() string { return p.info.Extension }
+func (p *External) Options() []Option { return p.info.Options }
+
+func (p *External) Configure(options map[string]string) error {
+	p.options = options
+	return nil
+}
+
+func (p *External) Run(ctx context.Context, tasks []*task.Task, out io.Writer) error {
+	input, err := json.Marshal(externalInput{Options: p.options, Tasks: tasks})
+	if err != nil {
+		return err
+	}
+	data, err := runExternal(ctx, p.path, "run", input)
+	if err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	_, err = out.Write(data)
+	return err
+}
+
+// runExternal запускает программу-плагин с командой command и возвращает ее вывод
+func runExternal(ctx context.Context, path, command string, input []byte) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, path, command)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			err = fmt.Errorf("%w: %s", err, message)
+		}
+		return nil, fmt.Errorf("plugin %s %s: %w", filepath.Base(path), command, err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// isExecutable проверяет, похож ли файл на программу
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(filepath.Ext(path), ".exe")
+	}
+	return info.Mode()&0o111 != 0
+}
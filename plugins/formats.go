@@ -0,0 +1,294 @@
+package plugins
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"taskmanager/report"
+	"taskmanager/task"
+	"taskmanager/update"
+)
+
+// CSV выгружает задачи в таблицу CSV
+type CSV struct {
+	Options task.CSVOptions
+}
+
+// Ключи настроек CSV
+const (
+	CSVDelimiter = "delimiter" // «,», «;» или «tab»
+	CSVBOM       = "bom"       // «yes» - метка UTF-8 для Excel
+	CSVColumns   = "columns"   // ключи колонок через запятую; пусто - все
+	CSVFilter    = "filter"    // all, open или due_range
+	CSVFrom      = "from"      // начало диапазона сроков, 2006-01-02
+	CSVTo        = "to"        // конец диапазона сроков, 2006-01-02
+)
+
+// csvDelimiters - разделители по значению настройки
+var csvDelimiters = map[string]rune{"": ',', ",": ',', ";": ';', "tab": '\t'}
+
+func (*CSV) Name() string      { return "CSV" }
+func (*CSV) Extension() string { return ".csv" }
+
+// Configure разбирает настройки выгрузки; пустые значения - как по умолчанию
+func (c *CSV) Configure(values map[string]string) error {
+	delimiter, ok := csvDelimiters[values[CSVDelimiter]]
+	if !ok {
+		return fmt.Errorf("unsupported delimiter %q", values[CSVDelimiter])
+	}
+	options := task.CSVOptions{
+		Delimiter: delimiter,
+		BOM:       values[CSVBOM] == "yes",
+		Filter:    task.CSVFilter(values[CSVFilter]),
+	}
+	for _, key := range strings.Split(values[CSVColumns], ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			options.Columns = append(options.Columns, key)
+		}
+	}
+	for key, bound := range map[string]*time.Time{CSVFrom: &options.From, CSVTo: &options.To} {
+		if values[key] == "" {
+			continue
+		}
+		day, err := time.ParseInLocation(task.DueDateLayout, values[key], time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid %s date, use YYYY-MM-DD", key)
+		}
+		*bound = day
+	}
+	if err := options.Validate(); err != nil {
+		return err
+	}
+	c.Options = options
+	return nil
+}
+
+func (c *CSV) Run(_ context.Context, tasks []*task.Task, out io.Writer) error {
+	return task.WriteCSV(out, tasks, c.Options)
+}
+
+// JSON выгружает задачи в JSON для обмена с другими программами; выгрузку можно
+// загрузить обратно импортом из JSON
+type JSON struct {
+	Options task.JSONExportOptions
+}
+
+// Ключи настроек JSON
+const (
+	JSONPretty   = "pretty"   // «yes» - с отступами
+	JSONArchived = "archived" // «yes» - с задачами из архива
+)
+
+func (*JSON) Name() string      { return "JSON" }
+func (*JSON) Extension() string { return ".json" }
+
+// Configure разбирает настройки выгрузки; без настроек выгрузка компактная и без архива
+func (j *JSON) Configure(values map[string]string) error {
+	j.Options = task.JSONExportOptions{
+		AppVersion:      update.Version,
+		Pretty:          values[JSONPretty] == "yes",
+		IncludeArchived: values[JSONArchived] == "yes",
+	}
+	return nil
+}
+
+func (j *JSON) Run(_ context.Context, tasks []*task.Task, out io.Writer) error {
+	return task.WriteJSON(out, tasks, j.Options)
+}
+
+// Markdown выгружает задачи списком с флажками, как в GitHub и Obsidian
+type Markdown struct{}
+
+func (Markdown) Name() string                      { return "Markdown" }
+func (Markdown) Extension() string                 { return ".md" }
+func (Markdown) Configure(map[string]string) error { return nil }
+
+func (Markdown) Run(_ context.Context, tasks []*task.Task, out io.Writer) error {
+	w := bufio.NewWriter(out)
+	fmt.Fprintln(w, "# Задачи")
+	fmt.Fprintln(w)
+	for _, t := range tasks {
+		fmt.Fprint(w, MarkdownItem(t))
+	}
+	return w.Flush()
+}
+
+// MarkdownItem возвращает задачу пунктом списка с флажком; описание - с отступом под пунктом
+func MarkdownItem(t *task.Task) string {
+	var b strings.Builder
+	mark := " "
+	if t.Completed {
+		mark = "x"
+	}
+	fmt.Fprintf(&b, "- [%s] %s (", mark, t.Title)
+	if !t.DueDate.IsZero() {
+		fmt.Fprintf(&b, "срок %s, ", t.DueDate.Format("2006-01-02"))
+	}
+	fmt.Fprintf(&b, "приоритет %s", task.PriorityText(t.Priority))
+	if t.Assignee != "" {
+		fmt.Fprintf(&b, ", %s", t.Assignee)
+	}
+	b.WriteString(")")
+	for _, tag := range t.Tags {
+		fmt.Fprintf(&b, " #%s", strings.ReplaceAll(tag, " ", "_"))
+	}
+	b.WriteString("\n")
+	if t.Description != "" {
+		for _, line := range strings.Split(t.Description, "\n") {
+			fmt.Fprintf(&b, "  %s\n", line)
+		}
+	}
+	return b.String()
+}
+
+// OrgMode выгружает задачи заголовками Emacs org-mode
+type OrgMode struct{}
+
+func (OrgMode) Name() string                      { return "Org-mode" }
+func (OrgMode) Extension() string                 { return ".org" }
+func (OrgMode) Configure(map[string]string) error { return nil }
+
+func (OrgMode) Run(_ context.Context, tasks []*task.Task, out io.Writer) error {
+	return task.WriteOrg(out, tasks)
+}
+
+// TodoTxt выгружает задачи в формате todo.txt
+type TodoTxt struct{}
+
+func (TodoTxt) Name() string                      { return "todo.txt" }
+func (TodoTxt) Extension() string                 { return ".txt" }
+func (TodoTxt) Configure(map[string]string) error { return nil }
+
+func (TodoTxt) Run(_ context.Context, tasks []*task.Task, out io.Writer) error {
+	return task.WriteTodoTxt(out, tasks)
+}
+
+// HTML выгружает отчет о задачах страницей со стилями, чтобы отправить его письмом
+// или открыть в браузере
+type HTML struct{}
+
+func (HTML) Name() string                      { return "HTML" }
+func (HTML) Extension() string                 { return ".html" }
+func (HTML) Configure(map[string]string) error { return nil }
+
+func (HTML) Run(_ context.Context, tasks []*task.Task, out io.Writer) error {
+	return report.Build(tasks, time.Now()).WriteHTML(out)
+}
+
+// ICalendar выгружает задачи в календарь iCalendar (VTODO): его открывают Thunderbird,
+// Apple Reminders и другие программы с задачами
+type ICalendar struct {
+	// Events - добавить к невыполненным задачам события на весь день срока (VEVENT):
+	// календари Google и телефонов не показывают VTODO
+	Events bool
+	// Title - название календаря при подписке; пусто - программа календаря называет его сама
+	Title string
+}
+
+func (ICalendar) Name() string                      { return "iCalendar" }
+func (ICalendar) Extension() string                 { return ".ics" }
+func (ICalendar) Configure(map[string]string) error { return nil }
+
+// icsPriority - приоритеты задач в iCalendar: 1 - самый высокий, 9 - самый низкий
+var icsPriority = map[int]int{1: 9, 2: 5, 3: 1}
+
+func (c ICalendar) Run(_ context.Context, tasks []*task.Task, out io.Writer) error {
+	w := bufio.NewWriter(out)
+	line := func(text string) { writeICSLine(w, text) }
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//GUITaskManager//Tasks//RU")
+	if c.Title != "" {
+		line("X-WR-CALNAME:" + escapeICS(c.Title))
+		line("REFRESH-INTERVAL;VALUE=DURATION:PT15M")
+		line("X-PUBLISHED-TTL:PT15M")
+	}
+	for _, t := range tasks {
+		line("BEGIN:VTODO")
+		line("UID:" + t.UUID)
+		line("DTSTAMP:" + stamp)
+		line("CREATED:" + t.CreatedAt.UTC().Format("20060102T150405Z"))
+		line("LAST-MODIFIED:" + t.UpdatedAt.UTC().Format("20060102T150405Z"))
+		line("SUMMARY:" + escapeICS(t.Title))
+		if t.Description != "" {
+			line("DESCRIPTION:" + escapeICS(t.Description))
+		}
+		if t.Location != "" {
+			line("LOCATION:" + escapeICS(t.Location))
+			if lat, lon, ok := task.ParseGeo(t.Location); ok {
+				line(fmt.Sprintf("GEO:%g;%g", lat, lon))
+			}
+		}
+		if t.Assignee != "" {
+			// Исполнитель - имя, а не адрес, поэтому не ATTENDEE, а свое свойство
+			line("X-ASSIGNEE:" + escapeICS(t.Assignee))
+		}
+		if !t.DueDate.IsZero() {
+			line("DUE;VALUE=DATE:" + t.DueDate.Format("20060102"))
+		}
+		line(fmt.Sprintf("PRIORITY:%d", icsPriority[t.Priority]))
+		if len(t.Tags) > 0 {
+			escaped := make([]string, len(t.Tags))
+			for i, tag := range t.Tags {
+				escaped[i] = escapeICS(tag)
+			}
+			line("CATEGORIES:" + strings.Join(escaped, ","))
+		}
+		if t.Completed {
+			line("STATUS:COMPLETED")
+		} else {
+			line("STATUS:NEEDS-ACTION")
+		}
+		line("END:VTODO")
+
+		if c.Events && !t.Completed && !t.DueDate.IsZero() {
+			line("BEGIN:VEVENT")
+			line("UID:" + t.UUID + "-due")
+			line("DTSTAMP:" + stamp)
+			line("LAST-MODIFIED:" + t.UpdatedAt.UTC().Format("20060102T150405Z"))
+			line("SUMMARY:" + escapeICS(t.Title))
+			if t.Description != "" {
+				line("DESCRIPTION:" + escapeICS(t.Description))
+			}
+			if t.Location != "" {
+				line("LOCATION:" + escapeICS(t.Location))
+			}
+			if t.Assignee != "" {
+				line("X-ASSIGNEE:" + escapeICS(t.Assignee))
+			}
+			line("DTSTART;VALUE=DATE:" + t.DueDate.Format("20060102"))
+			line("DTEND;VALUE=DATE:" + t.DueDate.AddDate(0, 0, 1).Format("20060102"))
+			line("TRANSP:TRANSPARENT")
+			line("END:VEVENT")
+		}
+	}
+	line("END:VCALENDAR")
+	return w.Flush()
+}
+
+// escapeICS экранирует текст значения iCalendar
+func escapeICS(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
+
+// writeICSLine пишет строку iCalendar, перенося ее через каждые 75 байт без разрыва символов UTF-8
+func writeICSLine(w *bufio.Writer, text string) {
+	const limit = 75
+	width := 0
+	for _, r := range text {
+		size := len(string(r))
+		if width+size > limit {
+			w.WriteString("\r\n ")
+			width = 1
+		}
+		w.WriteRune(r)
+		width += size
+	}
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,235 @@
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"taskmanager/task"
+)
+
+// JiraTag - метка-проект задач, загруженных из Jira
+const JiraTag = "Jira"
+
+// ErrNoDoneTransition возвращается, если из текущего статуса задачи Jira нельзя перейти в выполненные
+var ErrNoDoneTransition = errors.New("jira: no transition to a done status")
+
+// Jira загружает назначенные пользователю задачи Jira и передает в Jira учет времени
+// и выполнение задач. Работает с Jira Cloud (почта и токен API) и Jira Server
+// (персональный токен без почты)
+type Jira struct {
+	BaseURL string
+	Email   string
+	Token   string
+	HTTP    *http.Client
+}
+
+// NewJira создает подключение к Jira по адресу baseURL
+func NewJira(baseURL, email, token string) *Jira {
+	return &Jira{
+		BaseURL: strings.TrimRight(strings.TrimSpace(baseURL), "/"),
+		Email:   strings.TrimSpace(email),
+		Token:   strings.TrimSpace(token),
+		HTTP:    &http.Client{Timeout: time.Minute},
+	}
+}
+
+// jiraIssue - задача Jira в REST API 2: описание в нем простым текстом, а не документом ADF
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary     string `json:"summary"`
+		Description string `json:"description"`
+		DueDate     string `json:"duedate"`
+		Created     string `json:"created"`
+		Updated     string `json:"updated"`
+		Priority    *struct {
+			Name string `json:"name"`
+		} `json:"priority"`
+		Project struct {
+			Key string `json:"key"`
+		} `json:"project"`
+	} `json:"fields"`
+}
+
+// jiraPriority - приоритеты задач по стандартным приоритетам Jira
+var jiraPriority = map[string]int{"highest": 3, "high": 3, "medium": 2, "low": 1, "lowest": 1}
+
+// jiraTimeLayout - время в ответах Jira
+const jiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// jiraPageSize - сколько задач запрашивать за раз
+const jiraPageSize = 100
+
+// Assigned загружает нерешенные задачи Jira, назначенные пользователю. Задачам без срока
+// назначается срок today
+func (j *Jira) Assigned(ctx context.Context, today time.Time) ([]*task.Task, error) {
+	var tasks []*task.Task
+	for startAt := 0; ; {
+		query := url.Values{
+			"jql":        {"assignee = currentUser() AND resolution = Unresolved ORDER BY updated DESC"},
+			"fields":     {"summary,description,duedate,created,updated,priority,project"},
+			"startAt":    {fmt.Sprint(startAt)},
+			"maxResults": {fmt.Sprint(jiraPageSize)},
+		}
+		var page struct {
+			Total  int         `json:"total"`
+			Issues []jiraIssue `json:"issues"`
+		}
+		if err := j.do(ctx, http.MethodGet, "/rest/api/2/search?"+query.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+		for _, issue := range page.Issues {
+			t, err := j.issueTask(issue, today)
+			if err != nil {
+				return nil, fmt.Errorf("jira: %s: %w", issue.Key, err)
+			}
+			tasks = append(tasks, t)
+		}
+		startAt += len(page.Issues)
+		if len(page.Issues) == 0 || startAt >= page.Total {
+			return tasks, nil
+		}
+	}
+}
+
+// issueTask переводит задачу Jira в задачу приложения. UUID получается из адреса задачи,
+// поэтому при обновлении задача находит свою копию
+func (j *Jira) issueTask(issue jiraIssue, today time.Time) (*task.Task, error) {
+	t := &task.Task{
+		UUID:        j.IssueUUID(issue.Key),
+		Title:       issue.Key + " " + strings.TrimSpace(issue.Fields.Summary),
+		Description: strings.TrimSpace(issue.Fields.Description),
+		Priority:    2,
+		URL:         j.IssueURL(issue.Key),
+		Tags:        []string{JiraTag},
+		DueDate:     today,
+	}
+	if issue.Fields.Project.Key != "" {
+		t.Tags = append(t.Tags, issue.Fields.Project.Key)
+	}
+	if issue.Fields.Priority != nil {
+		if priority, ok := jiraPriority[strings.ToLower(issue.Fields.Priority.Name)]; ok {
+			t.Priority = priority
+		}
+	}
+	if issue.Fields.DueDate != "" {
+		due, err := time.ParseInLocation(task.DueDateLayout, issue.Fields.DueDate, today.Location())
+		if err != nil {
+			return nil, fmt.Errorf("invalid due date %q", issue.Fields.DueDate)
+		}
+		t.DueDate = due
+	}
+	for field, value := range map[*time.Time]string{&t.CreatedAt: issue.Fields.Created, &t.UpdatedAt: issue.Fields.Updated} {
+		parsed, err := time.Parse(jiraTimeLayout, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time %q", value)
+		}
+		*field = parsed
+	}
+	return t, nil
+}
+
+// IssueURL возвращает адрес задачи Jira в браузере
+func (j *Jira) IssueURL(key string) string {
+	return j.BaseURL + "/browse/" + url.PathEscape(key)
+}
+
+// IssueUUID возвращает UUID задачи приложения для задачи Jira
+func (j *Jira) IssueUUID(key string) string {
+	return nameUUID("jira:" + j.IssueURL(key))
+}
+
+// IssueKey возвращает ключ задачи Jira по адресу задачи приложения или пустую строку,
+// если задача не из этой Jira
+func (j *Jira) IssueKey(t *task.Task) string {
+	key, ok := strings.CutPrefix(t.URL, j.BaseURL+"/browse/")
+	if !ok || key == "" || j.IssueUUID(key) != t.UUID {
+		return ""
+	}
+	return key
+}
+
+// LogWork учитывает время spent в задаче Jira key. Jira считает время с точностью до минуты
+func (j *Jira) LogWork(ctx context.Context, key string, spent time.Duration) error {
+	seconds := int(spent.Round(time.Minute) / time.Second)
+	if seconds <= 0 {
+		return errors.New("jira: time spent must be at least a minute")
+	}
+	body := map[string]any{"timeSpentSeconds": seconds}
+	return j.do(ctx, http.MethodPost, "/rest/api/2/issue/"+url.PathEscape(key)+"/worklog", body, nil)
+}
+
+// MarkDone переводит задачу Jira key в первый доступный статус категории «Готово»
+func (j *Jira) MarkDone(ctx context.Context, key string) error {
+	path := "/rest/api/2/issue/" + url.PathEscape(key) + "/transitions"
+	var transitions struct {
+		Transitions []struct {
+			ID string `json:"id"`
+			To struct {
+				StatusCategory struct {
+					Key string `json:"key"`
+				} `json:"statusCategory"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	if err := j.do(ctx, http.MethodGet, path, nil, &transitions); err != nil {
+		return err
+	}
+	for _, transition := range transitions.Transitions {
+		if transition.To.StatusCategory.Key == "done" {
+			body := map[string]any{"transition": map[string]string{"id": transition.ID}}
+			return j.do(ctx, http.MethodPost, path, body, nil)
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrNoDoneTransition, key)
+}
+
+// do выполняет запрос к REST API Jira: body отправляется JSON, ответ разбирается в result
+func (j *Jira) do(ctx context.Context, method, path string, body, result any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, j.BaseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if j.Email != "" {
+		req.SetBasicAuth(j.Email, j.Token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+j.Token)
+	}
+
+	resp, err := j.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("jira: %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("jira: %w", err)
+	}
+	return nil
+}
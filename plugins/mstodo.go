@@ -0,0 +1,228 @@
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"taskmanager/reminders"
+	"taskmanager/storage"
+	"taskmanager/task"
+)
+
+// microsoftGraphAPI - адрес Microsoft Graph, через который доступны списки Microsoft To Do
+const microsoftGraphAPI = "https://graph.microsoft.com/v1.0"
+
+// MicrosoftOAuth возвращает настройки входа в учетную запись Microsoft для чтения задач.
+// clientID - приложение, зарегистрированное в Azure как общедоступный клиент
+func MicrosoftOAuth(clientID string) storage.OAuthConfig {
+	return storage.OAuthConfig{
+		ClientID: clientID,
+		AuthURL:  "https://login.microsoftonline.com/common/oauth2/v2.0/authorize",
+		TokenURL: "https://login.microsoftonline.com/common/oauth2/v2.0/token",
+		Scopes:   []string{"Tasks.Read"},
+	}
+}
+
+// MicrosoftToDo загружает задачи из Microsoft To Do (и задачи Outlook, которые хранятся там же)
+type MicrosoftToDo struct {
+	BaseURL string
+	// HTTP должен авторизовать запросы, см. storage.NewOAuthClient
+	HTTP *http.Client
+}
+
+// NewMicrosoftToDo создает загрузку задач через client
+func NewMicrosoftToDo(client *http.Client) *MicrosoftToDo {
+	return &MicrosoftToDo{BaseURL: microsoftGraphAPI, HTTP: client}
+}
+
+// msList - список задач Microsoft To Do
+type msList struct {
+	ID                string `json:"id"`
+	DisplayName       string `json:"displayName"`
+	WellknownListName string `json:"wellknownListName"` // defaultList - список «Задачи»
+}
+
+// msDateTime - время Graph: без смещения, часовой пояс отдельно
+type msDateTime struct {
+	DateTime string `json:"dateTime"`
+	TimeZone string `json:"timeZone"`
+}
+
+// msTask - задача Microsoft To Do
+type msTask struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	Importance string `json:"importance"` // low, normal или high
+	Status     string `json:"status"`     // completed или другие состояния невыполненной задачи
+	Body       struct {
+		Content string `json:"content"`
+	} `json:"body"`
+	Categories           []string    `json:"categories"`
+	CreatedDateTime      time.Time   `json:"createdDateTime"`
+	LastModifiedDateTime time.Time   `json:"lastModifiedDateTime"`
+	DueDateTime          *msDateTime `json:"dueDateTime"`
+	StartDateTime        *msDateTime `json:"startDateTime"`
+	CompletedDateTime    *msDateTime `json:"completedDateTime"`
+	ReminderDateTime     *msDateTime `json:"reminderDateTime"`
+	IsReminderOn         bool        `json:"isReminderOn"`
+	ChecklistItems       []struct {
+		DisplayName string `json:"displayName"`
+		IsChecked   bool   `json:"isChecked"`
+	} `json:"checklistItems"`
+}
+
+// msImportance - приоритеты задач по важности Microsoft To Do
+var msImportance = map[string]int{"low": 1, "normal": 2, "high": 3}
+
+// Fetch загружает задачи всех списков. Списки, кроме стандартного «Задачи», становятся
+// метками-проектами; задачам без срока назначается срок today
+func (m *MicrosoftToDo) Fetch(ctx context.Context, today time.Time) ([]*task.Task, error) {
+	var lists []msList
+	if err := graphGetAll(ctx, m.HTTP, m.BaseURL+"/me/todo/lists", &lists); err != nil {
+		return nil, err
+	}
+	var tasks []*task.Task
+	for _, list := range lists {
+		var items []msTask
+		listURL := m.BaseURL + "/me/todo/lists/" + url.PathEscape(list.ID) + "/tasks?$expand=checklistItems"
+		if err := graphGetAll(ctx, m.HTTP, listURL, &items); err != nil {
+			return nil, fmt.Errorf("microsoft to do: list %q: %w", list.DisplayName, err)
+		}
+		for _, item := range items {
+			t, err := item.toTask(list, today)
+			if err != nil {
+				return nil, fmt.Errorf("microsoft to do: %q: %w", item.Title, err)
+			}
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks, nil
+}
+
+// graphGetAll читает все страницы коллекции Graph в items
+func graphGetAll[T any](ctx context.Context, client *http.Client, next string, items *[]T) error {
+	for next != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, next, nil)
+		if err != nil {
+			return err
+		}
+		// Заметки задач нужны простым текстом, а не HTML
+		req.Header.Set("Prefer", `outlook.body-content-type="text"`)
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+			resp.Body.Close()
+			return fmt.Errorf("microsoft to do: %s: %s", resp.Status, strings.TrimSpace(string(message)))
+		}
+		var page struct {
+			Value    []T    `json:"value"`
+			NextLink string `json:"@odata.nextLink"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("microsoft to do: %w", err)
+		}
+		*items = append(*items, page.Value...)
+		next = page.NextLink
+	}
+	return nil
+}
+
+// time переводит время Graph в местное время. Неизвестный часовой пояс считается UTC,
+// как его пишет сам Graph
+func (d *msDateTime) time() (time.Time, error) {
+	if d == nil || d.DateTime == "" {
+		return time.Time{}, nil
+	}
+	loc, err := time.LoadLocation(d.TimeZone)
+	if err != nil || d.TimeZone == "" {
+		loc = time.UTC
+	}
+	t, err := time.ParseInLocation("2006-01-02T15:04:05.9999999", d.DateTime, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", d.DateTime)
+	}
+	return t.Local(), nil
+}
+
+// date возвращает день времени Graph. Сроки в To Do - дни без времени, Graph хранит их
+// полночью в часовом поясе пользователя, поэтому день берется из записи без перевода
+func (d *msDateTime) date(loc *time.Location) (time.Time, error) {
+	if d == nil || d.DateTime == "" {
+		return time.Time{}, nil
+	}
+	day, _, _ := strings.Cut(d.DateTime, "T")
+	t, err := time.ParseInLocation(task.DueDateLayout, day, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", d.DateTime)
+	}
+	return t, nil
+}
+
+// toTask переводит задачу To Do в задачу приложения. UUID получается из идентификатора
+// задачи в To Do, чтобы повторный импорт находил уже загруженные задачи
+func (item msTask) toTask(list msList, today time.Time) (*task.Task, error) {
+	t := &task.Task{
+		UUID:        nameUUID("microsoft-todo:" + item.ID),
+		Title:       strings.TrimSpace(item.Title),
+		Description: strings.TrimSpace(item.Body.Content),
+		Priority:    msImportance[item.Importance],
+		CreatedAt:   item.CreatedDateTime,
+		UpdatedAt:   item.LastModifiedDateTime,
+		Completed:   item.Status == "completed",
+	}
+	if t.Priority == 0 {
+		t.Priority = 2
+	}
+	if list.WellknownListName != "defaultList" && list.DisplayName != "" {
+		t.Tags = append(t.Tags, list.DisplayName)
+	}
+	for _, category := range item.Categories {
+		if category != "" && category != list.DisplayName {
+			t.Tags = append(t.Tags, category)
+		}
+	}
+	for _, entry := range item.ChecklistItems {
+		t.Checklist = append(t.Checklist, task.ChecklistItem{Text: entry.DisplayName, Done: entry.IsChecked})
+	}
+
+	var err error
+	if t.DueDate, err = item.DueDateTime.date(today.Location()); err != nil {
+		return nil, err
+	}
+	if t.DueDate.IsZero() {
+		t.DueDate = today
+	}
+	if t.StartDate, err = item.StartDateTime.date(today.Location()); err != nil {
+		return nil, err
+	}
+	if t.Completed {
+		if t.CompletedAt, err = item.CompletedDateTime.time(); err != nil {
+			return nil, err
+		}
+	}
+	if item.IsReminderOn {
+		at, err := item.ReminderDateTime.time()
+		if err != nil {
+			return nil, err
+		}
+		// Напоминание до срока становится напоминанием относительно срока и переедет
+		// вместе со сроком; напоминание после срока остается на свое время
+		if offset := reminders.DueTime(t).Sub(at); !at.IsZero() && item.DueDateTime != nil && offset >= 0 {
+			t.ReminderOffsets = []time.Duration{offset}
+		} else {
+			t.RemindAt = at
+		}
+	}
+	return t, nil
+}
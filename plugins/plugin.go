@@ -0,0 +1,123 @@
+// Package plugins содержит расширения экспорта и интеграций: выгрузку задач в CSV,
+// HTML, Markdown, org-mode, todo.txt, iCalendar, отправку в Todoist и внешние программы-плагины,
+// загрузку задач из Microsoft To Do и Jira, команду захвата для диктовки задач.
+// Интерфейс приложения находит их в реестре и не знает о конкретных форматах
+package plugins
+
+import (
+	"context"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"taskmanager/task"
+)
+
+// ErrDuplicate возвращается при регистрации второго расширения с тем же именем
+var ErrDuplicate = errors.New("plugin already registered")
+
+// Plugin - расширение экспорта или интеграции
+type Plugin interface {
+	// Name - название для меню; по нему же хранятся настройки расширения
+	Name() string
+	// Configure задает настройки перед запуском: ключи из Options, значения - как ввел пользователь
+	Configure(options map[string]string) error
+	// Run выгружает задачи. Задачи - копии, их можно читать из любой горутины.
+	// out - файл, выбранный пользователем, или nil, если расширение не пишет файл
+	Run(ctx context.Context, tasks []*task.Task, out io.Writer) error
+}
+
+// FileExporter - расширение, которое пишет файл: перед запуском пользователь выбирает, куда сохранить
+type FileExporter interface {
+	Plugin
+	// Extension - расширение файла по умолчанию, например ".csv"; пусто - файл не нужен
+	Extension() string
+}
+
+// WritesFile проверяет, нужен ли расширению файл для записи
+func WritesFile(p Plugin) (extension string, ok bool) {
+	exporter, ok := p.(FileExporter)
+	if !ok || exporter.Extension() == "" {
+		return "", false
+	}
+	return exporter.Extension(), true
+}
+
+// Configurable - расширение с настройками, которые пользователь вводит перед запуском
+type Configurable interface {
+	Plugin
+	Options() []Option
+}
+
+// Option - настройка расширения
+type Option struct {
+	Key    string `json:"key"`
+	Title  string `json:"title"`
+	Secret bool   `json:"secret,omitempty"` // пароль или токен: поле ввода скрывает текст
+}
+
+// Registry - зарегистрированные расширения
+type Registry struct {
+	plugins map[string]Plugin
+}
+
+// NewRegistry создает пустой реестр
+func NewRegistry() *Registry {
+	return &Registry{plugins: make(map[string]Plugin)}
+}
+
+// Builtin создает реестр со встроенными расширениями
+func Builtin() *Registry {
+	r := NewRegistry()
+	for _, p := range []Plugin{&CSV{}, &JSON{}, HTML{}, Markdown{}, OrgMode{}, TodoTxt{}, ICalendar{}, NewTodoist()} {
+		if err := r.Register(p); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// Register добавляет расширение. Имена расширений не должны совпадать
+func (r *Registry) Register(p Plugin) error {
+	if _, ok := r.plugins[p.Name()]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicate, p.Name())
+	}
+	r.plugins[p.Name()] = p
+	return nil
+}
+
+// Get возвращает расширение по имени или nil
+func (r *Registry) Get(name string) Plugin {
+	return r.plugins[name]
+}
+
+// Plugins возвращает расширения по алфавиту
+func (r *Registry) Plugins() []Plugin {
+	list := make([]Plugin, 0, len(r.plugins))
+	for _, p := range r.plugins {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+	return list
+}
+
+// Snapshot копирует задачи для передачи расширению
+func Snapshot(tasks []*task.Task) []*task.Task {
+	copies := make([]*task.Task, len(tasks))
+	for i, t := range tasks {
+		copies[i] = t.Clone()
+	}
+	return copies
+}
+
+// nameUUID возвращает UUID версии 5 для задачи из другого сервиса: повторная загрузка
+// той же задачи дает тот же UUID, и импорт находит уже загруженную копию
+func nameUUID(name string) string {
+	sum := sha1.Sum([]byte(name))
+	b := sum[:16]
+	b[6] = b[6]&0x0f | 0x50 // Версия 5
+	b[8] = b[8]&0x3f | 0x80 // Вариант RFC 4122
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,415 @@
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"taskmanager/reminders"
+	"taskmanager/task"
+)
+
+func testTasks() []*task.Task {
+	due := time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)
+	return []*task.Task{
+		{ID: 1, UUID: "u1", Title: "Купить молоко", Description: "2 литра\nобезжиренное", Priority: 1, DueDate: due, Tags: []string{"дом"}},
+		{ID: 2, UUID: "u2", Title: "Сдать отчет; срочно", Priority: 3, DueDate: due, Completed: true, Assignee: "Маша"},
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := Builtin()
+	var names []string
+	for _, p := range r.Plugins() {
+		names = append(names, p.Name())
+	}
+	assert.Equal(t, []string{"CSV", "HTML", "JSON", "Markdown", "Org-mode", "Todoist", "iCalendar", "todo.txt"}, names)
+	assert.ErrorIs(t, r.Register(&CSV{}), ErrDuplicate)
+	assert.Nil(t, r.Get("XML"))
+
+	ext, ok := WritesFile(r.Get("CSV"))
+	assert.True(t, ok)
+	assert.Equal(t, ".csv", ext)
+	_, ok = WritesFile(r.Get("Todoist"))
+	assert.False(t, ok)
+}
+
+func TestSnapshot(t *testing.T) {
+	tasks := testTasks()
+	copies := Snapshot(tasks)
+	copies[0].Title = "Другое"
+	copies[0].Tags[0] = "работа"
+	assert.Equal(t, "Купить молоко", tasks[0].Title)
+	assert.Equal(t, []string{"дом"}, tasks[0].Tags)
+}
+
+func TestCSV(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, (&CSV{}).Run(context.Background(), testTasks(), &out))
+	records, err := csv.NewReader(&out).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, "Купить молоко", records[1][1])
+	assert.Equal(t, "Yes", records[2][6])
+}
+
+func TestCSVConfigure(t *testing.T) {
+	p := &CSV{}
+	require.NoError(t, p.Configure(map[string]string{
+		CSVDelimiter: "tab",
+		CSVBOM:       "yes",
+		CSVColumns:   "id, title",
+		CSVFilter:    "due_range",
+		CSVFrom:      "2030-01-01",
+	}))
+	assert.Equal(t, '\t', p.Options.Delimiter)
+	assert.True(t, p.Options.BOM)
+	assert.Equal(t, []string{"id", "title"}, p.Options.Columns)
+	assert.Equal(t, "2030-01-01", p.Options.From.Format("2006-01-02"))
+	assert.True(t, p.Options.To.IsZero())
+
+	var out bytes.Buffer
+	require.NoError(t, p.Run(context.Background(), testTasks(), &out))
+	assert.True(t, strings.HasPrefix(out.String(), "\ufeffID\tTitle\n"))
+
+	assert.Error(t, p.Configure(map[string]string{CSVDelimiter: "|"}))
+	assert.Error(t, p.Configure(map[string]string{CSVFilter: "due_range", CSVTo: "завтра"}))
+	// Настройки по умолчанию
+	require.NoError(t, p.Configure(nil))
+	assert.Equal(t, ',', p.Options.Delimiter)
+}
+
+func TestJSON(t *testing.T) {
+	tasks := testTasks()
+	tasks[1].Archived = true
+	p := &JSON{}
+	require.NoError(t, p.Configure(nil))
+	var out bytes.Buffer
+	require.NoError(t, p.Run(context.Background(), tasks, &out))
+	assert.NotContains(t, out.String(), "\n  ")
+	envelope, err := task.ReadJSON(&out)
+	require.NoError(t, err)
+	require.Len(t, envelope.Tasks, 1)
+	assert.Equal(t, "u1", envelope.Tasks[0].UUID)
+
+	require.NoError(t, p.Configure(map[string]string{JSONPretty: "yes", JSONArchived: "yes"}))
+	out.Reset()
+	require.NoError(t, p.Run(context.Background(), tasks, &out))
+	assert.Contains(t, out.String(), "\n  ")
+	envelope, err = task.ReadJSON(&out)
+	require.NoError(t, err)
+	assert.Len(t, envelope.Tasks, 2)
+}
+
+func TestHTML(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, HTML{}.Run(context.Background(), testTasks(), &out))
+	assert.Contains(t, out.String(), "Купить молоко")
+	assert.Contains(t, out.String(), "<!DOCTYPE html>")
+}
+
+func TestMarkdown(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, Markdown{}.Run(context.Background(), testTasks(), &out))
+	assert.Equal(t, "# Задачи\n\n"+
+		"- [ ] Купить молоко (срок 2030-01-07, приоритет низкий) #дом\n"+
+		"  2 литра\n"+
+		"  обезжиренное\n"+
+		"- [x] Сдать отчет; срочно (срок 2030-01-07, приоритет высокий, Маша)\n", out.String())
+
+	assert.Equal(t, "- [ ] Когда-нибудь (приоритет средний)\n", MarkdownItem(&task.Task{Title: "Когда-нибудь", Priority: 2}))
+}
+
+func TestICalendar(t *testing.T) {
+	tasks := testTasks()
+	tasks[0].Description = strings.Repeat("очень длинное описание ", 10)
+	tasks[0].Location = "55.7539, 37.6208"
+	tasks[1].Location = "Москва, Тверская, 1"
+	tasks[0].Assignee = "Петров, Иван"
+	var out bytes.Buffer
+	require.NoError(t, ICalendar{}.Run(context.Background(), tasks, &out))
+	text := out.String()
+
+	assert.True(t, strings.HasPrefix(text, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.Contains(t, text, "UID:u1\r\n")
+	assert.Contains(t, text, `SUMMARY:Сдать отчет\; срочно`+"\r\n")
+	assert.Contains(t, text, "DUE;VALUE=DATE:20300107\r\n")
+	assert.Contains(t, text, "PRIORITY:9\r\n")
+	assert.Contains(t, text, "CATEGORIES:дом\r\n")
+	assert.Contains(t, text, "STATUS:COMPLETED\r\n")
+	assert.Contains(t, text, "GEO:55.7539;37.6208\r\n")
+	assert.Contains(t, text, `LOCATION:Москва\, Тверская\, 1`+"\r\n")
+	assert.Equal(t, 1, strings.Count(text, "GEO:"))
+	assert.Contains(t, text, "X-ASSIGNEE:Маша\r\n")
+	assert.Contains(t, text, `X-ASSIGNEE:Петров\, Иван`+"\r\n")
+	assert.True(t, strings.HasSuffix(text, "END:VCALENDAR\r\n"))
+
+	// Длинные строки переносятся, каждая часть - не длиннее 75 байт и без разрыва символов
+	for _, line := range strings.Split(text, "\r\n") {
+		assert.LessOrEqual(t, len(line), 75)
+		assert.True(t, strings.ToValidUTF8(line, "?") == line)
+	}
+	unfolded := strings.ReplaceAll(text, "\r\n ", "")
+	assert.Contains(t, unfolded, "DESCRIPTION:"+strings.Repeat("очень длинное описание ", 10))
+	assert.NotContains(t, text, "VEVENT")
+	assert.NotContains(t, text, "X-WR-CALNAME")
+
+	// Для подписки календарями: события на день срока только для невыполненных задач
+	out.Reset()
+	require.NoError(t, ICalendar{Events: true, Title: "Задачи"}.Run(context.Background(), tasks, &out))
+	text = out.String()
+	assert.Contains(t, text, "X-WR-CALNAME:Задачи\r\n")
+	assert.Equal(t, 1, strings.Count(text, "BEGIN:VEVENT"))
+	assert.Contains(t, text, "UID:u1-due\r\nDTSTAMP:")
+	assert.Contains(t, text, `X-ASSIGNEE:Петров\, Иван`+"\r\nDTSTART;VALUE=DATE:20300107\r\nDTEND;VALUE=DATE:20300108\r\n")
+
+	// У задачи без срока нет ни DUE, ни события
+	out.Reset()
+	someday := &task.Task{UUID: "u3", Title: "Когда-нибудь", Priority: 2}
+	require.NoError(t, ICalendar{Events: true}.Run(context.Background(), []*task.Task{someday}, &out))
+	assert.NotContains(t, out.String(), "DUE")
+	assert.NotContains(t, out.String(), "VEVENT")
+}
+
+func TestTodoist(t *testing.T) {
+	var created []todoistTask
+	var requestIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/tasks", r.URL.Path)
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		var body todoistTask
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		created = append(created, body)
+		requestIDs = append(requestIDs, r.Header.Get("X-Request-Id"))
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer server.Close()
+
+	p := NewTodoist()
+	p.BaseURL = server.URL
+	assert.Error(t, p.Configure(map[string]string{}))
+
+	require.NoError(t, p.Configure(map[string]string{"token": "secret", "project_id": "42"}))
+	require.NoError(t, p.Run(context.Background(), testTasks(), nil))
+
+	// Выполненная задача не отправляется
+	assert.Equal(t, []todoistTask{{
+		Content: "Купить молоко", Description: "2 литра\nобезжиренное", Priority: 2,
+		DueDate: "2030-01-07", Labels: []string{"дом"}, ProjectID: "42",
+	}}, created)
+	assert.Equal(t, []string{"u1"}, requestIDs)
+
+	require.NoError(t, p.Configure(map[string]string{"token": "wrong"}))
+	assert.ErrorContains(t, p.Run(context.Background(), testTasks(), nil), "403")
+}
+
+func TestMicrosoftToDo(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, `outlook.body-content-type="text"`, r.Header.Get("Prefer"))
+		switch r.URL.Path {
+		case "/me/todo/lists":
+			if r.URL.Query().Get("page") == "" {
+				fmt.Fprintf(w, `{"value":[{"id":"inbox","displayName":"Задачи","wellknownListName":"defaultList"}],"@odata.nextLink":%q}`,
+					server.URL+"/me/todo/lists?page=2")
+				return
+			}
+			w.Write([]byte(`{"value":[{"id":"work","displayName":"Работа","wellknownListName":"none"}]}`))
+		case "/me/todo/lists/inbox/tasks":
+			w.Write([]byte(`{"value":[{"id":"t1","title":"Купить молоко","importance":"low","status":"notStarted",
+				"createdDateTime":"2026-10-01T08:00:00Z","lastModifiedDateTime":"2026-10-02T08:00:00Z"}]}`))
+		case "/me/todo/lists/work/tasks":
+			assert.Equal(t, "checklistItems", r.URL.Query().Get("$expand"))
+			w.Write([]byte(`{"value":[{"id":"t2","title":"Сдать отчет","importance":"high","status":"completed",
+				"body":{"content":"Квартальный\n","contentType":"text"},"categories":["Срочно"],
+				"createdDateTime":"2026-10-01T08:00:00Z","lastModifiedDateTime":"2026-10-05T08:00:00Z",
+				"dueDateTime":{"dateTime":"2026-10-20T00:00:00.0000000","timeZone":"Russian Standard Time"},
+				"completedDateTime":{"dateTime":"2026-10-05T00:00:00.0000000","timeZone":"UTC"},
+				"reminderDateTime":{"dateTime":"2026-10-19T09:00:00.0000000","timeZone":"UTC"},"isReminderOn":true,
+				"checklistItems":[{"displayName":"Таблицы","isChecked":true}]}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	today := time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)
+	m := NewMicrosoftToDo(server.Client())
+	m.BaseURL = server.URL
+	tasks, err := m.Fetch(context.Background(), today)
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+
+	milk, report := tasks[0], tasks[1]
+	assert.Equal(t, "Купить молоко", milk.Title)
+	assert.Equal(t, 1, milk.Priority)
+	assert.Equal(t, today, milk.DueDate, "задачи без срока получают срок today")
+	assert.Empty(t, milk.Tags, "стандартный список не становится проектом")
+	assert.Equal(t, nameUUID("microsoft-todo:t1"), milk.UUID)
+
+	assert.Equal(t, 3, report.Priority)
+	assert.Equal(t, "Квартальный", report.Description)
+	assert.Equal(t, []string{"Работа", "Срочно"}, report.Tags)
+	assert.Equal(t, time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local), report.DueDate)
+	assert.True(t, report.Completed)
+	assert.Equal(t, time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC), report.CompletedAt.UTC())
+	assert.Equal(t, []task.ChecklistItem{{Text: "Таблицы", Done: true}}, report.Checklist)
+	remindAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	require.Len(t, report.ReminderOffsets, 1)
+	assert.True(t, remindAt.Equal(reminders.DueTime(report).Add(-report.ReminderOffsets[0])))
+
+	// Повторный импорт дает те же UUID
+	again, err := m.Fetch(context.Background(), today)
+	require.NoError(t, err)
+	assert.Equal(t, report.UUID, again[1].UUID)
+
+	m.BaseURL = server.URL + "/missing"
+	_, err = m.Fetch(context.Background(), today)
+	assert.ErrorContains(t, err, "404")
+}
+
+func TestJira(t *testing.T) {
+	var worklogs []map[string]any
+	var transitioned []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "me@example.com" || password != "secret" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.URL.Path == "/rest/api/2/search":
+			assert.Contains(t, r.URL.Query().Get("jql"), "assignee = currentUser()")
+			issue := `{"key":"APP-%d","fields":{"summary":"Задача %d","description":"Описание","duedate":%s,
+				"created":"2026-10-01T10:00:00.000+0300","updated":"2026-10-02T10:00:00.000+0300",
+				"priority":{"name":"High"},"project":{"key":"APP"}}}`
+			// Задачи приходят двумя страницами
+			if r.URL.Query().Get("startAt") == "0" {
+				fmt.Fprintf(w, `{"total":2,"issues":[`+issue+`]}`, 1, 1, `"2026-10-20"`)
+			} else {
+				fmt.Fprintf(w, `{"total":2,"issues":[`+issue+`]}`, 2, 2, "null")
+			}
+		case r.URL.Path == "/rest/api/2/issue/APP-1/worklog" && r.Method == http.MethodPost:
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			worklogs = append(worklogs, body)
+			w.WriteHeader(http.StatusCreated)
+		case r.URL.Path == "/rest/api/2/issue/APP-1/transitions" && r.Method == http.MethodGet:
+			w.Write([]byte(`{"transitions":[{"id":"11","to":{"statusCategory":{"key":"indeterminate"}}},
+				{"id":"31","to":{"statusCategory":{"key":"done"}}}]}`))
+		case r.URL.Path == "/rest/api/2/issue/APP-2/transitions" && r.Method == http.MethodGet:
+			w.Write([]byte(`{"transitions":[]}`))
+		case r.URL.Path == "/rest/api/2/issue/APP-1/transitions" && r.Method == http.MethodPost:
+			var body struct {
+				Transition struct {
+					ID string `json:"id"`
+				} `json:"transition"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			transitioned = append(transitioned, body.Transition.ID)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	today := time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)
+	jira := NewJira(server.URL+"/", "me@example.com", "secret")
+	tasks, err := jira.Assigned(context.Background(), today)
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "APP-1 Задача 1", tasks[0].Title)
+	assert.Equal(t, 3, tasks[0].Priority)
+	assert.Equal(t, server.URL+"/browse/APP-1", tasks[0].URL)
+	assert.Equal(t, []string{JiraTag, "APP"}, tasks[0].Tags)
+	assert.Equal(t, time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local), tasks[0].DueDate)
+	assert.Equal(t, today, tasks[1].DueDate, "задачи без срока получают срок today")
+	assert.Equal(t, "APP-1", jira.IssueKey(tasks[0]))
+	assert.Empty(t, jira.IssueKey(&task.Task{URL: tasks[0].URL, UUID: "other"}))
+
+	require.NoError(t, jira.LogWork(context.Background(), "APP-1", 90*time.Minute+10*time.Second))
+	assert.Equal(t, []map[string]any{{"timeSpentSeconds": float64(5400)}}, worklogs)
+	assert.Error(t, jira.LogWork(context.Background(), "APP-1", 10*time.Second))
+
+	require.NoError(t, jira.MarkDone(context.Background(), "APP-1"))
+	assert.Equal(t, []string{"31"}, transitioned)
+	assert.ErrorIs(t, jira.MarkDone(context.Background(), "APP-2"), ErrNoDoneTransition)
+
+	jira.Token = "wrong"
+	_, err = jira.Assigned(context.Background(), today)
+	assert.ErrorContains(t, err, "401")
+}
+
+func TestExternal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not executable on Windows")
+	}
+	dir := t.TempDir()
+	script := `#!/bin/sh
+case "$1" in
+describe) echo '{"name":"Счетчик","extension":".txt","options":[{"key":"prefix","title":"Префикс"}]}' ;;
+run) input=$(cat); echo "$input" | grep -o '"prefix":"[^"]*"' ;;
+esac
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "counter"), []byte(script), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken"), []byte("#!/bin/sh\necho oops >&2\nexit 1\n"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "readme.txt"), []byte("не программа"), 0o644))
+
+	loaded, errs := LoadExternal(context.Background(), dir)
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "oops")
+	require.Len(t, loaded, 1)
+
+	p := loaded[0]
+	assert.Equal(t, "Счетчик", p.Name())
+	assert.Equal(t, []Option{{Key: "prefix", Title: "Префикс"}}, p.Options())
+	ext, ok := WritesFile(p)
+	assert.True(t, ok)
+	assert.Equal(t, ".txt", ext)
+
+	require.NoError(t, p.Configure(map[string]string{"prefix": "- "}))
+	var out bytes.Buffer
+	require.NoError(t, p.Run(context.Background(), testTasks(), &out))
+	assert.Equal(t, `"prefix":"- "`+"\n", out.String())
+}
+
+func TestSplitCommand(t *testing.T) {
+	args, err := SplitCommand(`whisper-cli --model "C:\Models\ggml base.bin"  -l ru ''`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"whisper-cli", "--model", `C:\Models\ggml base.bin`, "-l", "ru", ""}, args)
+
+	_, err = SplitCommand(`listen "ru`)
+	assert.Error(t, err)
+	_, err = SplitCommand("   ")
+	assert.Error(t, err)
+}
+
+func TestCapture(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not executable on Windows")
+	}
+	text, err := Capture(context.Background(), `sh -c "echo '  Позвонить маме завтра #дом'"`)
+	require.NoError(t, err)
+	assert.Equal(t, "Позвонить маме завтра #дом", text)
+
+	_, err = Capture(context.Background(), `sh -c "echo 'no microphone' >&2; exit 2"`)
+	assert.ErrorContains(t, err, "no microphone")
+	_, err = Capture(context.Background(), "true")
+	assert.ErrorIs(t, err, ErrEmptyCapture)
+}
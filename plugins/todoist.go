@@ -0,0 +1,102 @@
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"taskmanager/task"
+)
+
+// todoistAPI - адрес REST API Todoist
+const todoistAPI = "https://api.todoist.com/rest/v2"
+
+// Todoist отправляет невыполненные задачи в Todoist
+type Todoist struct {
+	BaseURL   string
+	HTTP      *http.Client
+	token     string
+	projectID string
+}
+
+// NewTodoist создает интеграцию с Todoist
+func NewTodoist() *Todoist {
+	return &Todoist{BaseURL: todoistAPI, HTTP: &http.Client{Timeout: time.Minute}}
+}
+
+func (*Todoist) Name() string { return "Todoist" }
+
+func (*Todoist) Options() []Option {
+	return []Option{
+		{Key: "token", Title: "Токен API", Secret: true},
+		{Key: "project_id", Title: "ID проекта (пусто - «Входящие»)"},
+	}
+}
+
+func (p *Todoist) Configure(options map[string]string) error {
+	p.token = strings.TrimSpace(options["token"])
+	p.projectID = strings.TrimSpace(options["project_id"])
+	if p.token == "" {
+		return errors.New("todoist: api token is required")
+	}
+	return nil
+}
+
+// todoistTask - задача в API Todoist. Приоритет 4 - самый высокий
+type todoistTask struct {
+	Content     string   `json:"content"`
+	Description string   `json:"description,omitempty"`
+	Priority    int      `json:"priority"`
+	DueDate     string   `json:"due_date,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	ProjectID   string   `json:"project_id,omitempty"`
+}
+
+// Run создает в Todoist невыполненные задачи не из архива. UUID задачи передается
+// как X-Request-Id, чтобы повтор после сбоя сети не создал задачу дважды
+func (p *Todoist) Run(ctx context.Context, tasks []*task.Task, _ io.Writer) error {
+	for _, t := range tasks {
+		if t.Completed || t.Archived {
+			continue
+		}
+		var due string
+		if !t.DueDate.IsZero() {
+			due = t.DueDate.Format("2006-01-02")
+		}
+		body, err := json.Marshal(todoistTask{
+			Content:     t.Title,
+			Description: t.Description,
+			Priority:    t.Priority + 1,
+			DueDate:     due,
+			Labels:      t.Tags,
+			ProjectID:   p.projectID,
+		})
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.BaseURL+"/tasks", bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+p.token)
+		req.Header.Set("X-Request-Id", t.UUID)
+
+		resp, err := p.HTTP.Do(req)
+		if err != nil {
+			return err
+		}
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("todoist: %q: %s: %s", t.Title, resp.Status, strings.TrimSpace(string(message)))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package reminders
+
+import (
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+
+	"taskmanager/task"
+)
+
+// Alerts - как напоминать о задачах: задачи с высоким приоритетом можно выделить
+// звуком, а об остальных напоминать только в окне приложения. Вид напоминания и звук,
+// заданные в самой задаче, важнее этих настроек
+type Alerts struct {
+	High  task.Urgency // для задач с высоким приоритетом
+	Other task.Urgency // для остальных задач
+	Sound string       // файл звука; пусто - системный звук
+}
+
+// DefaultAlerts - напоминания, пока пользователь их не настроил: о важных задачах
+// со звуком, об остальных только в окне приложения
+var DefaultAlerts = Alerts{High: task.UrgencyLoud, Other: task.UrgencySilent}
+
+// For возвращает, как напомнить о задаче t, и файл звука для напоминания со звуком
+func (a Alerts) For(t *task.Task) (task.Urgency, string) {
+	urgency := t.Urgency
+	if urgency == task.UrgencyDefault {
+		urgency = a.Other
+		if t.Priority >= 3 {
+			urgency = a.High
+		}
+	}
+	if urgency == task.UrgencyDefault {
+		urgency = task.UrgencyNormal
+	}
+	sound := t.Sound
+	if sound == "" {
+		sound = a.Sound
+	}
+	return urgency, sound
+}
+
+// soundCommand возвращает команду, которая проигрывает файл звука в системе goos.
+// Без файла играет системный звук уведомления
+func soundCommand(goos, file string) (string, []string) {
+	switch goos {
+	case "windows":
+		if file == "" {
+			return "powershell", []string{"-NoProfile", "-Command", "[System.Media.SystemSounds]::Exclamation.Play(); Start-Sleep -Seconds 1"}
+		}
+		return "powershell", []string{"-NoProfile", "-Command", fmt.Sprintf("(New-Object Media.SoundPlayer '%s').PlaySync()", strings.ReplaceAll(file, "'", "''"))}
+	case "darwin":
+		if file == "" {
+			file = "/System/Library/Sounds/Glass.aiff"
+		}
+		return "afplay", []string{file}
+	}
+	if file == "" {
+		return "canberra-gtk-play", []string{"--id", "message-new-instant"}
+	}
+	return "paplay", []string{file}
+}
+
+// PlaySound проигрывает звук напоминания в фоне. Если проиграть не удалось, напоминание
+// все равно показывается, поэтому ошибка только записывается в журнал
+func PlaySound(goos, file string) {
+	name, args := soundCommand(goos, file)
+	cmd := exec.Command(name, args...)
+	if err := cmd.Start(); err != nil {
+		slog.Warn("failed to play reminder sound", "command", name, "err", err)
+		return
+	}
+	go func() {
+		if err := cmd.Wait(); err != nil {
+			slog.Warn("reminder sound command failed", "command", name, "err", err)
+		}
+	}()
+}
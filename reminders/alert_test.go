@@ -0,0 +1,46 @@
+package reminders
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+func TestAlertsFor(t *testing.T) {
+	alerts := Alerts{High: task.UrgencyLoud, Other: task.UrgencySilent, Sound: "/sounds/bell.wav"}
+
+	urgency, sound := alerts.For(&task.Task{Priority: 3})
+	assert.Equal(t, task.UrgencyLoud, urgency)
+	assert.Equal(t, "/sounds/bell.wav", sound)
+
+	urgency, _ = alerts.For(&task.Task{Priority: 2})
+	assert.Equal(t, task.UrgencySilent, urgency)
+
+	// Настройки задачи важнее общих
+	urgency, sound = alerts.For(&task.Task{Priority: 1, Urgency: task.UrgencyLoud, Sound: "/sounds/gong.wav"})
+	assert.Equal(t, task.UrgencyLoud, urgency)
+	assert.Equal(t, "/sounds/gong.wav", sound)
+
+	// Без настроек - обычное уведомление
+	urgency, _ = Alerts{}.For(&task.Task{Priority: 3})
+	assert.Equal(t, task.UrgencyNormal, urgency)
+}
+
+func TestSoundCommand(t *testing.T) {
+	name, args := soundCommand("linux", "/sounds/bell.wav")
+	assert.Equal(t, "paplay", name)
+	assert.Equal(t, []string{"/sounds/bell.wav"}, args)
+
+	name, _ = soundCommand("linux", "")
+	assert.Equal(t, "canberra-gtk-play", name)
+
+	name, args = soundCommand("darwin", "")
+	assert.Equal(t, "afplay", name)
+	assert.Equal(t, []string{"/System/Library/Sounds/Glass.aiff"}, args)
+
+	name, args = soundCommand("windows", `C:\bell.wav`)
+	assert.Equal(t, "powershell", name)
+	assert.Contains(t, args[len(args)-1], `C:\bell.wav`)
+}
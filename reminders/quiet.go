@@ -0,0 +1,41 @@
+package reminders
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// QuietHours - расписание «не беспокоить»: в это время напоминания не показываются,
+// а копятся и приходят, когда тихое время закончится
+type QuietHours struct {
+	// From и To - начало и конец тихого времени в минутах от полуночи. Если From позже To,
+	// тихое время переходит через полночь; если они равны, тихого времени по часам нет
+	From, To int
+	// Weekends - не беспокоить в субботу и воскресенье
+	Weekends bool
+}
+
+// Active сообщает, приходится ли момент now на тихое время
+func (q QuietHours) Active(now time.Time) bool {
+	if q.Weekends && (now.Weekday() == time.Saturday || now.Weekday() == time.Sunday) {
+		return true
+	}
+	minute := now.Hour()*60 + now.Minute()
+	switch {
+	case q.From == q.To:
+		return false
+	case q.From < q.To:
+		return minute >= q.From && minute < q.To
+	}
+	return minute >= q.From || minute < q.To
+}
+
+// ParseClock разбирает время суток «22:00» в минуты от полуночи
+func ParseClock(text string) (int, error) {
+	at, err := time.Parse("15:04", strings.TrimSpace(text))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, use HH:MM", text)
+	}
+	return at.Hour()*60 + at.Minute(), nil
+}
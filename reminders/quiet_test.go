@@ -0,0 +1,52 @@
+package reminders
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+func TestQuietHoursActive(t *testing.T) {
+	// 16.10.2026 - пятница
+	at := func(day, hour, minute int) time.Time { return time.Date(2026, 10, day, hour, minute, 0, 0, time.UTC) }
+	night := QuietHours{From: 22 * 60, To: 8 * 60}
+	assert.True(t, night.Active(at(16, 23, 0)))
+	assert.True(t, night.Active(at(16, 7, 59)))
+	assert.False(t, night.Active(at(16, 8, 0)))
+	assert.False(t, night.Active(at(16, 12, 0)))
+	assert.False(t, night.Active(at(17, 12, 0)))
+
+	lunch := QuietHours{From: 13 * 60, To: 14 * 60, Weekends: true}
+	assert.True(t, lunch.Active(at(16, 13, 30)))
+	assert.False(t, lunch.Active(at(16, 14, 0)))
+	assert.True(t, lunch.Active(at(17, 10, 0)))
+
+	assert.False(t, QuietHours{}.Active(at(17, 10, 0)))
+}
+
+func TestSchedulerQuietHours(t *testing.T) {
+	now := time.Date(2026, 10, 16, 23, 0, 0, 0, time.UTC)
+	call := &task.Task{UUID: "u1", Title: "Позвонить", RemindAt: now}
+
+	var notified int
+	s := NewScheduler(func(Reminder) { notified++ })
+	s.Quiet = QuietHours{From: 22 * 60, To: 8 * 60}
+	assert.Empty(t, s.Check([]*task.Task{call}, now))
+	assert.Zero(t, notified)
+
+	// Напоминание приходит, когда тихое время кончилось
+	assert.Len(t, s.Check([]*task.Task{call}, time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)), 1)
+	assert.Equal(t, 1, notified)
+}
+
+func TestParseClock(t *testing.T) {
+	minutes, err := ParseClock(" 22:30 ")
+	assert.NoError(t, err)
+	assert.Equal(t, 22*60+30, minutes)
+
+	_, err = ParseClock("25:00")
+	assert.Error(t, err)
+}
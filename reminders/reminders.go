@@ -0,0 +1,207 @@
+// Package reminders показывает напоминания о задачах в назначенное время
+// и позволяет их откладывать
+package reminders
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"taskmanager/task"
+)
+
+// Snooze - вариант откладывания напоминания
+type Snooze string
+
+const (
+	Snooze10Min    Snooze = "10m"
+	SnoozeHour     Snooze = "1h"
+	SnoozeTomorrow Snooze = "tomorrow"
+)
+
+// Snoozes - варианты откладывания в порядке показа
+var Snoozes = []Snooze{Snooze10Min, SnoozeHour, SnoozeTomorrow}
+
+// morningHour - во сколько напомнить, если напоминание отложено до завтра
+const morningHour = 9
+
+// DueHour - час, к которому относится срок задачи: у срока есть только дата
+const DueHour = 9
+
+// Title возвращает название варианта для интерфейса
+func (s Snooze) Title() string {
+	switch s {
+	case Snooze10Min:
+		return "На 10 минут"
+	case SnoozeHour:
+		return "На час"
+	case SnoozeTomorrow:
+		return "До завтра"
+	}
+	return string(s)
+}
+
+// Until возвращает, до какого времени отложить напоминание, отложенное в момент now
+func (s Snooze) Until(now time.Time) time.Time {
+	switch s {
+	case Snooze10Min:
+		return now.Add(10 * time.Minute)
+	case SnoozeHour:
+		return now.Add(time.Hour)
+	}
+	tomorrow := now.AddDate(0, 0, 1)
+	return time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), morningHour, 0, 0, 0, now.Location())
+}
+
+// DueTime возвращает момент срока задачи: дату срока в DueHour по местному времени
+func DueTime(t *task.Task) time.Time {
+	d := t.DueDate
+	return time.Date(d.Year(), d.Month(), d.Day(), DueHour, 0, 0, 0, time.Local)
+}
+
+// Reminder - одно напоминание о задаче
+type Reminder struct {
+	Task *task.Task
+	// Key отличает напоминания задачи друг от друга: по нему запоминается, что напоминание показано
+	Key string
+	At  time.Time
+	// Offset - за сколько до срока напомнить; имеет смысл, если Relative
+	Offset   time.Duration
+	Relative bool
+	Snoozed  bool
+}
+
+// Describe описывает, к чему относится напоминание
+func (r Reminder) Describe() string {
+	switch {
+	case r.Snoozed:
+		return "отложено"
+	case r.Relative:
+		return DescribeOffset(r.Offset)
+	}
+	return "в назначенное время"
+}
+
+// DescribeOffset описывает напоминание за offset до срока
+func DescribeOffset(offset time.Duration) string {
+	switch {
+	case offset == 0:
+		return "в срок"
+	case offset%(7*24*time.Hour) == 0:
+		return fmt.Sprintf("за %d нед. до срока", offset/(7*24*time.Hour))
+	case offset%(24*time.Hour) == 0:
+		return fmt.Sprintf("за %d дн. до срока", offset/(24*time.Hour))
+	case offset%time.Hour == 0:
+		return fmt.Sprintf("за %d ч до срока", offset/time.Hour)
+	}
+	return fmt.Sprintf("за %d мин до срока", offset/time.Minute)
+}
+
+// For возвращает напоминания задачи: в назначенное время, относительно срока и отложенное.
+// У выполненной задачи и задачи в архиве напоминаний нет, у задачи без срока - нет напоминаний
+// относительно срока
+func For(t *task.Task) []Reminder {
+	if t.Completed || t.Archived {
+		return nil
+	}
+	var list []Reminder
+	if !t.RemindAt.IsZero() {
+		list = append(list, Reminder{Task: t, Key: t.UUID, At: t.RemindAt})
+	}
+	if !t.DueDate.IsZero() {
+		due := DueTime(t)
+		for _, offset := range t.ReminderOffsets {
+			list = append(list, Reminder{Task: t, Key: fmt.Sprintf("%s/%s", t.UUID, offset), At: due.Add(-offset), Offset: offset, Relative: true})
+		}
+	}
+	if !t.SnoozedUntil.IsZero() {
+		list = append(list, Reminder{Task: t, Key: t.UUID + "/snooze", At: t.SnoozedUntil, Snoozed: true})
+	}
+	return list
+}
+
+// Scheduler находит напоминания, время которых подошло. Каждое напоминание показывается
+// один раз: его время запоминается по ключу. Если у задачи подошло сразу несколько напоминаний,
+// например после перезапуска, о задаче сообщается один раз. Не потокобезопасен:
+// вызывается в потоке менеджера задач
+type Scheduler struct {
+	// Fired - время напоминания, о котором уже сообщили; ключ - Reminder.Key
+	Fired map[string]time.Time
+	// Notify сообщает о задаче
+	Notify func(r Reminder)
+	// Quiet - тихое время, когда напоминания откладываются до его конца
+	Quiet QuietHours
+}
+
+// NewScheduler создает планировщик напоминаний
+func NewScheduler(notify func(r Reminder)) *Scheduler {
+	return &Scheduler{Fired: make(map[string]time.Time), Notify: notify}
+}
+
+// Due возвращает напоминания, время которых подошло, ничего не отмечая
+func (s *Scheduler) Due(tasks []*task.Task, now time.Time) []Reminder {
+	var due []Reminder
+	for _, t := range tasks {
+		for _, r := range For(t) {
+			if r.At.After(now) {
+				continue
+			}
+			if fired, ok := s.Fired[r.Key]; ok && fired.Equal(r.At) {
+				continue
+			}
+			due = append(due, r)
+		}
+	}
+	return due
+}
+
+// Check сообщает о задачах, напоминания которых подошли, и возвращает эти напоминания.
+// В тихое время ни о чем не сообщает: подошедшие напоминания придут после него
+func (s *Scheduler) Check(tasks []*task.Task, now time.Time) []Reminder {
+	if s.Quiet.Active(now) {
+		return nil
+	}
+	due := s.Due(tasks, now)
+	latest := make(map[*task.Task]Reminder)
+	for _, r := range due {
+		s.Fired[r.Key] = r.At
+		if prev, ok := latest[r.Task]; !ok || r.At.After(prev.At) {
+			latest[r.Task] = r
+		}
+	}
+	if s.Notify != nil {
+		for _, r := range due {
+			if latest[r.Task].Key == r.Key {
+				s.Notify(r)
+			}
+		}
+	}
+
+	// Отметки о напоминаниях, которых больше нет, не нужны
+	active := make(map[string]bool)
+	for _, t := range tasks {
+		for _, r := range For(t) {
+			active[r.Key] = true
+		}
+	}
+	for key := range s.Fired {
+		if !active[key] {
+			delete(s.Fired, key)
+		}
+	}
+	return due
+}
+
+// Upcoming возвращает напоминания, которые еще не наступили, по времени
+func Upcoming(tasks []*task.Task, now time.Time) []Reminder {
+	var upcoming []Reminder
+	for _, t := range tasks {
+		for _, r := range For(t) {
+			if r.At.After(now) {
+				upcoming = append(upcoming, r)
+			}
+		}
+	}
+	slices.SortStableFunc(upcoming, func(a, b Reminder) int { return a.At.Compare(b.At) })
+	return upcoming
+}
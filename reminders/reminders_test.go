@@ -0,0 +1,87 @@
+package reminders
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+func TestSchedulerCheck(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	call := &task.Task{UUID: "u1", Title: "Позвонить", RemindAt: now.Add(-time.Minute)}
+	later := &task.Task{UUID: "u2", Title: "Отчет", RemindAt: now.Add(time.Hour)}
+	done := &task.Task{UUID: "u3", Title: "Готово", RemindAt: now.Add(-time.Hour), Completed: true}
+	tasks := []*task.Task{call, later, done}
+
+	var notified []string
+	s := NewScheduler(func(r Reminder) { notified = append(notified, r.Task.Title) })
+	assert.Len(t, s.Check(tasks, now), 1)
+	assert.Equal(t, []string{"Позвонить"}, notified)
+
+	// Повторно о той же задаче не напоминается
+	assert.Empty(t, s.Check(tasks, now.Add(time.Minute)))
+
+	// Отложенное напоминание срабатывает отдельно, в новое время
+	call.SnoozedUntil = Snooze10Min.Until(now)
+	assert.Empty(t, s.Check(tasks, now.Add(5*time.Minute)))
+	due := s.Check(tasks, now.Add(10*time.Minute))
+	if assert.Len(t, due, 1) {
+		assert.True(t, due[0].Snoozed)
+	}
+
+	upcoming := Upcoming(tasks, now)
+	if assert.Len(t, upcoming, 2) {
+		assert.Equal(t, call, upcoming[0].Task)
+		assert.Equal(t, later, upcoming[1].Task)
+	}
+}
+
+func TestReminderOffsets(t *testing.T) {
+	day, week := 24*time.Hour, 7*24*time.Hour
+	report := &task.Task{
+		UUID: "u1", Title: "Отчет", DueDate: time.Date(2026, 10, 30, 0, 0, 0, 0, time.UTC),
+		ReminderOffsets: []time.Duration{week, day, 0},
+	}
+	due := time.Date(2026, 10, 30, DueHour, 0, 0, 0, time.Local)
+
+	list := For(report)
+	if assert.Len(t, list, 3) {
+		assert.Equal(t, due.Add(-week), list[0].At)
+		assert.Equal(t, "за 1 нед. до срока", list[0].Describe())
+		assert.Equal(t, "за 1 дн. до срока", list[1].Describe())
+		assert.Equal(t, "в срок", list[2].Describe())
+	}
+
+	// Каждое напоминание срабатывает отдельно
+	var notified int
+	s := NewScheduler(func(Reminder) { notified++ })
+	assert.Len(t, s.Check([]*task.Task{report}, due.Add(-week)), 1)
+	assert.Empty(t, s.Check([]*task.Task{report}, due.Add(-2*day)))
+	assert.Len(t, s.Check([]*task.Task{report}, due.Add(-day)), 1)
+	assert.Len(t, s.Fired, 2)
+
+	// Пропущенные напоминания приходят одним уведомлением
+	notified = 0
+	s = NewScheduler(func(Reminder) { notified++ })
+	assert.Len(t, s.Check([]*task.Task{report}, due), 3)
+	assert.Equal(t, 1, notified)
+
+	// Удаленное напоминание забывается
+	report.ReminderOffsets = []time.Duration{0}
+	s.Check([]*task.Task{report}, due)
+	assert.Len(t, s.Fired, 1)
+
+	// У задачи без срока напоминаний относительно срока нет
+	report.DueDate = time.Time{}
+	assert.Empty(t, For(report))
+}
+
+func TestSnoozeUntil(t *testing.T) {
+	now := time.Date(2026, 10, 16, 22, 30, 0, 0, time.UTC)
+	assert.Equal(t, now.Add(10*time.Minute), Snooze10Min.Until(now))
+	assert.Equal(t, now.Add(time.Hour), SnoozeHour.Until(now))
+	assert.Equal(t, time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC), SnoozeTomorrow.Until(now))
+}
@@ -0,0 +1,129 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"taskmanager/task"
+)
+
+// Сравнение оценок трудоемкости с временем, учтенным таймером, - чтобы точнее планировать.
+// Учтенное время хранится в задаче без дат, поэтому в период попадают задачи, выполненные
+// в нем, а невыполненные - измененные в нем
+
+// GroupBy - по чему собирать строки сравнения
+type GroupBy string
+
+const (
+	GroupByTask GroupBy = "task" // строка на задачу
+	GroupByTag  GroupBy = "tag"  // строка на метку; проект - тоже метка
+)
+
+// untaggedName - строка задач без меток при группировке по меткам
+const untaggedName = "без метки"
+
+// EstimateRow - оценка и учтенное время задачи или группы задач, в минутах
+type EstimateRow struct {
+	Name      string
+	Tasks     int
+	Estimated int
+	Tracked   int
+}
+
+// Diff возвращает, на сколько минут учтенное время больше оценки
+func (r EstimateRow) Diff() int {
+	return r.Tracked - r.Estimated
+}
+
+// Ratio возвращает отношение учтенного времени к оценке; без оценки - 0
+func (r EstimateRow) Ratio() float64 {
+	if r.Estimated == 0 {
+		return 0
+	}
+	return float64(r.Tracked) / float64(r.Estimated)
+}
+
+func (r *EstimateRow) add(t *task.Task) {
+	r.Tasks++
+	r.Estimated += t.EstimatedMinutes
+	r.Tracked += int(t.TimeSpent.Round(time.Minute) / time.Minute)
+}
+
+// Estimates сравнивает оценки и учтенное время задач с оценкой или учтенным временем
+// за период [from, to); нулевая граница не ограничивает период. Строки идут от самого
+// большого расхождения к меньшему, total - итог по всем задачам периода
+func Estimates(tasks []*task.Task, from, to time.Time, groupBy GroupBy) (rows []EstimateRow, total EstimateRow) {
+	total.Name = "Итого"
+	index := make(map[string]int)
+	row := func(name string) *EstimateRow {
+		i, ok := index[name]
+		if !ok {
+			i = len(rows)
+			index[name] = i
+			rows = append(rows, EstimateRow{Name: name})
+		}
+		return &rows[i]
+	}
+	for _, t := range tasks {
+		if t.EstimatedMinutes == 0 && t.TimeSpent < time.Minute {
+			continue
+		}
+		at := t.UpdatedAt
+		if t.Completed {
+			at = t.DoneAt()
+		}
+		if !from.IsZero() && at.Before(from) || !to.IsZero() && !at.Before(to) {
+			continue
+		}
+		total.add(t)
+		switch {
+		case groupBy == GroupByTask:
+			row(fmt.Sprintf("#%d %s", t.ID, t.Title)).add(t)
+		case len(t.Tags) == 0:
+			row(untaggedName).add(t)
+		default:
+			for _, tag := range t.Tags {
+				row("#" + tag).add(t)
+			}
+		}
+	}
+	slices.SortStableFunc(rows, func(a, b EstimateRow) int {
+		if d := abs(b.Diff()) - abs(a.Diff()); d != 0 {
+			return d
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return rows, total
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// WriteEstimatesCSV записывает сравнение в CSV с итоговой строкой в конце
+func WriteEstimatesCSV(w io.Writer, rows []EstimateRow, total EstimateRow) error {
+	// Метка порядка байт: по ней Excel узнает кодировку файла
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return err
+	}
+	out := csv.NewWriter(w)
+	out.Write([]string{"Name", "Tasks", "Estimated (min)", "Tracked (min)", "Difference (min)", "Tracked / Estimated"})
+	for _, r := range slices.Concat(rows, []EstimateRow{total}) {
+		ratio := ""
+		if r.Estimated > 0 {
+			ratio = strconv.FormatFloat(r.Ratio(), 'f', 2, 64)
+		}
+		out.Write([]string{r.Name, strconv.Itoa(r.Tasks), strconv.Itoa(r.Estimated), strconv.Itoa(r.Tracked),
+			strconv.Itoa(r.Diff()), ratio})
+	}
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,61 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"taskmanager/task"
+)
+
+func estimateTasks(now time.Time) []*task.Task {
+	return []*task.Task{
+		{ID: 1, Title: "Отчет", Tags: []string{"работа"}, EstimatedMinutes: 60, TimeSpent: 90 * time.Minute,
+			Completed: true, CompletedAt: now.AddDate(0, 0, -2)},
+		{ID: 2, Title: "Созвон", Tags: []string{"работа", "клиент"}, EstimatedMinutes: 30, TimeSpent: 20 * time.Minute,
+			UpdatedAt: now.AddDate(0, 0, -1)},
+		{ID: 3, Title: "Уборка", EstimatedMinutes: 45, UpdatedAt: now},
+		// Без оценки и времени, и выполненная до периода
+		{ID: 4, Title: "Молоко", UpdatedAt: now},
+		{ID: 5, Title: "Старое", EstimatedMinutes: 10, TimeSpent: time.Hour, Completed: true, CompletedAt: now.AddDate(0, -2, 0)},
+	}
+}
+
+func TestEstimates(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	from := now.AddDate(0, 0, -30)
+
+	rows, total := Estimates(estimateTasks(now), from, time.Time{}, GroupByTask)
+	assert.Equal(t, []EstimateRow{
+		{Name: "#3 Уборка", Tasks: 1, Estimated: 45},
+		{Name: "#1 Отчет", Tasks: 1, Estimated: 60, Tracked: 90},
+		{Name: "#2 Созвон", Tasks: 1, Estimated: 30, Tracked: 20},
+	}, rows)
+	assert.Equal(t, EstimateRow{Name: "Итого", Tasks: 3, Estimated: 135, Tracked: 110}, total)
+	assert.Equal(t, 1.5, rows[1].Ratio())
+	assert.Equal(t, -10, rows[2].Diff())
+
+	rows, _ = Estimates(estimateTasks(now), from, now, GroupByTag)
+	assert.Equal(t, []EstimateRow{
+		{Name: "#работа", Tasks: 2, Estimated: 90, Tracked: 110},
+		{Name: "#клиент", Tasks: 1, Estimated: 30, Tracked: 20},
+	}, rows)
+
+	rows, total = Estimates(estimateTasks(now), time.Time{}, time.Time{}, GroupByTag)
+	require.Len(t, rows, 3)
+	assert.Equal(t, EstimateRow{Name: untaggedName, Tasks: 2, Estimated: 55, Tracked: 60}, rows[2])
+	assert.Equal(t, 4, total.Tasks)
+}
+
+func TestWriteEstimatesCSV(t *testing.T) {
+	rows := []EstimateRow{{Name: "#работа", Tasks: 2, Estimated: 90, Tracked: 110}, {Name: "#дом", Tasks: 1, Tracked: 15}}
+	var out bytes.Buffer
+	require.NoError(t, WriteEstimatesCSV(&out, rows, EstimateRow{Name: "Итого", Tasks: 3, Estimated: 90, Tracked: 125}))
+	assert.Equal(t, "\ufeffName,Tasks,Estimated (min),Tracked (min),Difference (min),Tracked / Estimated\n"+
+		"#работа,2,90,110,20,1.22\n"+
+		"#дом,1,0,15,15,\n"+
+		"Итого,3,90,125,35,1.39\n", out.String())
+}
@@ -0,0 +1,129 @@
+// Package report составляет отчет о состоянии задач - отдельную страницу HTML со стилями,
+// которую можно отправить письмом или открыть в браузере тем, кто не пользуется приложением,
+// и сравнение оценок задач с учтенным временем
+package report
+
+import (
+	"embed"
+	"html/template"
+	"io"
+	"os"
+	"time"
+
+	"taskmanager/task"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+var pageTemplate = template.Must(template.New("report.html").
+	Funcs(template.FuncMap{"priority": task.PriorityText}).
+	ParseFS(templateFS, "templates/report.html"))
+
+// Section - раздел отчета
+type Section string
+
+const (
+	SectionOverdue   Section = "overdue"   // невыполненные задачи с прошедшим сроком
+	SectionToday     Section = "today"     // невыполненные задачи на сегодня
+	SectionUpcoming  Section = "upcoming"  // невыполненные задачи на будущие дни
+	SectionCompleted Section = "completed" // выполненные задачи, раздел свернут
+)
+
+// Sections - разделы в порядке показа
+var Sections = []Section{SectionOverdue, SectionToday, SectionUpcoming, SectionCompleted}
+
+// Title возвращает название раздела
+func (s Section) Title() string {
+	return map[Section]string{
+		SectionOverdue:   "Просрочено",
+		SectionToday:     "Сегодня",
+		SectionUpcoming:  "Предстоит",
+		SectionCompleted: "Выполнено",
+	}[s]
+}
+
+// Group - раздел отчета с задачами
+type Group struct {
+	Section Section
+	Title   string
+	Tasks   []*task.Task
+}
+
+// Collapsed сообщает, что раздел показывается свернутым
+func (g Group) Collapsed() bool {
+	return g.Section == SectionCompleted
+}
+
+// Report - отчет о задачах на день Date
+type Report struct {
+	Date   time.Time
+	Groups []Group
+}
+
+// section определяет раздел задачи; today - день отчета в формате срока
+func section(t *task.Task, today string) Section {
+	switch {
+	case t.Completed:
+		return SectionCompleted
+	case t.DueDate.IsZero() || t.DueDay() > today:
+		return SectionUpcoming
+	case t.DueDay() == today:
+		return SectionToday
+	}
+	return SectionOverdue
+}
+
+// Build составляет отчет на день now. Задачи в архиве не попадают в отчет; в разделе
+// сначала задачи с более высоким приоритетом, при равном - с более ранним сроком
+func Build(tasks []*task.Task, now time.Time) Report {
+	today := now.Format(task.DueDateLayout)
+	tasks = task.SortTasks(tasks, task.SortByDueDate, false)
+	tasks = task.SortTasks(tasks, task.SortByPriority, false)
+
+	r := Report{Date: now}
+	for _, s := range Sections {
+		group := Group{Section: s, Title: s.Title()}
+		for _, t := range tasks {
+			if !t.Archived && section(t, today) == s {
+				group.Tasks = append(group.Tasks, t)
+			}
+		}
+		r.Groups = append(r.Groups, group)
+	}
+	return r
+}
+
+// Title возвращает заголовок отчета
+func (r Report) Title() string {
+	return "Задачи на " + r.Date.Format("02.01.2006")
+}
+
+// Open возвращает число невыполненных задач в отчете
+func (r Report) Open() int {
+	n := 0
+	for _, group := range r.Groups {
+		if group.Section != SectionCompleted {
+			n += len(group.Tasks)
+		}
+	}
+	return n
+}
+
+// WriteHTML записывает отчет страницей HTML; стили встроены в страницу
+func (r Report) WriteHTML(w io.Writer) error {
+	return pageTemplate.Execute(w, r)
+}
+
+// ExportToHTML записывает отчет о задачах на день now в файл filename
+func ExportToHTML(filename string, tasks []*task.Task, now time.Time) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := Build(tasks, now).WriteHTML(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
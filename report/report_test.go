@@ -0,0 +1,73 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"taskmanager/task"
+)
+
+func testTasks(now time.Time) []*task.Task {
+	day := func(offset int) time.Time {
+		d := now.AddDate(0, 0, offset)
+		return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return []*task.Task{
+		{ID: 1, Title: "Просроченный отчет", Priority: 2, DueDate: day(-2), Assignee: "Маша"},
+		{ID: 2, Title: "Купить молоко", Priority: 1, DueDate: day(0), Tags: []string{"дом"}},
+		{ID: 3, Title: "Позвонить <боссу>", Priority: 3, DueDate: day(0), Description: "Про отпуск"},
+		{ID: 4, Title: "Записаться к врачу", Priority: 2, DueDate: day(3)},
+		{ID: 5, Title: "Оплатить счет", Priority: 2, DueDate: day(-1), Completed: true},
+		{ID: 6, Title: "Старое в архиве", Priority: 2, DueDate: day(-5), Archived: true},
+	}
+}
+
+func ids(group Group) []int {
+	var ids []int
+	for _, t := range group.Tasks {
+		ids = append(ids, t.ID)
+	}
+	return ids
+}
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	r := Build(testTasks(now), now)
+	require.Len(t, r.Groups, 4)
+	assert.Equal(t, []int{1}, ids(r.Groups[0]))
+	// Сначала высокий приоритет
+	assert.Equal(t, []int{3, 2}, ids(r.Groups[1]))
+	assert.Equal(t, []int{4}, ids(r.Groups[2]))
+	assert.Equal(t, []int{5}, ids(r.Groups[3]))
+	assert.True(t, r.Groups[3].Collapsed())
+	assert.Equal(t, 4, r.Open())
+	assert.Equal(t, "Задачи на 16.10.2026", r.Title())
+}
+
+func TestWriteHTML(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	var out bytes.Buffer
+	require.NoError(t, Build(testTasks(now), now).WriteHTML(&out))
+	page := out.String()
+	assert.Contains(t, page, "<title>Задачи на 16.10.2026</title>")
+	assert.Contains(t, page, "Позвонить &lt;боссу&gt;")
+	assert.Contains(t, page, `<span class="badge p3">высокий</span>`)
+	assert.Contains(t, page, "<details><summary>Выполнено (1)</summary>")
+	assert.Contains(t, page, `<span class="tag">#дом</span>`)
+	assert.NotContains(t, page, "Старое в архиве")
+}
+
+func TestExportToHTML(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	filename := filepath.Join(t.TempDir(), "report.html")
+	require.NoError(t, ExportToHTML(filename, testTasks(now), now))
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Открытых задач: 4")
+}
@@ -0,0 +1,168 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"taskmanager/task"
+)
+
+// maxPasses - сколько раз подряд применять правила к задаче: действие одного правила
+// может выполнить условие другого, но правила не должны зациклиться
+const maxPasses = 5
+
+// Effect - действие правила над задачей
+type Effect struct {
+	Rule Rule
+	Task *task.Task
+}
+
+// Describe описывает действие для предпросмотра и журнала
+func (e Effect) Describe() string {
+	switch e.Rule.Action {
+	case ActionSetPriority:
+		return fmt.Sprintf("«%s»: приоритет %s → %s", e.Task.Title,
+			task.PriorityText(e.Task.Priority), task.PriorityText(e.Rule.Priority))
+	case ActionNotify:
+		return fmt.Sprintf("«%s»: уведомление (%s)", e.Task.Title, e.Rule.Describe())
+	case ActionArchive:
+		return fmt.Sprintf("«%s»: в архив", e.Task.Title)
+	}
+	return e.Task.Title
+}
+
+// Engine применяет правила к задачам. Уведомление о задаче показывается один раз,
+// пока задача не изменится, а в архив правило убирает задачу только однажды, чтобы
+// задачу можно было вернуть из архива. Не потокобезопасен: вызывается в потоке менеджера задач
+type Engine struct {
+	Rules []Rule
+	// Applied - версия задачи (время изменения), для которой правило уведомило или убрало
+	// задачу в архив; ключ - правило и UUID задачи
+	Applied map[string]time.Time
+	// Notify показывает уведомление
+	Notify func(title, message string)
+
+	running bool
+}
+
+// NewEngine создает движок с правилами list
+func NewEngine(list []Rule, notify func(title, message string)) *Engine {
+	return &Engine{Rules: list, Applied: make(map[string]time.Time), Notify: notify}
+}
+
+// Plan возвращает действия, которые правила выполнили бы сейчас, ничего не меняя (пробный запуск)
+func (e *Engine) Plan(tasks []*task.Task, now time.Time) []Effect {
+	var effects []Effect
+	for _, t := range tasks {
+		effects = append(effects, e.planTask(t, now)...)
+	}
+	return effects
+}
+
+// planTask возвращает действия правил над одной задачей. Действия, которые ничего
+// не изменят (приоритет уже такой, уведомление уже показано), пропускаются
+func (e *Engine) planTask(t *task.Task, now time.Time) []Effect {
+	var effects []Effect
+	for _, rule := range e.Rules {
+		if !rule.Enabled || !rule.Matches(t, now) {
+			continue
+		}
+		switch rule.Action {
+		case ActionSetPriority:
+			if t.Priority == rule.Priority {
+				continue
+			}
+		case ActionNotify:
+			if applied, ok := e.Applied[appliedKey(rule, t)]; ok && applied.Equal(t.UpdatedAt) {
+				continue
+			}
+		case ActionArchive:
+			if _, ok := e.Applied[appliedKey(rule, t)]; ok {
+				continue
+			}
+		}
+		effects = append(effects, Effect{Rule: rule, Task: t})
+	}
+	return effects
+}
+
+// Run применяет правила ко всем задачам и возвращает выполненные действия.
+// Отметки о срабатывании для удаленных задач забываются
+func (e *Engine) Run(tm *task.TaskManager, now time.Time) []Effect {
+	present := make(map[string]bool)
+	var applied []Effect
+	for _, t := range append([]*task.Task(nil), tm.Tasks()...) {
+		present[t.UUID] = true
+		applied = append(applied, e.runTask(tm, t.ID, now)...)
+	}
+	for key := range e.Applied {
+		if _, uuid, _ := strings.Cut(key, "/"); !present[uuid] {
+			delete(e.Applied, key)
+		}
+	}
+	return applied
+}
+
+// Attach применяет правила к задаче при каждом ее добавлении или изменении.
+// Возвращает функцию отписки
+func (e *Engine) Attach(tm *task.TaskManager, now func() time.Time) (detach func()) {
+	return tm.Subscribe(func(event task.Event) {
+		switch event.Op {
+		case task.EventAdded, task.EventUpdated, task.EventCompleted, task.EventOccurrence:
+			e.runTask(tm, event.Task.ID, now())
+		}
+	})
+}
+
+// runTask применяет правила к задаче id, пока они что-то меняют.
+// Изменения, сделанные самими правилами, повторно их не запускают
+func (e *Engine) runTask(tm *task.TaskManager, id int, now time.Time) []Effect {
+	if e.running {
+		return nil
+	}
+	e.running = true
+	defer func() { e.running = false }()
+
+	var applied []Effect
+	for range maxPasses {
+		t := tm.GetTask(id)
+		if t == nil {
+			break
+		}
+		effects := e.planTask(t, now)
+		if len(effects) == 0 {
+			break
+		}
+		for _, effect := range effects {
+			snapshot := *effect.Task
+			if e.apply(tm, effect) == nil {
+				applied = append(applied, Effect{Rule: effect.Rule, Task: &snapshot})
+			}
+		}
+	}
+	return applied
+}
+
+// apply выполняет действие правила
+func (e *Engine) apply(tm *task.TaskManager, effect Effect) error {
+	t := effect.Task
+	switch effect.Rule.Action {
+	case ActionSetPriority:
+		return tm.UpdateTask(t.ID, t.Title, t.Description, effect.Rule.Priority, t.DueDate, t.Completed)
+	case ActionArchive:
+		e.Applied[appliedKey(effect.Rule, t)] = t.UpdatedAt
+		return tm.SetTaskArchived(t.ID, true)
+	case ActionNotify:
+		e.Applied[appliedKey(effect.Rule, t)] = t.UpdatedAt
+		if e.Notify != nil {
+			e.Notify(t.Title, effect.Rule.Describe())
+		}
+	}
+	return nil
+}
+
+// appliedKey - ключ срабатывания правила для задачи
+func appliedKey(rule Rule, t *task.Task) string {
+	return rule.ID + "/" + t.UUID
+}
@@ -0,0 +1,71 @@
+package rules
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/storage"
+	"taskmanager/task"
+)
+
+func newTestManager(t *testing.T) *task.TaskManager {
+	return task.NewTaskManager(storage.NewFile(filepath.Join(t.TempDir(), "tasks.json")))
+}
+
+func TestEngineRun(t *testing.T) {
+	tm := newTestManager(t)
+	late, _ := tm.AddTask("Сдать отчет", "", 1, time.Now().AddDate(0, 0, -1))
+	fresh, _ := tm.AddTask("Купить молоко", "", 1, time.Now())
+
+	raise := NewRule(CondOverdue, ActionSetPriority)
+	raise.Priority = 3
+	var notes []string
+	engine := NewEngine([]Rule{raise, NewRule(CondOverdue, ActionNotify)}, func(title, message string) {
+		notes = append(notes, title)
+	})
+
+	// Пробный запуск ничего не меняет
+	effects := engine.Plan(tm.Tasks(), time.Now())
+	assert.Len(t, effects, 2)
+	assert.Equal(t, "«Сдать отчет»: приоритет низкий → высокий", effects[0].Describe())
+	assert.Equal(t, 1, tm.GetTask(late.ID).Priority)
+	assert.Empty(t, notes)
+
+	assert.Len(t, engine.Run(tm, time.Now()), 2)
+	assert.Equal(t, 3, tm.GetTask(late.ID).Priority)
+	assert.Equal(t, 1, tm.GetTask(fresh.ID).Priority)
+	assert.Equal(t, []string{"Сдать отчет"}, notes)
+
+	// Повторный запуск не повторяет уведомление о неизменной задаче
+	assert.Empty(t, engine.Run(tm, time.Now()))
+	assert.Len(t, notes, 1)
+
+	// Отключенное правило не срабатывает
+	engine.Rules[0].Enabled = false
+	assert.NoError(t, tm.UpdateTask(late.ID, late.Title, "", 1, late.DueDate, false))
+	engine.Run(tm, time.Now())
+	assert.Equal(t, 1, tm.GetTask(late.ID).Priority)
+}
+
+func TestEngineAttach(t *testing.T) {
+	tm := newTestManager(t)
+	engine := NewEngine([]Rule{NewRule(CondCompleted, ActionArchive)}, nil)
+	detach := engine.Attach(tm, time.Now)
+
+	added, _ := tm.AddTask("Купить молоко", "", 1, time.Now())
+	assert.False(t, tm.GetTask(added.ID).Archived)
+	assert.NoError(t, tm.ToggleTaskCompletion(added.ID))
+	assert.True(t, tm.GetTask(added.ID).Archived)
+
+	// Задачу можно вернуть из архива, правило не убирает ее снова
+	assert.NoError(t, tm.SetTaskArchived(added.ID, false))
+	assert.False(t, tm.GetTask(added.ID).Archived)
+
+	detach()
+	other, _ := tm.AddTask("Позвонить маме", "", 1, time.Now())
+	assert.NoError(t, tm.ToggleTaskCompletion(other.ID))
+	assert.False(t, tm.GetTask(other.ID).Archived)
+}
@@ -0,0 +1,151 @@
+// Package rules - правила автоматизации: условие на задачу и действие над ней,
+// например «просрочена → высокий приоритет» или «выполнена → в архив».
+// Правила проверяются при изменении задач и по таймеру; пакет не зависит от интерфейса
+package rules
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"taskmanager/task"
+)
+
+// Condition - условие, при котором срабатывает правило
+type Condition string
+
+const (
+	CondOverdue   Condition = "overdue"   // срок прошел, а задача не выполнена
+	CondTagged    Condition = "tagged"    // у невыполненной задачи есть метка Tag и она не менялась Days дней
+	CondCompleted Condition = "completed" // задача выполнена и не менялась Days дней
+)
+
+// Action - действие над задачей, для которой выполнено условие
+type Action string
+
+const (
+	ActionSetPriority Action = "set_priority" // задать приоритет Priority
+	ActionNotify      Action = "notify"       // показать уведомление
+	ActionArchive     Action = "archive"      // убрать в архив
+)
+
+// ErrInvalidRule возвращается для правила без условия, действия или их параметров
+var ErrInvalidRule = errors.New("invalid rule")
+
+// Rule - правило автоматизации
+type Rule struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name,omitempty"`
+	Enabled   bool      `json:"enabled"`
+	Condition Condition `json:"condition"`
+	Tag       string    `json:"tag,omitempty"`
+	Days      int       `json:"days,omitempty"`
+	Action    Action    `json:"action"`
+	Priority  int       `json:"priority,omitempty"`
+}
+
+// NewRule создает включенное правило с новым идентификатором
+func NewRule(condition Condition, action Action) Rule {
+	id := make([]byte, 8)
+	rand.Read(id)
+	return Rule{ID: hex.EncodeToString(id), Enabled: true, Condition: condition, Action: action}
+}
+
+// Validate проверяет, что у правила есть все параметры условия и действия
+func (r Rule) Validate() error {
+	switch r.Condition {
+	case CondOverdue:
+	case CondTagged:
+		if r.Tag == "" {
+			return fmt.Errorf("%w: tag is required", ErrInvalidRule)
+		}
+	case CondCompleted:
+	default:
+		return fmt.Errorf("%w: unknown condition %q", ErrInvalidRule, r.Condition)
+	}
+	if r.Days < 0 {
+		return fmt.Errorf("%w: days must not be negative", ErrInvalidRule)
+	}
+
+	switch r.Action {
+	case ActionSetPriority:
+		if r.Priority < 1 || r.Priority > 3 {
+			return fmt.Errorf("%w: priority must be 1, 2 or 3", ErrInvalidRule)
+		}
+	case ActionNotify, ActionArchive:
+	default:
+		return fmt.Errorf("%w: unknown action %q", ErrInvalidRule, r.Action)
+	}
+	return nil
+}
+
+// Matches проверяет условие правила для задачи. Задачи в архиве правила не трогают
+func (r Rule) Matches(t *task.Task, now time.Time) bool {
+	if t.Archived {
+		return false
+	}
+	idle := now.Sub(t.UpdatedAt) >= time.Duration(r.Days)*24*time.Hour
+	switch r.Condition {
+	case CondOverdue:
+		return t.IsOverdue(now)
+	case CondTagged:
+		return !t.Completed && slices.Contains(t.Tags, r.Tag) && idle
+	case CondCompleted:
+		return t.Completed && idle
+	}
+	return false
+}
+
+// Describe описывает правило для списка правил
+func (r Rule) Describe() string {
+	var condition string
+	switch r.Condition {
+	case CondOverdue:
+		condition = "Просрочена"
+	case CondTagged:
+		condition = fmt.Sprintf("С меткой #%s дольше %d дн.", r.Tag, r.Days)
+	case CondCompleted:
+		condition = "Выполнена"
+		if r.Days > 0 {
+			condition += fmt.Sprintf(" больше %d дн. назад", r.Days)
+		}
+	}
+
+	var action string
+	switch r.Action {
+	case ActionSetPriority:
+		action = "приоритет " + task.PriorityText(r.Priority)
+	case ActionNotify:
+		action = "уведомить"
+	case ActionArchive:
+		action = "в архив"
+	}
+
+	description := condition + " → " + action
+	if r.Name != "" {
+		description = r.Name + ": " + description
+	}
+	return description
+}
+
+// Parse читает правила, записанные Marshal; пустая строка - правил нет
+func Parse(data string) ([]Rule, error) {
+	if data == "" {
+		return nil, nil
+	}
+	var list []Rule
+	if err := json.Unmarshal([]byte(data), &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Marshal записывает правила для хранения в настройках
+func Marshal(list []Rule) string {
+	data, _ := json.Marshal(list)
+	return string(data)
+}
@@ -0,0 +1,61 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+func TestRuleValidate(t *testing.T) {
+	rule := NewRule(CondOverdue, ActionSetPriority)
+	assert.ErrorIs(t, rule.Validate(), ErrInvalidRule)
+	rule.Priority = 3
+	assert.NoError(t, rule.Validate())
+
+	tagged := NewRule(CondTagged, ActionNotify)
+	assert.ErrorIs(t, tagged.Validate(), ErrInvalidRule)
+	tagged.Tag = "waiting"
+	assert.NoError(t, tagged.Validate())
+	tagged.Days = -1
+	assert.ErrorIs(t, tagged.Validate(), ErrInvalidRule)
+
+	assert.ErrorIs(t, NewRule("unknown", ActionArchive).Validate(), ErrInvalidRule)
+	assert.NotEqual(t, NewRule(CondOverdue, ActionArchive).ID, NewRule(CondOverdue, ActionArchive).ID)
+}
+
+func TestRuleMatches(t *testing.T) {
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	weekAgo := now.Add(-7 * 24 * time.Hour)
+
+	overdue := Rule{Condition: CondOverdue}
+	assert.True(t, overdue.Matches(&task.Task{DueDate: now.AddDate(0, 0, -1)}, now))
+	assert.False(t, overdue.Matches(&task.Task{DueDate: now}, now))
+	assert.False(t, overdue.Matches(&task.Task{DueDate: now.AddDate(0, 0, -1), Completed: true}, now))
+	assert.False(t, overdue.Matches(&task.Task{DueDate: now.AddDate(0, 0, -1), Archived: true}, now))
+
+	waiting := Rule{Condition: CondTagged, Tag: "waiting", Days: 7}
+	assert.True(t, waiting.Matches(&task.Task{Tags: []string{"waiting"}, UpdatedAt: weekAgo}, now))
+	assert.False(t, waiting.Matches(&task.Task{Tags: []string{"waiting"}, UpdatedAt: now.Add(-time.Hour)}, now))
+	assert.False(t, waiting.Matches(&task.Task{Tags: []string{"home"}, UpdatedAt: weekAgo}, now))
+
+	completed := Rule{Condition: CondCompleted}
+	assert.True(t, completed.Matches(&task.Task{Completed: true, UpdatedAt: now}, now))
+	assert.False(t, completed.Matches(&task.Task{UpdatedAt: weekAgo}, now))
+}
+
+func TestParseMarshal(t *testing.T) {
+	list, err := Parse("")
+	assert.NoError(t, err)
+	assert.Empty(t, list)
+
+	rule := NewRule(CondTagged, ActionNotify)
+	rule.Tag, rule.Days = "waiting", 7
+	list, err = Parse(Marshal([]Rule{rule}))
+	assert.NoError(t, err)
+	assert.Equal(t, []Rule{rule}, list)
+	assert.Equal(t, "Ждем ответа: С меткой #waiting дольше 7 дн. → уведомить",
+		Rule{Name: "Ждем ответа", Condition: CondTagged, Tag: "waiting", Days: 7, Action: ActionNotify}.Describe())
+}
@@ -0,0 +1,89 @@
+// Package sampledata создает задачи-примеры: понятный набор для первого запуска
+// и сколько угодно синтетических задач для тестов и бенчмарков
+package sampledata
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"taskmanager/task"
+)
+
+// example - задача-пример для первого запуска
+type example struct {
+	title       string
+	description string
+	priority    int
+	dueInDays   int
+	tags        []string
+	completed   bool
+}
+
+var examples = []example{
+	{"Познакомиться с менеджером задач", "Выберите задачу и нажмите «Редактировать», чтобы изменить ее", 3, 0, []string{"начало"}, false},
+	{"Купить продукты", "Молоко, хлеб, яблоки", 2, 1, []string{"дом"}, false},
+	{"Оплатить интернет", "", 3, 3, []string{"дом", "счета"}, false},
+	{"Подготовить отчет", "Собрать цифры за месяц и отправить руководителю", 3, 5, []string{"работа"}, false},
+	{"Записаться к врачу", "", 2, 7, []string{"здоровье"}, false},
+	{"Прочитать книгу", "Хотя бы по 20 страниц в день", 1, 30, []string{"личное"}, false},
+	{"Установить менеджер задач", "", 1, 0, []string{"начало"}, true},
+}
+
+// AddExamples добавляет небольшой набор понятных задач-примеров со сроками относительно now
+func AddExamples(tm task.TaskService, now time.Time) error {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for _, e := range examples {
+		added, err := tm.AddTask(e.title, e.description, e.priority, today.AddDate(0, 0, e.dueInDays))
+		if err != nil {
+			return err
+		}
+		if err := tm.SetTaskTags(added.ID, e.tags); err != nil {
+			return err
+		}
+		if e.completed {
+			if err := tm.ToggleTaskCompletion(added.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+var (
+	syntheticVerbs = []string{"Проверить", "Написать", "Обсудить", "Исправить", "Купить", "Позвонить", "Спланировать"}
+	syntheticNouns = []string{"отчет", "письмо", "бюджет", "ошибку", "подарок", "встречу", "ремонт", "договор"}
+	syntheticTags  = []string{"работа", "дом", "срочно", "личное", "счета"}
+)
+
+// Generate добавляет n синтетических задач. Одинаковый seed дает одинаковые задачи,
+// поэтому генератор подходит для воспроизводимых тестов и бенчмарков
+func Generate(tm task.TaskService, n int, seed int64, now time.Time) error {
+	rng := rand.New(rand.NewSource(seed))
+	for i := range n {
+		title := fmt.Sprintf("%s %s #%d",
+			syntheticVerbs[rng.Intn(len(syntheticVerbs))], syntheticNouns[rng.Intn(len(syntheticNouns))], i+1)
+		dueDate := now.AddDate(0, 0, rng.Intn(60)-15)
+
+		added, err := tm.AddTask(title, "Синтетическая задача", 1+rng.Intn(3), dueDate)
+		if err != nil {
+			return err
+		}
+
+		var tags []string
+		for _, tag := range syntheticTags {
+			if rng.Intn(4) == 0 {
+				tags = append(tags, tag)
+			}
+		}
+		if err := tm.SetTaskTags(added.ID, tags); err != nil {
+			return err
+		}
+		if rng.Intn(3) == 0 {
+			if err := tm.ToggleTaskCompletion(added.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package sampledata
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/storage"
+	"taskmanager/task"
+)
+
+func newManager(tb testing.TB) *task.TaskManager {
+	return task.NewTaskManager(storage.NewFile(filepath.Join(tb.TempDir(), "tasks.json")))
+}
+
+func TestAddExamples(t *testing.T) {
+	tm := newManager(t)
+	now := time.Date(2025, 6, 30, 15, 0, 0, 0, time.Local)
+
+	assert.NoError(t, AddExamples(tm, now))
+	tasks := tm.Tasks()
+	assert.Equal(t, len(examples), len(tasks))
+	assert.Equal(t, "2025-06-30", tasks[0].DueDate.Format("2006-01-02"))
+	assert.Equal(t, []string{"дом", "счета"}, tasks[2].Tags)
+	assert.Equal(t, 1, len(tm.FilterTasksByStatus(true)))
+}
+
+func TestAddExamplesWithStrictValidation(t *testing.T) {
+	tm := newManager(t)
+	tm.SetRequireDueAfterCreated(true)
+
+	// Сроки примеров не раньше сегодняшнего дня, поэтому проходят строгую проверку
+	assert.NoError(t, AddExamples(tm, time.Now()))
+}
+
+func TestGenerateIsReproducible(t *testing.T) {
+	now := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	first, second := newManager(t), newManager(t)
+
+	assert.NoError(t, Generate(first, 50, 42, now))
+	assert.NoError(t, Generate(second, 50, 42, now))
+	assert.Equal(t, 50, len(first.Tasks()))
+	for i, generated := range first.Tasks() {
+		other := second.Tasks()[i]
+		assert.Equal(t, generated.Title, other.Title)
+		assert.Equal(t, generated.Priority, other.Priority)
+		assert.Equal(t, generated.DueDate, other.DueDate)
+		assert.Equal(t, generated.Tags, other.Tags)
+		assert.Equal(t, generated.Completed, other.Completed)
+	}
+}
+
+func BenchmarkSearchTasks(b *testing.B) {
+	tm := newManager(b)
+	if err := Generate(tm, 10000, 1, time.Now()); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for range b.N {
+		tm.SearchTasks("отчет")
+	}
+}
+
+func BenchmarkSortTasks(b *testing.B) {
+	tm := newManager(b)
+	if err := Generate(tm, 10000, 1, time.Now()); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for range b.N {
+		task.SortTasks(tm.Tasks(), task.SortByDueDate, false)
+	}
+}
+
+func BenchmarkSaveToFile(b *testing.B) {
+	tm := newManager(b)
+	tm.SetBackupKeep(0)
+	if err := Generate(tm, 10000, 1, time.Now()); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for range b.N {
+		if err := tm.ForceSaveToFile(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
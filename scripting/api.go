@@ -0,0 +1,222 @@
+package scripting
+
+import (
+	"fmt"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+
+	"taskmanager/task"
+)
+
+// dateFormat - формат сроков задач в скриптах
+const dateFormat = "2006-01-02"
+
+// api возвращает функции, доступные скрипту: модуль tasks и notify
+func (r *Runtime) api() starlark.StringDict {
+	return starlark.StringDict{
+		"tasks": &starlarkstruct.Module{
+			Name: "tasks",
+			Members: starlark.StringDict{
+				"list":   starlark.NewBuiltin("tasks.list", r.list),
+				"get":    starlark.NewBuiltin("tasks.get", r.get),
+				"add":    starlark.NewBuiltin("tasks.add", r.add),
+				"update": starlark.NewBuiltin("tasks.update", r.update),
+			},
+		},
+		"notify": starlark.NewBuiltin("notify", func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			var message string
+			if err := starlark.UnpackArgs(b.Name(), args, kwargs, "message", &message); err != nil {
+				return nil, err
+			}
+			if r.notify != nil {
+				r.notify(message)
+			}
+			return starlark.None, nil
+		}),
+	}
+}
+
+// taskValue представляет задачу в скрипте. Значение неизменяемое: задачи меняются через tasks.update
+func taskValue(t *task.Task) starlark.Value {
+	tags := make([]starlark.Value, len(t.Tags))
+	for i, tag := range t.Tags {
+		tags[i] = starlark.String(tag)
+	}
+	due := ""
+	if !t.DueDate.IsZero() {
+		due = t.DueDate.Format(dateFormat)
+	}
+	return starlarkstruct.FromStringDict(starlark.String("task"), starlark.StringDict{
+		"id":          starlark.MakeInt(t.ID),
+		"uuid":        starlark.String(t.UUID),
+		"title":       starlark.String(t.Title),
+		"description": starlark.String(t.Description),
+		"priority":    starlark.MakeInt(t.Priority),
+		"due":         starlark.String(due),
+		"completed":   starlark.Bool(t.Completed),
+		"archived":    starlark.Bool(t.Archived),
+		"tags":        starlark.Tuple(tags),
+		"assignee":    starlark.String(t.Assignee),
+	})
+}
+
+// list: tasks.list() - все задачи
+func (r *Runtime) list(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs); err != nil {
+		return nil, err
+	}
+	values := make([]starlark.Value, 0, len(r.tm.Tasks()))
+	for _, t := range r.tm.Tasks() {
+		values = append(values, taskValue(t))
+	}
+	return starlark.NewList(values), nil
+}
+
+// get: tasks.get(id) - задача по номеру или None
+func (r *Runtime) get(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var id int
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "id", &id); err != nil {
+		return nil, err
+	}
+	t := r.tm.GetTask(id)
+	if t == nil {
+		return starlark.None, nil
+	}
+	return taskValue(t), nil
+}
+
+// add: tasks.add(title, description="", priority=2, due="ГГГГ-ММ-ДД") - добавляет задачу
+func (r *Runtime) add(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var title, description, due string
+	priority := 2
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs,
+		"title", &title, "description?", &description, "priority?", &priority, "due?", &due); err != nil {
+		return nil, err
+	}
+	// Как в окне новой задачи: по умолчанию срок - завтра
+	zone := r.tm.DueZone()
+	dueDate, _ := zone.ParseDueDate(zone.Now().AddDate(0, 0, 1).Format(dateFormat))
+	if due != "" {
+		parsed, err := zone.ParseDueDate(due)
+		if err != nil {
+			return nil, fmt.Errorf("%s: due must be YYYY-MM-DD", b.Name())
+		}
+		dueDate = parsed
+	}
+	added, err := r.tm.AddTask(title, description, priority, dueDate)
+	if err != nil {
+		return nil, err
+	}
+	return taskValue(added), nil
+}
+
+// update: tasks.update(id, title=, description=, priority=, due=, completed=, tags=, assignee=, archived=)
+// меняет только переданные поля и возвращает задачу
+func (r *Runtime) update(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var id int
+	var title, description, due, assignee starlark.Value
+	var priority, completed, archived starlark.Value
+	var tags *starlark.List
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "id", &id,
+		"title?", &title, "description?", &description, "priority?", &priority, "due?", &due,
+		"completed?", &completed, "tags?", &tags, "assignee?", &assignee, "archived?", &archived); err != nil {
+		return nil, err
+	}
+	t := r.tm.GetTask(id)
+	if t == nil {
+		return nil, fmt.Errorf("%s: %w: id %d", b.Name(), task.ErrNotFound, id)
+	}
+
+	newTitle, newDescription, newPriority, newDue, newCompleted := t.Title, t.Description, t.Priority, t.DueDate, t.Completed
+	if err := unpackField(b, "title", title, &newTitle); err != nil {
+		return nil, err
+	}
+	if err := unpackField(b, "description", description, &newDescription); err != nil {
+		return nil, err
+	}
+	if err := unpackField(b, "priority", priority, &newPriority); err != nil {
+		return nil, err
+	}
+	if err := unpackField(b, "completed", completed, &newCompleted); err != nil {
+		return nil, err
+	}
+	var dueText string
+	if err := unpackField(b, "due", due, &dueText); err != nil {
+		return nil, err
+	}
+	if dueText != "" {
+		parsed, err := r.tm.DueZone().ParseDueDate(dueText)
+		if err != nil {
+			return nil, fmt.Errorf("%s: due must be YYYY-MM-DD", b.Name())
+		}
+		newDue = parsed
+	}
+	newAssignee, newArchived := t.Assignee, t.Archived
+	if err := unpackField(b, "assignee", assignee, &newAssignee); err != nil {
+		return nil, err
+	}
+	if err := unpackField(b, "archived", archived, &newArchived); err != nil {
+		return nil, err
+	}
+	newTags := t.Tags
+	if tags != nil {
+		newTags = nil
+		for i := range tags.Len() {
+			tag, ok := starlark.AsString(tags.Index(i))
+			if !ok {
+				return nil, fmt.Errorf("%s: tags must be a list of strings", b.Name())
+			}
+			newTags = append(newTags, tag)
+		}
+	}
+
+	// Все аргументы проверены: теперь задачу можно менять
+	if err := r.tm.UpdateTask(id, newTitle, newDescription, newPriority, newDue, newCompleted); err != nil {
+		return nil, err
+	}
+	if tags != nil {
+		if err := r.tm.SetTaskTags(id, newTags); err != nil {
+			return nil, err
+		}
+	}
+	if newAssignee != t.Assignee {
+		if err := r.tm.SetTaskAssignee(id, newAssignee); err != nil {
+			return nil, err
+		}
+	}
+	if newArchived != t.Archived {
+		if err := r.tm.SetTaskArchived(id, newArchived); err != nil {
+			return nil, err
+		}
+	}
+	return taskValue(r.tm.GetTask(id)), nil
+}
+
+// unpackField переносит необязательный аргумент в dst; None и отсутствующий аргумент пропускаются
+func unpackField[T string | int | bool](b *starlark.Builtin, name string, v starlark.Value, dst *T) error {
+	if v == nil || v == starlark.None {
+		return nil
+	}
+	var err error
+	switch p := any(dst).(type) {
+	case *string:
+		s, ok := starlark.AsString(v)
+		if !ok {
+			err = fmt.Errorf("%s: %s must be a string", b.Name(), name)
+		}
+		*p = s
+	case *int:
+		*p, err = starlark.AsInt32(v)
+		if err != nil {
+			err = fmt.Errorf("%s: %s must be an int", b.Name(), name)
+		}
+	case *bool:
+		bv, ok := v.(starlark.Bool)
+		if !ok {
+			err = fmt.Errorf("%s: %s must be a bool", b.Name(), name)
+		}
+		*p = bool(bv)
+	}
+	return err
+}
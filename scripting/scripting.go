@@ -0,0 +1,180 @@
+// Package scripting запускает пользовательские скрипты на Starlark (диалект Python):
+// скрипт подписывается на события задач и добавляет свои команды в меню.
+// Скриптам доступны только задачи через API tasks, без файлов, сети и процессов,
+// а число шагов каждого вызова ограничено, чтобы скрипт не повесил приложение
+package scripting
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+
+	"taskmanager/task"
+)
+
+// maxSteps - наибольшее число шагов одного вызова скрипта
+const maxSteps = 1_000_000
+
+// Events - события задач, на которые подписываются скрипты через on()
+var Events = []task.EventOp{task.EventAdded, task.EventUpdated, task.EventCompleted, task.EventOccurrence, task.EventDeleted}
+
+// Command - команда, которую скрипт добавил в меню
+type Command struct {
+	Script string // имя файла скрипта
+	Name   string
+	fn     starlark.Callable
+}
+
+// handler - обработчик события задач
+type handler struct {
+	script string
+	op     task.EventOp
+	fn     starlark.Callable
+}
+
+// Runtime - загруженные скрипты. Не потокобезопасен: вызывается в потоке менеджера задач
+type Runtime struct {
+	tm       *task.TaskManager
+	notify   func(message string)
+	handlers []handler
+	commands []Command
+	running  bool
+}
+
+// Load загружает все скрипты *.star из папки dir. Скрипт с ошибкой пропускается,
+// ошибки возвращаются вместе со скриптами, которые удалось загрузить.
+// notify показывает уведомление по просьбе скрипта
+func Load(dir string, tm *task.TaskManager, notify func(message string)) (*Runtime, []error) {
+	r := &Runtime{tm: tm, notify: notify}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.star"))
+	if err != nil {
+		return r, []error{err}
+	}
+	sort.Strings(paths)
+
+	var errs []error
+	for _, path := range paths {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := r.LoadScript(filepath.Base(path), src); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return r, errs
+}
+
+// LoadScript выполняет скрипт name: он регистрирует обработчики событий и команды.
+// Если скрипт завершился с ошибкой, его обработчики и команды не добавляются
+func (r *Runtime) LoadScript(name string, src []byte) error {
+	var handlers []handler
+	var commands []Command
+
+	on := starlark.NewBuiltin("on", func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var event string
+		var fn starlark.Callable
+		if err := starlark.UnpackArgs(b.Name(), args, kwargs, "event", &event, "fn", &fn); err != nil {
+			return nil, err
+		}
+		op := task.EventOp(event)
+		if !containsEvent(op) {
+			return nil, fmt.Errorf("on: unknown event %q, expected one of %v", event, Events)
+		}
+		handlers = append(handlers, handler{script: name, op: op, fn: fn})
+		return starlark.None, nil
+	})
+	command := starlark.NewBuiltin("command", func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var title string
+		var fn starlark.Callable
+		if err := starlark.UnpackArgs(b.Name(), args, kwargs, "name", &title, "fn", &fn); err != nil {
+			return nil, err
+		}
+		commands = append(commands, Command{Script: name, Name: title, fn: fn})
+		return starlark.None, nil
+	})
+
+	predeclared := r.api()
+	predeclared["on"] = on
+	predeclared["command"] = command
+
+	thread := r.newThread(name)
+	if _, err := starlark.ExecFileOptions(&syntax.FileOptions{}, thread, name, src, predeclared); err != nil {
+		return fmt.Errorf("script %s: %w", name, err)
+	}
+	r.handlers = append(r.handlers, handlers...)
+	r.commands = append(r.commands, commands...)
+	return nil
+}
+
+// Commands возвращает команды скриптов в порядке регистрации
+func (r *Runtime) Commands() []Command {
+	return r.commands
+}
+
+// RunCommand выполняет команду скрипта
+func (r *Runtime) RunCommand(cmd Command) error {
+	return r.call(cmd.Script, cmd.fn)
+}
+
+// Attach вызывает обработчики скриптов при изменении задач. Изменения, которые делают
+// сами скрипты, обработчики повторно не запускают. Возвращает функцию отписки
+func (r *Runtime) Attach() (detach func()) {
+	return r.tm.Subscribe(func(event task.Event) {
+		if r.running {
+			return
+		}
+		for _, h := range r.handlers {
+			if h.op != event.Op {
+				continue
+			}
+			if err := r.call(h.script, h.fn, taskValue(event.Task)); err != nil {
+				slog.Error("script event handler failed", "script", h.script, "event", event.Op, "err", err)
+			}
+		}
+	})
+}
+
+// errBusy возвращается, если скрипт вызывается, пока выполняется другой
+var errBusy = errors.New("another script is running")
+
+// call вызывает функцию скрипта с ограничением числа шагов
+func (r *Runtime) call(script string, fn starlark.Callable, args ...starlark.Value) error {
+	if r.running {
+		return errBusy
+	}
+	r.running = true
+	defer func() { r.running = false }()
+
+	_, err := starlark.Call(r.newThread(script), fn, args, nil)
+	return err
+}
+
+// newThread создает поток выполнения скрипта: print пишет в журнал приложения
+func (r *Runtime) newThread(script string) *starlark.Thread {
+	thread := &starlark.Thread{
+		Name: script,
+		Print: func(thread *starlark.Thread, msg string) {
+			slog.Info("script output", "script", script, "message", msg)
+		},
+	}
+	thread.SetMaxExecutionSteps(maxSteps)
+	return thread
+}
+
+// containsEvent проверяет, можно ли подписаться на событие
+func containsEvent(op task.EventOp) bool {
+	for _, e := range Events {
+		if e == op {
+			return true
+		}
+	}
+	return false
+}
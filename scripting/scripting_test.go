@@ -0,0 +1,140 @@
+package scripting
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"taskmanager/storage"
+	"taskmanager/task"
+)
+
+func newTestManager(t *testing.T) *task.TaskManager {
+	return task.NewTaskManager(storage.NewFile(filepath.Join(t.TempDir(), "tasks.json")))
+}
+
+func TestScriptEvents(t *testing.T) {
+	tm := newTestManager(t)
+	var notes []string
+	r := &Runtime{tm: tm, notify: func(message string) { notes = append(notes, message) }}
+	require.NoError(t, r.LoadScript("urgent.star", []byte(`
+def tag_urgent(t):
+    if "срочно" in t.title.lower():
+        tasks.update(t.id, priority = 3, tags = ["срочно"])
+        notify("Срочная задача: " + t.title)
+
+on("added", tag_urgent)
+`)))
+	detach := r.Attach()
+
+	added, err := tm.AddTask("Срочно позвонить", "", 1, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	got := tm.GetTask(added.ID)
+	assert.Equal(t, 3, got.Priority)
+	assert.Equal(t, []string{"срочно"}, got.Tags)
+	assert.Equal(t, []string{"Срочная задача: Срочно позвонить"}, notes)
+
+	other, _ := tm.AddTask("Купить молоко", "", 1, time.Now().Add(time.Hour))
+	assert.Equal(t, 1, tm.GetTask(other.ID).Priority)
+
+	// После отписки обработчики не вызываются
+	detach()
+	late, _ := tm.AddTask("Срочно ответить", "", 1, time.Now().Add(time.Hour))
+	assert.Equal(t, 1, tm.GetTask(late.ID).Priority)
+}
+
+func TestScriptCommands(t *testing.T) {
+	tm := newTestManager(t)
+	tm.AddTask("Старая задача", "", 2, time.Now().Add(time.Hour))
+	r := &Runtime{tm: tm}
+	require.NoError(t, r.LoadScript("cleanup.star", []byte(`
+def complete_all():
+    for t in tasks.list():
+        tasks.update(t.id, completed = True)
+
+def plan_week():
+    tasks.add("Обзор недели", description = "Каждый понедельник", priority = 1, due = "2030-01-07")
+
+command("Выполнить все", complete_all)
+command("План недели", plan_week)
+`)))
+
+	commands := r.Commands()
+	require.Len(t, commands, 2)
+	assert.Equal(t, "Выполнить все", commands[0].Name)
+	assert.Equal(t, "cleanup.star", commands[0].Script)
+
+	require.NoError(t, r.RunCommand(commands[0]))
+	assert.True(t, tm.Tasks()[0].Completed)
+
+	require.NoError(t, r.RunCommand(commands[1]))
+	require.Len(t, tm.Tasks(), 2)
+	added := tm.Tasks()[1]
+	assert.Equal(t, "Обзор недели", added.Title)
+	assert.Equal(t, "Каждый понедельник", added.Description)
+	assert.Equal(t, "2030-01-07", added.DueDate.Format(dateFormat))
+}
+
+func TestScriptErrors(t *testing.T) {
+	tm := newTestManager(t)
+	r := &Runtime{tm: tm}
+
+	// Скрипт с ошибкой не регистрирует ни обработчики, ни команды
+	assert.Error(t, r.LoadScript("broken.star", []byte(`
+command("Не появится", lambda: None)
+fail("oops")
+`)))
+	assert.Error(t, r.LoadScript("event.star", []byte(`on("renamed", lambda t: None)`)))
+	assert.Error(t, r.LoadScript("syntax.star", []byte(`def (`)))
+	assert.Empty(t, r.Commands())
+
+	// Скрипту недоступны файлы и модули
+	assert.Error(t, r.LoadScript("load.star", []byte(`load("os.star", "x")`)))
+
+	// Бесконечный цикл прерывается по числу шагов
+	require.NoError(t, r.LoadScript("loop.star", []byte(`
+def spin():
+    n = 0
+    for i in range(100000000):
+        n += i
+
+command("Цикл", spin)
+`)))
+	assert.ErrorContains(t, r.RunCommand(r.Commands()[0]), "too many steps")
+
+	// Неверные аргументы не меняют задачу частично
+	added, _ := tm.AddTask("Задача", "", 2, time.Now().Add(time.Hour))
+	require.NoError(t, r.LoadScript("bad.star", []byte(`
+def bad():
+    tasks.update(1, title = "Новое", priority = "высокий")
+
+def missing():
+    tasks.update(42, title = "Нет такой")
+
+command("Плохо", bad)
+command("Нет задачи", missing)
+`)))
+	assert.Error(t, r.RunCommand(r.Commands()[1]))
+	assert.Equal(t, "Задача", tm.GetTask(added.ID).Title)
+	assert.ErrorIs(t, r.RunCommand(r.Commands()[2]), task.ErrNotFound)
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.star"), []byte(`command("A", lambda: None)`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.star"), []byte(`oops(`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "c.star"), []byte(`command("C", lambda: None)`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(`не скрипт`), 0o600))
+
+	r, errs := Load(dir, newTestManager(t), nil)
+	assert.Len(t, errs, 1)
+	var names []string
+	for _, cmd := range r.Commands() {
+		names = append(names, cmd.Name)
+	}
+	assert.Equal(t, []string{"A", "C"}, names)
+}
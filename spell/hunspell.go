@@ -0,0 +1,311 @@
+package spell
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Dictionary - словарь Hunspell: слова с флагами и правила приставок и окончаний из файла .aff.
+// Поддерживается то, что нужно для проверки слов: SFX, PFX, FLAG, SET и TRY.
+// Составные слова и правила замены не поддерживаются
+type Dictionary struct {
+	words    map[string][]string // слово - его флаги
+	suffixes map[string][]affixRule
+	prefixes map[string][]affixRule
+	try      string // буквы для подсказок, самые частые первыми
+}
+
+// affixRule - правило приставки или окончания: у основы отбрасывается strip и добавляется add,
+// если основа подходит под условие
+type affixRule struct {
+	flag  string
+	strip string
+	add   string
+	cond  []condClass
+}
+
+// condClass - один символ условия правила: любой, из набора или не из набора
+type condClass struct {
+	any   bool
+	neg   bool
+	chars string
+}
+
+func (c condClass) match(r rune) bool {
+	if c.any {
+		return true
+	}
+	return strings.ContainsRune(c.chars, r) != c.neg
+}
+
+// parseCondition разбирает условие правила: «.», «[^аеиоу]я», «ть»
+func parseCondition(s string) ([]condClass, error) {
+	if s == "." {
+		return nil, nil
+	}
+	var cond []condClass
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '.':
+			cond = append(cond, condClass{any: true})
+		case '[':
+			end := i + 1
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated condition %q", s)
+			}
+			class := condClass{chars: string(runes[i+1 : end])}
+			if rest, ok := strings.CutPrefix(class.chars, "^"); ok {
+				class.neg, class.chars = true, rest
+			}
+			cond = append(cond, class)
+			i = end
+		default:
+			cond = append(cond, condClass{chars: string(runes[i])})
+		}
+	}
+	return cond, nil
+}
+
+// matchEnd проверяет условие на конце основы, matchStart - на начале
+func matchEnd(cond []condClass, stem string) bool {
+	runes := []rune(stem)
+	if len(runes) < len(cond) {
+		return false
+	}
+	runes = runes[len(runes)-len(cond):]
+	for i, c := range cond {
+		if !c.match(runes[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchStart(cond []condClass, stem string) bool {
+	runes := []rune(stem)
+	if len(runes) < len(cond) {
+		return false
+	}
+	for i, c := range cond {
+		if !c.match(runes[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadDictionary читает словарь Hunspell из файла .dic и лежащего рядом .aff. Без .aff
+// файл читается как простой список слов, по одному в строке
+func LoadDictionary(dicPath string) (*Dictionary, error) {
+	dic, err := os.ReadFile(dicPath)
+	if err != nil {
+		return nil, err
+	}
+	aff, err := os.ReadFile(strings.TrimSuffix(dicPath, filepath.Ext(dicPath)) + ".aff")
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return ParseDictionary(aff, dic)
+}
+
+// ParseDictionary разбирает словарь Hunspell: содержимое .aff (может быть пустым) и .dic
+func ParseDictionary(aff, dic []byte) (*Dictionary, error) {
+	d := &Dictionary{
+		words:    make(map[string][]string),
+		suffixes: make(map[string][]affixRule),
+		prefixes: make(map[string][]affixRule),
+	}
+	decoder := affEncoding(aff)
+	if decoder != nil {
+		var err error
+		if aff, err = decoder.NewDecoder().Bytes(aff); err != nil {
+			return nil, fmt.Errorf("failed to decode affix file: %w", err)
+		}
+		if dic, err = decoder.NewDecoder().Bytes(dic); err != nil {
+			return nil, fmt.Errorf("failed to decode dictionary: %w", err)
+		}
+	}
+	flagMode, err := d.parseAffixes(aff)
+	if err != nil {
+		return nil, err
+	}
+	d.parseWords(dic, flagMode)
+	return d, nil
+}
+
+// affEncoding возвращает кодировку словаря из строки SET; nil - UTF-8
+func affEncoding(aff []byte) encoding.Encoding {
+	scanner := bufio.NewScanner(bytes.NewReader(aff))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "SET" {
+			continue
+		}
+		switch strings.ToUpper(fields[1]) {
+		case "KOI8-R":
+			return charmap.KOI8R
+		case "KOI8-U":
+			return charmap.KOI8U
+		case "MICROSOFT-CP1251", "CP1251", "WINDOWS-1251":
+			return charmap.Windows1251
+		case "ISO8859-1":
+			return charmap.ISO8859_1
+		case "ISO8859-15":
+			return charmap.ISO8859_15
+		}
+		return nil
+	}
+	return nil
+}
+
+// parseAffixes читает правила из .aff и возвращает вид флагов
+func (d *Dictionary) parseAffixes(aff []byte) (flagMode string, err error) {
+	scanner := bufio.NewScanner(bytes.NewReader(aff))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		switch fields[0] {
+		case "FLAG":
+			if len(fields) > 1 {
+				flagMode = fields[1]
+			}
+		case "TRY":
+			if len(fields) > 1 {
+				d.try = fields[1]
+			}
+		case "SFX", "PFX":
+			// Заголовок группы правил: «SFX A Y 3»; правило: «SFX A 0 ами [^ь]»
+			if len(fields) < 4 || len(fields) == 4 && isNumber(fields[3]) {
+				continue
+			}
+			rule := affixRule{flag: fields[1], strip: zeroEmpty(fields[2]), add: zeroEmpty(fields[3])}
+			// Флаги продолжения «ами/AB» не поддерживаются и отбрасываются
+			rule.add, _, _ = strings.Cut(rule.add, "/")
+			condition := "."
+			if len(fields) > 4 {
+				condition = fields[4]
+			}
+			if rule.cond, err = parseCondition(condition); err != nil {
+				return "", err
+			}
+			if fields[0] == "SFX" {
+				d.suffixes[rule.add] = append(d.suffixes[rule.add], rule)
+			} else {
+				d.prefixes[rule.add] = append(d.prefixes[rule.add], rule)
+			}
+		}
+	}
+	return flagMode, scanner.Err()
+}
+
+// parseWords читает слова .dic: первая строка - их число, дальше «слово/флаги»
+func (d *Dictionary) parseWords(dic []byte, flagMode string) {
+	scanner := bufio.NewScanner(bytes.NewReader(dic))
+	first := true
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if first {
+			first = false
+			if isNumber(line) {
+				continue
+			}
+		}
+		// Морфологические пометки после табуляции или пробела не нужны
+		if i := strings.IndexAny(line, " \t"); i >= 0 {
+			line = line[:i]
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		word, flags, _ := strings.Cut(line, "/")
+		d.words[word] = append(d.words[word], splitFlags(flags, flagMode)...)
+	}
+}
+
+// splitFlags разбирает флаги слова: по символу, по два символа (FLAG long) или числа через запятую (FLAG num)
+func splitFlags(flags, mode string) []string {
+	if flags == "" {
+		return nil
+	}
+	var split []string
+	switch mode {
+	case "long":
+		for i := 0; i+1 < len(flags); {
+			_, n1 := utf8.DecodeRuneInString(flags[i:])
+			_, n2 := utf8.DecodeRuneInString(flags[i+n1:])
+			split = append(split, flags[i:i+n1+n2])
+			i += n1 + n2
+		}
+	case "num":
+		split = strings.Split(flags, ",")
+	default:
+		for _, r := range flags {
+			split = append(split, string(r))
+		}
+	}
+	return split
+}
+
+func zeroEmpty(s string) string {
+	if s == "0" {
+		return ""
+	}
+	return s
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
+
+// hasFlag сообщает, есть ли слово word в словаре с флагом flag
+func (d *Dictionary) hasFlag(word, flag string) bool {
+	for _, f := range d.words[word] {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// Contains сообщает, есть ли слово в словаре как есть или с приставкой или окончанием по правилам
+func (d *Dictionary) Contains(word string) bool {
+	if _, ok := d.words[word]; ok {
+		return true
+	}
+	// Перебираем окончания слова и ищем правила, которые могли его добавить
+	for i := len(word); i >= 0; i-- {
+		if i < len(word) && !utf8.RuneStart(word[i]) {
+			continue
+		}
+		for _, rule := range d.suffixes[word[i:]] {
+			stem := word[:i] + rule.strip
+			if stem != "" && matchEnd(rule.cond, stem) && d.hasFlag(stem, rule.flag) {
+				return true
+			}
+		}
+		for _, rule := range d.prefixes[word[:i]] {
+			stem := rule.strip + word[i:]
+			if stem != "" && matchStart(rule.cond, stem) && d.hasFlag(stem, rule.flag) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,263 @@
+// Package spell - легкая проверка орфографии по словарям Hunspell: тем же, что у LibreOffice
+// и Firefox. Словари ищутся в системных папках по языку, например ru_RU или en_US.
+// Пакет не зависит от интерфейса
+package spell
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// maxSuggestions - сколько исправлений предлагать для слова
+const maxSuggestions = 5
+
+// Checker проверяет слова по нескольким словарям и личному словарю пользователя.
+// Слово верно, если оно есть хотя бы в одном словаре. Без словарей верны все слова
+type Checker struct {
+	dicts    []*Dictionary
+	personal map[string]bool
+}
+
+// NewChecker создает проверку по словарям dicts и личным словам personal
+func NewChecker(dicts []*Dictionary, personal []string) *Checker {
+	c := &Checker{dicts: dicts, personal: make(map[string]bool)}
+	for _, word := range personal {
+		c.AddWord(word)
+	}
+	return c
+}
+
+// Enabled сообщает, загружен ли хотя бы один словарь
+func (c *Checker) Enabled() bool {
+	return c != nil && len(c.dicts) > 0
+}
+
+// AddWord добавляет слово в личный словарь: оно больше не считается ошибкой
+func (c *Checker) AddWord(word string) {
+	c.personal[strings.ToLower(word)] = true
+}
+
+// Correct сообщает, написано ли слово верно. Слово с заглавной буквы проверяется и со строчной,
+// а слова из одних заглавных букв считаются сокращениями и не проверяются
+func (c *Checker) Correct(word string) bool {
+	if !c.Enabled() || c.personal[strings.ToLower(word)] {
+		return true
+	}
+	runes := []rune(word)
+	if len(runes) < 2 || strings.ToUpper(word) == word {
+		return true
+	}
+	if c.contains(word) || c.contains(strings.ToLower(word)) {
+		return true
+	}
+	// Слово через дефис верно, если верны его части: «темно-синий»
+	if parts := strings.Split(word, "-"); len(parts) > 1 {
+		for _, part := range parts {
+			if part != "" && !c.Correct(part) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func (c *Checker) contains(word string) bool {
+	for _, d := range c.dicts {
+		if d.Contains(word) {
+			return true
+		}
+	}
+	return false
+}
+
+// Suggest предлагает исправления слова: верные слова, которые отличаются от него одной буквой
+// или перестановкой соседних букв. Заглавная первая буква сохраняется
+func (c *Checker) Suggest(word string) []string {
+	if !c.Enabled() {
+		return nil
+	}
+	runes := []rune(strings.ToLower(word))
+	capital := unicode.IsUpper([]rune(word)[0])
+	var suggestions []string
+	add := func(candidate []rune) bool {
+		s := string(candidate)
+		if slices.Contains(suggestions, s) || !c.contains(s) {
+			return false
+		}
+		suggestions = append(suggestions, s)
+		return len(suggestions) >= maxSuggestions
+	}
+	letters := c.alphabet(runes)
+	// Сначала перестановки и пропуски: такие опечатки самые частые
+	for i := 0; i+1 < len(runes); i++ {
+		candidate := slices.Clone(runes)
+		candidate[i], candidate[i+1] = candidate[i+1], candidate[i]
+		if add(candidate) {
+			return capitalize(suggestions, capital)
+		}
+	}
+	for i := range runes {
+		if add(slices.Delete(slices.Clone(runes), i, i+1)) {
+			return capitalize(suggestions, capital)
+		}
+	}
+	for i := range runes {
+		for _, r := range letters {
+			if r == runes[i] {
+				continue
+			}
+			candidate := slices.Clone(runes)
+			candidate[i] = r
+			if add(candidate) {
+				return capitalize(suggestions, capital)
+			}
+		}
+	}
+	for i := 0; i <= len(runes); i++ {
+		for _, r := range letters {
+			if add(slices.Insert(slices.Clone(runes), i, r)) {
+				return capitalize(suggestions, capital)
+			}
+		}
+	}
+	return capitalize(suggestions, capital)
+}
+
+// alphabet возвращает буквы для подсказок: из строк TRY словарей или из самого слова
+func (c *Checker) alphabet(word []rune) []rune {
+	var letters []rune
+	for _, d := range c.dicts {
+		for _, r := range strings.ToLower(d.try) {
+			if unicode.IsLetter(r) && !slices.Contains(letters, r) {
+				letters = append(letters, r)
+			}
+		}
+	}
+	for _, r := range word {
+		if !slices.Contains(letters, r) {
+			letters = append(letters, r)
+		}
+	}
+	return letters
+}
+
+func capitalize(words []string, capital bool) []string {
+	if !capital {
+		return words
+	}
+	for i, w := range words {
+		runes := []rune(w)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return words
+}
+
+// Word - слово в тексте; Start и End - номера его первого символа и символа после него
+type Word struct {
+	Text       string
+	Start, End int
+}
+
+// Words находит в тексте слова для проверки. Ссылки, адреса почты, #метки, @имена
+// и слова с цифрами пропускаются. Start и End считаются в символах, а не байтах
+func Words(text string) []Word {
+	var words []Word
+	runes := []rune(text)
+	for start := 0; start < len(runes); {
+		if unicode.IsSpace(runes[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(runes) && !unicode.IsSpace(runes[end]) {
+			end++
+		}
+		chunk := string(runes[start:end])
+		if !skipChunk(chunk) {
+			words = append(words, chunkWords(runes[start:end], start)...)
+		}
+		start = end
+	}
+	return words
+}
+
+// skipChunk сообщает, что кусок текста между пробелами - не слова: ссылка, почта, метка,
+// имя или что-то с цифрами вроде «2-й» и «v1.2»
+func skipChunk(chunk string) bool {
+	return strings.Contains(chunk, "://") || strings.HasPrefix(chunk, "www.") ||
+		strings.HasPrefix(chunk, "#") || strings.Contains(chunk, "@") || strings.ContainsAny(chunk, "0123456789")
+}
+
+// chunkWords выделяет слова из куска текста: буквы, апострофы и дефисы между буквами.
+// offset - номер первого символа куска в тексте
+func chunkWords(runes []rune, offset int) []Word {
+	var words []Word
+	inner := func(i int) bool {
+		return (runes[i] == '-' || runes[i] == '\'' || runes[i] == '’') &&
+			i > 0 && i+1 < len(runes) && unicode.IsLetter(runes[i-1]) && unicode.IsLetter(runes[i+1])
+	}
+	for start := 0; start < len(runes); {
+		if !unicode.IsLetter(runes[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(runes) && (unicode.IsLetter(runes[end]) || inner(end)) {
+			end++
+		}
+		words = append(words, Word{Text: string(runes[start:end]), Start: offset + start, End: offset + end})
+		start = end
+	}
+	return words
+}
+
+// Misspelled возвращает слова текста с ошибками
+func (c *Checker) Misspelled(text string) []Word {
+	if !c.Enabled() {
+		return nil
+	}
+	var wrong []Word
+	for _, w := range Words(text) {
+		if !c.Correct(w.Text) {
+			wrong = append(wrong, w)
+		}
+	}
+	return wrong
+}
+
+// SearchDirs возвращает папки, где обычно лежат словари Hunspell в этой системе
+func SearchDirs() []string {
+	home, _ := os.UserHomeDir()
+	switch runtime.GOOS {
+	case "windows":
+		return []string{filepath.Join(os.Getenv("APPDATA"), "hunspell")}
+	case "darwin":
+		return []string{filepath.Join(home, "Library", "Spelling"), "/Library/Spelling"}
+	}
+	return []string{
+		filepath.Join(home, ".local", "share", "hunspell"),
+		"/usr/share/hunspell",
+		"/usr/share/myspell",
+		"/usr/share/myspell/dicts",
+	}
+}
+
+// FindDictionary ищет словарь языка lang («ru_RU») в папках dirs и возвращает путь к .dic
+func FindDictionary(lang string, dirs []string) (string, bool) {
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		path := filepath.Join(dir, lang+".dic")
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+	}
+	return "", false
+}
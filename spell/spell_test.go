@@ -0,0 +1,114 @@
+package spell
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+const testAff = `SET UTF-8
+TRY оеаинтсрвлкмдпуяыьгзбчйхжшюцщэфъё
+# Окончания существительных на -а
+SFX A Y 2
+SFX A а и а
+SFX A а у [^и]а
+PFX B Y 1
+PFX B 0 пере .
+`
+
+const testDic = `4
+задача/A
+писать/B
+отчет
+банк	po:noun
+`
+
+func testDictionary(t *testing.T) *Dictionary {
+	d, err := ParseDictionary([]byte(testAff), []byte(testDic))
+	require.NoError(t, err)
+	return d
+}
+
+func TestDictionaryContains(t *testing.T) {
+	d := testDictionary(t)
+	for _, word := range []string{"задача", "задачи", "задачу", "писать", "переписать", "отчет", "банк"} {
+		assert.True(t, d.Contains(word), word)
+	}
+	for _, word := range []string{"задачо", "отчеты", "перезадача", "пере"} {
+		assert.False(t, d.Contains(word), word)
+	}
+}
+
+func TestDictionaryEncoding(t *testing.T) {
+	aff, err := charmap.KOI8R.NewEncoder().Bytes([]byte("SET KOI8-R\nSFX A Y 1\nSFX A а ы а\n"))
+	require.NoError(t, err)
+	dic, err := charmap.KOI8R.NewEncoder().Bytes([]byte("1\nработа/A\n"))
+	require.NoError(t, err)
+	d, err := ParseDictionary(aff, dic)
+	require.NoError(t, err)
+	assert.True(t, d.Contains("работы"))
+}
+
+func TestLoadDictionary(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ru_TEST.aff"), []byte(testAff), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ru_TEST.dic"), []byte(testDic), 0o644))
+	// Без .aff словарь - простой список слов
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "words.dic"), []byte("привет\nмир\n"), 0o644))
+
+	path, ok := FindDictionary("ru_TEST", []string{"", filepath.Join(dir, "missing"), dir})
+	require.True(t, ok)
+	d, err := LoadDictionary(path)
+	require.NoError(t, err)
+	assert.True(t, d.Contains("задачу"))
+
+	words, err := LoadDictionary(filepath.Join(dir, "words.dic"))
+	require.NoError(t, err)
+	assert.True(t, words.Contains("привет"))
+
+	_, ok = FindDictionary("xx_XX", []string{dir})
+	assert.False(t, ok)
+}
+
+func TestWords(t *testing.T) {
+	words := Words("Позвонить в банк #работа @Маша https://x.ru 2-й раз, кто-нибудь don't")
+	var texts []string
+	for _, w := range words {
+		texts = append(texts, w.Text)
+	}
+	assert.Equal(t, []string{"Позвонить", "в", "банк", "раз", "кто-нибудь", "don't"}, texts)
+	assert.Equal(t, Word{Text: "банк", Start: 12, End: 16}, words[2])
+}
+
+func TestChecker(t *testing.T) {
+	c := NewChecker([]*Dictionary{testDictionary(t)}, []string{"Жира"})
+	assert.True(t, c.Enabled())
+	assert.True(t, c.Correct("Задачи"))
+	assert.True(t, c.Correct("НДС"), "abbreviations are not checked")
+	assert.True(t, c.Correct("жира"), "personal dictionary")
+	assert.True(t, c.Correct("отчет-задача"))
+	assert.False(t, c.Correct("отчт-задача"))
+	assert.False(t, c.Correct("зодача"))
+
+	wrong := c.Misspelled("Зодача: отчте в банк")
+	require.Len(t, wrong, 2)
+	assert.Equal(t, "Зодача", wrong[0].Text)
+	assert.Equal(t, []string{"Задача"}, c.Suggest("Зодача"))
+	assert.Equal(t, []string{"задача", "задачи", "задачу"}, c.Suggest("задачм"))
+	assert.Equal(t, []string{"отчет"}, c.Suggest("отчте"))
+
+	c.AddWord("Отчте")
+	assert.True(t, c.Correct("отчте"))
+
+	// Без словарей проверка выключена
+	none := NewChecker(nil, nil)
+	assert.False(t, none.Enabled())
+	assert.True(t, none.Correct("зодача"))
+	assert.Nil(t, none.Suggest("зодача"))
+	var missing *Checker
+	assert.False(t, missing.Enabled())
+}
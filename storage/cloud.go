@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"sync"
+)
+
+// cloudChunkSize - размер части при загрузке файла в облако. Файл задач обычно
+// меньше, но большие списки с вложениями загружаются частями, чтобы не упереться
+// в ограничение размера одного запроса
+var cloudChunkSize = 4 << 20
+
+// cloudAPI - операции облачного сервиса с файлом задач.
+// Ревизия - непрозрачная метка версии файла; пусто - файла нет
+type cloudAPI interface {
+	// download загружает файл и его ревизию; если файла нет, возвращает fs.ErrNotExist
+	download(ctx context.Context) ([]byte, string, error)
+	// revision возвращает текущую ревизию файла
+	revision(ctx context.Context) (string, error)
+	// upload записывает файл поверх ревизии rev и возвращает новую ревизию.
+	// Если файл уже изменили, возвращает ErrModified
+	upload(ctx context.Context, data []byte, rev string) (string, error)
+	// url описывает расположение файла
+	url() string
+}
+
+// Cloud хранит файл задач в папке приложения облачного сервиса (Dropbox, Google Диск).
+// Перед перезаписью сверяет ревизию файла, поэтому изменения с другого устройства
+// не затираются молча
+type Cloud struct {
+	api cloudAPI
+
+	mu  sync.Mutex
+	rev string // Ревизия, прочитанная или записанная последней
+}
+
+// URL возвращает расположение файла
+func (c *Cloud) URL() string {
+	return c.api.url()
+}
+
+// Read загружает файл и запоминает его ревизию
+func (c *Cloud) Read() ([]byte, error) {
+	data, rev, err := c.api.download(context.Background())
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.rev = rev
+	c.mu.Unlock()
+	return data, err
+}
+
+// Write загружает файл. Если файл изменили после последнего чтения, возвращает ErrModified
+func (c *Cloud) Write(data []byte) error {
+	c.mu.Lock()
+	rev := c.rev
+	c.mu.Unlock()
+
+	// Сервис может не проверять ревизию при загрузке сам, поэтому сверяем ее заранее
+	current, err := c.api.revision(context.Background())
+	if err != nil {
+		return err
+	}
+	if current != rev {
+		return ErrModified
+	}
+
+	rev, err = c.api.upload(context.Background(), data, rev)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.rev = rev
+	c.mu.Unlock()
+	return nil
+}
+
+// Modified сообщает, изменился ли файл в облаке после последнего чтения или записи
+func (c *Cloud) Modified(ctx context.Context) (bool, error) {
+	rev, err := c.api.revision(ctx)
+	if err != nil {
+		return false, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return rev != c.rev, nil
+}
@@ -0,0 +1,265 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDropbox - Dropbox API с одним файлом и сессиями загрузки
+type fakeDropbox struct {
+	mu       sync.Mutex
+	data     []byte
+	rev      int
+	sessions map[string][]byte
+	requests int // Сколько частей пришло в сессиях загрузки
+}
+
+// put заменяет файл, как это сделало бы другое устройство
+func (s *fakeDropbox) put(data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = data
+	s.rev++
+}
+
+func (s *fakeDropbox) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var arg struct {
+		Path   string          `json:"path"`
+		Mode   json.RawMessage `json:"mode"`
+		Cursor struct {
+			SessionID string `json:"session_id"`
+			Offset    int    `json:"offset"`
+		} `json:"cursor"`
+		Commit struct {
+			Mode json.RawMessage `json:"mode"`
+		} `json:"commit"`
+	}
+	json.Unmarshal([]byte(r.Header.Get("Dropbox-API-Arg")), &arg)
+	body, _ := io.ReadAll(r.Body)
+	fail := func(summary string) {
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprintf(w, `{"error_summary":%q}`, summary)
+	}
+	commit := func(mode json.RawMessage, data []byte) {
+		var update struct {
+			Update string `json:"update"`
+		}
+		json.Unmarshal(mode, &update)
+		switch {
+		case string(mode) == `"add"` && s.rev > 0, update.Update != "" && update.Update != strconv.Itoa(s.rev):
+			fail("path/conflict/file/..")
+			return
+		}
+		s.data = data
+		s.rev++
+		fmt.Fprintf(w, `{"rev":"%d"}`, s.rev)
+	}
+
+	switch r.URL.Path {
+	case "/files/get_metadata", "/files/download":
+		if r.URL.Path == "/files/get_metadata" {
+			json.Unmarshal(body, &arg)
+		}
+		if s.rev == 0 {
+			fail("path/not_found/..")
+			return
+		}
+		if r.URL.Path == "/files/download" {
+			w.Header().Set("Dropbox-API-Result", fmt.Sprintf(`{"rev":"%d"}`, s.rev))
+			w.Write(s.data)
+			return
+		}
+		fmt.Fprintf(w, `{"rev":"%d"}`, s.rev)
+	case "/files/upload":
+		commit(arg.Mode, body)
+	case "/files/upload_session/start":
+		id := strconv.Itoa(len(s.sessions) + 1)
+		s.sessions[id] = body
+		s.requests++
+		fmt.Fprintf(w, `{"session_id":%q}`, id)
+	case "/files/upload_session/append_v2", "/files/upload_session/finish":
+		data := s.sessions[arg.Cursor.SessionID]
+		if arg.Cursor.Offset != len(data) {
+			fail("incorrect_offset")
+			return
+		}
+		s.sessions[arg.Cursor.SessionID] = append(data, body...)
+		s.requests++
+		if r.URL.Path == "/files/upload_session/finish" {
+			commit(arg.Commit.Mode, s.sessions[arg.Cursor.SessionID])
+			return
+		}
+		w.Write([]byte("null"))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestDropbox(t *testing.T) (*Cloud, *fakeDropbox) {
+	server := &fakeDropbox{sessions: make(map[string][]byte)}
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+	c := NewDropbox(ts.Client(), "задачи.json")
+	c.api.(*dropbox).apiURL = ts.URL
+	c.api.(*dropbox).contentURL = ts.URL
+	return c, server
+}
+
+func TestDropbox(t *testing.T) {
+	c, server := newTestDropbox(t)
+	assert.Equal(t, "dropbox:/задачи.json", c.URL())
+
+	_, err := c.Read()
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.NoError(t, c.Write([]byte("first")))
+	data, err := c.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, "first", string(data))
+
+	// Файл изменили с другого устройства - запись не затирает его
+	server.put([]byte("other"))
+	modified, err := c.Modified(t.Context())
+	assert.NoError(t, err)
+	assert.True(t, modified)
+	assert.ErrorIs(t, c.Write([]byte("second")), ErrModified)
+
+	_, err = c.Read()
+	assert.NoError(t, err)
+	assert.NoError(t, c.Write([]byte("second")))
+	assert.Equal(t, "second", string(server.data))
+}
+
+func TestDropboxChunkedUpload(t *testing.T) {
+	cloudChunkSize = 4
+	defer func() { cloudChunkSize = 4 << 20 }()
+
+	c, server := newTestDropbox(t)
+	_, err := c.Read()
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.NoError(t, c.Write([]byte("0123456789")))
+	assert.Equal(t, "0123456789", string(server.data))
+	assert.Equal(t, 3, server.requests)
+}
+
+func TestDropboxArg(t *testing.T) {
+	arg := dropboxArg(map[string]any{"path": "/задачи 😀.json"})
+	assert.Equal(t, `{"path":"/\u0437\u0430\u0434\u0430\u0447\u0438 \ud83d\ude00.json"}`, arg)
+
+	var decoded map[string]string
+	assert.NoError(t, json.Unmarshal([]byte(arg), &decoded))
+	assert.Equal(t, "/задачи 😀.json", decoded["path"])
+}
+
+// fakeDrive - Google Drive API с папкой приложения и возобновляемой загрузкой
+type fakeDrive struct {
+	mu      sync.Mutex
+	files   map[string]*driveFile
+	data    map[string][]byte
+	uploads map[string]string // Сессия загрузки -> идентификатор файла
+	chunks  int
+}
+
+// put заменяет содержимое файла, как это сделало бы другое устройство
+func (s *fakeDrive) put(id string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[id] = data
+	version, _ := strconv.Atoi(s.files[id].Version)
+	s.files[id].Version = strconv.Itoa(version + 1)
+}
+
+func (s *fakeDrive) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path := r.URL.Path
+	switch {
+	case r.Method == http.MethodGet && path == "/files":
+		var files []driveFile
+		for _, file := range s.files {
+			if strings.Contains(r.URL.Query().Get("q"), "'tasks.json'") {
+				files = append(files, *file)
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"files": files})
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/files/"):
+		w.Write(s.data[strings.TrimPrefix(path, "/files/")])
+	case r.Method == http.MethodPost && path == "/upload/files":
+		id := strconv.Itoa(len(s.files) + 1)
+		s.files[id] = &driveFile{ID: id, Version: "0"}
+		s.startUpload(w, id)
+	case r.Method == http.MethodPatch && strings.HasPrefix(path, "/upload/files/"):
+		s.startUpload(w, strings.TrimPrefix(path, "/upload/files/"))
+	case r.Method == http.MethodPut && strings.HasPrefix(path, "/session/"):
+		id := s.uploads[path]
+		body, _ := io.ReadAll(r.Body)
+		var start, end, total int
+		fmt.Sscanf(r.Header.Get("Content-Range"), "bytes %d-%d/%d", &start, &end, &total)
+		s.data[id] = append(s.data[id][:start], body...)
+		s.chunks++
+		if end+1 < total {
+			w.WriteHeader(http.StatusPermanentRedirect)
+			return
+		}
+		version, _ := strconv.Atoi(s.files[id].Version)
+		s.files[id].Version = strconv.Itoa(version + 1)
+		json.NewEncoder(w).Encode(s.files[id])
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *fakeDrive) startUpload(w http.ResponseWriter, id string) {
+	session := "/session/" + strconv.Itoa(len(s.uploads)+1)
+	s.uploads[session] = id
+	s.data[id] = nil
+	w.Header().Set("Location", "http://"+w.Header().Get("X-Host")+session)
+}
+
+func TestGoogleDrive(t *testing.T) {
+	cloudChunkSize = 4
+	defer func() { cloudChunkSize = 4 << 20 }()
+
+	server := &fakeDrive{files: make(map[string]*driveFile), data: make(map[string][]byte), uploads: make(map[string]string)}
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Host", strings.TrimPrefix(ts.URL, "http://"))
+		server.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+	c := NewGoogleDrive(ts.Client(), "tasks.json")
+	c.api.(*googleDrive).apiURL = ts.URL
+	c.api.(*googleDrive).uploadURL = ts.URL + "/upload"
+
+	_, err := c.Read()
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.NoError(t, c.Write([]byte("0123456789")))
+	assert.Equal(t, 3, server.chunks)
+	data, err := c.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, "0123456789", string(data))
+
+	// Новая версия записывается в тот же файл
+	assert.NoError(t, c.Write([]byte("second")))
+	assert.Len(t, server.files, 1)
+	assert.Equal(t, "second", string(server.data["1"]))
+
+	// Файл изменили с другого устройства - запись не затирает его
+	server.put("1", []byte("other"))
+	modified, err := c.Modified(t.Context())
+	assert.NoError(t, err)
+	assert.True(t, modified)
+	assert.ErrorIs(t, c.Write([]byte("third")), ErrModified)
+	assert.Equal(t, "other", string(server.data["1"]))
+}
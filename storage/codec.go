@@ -0,0 +1,251 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Codec переводит файл задач из JSON в формат, в котором он лежит на диске, и обратно
+type Codec interface {
+	// Name - название формата для настроек: json, yaml, toml
+	Name() string
+	// Encode переводит JSON в формат на диске
+	Encode(data []byte) ([]byte, error)
+	// Decode переводит данные с диска в JSON
+	Decode(data []byte) ([]byte, error)
+}
+
+// Codecs - форматы файла задач; JSON - без перевода
+var Codecs = []Codec{JSONCodec{}, YAMLCodec{}, TOMLCodec{}}
+
+// CodecByName возвращает формат по названию или nil
+func CodecByName(name string) Codec {
+	for _, c := range Codecs {
+		if c.Name() == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// JSONCodec хранит файл как есть
+type JSONCodec struct{}
+
+func (JSONCodec) Name() string                       { return "json" }
+func (JSONCodec) Encode(data []byte) ([]byte, error) { return data, nil }
+func (JSONCodec) Decode(data []byte) ([]byte, error) { return data, nil }
+
+// YAMLCodec хранит файл в YAML: в нем удобно смотреть изменения в git.
+// Поля идут в том же порядке, что и в JSON
+type YAMLCodec struct{}
+
+func (YAMLCodec) Name() string { return "yaml" }
+
+func (YAMLCodec) Encode(data []byte) ([]byte, error) {
+	// JSON - частный случай YAML, поэтому разбор сохраняет порядок полей.
+	// Сброс стиля превращает записи в фигурных скобках в обычные блоки YAML
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	var reset func(node *yaml.Node)
+	reset = func(node *yaml.Node) {
+		node.Style = 0
+		for _, child := range node.Content {
+			reset(child)
+		}
+	}
+	reset(&doc)
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func (YAMLCodec) Decode(data []byte) ([]byte, error) {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid yaml: %w", err)
+	}
+	return json.Marshal(doc)
+}
+
+// TOMLCodec хранит файл в TOML. В TOML нет null, поэтому пустые значения не записываются
+type TOMLCodec struct{}
+
+func (TOMLCodec) Name() string { return "toml" }
+
+func (TOMLCodec) Encode(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	table, ok := tomlValue(doc).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("toml needs an object at the top level")
+	}
+	var out bytes.Buffer
+	encoder := toml.NewEncoder(&out)
+	encoder.Indent = ""
+	if err := encoder.Encode(table); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func (TOMLCodec) Decode(data []byte) ([]byte, error) {
+	var doc map[string]any
+	if _, err := toml.Decode(string(data), &doc); err != nil {
+		return nil, fmt.Errorf("invalid toml: %w", err)
+	}
+	return json.Marshal(doc)
+}
+
+// tomlValue готовит разобранный JSON к записи в TOML: убирает null
+// и записывает целые числа целыми, а не дробными
+func tomlValue(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, item := range value {
+			if item == nil {
+				delete(value, key)
+				continue
+			}
+			value[key] = tomlValue(item)
+		}
+	case []any:
+		for i, item := range value {
+			value[i] = tomlValue(item)
+		}
+	case json.Number:
+		if n, err := value.Int64(); err == nil {
+			return n
+		}
+		f, _ := value.Float64()
+		return f
+	}
+	return value
+}
+
+// Encoded хранит данные в другом формате: пишет их через Codec и читает обратно в JSON.
+// Файл, который еще лежит в JSON, читается как есть и переводится при следующей записи.
+// Резервные копии и блокировка записи остаются у исходного хранилища
+type Encoded struct {
+	Storage
+	codec Codec
+
+	mu sync.Mutex
+	// Последние записанные или прочитанные данные: прочитанный файл совпадает
+	// с записанным байт в байт, иначе перевод туда и обратно выглядел бы как изменение файла
+	lastRaw, lastJSON []byte
+}
+
+// NewEncoded создает хранилище, которое хранит данные store в формате codec
+func NewEncoded(store Storage, codec Codec) *Encoded {
+	return &Encoded{Storage: store, codec: codec}
+}
+
+// Read читает данные и переводит их в JSON
+func (e *Encoded) Read() ([]byte, error) {
+	raw, err := e.Storage.Read()
+	if err != nil {
+		return nil, err
+	}
+	return e.decode(raw)
+}
+
+func (e *Encoded) decode(raw []byte) ([]byte, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.lastRaw != nil && bytes.Equal(raw, e.lastRaw) {
+		return e.lastJSON, nil
+	}
+	data := raw
+	if !json.Valid(raw) {
+		decoded, err := e.codec.Decode(raw)
+		if err != nil {
+			return nil, err
+		}
+		data = decoded
+	}
+	e.lastRaw, e.lastJSON = raw, data
+	return data, nil
+}
+
+// Write переводит JSON в формат хранилища и записывает
+func (e *Encoded) Write(data []byte) error {
+	raw, err := e.codec.Encode(data)
+	if err != nil {
+		return err
+	}
+	if err := e.Storage.Write(raw); err != nil {
+		return err
+	}
+	e.mu.Lock()
+	e.lastRaw, e.lastJSON = raw, data
+	e.mu.Unlock()
+	return nil
+}
+
+// Backup сохраняет резервную копию, если исходное хранилище это умеет
+func (e *Encoded) Backup(keep int) error {
+	if backuper, ok := e.Storage.(Backuper); ok {
+		return backuper.Backup(keep)
+	}
+	return nil
+}
+
+// Backups возвращает резервные копии исходного хранилища
+func (e *Encoded) Backups() ([]Backup, error) {
+	if backuper, ok := e.Storage.(Backuper); ok {
+		return backuper.Backups()
+	}
+	return nil, nil
+}
+
+// ReadBackup читает резервную копию и переводит ее в JSON
+func (e *Encoded) ReadBackup(path string) ([]byte, error) {
+	backuper, ok := e.Storage.(Backuper)
+	if !ok {
+		return nil, fmt.Errorf("storage has no backups")
+	}
+	raw, err := backuper.ReadBackup(path)
+	if err != nil || json.Valid(raw) {
+		return raw, err
+	}
+	return e.codec.Decode(raw)
+}
+
+// WriteBackup переводит JSON в формат хранилища и перезаписывает резервную копию
+func (e *Encoded) WriteBackup(path string, data []byte) error {
+	backuper, ok := e.Storage.(Backuper)
+	if !ok {
+		return fmt.Errorf("storage has no backups")
+	}
+	raw, err := e.codec.Encode(data)
+	if err != nil {
+		return err
+	}
+	return backuper.WriteBackup(path, raw)
+}
+
+// Lock захватывает блокировку записи исходного хранилища, если она есть
+func (e *Encoded) Lock() (unlock func() error, err error) {
+	if locker, ok := e.Storage.(Locker); ok {
+		return locker.Lock()
+	}
+	return func() error { return nil }, nil
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testTasksFile - файл задач в том виде, в каком его пишет приложение
+const testTasksFile = `{
+  "version": 3,
+  "tasks": [
+    {
+      "id": 1,
+      "title": "123",
+      "description": "Первая строка\nвторая",
+      "due_date": "2026-10-16T00:00:00+03:00",
+      "completed": false,
+      "tags": ["yes", "дом"],
+      "time_spent": 5400000000000,
+      "checklist": [{"text": "Купить", "done": true}]
+    }
+  ]
+}`
+
+func TestCodecs(t *testing.T) {
+	for _, codec := range Codecs {
+		t.Run(codec.Name(), func(t *testing.T) {
+			encoded, err := codec.Encode([]byte(testTasksFile))
+			require.NoError(t, err)
+			decoded, err := codec.Decode(encoded)
+			require.NoError(t, err)
+			assert.JSONEq(t, testTasksFile, string(decoded))
+			assert.Equal(t, codec, CodecByName(codec.Name()))
+		})
+	}
+	assert.Nil(t, CodecByName("xml"))
+
+	// В YAML поля идут в порядке JSON, а строки, похожие на числа и логические значения, в кавычках
+	encoded, err := YAMLCodec{}.Encode([]byte(testTasksFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), "version: 3\ntasks:\n  - id: 1\n    title: \"123\"\n")
+}
+
+func TestEncoded(t *testing.T) {
+	file := NewFile(filepath.Join(t.TempDir(), "tasks.json"))
+	require.NoError(t, file.Write([]byte(testTasksFile)))
+
+	// Файл в JSON читается как есть и переводится в YAML при записи
+	store := NewEncoded(file, YAMLCodec{})
+	data, err := store.Read()
+	require.NoError(t, err)
+	assert.Equal(t, testTasksFile, string(data))
+
+	require.NoError(t, store.Backup(2))
+	require.NoError(t, store.Write(data))
+	raw, err := os.ReadFile(file.Path())
+	require.NoError(t, err)
+	assert.False(t, json.Valid(raw))
+
+	// Прочитанное совпадает с записанным байт в байт, иначе файл считался бы измененным
+	again, err := store.Read()
+	require.NoError(t, err)
+	assert.Equal(t, testTasksFile, string(again))
+
+	// Файл, измененный вручную, переводится обратно в JSON
+	require.NoError(t, os.WriteFile(file.Path(), append(raw, []byte("# комментарий\n")...), 0644))
+	edited, err := store.Read()
+	require.NoError(t, err)
+	assert.JSONEq(t, testTasksFile, string(edited))
+
+	backups, err := store.Backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	backup, err := store.ReadBackup(backups[0].Path)
+	require.NoError(t, err)
+	assert.Equal(t, testTasksFile, string(backup))
+
+	unlock, err := store.Lock()
+	require.NoError(t, err)
+	assert.NoError(t, unlock())
+}
@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// encryptedFormat - признак зашифрованного файла
+const encryptedFormat = "taskmanager-encrypted"
+
+// Параметры argon2id для получения ключа из пароля
+const (
+	kdfTime    = 1
+	kdfMemory  = 64 * 1024
+	kdfThreads = 4
+	kdfKeyLen  = 32
+
+	// SaltLen - длина соли для DeriveKey
+	SaltLen = 16
+)
+
+// ErrWrongPassphrase возвращается, если данные не удалось расшифровать указанным паролем
+var ErrWrongPassphrase = errors.New("wrong passphrase")
+
+// encryptedFile - содержимое зашифрованного файла
+type encryptedFile struct {
+	Format     string `json:"format"`
+	KDF        string `json:"kdf"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// DeriveKey получает ключ AES-256 из пароля
+func DeriveKey(passphrase string, salt []byte) []byte {
+	return argon2.IDKey([]byte(passphrase), salt, kdfTime, kdfMemory, kdfThreads, kdfKeyLen)
+}
+
+// newGCM создает шифр AES-GCM для ключа
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Encrypt шифрует данные паролем. Соль и nonce генерируются заново при каждой записи
+func Encrypt(plaintext []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(DeriveKey(passphrase, salt))
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(encryptedFile{
+		Format:     encryptedFormat,
+		KDF:        "argon2id",
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	}, "", "  ")
+}
+
+// Decrypt расшифровывает данные, записанные Encrypt
+func Decrypt(data []byte, passphrase string) ([]byte, error) {
+	var file encryptedFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(DeriveKey(passphrase, file.Salt))
+	if err != nil {
+		return nil, err
+	}
+	if len(file.Nonce) != gcm.NonceSize() {
+		return nil, errors.New("invalid encrypted file")
+	}
+
+	plaintext, err := gcm.Open(nil, file.Nonce, file.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plaintext, nil
+}
+
+// IsEncrypted проверяет, записаны ли данные функцией Encrypt
+func IsEncrypted(data []byte) bool {
+	var file struct {
+		Format string `json:"format"`
+	}
+	return json.Unmarshal(data, &file) == nil && file.Format == encryptedFormat
+}
@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	plaintext := []byte(`[{"title":"Секретная задача"}]`)
+
+	data, err := Encrypt(plaintext, "correct horse")
+	assert.NoError(t, err)
+	assert.True(t, IsEncrypted(data))
+	assert.NotContains(t, string(data), "Секретная")
+
+	// Правильный пароль возвращает исходные данные
+	decrypted, err := Decrypt(data, "correct horse")
+	assert.NoError(t, err)
+	assert.Equal(t, plaintext, decrypted)
+
+	// Неверный пароль
+	_, err = Decrypt(data, "wrong")
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+
+	// Обычный файл задач не считается зашифрованным
+	assert.False(t, IsEncrypted(plaintext))
+}
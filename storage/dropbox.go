@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"strings"
+	"unicode/utf16"
+)
+
+// DropboxOAuth возвращает настройки входа в Dropbox для приложения с ключом appKey.
+// Приложению нужен доступ только к своей папке (App folder)
+func DropboxOAuth(appKey string) OAuthConfig {
+	return OAuthConfig{
+		ClientID:   appKey,
+		AuthURL:    "https://www.dropbox.com/oauth2/authorize",
+		TokenURL:   "https://api.dropboxapi.com/oauth2/token",
+		AuthParams: map[string][]string{"token_access_type": {"offline"}},
+	}
+}
+
+// NewDropbox создает хранилище в файле name папки приложения Dropbox.
+// client должен авторизовать запросы, см. NewOAuthClient
+func NewDropbox(client *http.Client, name string) *Cloud {
+	return &Cloud{api: &dropbox{
+		client:     client,
+		path:       "/" + name,
+		apiURL:     "https://api.dropboxapi.com/2",
+		contentURL: "https://content.dropboxapi.com/2",
+	}}
+}
+
+// dropbox работает с файлом через Dropbox API v2
+type dropbox struct {
+	client     *http.Client
+	path       string
+	apiURL     string
+	contentURL string
+}
+
+func (d *dropbox) url() string {
+	return "dropbox:" + d.path
+}
+
+// dropboxFile - метаданные файла в ответах Dropbox
+type dropboxFile struct {
+	Rev string `json:"rev"`
+}
+
+func (d *dropbox) download(ctx context.Context) ([]byte, string, error) {
+	resp, err := d.call(ctx, d.contentURL+"/files/download", map[string]any{"path": d.path}, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	var file dropboxFile
+	if err := json.Unmarshal([]byte(resp.Header.Get("Dropbox-API-Result")), &file); err != nil {
+		return nil, "", fmt.Errorf("dropbox download: %w", err)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, file.Rev, nil
+}
+
+func (d *dropbox) revision(ctx context.Context) (string, error) {
+	body, _ := json.Marshal(map[string]any{"path": d.path})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.apiURL+"/files/get_metadata", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var file dropboxFile
+	err = d.decode(req, &file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	return file.Rev, err
+}
+
+func (d *dropbox) upload(ctx context.Context, data []byte, rev string) (string, error) {
+	// Запись только поверх прочитанной ревизии; если файла не было - только новым файлом
+	commit := map[string]any{"path": d.path, "mode": "add", "autorename": false}
+	if rev != "" {
+		commit["mode"] = map[string]any{".tag": "update", "update": rev}
+	}
+	if len(data) <= cloudChunkSize {
+		return d.commit(ctx, d.contentURL+"/files/upload", commit, data)
+	}
+
+	// Большой файл загружается сессией: первая часть, промежуточные части и последняя вместе с фиксацией
+	var session struct {
+		SessionID string `json:"session_id"`
+	}
+	resp, err := d.call(ctx, d.contentURL+"/files/upload_session/start", map[string]any{"close": false}, data[:cloudChunkSize])
+	if err != nil {
+		return "", err
+	}
+	err = json.NewDecoder(resp.Body).Decode(&session)
+	resp.Body.Close()
+	if err != nil {
+		return "", err
+	}
+
+	offset := cloudChunkSize
+	for len(data)-offset > cloudChunkSize {
+		cursor := map[string]any{"session_id": session.SessionID, "offset": offset}
+		resp, err := d.call(ctx, d.contentURL+"/files/upload_session/append_v2", map[string]any{"cursor": cursor}, data[offset:offset+cloudChunkSize])
+		if err != nil {
+			return "", err
+		}
+		resp.Body.Close()
+		offset += cloudChunkSize
+	}
+	cursor := map[string]any{"session_id": session.SessionID, "offset": offset}
+	return d.commit(ctx, d.contentURL+"/files/upload_session/finish", map[string]any{"cursor": cursor, "commit": commit}, data[offset:])
+}
+
+// commit выполняет запрос, создающий новую версию файла, и возвращает ее ревизию
+func (d *dropbox) commit(ctx context.Context, url string, arg map[string]any, data []byte) (string, error) {
+	resp, err := d.call(ctx, url, arg, data)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var file dropboxFile
+	if err := json.NewDecoder(resp.Body).Decode(&file); err != nil {
+		return "", err
+	}
+	return file.Rev, nil
+}
+
+// call выполняет запрос к content-адресу Dropbox: параметры передаются в заголовке, данные - в теле
+func (d *dropbox) call(ctx context.Context, url string, arg map[string]any, data []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Dropbox-API-Arg", dropboxArg(arg))
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, d.apiError(resp)
+	}
+	return resp, nil
+}
+
+// decode выполняет запрос и разбирает ответ в JSON
+func (d *dropbox) decode(req *http.Request, v any) error {
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return d.apiError(resp)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// apiError переводит ошибку Dropbox в ошибки пакета: not_found - fs.ErrNotExist, conflict - ErrModified
+func (d *dropbox) apiError(resp *http.Response) error {
+	if resp.StatusCode != http.StatusConflict {
+		return statusError(resp)
+	}
+	var body struct {
+		ErrorSummary string `json:"error_summary"`
+	}
+	json.NewDecoder(resp.Body).Decode(&body)
+	switch {
+	case strings.Contains(body.ErrorSummary, "not_found"):
+		return &fs.PathError{Op: "download", Path: d.url(), Err: fs.ErrNotExist}
+	case strings.Contains(body.ErrorSummary, "conflict"):
+		return ErrModified
+	}
+	return fmt.Errorf("dropbox: %s", body.ErrorSummary)
+}
+
+// dropboxArg кодирует параметры для заголовка Dropbox-API-Arg. Заголовок допускает
+// только ASCII, поэтому остальные символы (например, в кириллическом имени файла) экранируются
+func dropboxArg(arg map[string]any) string {
+	data, _ := json.Marshal(arg)
+	var b strings.Builder
+	for _, r := range string(data) {
+		switch {
+		case r < 0x80:
+			b.WriteRune(r)
+		case r > 0xFFFF:
+			r1, r2 := utf16.EncodeRune(r)
+			fmt.Fprintf(&b, `\u%04x\u%04x`, r1, r2)
+		default:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// GoogleDriveOAuth возвращает настройки входа в Google Диск для приложения clientID.
+// Приложению нужен доступ только к своей скрытой папке (appDataFolder)
+func GoogleDriveOAuth(clientID, clientSecret string) OAuthConfig {
+	return OAuthConfig{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		AuthURL:      "https://accounts.google.com/o/oauth2/v2/auth",
+		TokenURL:     "https://oauth2.googleapis.com/token",
+		Scopes:       []string{"https://www.googleapis.com/auth/drive.appdata"},
+		AuthParams:   map[string][]string{"access_type": {"offline"}, "prompt": {"consent"}},
+	}
+}
+
+// NewGoogleDrive создает хранилище в файле name папки приложения Google Диска.
+// client должен авторизовать запросы, см. NewOAuthClient
+func NewGoogleDrive(client *http.Client, name string) *Cloud {
+	return &Cloud{api: &googleDrive{
+		client:    client,
+		name:      name,
+		apiURL:    "https://www.googleapis.com/drive/v3",
+		uploadURL: "https://www.googleapis.com/upload/drive/v3",
+	}}
+}
+
+// googleDrive работает с файлом через Google Drive API v3. Ревизия - номер версии файла
+type googleDrive struct {
+	client    *http.Client
+	name      string
+	apiURL    string
+	uploadURL string
+
+	mu sync.Mutex
+	id string // Идентификатор файла, найденного последним
+}
+
+// driveFile - метаданные файла в ответах Google Диска
+type driveFile struct {
+	ID      string `json:"id"`
+	Version string `json:"version"`
+}
+
+func (g *googleDrive) url() string {
+	return "gdrive:appDataFolder/" + g.name
+}
+
+// find ищет файл по имени и запоминает его идентификатор; если файла нет, возвращает пустой driveFile
+func (g *googleDrive) find(ctx context.Context) (driveFile, error) {
+	query := url.Values{
+		"spaces":  {"appDataFolder"},
+		"q":       {fmt.Sprintf("name = '%s' and trashed = false", strings.ReplaceAll(g.name, "'", `\'`))},
+		"fields":  {"files(id,version)"},
+		"orderBy": {"modifiedTime desc"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.apiURL+"/files?"+query.Encode(), nil)
+	if err != nil {
+		return driveFile{}, err
+	}
+	var list struct {
+		Files []driveFile `json:"files"`
+	}
+	if err := g.decode(req, &list); err != nil {
+		return driveFile{}, err
+	}
+
+	var file driveFile
+	if len(list.Files) > 0 {
+		file = list.Files[0]
+	}
+	g.mu.Lock()
+	g.id = file.ID
+	g.mu.Unlock()
+	return file, nil
+}
+
+func (g *googleDrive) download(ctx context.Context) ([]byte, string, error) {
+	file, err := g.find(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	if file.ID == "" {
+		return nil, "", &fs.PathError{Op: "download", Path: g.url(), Err: fs.ErrNotExist}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.apiURL+"/files/"+file.ID+"?alt=media", nil)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", statusError(resp)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, file.Version, nil
+}
+
+func (g *googleDrive) revision(ctx context.Context) (string, error) {
+	file, err := g.find(ctx)
+	return file.Version, err
+}
+
+// upload загружает файл сессией возобновляемой загрузки, частями по cloudChunkSize.
+// Google Диск не сверяет версию при загрузке, поэтому ее проверяет Cloud.Write перед вызовом
+func (g *googleDrive) upload(ctx context.Context, data []byte, rev string) (string, error) {
+	g.mu.Lock()
+	id := g.id
+	g.mu.Unlock()
+
+	// Сессия создается для нового файла или для новой версии найденного
+	method, target, metadata := http.MethodPost, g.uploadURL+"/files", `{"name":`+quoteJSON(g.name)+`,"parents":["appDataFolder"]}`
+	if rev != "" && id != "" {
+		method, target, metadata = http.MethodPatch, g.uploadURL+"/files/"+id, `{}`
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target+"?uploadType=resumable&fields=id,version", strings.NewReader(metadata))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", statusError(resp)
+	}
+	session := resp.Header.Get("Location")
+	if session == "" {
+		return "", fmt.Errorf("google drive: upload session has no location")
+	}
+
+	offset := 0
+	for {
+		end := min(offset+cloudChunkSize, len(data))
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, session, bytes.NewReader(data[offset:end]))
+		if err != nil {
+			return "", err
+		}
+		if len(data) == 0 {
+			req.Header.Set("Content-Range", "bytes */0")
+		} else {
+			req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, end-1, len(data)))
+		}
+		resp, err := g.client.Do(req)
+		if err != nil {
+			return "", err
+		}
+
+		// 308 - часть принята, сервер ждет следующую; 200 и 201 - файл загружен
+		if resp.StatusCode == http.StatusPermanentRedirect && end < len(data) {
+			resp.Body.Close()
+			offset = end
+			continue
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+			return "", statusError(resp)
+		}
+		var file driveFile
+		if err := json.NewDecoder(resp.Body).Decode(&file); err != nil {
+			return "", err
+		}
+		g.mu.Lock()
+		g.id = file.ID
+		g.mu.Unlock()
+		return file.Version, nil
+	}
+}
+
+// decode выполняет запрос и разбирает ответ в JSON
+func (g *googleDrive) decode(req *http.Request, v any) error {
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return statusError(resp)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// quoteJSON кодирует строку как значение JSON
+func quoteJSON(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
@@ -0,0 +1,274 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sealedFormat - признак данных, зашифрованных ключом сквозного шифрования
+const sealedFormat = "taskmanager-sealed"
+
+var (
+	// ErrNoKey возвращается при шифровании, пока ключ сквозного шифрования не создан
+	ErrNoKey = errors.New("end-to-end encryption key is not set up")
+	// ErrUnknownKey возвращается, если данные зашифрованы ключом, которого нет на устройстве
+	ErrUnknownKey = errors.New("data is encrypted with an unknown key, enter its recovery phrase")
+	// ErrInvalidRecoveryPhrase возвращается для фразы восстановления с опечаткой
+	ErrInvalidRecoveryPhrase = errors.New("invalid recovery phrase")
+)
+
+// SyncKey - ключ сквозного шифрования. ID вычисляется из ключа, поэтому одинаков на всех устройствах
+type SyncKey struct {
+	ID      string    `json:"id"`
+	Secret  []byte    `json:"secret"`
+	Created time.Time `json:"created"`
+}
+
+// newSyncKey создает ключ из 32 байт secret
+func newSyncKey(secret []byte) SyncKey {
+	sum := sha256.Sum256(secret)
+	return SyncKey{ID: hex.EncodeToString(sum[:4]), Secret: secret, Created: time.Now()}
+}
+
+// Keyring - ключи сквозного шифрования устройства. Данные шифруются текущим (первым) ключом,
+// а прежние ключи после смены остаются, чтобы читать еще не перешифрованные данные.
+// Безопасен для вызова из нескольких горутин
+type Keyring struct {
+	mu   sync.Mutex
+	keys []SyncKey
+}
+
+// sealedData - содержимое зашифрованных данных
+type sealedData struct {
+	Format     string `json:"format"`
+	Key        string `json:"key"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// ParseKeyring читает ключи, записанные Keyring.Marshal; пустая строка - ключей нет
+func ParseKeyring(data string) (*Keyring, error) {
+	k := &Keyring{}
+	if data == "" {
+		return k, nil
+	}
+	if err := json.Unmarshal([]byte(data), &k.keys); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Marshal записывает ключи для хранения на устройстве
+func (k *Keyring) Marshal() string {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	data, _ := json.Marshal(k.keys)
+	return string(data)
+}
+
+// Empty сообщает, что ключ еще не создан
+func (k *Keyring) Empty() bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return len(k.keys) == 0
+}
+
+// CurrentID возвращает идентификатор текущего ключа; пусто - ключа нет
+func (k *Keyring) CurrentID() string {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if len(k.keys) == 0 {
+		return ""
+	}
+	return k.keys[0].ID
+}
+
+// Generate создает новый случайный ключ и делает его текущим. Используется при первой
+// настройке и для смены ключа: прежний остается для чтения старых данных
+func (k *Keyring) Generate() error {
+	secret := make([]byte, kdfKeyLen)
+	if _, err := rand.Read(secret); err != nil {
+		return err
+	}
+	k.use(newSyncKey(secret))
+	return nil
+}
+
+// Import делает текущим ключ из фразы восстановления, полученной на другом устройстве
+func (k *Keyring) Import(phrase string) error {
+	secret, err := parseRecoveryPhrase(phrase)
+	if err != nil {
+		return err
+	}
+	k.use(newSyncKey(secret))
+	return nil
+}
+
+// use делает ключ текущим; если он уже есть, переносит его в начало
+func (k *Keyring) use(key SyncKey) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	keys := []SyncKey{key}
+	for _, existing := range k.keys {
+		if existing.ID != key.ID {
+			keys = append(keys, existing)
+		}
+	}
+	k.keys = keys
+}
+
+// RecoveryPhrase возвращает фразу восстановления текущего ключа: по ней ключ вводят
+// на другом устройстве или после переустановки. Пусто - ключа нет
+func (k *Keyring) RecoveryPhrase() string {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if len(k.keys) == 0 {
+		return ""
+	}
+	secret := k.keys[0].Secret
+	sum := sha256.Sum256(secret)
+	encoded := recoveryEncoding.EncodeToString(append(append([]byte(nil), secret...), sum[:2]...))
+
+	var groups []string
+	for len(encoded) > 0 {
+		n := min(4, len(encoded))
+		groups = append(groups, encoded[:n])
+		encoded = encoded[n:]
+	}
+	return strings.Join(groups, "-")
+}
+
+// recoveryEncoding - base32 без похожих букв и цифр (Crockford), фраза не зависит от регистра
+var recoveryEncoding = base32.NewEncoding("0123456789ABCDEFGHJKMNPQRSTVWXYZ").WithPadding(base32.NoPadding)
+
+// parseRecoveryPhrase проверяет контрольную сумму фразы и возвращает ключ
+func parseRecoveryPhrase(phrase string) ([]byte, error) {
+	normalized := strings.Map(func(r rune) rune {
+		switch r {
+		case '-', ' ', '\t', '\n':
+			return -1
+		case 'O':
+			return '0'
+		case 'I', 'L':
+			return '1'
+		}
+		return r
+	}, strings.ToUpper(phrase))
+
+	data, err := recoveryEncoding.DecodeString(normalized)
+	if err != nil || len(data) != kdfKeyLen+2 {
+		return nil, ErrInvalidRecoveryPhrase
+	}
+	secret, checksum := data[:kdfKeyLen], data[kdfKeyLen:]
+	sum := sha256.Sum256(secret)
+	if !bytes.Equal(sum[:2], checksum) {
+		return nil, ErrInvalidRecoveryPhrase
+	}
+	return secret, nil
+}
+
+// Seal шифрует данные текущим ключом
+func (k *Keyring) Seal(plaintext []byte) ([]byte, error) {
+	k.mu.Lock()
+	if len(k.keys) == 0 {
+		k.mu.Unlock()
+		return nil, ErrNoKey
+	}
+	key := k.keys[0]
+	k.mu.Unlock()
+
+	gcm, err := newGCM(key.Secret)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return json.Marshal(sealedData{
+		Format:     sealedFormat,
+		Key:        key.ID,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, []byte(key.ID)),
+	})
+}
+
+// Open расшифровывает данные, записанные Seal, любым из ключей устройства
+func (k *Keyring) Open(data []byte) ([]byte, error) {
+	var sealed sealedData
+	if err := json.Unmarshal(data, &sealed); err != nil {
+		return nil, err
+	}
+
+	k.mu.Lock()
+	var secret []byte
+	for _, key := range k.keys {
+		if key.ID == sealed.Key {
+			secret = key.Secret
+		}
+	}
+	k.mu.Unlock()
+	if secret == nil {
+		return nil, ErrUnknownKey
+	}
+
+	gcm, err := newGCM(secret)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed.Nonce) != gcm.NonceSize() {
+		return nil, errors.New("invalid sealed data")
+	}
+	plaintext, err := gcm.Open(nil, sealed.Nonce, sealed.Ciphertext, []byte(sealed.Key))
+	if err != nil {
+		return nil, errors.New("sealed data is corrupted")
+	}
+	return plaintext, nil
+}
+
+// IsSealed проверяет, записаны ли данные функцией Keyring.Seal
+func IsSealed(data []byte) bool {
+	var sealed struct {
+		Format string `json:"format"`
+	}
+	return json.Unmarshal(data, &sealed) == nil && sealed.Format == sealedFormat
+}
+
+// Sealed шифрует файл задач в удаленном хранилище ключом сквозного шифрования,
+// так что сервер видит только шифротекст. Незашифрованный файл читается как есть
+// и шифруется при следующей записи
+type Sealed struct {
+	Remote
+	keys *Keyring
+}
+
+// NewSealed создает хранилище, шифрующее данные remote ключами keys
+func NewSealed(remote Remote, keys *Keyring) *Sealed {
+	return &Sealed{Remote: remote, keys: keys}
+}
+
+// Read загружает и расшифровывает файл
+func (s *Sealed) Read() ([]byte, error) {
+	data, err := s.Remote.Read()
+	if err != nil || !IsSealed(data) {
+		return data, err
+	}
+	return s.keys.Open(data)
+}
+
+// Write шифрует файл текущим ключом и загружает его
+func (s *Sealed) Write(data []byte) error {
+	sealed, err := s.keys.Seal(data)
+	if err != nil {
+		return err
+	}
+	return s.Remote.Write(sealed)
+}
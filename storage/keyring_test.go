@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyring(t *testing.T) {
+	keys := &Keyring{}
+	_, err := keys.Seal([]byte("secret"))
+	assert.ErrorIs(t, err, ErrNoKey)
+
+	assert.NoError(t, keys.Generate())
+	sealed, err := keys.Seal([]byte("secret"))
+	assert.NoError(t, err)
+	assert.True(t, IsSealed(sealed))
+	assert.NotContains(t, string(sealed), "secret")
+
+	// После смены ключа старые данные по-прежнему читаются
+	oldID := keys.CurrentID()
+	assert.NoError(t, keys.Generate())
+	assert.NotEqual(t, oldID, keys.CurrentID())
+	plaintext, err := keys.Open(sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", string(plaintext))
+
+	// Ключи переживают сохранение
+	restored, err := ParseKeyring(keys.Marshal())
+	assert.NoError(t, err)
+	assert.Equal(t, keys.CurrentID(), restored.CurrentID())
+	_, err = restored.Open(sealed)
+	assert.NoError(t, err)
+
+	_, err = (&Keyring{}).Open(sealed)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestRecoveryPhrase(t *testing.T) {
+	keys := &Keyring{}
+	assert.NoError(t, keys.Generate())
+	phrase := keys.RecoveryPhrase()
+	sealed, err := keys.Seal([]byte("secret"))
+	assert.NoError(t, err)
+
+	// Фраза вводится на другом устройстве в любом регистре и с пробелами вместо дефисов
+	other := &Keyring{}
+	assert.NoError(t, other.Import(strings.ToLower(strings.ReplaceAll(phrase, "-", " "))))
+	assert.Equal(t, keys.CurrentID(), other.CurrentID())
+	plaintext, err := other.Open(sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", string(plaintext))
+
+	// Опечатка обнаруживается по контрольной сумме
+	typo := []byte(phrase)
+	if typo[0] == 'A' {
+		typo[0] = 'B'
+	} else {
+		typo[0] = 'A'
+	}
+	assert.ErrorIs(t, other.Import(string(typo)), ErrInvalidRecoveryPhrase)
+	assert.ErrorIs(t, other.Import("1234"), ErrInvalidRecoveryPhrase)
+}
+
+func TestSealedStorage(t *testing.T) {
+	server := &fakeDAV{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	remote := NewWebDAV(ts.URL+"/tasks.json", "user", "secret")
+
+	// Незашифрованный файл читается как есть и шифруется при записи
+	server.put([]byte(`[{"title":"Купить молоко"}]`))
+	keys := &Keyring{}
+	assert.NoError(t, keys.Generate())
+	s := NewSealed(remote, keys)
+	data, err := s.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"title":"Купить молоко"}]`, string(data))
+
+	assert.NoError(t, s.Write(data))
+	assert.True(t, IsSealed(server.data))
+	assert.NotContains(t, string(server.data), "молоко")
+
+	data, err = s.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"title":"Купить молоко"}]`, string(data))
+
+	_, err = NewSealed(remote, &Keyring{}).Read()
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
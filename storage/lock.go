@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrLocked возвращается, если файл задач в это время сохраняет другая программа или компьютер
+var ErrLocked = errors.New("tasks file is locked by another program")
+
+// Locker - хранилище, которое умеет захватывать блокировку записи.
+// Блокировка рекомендательная: она защищает только от программ, которые тоже ее проверяют
+type Locker interface {
+	// Lock захватывает блокировку записи; unlock освобождает ее
+	Lock() (unlock func() error, err error)
+}
+
+const (
+	// LockStale - через сколько чужая блокировка считается брошенной (программа завершилась аварийно)
+	LockStale = 30 * time.Second
+	// lockRetry - пауза между попытками захватить занятую блокировку
+	lockRetry = 100 * time.Millisecond
+)
+
+// lockWait - сколько ждать, пока другой компьютер закончит запись
+var lockWait = 5 * time.Second
+
+// LockInfo - кто и когда захватил блокировку, хранится в файле блокировки
+type LockInfo struct {
+	Host string    `json:"host"`
+	PID  int       `json:"pid"`
+	Time time.Time `json:"time"`
+}
+
+// writeLockPath возвращает путь к файлу блокировки записи.
+// Файл лежит рядом с файлом задач, поэтому виден всем компьютерам, работающим с сетевой папкой
+func (f *File) writeLockPath() string {
+	return f.path + ".writelock"
+}
+
+// Lock захватывает блокировку записи файла. Если ее держит другая программа,
+// ждет до lockWait и возвращает ErrLocked; брошенную блокировку забирает
+func (f *File) Lock() (func() error, error) {
+	host, _ := os.Hostname()
+	info := LockInfo{Host: host, PID: os.Getpid(), Time: time.Now()}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+
+	path := f.writeLockPath()
+	deadline := time.Now().Add(lockWait)
+	for {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, err = file.Write(data)
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				os.Remove(path)
+				return nil, err
+			}
+			return func() error { return releaseLock(path, data) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		owner, err := readLock(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			continue // Блокировку только что освободили
+		case err == nil && time.Since(owner.Time) > LockStale:
+			os.Remove(path)
+			continue
+		case time.Now().After(deadline):
+			if err != nil {
+				return nil, ErrLocked
+			}
+			return nil, fmt.Errorf("%w: %s (pid %d) since %s", ErrLocked, owner.Host, owner.PID, owner.Time.Format("15:04:05"))
+		}
+		time.Sleep(lockRetry)
+	}
+}
+
+// readLock читает файл блокировки. Время берется из файла, а если его не удалось разобрать
+// (файл еще дописывается) - из времени изменения файла
+func readLock(path string) (LockInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return LockInfo{}, err
+	}
+	var info LockInfo
+	if json.Unmarshal(data, &info) == nil && !info.Time.IsZero() {
+		return info, nil
+	}
+	stat, err := os.Stat(path)
+	if err != nil {
+		return LockInfo{}, err
+	}
+	return LockInfo{Time: stat.ModTime()}, nil
+}
+
+// releaseLock удаляет файл блокировки, если его не забрал другой компьютер, сочтя брошенным
+func releaseLock(path string, data []byte) error {
+	current, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if string(current) != string(data) {
+		return nil
+	}
+	return os.Remove(path)
+}
+
+// ConflictCopies возвращает копии файла задач, которые Syncthing создает,
+// когда файл одновременно изменили на двух компьютерах
+// (tasks.sync-conflict-20250630-100000-ABCDEFG.json)
+func (f *File) ConflictCopies() ([]string, error) {
+	ext := filepath.Ext(f.path)
+	base := strings.TrimSuffix(f.path, ext)
+	return filepath.Glob(base + ".sync-conflict-*" + ext)
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileLock(t *testing.T) {
+	lockWait = 200 * time.Millisecond
+	defer func() { lockWait = 5 * time.Second }()
+
+	f := NewFile(filepath.Join(t.TempDir(), "tasks.json"))
+	unlock, err := f.Lock()
+	assert.NoError(t, err)
+
+	// Пока блокировку держит другой, записать нельзя
+	_, err = NewFile(f.Path()).Lock()
+	assert.ErrorIs(t, err, ErrLocked)
+
+	assert.NoError(t, unlock())
+	unlock, err = f.Lock()
+	assert.NoError(t, err)
+	assert.NoError(t, unlock())
+	assert.NoFileExists(t, f.writeLockPath())
+}
+
+func TestFileLockStale(t *testing.T) {
+	f := NewFile(filepath.Join(t.TempDir(), "tasks.json"))
+
+	// Программа на другом компьютере завершилась, не сняв блокировку
+	data, _ := json.Marshal(LockInfo{Host: "laptop", PID: 42, Time: time.Now().Add(-2 * LockStale)})
+	assert.NoError(t, os.WriteFile(f.writeLockPath(), data, 0644))
+
+	unlock, err := f.Lock()
+	assert.NoError(t, err)
+	owner, err := readLock(f.writeLockPath())
+	assert.NoError(t, err)
+	assert.Equal(t, os.Getpid(), owner.PID)
+
+	// Если блокировку забрали, сочтя брошенной, чужой файл не удаляется
+	assert.NoError(t, os.WriteFile(f.writeLockPath(), data, 0644))
+	assert.NoError(t, unlock())
+	assert.FileExists(t, f.writeLockPath())
+}
+
+func TestFileWriteKeepsMode(t *testing.T) {
+	f := NewFile(filepath.Join(t.TempDir(), "tasks.json"))
+	assert.NoError(t, os.WriteFile(f.Path(), []byte("old"), 0600))
+
+	assert.NoError(t, f.Write([]byte("new")))
+	stat, err := os.Stat(f.Path())
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), stat.Mode().Perm())
+	assert.NoFileExists(t, f.Path()+".tmp")
+}
+
+func TestFileBackupKeepsMode(t *testing.T) {
+	f := NewFile(filepath.Join(t.TempDir(), "tasks.json"))
+	assert.NoError(t, os.WriteFile(f.Path(), []byte("old"), 0600))
+
+	assert.NoError(t, f.Backup(1))
+	backups, err := f.Backups()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(backups))
+	assert.NoError(t, f.WriteBackup(backups[0].Path, []byte("new")))
+	stat, err := os.Stat(backups[0].Path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), stat.Mode().Perm())
+	data, err := f.ReadBackup(backups[0].Path)
+	assert.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+}
+
+func TestConflictCopies(t *testing.T) {
+	dir := t.TempDir()
+	f := NewFile(filepath.Join(dir, "tasks.json"))
+	conflict := filepath.Join(dir, "tasks.sync-conflict-20250630-100000-ABCDEFG.json")
+	assert.NoError(t, os.WriteFile(conflict, []byte("{}"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "other.sync-conflict-20250630-100000-ABCDEFG.json"), []byte("{}"), 0644))
+
+	copies, err := f.ConflictCopies()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{conflict}, copies)
+}
@@ -0,0 +1,226 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OAuthConfig описывает приложение, зарегистрированное у облачного сервиса
+type OAuthConfig struct {
+	ClientID     string
+	ClientSecret string // Для Google; у Dropbox с PKCE не нужен
+	AuthURL      string
+	TokenURL     string
+	Scopes       []string
+	AuthParams   url.Values // Дополнительные параметры страницы входа
+}
+
+// Token - токены доступа OAuth
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	Expiry       time.Time `json:"expiry,omitzero"`
+}
+
+// expired сообщает, что токен доступа пора обновить
+func (t Token) expired(now time.Time) bool {
+	return !t.Expiry.IsZero() && now.Add(time.Minute).After(t.Expiry)
+}
+
+// Authorize проводит вход в облачный сервис через браузер: открывает страницу входа
+// и ждет, пока сервис вернет код на локальный адрес 127.0.0.1. Код защищен PKCE,
+// поэтому секрет приложения для Dropbox не нужен
+func Authorize(ctx context.Context, cfg OAuthConfig, openBrowser func(*url.URL) error) (Token, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return Token{}, err
+	}
+	defer listener.Close()
+	redirectURL := "http://" + listener.Addr().String() + "/"
+
+	verifier := randomString()
+	state := randomString()
+	challenge := sha256.Sum256([]byte(verifier))
+
+	authURL, err := url.Parse(cfg.AuthURL)
+	if err != nil {
+		return Token{}, err
+	}
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {cfg.ClientID},
+		"redirect_uri":          {redirectURL},
+		"state":                 {state},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+	if len(cfg.Scopes) > 0 {
+		query.Set("scope", strings.Join(cfg.Scopes, " "))
+	}
+	for key, values := range cfg.AuthParams {
+		query[key] = values
+	}
+	authURL.RawQuery = query.Encode()
+
+	codes := make(chan string, 1)
+	errs := make(chan error, 1)
+	server := &http.Server{
+		ReadHeaderTimeout: 10 * time.Second,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			switch {
+			case q.Get("state") != state:
+				http.Error(w, "неверный параметр state", http.StatusBadRequest)
+				return
+			case q.Get("error") != "":
+				http.Error(w, "Вход отменен. Окно можно закрыть.", http.StatusForbidden)
+				select {
+				case errs <- fmt.Errorf("authorization denied: %s", q.Get("error")):
+				default:
+				}
+				return
+			}
+			fmt.Fprintln(w, "Вход выполнен. Окно можно закрыть и вернуться в приложение.")
+			select {
+			case codes <- q.Get("code"):
+			default:
+			}
+		}),
+	}
+	go server.Serve(listener)
+	defer server.Close()
+
+	if err := openBrowser(authURL); err != nil {
+		return Token{}, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return Token{}, ctx.Err()
+	case err := <-errs:
+		return Token{}, err
+	case code := <-codes:
+		return requestToken(ctx, cfg, url.Values{
+			"grant_type":    {"authorization_code"},
+			"code":          {code},
+			"redirect_uri":  {redirectURL},
+			"code_verifier": {verifier},
+		})
+	}
+}
+
+// randomString возвращает случайную строку для state и верификатора PKCE
+func randomString() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// requestToken получает токены у сервиса
+func requestToken(ctx context.Context, cfg OAuthConfig, form url.Values) (Token, error) {
+	form.Set("client_id", cfg.ClientID)
+	if cfg.ClientSecret != "" {
+		form.Set("client_secret", cfg.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Token{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := (&http.Client{Timeout: remoteTimeout}).Do(req)
+	if err != nil {
+		return Token{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Token{}, statusError(resp)
+	}
+
+	var body struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Token{}, err
+	}
+	if body.AccessToken == "" {
+		return Token{}, errors.New("token response has no access token")
+	}
+	token := Token{AccessToken: body.AccessToken, RefreshToken: body.RefreshToken}
+	if body.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(body.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// oauthTransport добавляет к запросам токен доступа и обновляет его, когда он истекает
+type oauthTransport struct {
+	cfg     OAuthConfig
+	base    http.RoundTripper
+	onToken func(Token) // Вызывается с обновленным токеном, чтобы его сохранить
+
+	mu    sync.Mutex
+	token Token
+}
+
+// NewOAuthClient создает HTTP-клиент, который авторизует запросы токеном token.
+// onToken вызывается после обновления токена, может быть nil
+func NewOAuthClient(cfg OAuthConfig, token Token, onToken func(Token)) *http.Client {
+	return &http.Client{
+		Timeout: remoteTimeout,
+		Transport: &oauthTransport{
+			cfg:     cfg,
+			base:    http.DefaultTransport,
+			onToken: onToken,
+			token:   token,
+		},
+	}
+}
+
+func (t *oauthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.current(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	return t.base.RoundTrip(req)
+}
+
+// current возвращает действующий токен, при необходимости обновляя его
+func (t *oauthTransport) current(ctx context.Context) (Token, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.token.expired(time.Now()) || t.token.RefreshToken == "" {
+		return t.token, nil
+	}
+
+	token, err := requestToken(ctx, t.cfg, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {t.token.RefreshToken},
+	})
+	if err != nil {
+		return Token{}, fmt.Errorf("refresh access token: %w", err)
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = t.token.RefreshToken // Сервис выдает новый только при первом входе
+	}
+	t.token = token
+	if t.onToken != nil {
+		t.onToken(token)
+	}
+	return token, nil
+}
@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTokenServer - сервер токенов OAuth, который выдает токены за код и за токен обновления
+func newTokenServer(t *testing.T, refreshes *atomic.Int32) *httptest.Server {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		assert.Equal(t, "app", r.Form.Get("client_id"))
+		switch r.Form.Get("grant_type") {
+		case "authorization_code":
+			assert.Equal(t, "code", r.Form.Get("code"))
+			assert.NotEmpty(t, r.Form.Get("code_verifier"))
+			json.NewEncoder(w).Encode(map[string]any{"access_token": "access", "refresh_token": "refresh", "expires_in": 3600})
+		case "refresh_token":
+			assert.Equal(t, "refresh", r.Form.Get("refresh_token"))
+			refreshes.Add(1)
+			json.NewEncoder(w).Encode(map[string]any{"access_token": "fresh", "expires_in": 3600})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestAuthorize(t *testing.T) {
+	tokens := newTokenServer(t, new(atomic.Int32))
+	cfg := OAuthConfig{ClientID: "app", AuthURL: "https://auth.example.com/authorize", TokenURL: tokens.URL}
+
+	// Браузер сразу возвращается на локальный адрес с кодом
+	token, err := Authorize(t.Context(), cfg, func(u *url.URL) error {
+		q := u.Query()
+		assert.Equal(t, "S256", q.Get("code_challenge_method"))
+		go http.Get(q.Get("redirect_uri") + "?code=code&state=" + url.QueryEscape(q.Get("state")))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "access", token.AccessToken)
+	assert.Equal(t, "refresh", token.RefreshToken)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), token.Expiry, time.Minute)
+}
+
+func TestAuthorizeDenied(t *testing.T) {
+	cfg := OAuthConfig{ClientID: "app", AuthURL: "https://auth.example.com/authorize", TokenURL: "http://127.0.0.1:1"}
+	_, err := Authorize(t.Context(), cfg, func(u *url.URL) error {
+		q := u.Query()
+		go http.Get(q.Get("redirect_uri") + "?error=access_denied&state=" + url.QueryEscape(q.Get("state")))
+		return nil
+	})
+	assert.ErrorContains(t, err, "access_denied")
+}
+
+func TestOAuthClientRefresh(t *testing.T) {
+	var refreshes atomic.Int32
+	tokens := newTokenServer(t, &refreshes)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer api.Close()
+
+	var saved Token
+	expired := Token{AccessToken: "old", RefreshToken: "refresh", Expiry: time.Now().Add(-time.Minute)}
+	client := NewOAuthClient(OAuthConfig{ClientID: "app", TokenURL: tokens.URL}, expired, func(token Token) { saved = token })
+
+	for range 2 {
+		resp, err := client.Get(api.URL)
+		assert.NoError(t, err)
+		var auth [64]byte
+		n, _ := resp.Body.Read(auth[:])
+		resp.Body.Close()
+		assert.Equal(t, "Bearer fresh", string(auth[:n]))
+	}
+	assert.EqualValues(t, 1, refreshes.Load())
+	assert.Equal(t, "fresh", saved.AccessToken)
+	assert.Equal(t, "refresh", saved.RefreshToken, "refresh token is kept when the service does not issue a new one")
+}
@@ -0,0 +1,161 @@
+// Package storage хранит содержимое файла задач. Пакет работает с байтами
+// и ничего не знает о задачах, поэтому хранилища можно подменять:
+// локальный файл, память в тестах, удаленный сервер
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Storage - место хранения файла задач.
+// Read возвращает ошибку, для которой errors.Is(err, fs.ErrNotExist), если данных еще нет
+type Storage interface {
+	Read() ([]byte, error)
+	Write(data []byte) error
+}
+
+// Remote - хранилище на сервере. Modified проверяет, изменился ли файл после последнего
+// чтения или записи, и безопасен для вызова из отдельной горутины
+type Remote interface {
+	Storage
+	URL() string
+	Modified(ctx context.Context) (bool, error)
+}
+
+// Backuper - хранилище, которое умеет сохранять резервные копии предыдущих версий
+type Backuper interface {
+	// Backup копирует текущие данные в резервную копию и оставляет не больше keep копий
+	Backup(keep int) error
+	// Backups возвращает резервные копии, начиная с самой новой
+	Backups() ([]Backup, error)
+	// ReadBackup читает резервную копию
+	ReadBackup(path string) ([]byte, error)
+	// WriteBackup перезаписывает резервную копию, например зашифрованной новым паролем
+	WriteBackup(path string, data []byte) error
+}
+
+// Backup описывает одну резервную копию
+type Backup struct {
+	Path string
+	Time time.Time
+}
+
+// backupTimeFormat - формат отметки времени в имени резервной копии (tasks.json.2025-06-30T10-00-00)
+const backupTimeFormat = "2006-01-02T15-04-05"
+
+// File хранит задачи в локальном файле, а резервные копии - рядом с ним
+type File struct {
+	path string
+}
+
+// NewFile создает хранилище в файле path
+func NewFile(path string) *File {
+	return &File{path: path}
+}
+
+// Path возвращает путь к файлу
+func (f *File) Path() string {
+	return f.path
+}
+
+// Read читает файл целиком
+func (f *File) Read() ([]byte, error) {
+	return os.ReadFile(f.path)
+}
+
+// Write перезаписывает файл. Данные сначала пишутся во временный файл, который затем
+// заменяет основной: другие компьютеры и программы синхронизации не увидят файл записанным наполовину
+func (f *File) Write(data []byte) error {
+	return replaceFile(f.path, data, f.mode())
+}
+
+// mode возвращает права файла задач; с ними же пишутся резервные копии
+func (f *File) mode() os.FileMode {
+	if stat, err := os.Stat(f.path); err == nil {
+		return stat.Mode().Perm() // Не открываем доступ к файлу, который пользователь закрыл
+	}
+	return 0644
+}
+
+// replaceFile записывает data во временный файл и заменяет им файл path
+func replaceFile(path string, data []byte, mode os.FileMode) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, mode); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// Backup копирует текущий файл в резервную копию
+// и удаляет самые старые копии сверх keep
+func (f *File) Backup(keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // Копировать пока нечего
+		}
+		return err
+	}
+
+	backupPath := f.path + "." + time.Now().Format(backupTimeFormat)
+	if err := os.WriteFile(backupPath, data, f.mode()); err != nil {
+		return err
+	}
+
+	backups, err := f.Backups()
+	if err != nil {
+		return err
+	}
+	for _, backup := range backups[min(len(backups), keep):] {
+		if err := os.Remove(backup.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Backups возвращает резервные копии файла, начиная с самой новой
+func (f *File) Backups() ([]Backup, error) {
+	matches, err := filepath.Glob(f.path + ".*")
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []Backup
+	for _, path := range matches {
+		suffix := strings.TrimPrefix(path, f.path+".")
+		t, err := time.ParseInLocation(backupTimeFormat, suffix, time.Local)
+		if err != nil {
+			continue // Посторонний файл с похожим именем
+		}
+		backups = append(backups, Backup{Path: path, Time: t})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Time.After(backups[j].Time)
+	})
+	return backups, nil
+}
+
+// ReadBackup читает резервную копию
+func (f *File) ReadBackup(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+// WriteBackup перезаписывает резервную копию с правами файла задач
+func (f *File) WriteBackup(path string, data []byte) error {
+	return replaceFile(path, data, f.mode())
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFile(t *testing.T) {
+	f := NewFile(filepath.Join(t.TempDir(), "tasks.json"))
+
+	// Файла еще нет
+	_, err := f.Read()
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	assert.NoError(t, f.Write([]byte("data")))
+	data, err := f.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+}
+
+func TestFileBackups(t *testing.T) {
+	f := NewFile(filepath.Join(t.TempDir(), "tasks.json"))
+
+	// Копировать пока нечего
+	assert.NoError(t, f.Backup(2))
+	backups, err := f.Backups()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(backups))
+
+	// Старые копии с прошлых сохранений и посторонний файл с похожим именем
+	for i := 1; i <= 3; i++ {
+		name := f.Path() + "." + time.Now().Add(-time.Duration(i)*time.Hour).Format(backupTimeFormat)
+		assert.NoError(t, os.WriteFile(name, []byte("old"), 0644))
+	}
+	assert.NoError(t, os.WriteFile(f.Path()+".old", []byte("other"), 0644))
+
+	// Копия текущего файла становится самой новой, лишние удаляются
+	assert.NoError(t, f.Write([]byte("current")))
+	assert.NoError(t, f.Backup(2))
+
+	backups, err = f.Backups()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(backups))
+	assert.True(t, backups[0].Time.After(backups[1].Time))
+
+	data, err := f.ReadBackup(backups[0].Path)
+	assert.NoError(t, err)
+	assert.Equal(t, "current", string(data))
+
+	// keep = 0 отключает копии
+	assert.NoError(t, f.Backup(0))
+	backups, err = f.Backups()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(backups))
+}
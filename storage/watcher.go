@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchDelay - пауза после последнего события, чтобы не реагировать на каждую запись по отдельности
+const WatchDelay = 500 * time.Millisecond
+
+// Watcher следит за файлом задач и сообщает, когда его изменяет другая программа
+// (второй экземпляр приложения, Dropbox, Syncthing)
+type Watcher struct {
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+	once    sync.Once
+}
+
+// Watch начинает следить за файлом. onChange вызывается из отдельной горутины
+// спустя delay после последнего изменения файла.
+// Следим за каталогом, а не за файлом: программы синхронизации обычно заменяют файл целиком
+func Watch(filename string, delay time.Duration, onChange func()) (*Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := filepath.Abs(filename)
+	if err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	fw := &Watcher{watcher: watcher, done: make(chan struct{})}
+	go fw.run(path, delay, onChange)
+	return fw, nil
+}
+
+func (fw *Watcher) run(path string, delay time.Duration, onChange func()) {
+	var timer *time.Timer
+	for {
+		select {
+		case event, ok := <-fw.watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != path {
+				continue // Резервные копии и другие файлы в том же каталоге
+			}
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.AfterFunc(delay, onChange)
+		case _, ok := <-fw.watcher.Errors:
+			if !ok {
+				return
+			}
+		case <-fw.done:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		}
+	}
+}
+
+// Close прекращает наблюдение за файлом
+func (fw *Watcher) Close() error {
+	var err error
+	fw.once.Do(func() {
+		close(fw.done)
+		err = fw.watcher.Close()
+	})
+	return err
+}
@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tasks.json")
+	assert.NoError(t, os.WriteFile(path, []byte("[]"), 0644))
+
+	changed := make(chan struct{}, 10)
+	watcher, err := Watch(path, 50*time.Millisecond, func() {
+		changed <- struct{}{}
+	})
+	assert.NoError(t, err)
+	defer watcher.Close()
+
+	// Посторонний файл в том же каталоге не вызывает уведомления
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "export.csv"), []byte("id"), 0644))
+
+	// Изменение файла задач другой программой
+	assert.NoError(t, os.WriteFile(path, []byte(`{"version": 3}`), 0644))
+
+	select {
+	case <-changed:
+	case <-time.After(3 * time.Second):
+		t.Fatal("изменение файла не обнаружено")
+	}
+
+	// Несколько событий подряд приводят к одному уведомлению
+	select {
+	case <-changed:
+		t.Fatal("лишнее уведомление")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrModified возвращается при записи, если файл на сервере изменили после последнего чтения
+var ErrModified = errors.New("file was changed on the server")
+
+// remoteTimeout - сколько ждать ответа удаленного хранилища
+const remoteTimeout = 30 * time.Second
+
+// WebDAV хранит файл задач на сервере WebDAV, например в Nextcloud.
+// Запись проходит, только если файл на сервере не менялся после последнего чтения (по ETag),
+// поэтому изменения с другого устройства не затираются молча
+type WebDAV struct {
+	url      string
+	user     string
+	password string
+	client   *http.Client
+
+	mu   sync.Mutex
+	etag string // ETag версии, прочитанной или записанной последней; пусто - файла не было
+}
+
+// NewWebDAV создает хранилище в файле url
+// (для Nextcloud - https://cloud.example.com/remote.php/dav/files/<пользователь>/tasks.json)
+func NewWebDAV(url, user, password string) *WebDAV {
+	return &WebDAV{
+		url:      url,
+		user:     user,
+		password: password,
+		client:   &http.Client{Timeout: remoteTimeout},
+	}
+}
+
+// URL возвращает адрес файла
+func (d *WebDAV) URL() string {
+	return d.url
+}
+
+// Read загружает файл и запоминает его ETag
+func (d *WebDAV) Read() ([]byte, error) {
+	resp, err := d.do(context.Background(), http.MethodGet, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		d.etag = ""
+		return nil, &fs.PathError{Op: "get", Path: d.url, Err: fs.ErrNotExist}
+	case resp.StatusCode != http.StatusOK:
+		return nil, statusError(resp)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	d.etag = resp.Header.Get("ETag")
+	return data, nil
+}
+
+// Write загружает файл на сервер. Если файл изменили после последнего чтения, возвращает ErrModified
+func (d *WebDAV) Write(data []byte) error {
+	d.mu.Lock()
+	etag := d.etag
+	d.mu.Unlock()
+
+	header := http.Header{}
+	if etag != "" {
+		header.Set("If-Match", etag)
+	} else {
+		header.Set("If-None-Match", "*") // Файла не было - не перезаписываем созданный кем-то другим
+	}
+
+	resp, err := d.do(context.Background(), http.MethodPut, header, data)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusPreconditionFailed:
+		return ErrModified
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return statusError(resp)
+	}
+
+	// Nextcloud возвращает ETag новой версии сразу, другим серверам нужен отдельный запрос
+	etag = resp.Header.Get("ETag")
+	if etag == "" {
+		if etag, err = d.currentETag(context.Background()); err != nil {
+			return err
+		}
+	}
+	d.mu.Lock()
+	d.etag = etag
+	d.mu.Unlock()
+	return nil
+}
+
+// Modified сообщает, изменился ли файл на сервере после последнего чтения или записи.
+// Не меняет прочитанную версию, поэтому его можно вызывать из отдельной горутины
+func (d *WebDAV) Modified(ctx context.Context) (bool, error) {
+	etag, err := d.currentETag(ctx)
+	if err != nil {
+		return false, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return etag != d.etag, nil
+}
+
+// currentETag возвращает ETag файла на сервере; пусто - файла нет
+func (d *WebDAV) currentETag(ctx context.Context) (string, error) {
+	resp, err := d.do(ctx, http.MethodHead, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", nil
+	case resp.StatusCode != http.StatusOK:
+		return "", statusError(resp)
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// do выполняет запрос к файлу на сервере
+func (d *WebDAV) do(ctx context.Context, method string, header http.Header, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, d.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if d.user != "" || d.password != "" {
+		req.SetBasicAuth(d.user, d.password)
+	}
+	return d.client.Do(req)
+}
+
+// statusError описывает неуспешный ответ сервера
+func statusError(resp *http.Response) error {
+	return fmt.Errorf("webdav %s %s: %s", resp.Request.Method, resp.Request.URL.Redacted(), resp.Status)
+}
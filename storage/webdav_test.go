@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDAV - сервер WebDAV с одним файлом, проверяющий If-Match и If-None-Match
+type fakeDAV struct {
+	mu      sync.Mutex
+	data    []byte
+	exists  bool
+	version int
+}
+
+func (s *fakeDAV) etag() string {
+	return fmt.Sprintf(`"v%d"`, s.version)
+}
+
+// put заменяет файл на сервере, как это сделало бы другое устройство
+func (s *fakeDAV) put(data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data, s.exists = data, true
+	s.version++
+}
+
+func (s *fakeDAV) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if user, password, _ := r.BasicAuth(); user != "user" || password != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		if !s.exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", s.etag())
+		if r.Method == http.MethodGet {
+			w.Write(s.data)
+		}
+	case http.MethodPut:
+		if match := r.Header.Get("If-Match"); match != "" && (!s.exists || match != s.etag()) ||
+			r.Header.Get("If-None-Match") == "*" && s.exists {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		s.data, _ = io.ReadAll(r.Body)
+		s.exists = true
+		s.version++
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestWebDAV(t *testing.T) {
+	server := &fakeDAV{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	d := NewWebDAV(ts.URL+"/tasks.json", "user", "secret")
+
+	_, err := d.Read()
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	assert.NoError(t, d.Write([]byte("first")))
+	data, err := d.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, "first", string(data))
+
+	modified, err := d.Modified(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, modified)
+
+	// Файл изменили с другого устройства - запись не затирает его
+	server.put([]byte("other"))
+	modified, err = d.Modified(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, modified)
+	assert.ErrorIs(t, d.Write([]byte("second")), ErrModified)
+
+	// После повторного чтения запись проходит
+	data, err = d.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, "other", string(data))
+	assert.NoError(t, d.Write([]byte("second")))
+	assert.Equal(t, "second", string(server.data))
+}
+
+func TestWebDAVCreateConflict(t *testing.T) {
+	server := &fakeDAV{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	d := NewWebDAV(ts.URL+"/tasks.json", "user", "secret")
+
+	_, err := d.Read()
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	// Файл создали с другого устройства, пока его не было при чтении
+	server.put([]byte("other"))
+	assert.ErrorIs(t, d.Write([]byte("mine")), ErrModified)
+}
+
+func TestWebDAVUnauthorized(t *testing.T) {
+	ts := httptest.NewServer(&fakeDAV{})
+	defer ts.Close()
+
+	_, err := NewWebDAV(ts.URL+"/tasks.json", "user", "wrong").Read()
+	assert.ErrorContains(t, err, "401")
+}
@@ -0,0 +1,138 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"taskmanager/storage"
+)
+
+// DefaultBackupKeep - сколько резервных копий хранится по умолчанию
+const DefaultBackupKeep = 5
+
+// BackupInfo описывает одну резервную копию файла задач
+type BackupInfo struct {
+	Path      string
+	Time      time.Time
+	TaskCount int
+}
+
+// SetBackupKeep задает, сколько резервных копий хранить (0 - не создавать копии)
+func (tm *TaskManager) SetBackupKeep(keep int) {
+	if keep < 0 {
+		keep = 0
+	}
+	tm.backupKeep = keep
+}
+
+// backupCurrentFile сохраняет предыдущую версию файла задач,
+// если хранилище поддерживает резервные копии. После смены пароля копия не делается:
+// прежний файл зашифрован старым паролем или открыт, а копии уже перешифрованы.
+// При шифровании копируется только файл, уже зашифрованный текущим паролем
+func (tm *TaskManager) backupCurrentFile() error {
+	backuper, ok := tm.store.(storage.Backuper)
+	if !ok {
+		return nil
+	}
+	if tm.keyChanged {
+		return nil
+	}
+	if tm.passphrase != "" {
+		data, err := tm.store.Read()
+		if err != nil || !storage.IsEncrypted(data) {
+			return nil
+		}
+	}
+	return backuper.Backup(tm.backupKeep)
+}
+
+// rekeyBackups переводит резервные копии с пароля previous на passphrase: шифрует их
+// новым паролем, а если passphrase пустой - расшифровывает. Копия, которую не удалось
+// расшифровать прежним паролем, - ошибка: иначе она осталась бы под сброшенным паролем.
+// Без прежнего пароля зашифрованные копии не трогаются
+func (tm *TaskManager) rekeyBackups(previous, passphrase string) error {
+	backuper, ok := tm.store.(storage.Backuper)
+	if !ok {
+		return nil
+	}
+	backups, err := backuper.Backups()
+	if err != nil {
+		return err
+	}
+	for _, backup := range backups {
+		data, err := backuper.ReadBackup(backup.Path)
+		if err != nil {
+			return err
+		}
+		encrypted := storage.IsEncrypted(data)
+		if encrypted {
+			if previous == "" {
+				// Шифрования не было: копия закрыта чужим паролем, переводить ее нечем
+				continue
+			}
+			if data, err = storage.Decrypt(data, previous); err != nil {
+				return fmt.Errorf("backup %s: %w", backup.Path, err)
+			}
+		}
+		if passphrase != "" {
+			if data, err = storage.Encrypt(data, passphrase); err != nil {
+				return err
+			}
+		} else if !encrypted {
+			continue
+		}
+		if err := backuper.WriteBackup(backup.Path, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListBackups возвращает список резервных копий, начиная с самой новой
+func (tm *TaskManager) ListBackups() ([]BackupInfo, error) {
+	backuper, ok := tm.store.(storage.Backuper)
+	if !ok {
+		return nil, nil
+	}
+	backups, err := backuper.Backups()
+	if err != nil {
+		return nil, err
+	}
+
+	var infos []BackupInfo
+	for _, backup := range backups {
+		info := BackupInfo{Path: backup.Path, Time: backup.Time, TaskCount: -1}
+		if tasks, err := tm.LoadBackup(backup.Path); err == nil {
+			info.TaskCount = len(tasks)
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// LoadBackup читает задачи из резервной копии, не меняя текущий список
+func (tm *TaskManager) LoadBackup(path string) ([]*Task, error) {
+	backuper, ok := tm.store.(storage.Backuper)
+	if !ok {
+		return nil, errors.New("storage does not support backups")
+	}
+	data, err := backuper.ReadBackup(path)
+	if err != nil {
+		return nil, err
+	}
+	return tm.decodeFile(data)
+}
+
+// RestoreBackup заменяет текущие задачи задачами из резервной копии.
+// Изменения попадают в основной файл при следующем сохранении
+func (tm *TaskManager) RestoreBackup(path string) error {
+	tasks, err := tm.LoadBackup(path)
+	if err != nil {
+		return err
+	}
+
+	tm.setTasks(tasks)
+	tm.dirty = true
+	return nil
+}
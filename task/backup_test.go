@@ -0,0 +1,87 @@
+package task
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupRotation(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+	tm.SetBackupKeep(2)
+
+	// Первое сохранение: копировать еще нечего
+	tm.AddTask("Task 1", "Description", 1, time.Now())
+	assert.NoError(t, tm.SaveToFile())
+	backups, err := tm.ListBackups()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(backups))
+
+	// Старые копии с прошлых сохранений (имена в формате storage.File)
+	for i := 1; i <= 3; i++ {
+		name := testFilename + "." + time.Now().Add(-time.Duration(i)*time.Hour).Format("2006-01-02T15-04-05")
+		assert.NoError(t, os.WriteFile(name, []byte("[]"), 0644))
+	}
+
+	// Сохранение копирует предыдущую версию файла и удаляет лишние копии
+	tm.AddTask("Task 2", "Description", 1, time.Now())
+	assert.NoError(t, tm.SaveToFile())
+
+	backups, err = tm.ListBackups()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(backups))
+	assert.True(t, backups[0].Time.After(backups[1].Time))
+	assert.Equal(t, 1, backups[0].TaskCount)
+	assert.Equal(t, 0, backups[1].TaskCount)
+}
+
+func TestBackupDisabled(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+	tm.SetBackupKeep(0)
+
+	tm.AddTask("Task 1", "Description", 1, time.Now())
+	assert.NoError(t, tm.SaveToFile())
+	tm.AddTask("Task 2", "Description", 1, time.Now())
+	assert.NoError(t, tm.SaveToFile())
+
+	backups, err := tm.ListBackups()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(backups))
+}
+
+func TestRestoreBackup(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	tm.AddTask("Task 1", "Description", 1, time.Now())
+	assert.NoError(t, tm.SaveToFile())
+	tm.AddTask("Task 2", "Description", 2, time.Now())
+	assert.NoError(t, tm.SaveToFile())
+
+	// Посторонний файл с похожим именем не считается копией
+	assert.NoError(t, os.WriteFile(testFilename+".old", []byte("[]"), 0644))
+
+	backups, err := tm.ListBackups()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(backups))
+
+	// Предпросмотр не меняет текущий список
+	preview, err := tm.LoadBackup(backups[0].Path)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(preview))
+	assert.Equal(t, 2, len(tm.tasks))
+
+	// Восстановление заменяет задачи и требует сохранения
+	assert.NoError(t, tm.RestoreBackup(backups[0].Path))
+	assert.Equal(t, 1, len(tm.tasks))
+	assert.Equal(t, "Task 1", tm.tasks[0].Title)
+	assert.True(t, tm.IsDirty())
+
+	// Новые задачи не получают ID удаленных
+	task := mustAddTask(t, tm, "Task 3", "Description", 1, time.Now())
+	assert.Equal(t, 3, task.ID)
+}
@@ -0,0 +1,177 @@
+package task
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// csvBOM - метка порядка байт UTF-8: по ней Excel узнает кодировку файла
+const csvBOM = "\ufeff"
+
+// CSVFilter - какие задачи выгружать в CSV
+type CSVFilter string
+
+const (
+	CSVFilterAll      CSVFilter = "all"       // все задачи
+	CSVFilterOpen     CSVFilter = "open"      // только невыполненные
+	CSVFilterDueRange CSVFilter = "due_range" // со сроком в диапазоне From - To
+)
+
+// CSVColumn - колонка CSV
+type CSVColumn struct {
+	Key    string
+	Header string
+	value  func(task *Task) string
+}
+
+// CSVColumns - колонки CSV в порядке выгрузки
+var CSVColumns = []CSVColumn{
+	{"id", "ID", func(t *Task) string { return strconv.Itoa(t.ID) }},
+	{"title", "Title", func(t *Task) string { return t.Title }},
+	{"description", "Description", func(t *Task) string { return t.Description }},
+	{"priority", "Priority", func(t *Task) string { return map[int]string{1: "Low", 2: "Medium", 3: "High"}[t.Priority] }},
+	{"due", "Due Date", func(t *Task) string { return csvTime(t.DueDate) }},
+	{"created", "Created At", func(t *Task) string { return t.CreatedAt.Format("2006-01-02 15:04") }},
+	{"completed", "Completed", func(t *Task) string { return csvYesNo(t.Completed) }},
+	{"uuid", "UUID", func(t *Task) string { return t.UUID }},
+	{"updated", "Updated At", func(t *Task) string { return t.UpdatedAt.Format("2006-01-02 15:04") }},
+	{"assignee", "Assignee", func(t *Task) string { return t.Assignee }},
+	{"archived", "Archived", func(t *Task) string { return csvYesNo(t.Archived) }},
+	{"context", "Context", func(t *Task) string { return t.Context }},
+	{"location", "Location", func(t *Task) string { return t.Location }},
+}
+
+// csvTime показывает время в CSV; нулевое время - пустая ячейка
+func csvTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+func csvYesNo(value bool) string {
+	if value {
+		return "Yes"
+	}
+	return "No"
+}
+
+// CSVOptions - настройки выгрузки в CSV. Нулевое значение - все задачи и все колонки через запятую
+type CSVOptions struct {
+	Delimiter rune      // разделитель: запятая, точка с запятой или табуляция; 0 - запятая
+	BOM       bool      // записать в начало метку UTF-8 для Excel
+	Columns   []string  // ключи колонок из CSVColumns; пусто - все колонки
+	Filter    CSVFilter // пусто - все задачи
+	From, To  time.Time // дни срока для CSVFilterDueRange включительно; нулевое - без границы
+}
+
+// Validate проверяет настройки выгрузки
+func (o CSVOptions) Validate() error {
+	switch o.Delimiter {
+	case 0, ',', ';', '\t':
+	default:
+		return &ValidationError{Field: "delimiter", Message: fmt.Sprintf("unsupported delimiter %q", o.Delimiter)}
+	}
+	for _, key := range o.Columns {
+		if !slices.ContainsFunc(CSVColumns, func(c CSVColumn) bool { return c.Key == key }) {
+			return &ValidationError{Field: "columns", Message: fmt.Sprintf("unknown column %q", key)}
+		}
+	}
+	switch o.Filter {
+	case "", CSVFilterAll, CSVFilterOpen:
+	case CSVFilterDueRange:
+		if !o.From.IsZero() && !o.To.IsZero() && o.From.After(o.To) {
+			return &ValidationError{Field: "due range", Message: "range start must not be after its end"}
+		}
+	default:
+		return &ValidationError{Field: "filter", Message: fmt.Sprintf("unknown filter %q", o.Filter)}
+	}
+	return nil
+}
+
+// columns возвращает выбранные колонки в порядке CSVColumns
+func (o CSVOptions) columns() []CSVColumn {
+	if len(o.Columns) == 0 {
+		return CSVColumns
+	}
+	var columns []CSVColumn
+	for _, c := range CSVColumns {
+		if slices.Contains(o.Columns, c.Key) {
+			columns = append(columns, c)
+		}
+	}
+	return columns
+}
+
+// matches проверяет, попадает ли задача в выгрузку
+func (o CSVOptions) matches(t *Task) bool {
+	switch o.Filter {
+	case CSVFilterOpen:
+		return !t.Completed
+	case CSVFilterDueRange:
+		day := t.DueDay()
+		return (o.From.IsZero() || day >= o.From.Format(DueDateLayout)) &&
+			(o.To.IsZero() || day <= o.To.Format(DueDateLayout))
+	}
+	return true
+}
+
+// ExportToCSV экспортирует задачи в CSV формат
+func (tm *TaskManager) ExportToCSV(filename string, options CSVOptions) error {
+	if err := options.Validate(); err != nil {
+		return err
+	}
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return WriteCSV(file, tm.tasks, options)
+}
+
+// WriteCSV записывает задачи в CSV формате
+func WriteCSV(w io.Writer, tasks []*Task, options CSVOptions) error {
+	if err := options.Validate(); err != nil {
+		return err
+	}
+	if options.BOM {
+		if _, err := io.WriteString(w, csvBOM); err != nil {
+			return err
+		}
+	}
+	writer := csv.NewWriter(w)
+	if options.Delimiter != 0 {
+		writer.Comma = options.Delimiter
+	}
+
+	columns := options.columns()
+	headers := make([]string, len(columns))
+	for i, c := range columns {
+		headers[i] = c.Header
+	}
+	if err := writer.Write(headers); err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		if !options.matches(task) {
+			continue
+		}
+		row := make([]string, len(columns))
+		for i, c := range columns {
+			row[i] = c.value(task)
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,46 @@
+package task
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCSVOptions(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC) }
+	tasks := []*Task{
+		{ID: 1, Title: "Отчет", DueDate: day(10), Completed: true},
+		{ID: 2, Title: "Бюджет", DueDate: day(15)},
+		{ID: 3, Title: "Отпуск", DueDate: day(30)},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, WriteCSV(&out, tasks, CSVOptions{
+		Delimiter: ';',
+		BOM:       true,
+		Columns:   []string{"title", "id"},
+		Filter:    CSVFilterOpen,
+	}))
+	text, found := strings.CutPrefix(out.String(), csvBOM)
+	assert.True(t, found)
+	// Колонки идут в обычном порядке, а не в порядке выбора
+	assert.Equal(t, "ID;Title\n2;Бюджет\n3;Отпуск\n", text)
+
+	out.Reset()
+	require.NoError(t, WriteCSV(&out, tasks, CSVOptions{Delimiter: '\t', Filter: CSVFilterDueRange, From: day(10), To: day(15)}))
+	reader := csv.NewReader(&out)
+	reader.Comma = '\t'
+	records, err := reader.ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, records, 3)
+	assert.Equal(t, "Бюджет", records[2][1])
+
+	assert.ErrorIs(t, WriteCSV(&out, tasks, CSVOptions{Delimiter: '|'}), ErrValidation)
+	assert.ErrorIs(t, WriteCSV(&out, tasks, CSVOptions{Columns: []string{"color"}}), ErrValidation)
+	assert.ErrorIs(t, WriteCSV(&out, tasks, CSVOptions{Filter: CSVFilterDueRange, From: day(15), To: day(10)}), ErrValidation)
+}
@@ -0,0 +1,75 @@
+package task
+
+import "slices"
+
+// SetTaskDependencies задает задачи, без которых нельзя начать задачу id. Задача не может
+// зависеть от себя, а зависимости не должны замыкаться в цикл
+func (tm *TaskManager) SetTaskDependencies(id int, dependsOn []int) error {
+	task := tm.GetTask(id)
+	if task == nil {
+		return notFoundError(id)
+	}
+	var uuids []string
+	for _, depID := range dependsOn {
+		dep := tm.GetTask(depID)
+		if dep == nil {
+			return notFoundError(depID)
+		}
+		if dep == task {
+			return &ValidationError{Field: "depends on", Message: "task cannot depend on itself"}
+		}
+		if tm.dependsOn(dep, task.UUID, make(map[string]bool)) {
+			return &ValidationError{Field: "depends on", Message: "dependencies must not form a cycle"}
+		}
+		if !slices.Contains(uuids, dep.UUID) {
+			uuids = append(uuids, dep.UUID)
+		}
+	}
+
+	task.DependsOn = uuids
+	tm.touch(task)
+	tm.emit(EventUpdated, task)
+	return nil
+}
+
+// Dependencies возвращает задачи, от которых зависит задача id
+func (tm *TaskManager) Dependencies(id int) []*Task {
+	task := tm.GetTask(id)
+	if task == nil {
+		return nil
+	}
+	var deps []*Task
+	for _, uuid := range task.DependsOn {
+		if dep := tm.GetTaskByUUID(uuid); dep != nil {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+// dependsOn проверяет, зависит ли задача от задачи uuid прямо или через другие задачи
+func (tm *TaskManager) dependsOn(task *Task, uuid string, seen map[string]bool) bool {
+	if seen[task.UUID] {
+		return false
+	}
+	seen[task.UUID] = true
+	for _, dep := range task.DependsOn {
+		if dep == uuid {
+			return true
+		}
+		if next := tm.GetTaskByUUID(dep); next != nil && tm.dependsOn(next, uuid, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// undepend убирает из зависимостей задачи удаленную задачу uuid
+func (tm *TaskManager) undepend(task *Task, uuid string) {
+	if !slices.Contains(task.DependsOn, uuid) {
+		return
+	}
+	task.DependsOn = slices.DeleteFunc(slices.Clone(task.DependsOn), func(dep string) bool { return dep == uuid })
+	tm.touch(task)
+	tm.emit(EventUpdated, task)
+}
@@ -0,0 +1,31 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetTaskDependencies(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	design := mustAddTask(t, tm, "Сделать макет", "", 2, time.Now())
+	code := mustAddTask(t, tm, "Сверстать", "", 2, time.Now())
+	release := mustAddTask(t, tm, "Выпустить", "", 2, time.Now())
+
+	assert.NoError(t, tm.SetTaskDependencies(code.ID, []int{design.ID, design.ID}))
+	assert.NoError(t, tm.SetTaskDependencies(release.ID, []int{code.ID}))
+	assert.Equal(t, []*Task{design}, tm.Dependencies(code.ID))
+
+	assert.ErrorIs(t, tm.SetTaskDependencies(design.ID, []int{design.ID}), ErrValidation)
+	// Макет не может ждать выпуска: выпуск уже ждет макета через верстку
+	assert.ErrorIs(t, tm.SetTaskDependencies(design.ID, []int{release.ID}), ErrValidation)
+	assert.Empty(t, tm.GetTask(design.ID).DependsOn)
+	assert.ErrorIs(t, tm.SetTaskDependencies(code.ID, []int{999}), ErrNotFound)
+
+	// Удаленная задача исчезает из зависимостей
+	assert.NoError(t, tm.DeleteTask(code.ID))
+	assert.Empty(t, tm.GetTask(release.ID).DependsOn)
+}
@@ -0,0 +1,60 @@
+package task
+
+import "time"
+
+// DueDateLayout - формат срока задачи: у срока есть только дата
+const DueDateLayout = "2006-01-02"
+
+// DueZone - в каком часовом поясе понимать сроки задач. Срок «2026-10-16» начинается
+// в полночь этого дня в выбранном поясе и хранится вместе с его смещением, поэтому
+// день срока не меняется, когда компьютер переезжает в другой пояс
+type DueZone string
+
+const (
+	DueZoneLocal DueZone = "local" // по часам компьютера
+	DueZoneUTC   DueZone = "utc"
+)
+
+// Location возвращает часовой пояс; по умолчанию - местный
+func (z DueZone) Location() *time.Location {
+	if z == DueZoneUTC {
+		return time.UTC
+	}
+	return time.Local
+}
+
+// Now возвращает текущее время в поясе z: по нему определяется, какой сегодня день
+func (z DueZone) Now() time.Time {
+	return time.Now().In(z.Location())
+}
+
+// ParseDueDate разбирает срок «2006-01-02» как начало дня в поясе z
+func (z DueZone) ParseDueDate(text string) (time.Time, error) {
+	return time.ParseInLocation(DueDateLayout, text, z.Location())
+}
+
+// Today возвращает день момента now в поясе z в формате срока
+func (z DueZone) Today(now time.Time) string {
+	return now.In(z.Location()).Format(DueDateLayout)
+}
+
+// SetDueZone задает часовой пояс, в котором понимаются сроки задач
+func (tm *TaskManager) SetDueZone(zone DueZone) {
+	tm.dueZone = zone
+}
+
+// DueZone возвращает часовой пояс сроков задач
+func (tm *TaskManager) DueZone() DueZone {
+	return tm.dueZone
+}
+
+// DueDay возвращает день срока таким, каким его задали: в поясе, сохраненном вместе со сроком
+func (t *Task) DueDay() string {
+	return t.DueDate.Format(DueDateLayout)
+}
+
+// IsOverdue проверяет, что день срока прошел, а задача не выполнена. now задается
+// в поясе сроков: сегодняшний день - день now
+func (t *Task) IsOverdue(now time.Time) bool {
+	return !t.Completed && !t.DueDate.IsZero() && t.DueDay() < now.Format(DueDateLayout)
+}
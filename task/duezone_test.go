@@ -0,0 +1,59 @@
+package task
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDueZoneParse(t *testing.T) {
+	due, err := DueZoneUTC.ParseDueDate("2026-10-16")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), due)
+
+	due, err = DueZoneLocal.ParseDueDate("2026-10-16")
+	require.NoError(t, err)
+	assert.Equal(t, time.Local, due.Location())
+	assert.Equal(t, time.Local, DueZone("").Location())
+
+	_, err = DueZoneUTC.ParseDueDate("16.10.2026")
+	assert.Error(t, err)
+}
+
+func TestIsOverdueAcrossZones(t *testing.T) {
+	// Срок задан в Окленде (+12:00) и сохранен вместе со смещением
+	auckland := time.FixedZone("NZ", 12*60*60)
+	data, err := json.Marshal(&Task{DueDate: time.Date(2026, 10, 16, 0, 0, 0, 0, auckland)})
+	require.NoError(t, err)
+	var loaded Task
+	require.NoError(t, json.Unmarshal(data, &loaded))
+	assert.Equal(t, "2026-10-16", loaded.DueDay())
+
+	// В Нью-Йорке (-05:00) 15 октября: срок еще впереди, хотя момент полуночи в Окленде прошел
+	newYork := time.FixedZone("NY", -5*60*60)
+	evening := time.Date(2026, 10, 15, 20, 0, 0, 0, newYork)
+	assert.True(t, loaded.DueDate.Before(evening))
+	assert.False(t, loaded.IsOverdue(evening))
+	assert.False(t, loaded.IsOverdue(evening.AddDate(0, 0, 1)))
+	assert.True(t, loaded.IsOverdue(evening.AddDate(0, 0, 2)))
+
+	// В UTC тот же момент - уже 16 октября
+	assert.Equal(t, "2026-10-16", DueZoneUTC.Today(evening))
+}
+
+func TestValidateDueInZone(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+	tm.SetRequireDueAfterCreated(true)
+	tm.SetDueZone(DueZoneUTC)
+
+	// Срок «сегодня» по UTC допустим
+	today, _ := DueZoneUTC.ParseDueDate(DueZoneUTC.Today(time.Now()))
+	_, err := tm.AddTask("Отчет", "", 2, today)
+	assert.NoError(t, err)
+	_, err = tm.AddTask("Вчера", "", 2, today.AddDate(0, 0, -1))
+	assert.ErrorIs(t, err, ErrValidation)
+}
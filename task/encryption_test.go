@@ -0,0 +1,127 @@
+package task
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/storage"
+)
+
+func TestEncryptedTaskFile(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	tm.AddTask("Secret meeting", "Confidential notes", 3, time.Now())
+	assert.NoError(t, tm.SetPassphrase("s3cret"))
+	assert.True(t, tm.IsEncrypted())
+	assert.NoError(t, tm.SaveToFile())
+
+	// Содержимое задач не хранится в открытом виде
+	data, err := os.ReadFile(testFilename)
+	assert.NoError(t, err)
+	assert.False(t, strings.Contains(string(data), "Secret meeting"))
+
+	// Без пароля файл не загружается
+	tm2 := NewTaskManager(storage.NewFile(testFilename))
+	assert.ErrorIs(t, tm2.LoadFromFile(), ErrPassphraseRequired)
+
+	// Неверный пароль не меняет состояние менеджера
+	assert.ErrorIs(t, tm2.Unlock("wrong"), storage.ErrWrongPassphrase)
+	assert.False(t, tm2.IsEncrypted())
+
+	assert.NoError(t, tm2.Unlock("s3cret"))
+	assert.Equal(t, 1, len(tm2.tasks))
+	assert.Equal(t, "Secret meeting", tm2.tasks[0].Title)
+	assert.True(t, tm2.CheckPassphrase("s3cret"))
+
+	// Отключение шифрования перезаписывает файл в открытом виде
+	assert.NoError(t, tm2.SetPassphrase(""))
+	assert.NoError(t, tm2.SaveToFile())
+	data, err = os.ReadFile(testFilename)
+	assert.NoError(t, err)
+	assert.True(t, strings.Contains(string(data), "Secret meeting"))
+
+	// Файл, зашифрованный сброшенным паролем, в копии не попадает, а следующая копия открыта
+	backups, err := tm2.ListBackups()
+	assert.NoError(t, err)
+	assert.Empty(t, backups)
+	assert.NoError(t, tm2.SaveToFile())
+	backups, err = tm2.ListBackups()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(backups))
+	assert.Equal(t, 1, backups[0].TaskCount)
+}
+
+func TestSetPassphraseEncryptsBackups(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+	tm.SetBackupKeep(DefaultBackupKeep)
+
+	// Открытый файл и открытая резервная копия, записанные до шифрования
+	tm.AddTask("Secret meeting", "Confidential notes", 3, time.Now())
+	assert.NoError(t, tm.SaveToFile())
+	tm.AddTask("Another secret", "", 2, time.Now())
+	assert.NoError(t, tm.SaveToFile())
+
+	assertEncryptedBackups := func(count int) {
+		t.Helper()
+		backups, err := tm.ListBackups()
+		assert.NoError(t, err)
+		assert.Equal(t, count, len(backups))
+		for _, backup := range backups {
+			data, err := os.ReadFile(backup.Path)
+			assert.NoError(t, err)
+			assert.True(t, storage.IsEncrypted(data), backup.Path)
+			assert.False(t, strings.Contains(string(data), "secret"))
+			assert.Equal(t, 1, backup.TaskCount) // Копия читается с текущим паролем
+		}
+	}
+
+	// Прежняя копия зашифрована новым паролем, а открытый файл при сохранении в копии не попал
+	assert.NoError(t, tm.SetPassphrase("s3cret"))
+	assertEncryptedBackups(1)
+	assert.NoError(t, tm.SaveToFile())
+	assertEncryptedBackups(1)
+
+	// Смена пароля перешифровывает копии
+	assert.NoError(t, tm.SetPassphrase("n3w"))
+	assertEncryptedBackups(1)
+}
+
+func TestSetPassphraseOffDecryptsBackups(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+	tm.SetBackupKeep(DefaultBackupKeep)
+
+	tm.AddTask("Secret meeting", "Confidential notes", 3, time.Now())
+	assert.NoError(t, tm.SetPassphrase("s3cret"))
+	assert.NoError(t, tm.SaveToFile())
+	assert.NoError(t, tm.SaveToFile())
+
+	// Копия, которую не расшифровать прежним паролем, не дает отключить шифрование
+	backups, err := tm.ListBackups()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(backups))
+	foreign, err := storage.Encrypt([]byte("[]"), "other")
+	assert.NoError(t, err)
+	good, err := os.ReadFile(backups[0].Path)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(backups[0].Path, foreign, 0o600))
+	assert.ErrorIs(t, tm.SetPassphrase(""), storage.ErrWrongPassphrase)
+	assert.True(t, tm.IsEncrypted())
+	assert.NoError(t, os.WriteFile(backups[0].Path, good, 0o600))
+
+	// После отключения шифрования копии открыты и читаются без пароля
+	assert.NoError(t, tm.SetPassphrase(""))
+	backups, err = tm.ListBackups()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(backups))
+	data, err := os.ReadFile(backups[0].Path)
+	assert.NoError(t, err)
+	assert.False(t, storage.IsEncrypted(data))
+	assert.Equal(t, 1, backups[0].TaskCount)
+}
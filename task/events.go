@@ -0,0 +1,112 @@
+package task
+
+import "sync"
+
+// EventOp - вид изменения списка задач
+type EventOp string
+
+const (
+	EventAdded     EventOp = "added"
+	EventUpdated   EventOp = "updated"
+	EventCompleted EventOp = "completed" // изменен статус выполнения
+	EventDeleted   EventOp = "deleted"
+	EventReloaded  EventOp = "reloaded" // список заменен целиком: загрузка, восстановление, слияние
+
+	// EventOccurrence - отмечено или снято выполнение повторяющейся задачи в текущем периоде;
+	// сама задача при этом не выполнена
+	EventOccurrence EventOp = "occurrence"
+)
+
+// Event описывает изменение в менеджере задач
+type Event struct {
+	Op   EventOp `json:"op"`
+	Task *Task   `json:"task,omitempty"` // копия задачи после изменения (для удаления - до него), nil для EventReloaded
+}
+
+// subscriber - подписчик на события менеджера задач
+type subscriber struct {
+	id int
+	fn func(Event)
+}
+
+// Subscribe подписывает fn на изменения задач и возвращает функцию отписки.
+// Подписчики вызываются синхронно в том же потоке, где произошло изменение,
+// в порядке подписки
+func (tm *TaskManager) Subscribe(fn func(Event)) (unsubscribe func()) {
+	tm.nextSubscriberID++
+	id := tm.nextSubscriberID
+	tm.subscribers = append(tm.subscribers, subscriber{id: id, fn: fn})
+
+	return func() {
+		for i, sub := range tm.subscribers {
+			if sub.id == id {
+				tm.subscribers = append(tm.subscribers[:i:i], tm.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// emit рассылает событие подписчикам
+func (tm *TaskManager) emit(op EventOp, task *Task) {
+	event := Event{Op: op}
+	if task != nil {
+		event.Task = snapshotTask(task)
+	}
+	// Подписчик может отписаться во время рассылки, поэтому идем по копии
+	for _, sub := range append([]subscriber(nil), tm.subscribers...) {
+		sub.fn(event)
+	}
+}
+
+// snapshotTask возвращает копию задачи, которую подписчик может хранить и менять
+func snapshotTask(task *Task) *Task {
+	return task.Clone()
+}
+
+// Feed - подписка на события для чтения из другой горутины
+// (например, обработчиком сетевого соединения)
+type Feed struct {
+	// Events - очередь событий
+	Events <-chan Event
+	// Overflow закрывается, если очередь переполнилась и события начали теряться
+	Overflow <-chan struct{}
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewFeed подписывается на события s с очередью на size событий.
+// Вызывать нужно в потоке, где работает менеджер задач, а Close - из любой горутины
+func NewFeed(s TaskService, size int) *Feed {
+	events := make(chan Event, size)
+	overflow := make(chan struct{})
+	feed := &Feed{Events: events, Overflow: overflow, done: make(chan struct{})}
+
+	overflowed := false
+	var unsubscribe func()
+	unsubscribe = s.Subscribe(func(e Event) {
+		// Ждать поток менеджера задач из Close нельзя, поэтому
+		// закрытая подписка отписывается сама при следующем событии
+		select {
+		case <-feed.done:
+			unsubscribe()
+			return
+		default:
+		}
+		select {
+		case events <- e:
+		default:
+			if !overflowed {
+				overflowed = true
+				close(overflow)
+			}
+		}
+	})
+	return feed
+}
+
+// Close прекращает подписку
+func (f *Feed) Close() {
+	f.closeOnce.Do(func() { close(f.done) })
+}
@@ -0,0 +1,97 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribe(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	var events []Event
+	unsubscribe := tm.Subscribe(func(e Event) {
+		events = append(events, e)
+	})
+
+	task := mustAddTask(t, tm, "Task", "Description", 1, time.Now())
+	assert.NoError(t, tm.UpdateTask(task.ID, "Updated", "Description", 2, time.Now(), false))
+	assert.NoError(t, tm.SetTaskTags(task.ID, []string{"work"}))
+	assert.NoError(t, tm.ToggleTaskCompletion(task.ID))
+	assert.NoError(t, tm.SaveToFile())
+	assert.NoError(t, tm.DeleteTask(task.ID))
+	assert.NoError(t, tm.LoadFromFile())
+
+	// Неудачные операции событий не порождают
+	assert.Error(t, tm.DeleteTask(999))
+	_, err := tm.AddTask("", "Description", 1, time.Now())
+	assert.Error(t, err)
+
+	var ops []EventOp
+	for _, e := range events {
+		ops = append(ops, e.Op)
+	}
+	assert.Equal(t, []EventOp{EventAdded, EventUpdated, EventUpdated, EventCompleted, EventDeleted, EventReloaded}, ops)
+
+	// События несут копию задачи на момент изменения
+	assert.Equal(t, "Task", events[0].Task.Title)
+	assert.Equal(t, "Updated", events[1].Task.Title)
+	assert.True(t, events[3].Task.Completed)
+	assert.Equal(t, task.ID, events[4].Task.ID)
+	assert.Nil(t, events[5].Task)
+
+	events[2].Task.Tags[0] = "changed"
+	assert.Equal(t, []string{"work"}, task.Tags)
+
+	// После отписки события не приходят
+	unsubscribe()
+	mustAddTask(t, tm, "Task 2", "Description", 1, time.Now())
+	assert.Equal(t, 6, len(events))
+}
+
+func TestUnsubscribeDuringEmit(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	first, second := 0, 0
+	var unsubscribe func()
+	unsubscribe = tm.Subscribe(func(Event) {
+		first++
+		unsubscribe()
+	})
+	tm.Subscribe(func(Event) { second++ })
+
+	mustAddTask(t, tm, "Task 1", "Description", 1, time.Now())
+	mustAddTask(t, tm, "Task 2", "Description", 1, time.Now())
+	assert.Equal(t, 1, first)
+	assert.Equal(t, 2, second)
+}
+
+func TestFeed(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	feed := NewFeed(tm, 2)
+	mustAddTask(t, tm, "Task 1", "Description", 1, time.Now())
+	assert.Equal(t, EventAdded, (<-feed.Events).Op)
+
+	// Переполнение очереди закрывает Overflow
+	mustAddTask(t, tm, "Task 2", "Description", 1, time.Now())
+	mustAddTask(t, tm, "Task 3", "Description", 1, time.Now())
+	select {
+	case <-feed.Overflow:
+		t.Fatal("overflow before queue is full")
+	default:
+	}
+	mustAddTask(t, tm, "Task 4", "Description", 1, time.Now())
+	_, overflowed := <-feed.Overflow
+	assert.False(t, overflowed)
+
+	// Закрытая подписка отписывается при следующем событии
+	feed.Close()
+	feed.Close()
+	mustAddTask(t, tm, "Task 5", "Description", 1, time.Now())
+	assert.Empty(t, tm.subscribers)
+}
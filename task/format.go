@@ -0,0 +1,142 @@
+package task
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// currentFileVersion - версия формата файла задач, которую пишет приложение
+const currentFileVersion = 3
+
+// ErrUnsupportedVersion возвращается для файлов, записанных более новой версией приложения
+var ErrUnsupportedVersion = errors.New("tasks file was written by a newer version of the app")
+
+// fileEnvelope - содержимое файла задач: версия формата и данные
+type fileEnvelope struct {
+	Version int     `json:"version"`
+	Tasks   []*Task `json:"tasks"`
+}
+
+// migration преобразует документ файла задач из версии N в версию N+1.
+// Документ передается в разобранном виде (json.Unmarshal в any), чтобы
+// миграции не зависели от текущих Go-структур
+type migration func(doc any) (any, error)
+
+// migrations[N] переводит файл из версии N в версию N+1
+var migrations = []migration{
+	migrateV0ToV1,
+	migrateV1ToV2,
+	migrateV2ToV3,
+}
+
+// migrateV0ToV1 оборачивает старый формат (массив задач) в конверт с версией
+func migrateV0ToV1(doc any) (any, error) {
+	tasks, ok := doc.([]any)
+	if !ok {
+		return nil, errors.New("expected a list of tasks")
+	}
+	return map[string]any{"version": 1, "tasks": tasks}, nil
+}
+
+// migrateV1ToV2 выдает UUID задачам, созданным до их появления
+func migrateV1ToV2(doc any) (any, error) {
+	return migrateTasks(doc, 2, func(task map[string]any) {
+		if uuid, _ := task["uuid"].(string); uuid == "" {
+			task["uuid"] = newUUID()
+		}
+	})
+}
+
+// migrateV2ToV3 считает временем изменения старых задач время их создания
+func migrateV2ToV3(doc any) (any, error) {
+	return migrateTasks(doc, 3, func(task map[string]any) {
+		if _, ok := task["updated_at"]; !ok {
+			task["updated_at"] = task["created_at"]
+		}
+	})
+}
+
+// migrateTasks применяет изменение к каждой задаче конверта и выставляет новую версию
+func migrateTasks(doc any, version int, migrate func(task map[string]any)) (any, error) {
+	envelope, ok := doc.(map[string]any)
+	if !ok {
+		return nil, errors.New("expected an object")
+	}
+	tasks, ok := envelope["tasks"].([]any)
+	if !ok && envelope["tasks"] != nil {
+		return nil, errors.New("expected a list of tasks")
+	}
+	for _, task := range tasks {
+		if task, ok := task.(map[string]any); ok {
+			migrate(task) // Остальные ошибки покажет разбор задач
+		}
+	}
+	envelope["version"] = version
+	return envelope, nil
+}
+
+// fileVersion определяет версию формата разобранного документа
+func fileVersion(doc any) (int, error) {
+	switch doc := doc.(type) {
+	case []any:
+		return 0, nil // До появления версий файл был просто массивом задач
+	case map[string]any:
+		version, ok := doc["version"].(float64)
+		if !ok || version < 1 || version != float64(int(version)) {
+			return 0, errors.New("missing or invalid format version")
+		}
+		return int(version), nil
+	}
+	return 0, errors.New("unexpected file structure")
+}
+
+// decodeTasks разбирает содержимое файла задач любой поддерживаемой версии
+func decodeTasks(data []byte) ([]*Task, error) {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid tasks file: %w", err)
+	}
+
+	version, err := fileVersion(doc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tasks file: %w", err)
+	}
+	if version > currentFileVersion {
+		return nil, fmt.Errorf("%w (version %d, supported %d)", ErrUnsupportedVersion, version, currentFileVersion)
+	}
+
+	// Последовательно применяем миграции до текущей версии
+	for ; version < currentFileVersion; version++ {
+		if doc, err = migrations[version](doc); err != nil {
+			return nil, fmt.Errorf("migrating tasks file from version %d: %w", version, err)
+		}
+	}
+
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var envelope fileEnvelope
+	if err := json.Unmarshal(migrated, &envelope); err != nil {
+		return nil, fmt.Errorf("invalid tasks file: %w", err)
+	}
+
+	for _, task := range envelope.Tasks {
+		if task == nil {
+			return nil, errors.New("invalid tasks file: empty task entry")
+		}
+	}
+	if envelope.Tasks == nil {
+		envelope.Tasks = []*Task{}
+	}
+	return envelope.Tasks, nil
+}
+
+// encodeTasks записывает задачи в текущем формате файла
+func encodeTasks(tasks []*Task) ([]byte, error) {
+	return json.MarshalIndent(fileEnvelope{
+		Version: currentFileVersion,
+		Tasks:   tasks,
+	}, "", "  ")
+}
@@ -0,0 +1,107 @@
+package task
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/storage"
+)
+
+func TestLoadLegacyFile(t *testing.T) {
+	defer teardownTestManager()
+
+	// Файл старого формата - просто массив задач
+	legacy := `[
+  {
+    "id": 7,
+    "title": "Название",
+    "description": "описание",
+    "priority": 1,
+    "due_date": "2025-12-10T00:00:00Z",
+    "created_at": "2025-12-09T17:29:39.7292425+07:00",
+    "completed": false
+  }
+]`
+	assert.NoError(t, os.WriteFile(testFilename, []byte(legacy), 0644))
+
+	tm := NewTaskManager(storage.NewFile(testFilename))
+	assert.NoError(t, tm.LoadFromFile())
+	assert.Equal(t, 1, len(tm.tasks))
+	assert.Equal(t, "Название", tm.tasks[0].Title)
+	assert.Equal(t, 8, tm.nextID)
+	assert.NotEmpty(t, tm.tasks[0].UUID, "Старым задачам выдается UUID")
+
+	// При сохранении файл переписывается в текущем формате
+	assert.NoError(t, tm.SaveToFile())
+	data, err := os.ReadFile(testFilename)
+	assert.NoError(t, err)
+
+	var envelope map[string]any
+	assert.NoError(t, json.Unmarshal(data, &envelope))
+	assert.Equal(t, float64(currentFileVersion), envelope["version"])
+}
+
+func TestSaveWritesEnvelope(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	tm.AddTask("Task 1", "Description", 2, time.Now())
+	assert.NoError(t, tm.SaveToFile())
+
+	data, err := os.ReadFile(testFilename)
+	assert.NoError(t, err)
+
+	var envelope fileEnvelope
+	assert.NoError(t, json.Unmarshal(data, &envelope))
+	assert.Equal(t, currentFileVersion, envelope.Version)
+	assert.Equal(t, 1, len(envelope.Tasks))
+}
+
+func TestMigrateV1ToV2(t *testing.T) {
+	tasks, err := decodeTasks([]byte(`{"version": 1, "tasks": [
+		{"id": 1, "title": "Old"},
+		{"id": 2, "title": "Synced", "uuid": "kept"}
+	]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(tasks))
+	assert.NotEmpty(t, tasks[0].UUID)
+	assert.Equal(t, "kept", tasks[1].UUID)
+}
+
+func TestMigrateV2ToV3(t *testing.T) {
+	tasks, err := decodeTasks([]byte(`{"version": 2, "tasks": [
+		{"id": 1, "title": "Old", "uuid": "a", "created_at": "2025-12-09T17:29:39Z"}
+	]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, tasks[0].CreatedAt, tasks[0].UpdatedAt)
+}
+
+func TestDecodeTasksErrors(t *testing.T) {
+	// Файл из будущей версии не читается молча
+	_, err := decodeTasks([]byte(`{"version": 99, "tasks": []}`))
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+
+	// Повреждения и неожиданная структура дают понятную ошибку
+	for _, data := range []string{
+		`not json`,
+		`{"tasks": []}`,
+		`{"version": "1", "tasks": []}`,
+		`{"version": 1, "tasks": {}}`,
+		`{"version": 3, "tasks": {}}`,
+		`{"version": 3, "tasks": [null]}`,
+		`"text"`,
+	} {
+		_, err := decodeTasks([]byte(data))
+		assert.Error(t, err, data)
+	}
+
+	// Пустой конверт - это пустой список, а не nil
+	tasks, err := decodeTasks([]byte(`{"version": 3}`))
+	assert.NoError(t, err)
+	assert.NotNil(t, tasks)
+	assert.Equal(t, 0, len(tasks))
+}
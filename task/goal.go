@@ -0,0 +1,29 @@
+package task
+
+// Цели описаны в пакете goals; задача хранит только идентификатор своей цели
+
+// SetTaskGoal связывает задачу с целью goal; пустая строка отвязывает задачу
+func (tm *TaskManager) SetTaskGoal(id int, goal string) error {
+	task := tm.GetTask(id)
+	if task == nil {
+		return notFoundError(id)
+	}
+	if task.Goal == goal {
+		return nil
+	}
+	task.Goal = goal
+	tm.touch(task)
+	tm.emit(EventUpdated, task)
+	return nil
+}
+
+// ClearGoal отвязывает от цели goal все задачи, например когда цель удалили
+func (tm *TaskManager) ClearGoal(goal string) {
+	for _, task := range tm.tasks {
+		if goal != "" && task.Goal == goal {
+			task.Goal = ""
+			tm.touch(task)
+			tm.emit(EventUpdated, task)
+		}
+	}
+}
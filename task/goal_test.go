@@ -0,0 +1,27 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetTaskGoal(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	report := mustAddTask(t, tm, "Отчет", "", 2, time.Now())
+	launch := mustAddTask(t, tm, "Запуск", "", 3, time.Now())
+	require.NoError(t, tm.SetTaskGoal(report.ID, "q4"))
+	require.NoError(t, tm.SetTaskGoal(launch.ID, "q4"))
+	assert.Equal(t, "q4", report.Goal)
+	assert.ErrorIs(t, tm.SetTaskGoal(999, "q4"), ErrNotFound)
+
+	require.NoError(t, tm.SetTaskGoal(launch.ID, ""))
+	assert.Empty(t, launch.Goal)
+
+	tm.ClearGoal("q4")
+	assert.Empty(t, report.Goal)
+}
@@ -0,0 +1,37 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxIconLength - сколько символов может занимать значок: эмодзи с модификаторами
+// и склейками вроде «👩‍💻» состоят из нескольких символов
+const maxIconLength = 8
+
+// SetTaskIcon задает значок задачи - эмодзи перед названием; пустая строка убирает значок
+func (tm *TaskManager) SetTaskIcon(id int, icon string) error {
+	task := tm.GetTask(id)
+	if task == nil {
+		return notFoundError(id)
+	}
+	icon = strings.TrimSpace(icon)
+	if len([]rune(icon)) > maxIconLength {
+		return &ValidationError{Field: "icon", Message: fmt.Sprintf("must be at most %d characters", maxIconLength)}
+	}
+	if task.Icon == icon {
+		return nil
+	}
+	task.Icon = icon
+	tm.touch(task)
+	tm.emit(EventUpdated, task)
+	return nil
+}
+
+// IconTitle возвращает название задачи со значком впереди, если он задан
+func (t *Task) IconTitle() string {
+	if t.Icon == "" {
+		return t.Title
+	}
+	return t.Icon + " " + t.Title
+}
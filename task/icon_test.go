@@ -0,0 +1,28 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetTaskIcon(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	trip := mustAddTask(t, tm, "Купить билеты", "", 2, time.Now())
+	assert.Equal(t, "Купить билеты", trip.IconTitle())
+
+	require.NoError(t, tm.SetTaskIcon(trip.ID, " ✈️ "))
+	assert.Equal(t, "✈️", trip.Icon)
+	assert.Equal(t, "✈️ Купить билеты", trip.IconTitle())
+
+	assert.ErrorIs(t, tm.SetTaskIcon(trip.ID, "слишком длинно"), ErrValidation)
+	assert.Equal(t, "✈️", trip.Icon)
+	assert.ErrorIs(t, tm.SetTaskIcon(999, "✈️"), ErrNotFound)
+
+	require.NoError(t, tm.SetTaskIcon(trip.ID, ""))
+	assert.Empty(t, trip.Icon)
+}
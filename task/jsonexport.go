@@ -0,0 +1,220 @@
+package task
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Формат выгрузки задач в JSON. В отличие от файла задач, выгрузка предназначена для обмена:
+// ее читают другие программы и другие копии приложения, поэтому у нее своя схема
+const (
+	ExportFormat = "taskmanager-export"
+	ExportSchema = 1
+)
+
+// ErrNotExport возвращается при импорте файла, который не является выгрузкой задач
+var ErrNotExport = errors.New("file is not a task export")
+
+// ExportEnvelope - выгрузка задач в JSON: метаданные и задачи
+type ExportEnvelope struct {
+	Format     string    `json:"format"`
+	Schema     int       `json:"schema"`
+	AppVersion string    `json:"app_version,omitempty"`
+	ExportedAt time.Time `json:"exported_at"`
+	Tasks      []*Task   `json:"tasks"`
+}
+
+// JSONExportOptions - настройки выгрузки в JSON
+type JSONExportOptions struct {
+	AppVersion      string // версия приложения для метаданных
+	Pretty          bool   // с отступами для чтения человеком; иначе компактно
+	IncludeArchived bool   // выгрузить и задачи из архива
+}
+
+// ImportDuplicates - что делать с задачей из файла, которая уже есть в списке
+type ImportDuplicates string
+
+const (
+	ImportSkip    ImportDuplicates = "skip"    // оставить свою задачу
+	ImportReplace ImportDuplicates = "replace" // заменить задачей из файла
+	ImportCopy    ImportDuplicates = "copy"    // оставить обе: добавить копию с новым UUID
+)
+
+// ImportDuplicate - задача из файла, которая, вероятно, уже есть в списке
+type ImportDuplicate struct {
+	Imported *Task
+	Existing *Task
+	SameUUID bool // совпал UUID; иначе совпали название и день срока
+}
+
+// ImportResult - итог импорта
+type ImportResult struct {
+	Added, Replaced, Skipped int
+}
+
+// WriteJSON записывает выгрузку задач в JSON
+func WriteJSON(w io.Writer, tasks []*Task, options JSONExportOptions) error {
+	envelope := ExportEnvelope{
+		Format:     ExportFormat,
+		Schema:     ExportSchema,
+		AppVersion: options.AppVersion,
+		ExportedAt: time.Now().UTC(),
+		Tasks:      []*Task{},
+	}
+	for _, task := range tasks {
+		if options.IncludeArchived || !task.Archived {
+			envelope.Tasks = append(envelope.Tasks, task)
+		}
+	}
+	encoder := json.NewEncoder(w)
+	if options.Pretty {
+		encoder.SetIndent("", "  ")
+	}
+	return encoder.Encode(envelope)
+}
+
+// ExportToJSON выгружает задачи в файл JSON
+func (tm *TaskManager) ExportToJSON(filename string, options JSONExportOptions) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := WriteJSON(file, tm.tasks, options); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// ReadJSON читает выгрузку задач и проверяет ее: формат, схему и каждую задачу
+func ReadJSON(r io.Reader) (*ExportEnvelope, error) {
+	var envelope ExportEnvelope
+	if err := json.NewDecoder(r).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotExport, err)
+	}
+	if envelope.Format != ExportFormat {
+		return nil, ErrNotExport
+	}
+	if envelope.Schema > ExportSchema {
+		return nil, fmt.Errorf("%w: export schema %d", ErrUnsupportedVersion, envelope.Schema)
+	}
+	seen := make(map[string]bool)
+	for i, task := range envelope.Tasks {
+		if err := validateImported(task); err != nil {
+			return nil, fmt.Errorf("task %d: %w", i+1, err)
+		}
+		if seen[task.UUID] {
+			return nil, fmt.Errorf("task %d: %w", i+1, &ValidationError{Field: "uuid", Message: "duplicated in the file"})
+		}
+		seen[task.UUID] = true
+	}
+	return &envelope, nil
+}
+
+// validateImported проверяет задачу из выгрузки. Срок раньше дня создания и задача без срока
+// допустимы: задачи выгружены такими, какими их сохранили
+func validateImported(task *Task) error {
+	if task == nil {
+		return &ValidationError{Field: "task", Message: "must not be null"}
+	}
+	if strings.TrimSpace(task.Title) == "" {
+		return &ValidationError{Field: "title", Message: "must not be empty"}
+	}
+	if len([]rune(task.Title)) > maxTitleLength {
+		return &ValidationError{Field: "title", Message: fmt.Sprintf("must be at most %d characters", maxTitleLength)}
+	}
+	if task.Priority < minPriority || task.Priority > maxPriority {
+		return &ValidationError{Field: "priority", Message: fmt.Sprintf("must be between %d and %d", minPriority, maxPriority)}
+	}
+	if strings.TrimSpace(task.UUID) == "" {
+		return &ValidationError{Field: "uuid", Message: "must not be empty"}
+	}
+	return nil
+}
+
+// ImportJSON читает выгрузку и добавляет ее задачи, как Import.
+// Если выгрузка некорректна, список не меняется
+func (tm *TaskManager) ImportJSON(r io.Reader, duplicates ImportDuplicates) (ImportResult, error) {
+	envelope, err := ReadJSON(r)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	return tm.Import(envelope, duplicates), nil
+}
+
+// findDuplicate ищет в списке задачу, которую повторяет imported: с тем же UUID, а если такой нет -
+// с тем же названием без учета регистра и тем же днем срока. Так находятся и задачи из программ,
+// которые не сохраняют UUID
+func (tm *TaskManager) findDuplicate(imported *Task) (existing *Task, sameUUID bool) {
+	if existing := tm.GetTaskByUUID(imported.UUID); existing != nil {
+		return existing, true
+	}
+	title := strings.ToLower(strings.TrimSpace(imported.Title))
+	for _, task := range tm.tasks {
+		if strings.ToLower(strings.TrimSpace(task.Title)) == title && task.DueDay() == imported.DueDay() {
+			return task, false
+		}
+	}
+	return nil, false
+}
+
+// FindDuplicates возвращает задачи из файла, которые, вероятно, уже есть в списке,
+// чтобы перед импортом спросить, что с ними делать
+func (tm *TaskManager) FindDuplicates(tasks []*Task) []ImportDuplicate {
+	var duplicates []ImportDuplicate
+	for _, imported := range tasks {
+		if existing, sameUUID := tm.findDuplicate(imported); existing != nil {
+			duplicates = append(duplicates, ImportDuplicate{Imported: imported, Existing: existing, SameUUID: sameUUID})
+		}
+	}
+	return duplicates
+}
+
+// Import добавляет задачи из выгрузки, прочитанной ReadJSON. Задачи сопоставляются по UUID,
+// затем по названию и дню срока; совпавшие обрабатываются по duplicates
+func (tm *TaskManager) Import(envelope *ExportEnvelope, duplicates ImportDuplicates) ImportResult {
+	var result ImportResult
+	for _, imported := range envelope.Tasks {
+		existing, _ := tm.findDuplicate(imported)
+		if existing == nil {
+			tm.addMergedTask(imported)
+			result.Added++
+			continue
+		}
+		switch duplicates {
+		case ImportReplace:
+			// Задача, совпавшая по названию, заменяется на месте и сохраняет свой UUID
+			replacement := imported.Clone()
+			replacement.UUID = existing.UUID
+			tm.ApplyRemote(replacement)
+			result.Replaced++
+		case ImportCopy:
+			copied := imported.Clone()
+			copied.UUID = newUUID()
+			copied.ID = 0
+			tm.addMergedTask(copied)
+			result.Added++
+		default:
+			result.Skipped++
+		}
+	}
+	if result.Added > 0 {
+		tm.dirty = true
+	}
+	return result
+}
+
+// ImportFromJSON добавляет задачи из файла выгрузки, как ImportJSON
+func (tm *TaskManager) ImportFromJSON(filename string, duplicates ImportDuplicates) (ImportResult, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	defer file.Close()
+	return tm.ImportJSON(file, duplicates)
+}
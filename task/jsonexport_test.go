@@ -0,0 +1,104 @@
+package task
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImportJSON(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	report := mustAddTask(t, tm, "Сдать отчет", "", 3, time.Now())
+	old := mustAddTask(t, tm, "Старая задача", "", 1, time.Now())
+	require.NoError(t, tm.SetTaskArchived(old.ID, true))
+
+	var compact, pretty bytes.Buffer
+	require.NoError(t, WriteJSON(&compact, tm.Tasks(), JSONExportOptions{AppVersion: "1.4.0"}))
+	require.NoError(t, WriteJSON(&pretty, tm.Tasks(), JSONExportOptions{Pretty: true, IncludeArchived: true}))
+	assert.Equal(t, 1, strings.Count(compact.String(), "\n"))
+	assert.Contains(t, pretty.String(), "\n  \"schema\": 1,\n")
+
+	envelope, err := ReadJSON(bytes.NewReader(compact.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, "1.4.0", envelope.AppVersion)
+	assert.Len(t, envelope.Tasks, 1)
+
+	// Повторный импорт: совпавшие по UUID задачи пропускаются, заменяются или копируются
+	result, err := tm.ImportJSON(bytes.NewReader(pretty.Bytes()), ImportSkip)
+	require.NoError(t, err)
+	assert.Equal(t, ImportResult{Skipped: 2}, result)
+
+	require.NoError(t, tm.UpdateTask(report.ID, "Переименовано", "", 3, report.DueDate, false))
+	result, err = tm.ImportJSON(bytes.NewReader(compact.Bytes()), ImportReplace)
+	require.NoError(t, err)
+	assert.Equal(t, ImportResult{Replaced: 1}, result)
+	assert.Equal(t, "Сдать отчет", tm.GetTask(report.ID).Title)
+
+	result, err = tm.ImportJSON(bytes.NewReader(compact.Bytes()), ImportCopy)
+	require.NoError(t, err)
+	assert.Equal(t, ImportResult{Added: 1}, result)
+	assert.Len(t, tm.Tasks(), 3)
+	assert.NotEqual(t, report.UUID, tm.Tasks()[2].UUID)
+
+	filename := filepath.Join(t.TempDir(), "export.json")
+	require.NoError(t, tm.ExportToJSON(filename, JSONExportOptions{}))
+	result, err = tm.ImportFromJSON(filename, ImportSkip)
+	require.NoError(t, err)
+	assert.Equal(t, ImportResult{Skipped: 2}, result)
+}
+
+func TestImportTitleDuplicates(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	due := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	report := mustAddTask(t, tm, "Сдать отчет", "", 2, due)
+	// Задачи из других программ приходят со своими UUID: похожими считаются те же название и день
+	imported := []*Task{
+		{UUID: "other-1", Title: "  сдать ОТЧЕТ ", Priority: 3, DueDate: due.Add(15 * time.Hour)},
+		{UUID: "other-2", Title: "Сдать отчет", Priority: 3, DueDate: due.AddDate(0, 0, 1)},
+		{UUID: report.UUID, Title: "Переименовано", Priority: 1, DueDate: due},
+	}
+	duplicates := tm.FindDuplicates(imported)
+	require.Len(t, duplicates, 2)
+	assert.Equal(t, report.ID, duplicates[0].Existing.ID)
+	assert.False(t, duplicates[0].SameUUID)
+	assert.True(t, duplicates[1].SameUUID)
+
+	result := tm.Import(&ExportEnvelope{Tasks: imported[:2]}, ImportSkip)
+	assert.Equal(t, ImportResult{Added: 1, Skipped: 1}, result)
+
+	// Задача, совпавшая по названию, заменяется на месте и сохраняет UUID
+	result = tm.Import(&ExportEnvelope{Tasks: imported[:1]}, ImportReplace)
+	assert.Equal(t, ImportResult{Replaced: 1}, result)
+	replaced := tm.GetTask(report.ID)
+	assert.Equal(t, report.UUID, replaced.UUID)
+	assert.Equal(t, 3, replaced.Priority)
+	assert.Nil(t, tm.GetTaskByUUID("other-1"))
+
+	result = tm.Import(&ExportEnvelope{Tasks: imported[:1]}, ImportCopy)
+	assert.Equal(t, ImportResult{Added: 1}, result)
+	assert.Len(t, tm.Tasks(), 3)
+}
+
+func TestReadJSONValidation(t *testing.T) {
+	_, err := ReadJSON(strings.NewReader(`{"version": 3, "tasks": []}`))
+	assert.ErrorIs(t, err, ErrNotExport)
+	_, err = ReadJSON(strings.NewReader(`не json`))
+	assert.ErrorIs(t, err, ErrNotExport)
+	_, err = ReadJSON(strings.NewReader(`{"format": "taskmanager-export", "schema": 99}`))
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+
+	task := `{"uuid": "a", "title": "Отчет", "priority": 2, "due_date": "2026-10-16T00:00:00Z"}`
+	_, err = ReadJSON(strings.NewReader(`{"format": "taskmanager-export", "schema": 1, "tasks": [` + task + `, ` + task + `]}`))
+	assert.ErrorIs(t, err, ErrValidation)
+	_, err = ReadJSON(strings.NewReader(`{"format": "taskmanager-export", "schema": 1, "tasks": [{"uuid": "a", "title": "Отчет", "priority": 5}]}`))
+	assert.ErrorIs(t, err, ErrValidation)
+}
@@ -0,0 +1,106 @@
+package task
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+// mentionPattern находит упоминания задач в тексте: «см. #42»
+var mentionPattern = regexp.MustCompile(`(^|[^\p{L}\p{N}_&])#(\d+)\b`)
+
+// Mention - упоминание задачи в тексте
+type Mention struct {
+	ID         int
+	Start, End int // границы «#42» в байтах
+}
+
+// FindMentions возвращает упоминания задач вида #42 в порядке появления
+func FindMentions(text string) []Mention {
+	var mentions []Mention
+	for _, m := range mentionPattern.FindAllStringSubmatchIndex(text, -1) {
+		id, err := strconv.Atoi(text[m[4]:m[5]])
+		if err != nil {
+			continue
+		}
+		mentions = append(mentions, Mention{ID: id, Start: m[4] - 1, End: m[5]})
+	}
+	return mentions
+}
+
+// LinkTasks связывает две задачи. Связь видна с обеих сторон
+func (tm *TaskManager) LinkTasks(id, otherID int) error {
+	task, other := tm.GetTask(id), tm.GetTask(otherID)
+	if task == nil {
+		return notFoundError(id)
+	}
+	if other == nil {
+		return notFoundError(otherID)
+	}
+	if task == other {
+		return &ValidationError{Field: "link", Message: "task cannot be linked to itself"}
+	}
+	tm.link(task, other)
+	return nil
+}
+
+// UnlinkTasks удаляет связь между задачами с обеих сторон
+func (tm *TaskManager) UnlinkTasks(id, otherID int) error {
+	task, other := tm.GetTask(id), tm.GetTask(otherID)
+	if task == nil {
+		return notFoundError(id)
+	}
+	if other == nil {
+		return notFoundError(otherID)
+	}
+	tm.unlink(task, other.UUID)
+	tm.unlink(other, task.UUID)
+	return nil
+}
+
+// LinkedTasks возвращает задачи, связанные с задачей id
+func (tm *TaskManager) LinkedTasks(id int) []*Task {
+	task := tm.GetTask(id)
+	if task == nil {
+		return nil
+	}
+	var linked []*Task
+	for _, uuid := range task.Links {
+		if other := tm.GetTaskByUUID(uuid); other != nil {
+			linked = append(linked, other)
+		}
+	}
+	return linked
+}
+
+// link добавляет связь с обеих сторон, если ее еще нет
+func (tm *TaskManager) link(task, other *Task) {
+	for _, pair := range [][2]*Task{{task, other}, {other, task}} {
+		from, to := pair[0], pair[1]
+		if slices.Contains(from.Links, to.UUID) {
+			continue
+		}
+		from.Links = append(from.Links, to.UUID)
+		tm.touch(from)
+		tm.emit(EventUpdated, from)
+	}
+}
+
+// unlink убирает из задачи ссылку на uuid
+func (tm *TaskManager) unlink(task *Task, uuid string) {
+	if !slices.Contains(task.Links, uuid) {
+		return
+	}
+	task.Links = slices.DeleteFunc(slices.Clone(task.Links), func(link string) bool { return link == uuid })
+	tm.touch(task)
+	tm.emit(EventUpdated, task)
+}
+
+// linkMentions связывает задачу с задачами, упомянутыми в ее описании
+func (tm *TaskManager) linkMentions(task *Task) {
+	for _, mention := range FindMentions(task.Description) {
+		if other := tm.GetTask(mention.ID); other != nil && other != task {
+			tm.link(task, other)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindMentions(t *testing.T) {
+	text := "Связано с #42 и #7, но не с задача#5 и не &#39;"
+	mentions := FindMentions(text)
+	if assert.Len(t, mentions, 2) {
+		assert.Equal(t, 42, mentions[0].ID)
+		assert.Equal(t, "#42", text[mentions[0].Start:mentions[0].End])
+		assert.Equal(t, 7, mentions[1].ID)
+	}
+	assert.Len(t, FindMentions("#1 в начале"), 1)
+}
+
+func TestLinkTasks(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	plan := mustAddTask(t, tm, "Составить план", "", 2, time.Now())
+	budget := mustAddTask(t, tm, "Посчитать бюджет", "", 2, time.Now())
+	trip := mustAddTask(t, tm, "Купить билеты", "", 2, time.Now())
+
+	assert.NoError(t, tm.LinkTasks(plan.ID, budget.ID))
+	assert.Equal(t, []*Task{budget}, tm.LinkedTasks(plan.ID))
+	assert.Equal(t, []*Task{plan}, tm.LinkedTasks(budget.ID))
+	assert.ErrorIs(t, tm.LinkTasks(plan.ID, plan.ID), ErrValidation)
+	assert.ErrorIs(t, tm.LinkTasks(plan.ID, 999), ErrNotFound)
+
+	// Упоминание в описании связывает задачи
+	assert.NoError(t, tm.UpdateTask(trip.ID, trip.Title, "После #1", 2, trip.DueDate, false))
+	assert.Equal(t, []*Task{budget, trip}, tm.LinkedTasks(plan.ID))
+	assert.Equal(t, []*Task{plan}, tm.LinkedTasks(trip.ID))
+
+	assert.NoError(t, tm.UnlinkTasks(budget.ID, plan.ID))
+	assert.Empty(t, tm.LinkedTasks(budget.ID))
+	assert.Equal(t, []*Task{trip}, tm.LinkedTasks(plan.ID))
+
+	// Удаленная задача исчезает из связей
+	assert.NoError(t, tm.DeleteTask(trip.ID))
+	assert.Empty(t, tm.GetTask(plan.ID).Links)
+}
@@ -0,0 +1,63 @@
+package task
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SetTaskLocation задает место выполнения задачи: адрес или координаты «55.75, 37.62»
+func (tm *TaskManager) SetTaskLocation(id int, location string) error {
+	task := tm.GetTask(id)
+	if task == nil {
+		return notFoundError(id)
+	}
+
+	task.Location = strings.TrimSpace(location)
+	tm.touch(task)
+	tm.emit(EventUpdated, task)
+	return nil
+}
+
+// Locations возвращает места задач без повторов и по алфавиту
+func (tm *TaskManager) Locations() []string {
+	var locations []string
+	for _, task := range tm.tasks {
+		if task.Location != "" && !slices.Contains(locations, task.Location) {
+			locations = append(locations, task.Location)
+		}
+	}
+	slices.Sort(locations)
+	return locations
+}
+
+// ParseGeo разбирает координаты «широта, долгота». ok ложно, если место задано адресом
+func ParseGeo(location string) (lat, lon float64, ok bool) {
+	latText, lonText, found := strings.Cut(location, ",")
+	if !found {
+		return 0, 0, false
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latText), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return 0, 0, false
+	}
+	lon, err = strconv.ParseFloat(strings.TrimSpace(lonText), 64)
+	if err != nil || lon < -180 || lon > 180 {
+		return 0, 0, false
+	}
+	return lat, lon, true
+}
+
+// MapsURL возвращает ссылку на место в OpenStreetMap: точку по координатам или поиск по адресу.
+// Пустая строка - у задачи нет места
+func MapsURL(location string) string {
+	if location == "" {
+		return ""
+	}
+	if lat, lon, ok := ParseGeo(location); ok {
+		return fmt.Sprintf("https://www.openstreetmap.org/?mlat=%g&mlon=%g#map=17/%g/%g", lat, lon, lat, lon)
+	}
+	return "https://www.openstreetmap.org/search?query=" + url.QueryEscape(location)
+}
@@ -0,0 +1,36 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetTaskLocation(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	bread := mustAddTask(t, tm, "Купить хлеб", "", 2, time.Now())
+	mail := mustAddTask(t, tm, "Забрать посылку", "", 2, time.Now())
+	assert.NoError(t, tm.SetTaskLocation(bread.ID, " Пекарня "))
+	assert.NoError(t, tm.SetTaskLocation(mail.ID, "Почта"))
+	assert.Equal(t, "Пекарня", tm.GetTask(bread.ID).Location)
+	assert.Equal(t, []string{"Пекарня", "Почта"}, tm.Locations())
+	assert.ErrorIs(t, tm.SetTaskLocation(999, ""), ErrNotFound)
+}
+
+func TestMapsURL(t *testing.T) {
+	lat, lon, ok := ParseGeo("55.7539, 37.6208")
+	assert.True(t, ok)
+	assert.Equal(t, 55.7539, lat)
+	assert.Equal(t, 37.6208, lon)
+	_, _, ok = ParseGeo("Москва, Тверская")
+	assert.False(t, ok)
+	_, _, ok = ParseGeo("95, 10")
+	assert.False(t, ok)
+
+	assert.Equal(t, "https://www.openstreetmap.org/?mlat=55.7539&mlon=37.6208#map=17/55.7539/37.6208", MapsURL("55.7539, 37.6208"))
+	assert.Equal(t, "https://www.openstreetmap.org/search?query=%D0%9F%D0%BE%D1%87%D1%82%D0%B0+1", MapsURL("Почта 1"))
+	assert.Empty(t, MapsURL(""))
+}
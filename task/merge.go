@@ -0,0 +1,299 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MergeField - поле задачи, которое сравнивается при слиянии файлов
+type MergeField struct {
+	Key   string
+	Title string
+	Value func(task *Task) string // значение для сравнения и показа пользователю
+	apply func(dst, src *Task)
+}
+
+// MergeFields - поля, различия в которых считаются конфликтом
+var MergeFields = []MergeField{
+	{"title", "Название",
+		func(t *Task) string { return t.Title },
+		func(dst, src *Task) { dst.Title = src.Title }},
+	{"description", "Описание",
+		func(t *Task) string { return t.Description },
+		func(dst, src *Task) { setDescription(dst, src.Description, time.Now()) }},
+	{"priority", "Приоритет",
+		func(t *Task) string { return PriorityText(t.Priority) },
+		func(dst, src *Task) { dst.Priority = src.Priority }},
+	{"due", "Срок",
+		func(t *Task) string { return t.DueDate.Format("2006-01-02 15:04") },
+		func(dst, src *Task) { dst.DueDate = src.DueDate }},
+	{"start", "Начало",
+		func(t *Task) string { return formatOptionalTime(t.StartDate) },
+		func(dst, src *Task) { dst.StartDate = src.StartDate }},
+	{"completed", "Выполнена",
+		func(t *Task) string { return yesNo(t.Completed) },
+		func(dst, src *Task) { dst.Completed, dst.CompletedAt = src.Completed, src.CompletedAt }},
+	{"tags", "Метки",
+		func(t *Task) string { return strings.Join(t.Tags, ", ") },
+		func(dst, src *Task) { dst.Tags = append([]string(nil), src.Tags...) }},
+	{"assignee", "Исполнитель",
+		func(t *Task) string { return t.Assignee },
+		func(dst, src *Task) { dst.Assignee = src.Assignee }},
+	{"url", "Ссылка",
+		func(t *Task) string { return t.URL },
+		func(dst, src *Task) { dst.URL = src.URL }},
+	{"location", "Место",
+		func(t *Task) string { return t.Location },
+		func(dst, src *Task) { dst.Location = src.Location }},
+	{"waiting_on", "Ждем",
+		func(t *Task) string { return t.WaitingOn },
+		func(dst, src *Task) { dst.WaitingOn = src.WaitingOn }},
+	{"goal", "Цель",
+		func(t *Task) string { return t.Goal },
+		func(dst, src *Task) { dst.Goal = src.Goal }},
+	{"icon", "Значок",
+		func(t *Task) string { return t.Icon },
+		func(dst, src *Task) { dst.Icon = src.Icon }},
+	{"urgency", "Срочность напоминания",
+		func(t *Task) string { return t.Urgency.Text() },
+		func(dst, src *Task) { dst.Urgency = src.Urgency }},
+	{"sound", "Звук напоминания",
+		func(t *Task) string { return t.Sound },
+		func(dst, src *Task) { dst.Sound = src.Sound }},
+	{"context", "Контекст",
+		func(t *Task) string { return t.Context },
+		func(dst, src *Task) { dst.Context = src.Context }},
+	{"archived", "В архиве",
+		func(t *Task) string { return yesNo(t.Archived) },
+		func(dst, src *Task) { dst.Archived = src.Archived }},
+	{"checklist", "Чек-лист",
+		func(t *Task) string { return checklistText(t.Checklist) },
+		func(dst, src *Task) { dst.Checklist = append([]ChecklistItem(nil), src.Checklist...) }},
+	{"recurrence", "Повторение",
+		func(t *Task) string { return t.Recurrence.Text() },
+		func(dst, src *Task) { dst.Recurrence = src.Recurrence }},
+	{"completions", "Отметки выполнения",
+		func(t *Task) string { return fmt.Sprint(len(t.Completions)) },
+		func(dst, src *Task) { dst.Completions = append([]time.Time(nil), src.Completions...) }},
+	{"links", "Связанные задачи",
+		func(t *Task) string { return strings.Join(t.Links, ", ") },
+		func(dst, src *Task) { dst.Links = append([]string(nil), src.Links...) }},
+	{"depends_on", "Зависит от",
+		func(t *Task) string { return strings.Join(t.DependsOn, ", ") },
+		func(dst, src *Task) { dst.DependsOn = append([]string(nil), src.DependsOn...) }},
+	{"remind_at", "Напоминание",
+		func(t *Task) string { return formatOptionalTime(t.RemindAt) },
+		func(dst, src *Task) { dst.RemindAt = src.RemindAt }},
+	{"reminder_offsets", "Напоминания до срока",
+		func(t *Task) string { return fmt.Sprint(t.ReminderOffsets) },
+		func(dst, src *Task) { dst.ReminderOffsets = append([]time.Duration(nil), src.ReminderOffsets...) }},
+	{"snoozed_until", "Отложено до",
+		func(t *Task) string { return formatOptionalTime(t.SnoozedUntil) },
+		func(dst, src *Task) { dst.SnoozedUntil = src.SnoozedUntil }},
+	{"progress", "Готовность",
+		func(t *Task) string { return fmt.Sprintf("%d%%", t.Progress) },
+		func(dst, src *Task) { dst.Progress = src.Progress }},
+	{"estimate", "Оценка",
+		func(t *Task) string { return fmt.Sprintf("%d мин", t.EstimatedMinutes) },
+		func(dst, src *Task) { dst.EstimatedMinutes = src.EstimatedMinutes }},
+	{"time_spent", "Затрачено",
+		func(t *Task) string { return t.TimeSpent.Round(time.Second).String() },
+		func(dst, src *Task) { dst.TimeSpent = src.TimeSpent }},
+	{"my_day", "Мой день",
+		func(t *Task) string { return t.MyDay },
+		func(dst, src *Task) { dst.MyDay = src.MyDay }},
+}
+
+// formatOptionalTime показывает время или «нет», если оно не задано
+func formatOptionalTime(t time.Time) string {
+	if t.IsZero() {
+		return "нет"
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+// checklistText показывает чек-лист одной строкой
+func checklistText(items []ChecklistItem) string {
+	parts := make([]string, len(items))
+	for i, item := range items {
+		mark := "[ ]"
+		if item.Done {
+			mark = "[x]"
+		}
+		parts[i] = mark + " " + item.Text
+	}
+	return strings.Join(parts, "; ")
+}
+
+// MergeConflict - задача, которая по-разному изменена в обоих файлах
+type MergeConflict struct {
+	Local  *Task
+	Remote *Task
+	Fields []string // ключи различающихся полей из MergeFields
+}
+
+// MergeResult - итог слияния с другим файлом задач
+type MergeResult struct {
+	Added     int
+	Conflicts []MergeConflict
+}
+
+// MergeFrom добавляет задачи другого менеджера, сопоставляя их по UUID.
+// Новые задачи добавляются сразу, а различающиеся возвращаются как конфликты
+// и не меняются, пока не будут разрешены через ResolveConflict
+func (tm *TaskManager) MergeFrom(other *TaskManager) MergeResult {
+	var result MergeResult
+
+	for _, remote := range other.tasks {
+		local := tm.GetTaskByUUID(remote.UUID)
+		if local == nil {
+			tm.addMergedTask(remote)
+			result.Added++
+			continue
+		}
+
+		if fields := diffTasks(local, remote); len(fields) > 0 {
+			result.Conflicts = append(result.Conflicts, MergeConflict{Local: local, Remote: remote, Fields: fields})
+		}
+	}
+
+	if result.Added > 0 {
+		tm.dirty = true
+	}
+	return result
+}
+
+// MergeFile сливает с текущим списком версию файла, которую записала другая программа
+// (например, второй компьютер с той же сетевой папкой), так же, как MergeFrom.
+// После этого версия на диске считается прочитанной, и следующее сохранение
+// запишет объединенный список. Задачи, удаленные только в одной из версий, остаются
+func (tm *TaskManager) MergeFile() (MergeResult, error) {
+	data, err := tm.store.Read()
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			tm.fileHash = ""
+			tm.dirty = true
+			return MergeResult{}, nil
+		}
+		return MergeResult{}, err
+	}
+
+	tasks, err := tm.decodeFile(data)
+	if err != nil {
+		return MergeResult{}, err
+	}
+	result := tm.MergeFrom(&TaskManager{tasks: tasks})
+	tm.fileHash = hashData(data)
+	tm.dirty = true
+	return result, nil
+}
+
+// addMergedTask добавляет копию задачи из другого файла.
+// Числовой ID сохраняется, если он свободен, иначе выдается новый
+func (tm *TaskManager) addMergedTask(remote *Task) {
+	task := remote.Clone()
+	if tm.GetTask(task.ID) != nil || task.ID <= 0 {
+		task.ID = tm.nextID
+	}
+	if task.ID >= tm.nextID {
+		tm.nextID = task.ID + 1
+	}
+	tm.tasks = append(tm.tasks, task)
+	tm.emit(EventAdded, task)
+}
+
+// NewMergeConflict сравнивает локальную версию задачи с версией из другого источника
+func NewMergeConflict(local, remote *Task) MergeConflict {
+	return MergeConflict{Local: local, Remote: remote, Fields: diffTasks(local, remote)}
+}
+
+// ApplyRemote записывает версию задачи с другого устройства: заменяет задачу с тем же UUID
+// или добавляет новую. Время изменения берется из удаленной версии, числовой ID остается прежним
+func (tm *TaskManager) ApplyRemote(remote *Task) {
+	tm.dirty = true
+	local := tm.GetTaskByUUID(remote.UUID)
+	if local == nil {
+		tm.addMergedTask(remote)
+		return
+	}
+
+	id := local.ID
+	*local = *remote.Clone()
+	local.ID = id
+	tm.emit(EventUpdated, local)
+}
+
+// DeleteTaskByUUID удаляет задачу по UUID
+func (tm *TaskManager) DeleteTaskByUUID(uuid string) error {
+	task := tm.GetTaskByUUID(uuid)
+	if task == nil {
+		return fmt.Errorf("%w: uuid %s", ErrNotFound, uuid)
+	}
+	return tm.DeleteTask(task.ID)
+}
+
+// diffTasks возвращает ключи полей, в которых задачи различаются
+func diffTasks(a, b *Task) []string {
+	var fields []string
+	for _, field := range MergeFields {
+		if field.Value(a) != field.Value(b) {
+			fields = append(fields, field.Key)
+		}
+	}
+	return fields
+}
+
+// ResolveConflict переносит в локальную задачу выбранные поля из другого файла.
+// Пустой список полей оставляет локальную версию без изменений
+func (tm *TaskManager) ResolveConflict(conflict MergeConflict, fields []string) error {
+	task := tm.GetTaskByUUID(conflict.Remote.UUID)
+	if task == nil {
+		return fmt.Errorf("%w: uuid %s", ErrNotFound, conflict.Remote.UUID)
+	}
+
+	changed := false
+	for _, field := range MergeFields {
+		if slices.Contains(fields, field.Key) {
+			field.apply(task, conflict.Remote)
+			changed = true
+		}
+	}
+	if changed {
+		tm.touch(task)
+		tm.emit(EventUpdated, task)
+	}
+	return nil
+}
+
+// ResolveNewest разрешает конфликты в пользу более поздней версии задачи.
+// Возвращает количество задач, взятых из другого файла
+func (tm *TaskManager) ResolveNewest(conflicts []MergeConflict) int {
+	taken := 0
+	for _, conflict := range conflicts {
+		task := tm.GetTaskByUUID(conflict.Remote.UUID)
+		if task == nil || !conflict.Remote.UpdatedAt.After(task.UpdatedAt) {
+			continue
+		}
+		for _, field := range MergeFields {
+			field.apply(task, conflict.Remote)
+		}
+		// Задача совпадает с версией из другого файла, поэтому сохраняем и ее время
+		task.UpdatedAt = conflict.Remote.UpdatedAt
+		tm.dirty = true
+		tm.emit(EventUpdated, task)
+		taken++
+	}
+	return taken
+}
+
+// yesNo возвращает "да" или "нет" для отображения
+func yesNo(value bool) string {
+	if value {
+		return "да"
+	}
+	return "нет"
+}
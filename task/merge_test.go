@@ -0,0 +1,135 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/storage"
+)
+
+func TestMergeFrom(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	shared := mustAddTask(t, tm, "Shared", "Description", 2, time.Now())
+	same := mustAddTask(t, tm, "Same", "Description", 2, time.Now())
+	assert.NoError(t, tm.SaveToFile())
+
+	// Второй компьютер получил копию файла и изменил ее независимо
+	other := NewTaskManager(storage.NewFile(testFilename))
+	assert.NoError(t, other.LoadFromFile())
+	assert.NoError(t, other.UpdateTask(shared.ID, "Shared (laptop)", "Description", 3, shared.DueDate, true))
+	laptop := mustAddTask(t, other, "Laptop task", "Description", 1, time.Now())
+
+	// Локально добавлена задача с тем же числовым ID
+	local := mustAddTask(t, tm, "Desktop task", "Description", 1, time.Now())
+	assert.Equal(t, laptop.ID, local.ID)
+	assert.NoError(t, tm.SaveToFile())
+
+	result := tm.MergeFrom(other)
+	assert.Equal(t, 1, result.Added)
+	assert.True(t, tm.IsDirty())
+
+	// Новая задача добавлена с новым числовым ID, UUID сохранен
+	merged := tm.GetTaskByUUID(laptop.UUID)
+	if assert.NotNil(t, merged) {
+		assert.Equal(t, "Laptop task", merged.Title)
+		assert.NotEqual(t, local.ID, merged.ID)
+		assert.NotSame(t, laptop, merged)
+	}
+
+	// Одинаковые задачи не считаются конфликтом, различающиеся не меняются до разрешения
+	if assert.Equal(t, 1, len(result.Conflicts)) {
+		conflict := result.Conflicts[0]
+		assert.Equal(t, shared.UUID, conflict.Local.UUID)
+		assert.Equal(t, []string{"title", "priority", "completed"}, conflict.Fields)
+		assert.Equal(t, "Shared", tm.GetTask(shared.ID).Title)
+
+		// Берем из другого файла только статус
+		assert.NoError(t, tm.ResolveConflict(conflict, []string{"completed"}))
+		assert.Equal(t, "Shared", tm.GetTask(shared.ID).Title)
+		assert.Equal(t, 2, tm.GetTask(shared.ID).Priority)
+		assert.True(t, tm.GetTask(shared.ID).Completed)
+	}
+	assert.Equal(t, "Same", tm.GetTask(same.ID).Title)
+
+	// Повторное слияние ничего не добавляет
+	result = tm.MergeFrom(other)
+	assert.Equal(t, 0, result.Added)
+	assert.Equal(t, 4, len(tm.tasks))
+}
+
+func TestMergeFile(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	shared := mustAddTask(t, tm, "Shared", "Description", 2, time.Now())
+	assert.NoError(t, tm.SaveToFile())
+
+	// Второй компьютер сохранил файл, пока здесь были несохраненные изменения
+	other := NewTaskManager(storage.NewFile(testFilename))
+	assert.NoError(t, other.LoadFromFile())
+	laptop := mustAddTask(t, other, "Laptop task", "Description", 1, time.Now())
+	assert.NoError(t, other.SaveToFile())
+
+	desktop := mustAddTask(t, tm, "Desktop task", "Description", 1, time.Now())
+	assert.ErrorIs(t, tm.SaveToFile(), ErrFileChanged)
+
+	result, err := tm.MergeFile()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Added)
+	assert.Empty(t, result.Conflicts)
+
+	// После слияния файл можно сохранить, и в нем есть изменения обоих компьютеров
+	assert.NoError(t, tm.SaveToFile())
+	assert.NoError(t, other.LoadFromFile())
+	for _, uuid := range []string{shared.UUID, laptop.UUID, desktop.UUID} {
+		assert.NotNil(t, other.GetTaskByUUID(uuid))
+	}
+}
+
+func TestResolveNewest(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	older := mustAddTask(t, tm, "Older remotely", "Description", 1, time.Now())
+	newer := mustAddTask(t, tm, "Newer remotely", "Description", 1, time.Now())
+	assert.NoError(t, tm.SaveToFile())
+
+	other := NewTaskManager(storage.NewFile(testFilename))
+	assert.NoError(t, other.LoadFromFile())
+
+	// Одну задачу сначала изменили в другом файле, а потом здесь, другую - наоборот
+	assert.NoError(t, other.UpdateTask(older.ID, "Remote edit", "Description", 1, older.DueDate, false))
+	time.Sleep(time.Millisecond)
+	assert.NoError(t, tm.UpdateTask(older.ID, "Local edit", "Description", 1, older.DueDate, false))
+	assert.NoError(t, tm.UpdateTask(newer.ID, "Local edit", "Description", 1, newer.DueDate, false))
+	time.Sleep(time.Millisecond)
+	assert.NoError(t, other.UpdateTask(newer.ID, "Remote edit", "Description", 3, newer.DueDate, true))
+
+	result := tm.MergeFrom(other)
+	assert.Equal(t, 2, len(result.Conflicts))
+	assert.Equal(t, 1, tm.ResolveNewest(result.Conflicts))
+
+	assert.Equal(t, "Local edit", tm.GetTask(older.ID).Title)
+	assert.Equal(t, "Remote edit", tm.GetTask(newer.ID).Title)
+	assert.Equal(t, 3, tm.GetTask(newer.ID).Priority)
+	assert.True(t, tm.GetTask(newer.ID).Completed)
+	assert.Equal(t, other.GetTask(newer.ID).UpdatedAt, tm.GetTask(newer.ID).UpdatedAt)
+
+	// Взятая из файла задача совпадает с ним, конфликтом остается только локальная
+	conflicts := tm.MergeFrom(other).Conflicts
+	if assert.Equal(t, 1, len(conflicts)) {
+		assert.Equal(t, older.UUID, conflicts[0].Local.UUID)
+	}
+}
+
+func TestResolveConflictMissingTask(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	conflict := MergeConflict{Remote: &Task{UUID: "missing"}}
+	assert.ErrorIs(t, tm.ResolveConflict(conflict, []string{"title"}), ErrNotFound)
+}
@@ -0,0 +1,76 @@
+package task
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// «Мой день» - список дел на сегодня, который пользователь собирает сам каждое утро.
+// Задача в нем, пока день, в который ее добавили, совпадает с сегодняшним, поэтому
+// список пустеет сам с наступлением нового дня, а срок задачи не меняется
+
+// InMyDay сообщает, что задача добавлена в «Мой день» в день now
+func (t *Task) InMyDay(now time.Time) bool {
+	return t.MyDay != "" && t.MyDay == now.Format(DueDateLayout)
+}
+
+// SetMyDay добавляет задачу в «Мой день» дня now или убирает ее оттуда
+func (tm *TaskManager) SetMyDay(id int, add bool, now time.Time) error {
+	task := tm.GetTask(id)
+	if task == nil {
+		return notFoundError(id)
+	}
+	day := ""
+	if add {
+		day = now.Format(DueDateLayout)
+	}
+	if task.MyDay == day {
+		return nil
+	}
+	task.MyDay = day
+	tm.touch(task)
+	tm.emit(EventUpdated, task)
+	return nil
+}
+
+// MyDay возвращает задачи «Моего дня» на день now: сначала невыполненные
+func (tm *TaskManager) MyDay(now time.Time) []*Task {
+	var tasks []*Task
+	for _, task := range tm.tasks {
+		if !task.Archived && task.InMyDay(now) {
+			tasks = append(tasks, task)
+		}
+	}
+	slices.SortStableFunc(tasks, func(a, b *Task) int {
+		return boolOrder(a.Completed) - boolOrder(b.Completed)
+	})
+	return tasks
+}
+
+// MyDaySuggestions возвращает задачи, которые стоит добавить в «Мой день»: невыполненные
+// просроченные и на сегодня, которых еще нет в списке. Сначала самые старые сроки,
+// в пределах дня - более высокий приоритет
+func (tm *TaskManager) MyDaySuggestions(now time.Time) []*Task {
+	today := now.Format(DueDateLayout)
+	var tasks []*Task
+	for _, task := range tm.tasks {
+		if task.Completed || task.Archived || task.InMyDay(now) || task.DueDate.IsZero() {
+			continue
+		}
+		if task.DueDay() <= today {
+			tasks = append(tasks, task)
+		}
+	}
+	slices.SortStableFunc(tasks, func(a, b *Task) int {
+		return cmp.Or(cmp.Compare(a.DueDay(), b.DueDay()), cmp.Compare(b.Priority, a.Priority))
+	})
+	return tasks
+}
+
+func boolOrder(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,41 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMyDay(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	today := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	report := mustAddTask(t, tm, "Отчет", "", 1, today)
+	overdue := mustAddTask(t, tm, "Счет", "", 2, today.AddDate(0, 0, -3))
+	urgent := mustAddTask(t, tm, "Позвонить", "", 3, today)
+	later := mustAddTask(t, tm, "Отпуск", "", 2, today.AddDate(0, 0, 5))
+
+	assert.Empty(t, tm.MyDay(now))
+	assert.Equal(t, []*Task{overdue, urgent, report}, tm.MyDaySuggestions(now))
+
+	// В «Мой день» можно добавить и задачу с далеким сроком; срок при этом не меняется
+	require.NoError(t, tm.SetMyDay(later.ID, true, now))
+	require.NoError(t, tm.SetMyDay(urgent.ID, true, now))
+	assert.Equal(t, []*Task{urgent, later}, tm.MyDay(now))
+	assert.Equal(t, []*Task{overdue, report}, tm.MyDaySuggestions(now))
+	assert.Equal(t, today.AddDate(0, 0, 5), later.DueDate)
+
+	require.NoError(t, tm.ToggleTaskCompletion(urgent.ID))
+	assert.Equal(t, []*Task{later, urgent}, tm.MyDay(now), "completed tasks go last")
+
+	require.NoError(t, tm.SetMyDay(later.ID, false, now))
+	assert.Equal(t, []*Task{urgent}, tm.MyDay(now))
+
+	// На следующий день список пуст
+	assert.Empty(t, tm.MyDay(now.AddDate(0, 0, 1)))
+	assert.ErrorIs(t, tm.SetMyDay(999, true, now), ErrNotFound)
+}
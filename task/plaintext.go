@@ -0,0 +1,356 @@
+package task
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Обмен задачами с программами для простого текста: заголовками Emacs org-mode и списками todo.txt.
+// Поля, которых нет в этих форматах, при выгрузке пропускаются. Задачам из файла без срока
+// назначается срок today, UUID сохраняется в файле, чтобы повторный импорт находил те же задачи
+
+// plaintextPriority - буквы приоритетов org-mode и todo.txt: A - самый высокий
+var plaintextPriority = map[int]string{1: "C", 2: "B", 3: "A"}
+
+// priorityFromLetter переводит букву приоритета в приоритет задачи. Буквы ниже C в todo.txt
+// считаются низким приоритетом, задачи без буквы - средним
+func priorityFromLetter(letter string) int {
+	switch letter {
+	case "A":
+		return 3
+	case "B", "":
+		return 2
+	}
+	return 1
+}
+
+// plaintextTag заменяет пробелы в метке: в обоих форматах метка - одно слово
+func plaintextTag(tag string) string {
+	return strings.Join(strings.Fields(tag), "_")
+}
+
+// newImportedTask создает задачу из простого текста с полями по умолчанию
+func newImportedTask(now time.Time) *Task {
+	return &Task{UUID: newUUID(), Priority: 2, CreatedAt: now, UpdatedAt: now}
+}
+
+// finishImported назначает срок задачам без срока и проверяет задачу.
+// seen - UUID уже прочитанных задач файла
+func finishImported(task *Task, today time.Time, line int, seen map[string]bool) error {
+	if task.DueDate.IsZero() {
+		task.DueDate = today
+	}
+	if task.Completed && task.CompletedAt.IsZero() {
+		task.CompletedAt = task.UpdatedAt
+	}
+	if err := validateImported(task); err != nil {
+		return fmt.Errorf("line %d: %w", line, err)
+	}
+	if seen[task.UUID] {
+		return fmt.Errorf("line %d: %w", line, &ValidationError{Field: "uuid", Message: "duplicated in the file"})
+	}
+	seen[task.UUID] = true
+	return nil
+}
+
+// orgDate - дата org-mode: <2026-10-16 Fri> для сроков и [2026-10-16 Fri 14:05] для отметок
+func orgDate(t time.Time, open, close string, withTime bool) string {
+	layout := "2006-01-02 Mon"
+	if withTime {
+		layout += " 15:04"
+	}
+	return open + t.Format(layout) + close
+}
+
+// WriteOrg записывает задачи заголовками org-mode: TODO или DONE, приоритет [#A],
+// метки и контекст - тегами, срок - DEADLINE, дата начала - SCHEDULED
+func WriteOrg(w io.Writer, tasks []*Task) error {
+	out := bufio.NewWriter(w)
+	for _, t := range tasks {
+		keyword := "TODO"
+		if t.Completed {
+			keyword = "DONE"
+		}
+		fmt.Fprintf(out, "* %s [#%s] %s", keyword, plaintextPriority[t.Priority], t.Title)
+		var tags []string
+		for _, tag := range t.Tags {
+			tags = append(tags, plaintextTag(tag))
+		}
+		if t.Context != "" {
+			tags = append(tags, plaintextTag(t.Context))
+		}
+		if len(tags) > 0 {
+			fmt.Fprintf(out, " :%s:", strings.Join(tags, ":"))
+		}
+		out.WriteString("\n")
+
+		var planning []string
+		if t.Completed {
+			planning = append(planning, "CLOSED: "+orgDate(t.DoneAt().Local(), "[", "]", true))
+		}
+		if !t.StartDate.IsZero() {
+			planning = append(planning, "SCHEDULED: "+orgDate(t.StartDate, "<", ">", false))
+		}
+		if !t.DueDate.IsZero() {
+			planning = append(planning, "DEADLINE: "+orgDate(t.DueDate, "<", ">", false))
+		}
+		if len(planning) > 0 {
+			fmt.Fprintf(out, "  %s\n", strings.Join(planning, " "))
+		}
+
+		out.WriteString("  :PROPERTIES:\n")
+		fmt.Fprintf(out, "  :ID: %s\n", t.UUID)
+		if t.Assignee != "" {
+			fmt.Fprintf(out, "  :ASSIGNEE: %s\n", t.Assignee)
+		}
+		out.WriteString("  :END:\n")
+		if t.Description != "" {
+			for _, line := range strings.Split(t.Description, "\n") {
+				fmt.Fprintf(out, "  %s\n", line)
+			}
+		}
+	}
+	return out.Flush()
+}
+
+var (
+	orgHeadline = regexp.MustCompile(`^\*+\s+(?:(TODO|DONE)(?:\s+|$))?(?:\[#([A-Z])\]\s*)?(.*?)(?:\s+(:(?:[^\s:]+:)+))?\s*$`)
+	orgPlanning = regexp.MustCompile(`(SCHEDULED|DEADLINE|CLOSED):\s*[<\[](\d{4}-\d{2}-\d{2})[^>\]]*?(?:\s(\d{1,2}:\d{2}))?[>\]]`)
+	orgProperty = regexp.MustCompile(`^:([^:\s]+):\s*(.*)$`)
+)
+
+// ReadOrg читает задачи из заголовков org-mode с ключевыми словами TODO и DONE.
+// Заголовки без ключевого слова считаются разделами и пропускаются вместе с текстом
+func ReadOrg(r io.Reader, today time.Time) ([]*Task, error) {
+	now := time.Now()
+	var (
+		tasks        []*Task
+		current      *Task
+		currentLine  int
+		description  []string
+		inProperties bool
+		seen         = make(map[string]bool)
+	)
+	finish := func() error {
+		if current == nil {
+			return nil
+		}
+		current.Description = strings.TrimSpace(strings.Join(description, "\n"))
+		if err := finishImported(current, today, currentLine, seen); err != nil {
+			return err
+		}
+		tasks = append(tasks, current)
+		current, description = nil, nil
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "*") {
+			if match := orgHeadline.FindStringSubmatch(line); match != nil {
+				if err := finish(); err != nil {
+					return nil, err
+				}
+				inProperties = false
+				if match[1] == "" {
+					continue
+				}
+				current, currentLine = newImportedTask(now), lineNo
+				current.Completed = match[1] == "DONE"
+				current.Priority = priorityFromLetter(match[2])
+				current.Title = match[3]
+				for _, tag := range strings.Split(strings.Trim(match[4], ":"), ":") {
+					switch {
+					case tag == "":
+					case strings.HasPrefix(tag, "@") && current.Context == "":
+						current.Context = tag
+					default:
+						current.Tags = append(current.Tags, tag)
+					}
+				}
+				continue
+			}
+		}
+		if current == nil {
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == ":PROPERTIES:":
+			inProperties = true
+		case inProperties && trimmed == ":END:":
+			inProperties = false
+		case inProperties:
+			if match := orgProperty.FindStringSubmatch(trimmed); match != nil {
+				switch strings.ToUpper(match[1]) {
+				case "ID":
+					if match[2] != "" {
+						current.UUID = match[2]
+					}
+				case "ASSIGNEE":
+					current.Assignee = match[2]
+				}
+			}
+		case len(description) == 0 && orgPlanning.MatchString(trimmed):
+			for _, match := range orgPlanning.FindAllStringSubmatch(trimmed, -1) {
+				layout, value := DueDateLayout, match[2]
+				if match[3] != "" {
+					layout, value = DueDateLayout+" 15:04", value+" "+match[3]
+				}
+				date, err := time.ParseInLocation(layout, value, today.Location())
+				if err != nil {
+					return nil, fmt.Errorf("line %d: invalid %s date: %w", lineNo, strings.ToLower(match[1]), err)
+				}
+				switch match[1] {
+				case "SCHEDULED":
+					current.StartDate = date
+				case "DEADLINE":
+					current.DueDate = date
+				case "CLOSED":
+					current.CompletedAt = date
+				}
+			}
+		default:
+			description = append(description, trimmed)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := finish(); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// WriteTodoTxt записывает задачи строками todo.txt: приоритет (A), метки - проектами +метка,
+// контекст - @контекстом, срок, дата начала и UUID - парами due:, t: и uuid:.
+// Описание в todo.txt не помещается и не выгружается
+func WriteTodoTxt(w io.Writer, tasks []*Task) error {
+	out := bufio.NewWriter(w)
+	for _, t := range tasks {
+		var fields []string
+		if t.Completed {
+			fields = append(fields, "x", t.DoneAt().Local().Format(DueDateLayout))
+		} else {
+			fields = append(fields, "("+plaintextPriority[t.Priority]+")")
+		}
+		fields = append(fields, t.CreatedAt.Local().Format(DueDateLayout), t.Title)
+		for _, tag := range t.Tags {
+			fields = append(fields, "+"+plaintextTag(tag))
+		}
+		if t.Context != "" {
+			fields = append(fields, plaintextTag(t.Context))
+		}
+		if !t.DueDate.IsZero() {
+			fields = append(fields, "due:"+t.DueDate.Format(DueDateLayout))
+		}
+		if !t.StartDate.IsZero() {
+			fields = append(fields, "t:"+t.StartDate.Format(DueDateLayout))
+		}
+		if t.Completed {
+			// У выполненных задач todo.txt хранит приоритет парой pri:
+			fields = append(fields, "pri:"+plaintextPriority[t.Priority])
+		}
+		fields = append(fields, "uuid:"+t.UUID)
+		fmt.Fprintln(out, strings.Join(fields, " "))
+	}
+	return out.Flush()
+}
+
+var (
+	todoTxtPriority = regexp.MustCompile(`^\(([A-Z])\)$`)
+	todoTxtDate     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+)
+
+// ReadTodoTxt читает задачи из списка todo.txt. Первый @контекст становится контекстом задачи,
+// остальные и +проекты - метками
+func ReadTodoTxt(r io.Reader, today time.Time) ([]*Task, error) {
+	now := time.Now()
+	parseDate := func(value string) (time.Time, error) {
+		return time.ParseInLocation(DueDateLayout, value, today.Location())
+	}
+
+	var tasks []*Task
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		task := newImportedTask(now)
+
+		if fields[0] == "x" {
+			task.Completed = true
+			fields = fields[1:]
+			if len(fields) > 0 && todoTxtDate.MatchString(fields[0]) {
+				done, err := parseDate(fields[0])
+				if err != nil {
+					return nil, fmt.Errorf("line %d: invalid completion date: %w", lineNo, err)
+				}
+				task.CompletedAt = done
+				fields = fields[1:]
+			}
+		} else if len(fields) > 0 {
+			if match := todoTxtPriority.FindStringSubmatch(fields[0]); match != nil {
+				task.Priority = priorityFromLetter(match[1])
+				fields = fields[1:]
+			}
+		}
+		if len(fields) > 0 && todoTxtDate.MatchString(fields[0]) {
+			created, err := parseDate(fields[0])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid creation date: %w", lineNo, err)
+			}
+			task.CreatedAt = created
+			fields = fields[1:]
+		}
+
+		var title []string
+		for _, field := range fields {
+			key, value, pair := strings.Cut(field, ":")
+			switch {
+			case len(field) > 1 && field[0] == '+':
+				task.Tags = append(task.Tags, field[1:])
+			case len(field) > 1 && field[0] == '@':
+				if task.Context == "" {
+					task.Context = field
+				} else {
+					task.Tags = append(task.Tags, field)
+				}
+			case pair && value != "" && (key == "due" || key == "t"):
+				date, err := parseDate(value)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: invalid %s date: %w", lineNo, key, err)
+				}
+				if key == "due" {
+					task.DueDate = date
+				} else {
+					task.StartDate = date
+				}
+			case pair && key == "pri" && len(value) == 1:
+				task.Priority = priorityFromLetter(value)
+			case pair && key == "uuid" && value != "":
+				task.UUID = value
+			default:
+				title = append(title, field)
+			}
+		}
+		task.Title = strings.Join(title, " ")
+		if err := finishImported(task, today, lineNo, seen); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
@@ -0,0 +1,141 @@
+package task
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrgRoundTrip(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.Local) }
+	tasks := []*Task{
+		{UUID: "a1", Title: "Сдать отчет", Description: "Первая строка\nВторая строка", Priority: 3,
+			DueDate: day(20), StartDate: day(16), Tags: []string{"работа", "квартальный отчет"}, Context: "@офис", Assignee: "Анна"},
+		{UUID: "b2", Title: "Купить молоко", Priority: 1, DueDate: day(17), Completed: true,
+			CompletedAt: time.Date(2026, 10, 16, 18, 30, 0, 0, time.Local)},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, WriteOrg(&out, tasks))
+	assert.Contains(t, out.String(), "* TODO [#A] Сдать отчет :работа:квартальный_отчет:@офис:\n"+
+		"  SCHEDULED: <2026-10-16 Fri> DEADLINE: <2026-10-20 Tue>\n")
+	assert.Contains(t, out.String(), "* DONE [#C] Купить молоко\n  CLOSED: [2026-10-16 Fri 18:30] DEADLINE:")
+
+	read, err := ReadOrg(&out, day(1))
+	require.NoError(t, err)
+	require.Len(t, read, 2)
+	assert.Equal(t, "a1", read[0].UUID)
+	assert.Equal(t, "Сдать отчет", read[0].Title)
+	assert.Equal(t, "Первая строка\nВторая строка", read[0].Description)
+	assert.Equal(t, 3, read[0].Priority)
+	assert.Equal(t, day(20), read[0].DueDate)
+	assert.Equal(t, day(16), read[0].StartDate)
+	assert.Equal(t, []string{"работа", "квартальный_отчет"}, read[0].Tags)
+	assert.Equal(t, "@офис", read[0].Context)
+	assert.Equal(t, "Анна", read[0].Assignee)
+	assert.True(t, read[1].Completed)
+	assert.Equal(t, tasks[1].CompletedAt, read[1].CompletedAt)
+}
+
+func TestReadOrgHandWritten(t *testing.T) {
+	today := time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)
+	text := "#+TITLE: Дела\n" +
+		"* Проекты\n" +
+		"Текст раздела\n" +
+		"** TODO Позвонить маме\n" +
+		"** DONE [#B] Полить цветы :дом:\n" +
+		"*** Заметки без ключевого слова\n"
+	tasks, err := ReadOrg(strings.NewReader(text), today)
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "Позвонить маме", tasks[0].Title)
+	assert.Equal(t, 2, tasks[0].Priority)
+	assert.Equal(t, today, tasks[0].DueDate, "задачи без срока получают срок today")
+	assert.Empty(t, tasks[0].Description, "текст раздела не относится к задаче")
+	assert.NotEmpty(t, tasks[0].UUID)
+	assert.True(t, tasks[1].Completed)
+	assert.Equal(t, []string{"дом"}, tasks[1].Tags)
+
+	_, err = ReadOrg(strings.NewReader("* TODO\n"), today)
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.ErrorContains(t, err, "line 1")
+}
+
+func TestTodoTxtRoundTrip(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.Local) }
+	tasks := []*Task{
+		{UUID: "a1", Title: "Сдать отчет", Priority: 3, CreatedAt: day(1), DueDate: day(20), StartDate: day(16),
+			Tags: []string{"работа"}, Context: "@офис"},
+		{UUID: "b2", Title: "Купить молоко", Priority: 1, CreatedAt: day(2), DueDate: day(17), Completed: true, CompletedAt: day(16)},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, WriteTodoTxt(&out, tasks))
+	assert.Equal(t, "(A) 2026-10-01 Сдать отчет +работа @офис due:2026-10-20 t:2026-10-16 uuid:a1\n"+
+		"x 2026-10-16 2026-10-02 Купить молоко due:2026-10-17 pri:C uuid:b2\n", out.String())
+
+	read, err := ReadTodoTxt(&out, day(1))
+	require.NoError(t, err)
+	require.Len(t, read, 2)
+	for i := range tasks {
+		assert.Equal(t, tasks[i].UUID, read[i].UUID)
+		assert.Equal(t, tasks[i].Title, read[i].Title)
+		assert.Equal(t, tasks[i].Priority, read[i].Priority)
+		assert.Equal(t, tasks[i].CreatedAt, read[i].CreatedAt)
+		assert.Equal(t, tasks[i].DueDate, read[i].DueDate)
+		assert.Equal(t, tasks[i].StartDate, read[i].StartDate)
+		assert.Equal(t, tasks[i].Completed, read[i].Completed)
+		assert.Equal(t, tasks[i].Context, read[i].Context)
+	}
+	assert.Equal(t, []string{"работа"}, read[0].Tags)
+	assert.Equal(t, day(16), read[1].CompletedAt)
+
+	// У задачи без срока нет due: и DEADLINE
+	someday := []*Task{{UUID: "c3", Title: "Когда-нибудь", Priority: 2, CreatedAt: day(3)}}
+	out.Reset()
+	require.NoError(t, WriteTodoTxt(&out, someday))
+	assert.Equal(t, "(B) 2026-10-03 Когда-нибудь uuid:c3\n", out.String())
+	out.Reset()
+	require.NoError(t, WriteOrg(&out, someday))
+	assert.NotContains(t, out.String(), "DEADLINE")
+}
+
+func TestReadTodoTxtHandWritten(t *testing.T) {
+	today := time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)
+	text := "(D) Починить кран @дом @выходные +ремонт\n\nx Вынести мусор\n"
+	tasks, err := ReadTodoTxt(strings.NewReader(text), today)
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "Починить кран", tasks[0].Title)
+	assert.Equal(t, 1, tasks[0].Priority)
+	assert.Equal(t, "@дом", tasks[0].Context)
+	assert.Equal(t, []string{"@выходные", "ремонт"}, tasks[0].Tags)
+	assert.Equal(t, today, tasks[0].DueDate)
+	assert.True(t, tasks[1].Completed)
+	assert.Equal(t, 2, tasks[1].Priority)
+
+	_, err = ReadTodoTxt(strings.NewReader("Задача due:завтра\n"), today)
+	assert.ErrorContains(t, err, "line 1: invalid due date")
+
+	_, err = ReadTodoTxt(strings.NewReader("Первая uuid:x\nВторая uuid:x\n"), today)
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestImportPlaintextDuplicates(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	existing := mustAddTask(t, tm, "Сдать отчет", "", 2, time.Now())
+	tasks, err := ReadTodoTxt(strings.NewReader("(A) Сдать отчет uuid:"+existing.UUID+"\nНовая задача\n"), time.Now())
+	require.NoError(t, err)
+
+	result := tm.Import(&ExportEnvelope{Tasks: tasks}, ImportReplace)
+	assert.Equal(t, ImportResult{Added: 1, Replaced: 1}, result)
+	assert.Equal(t, 3, tm.GetTaskByUUID(existing.UUID).Priority)
+	assert.Len(t, tm.Tasks(), 2)
+}
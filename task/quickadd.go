@@ -0,0 +1,120 @@
+package task
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QuickAdd - задача, разобранная из строки быстрого ввода
+type QuickAdd struct {
+	Title       string
+	Description string
+	Priority    int
+	DueDate     time.Time
+	Tags        []string
+	Assignee    string
+	Completed   bool
+}
+
+// quickDays - слова, задающие срок относительно сегодняшнего дня
+var quickDays = map[string]int{"сегодня": 0, "завтра": 1, "послезавтра": 2, "today": 0, "tomorrow": 1}
+
+// quickDate - срок числом: 2026-10-20, 20.10.2026 или 20.10
+var quickDate = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}|\d{1,2}\.\d{1,2}(\.\d{4})?)$`)
+
+// ParseQuickAdd разбирает строку быстрого ввода: «Позвонить маме завтра !3 #дом @Петя».
+// #метка добавляет метку, @имя - исполнителя, !1..!3 - приоритет, срок - слово «сегодня»,
+// «завтра», «послезавтра» или дата. Остальные слова составляют название.
+// Без срока задача получает срок на завтра, без приоритета - средний
+func ParseQuickAdd(line string, now time.Time) QuickAdd {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	q := QuickAdd{Priority: 2, DueDate: today.AddDate(0, 0, 1)}
+
+	var title []string
+	for _, word := range strings.Fields(line) {
+		days, isDay := quickDays[strings.ToLower(word)]
+		switch {
+		case len(word) > 1 && word[0] == '#':
+			q.Tags = append(q.Tags, word[1:])
+		case len(word) > 1 && word[0] == '@':
+			q.Assignee = word[1:]
+		case len(word) == 2 && word[0] == '!' && word[1] >= '1' && word[1] <= '3':
+			q.Priority = int(word[1] - '0')
+		case isDay:
+			q.DueDate = today.AddDate(0, 0, days)
+		case quickDate.MatchString(word):
+			if due, ok := parseQuickDate(word, today); ok {
+				q.DueDate = due
+			} else {
+				title = append(title, word)
+			}
+		default:
+			title = append(title, word)
+		}
+	}
+	q.Title = strings.Join(title, " ")
+	return q
+}
+
+// parseQuickDate разбирает дату быстрого ввода. Дата без года - ближайшая, не раньше сегодняшней.
+// Срок задается в часовом поясе today
+func parseQuickDate(word string, today time.Time) (time.Time, bool) {
+	if due, err := time.ParseInLocation(DueDateLayout, word, today.Location()); err == nil {
+		return due, true
+	}
+	if due, err := time.ParseInLocation("2.1.2006", word, today.Location()); err == nil {
+		return due, true
+	}
+	parts := strings.Split(word, ".")
+	day, _ := strconv.Atoi(parts[0])
+	month, _ := strconv.Atoi(parts[1])
+	if month < 1 || month > 12 || day < 1 || day > 31 {
+		return time.Time{}, false
+	}
+	due := time.Date(today.Year(), time.Month(month), day, 0, 0, 0, 0, today.Location())
+	if due.Day() != day {
+		return time.Time{}, false // 31.02 и подобные
+	}
+	if due.Before(today) {
+		due = due.AddDate(1, 0, 0)
+	}
+	return due, true
+}
+
+// markdownItem - пункт списка Markdown: «- [ ] задача», «* задача», «1. задача»
+var markdownItem = regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s+(?:\[([ xX])\]\s+)?(.*)$`)
+
+// ParseTaskLines разбирает вставленный текст или файл .txt/.md: каждая непустая строка
+// или пункт списка Markdown становится задачей. Заголовки Markdown пропускаются,
+// а строки с отступом под пунктом списка становятся описанием задачи
+func ParseTaskLines(text string, now time.Time) []QuickAdd {
+	var tasks []QuickAdd
+	for _, raw := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "# ") || strings.HasPrefix(line, "##") {
+			continue
+		}
+		indented := raw != strings.TrimLeft(raw, " \t")
+		match := markdownItem.FindStringSubmatch(line)
+		if indented && match == nil && len(tasks) > 0 {
+			last := &tasks[len(tasks)-1]
+			last.Description = strings.TrimPrefix(last.Description+"\n"+line, "\n")
+			continue
+		}
+
+		completed := false
+		if match != nil {
+			completed = strings.EqualFold(match[1], "x")
+			line = match[2]
+		}
+		q := ParseQuickAdd(line, now)
+		if q.Title == "" {
+			continue
+		}
+		q.Completed = completed
+		tasks = append(tasks, q)
+	}
+	return tasks
+}
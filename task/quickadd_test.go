@@ -0,0 +1,68 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuickAdd(t *testing.T) {
+	now := time.Date(2026, 10, 16, 15, 30, 0, 0, time.Local)
+	// Срок задается в часовом поясе now
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 0, 0, 0, 0, time.Local) }
+
+	q := ParseQuickAdd("Позвонить маме завтра !3 #дом #семья @Петя", now)
+	assert.Equal(t, QuickAdd{
+		Title: "Позвонить маме", Priority: 3, DueDate: day(10, 17),
+		Tags: []string{"дом", "семья"}, Assignee: "Петя",
+	}, q)
+
+	// Без срока и приоритета - завтра и средний
+	q = ParseQuickAdd("Купить молоко", now)
+	assert.Equal(t, "Купить молоко", q.Title)
+	assert.Equal(t, 2, q.Priority)
+	assert.Equal(t, day(10, 17), q.DueDate)
+
+	assert.Equal(t, day(10, 16), ParseQuickAdd("Отчет сегодня", now).DueDate)
+	assert.Equal(t, day(10, 18), ParseQuickAdd("Отчет послезавтра", now).DueDate)
+	assert.Equal(t, day(12, 1), ParseQuickAdd("Отчет 2026-12-01", now).DueDate)
+	assert.Equal(t, day(11, 5), ParseQuickAdd("Отчет 5.11.2026", now).DueDate)
+	assert.Equal(t, day(11, 20), ParseQuickAdd("Отчет 20.11", now).DueDate)
+
+	// Прошедшая дата без года - в следующем году
+	assert.Equal(t, time.Date(2027, 1, 10, 0, 0, 0, 0, time.Local), ParseQuickAdd("Отчет 10.01", now).DueDate)
+
+	// Невозможная дата и одиночные символы остаются в названии
+	q = ParseQuickAdd("Версия 31.02 # @ !5", now)
+	assert.Equal(t, "Версия 31.02 # @ !5", q.Title)
+	assert.Empty(t, q.Tags)
+}
+
+func TestParseTaskLines(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	text := "# Задачи\r\n" +
+		"\r\n" +
+		"- [ ] Купить молоко #дом\r\n" +
+		"  2 литра\r\n" +
+		"  обезжиренное\r\n" +
+		"- [x] Сдать отчет !3\r\n" +
+		"* Позвонить завтра\r\n" +
+		"1. Первый шаг\r\n" +
+		"Просто строка\r\n" +
+		"- [ ] #метка\r\n"
+
+	tasks := ParseTaskLines(text, now)
+	var titles []string
+	for _, q := range tasks {
+		titles = append(titles, q.Title)
+	}
+	assert.Equal(t, []string{"Купить молоко", "Сдать отчет", "Позвонить", "Первый шаг", "Просто строка"}, titles)
+	assert.Equal(t, "2 литра\nобезжиренное", tasks[0].Description)
+	assert.Equal(t, []string{"дом"}, tasks[0].Tags)
+	assert.False(t, tasks[0].Completed)
+	assert.True(t, tasks[1].Completed)
+	assert.Equal(t, 3, tasks[1].Priority)
+
+	assert.Empty(t, ParseTaskLines("\n\n## Заголовок\n", now))
+}
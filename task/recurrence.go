@@ -0,0 +1,165 @@
+package task
+
+import (
+	"slices"
+	"time"
+)
+
+// Recurrence задает, как часто повторяется задача-привычка
+type Recurrence string
+
+const (
+	RecurNone   Recurrence = ""
+	RecurDaily  Recurrence = "daily"
+	RecurWeekly Recurrence = "weekly"
+)
+
+// Recurrences - все виды повторения в порядке показа
+var Recurrences = []Recurrence{RecurNone, RecurDaily, RecurWeekly}
+
+// Text возвращает название повторения для интерфейса
+func (r Recurrence) Text() string {
+	switch r {
+	case RecurDaily:
+		return "каждый день"
+	case RecurWeekly:
+		return "каждую неделю"
+	}
+	return "не повторяется"
+}
+
+// step возвращает длину периода повторения в днях
+func (r Recurrence) step() int {
+	if r == RecurWeekly {
+		return 7
+	}
+	return 1
+}
+
+// periodStart возвращает начало периода, в который попадает момент t:
+// день или неделю с понедельника
+func (r Recurrence) periodStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if r == RecurWeekly {
+		day = day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	}
+	return day
+}
+
+// SetTaskRecurrence делает задачу повторяющейся или снимает повторение
+func (tm *TaskManager) SetTaskRecurrence(id int, recurrence Recurrence) error {
+	task := tm.GetTask(id)
+	if task == nil {
+		return notFoundError(id)
+	}
+	if !slices.Contains(Recurrences, recurrence) {
+		return &ValidationError{Field: "recurrence", Message: "must be daily, weekly or empty"}
+	}
+
+	task.Recurrence = recurrence
+	tm.touch(task)
+	tm.emit(EventUpdated, task)
+	return nil
+}
+
+// toggleOccurrence отмечает выполнение повторяющейся задачи в текущем периоде и переносит
+// срок на следующее повторение. Повторный вызов в том же периоде снимает отметку и
+// возвращает прежний срок. now задается в поясе сроков
+func (tm *TaskManager) toggleOccurrence(task *Task, now time.Time) {
+	step := task.Recurrence.step()
+	current := task.Recurrence.periodStart(now)
+	if task.DoneInPeriod(now) {
+		task.Completions = slices.DeleteFunc(task.Completions, func(c time.Time) bool {
+			return task.Recurrence.periodStart(c).Equal(current)
+		})
+		if task.PreviousDue != nil {
+			task.DueDate = *task.PreviousDue
+			task.PreviousDue = nil
+		} else {
+			// В файлах без прежнего срока срок возвращается на одно повторение,
+			// а перенесенный с нерабочего дня - на рабочий день до него
+			task.DueDate = tm.skipDaysOff(task.DueDate.AddDate(0, 0, -step), -1)
+		}
+	} else {
+		task.Completions = append(task.Completions, now)
+		previous := task.DueDate
+		task.PreviousDue = &previous
+		// Задача без срока получает срок от текущего периода
+		if task.DueDate.IsZero() {
+			task.DueDate = time.Date(current.Year(), current.Month(), current.Day(), 0, 0, 0, 0, now.Location())
+		}
+		today := now.Format(DueDateLayout)
+		for task.DueDay() <= today {
+			task.DueDate = task.DueDate.AddDate(0, 0, step)
+		}
+		task.DueDate = tm.skipDaysOff(task.DueDate, 1)
+	}
+	tm.touch(task)
+	tm.emit(EventOccurrence, task)
+}
+
+// periods возвращает начала периодов, в которые задача выполнялась, по возрастанию.
+// День отметки считается по часовому поясу, в котором ее сделали
+func (t *Task) periods() []time.Time {
+	var periods []time.Time
+	for _, c := range t.Completions {
+		start := t.Recurrence.periodStart(c)
+		if !slices.ContainsFunc(periods, start.Equal) {
+			periods = append(periods, start)
+		}
+	}
+	slices.SortFunc(periods, func(a, b time.Time) int { return a.Compare(b) })
+	return periods
+}
+
+// DoneInPeriod сообщает, выполнена ли повторяющаяся задача в текущем периоде
+func (t *Task) DoneInPeriod(now time.Time) bool {
+	return slices.ContainsFunc(t.periods(), t.Recurrence.periodStart(now).Equal)
+}
+
+// Streak возвращает текущую серию: сколько периодов подряд задача выполнялась.
+// Пока текущий период не закончился, серия продолжается с предыдущего
+func (t *Task) Streak(now time.Time) int {
+	if t.Recurrence == RecurNone {
+		return 0
+	}
+	periods := t.periods()
+	step := t.Recurrence.step()
+	day := t.Recurrence.periodStart(now)
+	if !slices.ContainsFunc(periods, day.Equal) {
+		day = day.AddDate(0, 0, -step)
+	}
+	streak := 0
+	for slices.ContainsFunc(periods, day.Equal) {
+		streak++
+		day = day.AddDate(0, 0, -step)
+	}
+	return streak
+}
+
+// BestStreak возвращает самую длинную серию выполнений за всю историю
+func (t *Task) BestStreak() int {
+	if t.Recurrence == RecurNone {
+		return 0
+	}
+	best, run := 0, 0
+	var prev time.Time
+	for _, start := range t.periods() {
+		if run > 0 && prev.AddDate(0, 0, t.Recurrence.step()).Equal(start) {
+			run++
+		} else {
+			run = 1
+		}
+		best = max(best, run)
+		prev = start
+	}
+	return best
+}
+
+// CompletedOn сообщает, отмечалось ли выполнение задачи в день day
+func (t *Task) CompletedOn(day time.Time) bool {
+	date := day.Format("2006-01-02")
+	return slices.ContainsFunc(t.Completions, func(c time.Time) bool {
+		return c.Format("2006-01-02") == date
+	})
+}
@@ -0,0 +1,93 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToggleRecurringTask(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	today := time.Now().Format("2006-01-02")
+	due, _ := time.Parse("2006-01-02", today)
+	added := mustAddTask(t, tm, "Зарядка", "", 2, due.AddDate(0, 0, -3))
+	assert.NoError(t, tm.SetTaskRecurrence(added.ID, RecurDaily))
+	assert.ErrorIs(t, tm.SetTaskRecurrence(added.ID, "monthly"), ErrValidation)
+
+	// Задача не закрывается, а переносится на следующий день после сегодняшнего
+	assert.NoError(t, tm.ToggleTaskCompletion(added.ID))
+	got := tm.GetTask(added.ID)
+	assert.False(t, got.Completed)
+	assert.Equal(t, due.AddDate(0, 0, 1), got.DueDate)
+	assert.Len(t, got.Completions, 1)
+	assert.True(t, got.DoneInPeriod(time.Now()))
+
+	// Повторное нажатие снимает сегодняшнюю отметку и возвращает срок, пропущенный
+	// на несколько повторений назад
+	assert.NoError(t, tm.ToggleTaskCompletion(added.ID))
+	assert.Empty(t, got.Completions)
+	assert.Equal(t, due.AddDate(0, 0, -3), got.DueDate)
+	assert.Nil(t, got.PreviousDue)
+
+	// Задача без срока получает срок на следующий период, а после снятия отметки
+	// снова остается без срока
+	someday := mustAddTask(t, tm, "Растяжка", "", 2, time.Time{})
+	assert.NoError(t, tm.SetTaskRecurrence(someday.ID, RecurDaily))
+	assert.NoError(t, tm.ToggleTaskCompletion(someday.ID))
+	assert.Equal(t, time.Now().AddDate(0, 0, 1).Format("2006-01-02"), tm.GetTask(someday.ID).DueDay())
+	assert.NoError(t, tm.ToggleTaskCompletion(someday.ID))
+	assert.True(t, tm.GetTask(someday.ID).DueDate.IsZero())
+}
+
+func TestToggleOccurrenceEvent(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	habit := mustAddTask(t, tm, "Зарядка", "", 2, time.Time{})
+	assert.NoError(t, tm.SetTaskRecurrence(habit.ID, RecurDaily))
+	var ops []EventOp
+	tm.Subscribe(func(e Event) { ops = append(ops, e.Op) })
+
+	// Отметка повторения не выполняет задачу, поэтому это не EventCompleted
+	assert.NoError(t, tm.ToggleTaskCompletion(habit.ID))
+	assert.NoError(t, tm.ToggleTaskCompletion(habit.ID))
+	assert.Equal(t, []EventOp{EventOccurrence, EventOccurrence}, ops)
+}
+
+func TestToggleOccurrenceInDueZone(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+	tm.SetDueZone(DueZoneUTC)
+
+	// Срок без даты получает день в поясе сроков, а не по часам компьютера
+	habit := mustAddTask(t, tm, "Зарядка", "", 2, time.Time{})
+	assert.NoError(t, tm.SetTaskRecurrence(habit.ID, RecurDaily))
+	assert.NoError(t, tm.ToggleTaskCompletion(habit.ID))
+	got := tm.GetTask(habit.ID)
+	assert.Equal(t, time.UTC, got.DueDate.Location())
+	assert.Equal(t, DueZoneUTC.Now().AddDate(0, 0, 1).Format(DueDateLayout), got.DueDay())
+	assert.True(t, got.DoneInPeriod(DueZoneUTC.Now()))
+}
+
+func TestStreak(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 9, 0, 0, 0, time.UTC) }
+	daily := &Task{Recurrence: RecurDaily, Completions: []time.Time{day(1), day(2), day(3), day(4), day(10), day(11), day(11), day(12)}}
+
+	assert.Equal(t, 3, daily.Streak(day(12)))
+	// Сегодня еще не отмечено - серия не прервана
+	assert.Equal(t, 3, daily.Streak(day(13)))
+	assert.Equal(t, 0, daily.Streak(day(14)))
+	assert.Equal(t, 4, daily.BestStreak())
+	assert.True(t, daily.CompletedOn(day(2)))
+	assert.False(t, daily.CompletedOn(day(5)))
+
+	// Неделя считается с понедельника: 5 и 11 октября 2026 - одна неделя
+	weekly := &Task{Recurrence: RecurWeekly, Completions: []time.Time{day(5), day(11), day(14), day(28)}}
+	assert.Equal(t, 1, weekly.Streak(day(30)))
+	assert.Equal(t, 2, weekly.BestStreak())
+
+	assert.Equal(t, 0, (&Task{Completions: []time.Time{day(1)}}).Streak(day(1)))
+}
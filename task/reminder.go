@@ -0,0 +1,64 @@
+package task
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// SetTaskReminder задает время напоминания о задаче; нулевое время снимает напоминание.
+// Отложенное напоминание при этом сбрасывается
+func (tm *TaskManager) SetTaskReminder(id int, at time.Time) error {
+	task := tm.GetTask(id)
+	if task == nil {
+		return notFoundError(id)
+	}
+
+	task.RemindAt = at
+	task.SnoozedUntil = time.Time{}
+	tm.touch(task)
+	tm.emit(EventUpdated, task)
+	return nil
+}
+
+// SetTaskReminderOffsets задает напоминания относительно срока: за сколько до него напомнить.
+// 0 - в срок. Повторы убираются, напоминания идут от самого раннего
+func (tm *TaskManager) SetTaskReminderOffsets(id int, offsets []time.Duration) error {
+	task := tm.GetTask(id)
+	if task == nil {
+		return notFoundError(id)
+	}
+	for _, offset := range offsets {
+		if offset < 0 {
+			return &ValidationError{Field: "reminder", Message: "must not be after the due time"}
+		}
+	}
+
+	sorted := slices.Clone(offsets)
+	slices.SortFunc(sorted, func(a, b time.Duration) int { return cmp.Compare(b, a) })
+	task.ReminderOffsets = slices.Compact(sorted)
+	tm.touch(task)
+	tm.emit(EventUpdated, task)
+	return nil
+}
+
+// SnoozeTask откладывает напоминание о задаче до времени until
+func (tm *TaskManager) SnoozeTask(id int, until time.Time) error {
+	task := tm.GetTask(id)
+	if task == nil {
+		return notFoundError(id)
+	}
+	if !task.HasReminders() {
+		return &ValidationError{Field: "reminder", Message: "is not set"}
+	}
+
+	task.SnoozedUntil = until
+	tm.touch(task)
+	tm.emit(EventUpdated, task)
+	return nil
+}
+
+// HasReminders сообщает, заданы ли у задачи напоминания
+func (t *Task) HasReminders() bool {
+	return !t.RemindAt.IsZero() || len(t.ReminderOffsets) > 0
+}
@@ -0,0 +1,41 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnoozeTask(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	added := mustAddTask(t, tm, "Позвонить врачу", "", 2, time.Now())
+	assert.ErrorIs(t, tm.SnoozeTask(added.ID, time.Now()), ErrValidation)
+
+	at := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+	assert.NoError(t, tm.SetTaskReminder(added.ID, at))
+	assert.True(t, tm.GetTask(added.ID).HasReminders())
+
+	assert.NoError(t, tm.SnoozeTask(added.ID, at.Add(time.Hour)))
+	assert.Equal(t, at.Add(time.Hour), tm.GetTask(added.ID).SnoozedUntil)
+
+	// Новое время напоминания сбрасывает откладывание
+	assert.NoError(t, tm.SetTaskReminder(added.ID, at.Add(2*time.Hour)))
+	assert.True(t, tm.GetTask(added.ID).SnoozedUntil.IsZero())
+}
+
+func TestSetTaskReminderOffsets(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	added := mustAddTask(t, tm, "Сдать отчет", "", 2, time.Now())
+	day, week := 24*time.Hour, 7*24*time.Hour
+	assert.NoError(t, tm.SetTaskReminderOffsets(added.ID, []time.Duration{0, week, day, week}))
+	assert.Equal(t, []time.Duration{week, day, 0}, tm.GetTask(added.ID).ReminderOffsets)
+	assert.ErrorIs(t, tm.SetTaskReminderOffsets(added.ID, []time.Duration{-time.Hour}), ErrValidation)
+
+	// Напоминания до срока позволяют отложить напоминание
+	assert.NoError(t, tm.SnoozeTask(added.ID, time.Now().Add(time.Hour)))
+}
@@ -0,0 +1,109 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Прежние описания задачи хранятся вместе с ней: случайно затертую заметку можно сравнить
+// с текущей и вернуть
+
+// maxDescriptionRevisions - сколько прежних описаний хранится у задачи
+const maxDescriptionRevisions = 20
+
+// DescriptionRevision - прежнее описание задачи и когда его заменили
+type DescriptionRevision struct {
+	Text       string    `json:"text"`
+	ReplacedAt time.Time `json:"replaced_at"`
+}
+
+// setDescription меняет описание задачи и запоминает прежнее. Пустое описание
+// не запоминается: сравнивать с ним нечего
+func setDescription(task *Task, description string, now time.Time) {
+	if task.Description == description {
+		return
+	}
+	if task.Description != "" {
+		task.DescriptionRevisions = append(task.DescriptionRevisions, DescriptionRevision{Text: task.Description, ReplacedAt: now})
+		if extra := len(task.DescriptionRevisions) - maxDescriptionRevisions; extra > 0 {
+			task.DescriptionRevisions = append([]DescriptionRevision(nil), task.DescriptionRevisions[extra:]...)
+		}
+	}
+	task.Description = description
+}
+
+// RestoreDescription возвращает задаче описание из прежней версии revision.
+// Текущее описание при этом тоже попадает в историю, поэтому возврат можно отменить
+func (tm *TaskManager) RestoreDescription(id, revision int) error {
+	task := tm.GetTask(id)
+	if task == nil {
+		return notFoundError(id)
+	}
+	if revision < 0 || revision >= len(task.DescriptionRevisions) {
+		return &ValidationError{Field: "revision", Message: fmt.Sprintf("no revision %d", revision)}
+	}
+	setDescription(task, task.DescriptionRevisions[revision].Text, time.Now())
+	tm.touch(task)
+	tm.emit(EventUpdated, task)
+	tm.linkMentions(task)
+	return nil
+}
+
+// DiffOp - вид строки сравнения текстов
+type DiffOp int
+
+const (
+	DiffSame    DiffOp = iota // строка есть в обоих текстах
+	DiffRemoved               // строка только в прежнем тексте
+	DiffAdded                 // строка только в новом тексте
+)
+
+// DiffLine - строка сравнения текстов
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+// DiffLines сравнивает тексты по строкам: сколько можно строк считаются общими,
+// остальные - удаленными из old или добавленными в new. Удаленные строки идут перед добавленными
+func DiffLines(old, new string) []DiffLine {
+	a, b := splitLines(old), splitLines(new)
+	// common[i][j] - длина наибольшей общей последовательности строк a[i:] и b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+	var diff []DiffLine
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			diff = append(diff, DiffLine{DiffSame, a[i]})
+			i, j = i+1, j+1
+		case j == len(b) || i < len(a) && common[i+1][j] >= common[i][j+1]:
+			diff = append(diff, DiffLine{DiffRemoved, a[i]})
+			i++
+		default:
+			diff = append(diff, DiffLine{DiffAdded, b[j]})
+			j++
+		}
+	}
+	return diff
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
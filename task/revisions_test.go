@@ -0,0 +1,57 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescriptionRevisions(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	notes := mustAddTask(t, tm, "Заметки", "", 2, time.Now())
+	require.NoError(t, tm.UpdateTask(notes.ID, notes.Title, "первая версия", notes.Priority, notes.DueDate, false))
+	assert.Empty(t, notes.DescriptionRevisions, "empty description is not kept")
+
+	require.NoError(t, tm.UpdateTask(notes.ID, notes.Title, "вторая версия", notes.Priority, notes.DueDate, false))
+	require.NoError(t, tm.UpdateTask(notes.ID, notes.Title, "вторая версия", 3, notes.DueDate, false))
+	require.Len(t, notes.DescriptionRevisions, 1)
+	assert.Equal(t, "первая версия", notes.DescriptionRevisions[0].Text)
+	assert.False(t, notes.DescriptionRevisions[0].ReplacedAt.IsZero())
+
+	// Возврат тоже попадает в историю
+	require.NoError(t, tm.RestoreDescription(notes.ID, 0))
+	assert.Equal(t, "первая версия", notes.Description)
+	require.Len(t, notes.DescriptionRevisions, 2)
+	assert.Equal(t, "вторая версия", notes.DescriptionRevisions[1].Text)
+
+	assert.ErrorIs(t, tm.RestoreDescription(notes.ID, 5), ErrValidation)
+	assert.ErrorIs(t, tm.RestoreDescription(999, 0), ErrNotFound)
+}
+
+func TestDescriptionRevisionsLimit(t *testing.T) {
+	task := &Task{Description: "0"}
+	now := time.Now()
+	for i := 1; i <= maxDescriptionRevisions+5; i++ {
+		setDescription(task, string(rune('a'+i)), now)
+	}
+	require.Len(t, task.DescriptionRevisions, maxDescriptionRevisions)
+	assert.Equal(t, string(rune('a'+5)), task.DescriptionRevisions[0].Text)
+}
+
+func TestDiffLines(t *testing.T) {
+	diff := DiffLines("купить:\nхлеб\nмолоко\nсыр", "купить:\nхлеб\nкефир\nсыр\nяйца")
+	assert.Equal(t, []DiffLine{
+		{DiffSame, "купить:"},
+		{DiffSame, "хлеб"},
+		{DiffRemoved, "молоко"},
+		{DiffAdded, "кефир"},
+		{DiffSame, "сыр"},
+		{DiffAdded, "яйца"},
+	}, diff)
+	assert.Equal(t, []DiffLine{{DiffAdded, "текст"}}, DiffLines("", "текст"))
+	assert.Empty(t, DiffLines("", ""))
+}
@@ -0,0 +1,32 @@
+package task
+
+import "time"
+
+// SetTaskDates задает начало и срок задачи. Нулевое начало - задача без начала,
+// начало не может быть позже срока
+func (tm *TaskManager) SetTaskDates(id int, start, due time.Time) error {
+	task := tm.GetTask(id)
+	if task == nil {
+		return notFoundError(id)
+	}
+	if err := tm.validateTask(task.Title, task.Priority, due, task.CreatedAt); err != nil {
+		return err
+	}
+	if !start.IsZero() && start.Format(DueDateLayout) > due.Format(DueDateLayout) {
+		return &ValidationError{Field: "start date", Message: "must not be after the due date"}
+	}
+
+	task.StartDate = start
+	task.DueDate = due
+	tm.touch(task)
+	tm.emit(EventUpdated, task)
+	return nil
+}
+
+// StartDay возвращает первый день работы над задачей: день начала или, если его нет, день срока
+func (t *Task) StartDay() string {
+	if t.StartDate.IsZero() {
+		return t.DueDay()
+	}
+	return t.StartDate.Format(DueDateLayout)
+}
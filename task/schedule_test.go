@@ -0,0 +1,36 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetTaskDates(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	due := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	added := mustAddTask(t, tm, "Ремонт", "", 2, due)
+	assert.Equal(t, "2026-10-20", added.StartDay())
+
+	start := due.AddDate(0, 0, -5)
+	assert.NoError(t, tm.SetTaskDates(added.ID, start, due.AddDate(0, 0, 1)))
+	got := tm.GetTask(added.ID)
+	assert.Equal(t, "2026-10-15", got.StartDay())
+	assert.Equal(t, "2026-10-21", got.DueDay())
+
+	// Начало в день срока допустимо, позже - нет
+	assert.NoError(t, tm.SetTaskDates(added.ID, due, due))
+	assert.ErrorIs(t, tm.SetTaskDates(added.ID, due.AddDate(0, 0, 1), due), ErrValidation)
+	assert.ErrorIs(t, tm.SetTaskDates(999, start, due), ErrNotFound)
+
+	// Без срока задача уходит в «Когда-нибудь», начало без срока не задается
+	assert.ErrorIs(t, tm.SetTaskDates(added.ID, start, time.Time{}), ErrValidation)
+	assert.NoError(t, tm.SetTaskDates(added.ID, time.Time{}, time.Time{}))
+	assert.True(t, tm.GetTask(added.ID).DueDate.IsZero())
+
+	assert.NoError(t, tm.SetTaskDates(added.ID, time.Time{}, due))
+	assert.True(t, tm.GetTask(added.ID).StartDate.IsZero())
+}
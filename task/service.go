@@ -0,0 +1,30 @@
+package task
+
+import "time"
+
+// TaskService - операции над задачами, доступные интерфейсу, консольной утилите и серверу.
+// Позволяет подменять менеджер задач в тестах и встраивать его в другие программы
+type TaskService interface {
+	Tasks() []*Task
+	GetTask(id int) *Task
+	GetTaskByUUID(uuid string) *Task
+	SearchTasks(keyword string) []*Task
+	FilterTasksByStatus(completed bool) []*Task
+
+	AddTask(title, description string, priority int, dueDate time.Time) (*Task, error)
+	UpdateTask(id int, title, description string, priority int, dueDate time.Time, completed bool) error
+	DeleteTask(id int) error
+	ToggleTaskCompletion(id int) error
+	SetTaskTags(id int, tags []string) error
+	SetTaskAssignee(id int, assignee string) error
+	CheckUnchanged(id int, updatedAt time.Time) error
+	DueZone() DueZone
+
+	Subscribe(fn func(Event)) (unsubscribe func())
+
+	LoadFromFile() error
+	SaveToFile() error
+	IsDirty() bool
+}
+
+var _ TaskService = (*TaskManager)(nil)
@@ -0,0 +1,107 @@
+package task
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// similarThreshold - сходство названий, начиная с которого задачи считаются вероятными
+// дубликатами: 1 - названия совпадают, 0 - не имеют ничего общего
+const similarThreshold = 0.85
+
+// minFuzzyLength - самое короткое название, которое сравнивается нечетко. Короткие названия
+// вроде «Чай» и «Сок» отличаются одной-двумя буквами, но это разные задачи
+const minFuzzyLength = 6
+
+// NormalizeTitle приводит название к виду для сравнения: нижний регистр, без знаков
+// препинания и лишних пробелов, ё как е
+func NormalizeTitle(title string) string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.ReplaceAll(strings.Join(words, " "), "ё", "е")
+}
+
+// TitleSimilarity сравнивает названия: 1 - одинаковые после NormalizeTitle или из тех же слов
+// в другом порядке, иначе доля символов, которые не нужно править, чтобы получить одно из другого
+func TitleSimilarity(a, b string) float64 {
+	a, b = NormalizeTitle(a), NormalizeTitle(b)
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b || sameWords(a, b) {
+		return 1
+	}
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if min(len(ra), len(rb)) < minFuzzyLength {
+		return 0
+	}
+	// Названия слишком разной длины не могут быть похожими, расстояние не считаем
+	if float64(longest-min(len(ra), len(rb)))/float64(longest) > 1-similarThreshold {
+		return 0
+	}
+	return 1 - float64(editDistance(ra, rb))/float64(longest)
+}
+
+// sameWords сообщает, состоят ли названия из одних и тех же слов
+func sameWords(a, b string) bool {
+	wa, wb := strings.Fields(a), strings.Fields(b)
+	slices.Sort(wa)
+	slices.Sort(wb)
+	return slices.Equal(wa, wb)
+}
+
+// editDistance возвращает расстояние Левенштейна: сколько символов вставить, удалить или заменить
+func editDistance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// FindSimilar возвращает открытые задачи с названием, похожим на title, самые похожие первыми.
+// По ним перед добавлением предупреждают о вероятном дубликате
+func (tm *TaskManager) FindSimilar(title string) []*Task {
+	type match struct {
+		task  *Task
+		score float64
+	}
+	var matches []match
+	for _, task := range tm.tasks {
+		if task.Completed || task.Archived {
+			continue
+		}
+		if score := TitleSimilarity(title, task.Title); score >= similarThreshold {
+			matches = append(matches, match{task, score})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b match) int {
+		switch {
+		case a.score > b.score:
+			return -1
+		case a.score < b.score:
+			return 1
+		}
+		return 0
+	})
+	similar := make([]*Task, len(matches))
+	for i, m := range matches {
+		similar[i] = m.task
+	}
+	return similar
+}
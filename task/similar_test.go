@@ -0,0 +1,41 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTitle(t *testing.T) {
+	assert.Equal(t, "позвонить в банк", NormalizeTitle("  Позвонить в БАНК!!! "))
+	assert.Equal(t, "еще раз проверить отчет 2", NormalizeTitle("Ещё раз: проверить отчет #2"))
+}
+
+func TestTitleSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, TitleSimilarity("Позвонить в банк", "позвонить в банк."))
+	assert.Equal(t, 1.0, TitleSimilarity("Отчет квартальный", "квартальный отчет"))
+	assert.GreaterOrEqual(t, TitleSimilarity("Подготовить презентацию", "Подготовить презентацыю"), similarThreshold)
+	assert.Less(t, TitleSimilarity("Подготовить презентацию", "Подготовить отчет"), similarThreshold)
+	// Короткие названия сравниваются только точно
+	assert.Zero(t, TitleSimilarity("Чай", "Чаи"))
+	assert.Zero(t, TitleSimilarity("", "Отчет"))
+}
+
+func TestFindSimilar(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+	due := time.Now().Add(24 * time.Hour)
+	bank := mustAddTask(t, tm, "Позвонить в банк", "", 2, due)
+	banks := mustAddTask(t, tm, "Позвонить в банки", "", 2, due)
+	done := mustAddTask(t, tm, "позвонить в банк", "", 2, due)
+	assert.NoError(t, tm.ToggleTaskCompletion(done.ID))
+	mustAddTask(t, tm, "Купить хлеб", "", 2, due)
+
+	similar := tm.FindSimilar("Позвонить в банк!")
+	if assert.Len(t, similar, 2, "completed tasks are not duplicates") {
+		assert.Equal(t, bank.ID, similar[0].ID)
+		assert.Equal(t, banks.ID, similar[1].ID)
+	}
+	assert.Empty(t, tm.FindSimilar("Отправить письмо"))
+}
@@ -0,0 +1,80 @@
+package task
+
+import (
+	"sort"
+	"strings"
+)
+
+// SortMode определяет порядок задач
+type SortMode int
+
+const (
+	SortNone SortMode = iota
+	SortByPriority
+	SortByDueDate
+	SortByID
+	SortByTitle
+	SortByTags
+	SortByStatus
+	SortByUpdated
+	SortByAssignee
+	SortByContext
+	SortByProgress
+)
+
+// taskLess возвращает функцию сравнения задач для режима сортировки
+func taskLess(mode SortMode) func(a, b *Task) bool {
+	switch mode {
+	case SortByPriority:
+		// Сначала высокий приоритет
+		return func(a, b *Task) bool { return a.Priority > b.Priority }
+	case SortByDueDate:
+		// Задачи без срока - после всех задач со сроком
+		return func(a, b *Task) bool {
+			if a.DueDate.IsZero() != b.DueDate.IsZero() {
+				return b.DueDate.IsZero()
+			}
+			return a.DueDate.Before(b.DueDate)
+		}
+	case SortByID:
+		return func(a, b *Task) bool { return a.ID < b.ID }
+	case SortByTitle:
+		return func(a, b *Task) bool { return strings.ToLower(a.Title) < strings.ToLower(b.Title) }
+	case SortByTags:
+		return func(a, b *Task) bool { return strings.Join(a.Tags, ",") < strings.Join(b.Tags, ",") }
+	case SortByStatus:
+		// Сначала активные задачи
+		return func(a, b *Task) bool { return !a.Completed && b.Completed }
+	case SortByUpdated:
+		// Сначала недавно измененные
+		return func(a, b *Task) bool { return a.UpdatedAt.After(b.UpdatedAt) }
+	case SortByAssignee:
+		return func(a, b *Task) bool { return strings.ToLower(a.Assignee) < strings.ToLower(b.Assignee) }
+	case SortByProgress:
+		// Сначала более готовые
+		return func(a, b *Task) bool { return a.ProgressPercent() > b.ProgressPercent() }
+	case SortByContext:
+		return func(a, b *Task) bool { return strings.ToLower(a.Context) < strings.ToLower(b.Context) }
+	}
+	return nil
+}
+
+// SortTasks возвращает отсортированную копию списка, не меняя исходный
+func SortTasks(tasks []*Task, mode SortMode, reverse bool) []*Task {
+	sortedTasks := make([]*Task, len(tasks))
+	copy(sortedTasks, tasks)
+
+	less := taskLess(mode)
+	if less == nil {
+		return sortedTasks
+	}
+
+	sort.SliceStable(sortedTasks, func(i, j int) bool {
+		if reverse {
+			return less(sortedTasks[j], sortedTasks[i])
+		}
+		return less(sortedTasks[i], sortedTasks[j])
+	})
+
+	return sortedTasks
+}
@@ -0,0 +1,692 @@
+// Package task содержит задачи и менеджер задач: изменение, поиск, сортировку,
+// сохранение в хранилище. Пакет не зависит от интерфейса, поэтому его можно
+// использовать в консольной утилите, сервере и тестах
+package task
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+	"time"
+
+	"taskmanager/storage"
+)
+
+// Task представляет одну задачу
+type Task struct {
+	ID          int       `json:"id"`   // номер задачи для отображения, уникален в пределах файла
+	UUID        string    `json:"uuid"` // постоянный идентификатор для синхронизации и слияния файлов
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Priority    int       `json:"priority"` // 1 - низкий, 2 - средний, 3 - высокий
+	DueDate     time.Time `json:"due_date"`
+	StartDate   time.Time `json:"start_date,omitzero"` // когда начать работу над задачей, для таймлайна
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"` // время последнего изменения, по нему решаются конфликты синхронизации
+	Completed   bool      `json:"completed"`
+	CompletedAt time.Time `json:"completed_at,omitzero"` // когда задачу выполнили, для диаграммы сгорания
+	Tags        []string  `json:"tags,omitempty"`
+	Assignee    string    `json:"assignee,omitempty"` // кто выполняет задачу, для общих списков
+	Context     string    `json:"context,omitempty"`  // где можно выполнить задачу по GTD: @дом, @работа
+	URL         string    `json:"url,omitempty"`      // страница, с которой связана задача
+	Location    string    `json:"location,omitempty"` // где выполнить задачу: адрес или координаты
+	Archived    bool      `json:"archived,omitempty"` // задача убрана в архив и не показывается в списке
+
+	Checklist []ChecklistItem `json:"checklist,omitempty"`  // пункты, которые нужно выполнить
+	TimeSpent time.Duration   `json:"time_spent,omitempty"` // сколько времени учтено таймером
+
+	EstimatedMinutes int `json:"estimated_minutes,omitempty"` // оценка трудоемкости, для загрузки по дням
+	Progress         int `json:"progress,omitempty"`          // готовность в процентах, если нет чек-листа
+
+	Links     []string `json:"links,omitempty"`      // UUID связанных задач, связь хранится с обеих сторон
+	DependsOn []string `json:"depends_on,omitempty"` // UUID задач, которые нужно выполнить раньше этой
+
+	RemindAt        time.Time       `json:"remind_at,omitzero"`         // когда напомнить о задаче
+	ReminderOffsets []time.Duration `json:"reminder_offsets,omitempty"` // за сколько до срока напомнить, 0 - в срок
+	SnoozedUntil    time.Time       `json:"snoozed_until,omitzero"`     // напоминание отложено до этого времени
+	Urgency         Urgency         `json:"urgency,omitempty"`          // как напоминать; пусто - по настройкам для приоритета
+	Sound           string          `json:"sound,omitempty"`            // файл звука напоминания вместо заданного в настройках
+
+	Recurrence  Recurrence  `json:"recurrence,omitempty"`   // задача-привычка повторяется каждый день или неделю
+	Completions []time.Time `json:"completions,omitempty"`  // когда отмечалось выполнение повторяющейся задачи
+	PreviousDue *time.Time  `json:"previous_due,omitempty"` // срок до отметки в текущем периоде, чтобы ее снять; нулевой - срока не было
+
+	MyDay string `json:"my_day,omitempty"` // день, в который задачу добавили в «Мой день», 2006-01-02
+
+	WaitingOn string `json:"waiting_on,omitempty"` // от кого ждем ответа или действия; пусто - задача не ждет
+
+	Goal string `json:"goal,omitempty"` // идентификатор цели, к которой ведет задача
+
+	Icon string `json:"icon,omitempty"` // эмодзи перед названием в списке и календаре
+
+	DescriptionRevisions []DescriptionRevision `json:"description_revisions,omitempty"` // прежние описания, старые первыми
+}
+
+// ChecklistItem - пункт чек-листа задачи
+type ChecklistItem struct {
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+}
+
+// Clone возвращает копию задачи, не связанную с оригиналом
+func (t *Task) Clone() *Task {
+	c := *t
+	c.Tags = append([]string(nil), t.Tags...)
+	c.Checklist = append([]ChecklistItem(nil), t.Checklist...)
+	c.Completions = append([]time.Time(nil), t.Completions...)
+	c.Links = append([]string(nil), t.Links...)
+	c.DependsOn = append([]string(nil), t.DependsOn...)
+	c.ReminderOffsets = append([]time.Duration(nil), t.ReminderOffsets...)
+	c.DescriptionRevisions = append([]DescriptionRevision(nil), t.DescriptionRevisions...)
+	if t.PreviousDue != nil {
+		due := *t.PreviousDue
+		c.PreviousDue = &due
+	}
+	return &c
+}
+
+// ProgressPercent возвращает готовность задачи в процентах: по чек-листу, если он есть,
+// иначе - заданную вручную
+func (t *Task) ProgressPercent() int {
+	if len(t.Checklist) == 0 {
+		return t.Progress
+	}
+	done := 0
+	for _, item := range t.Checklist {
+		if item.Done {
+			done++
+		}
+	}
+	return done * 100 / len(t.Checklist)
+}
+
+// TaskManager управляет списком задач
+type TaskManager struct {
+	tasks      []*Task
+	nextID     int
+	store      storage.Storage
+	dirty      bool   // есть изменения, не сохраненные в файл
+	backupKeep int    // сколько резервных копий хранить при сохранении
+	fileHash   string // хеш содержимого файла при последнем чтении или записи
+	passphrase string // пароль шифрования файла, пустой - файл не шифруется
+	keyChanged bool   // пароль сменили, а файл на диске еще записан в прежнем виде
+
+	requireDueAfterCreated bool                     // срок задачи не может быть раньше дня ее создания
+	dueZone                DueZone                  // часовой пояс сроков задач
+	dayOff                 func(day time.Time) bool // нерабочие дни, которые пропускают повторение и перенос срока
+
+	subscribers      []subscriber
+	nextSubscriberID int
+
+	storageObserver func(op StorageOp, duration time.Duration, err error)
+}
+
+// StorageOp - операция с хранилищем, о которой сообщается наблюдателю
+type StorageOp string
+
+const (
+	StorageSave StorageOp = "save"
+	StorageLoad StorageOp = "load"
+)
+
+var (
+	// ErrFileChanged возвращается при сохранении, если файл задач изменила другая программа
+	ErrFileChanged = errors.New("tasks file was changed by another program")
+	// ErrPassphraseRequired возвращается при загрузке зашифрованного файла без пароля
+	ErrPassphraseRequired = errors.New("tasks file is encrypted, passphrase required")
+)
+
+// NewTaskManager создает новый менеджер задач, который сохраняет задачи в store
+func NewTaskManager(store storage.Storage) *TaskManager {
+	return &TaskManager{
+		tasks:      []*Task{},
+		nextID:     1,
+		store:      store,
+		backupKeep: DefaultBackupKeep,
+	}
+}
+
+// Tasks возвращает все задачи в порядке добавления
+func (tm *TaskManager) Tasks() []*Task {
+	return tm.tasks
+}
+
+// AddTask проверяет и добавляет новую задачу
+func (tm *TaskManager) AddTask(title, description string, priority int, dueDate time.Time) (*Task, error) {
+	createdAt := time.Now()
+	if err := tm.validateTask(title, priority, dueDate, createdAt); err != nil {
+		return nil, err
+	}
+
+	task := &Task{
+		ID:          tm.nextID,
+		UUID:        newUUID(),
+		Title:       strings.TrimSpace(title),
+		Description: description,
+		Priority:    priority,
+		DueDate:     dueDate,
+		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+		Completed:   false,
+	}
+
+	tm.tasks = append(tm.tasks, task)
+	tm.nextID++
+	tm.dirty = true
+	tm.emit(EventAdded, task)
+	tm.linkMentions(task)
+	return task, nil
+}
+
+// GetTask возвращает задачу по ID
+func (tm *TaskManager) GetTask(id int) *Task {
+	for _, task := range tm.tasks {
+		if task.ID == id {
+			return task
+		}
+	}
+	return nil
+}
+
+// GetTaskByUUID возвращает задачу по UUID
+func (tm *TaskManager) GetTaskByUUID(uuid string) *Task {
+	for _, task := range tm.tasks {
+		if task.UUID == uuid {
+			return task
+		}
+	}
+	return nil
+}
+
+// DeleteTask удаляет задачу по ID
+func (tm *TaskManager) DeleteTask(id int) error {
+	for i, task := range tm.tasks {
+		if task.ID == id {
+			tm.tasks = append(tm.tasks[:i], tm.tasks[i+1:]...)
+			tm.dirty = true
+			tm.emit(EventDeleted, task)
+			for _, other := range tm.tasks {
+				tm.unlink(other, task.UUID)
+				tm.undepend(other, task.UUID)
+			}
+			return nil
+		}
+	}
+	return notFoundError(id)
+}
+
+// UpdateTask проверяет и обновляет существующую задачу
+func (tm *TaskManager) UpdateTask(id int, title, description string, priority int, dueDate time.Time, completed bool) error {
+	task := tm.GetTask(id)
+	if task == nil {
+		return notFoundError(id)
+	}
+	if err := tm.validateTask(title, priority, dueDate, task.CreatedAt); err != nil {
+		return err
+	}
+
+	task.Title = strings.TrimSpace(title)
+	setDescription(task, description, time.Now())
+	task.Priority = priority
+	task.DueDate = dueDate
+	setCompleted(task, completed)
+	tm.touch(task)
+	tm.emit(EventUpdated, task)
+	tm.linkMentions(task)
+	return nil
+}
+
+// ToggleTaskCompletion изменяет статус выполнения задачи. Повторяющаяся задача
+// не закрывается: выполнение отмечается в истории, а срок переносится на следующее повторение
+func (tm *TaskManager) ToggleTaskCompletion(id int) error {
+	task := tm.GetTask(id)
+	if task == nil {
+		return notFoundError(id)
+	}
+	if task.Recurrence != RecurNone && !task.Completed {
+		tm.toggleOccurrence(task, tm.dueZone.Now())
+		return nil
+	}
+
+	setCompleted(task, !task.Completed)
+	tm.touch(task)
+	tm.emit(EventCompleted, task)
+	return nil
+}
+
+// setCompleted отмечает выполнение задачи и запоминает, когда ее выполнили
+func setCompleted(task *Task, completed bool) {
+	switch {
+	case completed && !task.Completed:
+		task.CompletedAt = time.Now()
+	case !completed:
+		task.CompletedAt = time.Time{}
+	}
+	task.Completed = completed
+}
+
+// DoneAt возвращает, когда задачу выполнили. В файлах, сохраненных до появления
+// CompletedAt, время выполнения не записано - тогда берется время последнего изменения
+func (t *Task) DoneAt() time.Time {
+	if t.CompletedAt.IsZero() {
+		return t.UpdatedAt
+	}
+	return t.CompletedAt
+}
+
+// SetTaskTags заменяет метки задачи
+func (tm *TaskManager) SetTaskTags(id int, tags []string) error {
+	task := tm.GetTask(id)
+	if task == nil {
+		return notFoundError(id)
+	}
+
+	task.Tags = tags
+	tm.touch(task)
+	tm.emit(EventUpdated, task)
+	return nil
+}
+
+// SetTaskAssignee назначает исполнителя задачи; пустая строка снимает назначение
+func (tm *TaskManager) SetTaskAssignee(id int, assignee string) error {
+	task := tm.GetTask(id)
+	if task == nil {
+		return notFoundError(id)
+	}
+
+	task.Assignee = strings.TrimSpace(assignee)
+	tm.touch(task)
+	tm.emit(EventUpdated, task)
+	return nil
+}
+
+// SetTaskContext задает контекст задачи; пустая строка снимает контекст
+func (tm *TaskManager) SetTaskContext(id int, context string) error {
+	task := tm.GetTask(id)
+	if task == nil {
+		return notFoundError(id)
+	}
+
+	task.Context = NormalizeContext(context)
+	tm.touch(task)
+	tm.emit(EventUpdated, task)
+	return nil
+}
+
+// SetTaskArchived убирает задачу в архив или возвращает из него
+func (tm *TaskManager) SetTaskArchived(id int, archived bool) error {
+	task := tm.GetTask(id)
+	if task == nil {
+		return notFoundError(id)
+	}
+
+	task.Archived = archived
+	tm.touch(task)
+	tm.emit(EventUpdated, task)
+	return nil
+}
+
+// SetTaskChecklist заменяет чек-лист задачи
+func (tm *TaskManager) SetTaskChecklist(id int, items []ChecklistItem) error {
+	task := tm.GetTask(id)
+	if task == nil {
+		return notFoundError(id)
+	}
+
+	task.Checklist = append([]ChecklistItem(nil), items...)
+	tm.touch(task)
+	tm.emit(EventUpdated, task)
+	return nil
+}
+
+// SetTaskProgress задает готовность задачи в процентах. Для задачи с чек-листом
+// готовность считается по пунктам, а заданное значение используется, если чек-лист удалят
+func (tm *TaskManager) SetTaskProgress(id int, progress int) error {
+	task := tm.GetTask(id)
+	if task == nil {
+		return notFoundError(id)
+	}
+	if progress < 0 || progress > 100 {
+		return &ValidationError{Field: "progress", Message: "must be between 0 and 100"}
+	}
+
+	task.Progress = progress
+	tm.touch(task)
+	tm.emit(EventUpdated, task)
+	return nil
+}
+
+// SetTaskEstimate задает оценку трудоемкости задачи в минутах; 0 снимает оценку
+func (tm *TaskManager) SetTaskEstimate(id int, minutes int) error {
+	task := tm.GetTask(id)
+	if task == nil {
+		return notFoundError(id)
+	}
+	if minutes < 0 {
+		return &ValidationError{Field: "estimate", Message: "must not be negative"}
+	}
+
+	task.EstimatedMinutes = minutes
+	tm.touch(task)
+	tm.emit(EventUpdated, task)
+	return nil
+}
+
+// AddTaskTime добавляет к задаче время, учтенное таймером
+func (tm *TaskManager) AddTaskTime(id int, spent time.Duration) error {
+	task := tm.GetTask(id)
+	if task == nil {
+		return notFoundError(id)
+	}
+	if spent <= 0 {
+		return nil
+	}
+
+	task.TimeSpent += spent
+	tm.touch(task)
+	tm.emit(EventUpdated, task)
+	return nil
+}
+
+// Assignees возвращает исполнителей, назначенных задачам, без повторов и по алфавиту
+func (tm *TaskManager) Assignees() []string {
+	var assignees []string
+	for _, task := range tm.tasks {
+		if task.Assignee != "" && !slices.Contains(assignees, task.Assignee) {
+			assignees = append(assignees, task.Assignee)
+		}
+	}
+	slices.Sort(assignees)
+	return assignees
+}
+
+// Contexts возвращает контексты задач без повторов и по алфавиту
+func (tm *TaskManager) Contexts() []string {
+	var contexts []string
+	for _, task := range tm.tasks {
+		if task.Context != "" && !slices.Contains(contexts, task.Context) {
+			contexts = append(contexts, task.Context)
+		}
+	}
+	slices.Sort(contexts)
+	return contexts
+}
+
+// touch отмечает изменение задачи
+func (tm *TaskManager) touch(task *Task) {
+	task.UpdatedAt = time.Now()
+	tm.dirty = true
+}
+
+// CheckUnchanged проверяет, что задача не менялась после updatedAt.
+// Клиент, редактировавший устаревшую версию задачи, получает ErrConflict
+func (tm *TaskManager) CheckUnchanged(id int, updatedAt time.Time) error {
+	task := tm.GetTask(id)
+	if task == nil {
+		return notFoundError(id)
+	}
+	if !task.UpdatedAt.Equal(updatedAt) {
+		return fmt.Errorf("%w: id %d", ErrConflict, id)
+	}
+	return nil
+}
+
+// SearchTasks ищет задачи по ключевому слову
+func (tm *TaskManager) SearchTasks(keyword string) []*Task {
+	keyword = strings.ToLower(keyword)
+	var results []*Task
+
+	for _, task := range tm.tasks {
+		if strings.Contains(strings.ToLower(task.Title), keyword) ||
+			strings.Contains(strings.ToLower(task.Description), keyword) {
+			results = append(results, task)
+		}
+	}
+
+	return results
+}
+
+// FilterTasksByStatus фильтрует задачи по статусу
+func (tm *TaskManager) FilterTasksByStatus(completed bool) []*Task {
+	var results []*Task
+
+	for _, task := range tm.tasks {
+		if task.Completed == completed {
+			results = append(results, task)
+		}
+	}
+
+	return results
+}
+
+// SortTasksByPriority сортирует задачи по приоритету
+func (tm *TaskManager) SortTasksByPriority() []*Task {
+	return SortTasks(tm.tasks, SortByPriority, false)
+}
+
+// SortTasksByDueDate сортирует задачи по сроку выполнения
+func (tm *TaskManager) SortTasksByDueDate() []*Task {
+	return SortTasks(tm.tasks, SortByDueDate, false)
+}
+
+// SaveToFile сохраняет задачи в файл. Если файл был изменен другой программой
+// после последнего чтения, возвращает ErrFileChanged, чтобы не затереть чужие изменения
+func (tm *TaskManager) SaveToFile() error {
+	return tm.save(false)
+}
+
+// SetStorageObserver задает функцию, которая узнает о каждом сохранении и загрузке:
+// сколько они заняли и чем закончились. Используется для метрик
+func (tm *TaskManager) SetStorageObserver(fn func(op StorageOp, duration time.Duration, err error)) {
+	tm.storageObserver = fn
+}
+
+// observeStorage сообщает наблюдателю об операции с хранилищем, начатой в start
+func (tm *TaskManager) observeStorage(op StorageOp, start time.Time, err error) {
+	if tm.storageObserver != nil {
+		tm.storageObserver(op, time.Since(start), err)
+	}
+}
+
+// ForceSaveToFile сохраняет задачи в файл, даже если он был изменен другой программой
+func (tm *TaskManager) ForceSaveToFile() error {
+	return tm.save(true)
+}
+
+// save записывает задачи в хранилище. Если хранилище умеет блокировать запись,
+// проверка изменений и запись идут под блокировкой, чтобы другой компьютер
+// с той же сетевой папкой не записал файл между ними
+func (tm *TaskManager) save(force bool) (err error) {
+	defer func(start time.Time) { tm.observeStorage(StorageSave, start, err) }(time.Now())
+
+	if locker, ok := tm.store.(storage.Locker); ok {
+		unlock, err := locker.Lock()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	// Проверяем даже при перезаписи: удаленное хранилище при чтении запоминает версию файла,
+	// поверх которой разрешит запись
+	changed, err := tm.FileChanged()
+	if err != nil && !force {
+		return err
+	}
+	if changed && !force {
+		return ErrFileChanged
+	}
+
+	data, err := encodeTasks(tm.tasks)
+	if err != nil {
+		return err
+	}
+
+	if tm.passphrase != "" {
+		if data, err = storage.Encrypt(data, tm.passphrase); err != nil {
+			return err
+		}
+	}
+
+	// Перед перезаписью сохраняем предыдущую версию файла
+	if err := tm.backupCurrentFile(); err != nil {
+		return fmt.Errorf("backup failed: %w", err)
+	}
+
+	if err := tm.store.Write(data); err != nil {
+		if errors.Is(err, storage.ErrModified) {
+			return ErrFileChanged // Файл изменили между проверкой и записью
+		}
+		return err
+	}
+
+	tm.fileHash = hashData(data)
+	tm.dirty = false
+	tm.keyChanged = false
+	return nil
+}
+
+// FileChanged сообщает, изменилось ли содержимое файла с момента последнего чтения или записи
+func (tm *TaskManager) FileChanged() (bool, error) {
+	data, err := tm.store.Read()
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return tm.fileHash != "", nil
+		}
+		return false, err
+	}
+	return hashData(data) != tm.fileHash, nil
+}
+
+// hashData возвращает хеш содержимого файла
+func hashData(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// IsDirty сообщает, есть ли изменения, не сохраненные в файл
+func (tm *TaskManager) IsDirty() bool {
+	return tm.dirty
+}
+
+// LoadFromFile загружает задачи из файла
+func (tm *TaskManager) LoadFromFile() (err error) {
+	defer func(start time.Time) { tm.observeStorage(StorageLoad, start, err) }(time.Now())
+
+	data, err := tm.store.Read()
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			tm.fileHash = ""
+			return nil // Файл не существует, это нормально для первого запуска
+		}
+		return err
+	}
+
+	tasks, err := tm.decodeFile(data)
+	if err != nil {
+		return err
+	}
+
+	tm.setTasks(tasks)
+	tm.fileHash = hashData(data)
+	tm.dirty = false
+	return nil
+}
+
+// decodeFile разбирает содержимое файла задач, расшифровывая его при необходимости
+func (tm *TaskManager) decodeFile(data []byte) ([]*Task, error) {
+	if storage.IsEncrypted(data) {
+		if tm.passphrase == "" {
+			return nil, ErrPassphraseRequired
+		}
+
+		plaintext, err := storage.Decrypt(data, tm.passphrase)
+		if err != nil {
+			return nil, err
+		}
+		data = plaintext
+	}
+	return decodeTasks(data)
+}
+
+// Unlock задает пароль и загружает зашифрованный файл задач.
+// При неверном пароле возвращает storage.ErrWrongPassphrase и не меняет состояние
+func (tm *TaskManager) Unlock(passphrase string) error {
+	previous := tm.passphrase
+	tm.passphrase = passphrase
+	if err := tm.LoadFromFile(); err != nil {
+		tm.passphrase = previous
+		return err
+	}
+	return nil
+}
+
+// SetPassphrase включает шифрование файла или меняет пароль; пустой пароль отключает шифрование.
+// Резервные копии сразу переводятся на новый пароль или расшифровываются, чтобы на диске
+// не осталось открытых копий или копий под сброшенным паролем, а файл перезаписывается
+// в новом виде при следующем сохранении. Если копию перевести не удалось, пароль не меняется
+func (tm *TaskManager) SetPassphrase(passphrase string) error {
+	if passphrase == tm.passphrase {
+		return nil
+	}
+	if err := tm.rekeyBackups(tm.passphrase, passphrase); err != nil {
+		return fmt.Errorf("backup re-encryption failed: %w", err)
+	}
+	tm.passphrase = passphrase
+	tm.keyChanged = true
+	tm.dirty = true
+	return nil
+}
+
+// IsEncrypted сообщает, шифруется ли файл задач
+func (tm *TaskManager) IsEncrypted() bool {
+	return tm.passphrase != ""
+}
+
+// CheckPassphrase проверяет, совпадает ли пароль с текущим
+func (tm *TaskManager) CheckPassphrase(passphrase string) bool {
+	return subtle.ConstantTimeCompare([]byte(passphrase), []byte(tm.passphrase)) == 1
+}
+
+// setTasks заменяет список задач
+func (tm *TaskManager) setTasks(tasks []*Task) {
+	tm.tasks = tasks
+
+	// Обновляем nextID
+	for _, task := range tm.tasks {
+		if task.ID >= tm.nextID {
+			tm.nextID = task.ID + 1
+		}
+	}
+	tm.emit(EventReloaded, nil)
+}
+
+// ParseTags разбирает метки, введенные через запятую
+func ParseTags(text string) []string {
+	var tags []string
+	for _, tag := range strings.Split(text, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// NormalizeContext приводит контекст к виду «@дом»: без пробелов по краям и с @ в начале
+func NormalizeContext(context string) string {
+	context = strings.TrimSpace(context)
+	if context == "" || strings.HasPrefix(context, "@") {
+		return context
+	}
+	return "@" + context
+}
+
+// PriorityText возвращает название приоритета для интерфейса
+func PriorityText(priority int) string {
+	return map[int]string{1: "низкий", 2: "средний", 3: "высокий"}[priority]
+}
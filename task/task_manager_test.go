@@ -0,0 +1,613 @@
+package task
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/storage"
+)
+
+const testFilename = "test_tasks.json"
+const testCSVFilename = "test_export.csv"
+
+func setupTestManager() *TaskManager {
+	os.Remove(testFilename)    // Удаляем файл, если он существует
+	os.Remove(testCSVFilename) // Удаляем файл экспорта, если он существует
+	return NewTaskManager(storage.NewFile(testFilename))
+}
+
+func teardownTestManager() {
+	os.Remove(testFilename)
+	os.Remove(testCSVFilename)
+
+	// Удаляем резервные копии, созданные при сохранении
+	backups, _ := filepath.Glob(testFilename + ".*")
+	for _, backup := range backups {
+		os.Remove(backup)
+	}
+}
+
+// mustAddTask добавляет задачу и завершает тест при ошибке
+func mustAddTask(t *testing.T, tm *TaskManager, title, description string, priority int, dueDate time.Time) *Task {
+	t.Helper()
+	task, err := tm.AddTask(title, description, priority, dueDate)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return task
+}
+
+func TestAddTask(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	// Добавляем задачу
+	title := "Test Task"
+	description := "Test Description"
+	priority := 2
+	dueDate := time.Now().Add(24 * time.Hour)
+
+	task, err := tm.AddTask(title, description, priority, dueDate)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, task)
+	assert.Equal(t, title, task.Title)
+	assert.Equal(t, description, task.Description)
+	assert.Equal(t, priority, task.Priority)
+	assert.Equal(t, dueDate.Format("2006-01-02"), task.DueDate.Format("2006-01-02"))
+	assert.False(t, task.Completed)
+	assert.Equal(t, 1, task.ID)
+
+	// Проверяем, что задача добавлена в список
+	assert.Equal(t, 1, len(tm.tasks))
+	assert.Equal(t, 2, tm.nextID)
+}
+
+func TestGetTask(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	task := mustAddTask(t, tm, "Task 1", "Description", 1, time.Now())
+	tm.AddTask("Task 2", "Description", 2, time.Now())
+
+	foundTask := tm.GetTask(task.ID)
+	assert.NotNil(t, foundTask)
+	assert.Equal(t, task.ID, foundTask.ID)
+	assert.Equal(t, task.Title, foundTask.Title)
+
+	// Проверяем отсутствующую задачу
+	notFoundTask := tm.GetTask(999)
+	assert.Nil(t, notFoundTask)
+}
+
+func TestDeleteTask(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	task := mustAddTask(t, tm, "Task to delete", "Description", 1, time.Now())
+
+	// Удаляем существующую задачу
+	err := tm.DeleteTask(task.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(tm.tasks))
+
+	// Пытаемся удалить несуществующую задачу
+	err = tm.DeleteTask(999)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestUpdateTask(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	task := mustAddTask(t, tm, "Original Title", "Original Description", 1, time.Now())
+
+	newTitle := "Updated Title"
+	newDescription := "Updated Description"
+	newPriority := 3
+	newDueDate := time.Now().Add(48 * time.Hour)
+	newCompleted := true
+
+	err := tm.UpdateTask(task.ID, newTitle, newDescription, newPriority, newDueDate, newCompleted)
+	assert.NoError(t, err)
+
+	updatedTask := tm.GetTask(task.ID)
+	assert.NotNil(t, updatedTask)
+	assert.Equal(t, newTitle, updatedTask.Title)
+	assert.Equal(t, newDescription, updatedTask.Description)
+	assert.Equal(t, newPriority, updatedTask.Priority)
+	assert.Equal(t, newDueDate.Format("2006-01-02"), updatedTask.DueDate.Format("2006-01-02"))
+	assert.Equal(t, newCompleted, updatedTask.Completed)
+
+	// Пытаемся обновить несуществующую задачу
+	err = tm.UpdateTask(999, "Title", "Description", 1, time.Now(), false)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestToggleTaskCompletion(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	task := mustAddTask(t, tm, "Task to toggle", "Description", 2, time.Now())
+	assert.False(t, task.Completed)
+
+	// Переключаем статус
+	err := tm.ToggleTaskCompletion(task.ID)
+	assert.NoError(t, err)
+	assert.True(t, tm.GetTask(task.ID).Completed)
+	assert.False(t, tm.GetTask(task.ID).CompletedAt.IsZero())
+	assert.Equal(t, tm.GetTask(task.ID).CompletedAt, tm.GetTask(task.ID).DoneAt())
+
+	// Переключаем еще раз
+	err = tm.ToggleTaskCompletion(task.ID)
+	assert.NoError(t, err)
+	assert.False(t, tm.GetTask(task.ID).Completed)
+	assert.True(t, tm.GetTask(task.ID).CompletedAt.IsZero())
+
+	// Пытаемся переключить несуществующую задачу
+	err = tm.ToggleTaskCompletion(999)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestSetTaskTags(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	task := mustAddTask(t, tm, "Tagged task", "Description", 2, time.Now())
+
+	// Метки разбираются из строки через запятую
+	err := tm.SetTaskTags(task.ID, ParseTags(" work, home ,, urgent"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"work", "home", "urgent"}, tm.GetTask(task.ID).Tags)
+
+	// Пустая строка очищает метки
+	tm.SetTaskTags(task.ID, ParseTags(""))
+	assert.Empty(t, tm.GetTask(task.ID).Tags)
+
+	// Пытаемся изменить метки несуществующей задачи
+	err = tm.SetTaskTags(999, []string{"work"})
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestSetTaskAssignee(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	first := mustAddTask(t, tm, "Вынести мусор", "", 2, time.Now())
+	second := mustAddTask(t, tm, "Полить цветы", "", 2, time.Now())
+	mustAddTask(t, tm, "Купить хлеб", "", 2, time.Now())
+
+	assert.NoError(t, tm.SetTaskAssignee(first.ID, " Петя "))
+	assert.NoError(t, tm.SetTaskAssignee(second.ID, "Маша"))
+	assert.Equal(t, "Петя", tm.GetTask(first.ID).Assignee)
+	assert.Equal(t, []string{"Маша", "Петя"}, tm.Assignees())
+
+	// Пустая строка снимает назначение
+	assert.NoError(t, tm.SetTaskAssignee(first.ID, ""))
+	assert.Equal(t, []string{"Маша"}, tm.Assignees())
+
+	assert.ErrorIs(t, tm.SetTaskAssignee(999, "Маша"), ErrNotFound)
+}
+
+func TestSetTaskContext(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	first := mustAddTask(t, tm, "Вынести мусор", "", 2, time.Now())
+	second := mustAddTask(t, tm, "Написать отчет", "", 2, time.Now())
+
+	// Контекст без @ дополняется
+	assert.NoError(t, tm.SetTaskContext(first.ID, " дом "))
+	assert.NoError(t, tm.SetTaskContext(second.ID, "@работа"))
+	assert.Equal(t, "@дом", tm.GetTask(first.ID).Context)
+	assert.Equal(t, []string{"@дом", "@работа"}, tm.Contexts())
+
+	assert.NoError(t, tm.SetTaskContext(first.ID, ""))
+	assert.Equal(t, []string{"@работа"}, tm.Contexts())
+
+	assert.ErrorIs(t, tm.SetTaskContext(999, "@дом"), ErrNotFound)
+}
+
+func TestTaskProgress(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	added := mustAddTask(t, tm, "Переезд", "", 2, time.Now())
+	assert.NoError(t, tm.SetTaskProgress(added.ID, 40))
+	assert.Equal(t, 40, tm.GetTask(added.ID).ProgressPercent())
+	assert.ErrorIs(t, tm.SetTaskProgress(added.ID, 101), ErrValidation)
+
+	// С чек-листом готовность считается по выполненным пунктам
+	assert.NoError(t, tm.SetTaskChecklist(added.ID, []ChecklistItem{{Text: "Коробки", Done: true}, {Text: "Грузчики"}, {Text: "Ключи"}}))
+	assert.Equal(t, 33, tm.GetTask(added.ID).ProgressPercent())
+	assert.NoError(t, tm.SetTaskChecklist(added.ID, nil))
+	assert.Equal(t, 40, tm.GetTask(added.ID).ProgressPercent())
+}
+
+func TestSetTaskEstimate(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	added := mustAddTask(t, tm, "Написать отчет", "", 2, time.Now())
+	assert.NoError(t, tm.SetTaskEstimate(added.ID, 90))
+	assert.Equal(t, 90, tm.GetTask(added.ID).EstimatedMinutes)
+	assert.ErrorIs(t, tm.SetTaskEstimate(added.ID, -5), ErrValidation)
+	assert.ErrorIs(t, tm.SetTaskEstimate(999, 10), ErrNotFound)
+}
+
+func TestSearchTasks(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	tm.AddTask("Important Meeting", "Discuss quarterly results", 3, time.Now())
+	tm.AddTask("Buy Groceries", "Milk, bread, eggs", 2, time.Now())
+	tm.AddTask("Call Mom", "Wish her happy birthday", 1, time.Now())
+
+	// Поиск по заголовку
+	results := tm.SearchTasks("Meeting")
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, "Important Meeting", results[0].Title)
+
+	// Поиск по описанию
+	results = tm.SearchTasks("birthday")
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, "Call Mom", results[0].Title)
+
+	// Поиск без результатов
+	results = tm.SearchTasks("nothing")
+	assert.Equal(t, 0, len(results))
+
+	// Поиск с несколькими результатами
+	results = tm.SearchTasks("e")
+	assert.True(t, len(results) >= 2) // Должно быть минимум 2 совпадения
+}
+
+func TestFilterTasksByStatus(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	// Создаем задачи с разными статусами
+	tm.AddTask("Task 1", "Description", 1, time.Now())
+	t2 := mustAddTask(t, tm, "Task 2", "Description", 2, time.Now())
+	tm.AddTask("Task 3", "Description", 3, time.Now())
+
+	// Помечаем вторую задачу как выполненную
+	tm.ToggleTaskCompletion(t2.ID)
+
+	// Фильтруем активные задачи (не выполненные)
+	activeTasks := tm.FilterTasksByStatus(false)
+	assert.Equal(t, 2, len(activeTasks), "Должно быть 2 активные задачи")
+
+	// Проверяем, что все активные задачи не помечены как выполненные
+	for _, task := range activeTasks {
+		assert.False(t, task.Completed, "Активная задача не должна быть помечена как выполненная")
+	}
+
+	// Фильтруем выполненные задачи
+	completedTasks := tm.FilterTasksByStatus(true)
+	assert.Equal(t, 1, len(completedTasks), "Должна быть 1 выполненная задача")
+
+	// Проверяем, что выполненная задача помечена как выполненная
+	assert.True(t, completedTasks[0].Completed, "Задача должна быть помечена как выполненная")
+	assert.Equal(t, t2.ID, completedTasks[0].ID, "ID выполненной задачи должен соответствовать")
+}
+
+func TestSortTasksByPriority(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	tm.AddTask("Low priority", "Description", 1, time.Now())
+	tm.AddTask("High priority", "Description", 3, time.Now())
+	tm.AddTask("Medium priority", "Description", 2, time.Now())
+
+	// Сортируем по приоритету
+	sortedTasks := tm.SortTasksByPriority()
+
+	// Проверяем порядок: сначала высокий приоритет, затем средний, затем низкий
+	assert.Equal(t, 3, sortedTasks[0].Priority)
+	assert.Equal(t, "High priority", sortedTasks[0].Title)
+
+	assert.Equal(t, 2, sortedTasks[1].Priority)
+	assert.Equal(t, "Medium priority", sortedTasks[1].Title)
+
+	assert.Equal(t, 1, sortedTasks[2].Priority)
+	assert.Equal(t, "Low priority", sortedTasks[2].Title)
+}
+
+func TestSaveAndLoadFromFile(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	// Создаем несколько задач
+	tm.AddTask("Task 1", "Description 1", 1, time.Now())
+	tm.AddTask("Task 2", "Description 2", 2, time.Now().Add(24*time.Hour))
+	tm.AddTask("Task 3", "Description 3", 3, time.Now().Add(48*time.Hour))
+
+	// Сохраняем в файл
+	err := tm.SaveToFile()
+	assert.NoError(t, err)
+
+	// Проверяем, что файл создан
+	_, err = os.Stat(testFilename)
+	assert.False(t, os.IsNotExist(err))
+
+	// Создаем новый менеджер и загружаем данные
+	tm2 := NewTaskManager(storage.NewFile(testFilename))
+	err = tm2.LoadFromFile()
+	assert.NoError(t, err)
+
+	// Проверяем загруженные данные
+	assert.Equal(t, 3, len(tm2.tasks))
+	assert.Equal(t, 4, tm2.nextID) // nextID должен быть равен последнему ID + 1
+
+	// Проверяем содержимое задач
+	assert.Equal(t, "Task 1", tm2.tasks[0].Title)
+	assert.Equal(t, "Task 2", tm2.tasks[1].Title)
+	assert.Equal(t, "Task 3", tm2.tasks[2].Title)
+
+	// Проверяем приоритеты и другие поля
+	assert.Equal(t, 1, tm2.tasks[0].Priority)
+	assert.Equal(t, 2, tm2.tasks[1].Priority)
+	assert.Equal(t, 3, tm2.tasks[2].Priority)
+}
+
+func TestDirtyTracking(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	// Новый менеджер не содержит изменений
+	assert.False(t, tm.IsDirty())
+
+	task := mustAddTask(t, tm, "Task", "Description", 1, time.Now())
+	assert.True(t, tm.IsDirty())
+
+	// Сохранение сбрасывает флаг
+	assert.NoError(t, tm.SaveToFile())
+	assert.False(t, tm.IsDirty())
+
+	// Каждое изменение снова помечает менеджер
+	tm.ToggleTaskCompletion(task.ID)
+	assert.True(t, tm.IsDirty())
+	assert.NoError(t, tm.SaveToFile())
+
+	tm.UpdateTask(task.ID, "Updated", "Description", 2, time.Now(), false)
+	assert.True(t, tm.IsDirty())
+	assert.NoError(t, tm.SaveToFile())
+
+	tm.DeleteTask(task.ID)
+	assert.True(t, tm.IsDirty())
+
+	// Загрузка из файла тоже сбрасывает флаг
+	assert.NoError(t, tm.LoadFromFile())
+	assert.False(t, tm.IsDirty())
+
+	// Неудачные операции не помечают менеджер
+	tm.DeleteTask(999)
+	tm.ToggleTaskCompletion(999)
+	assert.False(t, tm.IsDirty())
+}
+
+func TestUpdatedAt(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	task := mustAddTask(t, tm, "Task", "Description", 1, time.Now())
+	assert.Equal(t, task.CreatedAt, task.UpdatedAt)
+
+	// Каждое изменение обновляет время изменения
+	for _, change := range []func() error{
+		func() error { return tm.ToggleTaskCompletion(task.ID) },
+		func() error { return tm.UpdateTask(task.ID, "Updated", "Description", 2, time.Now(), false) },
+		func() error { return tm.SetTaskTags(task.ID, []string{"work"}) },
+	} {
+		before := task.UpdatedAt
+		time.Sleep(time.Millisecond)
+		assert.NoError(t, change())
+		assert.True(t, task.UpdatedAt.After(before))
+	}
+
+	// Неудачное изменение время не трогает
+	before := task.UpdatedAt
+	assert.Error(t, tm.UpdateTask(task.ID, "", "Description", 2, time.Now(), false))
+	assert.Equal(t, before, task.UpdatedAt)
+}
+
+func TestCheckUnchanged(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	task := mustAddTask(t, tm, "Task", "Description", 1, time.Now())
+	assert.NoError(t, tm.SaveToFile())
+
+	// Клиент прочитал задачу из файла
+	client := NewTaskManager(storage.NewFile(testFilename))
+	assert.NoError(t, client.LoadFromFile())
+	seen := client.GetTask(task.ID).UpdatedAt
+	assert.NoError(t, tm.CheckUnchanged(task.ID, seen))
+
+	// Задачу изменили после чтения
+	time.Sleep(time.Millisecond)
+	assert.NoError(t, tm.ToggleTaskCompletion(task.ID))
+	assert.ErrorIs(t, tm.CheckUnchanged(task.ID, seen), ErrConflict)
+	assert.ErrorIs(t, tm.CheckUnchanged(999, seen), ErrNotFound)
+}
+
+func TestSaveDetectsExternalChanges(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	tm.AddTask("Task 1", "Description", 1, time.Now())
+	assert.NoError(t, tm.SaveToFile())
+
+	// Собственное сохранение не считается внешним изменением
+	changed, err := tm.FileChanged()
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	// Другая программа перезаписывает файл
+	assert.NoError(t, os.WriteFile(testFilename, []byte("[]"), 0644))
+	changed, err = tm.FileChanged()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	// Обычное сохранение не затирает чужие изменения
+	tm.AddTask("Task 2", "Description", 1, time.Now())
+	assert.ErrorIs(t, tm.SaveToFile(), ErrFileChanged)
+
+	// Принудительное сохранение перезаписывает файл
+	assert.NoError(t, tm.ForceSaveToFile())
+	changed, err = tm.FileChanged()
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	// После загрузки с диска сохранение снова разрешено
+	assert.NoError(t, os.WriteFile(testFilename, []byte("[]"), 0644))
+	assert.NoError(t, tm.LoadFromFile())
+	assert.NoError(t, tm.SaveToFile())
+}
+
+func TestSetTaskArchived(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	added := mustAddTask(t, tm, "Task 1", "", 1, time.Now())
+	assert.NoError(t, tm.SetTaskArchived(added.ID, true))
+	assert.True(t, tm.GetTask(added.ID).Archived)
+	assert.NoError(t, tm.SetTaskArchived(added.ID, false))
+	assert.False(t, tm.GetTask(added.ID).Archived)
+	assert.ErrorIs(t, tm.SetTaskArchived(999, true), ErrNotFound)
+}
+
+func TestSetTaskChecklist(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	added := mustAddTask(t, tm, "Task 1", "", 1, time.Now())
+	items := []ChecklistItem{{Text: "Купить"}, {Text: "Принести", Done: true}}
+	assert.NoError(t, tm.SetTaskChecklist(added.ID, items))
+	items[0].Done = true // Менеджер хранит свою копию
+	assert.Equal(t, []ChecklistItem{{Text: "Купить"}, {Text: "Принести", Done: true}}, tm.GetTask(added.ID).Checklist)
+	assert.ErrorIs(t, tm.SetTaskChecklist(999, nil), ErrNotFound)
+
+	// Копия задачи не делит чек-лист с оригиналом
+	clone := tm.GetTask(added.ID).Clone()
+	clone.Checklist[0].Text = "Другое"
+	assert.Equal(t, "Купить", tm.GetTask(added.ID).Checklist[0].Text)
+}
+
+func TestAddTaskTime(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	added := mustAddTask(t, tm, "Task 1", "", 1, time.Now())
+	assert.NoError(t, tm.AddTaskTime(added.ID, 90*time.Second))
+	assert.NoError(t, tm.AddTaskTime(added.ID, time.Minute))
+	assert.NoError(t, tm.AddTaskTime(added.ID, -time.Hour))
+	assert.Equal(t, 150*time.Second, tm.GetTask(added.ID).TimeSpent)
+	assert.ErrorIs(t, tm.AddTaskTime(999, time.Second), ErrNotFound)
+}
+
+func TestExportToCSV(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	// Создаем задачи для экспорта
+	t1 := mustAddTask(t, tm, "Task 1", "Description 1", 1, time.Now())
+	t2 := mustAddTask(t, tm, "Task 2", "Description 2", 3, time.Now().Add(24*time.Hour))
+	tm.SetTaskAssignee(t2.ID, "Маша")
+
+	// Помечаем первую задачу как выполненную
+	tm.ToggleTaskCompletion(t1.ID)
+
+	// Экспортируем в CSV
+	err := tm.ExportToCSV(testCSVFilename, CSVOptions{})
+	assert.NoError(t, err, "Экспорт в CSV не должен вызывать ошибок")
+
+	// Проверяем, что файл создан
+	_, err = os.Stat(testCSVFilename)
+	assert.False(t, os.IsNotExist(err), "Файл CSV должен быть создан")
+
+	// Считываем содержимое файла
+	file, err := os.Open(testCSVFilename)
+	assert.NoError(t, err, "Не удалось открыть файл CSV")
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	records, err := reader.ReadAll()
+	assert.NoError(t, err, "Не удалось прочитать содержимое CSV файла")
+
+	// Проверяем количество записей (заголовок + 2 задачи)
+	assert.Equal(t, 3, len(records), "В CSV файле должно быть 3 записи (заголовок + 2 задачи)")
+
+	// Проверяем заголовки
+	assert.Equal(t, []string{"ID", "Title", "Description", "Priority", "Due Date", "Created At", "Completed", "UUID", "Updated At", "Assignee", "Archived", "Context", "Location"}, records[0])
+
+	// Проверяем первую задачу
+	assert.Contains(t, records[1][1], "Task 1", "Первая задача должна содержать 'Task 1'")
+	assert.Contains(t, records[1][3], "Low", "Первая задача должна иметь приоритет 'Low'")
+	assert.Contains(t, records[1][6], "Yes", "Первая задача должна быть помечена как выполненная (Yes)")
+	assert.Equal(t, t1.UUID, records[1][7], "Первая задача должна содержать UUID")
+
+	// Проверяем вторую задачу
+	assert.Contains(t, records[2][1], "Task 2", "Вторая задача должна содержать 'Task 2'")
+	assert.Contains(t, records[2][3], "High", "Вторая задача должна иметь приоритет 'High'")
+	assert.Contains(t, records[2][6], "No", "Вторая задача должна быть помечена как невыполненная (No)")
+	assert.Equal(t, "", records[1][9])
+	assert.Equal(t, "Маша", records[2][9])
+	assert.Equal(t, "No", records[1][10])
+}
+
+func TestSortTasksByDueDate(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	// Создаем задачи с разными сроками выполнения
+	now := time.Now()
+	t1 := mustAddTask(t, tm, "Task 1", "Due tomorrow", 2, now.Add(24*time.Hour))
+	t2 := mustAddTask(t, tm, "Task 2", "Due today", 3, now) // Сегодня
+	t3 := mustAddTask(t, tm, "Task 3", "Due in a week", 1, now.Add(7*24*time.Hour))
+	someday := mustAddTask(t, tm, "Someday", "No due date", 2, time.Time{})
+
+	// Сортируем по сроку выполнения
+	sortedTasks := tm.SortTasksByDueDate()
+
+	// Проверяем порядок: сначала сегодня, потом завтра, потом через неделю
+	assert.Equal(t, t2.ID, sortedTasks[0].ID)      // Сегодня
+	assert.Equal(t, t1.ID, sortedTasks[1].ID)      // Завтра
+	assert.Equal(t, t3.ID, sortedTasks[2].ID)      // Через неделю
+	assert.Equal(t, someday.ID, sortedTasks[3].ID) // Без срока - в конце
+
+	// Проверяем, что даты в правильном порядке
+	assert.True(t, sortedTasks[0].DueDate.Before(sortedTasks[1].DueDate))
+	assert.True(t, sortedTasks[1].DueDate.Before(sortedTasks[2].DueDate))
+}
+
+func TestStorageObserver(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	var ops []StorageOp
+	var errs []error
+	tm.SetStorageObserver(func(op StorageOp, duration time.Duration, err error) {
+		assert.GreaterOrEqual(t, duration, time.Duration(0))
+		ops = append(ops, op)
+		errs = append(errs, err)
+	})
+
+	mustAddTask(t, tm, "Task", "Description", 1, time.Now())
+	assert.NoError(t, tm.SaveToFile())
+	assert.NoError(t, tm.LoadFromFile())
+
+	os.WriteFile(testFilename, []byte("not json"), 0644)
+	assert.Error(t, tm.LoadFromFile())
+
+	assert.Equal(t, []StorageOp{StorageSave, StorageLoad, StorageLoad}, ops)
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+	assert.Error(t, errs[2])
+}
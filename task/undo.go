@@ -0,0 +1,59 @@
+package task
+
+import (
+	"fmt"
+	"slices"
+)
+
+// DeletedTask - удаленная задача и то, что нужно, чтобы вернуть ее на место
+type DeletedTask struct {
+	Task *Task
+
+	index      int      // место в списке задач
+	dependents []string // задачи, которые от нее зависели
+}
+
+// DeleteTaskForUndo удаляет задачу, как DeleteTask, и возвращает ее для RestoreTask
+func (tm *TaskManager) DeleteTaskForUndo(id int) (*DeletedTask, error) {
+	index := slices.IndexFunc(tm.tasks, func(t *Task) bool { return t.ID == id })
+	if index < 0 {
+		return nil, notFoundError(id)
+	}
+	deleted := &DeletedTask{Task: tm.tasks[index].Clone(), index: index}
+	for _, other := range tm.tasks {
+		if slices.Contains(other.DependsOn, deleted.Task.UUID) {
+			deleted.dependents = append(deleted.dependents, other.UUID)
+		}
+	}
+	return deleted, tm.DeleteTask(id)
+}
+
+// RestoreTask возвращает удаленную задачу с прежним ID, если он свободен, а также ее связи
+// и зависимости других задач от нее
+func (tm *TaskManager) RestoreTask(deleted *DeletedTask) error {
+	if tm.GetTaskByUUID(deleted.Task.UUID) != nil {
+		return fmt.Errorf("task %q already exists", deleted.Task.Title)
+	}
+	task := deleted.Task.Clone()
+	if tm.GetTask(task.ID) != nil || task.ID <= 0 {
+		task.ID = tm.nextID
+	}
+	tm.nextID = max(tm.nextID, task.ID+1)
+	tm.tasks = slices.Insert(tm.tasks, min(deleted.index, len(tm.tasks)), task)
+	tm.dirty = true
+	tm.emit(EventAdded, task)
+
+	for _, uuid := range task.Links {
+		if other := tm.GetTaskByUUID(uuid); other != nil {
+			tm.link(task, other)
+		}
+	}
+	for _, uuid := range deleted.dependents {
+		if other := tm.GetTaskByUUID(uuid); other != nil && !slices.Contains(other.DependsOn, task.UUID) {
+			other.DependsOn = append(slices.Clone(other.DependsOn), task.UUID)
+			tm.touch(other)
+			tm.emit(EventUpdated, other)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestoreTask(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	plan := mustAddTask(t, tm, "Составить план", "", 2, time.Now())
+	budget := mustAddTask(t, tm, "Посчитать бюджет", "", 2, time.Now())
+	trip := mustAddTask(t, tm, "Купить билеты", "", 2, time.Now())
+	require.NoError(t, tm.LinkTasks(budget.ID, plan.ID))
+	require.NoError(t, tm.SetTaskDependencies(trip.ID, []int{budget.ID}))
+
+	deleted, err := tm.DeleteTaskForUndo(budget.ID)
+	require.NoError(t, err)
+	assert.Nil(t, tm.GetTask(budget.ID))
+	assert.Empty(t, tm.LinkedTasks(plan.ID))
+	assert.Empty(t, tm.Dependencies(trip.ID))
+
+	require.NoError(t, tm.RestoreTask(deleted))
+	restored := tm.GetTask(budget.ID)
+	require.NotNil(t, restored)
+	assert.Equal(t, budget.UUID, restored.UUID)
+	assert.Equal(t, []*Task{plan, restored, trip}, tm.Tasks())
+	assert.Equal(t, []*Task{restored}, tm.LinkedTasks(plan.ID))
+	assert.Equal(t, []*Task{restored}, tm.Dependencies(trip.ID))
+
+	// Задачу нельзя вернуть дважды
+	assert.Error(t, tm.RestoreTask(deleted))
+	_, err = tm.DeleteTaskForUndo(999)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
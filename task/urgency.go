@@ -0,0 +1,52 @@
+package task
+
+import (
+	"slices"
+	"strings"
+)
+
+// Urgency задает, как настойчиво напоминать о задаче
+type Urgency string
+
+const (
+	UrgencyDefault Urgency = ""       // как в настройках для приоритета задачи
+	UrgencySilent  Urgency = "silent" // только в окне приложения, без системного уведомления
+	UrgencyNormal  Urgency = "normal" // системное уведомление без звука
+	UrgencyLoud    Urgency = "loud"   // системное уведомление со звуком
+)
+
+// Urgencies - все виды напоминаний в порядке показа
+var Urgencies = []Urgency{UrgencyDefault, UrgencySilent, UrgencyNormal, UrgencyLoud}
+
+// Text возвращает название вида напоминания для интерфейса
+func (u Urgency) Text() string {
+	switch u {
+	case UrgencySilent:
+		return "тихо, только в окне"
+	case UrgencyNormal:
+		return "уведомление"
+	case UrgencyLoud:
+		return "уведомление со звуком"
+	}
+	return "по приоритету"
+}
+
+// SetTaskAlert задает, как напоминать о задаче: вид напоминания и файл звука.
+// Пустые значения возвращают настройки по умолчанию
+func (tm *TaskManager) SetTaskAlert(id int, urgency Urgency, sound string) error {
+	task := tm.GetTask(id)
+	if task == nil {
+		return notFoundError(id)
+	}
+	if !slices.Contains(Urgencies, urgency) {
+		return &ValidationError{Field: "urgency", Message: "must be silent, normal, loud or empty"}
+	}
+	sound = strings.TrimSpace(sound)
+	if task.Urgency == urgency && task.Sound == sound {
+		return nil
+	}
+	task.Urgency, task.Sound = urgency, sound
+	tm.touch(task)
+	tm.emit(EventUpdated, task)
+	return nil
+}
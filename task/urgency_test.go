@@ -0,0 +1,29 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetTaskAlert(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	call := mustAddTask(t, tm, "Позвонить", "", 3, time.Now())
+	assert.Equal(t, "по приоритету", call.Urgency.Text())
+
+	require.NoError(t, tm.SetTaskAlert(call.ID, UrgencyLoud, " /tmp/bell.wav "))
+	assert.Equal(t, UrgencyLoud, call.Urgency)
+	assert.Equal(t, "/tmp/bell.wav", call.Sound)
+
+	assert.ErrorIs(t, tm.SetTaskAlert(call.ID, "shout", ""), ErrValidation)
+	assert.Equal(t, UrgencyLoud, call.Urgency)
+	assert.ErrorIs(t, tm.SetTaskAlert(999, UrgencySilent, ""), ErrNotFound)
+
+	require.NoError(t, tm.SetTaskAlert(call.ID, UrgencyDefault, ""))
+	assert.Empty(t, call.Urgency)
+	assert.Empty(t, call.Sound)
+}
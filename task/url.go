@@ -0,0 +1,48 @@
+package task
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// urlPattern находит адреса http(s) в описании задачи
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"]+`)
+
+// ValidateURL проверяет, что адрес задачи - абсолютная ссылка http или https
+func ValidateURL(link string) error {
+	u, err := url.Parse(link)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &ValidationError{Field: "url", Message: "must be an http or https link"}
+	}
+	return nil
+}
+
+// SetTaskURL задает ссылку задачи; пустая строка снимает ссылку
+func (tm *TaskManager) SetTaskURL(id int, link string) error {
+	task := tm.GetTask(id)
+	if task == nil {
+		return notFoundError(id)
+	}
+	link = strings.TrimSpace(link)
+	if link != "" {
+		if err := ValidateURL(link); err != nil {
+			return err
+		}
+	}
+
+	task.URL = link
+	tm.touch(task)
+	tm.emit(EventUpdated, task)
+	return nil
+}
+
+// LinkURL возвращает ссылку задачи: из поля URL, а если оно пустое - первую
+// ссылку из описания. Пустая строка - ссылки нет
+func (t *Task) LinkURL() string {
+	if t.URL != "" {
+		return t.URL
+	}
+	// Точка или скобка в конце обычно завершают предложение, а не ссылку
+	return strings.TrimRight(urlPattern.FindString(t.Description), ".,;:!?)")
+}
@@ -0,0 +1,27 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetTaskURL(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	added := mustAddTask(t, tm, "Прочитать статью", "", 2, time.Now())
+	assert.NoError(t, tm.SetTaskURL(added.ID, " https://go.dev/blog "))
+	assert.Equal(t, "https://go.dev/blog", tm.GetTask(added.ID).URL)
+	assert.ErrorIs(t, tm.SetTaskURL(added.ID, "go.dev"), ErrValidation)
+	assert.ErrorIs(t, tm.SetTaskURL(added.ID, "javascript:alert(1)"), ErrValidation)
+	assert.NoError(t, tm.SetTaskURL(added.ID, ""))
+	assert.ErrorIs(t, tm.SetTaskURL(999, ""), ErrNotFound)
+}
+
+func TestLinkURL(t *testing.T) {
+	assert.Equal(t, "https://example.com/a", (&Task{URL: "https://example.com/a", Description: "http://other"}).LinkURL())
+	assert.Equal(t, "https://go.dev/doc?x=1", (&Task{Description: "Изучить (https://go.dev/doc?x=1)."}).LinkURL())
+	assert.Empty(t, (&Task{Description: "Без ссылок"}).LinkURL())
+}
@@ -0,0 +1,18 @@
+package task
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// newUUID возвращает случайный UUID версии 4. В отличие от числового ID
+// он не совпадает у задач, созданных на разных компьютерах
+func newUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err) // crypto/rand не возвращает ошибок на поддерживаемых платформах
+	}
+	b[6] = b[6]&0x0f | 0x40 // Версия 4
+	b[8] = b[8]&0x3f | 0x80 // Вариант RFC 4122
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
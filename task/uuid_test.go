@@ -0,0 +1,41 @@
+package task
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/storage"
+)
+
+func TestNewUUID(t *testing.T) {
+	pattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		uuid := newUUID()
+		assert.Regexp(t, pattern, uuid)
+		assert.False(t, seen[uuid], "UUID не должны повторяться")
+		seen[uuid] = true
+	}
+}
+
+func TestTaskUUID(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	t1 := mustAddTask(t, tm, "Task 1", "Description", 1, time.Now())
+	t2 := mustAddTask(t, tm, "Task 2", "Description", 1, time.Now())
+	assert.NotEmpty(t, t1.UUID)
+	assert.NotEqual(t, t1.UUID, t2.UUID)
+	assert.Equal(t, t2, tm.GetTaskByUUID(t2.UUID))
+	assert.Nil(t, tm.GetTaskByUUID("missing"))
+
+	// UUID сохраняется в файле
+	assert.NoError(t, tm.SaveToFile())
+	loaded := NewTaskManager(storage.NewFile(testFilename))
+	assert.NoError(t, loaded.LoadFromFile())
+	assert.Equal(t, t1.UUID, loaded.GetTask(t1.ID).UUID)
+}
@@ -0,0 +1,69 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Допустимые значения полей задачи
+const (
+	minPriority    = 1
+	maxPriority    = 3
+	maxTitleLength = 200
+)
+
+var (
+	// ErrNotFound возвращается, если задачи с указанным ID нет
+	ErrNotFound = errors.New("task not found")
+	// ErrValidation возвращается, если данные задачи некорректны
+	ErrValidation = errors.New("invalid task")
+	// ErrConflict возвращается, если задачу изменили после того, как ее прочитал клиент
+	ErrConflict = errors.New("task was modified by someone else")
+)
+
+// ValidationError описывает некорректное поле задачи
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// Unwrap позволяет проверять ошибку через errors.Is(err, ErrValidation)
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
+
+// notFoundError возвращает ошибку для отсутствующей задачи
+func notFoundError(id int) error {
+	return fmt.Errorf("%w: id %d", ErrNotFound, id)
+}
+
+// SetRequireDueAfterCreated включает проверку, что срок не раньше дня создания задачи
+func (tm *TaskManager) SetRequireDueAfterCreated(require bool) {
+	tm.requireDueAfterCreated = require
+}
+
+// validateTask проверяет поля задачи перед добавлением или изменением
+func (tm *TaskManager) validateTask(title string, priority int, dueDate, createdAt time.Time) error {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return &ValidationError{Field: "title", Message: "must not be empty"}
+	}
+	if len([]rune(title)) > maxTitleLength {
+		return &ValidationError{Field: "title", Message: fmt.Sprintf("must be at most %d characters", maxTitleLength)}
+	}
+	if priority < minPriority || priority > maxPriority {
+		return &ValidationError{Field: "priority", Message: fmt.Sprintf("must be between %d and %d", minPriority, maxPriority)}
+	}
+	// Срок необязателен: задачи без срока попадают в «Когда-нибудь».
+	// Сравниваем по дням: задача со сроком "сегодня" допустима
+	if tm.requireDueAfterCreated && !dueDate.IsZero() && dueDate.Format(DueDateLayout) < tm.dueZone.Today(createdAt) {
+		return &ValidationError{Field: "due date", Message: "must not be before the creation date"}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package task
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddTaskValidation(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	cases := []struct {
+		name     string
+		title    string
+		priority int
+		dueDate  time.Time
+		field    string
+	}{
+		{"empty title", "", 2, time.Now(), "title"},
+		{"blank title", "   ", 2, time.Now(), "title"},
+		{"long title", strings.Repeat("я", maxTitleLength+1), 2, time.Now(), "title"},
+		{"priority too low", "Task", 0, time.Now(), "priority"},
+		{"priority too high", "Task", 4, time.Now(), "priority"},
+	}
+	for _, c := range cases {
+		task, err := tm.AddTask(c.title, "Description", c.priority, c.dueDate)
+		assert.Nil(t, task, c.name)
+		assert.ErrorIs(t, err, ErrValidation, c.name)
+
+		var validationErr *ValidationError
+		if assert.True(t, errors.As(err, &validationErr), c.name) {
+			assert.Equal(t, c.field, validationErr.Field, c.name)
+		}
+	}
+
+	// Некорректные задачи не добавляются и не помечают менеджер
+	assert.Equal(t, 0, len(tm.tasks))
+	assert.Equal(t, 1, tm.nextID)
+	assert.False(t, tm.IsDirty())
+
+	// Пробелы по краям названия отбрасываются
+	task := mustAddTask(t, tm, "  Task  ", "Description", 2, time.Now())
+	assert.Equal(t, "Task", task.Title)
+
+	// Срок необязателен
+	task = mustAddTask(t, tm, "Someday", "Description", 2, time.Time{})
+	assert.True(t, task.DueDate.IsZero())
+}
+
+func TestUpdateTaskValidation(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	task := mustAddTask(t, tm, "Task", "Description", 2, time.Now())
+	assert.NoError(t, tm.SaveToFile())
+
+	err := tm.UpdateTask(task.ID, "", "Description", 2, time.Now(), false)
+	assert.ErrorIs(t, err, ErrValidation)
+
+	// Задача не изменилась
+	assert.Equal(t, "Task", tm.GetTask(task.ID).Title)
+	assert.False(t, tm.IsDirty())
+}
+
+func TestRequireDueAfterCreated(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	yesterday := time.Now().Add(-24 * time.Hour)
+
+	// По умолчанию просроченный срок допустим
+	task := mustAddTask(t, tm, "Task", "Description", 2, yesterday)
+
+	tm.SetRequireDueAfterCreated(true)
+	_, err := tm.AddTask("Task", "Description", 2, yesterday)
+	assert.ErrorIs(t, err, ErrValidation)
+
+	// Срок "сегодня" допустим
+	mustAddTask(t, tm, "Task", "Description", 2, time.Now())
+
+	// При изменении срок сравнивается с днем создания задачи
+	err = tm.UpdateTask(task.ID, "Task", "Description", 2, task.CreatedAt.Add(-48*time.Hour), false)
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.NoError(t, tm.UpdateTask(task.ID, "Task", "Description", 2, task.CreatedAt, false))
+
+	// Задача без срока не бывает раньше дня создания
+	assert.NoError(t, tm.UpdateTask(task.ID, "Task", "Description", 2, time.Time{}, false))
+}
@@ -0,0 +1,48 @@
+package task
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// Ожидание - задача передана другому человеку, и дальше она двигается только после его ответа.
+// Если такая задача долго не меняется, пора напомнить человеку о ней
+
+// IsWaiting сообщает, что невыполненная задача ждет ответа или действия другого человека
+func (t *Task) IsWaiting() bool {
+	return t.WaitingOn != "" && !t.Completed
+}
+
+// NeedsNudge сообщает, что задача ждет и не менялась after или дольше к моменту now
+func (t *Task) NeedsNudge(now time.Time, after time.Duration) bool {
+	return t.IsWaiting() && !t.Archived && now.Sub(t.UpdatedAt) >= after
+}
+
+// SetTaskWaitingOn отмечает, что задача ждет человека who; пустая строка снимает ожидание
+func (tm *TaskManager) SetTaskWaitingOn(id int, who string) error {
+	task := tm.GetTask(id)
+	if task == nil {
+		return notFoundError(id)
+	}
+	who = strings.TrimSpace(who)
+	if task.WaitingOn == who {
+		return nil
+	}
+	task.WaitingOn = who
+	tm.touch(task)
+	tm.emit(EventUpdated, task)
+	return nil
+}
+
+// WaitingContacts возвращает по алфавиту людей, от которых ждут ответа по невыполненным задачам
+func (tm *TaskManager) WaitingContacts() []string {
+	var contacts []string
+	for _, task := range tm.tasks {
+		if task.IsWaiting() && !task.Archived && !slices.Contains(contacts, task.WaitingOn) {
+			contacts = append(contacts, task.WaitingOn)
+		}
+	}
+	slices.Sort(contacts)
+	return contacts
+}
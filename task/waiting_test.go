@@ -0,0 +1,45 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitingOn(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	contract := mustAddTask(t, tm, "Договор", "", 2, time.Now())
+	invoice := mustAddTask(t, tm, "Счет", "", 2, time.Now())
+	assert.False(t, contract.IsWaiting())
+	assert.Empty(t, tm.WaitingContacts())
+
+	require.NoError(t, tm.SetTaskWaitingOn(contract.ID, "  Юрист "))
+	require.NoError(t, tm.SetTaskWaitingOn(invoice.ID, "Бухгалтерия"))
+	assert.True(t, contract.IsWaiting())
+	assert.Equal(t, "Юрист", contract.WaitingOn)
+	assert.Equal(t, []string{"Бухгалтерия", "Юрист"}, tm.WaitingContacts())
+
+	// Выполненная задача больше не ждет
+	require.NoError(t, tm.ToggleTaskCompletion(invoice.ID))
+	assert.False(t, invoice.IsWaiting())
+	assert.Equal(t, []string{"Юрист"}, tm.WaitingContacts())
+
+	require.NoError(t, tm.SetTaskWaitingOn(contract.ID, ""))
+	assert.False(t, contract.IsWaiting())
+	assert.ErrorIs(t, tm.SetTaskWaitingOn(999, "Юрист"), ErrNotFound)
+}
+
+func TestNeedsNudge(t *testing.T) {
+	updated := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+	waiting := &Task{WaitingOn: "Юрист", UpdatedAt: updated}
+	after := 3 * 24 * time.Hour
+
+	assert.False(t, waiting.NeedsNudge(updated.Add(after-time.Minute), after))
+	assert.True(t, waiting.NeedsNudge(updated.Add(after), after))
+	assert.False(t, (&Task{UpdatedAt: updated}).NeedsNudge(updated.Add(after), after))
+	assert.False(t, (&Task{WaitingOn: "Юрист", UpdatedAt: updated, Archived: true}).NeedsNudge(updated.Add(after), after))
+}
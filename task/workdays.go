@@ -0,0 +1,29 @@
+package task
+
+import "time"
+
+// maxDaysOff - сколько нерабочих дней подряд пропускается самое большее, чтобы календарь,
+// в котором нерабочие все дни, не зациклил перенос
+const maxDaysOff = 60
+
+// SetDaysOff задает нерабочие дни, которые пропускают повторение задач и перенос срока.
+// nil - пропускать нечего
+func (tm *TaskManager) SetDaysOff(dayOff func(day time.Time) bool) {
+	tm.dayOff = dayOff
+}
+
+// NextWorkday возвращает day, а если это нерабочий день - ближайший рабочий после него
+func (tm *TaskManager) NextWorkday(day time.Time) time.Time {
+	return tm.skipDaysOff(day, 1)
+}
+
+// skipDaysOff сдвигает day на dir дней, пока он нерабочий
+func (tm *TaskManager) skipDaysOff(day time.Time, dir int) time.Time {
+	if tm.dayOff == nil {
+		return day
+	}
+	for i := 0; i < maxDaysOff && tm.dayOff(day); i++ {
+		day = day.AddDate(0, 0, dir)
+	}
+	return day
+}
@@ -0,0 +1,37 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecurrenceSkipsDaysOff(t *testing.T) {
+	defer teardownTestManager()
+	tm := setupTestManager()
+
+	today, _ := time.Parse("2006-01-02", time.Now().Format("2006-01-02"))
+	tomorrow := today.AddDate(0, 0, 1)
+	// Завтра нерабочий день
+	tm.SetDaysOff(func(day time.Time) bool { return day.Format("2006-01-02") == tomorrow.Format("2006-01-02") })
+	assert.Equal(t, today, tm.NextWorkday(today))
+	assert.Equal(t, today.AddDate(0, 0, 2), tm.NextWorkday(tomorrow))
+
+	added := mustAddTask(t, tm, "Зарядка", "", 2, today)
+	assert.NoError(t, tm.SetTaskRecurrence(added.ID, RecurDaily))
+	assert.NoError(t, tm.ToggleTaskCompletion(added.ID))
+	assert.Equal(t, today.AddDate(0, 0, 2), tm.GetTask(added.ID).DueDate)
+
+	// Снятая отметка возвращает срок на рабочий день до нерабочего
+	assert.NoError(t, tm.ToggleTaskCompletion(added.ID))
+	assert.Equal(t, today, tm.GetTask(added.ID).DueDate)
+
+	// Без нерабочих дней срок не сдвигается
+	tm.SetDaysOff(nil)
+	assert.Equal(t, tomorrow, tm.NextWorkday(tomorrow))
+
+	// Календарь, где нерабочие все дни, не зацикливает перенос
+	tm.SetDaysOff(func(time.Time) bool { return true })
+	assert.Equal(t, today.AddDate(0, 0, maxDaysOff), tm.NextWorkday(today))
+}
@@ -0,0 +1,128 @@
+package tasksync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"taskmanager/storage"
+	"taskmanager/task"
+)
+
+// requestTimeout - сколько ждать ответа сервера синхронизации
+const requestTimeout = time.Minute
+
+// Client обращается к серверу синхронизации
+type Client struct {
+	URL  string // адрес сервера, например https://sync.example.com:8443
+	Key  string // ключ API, выданный сервером
+	HTTP *http.Client
+	// Keys - ключи сквозного шифрования; nil - задачи передаются открытым текстом
+	Keys *storage.Keyring
+}
+
+// NewClient создает клиента сервера url с ключом key
+func NewClient(url, key string) *Client {
+	return &Client{
+		URL:  strings.TrimRight(url, "/"),
+		Key:  key,
+		HTTP: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Exchange отправляет изменения устройства и получает изменения других устройств.
+// Не обращается к менеджеру задач, поэтому его можно вызывать из отдельной горутины
+func (c *Client) Exchange(ctx context.Context, req Request) (Response, error) {
+	if c.Keys != nil {
+		sealed, err := sealChanges(req.Changes, c.Keys)
+		if err != nil {
+			return Response{}, err
+		}
+		req.Changes = sealed
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return Response{}, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL+"/v1/sync", bytes.NewReader(body))
+	if err != nil {
+		return Response{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.Key != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.Key)
+	}
+
+	httpResp, err := c.HTTP.Do(httpReq)
+	if err != nil {
+		return Response{}, err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(httpResp.Body, 1024))
+		return Response{}, fmt.Errorf("sync server: %s: %s", httpResp.Status, strings.TrimSpace(string(message)))
+	}
+
+	var resp Response
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return Response{}, fmt.Errorf("sync server: invalid response: %w", err)
+	}
+	if err := openRecords(resp.Changes, c.Keys); err != nil {
+		return Response{}, err
+	}
+	if err := openRecords(resp.Conflicts, c.Keys); err != nil {
+		return Response{}, err
+	}
+	return resp, nil
+}
+
+// sealChanges возвращает копию изменений, в которой задачи зашифрованы текущим ключом
+func sealChanges(changes []Change, keys *storage.Keyring) ([]Change, error) {
+	sealed := make([]Change, len(changes))
+	for i, change := range changes {
+		if change.Task != nil {
+			data, err := json.Marshal(change.Task)
+			if err != nil {
+				return nil, err
+			}
+			if change.Sealed, err = keys.Seal(data); err != nil {
+				return nil, err
+			}
+			change.Task = nil
+		}
+		sealed[i] = change
+	}
+	return sealed, nil
+}
+
+// openRecords расшифровывает задачи в версиях сервера. Задачи, отправленные без шифрования, остаются как есть
+func openRecords(records []Record, keys *storage.Keyring) error {
+	for i := range records {
+		if len(records[i].Sealed) == 0 {
+			continue
+		}
+		if keys == nil {
+			return storage.ErrUnknownKey
+		}
+		data, err := keys.Open(records[i].Sealed)
+		if err != nil {
+			return err
+		}
+		var t task.Task
+		if err := json.Unmarshal(data, &t); err != nil {
+			return err
+		}
+		if t.UUID != records[i].UUID {
+			return ErrInvalidChange
+		}
+		records[i].Task, records[i].Sealed = &t, nil
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package tasksync
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"sort"
+	"time"
+
+	"taskmanager/storage"
+	"taskmanager/task"
+)
+
+// State - что устройство знает о сервере: последняя полученная ревизия и время изменения
+// каждой задачи в последней синхронизированной версии. По нему находятся изменения
+// и удаления задач после прошлой синхронизации
+type State struct {
+	Server   string               `json:"server"`
+	Revision int64                `json:"revision"`
+	Known    map[string]time.Time `json:"known"`
+	KeyID    string               `json:"key_id,omitempty"` // ключ, которым зашифрованы отправленные задачи
+
+	sendKey string // ключ, которым шифруется текущая синхронизация
+}
+
+// StatePath возвращает путь к файлу состояния синхронизации рядом с файлом задач
+func StatePath(tasksFile string) string {
+	return tasksFile + ".sync"
+}
+
+// LoadState читает состояние синхронизации. Если файла нет, возвращает пустое состояние
+func LoadState(path string) (*State, error) {
+	state := &State{Known: make(map[string]time.Time)}
+	data, err := storage.NewFile(path).Read()
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	if state.Known == nil {
+		state.Known = make(map[string]time.Time)
+	}
+	return state, nil
+}
+
+// Save записывает состояние синхронизации. Его нужно сохранять только после файла задач:
+// иначе задачи, которых нет в файле, при следующей синхронизации сочтутся удаленными
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return storage.NewFile(path).Write(data)
+}
+
+// UseServer начинает синхронизацию с начала, если сменился сервер
+func (s *State) UseServer(url string) {
+	if s.Server == url {
+		return
+	}
+	s.Server = url
+	s.Revision = 0
+	s.Known = make(map[string]time.Time)
+}
+
+// UseKey задает ключ сквозного шифрования текущей синхронизации. Если задачи отправлялись
+// открытым текстом или под другим ключом, Prepare отправит заново и неизмененные задачи
+func (s *State) UseKey(id string) {
+	s.sendKey = id
+}
+
+// Result - итог синхронизации
+type Result struct {
+	Sent     int // изменений устройства принято сервером
+	Received int // изменений других устройств применено
+	Restored int // задач, удаленных здесь, но измененных на другом устройстве, восстановлено
+	// Conflicts - задачи, измененные и здесь, и на другом устройстве: Remote - версия сервера.
+	// Версия, которую пользователь оставит, отправится на сервер при следующей синхронизации
+	Conflicts []task.MergeConflict
+}
+
+// Prepare собирает изменения задач после прошлой синхронизации.
+// Вызывается в потоке менеджера задач; now - время удаления для надгробий
+func Prepare(tm *task.TaskManager, state *State, now time.Time) Request {
+	req := Request{Since: state.Revision}
+	rekey := state.sendKey != "" && state.sendKey != state.KeyID
+
+	present := make(map[string]bool)
+	for _, t := range tm.Tasks() {
+		present[t.UUID] = true
+		base, known := state.Known[t.UUID]
+		unchanged := known && base.Equal(t.UpdatedAt)
+		if unchanged && !rekey {
+			continue
+		}
+		req.Changes = append(req.Changes, Change{
+			Record: Record{UUID: t.UUID, UpdatedAt: t.UpdatedAt, Task: t.Clone()},
+			Base:   base,
+			Rekey:  unchanged && state.KeyID != "",
+		})
+	}
+	for uuid, base := range state.Known {
+		if !present[uuid] {
+			req.Changes = append(req.Changes, Change{
+				Record: Record{UUID: uuid, UpdatedAt: now, Deleted: true},
+				Base:   base,
+			})
+		}
+	}
+
+	sort.Slice(req.Changes, func(i, j int) bool {
+		return req.Changes[i].UUID < req.Changes[j].UUID
+	})
+	return req
+}
+
+// Apply применяет ответ сервера на запрос req к задачам и состоянию синхронизации.
+// Вызывается в потоке менеджера задач
+func Apply(tm *task.TaskManager, state *State, req Request, resp Response) Result {
+	var result Result
+	conflicted := make(map[string]bool)
+	for _, record := range resp.Conflicts {
+		conflicted[record.UUID] = true
+	}
+
+	// Принятые сервером изменения устройства
+	for _, change := range req.Changes {
+		if conflicted[change.UUID] {
+			continue
+		}
+		if change.Deleted {
+			delete(state.Known, change.UUID)
+		} else {
+			state.Known[change.UUID] = change.UpdatedAt
+		}
+		result.Sent++
+	}
+
+	// Изменения других устройств: здесь эти задачи не менялись, иначе сервер вернул бы конфликт
+	for _, record := range resp.Changes {
+		if conflicted[record.UUID] {
+			continue
+		}
+		if record.Deleted {
+			tm.DeleteTaskByUUID(record.UUID) // Задачи может уже не быть
+			delete(state.Known, record.UUID)
+		} else {
+			tm.ApplyRemote(record.Task)
+			state.Known[record.UUID] = record.UpdatedAt
+		}
+		result.Received++
+	}
+
+	for _, record := range resp.Conflicts {
+		// Теперь устройство видело версию сервера, и следующее изменение будет принято
+		state.Known[record.UUID] = record.UpdatedAt
+
+		local := tm.GetTaskByUUID(record.UUID)
+		if local == nil {
+			// Удалена здесь, но изменена на другом устройстве - изменение важнее удаления
+			tm.ApplyRemote(record.Task)
+			result.Restored++
+			continue
+		}
+		if conflict := task.NewMergeConflict(local, record.Task); len(conflict.Fields) > 0 {
+			result.Conflicts = append(result.Conflicts, conflict)
+		}
+	}
+
+	state.Revision = resp.Revision
+	if len(resp.Conflicts) == 0 {
+		// Задачи с конфликтами еще не отправлены под новым ключом - отправим в следующий раз
+		state.KeyID = state.sendKey
+	}
+	return result
+}
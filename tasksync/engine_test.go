@@ -0,0 +1,219 @@
+package tasksync
+
+import (
+	"context"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/apiauth"
+	"taskmanager/storage"
+	"taskmanager/task"
+)
+
+// device - устройство с собственным файлом задач и состоянием синхронизации
+type device struct {
+	tm     *task.TaskManager
+	state  *State
+	client *Client
+}
+
+func newDevice(t *testing.T, url, key string) *device {
+	return &device{
+		tm:     task.NewTaskManager(storage.NewFile(filepath.Join(t.TempDir(), "tasks.json"))),
+		state:  &State{Known: make(map[string]time.Time)},
+		client: NewClient(url, key),
+	}
+}
+
+// sync выполняет синхронизацию так же, как приложение
+func (d *device) sync(t *testing.T) Result {
+	t.Helper()
+	if d.client.Keys != nil {
+		d.state.UseKey(d.client.Keys.CurrentID())
+	}
+	req := Prepare(d.tm, d.state, time.Now())
+	resp, err := d.client.Exchange(context.Background(), req)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return Apply(d.tm, d.state, req, resp)
+}
+
+func mustAddTask(t *testing.T, tm *task.TaskManager, title string) *task.Task {
+	t.Helper()
+	added, err := tm.AddTask(title, "", 2, time.Now())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return added
+}
+
+// newTestServer запускает сервер синхронизации с одним ключом записи
+func newTestServer(t *testing.T) (url, secret string) {
+	key, secret := apiauth.NewKey("test", apiauth.ScopeWrite)
+	server, err := NewServer(storage.NewFile(filepath.Join(t.TempDir(), "sync.json")), apiauth.New([]apiauth.Key{key}))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	ts := httptest.NewServer(server.Handler())
+	t.Cleanup(ts.Close)
+	return ts.URL, secret
+}
+
+func TestSyncTwoDevices(t *testing.T) {
+	url, secret := newTestServer(t)
+	laptop, phone := newDevice(t, url, secret), newDevice(t, url, secret)
+
+	milk := mustAddTask(t, laptop.tm, "Купить молоко")
+	bread := mustAddTask(t, laptop.tm, "Купить хлеб")
+	assert.Equal(t, 2, laptop.sync(t).Sent)
+
+	result := phone.sync(t)
+	assert.Equal(t, 2, result.Received)
+	assert.Equal(t, "Купить молоко", phone.tm.GetTaskByUUID(milk.UUID).Title)
+
+	// Изменение и удаление на телефоне доходят до ноутбука
+	phoneMilk := phone.tm.GetTaskByUUID(milk.UUID)
+	assert.NoError(t, phone.tm.ToggleTaskCompletion(phoneMilk.ID))
+	assert.NoError(t, phone.tm.DeleteTaskByUUID(bread.UUID))
+	assert.Equal(t, 2, phone.sync(t).Sent)
+
+	result = laptop.sync(t)
+	assert.Equal(t, 2, result.Received)
+	assert.True(t, laptop.tm.GetTaskByUUID(milk.UUID).Completed)
+	assert.Nil(t, laptop.tm.GetTaskByUUID(bread.UUID))
+
+	// Без изменений ничего не передается
+	assert.Equal(t, Result{}, laptop.sync(t))
+	assert.Equal(t, Result{}, phone.sync(t))
+}
+
+func TestSyncConflicts(t *testing.T) {
+	url, secret := newTestServer(t)
+	laptop, phone := newDevice(t, url, secret), newDevice(t, url, secret)
+
+	report := mustAddTask(t, laptop.tm, "Отчет")
+	trash := mustAddTask(t, laptop.tm, "Вынести мусор")
+	laptop.sync(t)
+	phone.sync(t)
+
+	// Обе версии изменены: первая принята, вторая - конфликт
+	laptopReport := laptop.tm.GetTaskByUUID(report.UUID)
+	phoneReport := phone.tm.GetTaskByUUID(report.UUID)
+	assert.NoError(t, laptop.tm.UpdateTask(laptopReport.ID, "Отчет (ноутбук)", "", 2, laptopReport.DueDate, false))
+	assert.NoError(t, phone.tm.UpdateTask(phoneReport.ID, "Отчет (телефон)", "", 3, phoneReport.DueDate, false))
+
+	// Удаление на ноутбуке и изменение на телефоне: изменение важнее
+	assert.NoError(t, laptop.tm.DeleteTaskByUUID(trash.UUID))
+	phoneTrash := phone.tm.GetTaskByUUID(trash.UUID)
+	assert.NoError(t, phone.tm.SetTaskAssignee(phoneTrash.ID, "Петя"))
+
+	laptop.sync(t)
+	result := phone.sync(t)
+	if assert.Len(t, result.Conflicts, 1) {
+		conflict := result.Conflicts[0]
+		assert.Equal(t, "Отчет (телефон)", conflict.Local.Title)
+		assert.Equal(t, "Отчет (ноутбук)", conflict.Remote.Title)
+		assert.Equal(t, []string{"title", "priority"}, conflict.Fields)
+	}
+
+	// Пользователь оставил версию телефона - она отправляется при следующей синхронизации
+	assert.Equal(t, 1, phone.sync(t).Sent)
+	result = laptop.sync(t)
+	assert.Equal(t, 2, result.Received)
+	assert.Equal(t, "Отчет (телефон)", laptop.tm.GetTaskByUUID(report.UUID).Title)
+	if restored := laptop.tm.GetTaskByUUID(trash.UUID); assert.NotNil(t, restored) {
+		assert.Equal(t, "Петя", restored.Assignee)
+	}
+}
+
+func TestSyncDeletedLocallyChangedRemotely(t *testing.T) {
+	url, secret := newTestServer(t)
+	laptop, phone := newDevice(t, url, secret), newDevice(t, url, secret)
+
+	plants := mustAddTask(t, laptop.tm, "Полить цветы")
+	laptop.sync(t)
+	phone.sync(t)
+
+	phonePlants := phone.tm.GetTaskByUUID(plants.UUID)
+	assert.NoError(t, phone.tm.ToggleTaskCompletion(phonePlants.ID))
+	phone.sync(t)
+
+	// Ноутбук удалил задачу, не зная об изменении на телефоне - она восстанавливается
+	assert.NoError(t, laptop.tm.DeleteTaskByUUID(plants.UUID))
+	result := laptop.sync(t)
+	assert.Equal(t, 1, result.Restored)
+	if restored := laptop.tm.GetTaskByUUID(plants.UUID); assert.NotNil(t, restored) {
+		assert.True(t, restored.Completed)
+	}
+	assert.Equal(t, Result{}, laptop.sync(t))
+}
+
+func TestSyncState(t *testing.T) {
+	path := StatePath(filepath.Join(t.TempDir(), "tasks.json"))
+
+	state, err := LoadState(path)
+	assert.NoError(t, err)
+	state.UseServer("https://sync.example.com")
+	state.Revision = 7
+	state.Known["uuid"] = time.Date(2025, 6, 30, 10, 0, 0, 0, time.UTC)
+	assert.NoError(t, state.Save(path))
+
+	loaded, err := LoadState(path)
+	assert.NoError(t, err)
+	assert.Equal(t, state, loaded)
+
+	// Другой сервер - синхронизация с начала
+	loaded.UseServer("https://other.example.com")
+	assert.Zero(t, loaded.Revision)
+	assert.Empty(t, loaded.Known)
+}
+
+func TestSyncEndToEnd(t *testing.T) {
+	key, secret := apiauth.NewKey("test", apiauth.ScopeWrite)
+	dataFile := filepath.Join(t.TempDir(), "sync.json")
+	server, err := NewServer(storage.NewFile(dataFile), apiauth.New([]apiauth.Key{key}))
+	assert.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	laptop, phone := newDevice(t, ts.URL, secret), newDevice(t, ts.URL, secret)
+	laptop.client.Keys = &storage.Keyring{}
+	assert.NoError(t, laptop.client.Keys.Generate())
+	phone.client.Keys = &storage.Keyring{}
+	assert.NoError(t, phone.client.Keys.Import(laptop.client.Keys.RecoveryPhrase()))
+
+	milk := mustAddTask(t, laptop.tm, "Купить молоко")
+	assert.Equal(t, 1, laptop.sync(t).Sent)
+	assert.Equal(t, 1, phone.sync(t).Received)
+	assert.Equal(t, "Купить молоко", phone.tm.GetTaskByUUID(milk.UUID).Title)
+
+	// Сервер хранит только шифротекст
+	data, err := storage.NewFile(dataFile).Read()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "Купить")
+	assert.NotContains(t, string(data), `"task"`)
+
+	// После смены ключа неизмененные задачи перешифровываются
+	oldPhrase := laptop.client.Keys.RecoveryPhrase()
+	assert.NoError(t, laptop.client.Keys.Generate())
+	assert.Equal(t, 1, laptop.sync(t).Sent)
+	assert.Equal(t, Result{}, laptop.sync(t))
+
+	// Телефон без нового ключа не может прочитать задачи, пока не введет новую фразу
+	_, err = phone.client.Exchange(context.Background(), Prepare(phone.tm, phone.state, time.Now()))
+	assert.ErrorIs(t, err, storage.ErrUnknownKey)
+
+	laptopMilk := laptop.tm.GetTaskByUUID(milk.UUID)
+	assert.NoError(t, laptop.tm.UpdateTask(laptopMilk.ID, "Купить кефир", "", 2, laptopMilk.DueDate, false))
+	assert.Equal(t, 1, laptop.sync(t).Sent)
+
+	assert.NoError(t, phone.client.Keys.Import(laptop.client.Keys.RecoveryPhrase()))
+	assert.NotEqual(t, oldPhrase, phone.client.Keys.RecoveryPhrase())
+	phone.sync(t)
+	assert.Equal(t, "Купить кефир", phone.tm.GetTaskByUUID(milk.UUID).Title)
+}
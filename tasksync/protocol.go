@@ -0,0 +1,66 @@
+// Package tasksync синхронизирует задачи между устройствами через собственный сервер:
+// протокол обмена изменениями, сервер и клиентскую часть приложения.
+//
+// Устройство отправляет задачи, измененные после прошлой синхронизации, вместе с временем
+// изменения версии, которую оно видело последней (Base), и получает изменения других устройств.
+// Сервер принимает изменение, только если его версия задачи совпадает с Base;
+// иначе задачу изменили и здесь, и на другом устройстве, и конфликт разрешает пользователь.
+//
+// Со сквозным шифрованием задача передается зашифрованной ключом устройства (Sealed),
+// и сервер видит только UUID, время изменения и признак удаления
+package tasksync
+
+import (
+	"errors"
+	"time"
+
+	"taskmanager/task"
+)
+
+// Record - версия задачи на сервере. Удаленная задача остается надгробием (Deleted),
+// чтобы удаление дошло до остальных устройств
+type Record struct {
+	UUID      string     `json:"uuid"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Deleted   bool       `json:"deleted,omitempty"`
+	Task      *task.Task `json:"task,omitempty"`
+	Sealed    []byte     `json:"sealed,omitempty"`   // задача, зашифрованная storage.Keyring; тогда Task пусто
+	Revision  int64      `json:"revision,omitempty"` // номер изменения на сервере
+}
+
+// Change - изменение задачи на устройстве
+type Change struct {
+	Record
+	// Base - время изменения версии задачи, которую устройство получило с сервера последней;
+	// нулевое, если устройство еще не синхронизировало эту задачу
+	Base time.Time `json:"base"`
+	// Rekey - задача не менялась и отправлена заново, зашифрованная новым ключом после его смены
+	Rekey bool `json:"rekey,omitempty"`
+}
+
+// Request - запрос синхронизации
+type Request struct {
+	Since   int64    `json:"since"` // последняя ревизия, полученная устройством
+	Changes []Change `json:"changes"`
+}
+
+// Response - ответ сервера на запрос синхронизации
+type Response struct {
+	Revision  int64    `json:"revision"`  // текущая ревизия, с нее начнется следующая синхронизация
+	Changes   []Record `json:"changes"`   // изменения других устройств после Since
+	Conflicts []Record `json:"conflicts"` // серверные версии задач, изменения которых не приняты
+}
+
+// ErrInvalidChange возвращается сервером для изменения без UUID или без задачи
+var ErrInvalidChange = errors.New("invalid sync change")
+
+// validate проверяет, что изменение можно сохранить
+func (c Change) validate() error {
+	if c.UUID == "" {
+		return ErrInvalidChange
+	}
+	if !c.Deleted && len(c.Sealed) == 0 && (c.Task == nil || c.Task.UUID != c.UUID) {
+		return ErrInvalidChange
+	}
+	return nil
+}
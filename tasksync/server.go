@@ -0,0 +1,201 @@
+package tasksync
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"net/http"
+	"sort"
+	"sync"
+
+	"taskmanager/apiauth"
+	"taskmanager/storage"
+)
+
+// maxRequestSize - наибольший размер запроса синхронизации
+const maxRequestSize = 32 << 20
+
+// serverData - содержимое файла данных сервера
+type serverData struct {
+	Revision int64    `json:"revision"`
+	Records  []Record `json:"records"`
+}
+
+// Server хранит последние версии задач всех устройств и обменивается с ними изменениями
+type Server struct {
+	mu       sync.Mutex
+	store    storage.Storage
+	auth     *apiauth.Authenticator
+	revision int64
+	records  map[string]Record
+}
+
+// NewServer создает сервер с данными в store. Устройства подключаются с ключами auth с правами записи
+func NewServer(store storage.Storage, auth *apiauth.Authenticator) (*Server, error) {
+	s := &Server{store: store, auth: auth, records: make(map[string]Record)}
+
+	raw, err := store.Read()
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var data serverData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+	s.revision = data.Revision
+	for _, record := range data.Records {
+		s.records[record.UUID] = record
+	}
+	return s, nil
+}
+
+// Sync принимает изменения устройства и возвращает изменения остальных устройств
+func (s *Server) Sync(req Request) (Response, error) {
+	for _, change := range req.Changes {
+		if err := change.validate(); err != nil {
+			return Response{}, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Изменения собираются в копии и попадают в память, только когда записаны в файл:
+	// иначе после ошибки записи сервер раздавал бы устройствам версии, которых нет в файле
+	records := maps.Clone(s.records)
+	revision := s.revision
+	var resp Response
+	accepted := make(map[string]bool)
+	changed := false
+	for _, change := range req.Changes {
+		current, exists := records[change.UUID]
+		switch {
+		case exists && change.Rekey && (current.Deleted || !current.UpdatedAt.Equal(change.UpdatedAt)):
+			// Задачу уже изменили или удалили на другом устройстве: перешифровывать нечего,
+			// устройство получит новую версию вместе с остальными изменениями
+			continue
+		case exists && !current.Deleted && !change.Deleted && current.UpdatedAt.Equal(change.UpdatedAt) &&
+			len(change.Sealed) > 0 && (len(current.Sealed) == 0 || change.Rekey) && !bytes.Equal(current.Sealed, change.Sealed):
+			// Та же версия, зашифрованная впервые или новым ключом: заменяем содержимое,
+			// чтобы на сервере не оставались открытый текст и данные под старым ключом
+			revision++
+			record := change.Record
+			record.Revision = revision
+			records[change.UUID] = record
+			changed = true
+		case exists && current.Deleted && change.Deleted,
+			exists && !current.Deleted && !change.Deleted && current.UpdatedAt.Equal(change.UpdatedAt):
+			// У устройства та же версия: например, оба устройства начали с одного файла
+			// или удалили одну и ту же задачу
+		case !exists, current.UpdatedAt.Equal(change.Base), current.Deleted:
+			// Изменение важнее удаления: задачу, удаленную на другом устройстве, восстанавливаем
+			revision++
+			record := change.Record
+			record.Revision = revision
+			if record.Deleted {
+				record.Task = nil
+			}
+			records[change.UUID] = record
+			changed = true
+		default:
+			resp.Conflicts = append(resp.Conflicts, current)
+			continue
+		}
+		accepted[change.UUID] = true
+	}
+
+	for _, record := range records {
+		if record.Revision > req.Since && !accepted[record.UUID] && !containsRecord(resp.Conflicts, record.UUID) {
+			resp.Changes = append(resp.Changes, record)
+		}
+	}
+	sort.Slice(resp.Changes, func(i, j int) bool {
+		return resp.Changes[i].Revision < resp.Changes[j].Revision
+	})
+	resp.Revision = revision
+
+	if changed {
+		if err := s.save(revision, records); err != nil {
+			return Response{}, err
+		}
+		s.revision, s.records = revision, records
+	}
+	return resp, nil
+}
+
+// save записывает данные сервера
+func (s *Server) save(revision int64, records map[string]Record) error {
+	data := serverData{Revision: revision}
+	for _, record := range records {
+		data.Records = append(data.Records, record)
+	}
+	sort.Slice(data.Records, func(i, j int) bool {
+		return data.Records[i].Revision < data.Records[j].Revision
+	})
+
+	raw, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	return s.store.Write(raw)
+}
+
+// containsRecord проверяет, есть ли в списке версия задачи uuid
+func containsRecord(records []Record, uuid string) bool {
+	for _, record := range records {
+		if record.UUID == uuid {
+			return true
+		}
+	}
+	return false
+}
+
+// Handler возвращает HTTP-обработчик сервера: POST /v1/sync и GET /healthz
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok\n"))
+	})
+	mux.HandleFunc("POST /v1/sync", s.handleSync)
+	return mux
+}
+
+func (s *Server) handleSync(w http.ResponseWriter, r *http.Request) {
+	err := s.auth.Check(apiauth.BearerToken(r.Header.Get("Authorization")), r.RemoteAddr, apiauth.ScopeWrite)
+	switch {
+	case errors.Is(err, apiauth.ErrUnauthenticated):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	var req Request
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&req); err != nil {
+		http.Error(w, "invalid sync request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := s.Sync(req)
+	switch {
+	case errors.Is(err, ErrInvalidChange):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case err != nil:
+		slog.Error("sync failed", "client", r.RemoteAddr, "err", err)
+		http.Error(w, "failed to store changes", http.StatusInternalServerError)
+		return
+	}
+	slog.Info("sync", "client", r.RemoteAddr, "sent", len(req.Changes), "received", len(resp.Changes),
+		"conflicts", len(resp.Conflicts), "revision", resp.Revision)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,101 @@
+package tasksync
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/apiauth"
+	"taskmanager/storage"
+	"taskmanager/task"
+)
+
+func TestServerPersists(t *testing.T) {
+	store := storage.NewFile(filepath.Join(t.TempDir(), "sync.json"))
+	server, err := NewServer(store, apiauth.New(nil))
+	assert.NoError(t, err)
+
+	now := time.Now()
+	resp, err := server.Sync(Request{Changes: []Change{
+		{Record: Record{UUID: "a", UpdatedAt: now, Task: &task.Task{UUID: "a", Title: "A", UpdatedAt: now}}},
+		{Record: Record{UUID: "b", UpdatedAt: now, Deleted: true}},
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), resp.Revision)
+	assert.Empty(t, resp.Changes)
+
+	// После перезапуска сервер отдает те же данные
+	server, err = NewServer(store, apiauth.New(nil))
+	assert.NoError(t, err)
+	resp, err = server.Sync(Request{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), resp.Revision)
+	if assert.Len(t, resp.Changes, 2) {
+		assert.Equal(t, "A", resp.Changes[0].Task.Title)
+		assert.True(t, resp.Changes[1].Deleted)
+	}
+
+	_, err = server.Sync(Request{Changes: []Change{{Record: Record{UUID: "c", UpdatedAt: now}}}})
+	assert.ErrorIs(t, err, ErrInvalidChange)
+}
+
+func TestServerRequiresKey(t *testing.T) {
+	url, _ := newTestServer(t)
+
+	_, err := NewClient(url, "wrong").Exchange(context.Background(), Request{})
+	assert.ErrorContains(t, err, "401")
+}
+
+// failingStore - хранилище, запись в которое можно сломать
+type failingStore struct {
+	storage.Storage
+	fail bool
+}
+
+func (f *failingStore) Write(data []byte) error {
+	if f.fail {
+		return errors.New("disk full")
+	}
+	return f.Storage.Write(data)
+}
+
+func TestServerKeepsStateWhenSaveFails(t *testing.T) {
+	store := &failingStore{Storage: storage.NewFile(filepath.Join(t.TempDir(), "sync.json"))}
+	server, err := NewServer(store, apiauth.New(nil))
+	assert.NoError(t, err)
+
+	now := time.Now()
+	_, err = server.Sync(Request{Changes: []Change{
+		{Record: Record{UUID: "a", UpdatedAt: now, Task: &task.Task{UUID: "a", Title: "A", UpdatedAt: now}}},
+	}})
+	assert.NoError(t, err)
+
+	// Незаписанное изменение не попадает ни в память, ни к другим устройствам
+	store.fail = true
+	later := now.Add(time.Minute)
+	_, err = server.Sync(Request{Changes: []Change{
+		{Record: Record{UUID: "a", UpdatedAt: later, Task: &task.Task{UUID: "a", Title: "A2", UpdatedAt: later}}, Base: now},
+		{Record: Record{UUID: "b", UpdatedAt: later, Task: &task.Task{UUID: "b", Title: "B", UpdatedAt: later}}},
+	}})
+	assert.Error(t, err)
+
+	resp, err := server.Sync(Request{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), resp.Revision)
+	if assert.Len(t, resp.Changes, 1) {
+		assert.Equal(t, "A", resp.Changes[0].Task.Title)
+	}
+
+	// Устройство повторяет синхронизацию, когда запись снова работает
+	store.fail = false
+	resp, err = server.Sync(Request{Since: 1, Changes: []Change{
+		{Record: Record{UUID: "a", UpdatedAt: later, Task: &task.Task{UUID: "a", Title: "A2", UpdatedAt: later}}, Base: now},
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), resp.Revision)
+	assert.Empty(t, resp.Conflicts)
+}
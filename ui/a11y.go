@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Fyne пока не передает подписи элементов программам чтения с экрана, поэтому интерфейс
+// держится правил, которые помогают и им, и работе с клавиатуры: у кнопок подпись словами,
+// а не символом, фокус по Tab обходит окно в порядке чтения, диалог открывается с фокусом
+// на первом поле
+
+// newReadingBorder - как container.NewBorder, но объекты идут в порядке чтения: сверху вниз
+// и слева направо. Фокус по Tab обходит объекты контейнера по порядку, а container.NewBorder
+// ставит центр первым, и Tab из панели кнопок уходил сразу в список
+func newReadingBorder(top, bottom, left, right fyne.CanvasObject, center ...fyne.CanvasObject) *fyne.Container {
+	var objects []fyne.CanvasObject
+	for _, object := range []fyne.CanvasObject{top, left} {
+		if object != nil {
+			objects = append(objects, object)
+		}
+	}
+	objects = append(objects, center...)
+	for _, object := range []fyne.CanvasObject{right, bottom} {
+		if object != nil {
+			objects = append(objects, object)
+		}
+	}
+	return container.New(layout.NewBorderLayout(top, bottom, left, right), objects...)
+}
+
+// newStepButtons создает кнопки «Назад» и «Вперед» для страниц, месяцев и недель
+func newStepButtons(back, next func()) (backButton, nextButton *widget.Button) {
+	backButton = widget.NewButtonWithIcon("Назад", theme.NavigateBackIcon(), back)
+	nextButton = widget.NewButtonWithIcon("Вперед", theme.NavigateNextIcon(), next)
+	nextButton.IconPlacement = widget.ButtonIconTrailingText
+	return backButton, nextButton
+}
+
+// newCloseButton создает неприметную кнопку «Закрыть» для уведомлений над списком
+func newCloseButton(tapped func()) *widget.Button {
+	button := widget.NewButtonWithIcon("Закрыть", theme.CancelIcon(), tapped)
+	button.Importance = widget.LowImportance
+	return button
+}
+
+// tabOutEntry - многострочное поле, из которого Tab переводит фокус к следующему полю,
+// а не вставляет табуляцию: иначе фокус застревал бы в описании задачи
+type tabOutEntry struct {
+	widget.Entry
+}
+
+func newTabOutEntry() *tabOutEntry {
+	e := &tabOutEntry{}
+	e.MultiLine = true
+	e.Wrapping = fyne.TextWrap(fyne.TextTruncateClip)
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+func (e *tabOutEntry) AcceptsTab() bool {
+	return false
+}
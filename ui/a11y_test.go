@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadingBorderFocusOrder(t *testing.T) {
+	test.NewTempApp(t)
+
+	top, left, center, right, bottom := widget.NewEntry(), widget.NewEntry(), widget.NewEntry(), widget.NewEntry(), widget.NewEntry()
+	w := test.NewWindow(newReadingBorder(top, bottom, left, right, center))
+	defer w.Close()
+
+	c := w.Canvas()
+	for _, want := range []*widget.Entry{top, left, center, right, bottom} {
+		c.FocusNext()
+		assert.Same(t, want, c.Focused())
+	}
+}
+
+func TestTabOutEntry(t *testing.T) {
+	test.NewTempApp(t)
+
+	e := newTabOutEntry()
+	assert.True(t, e.MultiLine)
+	assert.False(t, e.AcceptsTab())
+}
@@ -0,0 +1,169 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/task"
+)
+
+// Размеры карты выполнений
+const (
+	activityCell float32 = 12
+	activityGap  float32 = 2
+	activityDays         = 365 // карта охватывает последний год
+)
+
+// completedByDay раскладывает выполненные задачи по дням выполнения в поясе loc.
+// Выполнения повторяющихся задач берутся из их истории
+func completedByDay(tasks []*task.Task, loc *time.Location) map[string][]*task.Task {
+	days := make(map[string][]*task.Task)
+	for _, t := range tasks {
+		if t.Completed {
+			day := t.DoneAt().In(loc).Format(task.DueDateLayout)
+			days[day] = append(days[day], t)
+		}
+		for _, c := range t.Completions {
+			day := c.In(loc).Format(task.DueDateLayout)
+			days[day] = append(days[day], t)
+		}
+	}
+	return days
+}
+
+// activityStart возвращает понедельник, с которого начинается карта: неделя,
+// в которую попадает день год назад
+func activityStart(today time.Time) time.Time {
+	first := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location()).AddDate(0, 0, -activityDays+1)
+	return first.AddDate(0, 0, -((int(first.Weekday()) + 6) % 7))
+}
+
+// activityLevel - насыщенность клетки от 0 до 4 относительно самого продуктивного дня
+func activityLevel(count, peak int) int {
+	if count == 0 || peak == 0 {
+		return 0
+	}
+	return min((count*4+peak-1)/peak, 4)
+}
+
+// formatActivityDay описывает день карты: дату и выполненные задачи
+func formatActivityDay(day time.Time, done []*task.Task) string {
+	if len(done) == 0 {
+		return day.Format("02.01.2006") + ": ничего не выполнено"
+	}
+	titles := make([]string, len(done))
+	for i, t := range done {
+		titles[i] = t.Title
+	}
+	return fmt.Sprintf("%s: выполнено %d — %s", day.Format("02.01.2006"), len(done), strings.Join(titles, ", "))
+}
+
+// activityCellWidget - день на карте; при наведении мыши показывает, что в этот день выполнено
+type activityCellWidget struct {
+	widget.BaseWidget
+	fill    color.Color
+	onHover func(hovered bool)
+}
+
+func newActivityCell(fill color.Color, onHover func(hovered bool)) *activityCellWidget {
+	c := &activityCellWidget{fill: fill, onHover: onHover}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+func (c *activityCellWidget) CreateRenderer() fyne.WidgetRenderer {
+	rect := canvas.NewRectangle(c.fill)
+	rect.CornerRadius = 2
+	return widget.NewSimpleRenderer(rect)
+}
+
+func (c *activityCellWidget) MouseIn(*desktop.MouseEvent) {
+	c.onHover(true)
+}
+
+func (c *activityCellWidget) MouseMoved(*desktop.MouseEvent) {}
+
+func (c *activityCellWidget) MouseOut() {
+	c.onHover(false)
+}
+
+// activityFill - цвет клетки: чем больше выполнено, тем насыщеннее
+func activityFill(level int) color.Color {
+	if level == 0 {
+		return theme.Color(theme.ColorNameInputBackground)
+	}
+	r, g, b, _ := theme.Color(theme.ColorNameSuccess).RGBA()
+	return color.NRGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: uint8(255 * level / 4)}
+}
+
+// newActivityView создает карту выполненных задач за год для вкладки статистики:
+// недели - столбцы, дни недели - строки. Подпись под картой показывает день под мышью.
+// У Fyne нет всплывающих подсказок, поэтому подсказка выводится в подпись
+func newActivityView(tm *task.TaskManager) (view fyne.CanvasObject, refresh func()) {
+	layout := &fixedLayout{}
+	grid := container.New(layout)
+	total := widget.NewLabel("")
+	info := widget.NewLabel("")
+
+	refresh = func() {
+		today := tm.DueZone().Now()
+		done := completedByDay(tm.Tasks(), today.Location())
+		first := activityStart(today)
+		todayKey := today.Format(task.DueDateLayout)
+		peak, count := 0, 0
+		for day, list := range done {
+			if day >= first.Format(task.DueDateLayout) && day <= todayKey {
+				peak = max(peak, len(list))
+				count += len(list)
+			}
+		}
+		total.SetText(fmt.Sprintf("Выполнено за год: %d", count))
+		info.SetText("Наведите мышь на день, чтобы увидеть выполненные задачи")
+
+		var objects []fyne.CanvasObject
+		place := func(obj fyne.CanvasObject, x, y, width, height float32) {
+			obj.Move(fyne.NewPos(x, y))
+			obj.Resize(fyne.NewSize(width, height))
+			objects = append(objects, obj)
+		}
+		top := theme.CaptionTextSize() + 4
+		step := activityCell + activityGap
+		week := 0
+		for day := first; day.Format(task.DueDateLayout) <= todayKey; day = day.AddDate(0, 0, 1) {
+			row := (int(day.Weekday()) + 6) % 7
+			if row == 0 && !day.Equal(first) {
+				week++
+			}
+			// Подпись месяца над неделей, в которой он начинается
+			if day.Day() == 1 {
+				label := canvas.NewText(day.Format("01.06"), theme.Color(theme.ColorNamePlaceHolder))
+				label.TextSize = theme.CaptionTextSize()
+				place(label, float32(week)*step, 0, step*4, top)
+			}
+			list := done[day.Format(task.DueDateLayout)]
+			hoverDay := day
+			cell := newActivityCell(activityFill(activityLevel(len(list), peak)), func(hovered bool) {
+				if hovered {
+					info.SetText(formatActivityDay(hoverDay, list))
+				}
+			})
+			place(cell, float32(week)*step, top+float32(row)*step, activityCell, activityCell)
+		}
+		layout.size = fyne.NewSize(float32(week+1)*step, top+7*step)
+		grid.Objects = objects
+		grid.Refresh()
+	}
+	refresh()
+
+	header := widget.NewLabelWithStyle("Выполненные задачи по дням", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	return container.NewVBox(container.NewHBox(header, total), container.NewHScroll(grid), info), refresh
+}
@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+func TestCompletedByDay(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 12, 0, 0, 0, time.UTC) }
+	report := &task.Task{Title: "Отчет", Completed: true, CompletedAt: day(14)}
+	gym := &task.Task{Title: "Зарядка", Recurrence: task.RecurDaily, Completions: []time.Time{day(14), day(15)}}
+	open := &task.Task{Title: "Черновик"}
+
+	days := completedByDay([]*task.Task{report, gym, open}, time.UTC)
+	assert.Equal(t, []*task.Task{report, gym}, days["2026-10-14"])
+	assert.Equal(t, []*task.Task{gym}, days["2026-10-15"])
+	assert.Len(t, days, 2)
+
+	assert.Equal(t, "14.10.2026: выполнено 2 — Отчет, Зарядка", formatActivityDay(day(14), days["2026-10-14"]))
+	assert.Equal(t, "16.10.2026: ничего не выполнено", formatActivityDay(day(16), nil))
+}
+
+func TestActivityStart(t *testing.T) {
+	// Год назад от 16.10.2026 - 17.10.2025, пятница; карта начинается с понедельника той недели
+	start := activityStart(time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC))
+	assert.Equal(t, "2025-10-13", start.Format(task.DueDateLayout))
+	assert.Equal(t, time.Monday, start.Weekday())
+}
+
+func TestActivityLevel(t *testing.T) {
+	assert.Equal(t, 0, activityLevel(0, 8))
+	assert.Equal(t, 1, activityLevel(1, 8))
+	assert.Equal(t, 2, activityLevel(4, 8))
+	assert.Equal(t, 4, activityLevel(8, 8))
+}
+
+func TestActivityView(t *testing.T) {
+	test.NewTempApp(t)
+	tm := newTestManager(t)
+	done := mustAddTask(t, tm, "Отправить отчет", "", 2, time.Now())
+	assert.NoError(t, tm.ToggleTaskCompletion(done.ID))
+	view, refresh := newActivityView(tm)
+	refresh()
+	assert.NotNil(t, view)
+}
@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/agenda"
+	"taskmanager/task"
+)
+
+// prefAgendaSections - разделы повестки дня, выбранные пользователем
+const prefAgendaSections = "agenda.sections"
+
+// agendaSections читает выбранные разделы повестки
+func agendaSections(prefs fyne.Preferences) []agenda.Section {
+	names := prefs.StringList(prefAgendaSections)
+	if len(names) == 0 {
+		return agenda.DefaultSections
+	}
+	sections := make([]agenda.Section, len(names))
+	for i, name := range names {
+		sections[i] = agenda.Section(name)
+	}
+	return sections
+}
+
+// printAgenda открывает повестку в браузере, который сразу показывает диалог печати
+func printAgenda(a fyne.App, day agenda.Agenda) error {
+	file, err := os.CreateTemp("", "agenda-*.html")
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := day.WriteHTML(file, true); err != nil {
+		return err
+	}
+	path := filepath.ToSlash(file.Name())
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path // C:/... в Windows
+	}
+	return a.OpenURL(&url.URL{Scheme: "file", Path: path})
+}
+
+// showAgendaDialog показывает повестку дня: просроченные задачи, задачи на сегодня
+// и разделы на выбор. Повестку можно распечатать, сохранить в файл или скопировать
+func showAgendaDialog(a fyne.App, w fyne.Window, prefs fyne.Preferences, tm *task.TaskManager) {
+	titles := make([]string, len(agenda.Sections))
+	for i, section := range agenda.Sections {
+		titles[i] = section.Title()
+	}
+	selected := func(checks *widget.CheckGroup) []agenda.Section {
+		var sections []agenda.Section
+		for _, section := range agenda.Sections {
+			for _, title := range checks.Selected {
+				if title == section.Title() {
+					sections = append(sections, section)
+				}
+			}
+		}
+		return sections
+	}
+
+	var day agenda.Agenda
+	preview := widget.NewLabel("")
+	// Загрузка сегодняшнего дня по оценкам задач
+	minutes, capacity := dayWorkload(tm.Tasks(), tm.DueZone().Now().Format("2006-01-02")), workloadCapacity(prefs)
+	workload := container.NewBorder(nil, nil, widget.NewLabel("Загрузка: "+workloadText(minutes, capacity)), nil,
+		newWorkloadBar(minutes, capacity))
+	checks := widget.NewCheckGroup(titles, nil)
+	for _, section := range agendaSections(prefs) {
+		checks.Selected = append(checks.Selected, section.Title())
+	}
+	rebuild := func() {
+		sections := selected(checks)
+		names := make([]string, len(sections))
+		for i, section := range sections {
+			names[i] = string(section)
+		}
+		prefs.SetStringList(prefAgendaSections, names)
+		day = agenda.Build(tm.Tasks(), tm.DueZone().Now(), sections)
+		preview.SetText(day.Text())
+	}
+	checks.OnChanged = func([]string) { rebuild() }
+	checks.Horizontal = true
+	rebuild()
+
+	printButton := widget.NewButton("Печать…", func() {
+		if err := printAgenda(a, day); err != nil {
+			slog.Error("failed to print agenda", "err", err)
+			dialog.ShowError(err, w)
+		}
+	})
+	saveButton := widget.NewButton("Сохранить…", func() {
+		save := dialog.NewFileSave(func(file fyne.URIWriteCloser, err error) {
+			if err != nil || file == nil {
+				return
+			}
+			defer file.Close()
+			// .txt - простым текстом, иначе - страницей HTML
+			if strings.EqualFold(file.URI().Extension(), ".txt") {
+				_, err = file.Write([]byte(day.Text()))
+			} else {
+				err = day.WriteHTML(file, false)
+			}
+			if err != nil {
+				slog.Error("failed to save agenda", "file", file.URI().String(), "err", err)
+				dialog.ShowError(err, w)
+			}
+		}, w)
+		save.SetFileName("agenda-" + day.Date.Format("2006-01-02") + ".html")
+		save.Show()
+	})
+	copyButton := widget.NewButton("Копировать", func() {
+		a.Clipboard().SetContent(day.Text())
+	})
+
+	content := container.NewBorder(
+		container.NewVBox(workload, checks),
+		container.NewHBox(printButton, saveButton, copyButton),
+		nil, nil,
+		container.NewVScroll(preview),
+	)
+	d := dialog.NewCustom("Повестка дня", "Закрыть", content, w)
+	d.Resize(dialogSize(w, fyne.NewSize(560, 480)))
+	d.Show()
+}
@@ -0,0 +1,19 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/agenda"
+)
+
+func TestAgendaSections(t *testing.T) {
+	a := test.NewTempApp(t)
+	prefs := a.Preferences()
+	assert.Equal(t, agenda.DefaultSections, agendaSections(prefs))
+
+	prefs.SetStringList(prefAgendaSections, []string{"today", "done"})
+	assert.Equal(t, []agenda.Section{agenda.SectionToday, agenda.SectionDone}, agendaSections(prefs))
+}
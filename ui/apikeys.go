@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"crypto/tls"
+	"errors"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/apiauth"
+)
+
+// loadAPIKeys возвращает ключи API из настроек
+func loadAPIKeys(prefs fyne.Preferences) []apiauth.Key {
+	return apiauth.ParseKeys(prefs.StringList(prefAPIKeys))
+}
+
+// saveAPIKeys сохраняет ключи API и сразу применяет их к работающим серверам
+func saveAPIKeys(prefs fyne.Preferences, auth *apiauth.Authenticator, keys []apiauth.Key) {
+	list := make([]string, len(keys))
+	for i, key := range keys {
+		list[i] = key.String()
+	}
+	prefs.SetStringList(prefAPIKeys, list)
+	auth.SetKeys(keys)
+}
+
+// loadTLSConfig загружает сертификат серверов API из настроек; nil - TLS не настроен
+func loadTLSConfig(prefs fyne.Preferences) (*tls.Config, error) {
+	certFile, keyFile := prefs.String(prefTLSCert), prefs.String(prefTLSKey)
+	if certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+	if certFile == "" || keyFile == "" {
+		return nil, errors.New("both TLS certificate and key must be set")
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}, nil
+}
+
+// showAPIKeysDialog показывает ключи доступа к серверам API и позволяет создавать и отзывать их
+func showAPIKeysDialog(w fyne.Window, prefs fyne.Preferences, auth *apiauth.Authenticator) {
+	keys := loadAPIKeys(prefs)
+
+	hint := widget.NewLabel("Пока ключей нет, серверы API доступны только с этого компьютера")
+	hint.Wrapping = fyne.TextWrapWord
+
+	var keyList *widget.List
+	keyList = widget.NewList(
+		func() int { return len(keys) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil, widget.NewButton("Отозвать", nil), widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			row := item.(*fyne.Container)
+			scope := "чтение и запись"
+			if keys[id].Scope == apiauth.ScopeRead {
+				scope = "только чтение"
+			}
+			row.Objects[0].(*widget.Label).SetText(keys[id].Name + " (" + scope + ")")
+			row.Objects[1].(*widget.Button).OnTapped = func() {
+				keys = append(keys[:id:id], keys[id+1:]...)
+				saveAPIKeys(prefs, auth, keys)
+				keyList.Refresh()
+			}
+		},
+	)
+
+	createButton := widget.NewButton("Создать ключ", func() {
+		nameEntry := widget.NewEntry()
+		nameEntry.SetPlaceHolder("Например, телефон")
+		readOnlyCheck := widget.NewCheck("Только чтение", nil)
+
+		formItems := []*widget.FormItem{
+			{Text: "Название", Widget: nameEntry},
+			{Text: "", Widget: readOnlyCheck},
+		}
+		dialog.ShowForm("Новый ключ API", "Создать", "Отмена", formItems, func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			scope := apiauth.ScopeWrite
+			if readOnlyCheck.Checked {
+				scope = apiauth.ScopeRead
+			}
+			key, secret := apiauth.NewKey(nameEntry.Text, scope)
+			keys = append(keys, key)
+			saveAPIKeys(prefs, auth, keys)
+			keyList.Refresh()
+			showNewAPIKey(w, secret)
+		}, w)
+	})
+
+	content := container.NewBorder(hint, createButton, nil, nil, keyList)
+	d := dialog.NewCustom("Ключи API", "Закрыть", content, w)
+	d.Resize(dialogSize(w, fyne.NewSize(480, 360)))
+	d.Show()
+}
+
+// showNewAPIKey показывает только что созданный ключ: после закрытия окна узнать его будет нельзя
+func showNewAPIKey(w fyne.Window, secret string) {
+	secretEntry := widget.NewEntry()
+	secretEntry.SetText(secret)
+	copyButton := widget.NewButton("Копировать", func() {
+		fyne.CurrentApp().Clipboard().SetContent(secret)
+	})
+
+	message := widget.NewLabel("Скопируйте ключ сейчас: он больше не будет показан. " +
+		"Клиенты передают его в заголовке Authorization: Bearer <ключ>, " +
+		"календари подписываются на адрес /calendar.ics?key=<ключ>")
+	message.Wrapping = fyne.TextWrapWord
+
+	content := container.NewVBox(message, container.NewBorder(nil, nil, nil, copyButton, secretEntry))
+	d := dialog.NewCustom("Ключ создан", "Готово", content, w)
+	d.Resize(dialogSize(w, fyne.NewSize(480, 200)))
+	d.Show()
+}
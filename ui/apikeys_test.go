@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/apiauth"
+)
+
+func TestAPIKeysSaveAndLoad(t *testing.T) {
+	a := test.NewTempApp(t)
+	auth := apiauth.New(nil)
+
+	key, secret := apiauth.NewKey("phone", apiauth.ScopeRead)
+	saveAPIKeys(a.Preferences(), auth, []apiauth.Key{key})
+
+	assert.Equal(t, []apiauth.Key{key}, loadAPIKeys(a.Preferences()))
+	scope, err := auth.Authorize(secret, "192.168.1.20:5000")
+	assert.NoError(t, err)
+	assert.Equal(t, apiauth.ScopeRead, scope)
+}
+
+func TestLoadTLSConfig(t *testing.T) {
+	a := test.NewTempApp(t)
+
+	config, err := loadTLSConfig(a.Preferences())
+	assert.NoError(t, err)
+	assert.Nil(t, config)
+
+	// Сертификат без ключа - ошибка, а не работа без шифрования
+	a.Preferences().SetString(prefTLSCert, "cert.pem")
+	_, err = loadTLSConfig(a.Preferences())
+	assert.Error(t, err)
+
+	a.Preferences().SetString(prefTLSKey, "missing-key.pem")
+	_, err = loadTLSConfig(a.Preferences())
+	assert.Error(t, err)
+}
@@ -0,0 +1,1242 @@
+// Package ui - графический интерфейс менеджера задач на Fyne
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/app"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/apiauth"
+	"taskmanager/applog"
+	"taskmanager/grpcapi"
+	"taskmanager/httpapi"
+	"taskmanager/metrics"
+	"taskmanager/storage"
+	"taskmanager/task"
+)
+
+// Options - параметры запуска приложения
+type Options struct {
+	// TasksFile - файл задач основного профиля
+	TasksFile string
+	// AddTitle - название задачи, которую нужно добавить (в уже запущенном экземпляре, если он есть)
+	AddTitle string
+	// Profile - профиль; если не задан, открывается основной профиль или предлагается выбор
+	Profile string
+	// Verbose включает отладочные сообщения в журнале
+	Verbose bool
+}
+
+// Run запускает приложение с параметрами opts
+func Run(opts Options) {
+	a := app.NewWithID("com.zhumarradriga.taskmanager")
+	applyAppTheme(a)
+
+	// Журнал пишется в каталог данных приложения; без него работаем, но сообщаем в stderr
+	logFile, err := applog.Setup(a.Storage().RootURI().Path(), opts.Verbose)
+	if err != nil {
+		slog.Warn("failed to open log file", "err", err)
+	} else {
+		defer logFile.Close()
+	}
+
+	profiles := newProfileStore(a.Preferences(), opts.TasksFile)
+	open := func(profile string) bool {
+		return openProfile(a, profiles, profile, opts.AddTitle, logFile)
+	}
+	switch {
+	case opts.Profile == defaultProfileTitle:
+		if !open("") {
+			return
+		}
+	case opts.Profile != "":
+		if !profiles.Has(opts.Profile) {
+			if err := profiles.Add(opts.Profile); err != nil {
+				slog.Error("invalid profile name", "profile", opts.Profile, "err", err)
+				os.Exit(2)
+			}
+		}
+		if !open(opts.Profile) {
+			return
+		}
+	case len(profiles.Names()) > 1:
+		// Профилей несколько - пользователь выбирает, с каким работать
+		showProfilePicker(a, profiles, open)
+	default:
+		if !open("") {
+			return
+		}
+	}
+	a.Run()
+}
+
+// openProfile открывает окно профиля profile. Если профиль уже открыт в другом экземпляре,
+// передает ему команду и возвращает false. Ресурсы профиля освобождаются при закрытии окна
+func openProfile(a fyne.App, profiles *profileStore, profile, addTitle string, logFile *applog.File) bool {
+	prefs := profiles.Preferences(profile)
+	tasksFilename := profiles.TasksFile(profile)
+	profiles.SetLast(profile)
+
+	var cleanups []func()
+	cleanup := sync.OnceFunc(func() {
+		for i := len(cleanups) - 1; i >= 0; i-- {
+			cleanups[i]()
+		}
+	})
+
+	// Не даем двум экземплярам работать с одним файлом задач:
+	// второй экземпляр передает команду первому и завершается
+	var handleCommand func(cmd, arg string)
+	lock, err := acquireInstanceLock(lockPath(tasksFilename), func(cmd, arg string) {
+		fyne.Do(func() {
+			if handleCommand != nil {
+				handleCommand(cmd, arg)
+			}
+		})
+	})
+	if errors.Is(err, errInstanceRunning) {
+		cmd, arg := instanceCmdShow, ""
+		if addTitle != "" {
+			cmd, arg = instanceCmdAdd, addTitle
+		}
+		if err := sendToInstance(lockPath(tasksFilename), cmd, arg); err != nil {
+			slog.Error("failed to contact running instance", "err", err)
+		}
+		return false
+	}
+	if err == nil {
+		cleanups = append(cleanups, func() { lock.Release() })
+	} else {
+		slog.Warn("failed to lock tasks file", "file", tasksFilename, "err", err)
+	}
+
+	title := "Task Manager"
+	if profile != "" {
+		title += " - " + profile
+	}
+	// При письме справа налево окно отражает содержимое, диалоги и меню
+	rtl := loadRTL(prefs)
+	w := newDirectionalWindow(rtl, a.NewWindow(title))
+
+	// Восстанавливаем размер окна и состояние интерфейса с прошлого запуска
+	state := loadUIState(prefs)
+	w.Resize(fyne.NewSize(state.Width, state.Height))
+
+	// Файл задач лежит локально или на сервере WebDAV (Nextcloud), если он задан в настройках.
+	// Локальный файл можно хранить в YAML или TOML
+	localFile := storage.NewFile(tasksFilename)
+	var store storage.Storage = localFile
+	if codec := storageCodec(prefs); codec != (storage.JSONCodec{}) {
+		store = storage.NewEncoded(localFile, codec)
+	}
+	location := tasksFilename
+	remote := newRemoteStorage(prefs, tasksFilename)
+	keys := loadKeyring(prefs)
+	if remote != nil {
+		// На сервер файл уходит только зашифрованным ключом устройства
+		if keys.Empty() {
+			if err := keys.Generate(); err != nil {
+				slog.Error("failed to create end-to-end encryption key", "err", err)
+			}
+			saveKeyring(prefs, keys)
+		}
+		store, location = storage.NewSealed(remote, keys), remote.URL()
+	}
+
+	tm := task.NewTaskManager(store)
+	appLocker := newAppLock("", 0)
+	applySettings(prefs, tm, appLocker)
+	loadErr := tm.LoadFromFile()
+	firstRun := loadErr == nil && len(tm.Tasks()) == 0 && !prefs.Bool(prefOnboardingDone)
+	switch {
+	case loadErr == nil:
+		slog.Debug("tasks loaded", "file", location, "count", len(tm.Tasks()))
+	case errors.Is(loadErr, task.ErrPassphraseRequired):
+		slog.Debug("tasks file is encrypted, waiting for passphrase", "file", location)
+	case errors.Is(loadErr, storage.ErrUnknownKey):
+		slog.Warn("tasks file is encrypted with an unknown key, waiting for recovery phrase", "file", location)
+	default:
+		slog.Error("failed to load tasks", "file", location, "err", loadErr)
+	}
+
+	// Модель представления: задачи текущего вида с учетом поиска, фильтра и сортировки
+	model := newTaskListModel(tm, state.PageSize)
+	model.sort, model.reverse = state.Sort, state.SortReverse
+	selectedTaskID := 0
+	pageLabel := widget.NewLabel("")
+
+	// Создаем интерфейс
+	// Контекстное меню строки задачи задается ниже, когда созданы все действия
+	// Метка в строке открывает вкладку проекта; вкладки создаются ниже
+	var showTaskMenu func(row int, pos fyne.Position)
+	// Открытие задачи для редактирования задается ниже, когда созданы список и его страницы
+	var openTask func(id int)
+	var openProject func(tag string)
+	density := loadRowDensity(prefs)
+	compact := isCompactScreen()
+	undoNotices := container.NewVBox()
+	var taskListView *widget.List
+	taskListView = widget.NewList(
+		model.Len,
+		func() fyne.CanvasObject {
+			return newSwipeRow(newTaskRow(density, rtl))
+		},
+		func(row widget.ListItemID, item fyne.CanvasObject) {
+			if task := model.TaskAt(row); task != nil {
+				swipe := item.(*swipeRow)
+				taskRow := swipe.row
+				taskRow.SetTask(task, tm.DueZone().Now(), density)
+				taskRow.onMenu = func(pos fyne.Position) { showTaskMenu(row, pos) }
+				taskRow.onTag = func(tag string) { openProject(tag) }
+				id := task.ID
+				taskRow.onToggle = func() {
+					if err := tm.ToggleTaskCompletion(id); err != nil {
+						dialog.ShowError(err, w)
+					}
+				}
+				// Жесты сенсорного экрана; случайно удаленную задачу можно вернуть из уведомления
+				swipe.onComplete = taskRow.onToggle
+				swipe.onDelete = func() {
+					deleted, err := tm.DeleteTaskForUndo(id)
+					if err != nil {
+						dialog.ShowError(err, w)
+						return
+					}
+					showUndoDelete(w, undoNotices, tm, deleted)
+				}
+				swipe.onScroll = func(dy float32) {
+					taskListView.ScrollToOffset(taskListView.GetScrollOffset() - dy)
+				}
+			}
+		},
+	)
+
+	// Обработка выбора задачи
+	taskListView.OnSelected = func(row widget.ListItemID) {
+		appLocker.Touch()
+		if task := model.TaskAt(row); task != nil {
+			selectedTaskID = task.ID
+		}
+	}
+
+	// Табличный вид поверх той же модели
+	taskTableView := newTaskTable(model, visibleColumns(prefs.StringList(prefHiddenColumns)))
+	taskTableView.OnSelected = func(id widget.TableCellID) {
+		appLocker.Touch()
+		if task := model.TaskAt(id.Row); task != nil {
+			selectedTaskID = task.ID
+		}
+	}
+
+	// Перерисовываем текущую страницу и восстанавливаем выделение по ID задачи
+	renderPage := func() {
+		taskListView.Refresh()
+		taskTableView.Refresh()
+		// Страница перерисовывается и по событиям задач без участия пользователя:
+		// выделение строки программой не откладывает автоблокировку
+		appLocker.WithoutTouch(func() {
+			if row := model.RowOf(selectedTaskID); row >= 0 {
+				taskListView.Select(row)
+				taskTableView.Select(widget.TableCellID{Row: row, Col: 0})
+			} else {
+				taskListView.UnselectAll()
+				taskTableView.UnselectAll()
+				selectedTaskID = 0
+			}
+		})
+		pageLabel.SetText(fmt.Sprintf("Страница %d из %d (задач: %d)",
+			model.pager.Page()+1, model.pager.PageCount(), len(model.pager.tasks)))
+	}
+
+	// Обновляем список задач в интерфейсе
+	updateTaskList := func() {
+		model.Refresh()
+		renderPage()
+	}
+
+	// Инициализируем список и обновляем его при любом изменении задач
+	updateTaskList()
+	tm.Subscribe(func(task.Event) {
+		updateTaskList()
+	})
+
+	// Число просроченных задач видно в заголовке окна и в трее
+	badge := newOverdueBadge(a, w, title, tm)
+	badge.Update()
+	tm.Subscribe(func(task.Event) {
+		badge.Update()
+	})
+
+	// Исполнители для выбора: люди из настроек и все, кто уже назначен задачам
+	people := func() []string {
+		return assigneeChoices(prefs.StringList(prefPeople), tm.Assignees())
+	}
+	// Контексты GTD для выбора: из настроек и уже заданные задачам
+	contexts := func() []string {
+		return contextChoices(prefs, tm.Contexts())
+	}
+
+	// Проверка орфографии в названии и описании задачи
+	spelling := newSpellChecker(prefs)
+
+	// Кнопки управления. Действия кнопок и меню попадают в реестр, из которого строится палитра команд
+	actions := &actionRegistry{onRun: appLocker.Touch}
+	addButton := actions.Button("Добавить задачу", func() {
+		// Устанавливаем завтрашнюю дату как значение по умолчанию
+		showAddTaskDialog(w, tm, spelling, people(), contexts(), loadGoals(prefs), defaultDueDate(tm.DueZone()), openTask)
+	})
+
+	editSelectedTask := func() {
+		task := tm.GetTask(selectedTaskID)
+		if task != nil {
+			showEditTaskDialog(w, tm, spelling, task, people(), contexts(), loadGoals(prefs))
+		} else {
+			dialog.ShowInformation("Ошибка", "Выберите задачу для редактирования", w)
+		}
+	}
+	editButton := actions.Button("Редактировать", editSelectedTask)
+	// Диктовка: задачи создаются из вывода внешней команды, например распознавания речи
+	captureButton := actions.Button("Надиктовать", func() {
+		runCapture(w, prefs, tm)
+	})
+
+	// Место задачи открывается в OpenStreetMap
+	openSelectedMap := func() {
+		t := tm.GetTask(selectedTaskID)
+		if t == nil {
+			dialog.ShowInformation("Ошибка", "Выберите задачу, чтобы открыть ее место на карте", w)
+			return
+		}
+		if err := openTaskMap(a, t); errors.Is(err, errNoLocation) {
+			dialog.ShowInformation("Карта", "У задачи не указано место", w)
+		} else if err != nil {
+			slog.Error("failed to open task location", "id", t.ID, "err", err)
+			dialog.ShowError(err, w)
+		}
+	}
+
+	// Ссылка задачи - из поля URL или первая из описания - открывается в браузере
+	openSelectedLink := func() {
+		t := tm.GetTask(selectedTaskID)
+		if t == nil {
+			dialog.ShowInformation("Ошибка", "Выберите задачу, чтобы открыть ее ссылку", w)
+			return
+		}
+		if err := openTaskLink(a, t); errors.Is(err, errNoLink) {
+			dialog.ShowInformation("Ссылка", "У задачи нет ссылки", w)
+		} else if err != nil {
+			slog.Error("failed to open task link", "id", t.ID, "err", err)
+			dialog.ShowError(err, w)
+		}
+	}
+
+	// Задачу можно держать на виду в отдельном окне, пока просматривается список
+	// Таймеры задач останавливаются при выполнении задачи, закрытии приложения и сне компьютера
+	timers := newTimerJournal(prefs, tm)
+	taskWindows := newTaskWindows(a, prefs, tm, timers)
+	cleanups = append(cleanups, taskWindows.CloseAll)
+	windowButton := actions.Button("В отдельном окне", func() {
+		if selectedTaskID == 0 {
+			dialog.ShowInformation("Ошибка", "Выберите задачу, чтобы открыть ее в отдельном окне", w)
+			return
+		}
+		taskWindows.Open(selectedTaskID)
+	})
+	taskTableView.OnDoubleTapped = editSelectedTask
+	taskTableView.OnSortChanged = renderPage
+
+	deleteButton := actions.Button("Удалить", func() {
+		if selectedTaskID == 0 {
+			return
+		}
+		if err := tm.DeleteTask(selectedTaskID); err != nil {
+			dialog.ShowError(err, w)
+		}
+	})
+
+	toggleButton := actions.Button("Изменить статус", func() {
+		if selectedTaskID == 0 {
+			return
+		}
+		if err := tm.ToggleTaskCompletion(selectedTaskID); err != nil {
+			dialog.ShowError(err, w)
+		}
+	})
+
+	saveButton := actions.Button("Сохранить", func() {
+		saveTasks(w, tm, func() {
+			dialog.ShowInformation("Успешно", "Задачи сохранены в файл", w)
+		})
+	})
+
+	rulesEngine := newRulesEngine(a, prefs)
+	scripts := newScriptHost(a, w, prefs, tm)
+
+	// Синхронизация с собственным сервером
+	syncSession := newSyncSession(prefs, tm, tasksFilename, keys)
+	syncButton := actions.Button("Синхронизировать", func() {
+		syncSession.Run(w)
+	})
+
+	// Экспорт и интеграции - встроенные и внешние расширения
+	pluginRegistry := newPluginRegistry(prefs)
+	exportButton := actions.Button("Экспорт…", func() {
+		showExportDialog(w, prefs, tm, pluginRegistry, model.Tasks(), "")
+	})
+
+	// Кнопка для сортировки по приоритету
+	sortPriorityButton := actions.Button("Сортировка по приоритету", func() {
+		model.SetSort(task.SortByPriority)
+		renderPage()
+	})
+
+	// Кнопка для сортировки по дате выполнения
+	sortDateButton := actions.Button("Сортировка по дате", func() {
+		model.SetSort(task.SortByDueDate)
+		renderPage()
+	})
+
+	// Кнопка для показа недавно измененных задач первыми
+	sortUpdatedButton := actions.Button("Недавно измененные", func() {
+		model.SetSort(task.SortByUpdated)
+		renderPage()
+	})
+
+	// Поле для поиска
+	searchEntry := widget.NewEntry()
+	searchEntry.SetPlaceHolder("Поиск задач...")
+	searchEntry.OnChanged = func(text string) {
+		// Пустая строка поиска показывает все задачи
+		model.SetSearch(text)
+		renderPage()
+	}
+
+	// Чекбокс для фильтрации по статусу
+	filterActive := widget.NewCheck("Показать только активные", func(checked bool) {
+		model.SetOnlyActive(checked)
+		renderPage()
+	})
+
+	// Задачи из архива по умолчанию скрыты
+	showArchived := widget.NewCheck("Архив", func(checked bool) {
+		model.SetShowArchived(checked)
+		renderPage()
+	})
+
+	// Переключение между списком и таблицей
+	taskTableView.Hide()
+	viewSelect := widget.NewRadioGroup([]string{"Список", "Таблица"}, func(value string) {
+		if value == "Таблица" {
+			taskListView.Hide()
+			taskTableView.Show()
+		} else {
+			taskTableView.Hide()
+			taskListView.Show()
+		}
+	})
+	viewSelect.Horizontal = true
+	viewSelect.Required = true
+	if state.View == "Таблица" {
+		viewSelect.SetSelected("Таблица")
+	} else {
+		viewSelect.SetSelected("Список")
+	}
+
+	columnsButton := actions.Button("Колонки", func() {
+		showColumnsDialog(w, prefs, func(columns []tableColumn) {
+			taskTableView.SetColumns(columns)
+		})
+	})
+
+	// Переключение страниц
+	prevPageButton, nextPageButton := newStepButtons(func() {
+		if model.pager.PrevPage() {
+			renderPage()
+		}
+	}, func() {
+		if model.pager.NextPage() {
+			renderPage()
+		}
+	})
+	pageSizeSelect := widget.NewSelect([]string{"25", "50", "100", "500", "Все"}, func(value string) {
+		size, err := strconv.Atoi(value)
+		if err != nil {
+			size = 0 // "Все" - без разбиения на страницы
+		}
+		if size != model.pager.pageSize {
+			model.pager.SetPageSize(size)
+			renderPage()
+		}
+	})
+	if state.PageSize > 0 {
+		pageSizeSelect.SetSelected(strconv.Itoa(state.PageSize))
+	} else {
+		pageSizeSelect.SetSelected("Все")
+	}
+
+	// Фильтр по исполнителю
+	assigneeSelect := widget.NewSelect(nil, func(value string) {
+		switch value {
+		case assigneeAll:
+			model.ClearAssignee()
+		case assigneeUnassigned:
+			model.SetAssignee("")
+		default:
+			model.SetAssignee(value)
+		}
+		renderPage()
+	})
+	updateAssigneeOptions := func() {
+		assigneeSelect.SetOptions(append([]string{assigneeAll, assigneeUnassigned}, people()...))
+	}
+	updateAssigneeOptions()
+	tm.Subscribe(func(task.Event) {
+		updateAssigneeOptions()
+	})
+	assigneeSelect.SetSelected(assigneeAll)
+	if state.FilterAssignee && state.Assignee == "" {
+		assigneeSelect.SetSelected(assigneeUnassigned)
+	} else if state.FilterAssignee {
+		assigneeSelect.SetSelected(state.Assignee)
+	}
+
+	// Быстрый фильтр по контексту GTD: задачи, которые можно сделать там, где вы сейчас
+	contextSelect := widget.NewSelect(nil, func(value string) {
+		switch value {
+		case contextAll:
+			model.ClearContext()
+		case contextNone:
+			model.SetContext("")
+		default:
+			model.SetContext(value)
+		}
+		renderPage()
+	})
+	updateContextOptions := func() {
+		contextSelect.SetOptions(append([]string{contextAll, contextNone}, contexts()...))
+	}
+	updateContextOptions()
+	tm.Subscribe(func(task.Event) {
+		updateContextOptions()
+	})
+	contextSelect.SetSelected(contextAll)
+	if state.FilterContext && state.Context == "" {
+		contextSelect.SetSelected(contextNone)
+	} else if state.FilterContext {
+		contextSelect.SetSelected(state.Context)
+	}
+
+	// Фильтр по месту: поручения, которые можно сделать по пути
+	locationSelect := widget.NewSelect(nil, func(value string) {
+		switch value {
+		case locationAll:
+			model.ClearLocation()
+		case locationNone:
+			model.SetLocation("")
+		default:
+			model.SetLocation(value)
+		}
+		renderPage()
+	})
+	updateLocationOptions := func() {
+		locationSelect.SetOptions(append([]string{locationAll, locationNone}, tm.Locations()...))
+	}
+	updateLocationOptions()
+	tm.Subscribe(func(task.Event) {
+		updateLocationOptions()
+	})
+	locationSelect.SetSelected(locationAll)
+	if state.FilterLocation && state.Location == "" {
+		locationSelect.SetSelected(locationNone)
+	} else if state.FilterLocation {
+		locationSelect.SetSelected(state.Location)
+	}
+
+	// Фильтр по ожиданию: задачи, переданные другим людям
+	waitingSelect := widget.NewSelect(nil, func(value string) {
+		switch value {
+		case waitingAll:
+			model.ClearWaiting()
+		case waitingAny:
+			model.SetWaiting("")
+		default:
+			model.SetWaiting(strings.TrimPrefix(value, waitingPrefix))
+		}
+		renderPage()
+	})
+	updateWaitingOptions := func() {
+		waitingSelect.SetOptions(waitingOptions(tm.WaitingContacts()))
+	}
+	updateWaitingOptions()
+	tm.Subscribe(func(task.Event) {
+		updateWaitingOptions()
+	})
+	waitingSelect.SetSelected(waitingAll)
+	if state.FilterWaiting && state.Waiting == "" {
+		waitingSelect.SetSelected(waitingAny)
+	} else if state.FilterWaiting {
+		waitingSelect.SetSelected(waitingPrefix + state.Waiting)
+	}
+
+	// Фильтр по сроку: задачи на сегодня, просроченные, выполненные вчера
+	dueSelect := widget.NewSelect(dueFilterTitles(), func(title string) {
+		model.SetDueFilter(dueFilterByTitle(title))
+		renderPage()
+	})
+	dueSelect.SetSelected(state.DueFilter.Title())
+
+	filterActive.SetChecked(state.OnlyActive)
+	searchEntry.SetText(state.Search)
+
+	// Копирование выбранной задачи в буфер обмена: из меню «Правка», контекстного меню и по Ctrl+C
+	var copyItems []*fyne.MenuItem
+	for _, format := range clipboardFormats {
+		copyItems = append(copyItems, actions.MenuItem(format.Title, func() {
+			t := tm.GetTask(selectedTaskID)
+			if t == nil {
+				dialog.ShowInformation("Ошибка", "Выберите задачу, чтобы скопировать ее", w)
+				return
+			}
+			if err := copyTasks(a.Clipboard(), []*task.Task{t}, format); err != nil {
+				dialog.ShowError(err, w)
+			}
+		}))
+	}
+	// Вставленный текст и перетащенные файлы .txt/.md превращаются в задачи построчно
+	pasteItem := actions.MenuItem("Вставить задачи из буфера обмена", func() {
+		showQuickAddPreview(w, tm, a.Clipboard().Content())
+	})
+	captureSettingsItem := actions.MenuItem("Команда диктовки…", func() {
+		showCaptureSettings(w, prefs)
+	})
+	w.SetOnDropped(func(_ fyne.Position, uris []fyne.URI) {
+		if !appLocker.Locked() {
+			dropTaskFiles(w, tm, uris)
+		}
+	})
+
+	showTaskMenu = func(row int, pos fyne.Position) {
+		taskListView.Select(row)
+		items := []*fyne.MenuItem{
+			fyne.NewMenuItem("Редактировать", editSelectedTask),
+			fyne.NewMenuItem("В отдельном окне", windowButton.OnTapped),
+		}
+		if t := tm.GetTask(selectedTaskID); t != nil && t.LinkURL() != "" {
+			items = append(items, fyne.NewMenuItem("Открыть ссылку", openSelectedLink))
+		}
+		if t := tm.GetTask(selectedTaskID); t != nil && t.Location != "" {
+			items = append(items, fyne.NewMenuItem("Открыть на карте", openSelectedMap))
+		}
+		if t := tm.GetTask(selectedTaskID); t != nil {
+			// «Мой день» собирается вручную и не зависит от срока задачи
+			now := tm.DueZone().Now()
+			title := "Добавить в «Мой день»"
+			if t.InMyDay(now) {
+				title = "Убрать из «Моего дня»"
+			}
+			id, add := t.ID, !t.InMyDay(now)
+			items = append(items, fyne.NewMenuItem(title, func() {
+				if err := tm.SetMyDay(id, add, now); err != nil {
+					dialog.ShowError(err, w)
+				}
+			}))
+		}
+		items = append(append(items, fyne.NewMenuItemSeparator()), copyItems...)
+		widget.ShowPopUpMenuAtPosition(fyne.NewMenu("", items...), w.Canvas(), pos)
+	}
+
+	// Действия без кнопок - только для палитры команд
+	actions.Add("Вид: список", func() { viewSelect.SetSelected("Список") })
+	actions.Add("Вид: таблица", func() { viewSelect.SetSelected("Таблица") })
+	actions.Add("Фильтр: только активные", func() { filterActive.SetChecked(!filterActive.Checked) })
+	actions.Add("Фильтр: показать архив", func() { showArchived.SetChecked(!showArchived.Checked) })
+	actions.Add("Фильтр: сбросить", func() {
+		searchEntry.SetText("")
+		filterActive.SetChecked(false)
+		showArchived.SetChecked(false)
+		assigneeSelect.SetSelected(assigneeAll)
+		contextSelect.SetSelected(contextAll)
+		locationSelect.SetSelected(locationAll)
+		waitingSelect.SetSelected(waitingAll)
+		dueSelect.SetSelected(dueFilterAll.Title())
+	})
+	actions.Add("Поиск задач", func() { w.Canvas().Focus(searchEntry) })
+	actions.Add("Открыть ссылку задачи", openSelectedLink)
+	actions.Add("Открыть место задачи на карте", openSelectedMap)
+	actions.Add("Следующая страница", nextPageButton.OnTapped)
+	actions.Add("Предыдущая страница", prevPageButton.OnTapped)
+
+	// Задача из палитры команд, календаря, доски или проекта открывается для редактирования
+	openTask = func(id int) {
+		selectedTaskID = id
+		renderPage()
+		if t := tm.GetTask(id); t != nil {
+			showEditTaskDialog(w, tm, spelling, t, people(), contexts(), loadGoals(prefs))
+		}
+	}
+	showPalette := func() {
+		showCommandPalette(w, actions, tm, openTask)
+	}
+
+	// Режим фокусировки: окно показывает только первую невыполненную задачу текущего вида
+	enterFocus := func() {
+		content, menu := w.Content(), w.MainMenu()
+		w.SetMainMenu(nil)
+		w.SetContent(newFocusView(tm, model.Tasks, timers, newIdleTracker(w, prefs), func(err error) {
+			if err != nil {
+				dialog.ShowError(err, w)
+			}
+		}, func(stats focusStats) {
+			w.SetContent(content)
+			w.SetMainMenu(menu)
+			saveFocusSession(prefs, stats)
+			showFocusSummary(w, stats)
+		}))
+	}
+
+	// Вкладки: список с фильтрами и страницами, календарь, доска, статистика и проекты
+	sortContainer := container.NewGridWithColumns(3, sortPriorityButton, sortDateButton, sortUpdatedButton)
+	filterContainer := newReadingBorder(nil, nil,
+		container.NewHBox(filterActive, showArchived),
+		container.NewHBox(dueSelect, contextSelect, locationSelect, assigneeSelect, waitingSelect, viewSelect, columnsButton),
+		searchEntry)
+	pagerContainer := container.NewHBox(prevPageButton, pageLabel, nextPageButton, widget.NewLabel("На странице:"), pageSizeSelect)
+	if compact {
+		// На узком экране фильтры и страницы идут друг под другом
+		filterContainer = container.NewVBox(searchEntry,
+			container.NewGridWithColumns(2, filterActive, showArchived, dueSelect, contextSelect, locationSelect, assigneeSelect, waitingSelect))
+		pagerContainer = newReadingBorder(nil, nil, prevPageButton, nextPageButton, pageLabel)
+	}
+	listContainer := newReadingBorder(
+		container.NewVBox(sortContainer, filterContainer, widget.NewSeparator()),
+		pagerContainer, nil, nil,
+		container.NewStack(taskListView, taskTableView),
+	)
+	// Двойное нажатие по дню календаря или недели добавляет задачу со сроком в этот день
+	addTaskOn := func(day time.Time) {
+		due, err := tm.DueZone().ParseDueDate(day.Format(task.DueDateLayout))
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		showAddTaskDialog(w, tm, spelling, people(), contexts(), loadGoals(prefs), due, openTask)
+	}
+	tabs := newMainTabs(w, prefs, tm, listContainer, undoNotices, func() int { return workloadCapacity(prefs) }, openTask, addTaskOn)
+	openProject = tabs.OpenProject
+	if rtl {
+		// Вкладки проектов открываются после того, как окно отражено; их отражаем при выборе
+		onSelected := tabs.OnSelected
+		tabs.OnSelected = func(item *container.TabItem) {
+			mirror(item.Content)
+			onSelected(item)
+		}
+	}
+	for _, tag := range state.Projects {
+		tabs.OpenProject(tag)
+	}
+	tabs.SelectTitle(state.Tab)
+	for _, title := range []string{tabList, tabMyDay, tabGoals, tabCalendar, tabWeek, tabTimeline, tabBoard, tabStats} {
+		actions.Add("Вкладка: "+title, func() { tabs.SelectTitle(title) })
+	}
+
+	// План на день: из сводки можно сразу перейти к задачам раздела в списке
+	showDue := func(f dueFilter) {
+		searchEntry.SetText("")
+		filterActive.SetChecked(false)
+		dueSelect.SetSelected(f.Title())
+		tabs.SelectTitle(tabList)
+	}
+	showSummary := func() {
+		showStartupSummary(w, prefs, buildStartupSummary(tm.Tasks(), tm.DueZone().Now()), showDue)
+	}
+
+	// Команды от второго экземпляра приложения
+	handleCommand = func(cmd, arg string) {
+		if cmd == instanceCmdAdd && arg != "" {
+			if _, err := tm.AddTask(arg, "", 2, defaultDueDate(tm.DueZone())); err != nil {
+				dialog.ShowError(err, w)
+			}
+		}
+		w.Show()
+		w.RequestFocus()
+	}
+
+	// Копии файла, которые Syncthing создал при одновременном изменении на двух компьютерах,
+	// предлагаем слить с текущим списком
+	conflictPromptShown := false
+	checkConflictCopies := func() {
+		if conflictPromptShown {
+			return
+		}
+		conflictPromptShown = true
+		offerConflictCopies(w, tm, localFile, func() {
+			conflictPromptShown = false
+		})
+	}
+
+	// Следим за изменениями файла задач другими программами и компьютерами
+	reloadPromptShown := false
+	onStoreChanged := func() {
+		fyne.Do(func() {
+			if remote == nil {
+				checkConflictCopies()
+			}
+			changed, err := tm.FileChanged()
+			if err != nil || !changed || reloadPromptShown {
+				return
+			}
+
+			reloadPromptShown = true
+			showFileChangedDialog(w, tm, func() {
+				reloadPromptShown = false
+			})
+		})
+	}
+	if remote != nil {
+		cleanups = append(cleanups, pollRemoteStorage(remote, onStoreChanged))
+	} else if watcher, err := storage.Watch(tasksFilename, storage.WatchDelay, onStoreChanged); err == nil {
+		cleanups = append(cleanups, func() { watcher.Close() })
+	} else {
+		slog.Warn("failed to watch tasks file, external changes will not be noticed", "file", tasksFilename, "err", err)
+	}
+
+	// Серверы API: gRPC для сопутствующих приложений, HTTP для веб-интерфейса,
+	// /ws - изменения задач для веб-панелей, /calendar.ics для подписки календарей
+	// и /metrics для Prometheus.
+	// Если сертификат TLS указан, но не загрузился, серверы не запускаются:
+	// нельзя молча отдавать задачи без шифрования
+	apiAuth := apiauth.New(loadAPIKeys(prefs))
+	grpcAddr, httpAddr := prefs.String(prefGRPCAddr), prefs.String(prefHTTPAddr)
+	if grpcAddr != "" || httpAddr != "" {
+		tlsConfig, err := loadTLSConfig(prefs)
+		if err != nil {
+			slog.Error("failed to load TLS certificate, API servers are disabled", "err", err)
+			grpcAddr, httpAddr = "", ""
+		}
+		if grpcAddr != "" {
+			server, err := grpcapi.Start(grpcAddr, tm, fyne.DoAndWait, apiAuth, tlsConfig)
+			if err != nil {
+				slog.Error("failed to start gRPC API", "addr", grpcAddr, "err", err)
+			} else {
+				slog.Info("gRPC API started", "addr", grpcAddr, "tls", tlsConfig != nil)
+				cleanups = append(cleanups, server.Stop)
+			}
+		}
+		if httpAddr != "" {
+			handler := httpapi.NewServer(tm, fyne.DoAndWait, apiAuth)
+			taskMetrics := metrics.New(tm, fyne.DoAndWait)
+			tm.SetStorageObserver(taskMetrics.ObserveStorage)
+			handler.Handle("GET /metrics", apiauth.ScopeRead, taskMetrics.Handler())
+
+			server, err := httpapi.Start(httpAddr, handler, tlsConfig)
+			if err != nil {
+				slog.Error("failed to start HTTP server", "addr", httpAddr, "err", err)
+			} else {
+				slog.Info("HTTP server started", "addr", httpAddr, "tls", tlsConfig != nil)
+				cleanups = append(cleanups, func() { server.Close() })
+			}
+		}
+	}
+
+	// Над кнопками появляется сообщение о новой версии и напоминание об обзоре задач
+	updateNotices := container.NewVBox()
+	reviewNotices := container.NewVBox()
+	reminderHost := newReminderHost(a, w, prefs, tm)
+	nudges := newNudgeHost(a, w, prefs, tm)
+	jira := newJiraSync(tm, prefs)
+	chats := newWebhookHost(prefs, tm)
+	mailer := newEmailHost(prefs, tm)
+	homeAutomation := newMQTTHost(prefs, tm)
+
+	// Подсказки тура по интерфейсу
+	tourSteps := []tourStep{
+		{addButton, "Новая задача: название, описание, приоритет и срок"},
+		{editButton, "Выберите задачу и измените ее здесь (в таблице - двойным щелчком)"},
+		{toggleButton, "Отметьте задачу выполненной или верните ее в работу"},
+		{saveButton, "Сохраните изменения в файл задач"},
+		{searchEntry, "Поиск по названию и описанию"},
+		{viewSelect, "Список или таблица с настраиваемыми колонками"},
+		{sortPriorityButton, "Сортировка списка по приоритету, сроку или времени изменения"},
+	}
+
+	// Главное меню
+	w.SetMainMenu(fyne.NewMainMenu(
+		fyne.NewMenu("Файл",
+			fyne.NewMenuItem("Палитра команд (Ctrl+P)…", showPalette),
+			fyne.NewMenuItemSeparator(),
+			actions.MenuItem("Сменить профиль…", func() {
+				showSwitchProfileDialog(w, profiles, profile, func(next string) {
+					switchProfile := func() {
+						// Сначала освобождаем файл и адреса серверов, затем открываем новый профиль
+						cleanup()
+						openProfile(a, profiles, next, "", logFile)
+						w.Close()
+					}
+					timers.StopAll(time.Now())
+					if tm.IsDirty() {
+						showUnsavedChangesDialog(w, tm, switchProfile)
+						return
+					}
+					switchProfile()
+				})
+			}),
+			fyne.NewMenuItemSeparator(),
+			actions.MenuItem("Восстановить из резервной копии…", func() {
+				showRestoreBackupDialog(w, tm)
+			}),
+			actions.MenuItem("Слить с файлом…", func() {
+				showMergeDialog(w, tm)
+			}),
+			actions.MenuItem("Экспорт в JSON…", func() {
+				showExportDialog(w, prefs, tm, pluginRegistry, model.Tasks(), "JSON")
+			}),
+			actions.MenuItem("Импорт из JSON…", func() {
+				showJSONImportDialog(w, tm)
+			}),
+			actions.MenuItem("Импорт из org-mode или todo.txt…", func() {
+				showPlaintextImportDialog(w, tm)
+			}),
+			actions.MenuItem("Импорт из Microsoft To Do…", func() {
+				showMicrosoftToDoImport(w, tm, prefs)
+			}),
+			fyne.NewMenuItemSeparator(),
+			actions.MenuItem("Шифрование…", func() {
+				showEncryptionDialog(w, tm)
+			}),
+			actions.MenuItem("Сквозное шифрование…", func() {
+				showEndToEndDialog(w, prefs, keys, func() {
+					// Файл в удаленном хранилище перешифровывается сразу, задачи на сервере
+					// синхронизации - при следующей синхронизации
+					if remote != nil {
+						saveTasks(w, tm, func() {})
+					}
+				})
+			}),
+			actions.MenuItem("Настройки…", func() {
+				showSettingsDialog(w, prefs, tm, appLocker, func() {
+					updateAssigneeOptions()
+					updateContextOptions()
+					density = loadRowDensity(prefs)
+					taskListView.Refresh()
+					tabs.RefreshViews()
+					refreshHolidayFeed(prefs, tm, tabs.RefreshViews)
+					spelling.Reload()
+				})
+			}),
+			actions.MenuItem("Еженедельный обзор…", func() {
+				showReviewDialog(w, prefs, tm, reviewNotices.RemoveAll)
+			}),
+			actions.MenuItem("Напоминания…", func() {
+				showRemindersDialog(w, tm)
+			}),
+			actions.MenuItem("Уведомления в чаты…", func() {
+				showWebhooksDialog(w, prefs, tm, chats)
+			}),
+			actions.MenuItem("Повестка на почту…", func() {
+				showEmailDialog(w, prefs, tm, mailer)
+			}),
+			actions.MenuItem("MQTT…", func() {
+				showMQTTDialog(w, prefs, homeAutomation)
+			}),
+			actions.MenuItem("План на день…", showSummary),
+			actions.MenuItem("Режим фокусировки", enterFocus),
+			actions.MenuItem("Оценка и учтенное время…", func() {
+				showEstimatesDialog(w, tm)
+			}),
+			actions.MenuItem("Граф зависимостей…", func() {
+				showDependencyGraphDialog(w, tm, openTask)
+			}),
+			actions.MenuItem("Повестка дня…", func() {
+				showAgendaDialog(a, w, prefs, tm)
+			}),
+			actions.MenuItem("Следующие действия по контекстам…", func() {
+				showNextActionsDialog(a, w, tm)
+			}),
+			actions.MenuItem("Вкладки проектов…", func() {
+				showProjectTabsDialog(w, tabs)
+			}),
+			actions.MenuItem("Правила…", func() {
+				showRulesDialog(w, prefs, tm, rulesEngine)
+			}),
+			actions.MenuItem("Jira…", func() {
+				showJiraPanel(w, tm, prefs, jira)
+			}),
+			actions.MenuItem("Ключи API…", func() {
+				showAPIKeysDialog(w, prefs, apiAuth)
+			}),
+			fyne.NewMenuItemSeparator(),
+			actions.MenuItem("Проверить обновления…", func() {
+				checkForUpdates(w, updateNotices, true)
+			}),
+			actions.MenuItem("Знакомство с интерфейсом", func() {
+				showTour(w, tourSteps)
+			}),
+			actions.MenuItem("Показать журнал…", func() {
+				if logFile == nil {
+					dialog.ShowInformation("Журнал", "Файл журнала не удалось открыть", w)
+					return
+				}
+				showLogDialog(w, logFile.Path())
+			}),
+		),
+		fyne.NewMenu("Правка", append(copyItems, fyne.NewMenuItemSeparator(), pasteItem, captureSettingsItem)...),
+		scripts.menu,
+	))
+
+	// Не даем закрыть окно с несохраненными изменениями без подтверждения. Время
+	// запущенных таймеров записывается до проверки, чтобы его можно было сохранить
+	w.SetCloseIntercept(func() {
+		timers.StopAll(time.Now())
+		if !tm.IsDirty() {
+			w.Close()
+			return
+		}
+		showUnsavedChangesDialog(w, tm, w.Close)
+	})
+
+	// Сохраняем состояние интерфейса и освобождаем ресурсы профиля при закрытии окна
+	w.SetOnClosed(func() {
+		defer cleanup()
+		size := w.Canvas().Size()
+		uiState{
+			Width:       size.Width,
+			Height:      size.Height,
+			View:        viewSelect.Selected,
+			OnlyActive:  filterActive.Checked,
+			Search:      searchEntry.Text,
+			Sort:        model.sort,
+			SortReverse: model.reverse,
+			PageSize:    model.pager.pageSize,
+
+			Assignee:       model.assignee,
+			FilterAssignee: model.filterAssignee,
+			Context:        model.context,
+			FilterContext:  model.filterContext,
+			Location:       model.location,
+			FilterLocation: model.filterLocation,
+			Waiting:        model.waiting,
+			FilterWaiting:  model.filterWaiting,
+			DueFilter:      model.due,
+
+			Tab:      tabs.Selected().Text,
+			Projects: tabs.Projects(),
+		}.save(prefs)
+	})
+
+	// Размещение элементов интерфейса. Фокус по Tab идет от кнопок к поиску, списку и страницам
+	buttonContainer := container.NewGridWithColumns(9, addButton, captureButton, editButton, windowButton, deleteButton, toggleButton, saveButton, syncButton, exportButton)
+
+	content := newReadingBorder(
+		container.NewVBox(updateNotices, reviewNotices, reminderHost.notices, nudges.notices, undoNotices, buttonContainer),
+		syncSession.status, nil, nil,
+		tabs,
+	)
+	if compact {
+		// На телефоне вместо ряда кнопок - плавающая кнопка «+»; остальные действия
+		// доступны из меню и долгим нажатием на строку
+		content = newReadingBorder(
+			container.NewVBox(updateNotices, reviewNotices, reminderHost.notices, nudges.notices, undoNotices),
+			syncSession.status, nil, nil,
+			newFloatingButton(tabs, addButton.OnTapped),
+		)
+	}
+
+	w.SetContent(content)
+	// Блокировка окна PIN-кодом: по Ctrl+L или после бездействия
+	lockWindow := func() {
+		if !appLocker.Lock() {
+			return
+		}
+
+		// Закрываем открытые диалоги и окна задач, чтобы за экраном блокировки не остались данные задач
+		taskWindows.CloseAll()
+		overlays := w.Canvas().Overlays()
+		for overlays.Top() != nil {
+			overlays.Remove(overlays.Top())
+		}
+
+		content, menu := w.Content(), w.MainMenu()
+		w.SetMainMenu(nil)
+		w.SetContent(newLockScreen(appLocker, func() {
+			w.SetContent(content)
+			w.SetMainMenu(menu)
+		}))
+	}
+	w.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyL, Modifier: fyne.KeyModifierShortcutDefault}, func(fyne.Shortcut) {
+		if !appLocker.Enabled() {
+			dialog.ShowInformation("Блокировка", "Задайте PIN-код в настройках, чтобы блокировать окно", w)
+			return
+		}
+		lockWindow()
+	})
+	w.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyP, Modifier: fyne.KeyModifierShortcutDefault}, func(fyne.Shortcut) {
+		if !appLocker.Locked() {
+			showPalette()
+		}
+	})
+	w.Canvas().AddShortcut(&fyne.ShortcutCopy{}, func(fyne.Shortcut) {
+		if selectedTaskID != 0 && !appLocker.Locked() {
+			copyItems[0].Action()
+		}
+	})
+	w.Canvas().AddShortcut(&fyne.ShortcutPaste{}, func(fyne.Shortcut) {
+		if !appLocker.Locked() {
+			pasteItem.Action()
+		}
+	})
+	appLocker.watchInput(w.Canvas())
+	// Правила автоматизации срабатывают при изменении задач и по таймеру
+	stopRules := make(chan struct{})
+	cleanups = append(cleanups, func() { close(stopRules) })
+	go func() {
+		ticker := time.NewTicker(rulesInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopRules:
+				return
+			case <-ticker.C:
+				fyne.Do(func() { runRules(prefs, rulesEngine, tm) })
+			}
+		}
+	}()
+
+	stopIdleCheck := make(chan struct{})
+	cleanups = append(cleanups, func() { close(stopIdleCheck) })
+	go func() {
+		ticker := time.NewTicker(15 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopIdleCheck:
+				return
+			case <-ticker.C:
+				fyne.Do(func() {
+					timers.Heartbeat(time.Now())
+					appLocker.NoteInput(inputState(w.Canvas()))
+					if appLocker.IdleExpired(time.Now()) {
+						lockWindow()
+					}
+				})
+			}
+		}
+	}()
+
+	// Относительные сроки в списке («завтра», «через 3 дня») меняются и без изменения задач
+	stopDueRefresh := make(chan struct{})
+	cleanups = append(cleanups, func() { close(stopDueRefresh) })
+	go func() {
+		ticker := time.NewTicker(relativeDueInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopDueRefresh:
+				return
+			case <-ticker.C:
+				fyne.Do(taskListView.Refresh)
+			}
+		}
+	}()
+
+	// Задача из командной строки добавляется, когда задачи уже загружены.
+	// Новому пользователю предлагаем примеры и знакомство с интерфейсом
+	onLoaded := func() {
+		runRules(prefs, rulesEngine, tm)
+		cleanups = append(cleanups, rulesEngine.Attach(tm, func() time.Time { return tm.DueZone().Now() }), func() { saveRulesApplied(prefs, rulesEngine) })
+		scripts.Reload(false)
+		cleanups = append(cleanups, scripts.Close)
+		// Напоминания проверяются только после загрузки задач, иначе отметки о показанных
+		// напоминаниях сочлись бы ненужными
+		reminderHost.Check()
+		nudges.Check()
+		chats.Check()
+		mailer.Check()
+		timers.Recover(w)
+		stopReminders := make(chan struct{})
+		cleanups = append(cleanups, func() { close(stopReminders) })
+		go func() {
+			ticker := time.NewTicker(reminderInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-stopReminders:
+					return
+				case <-ticker.C:
+					// Задачи становятся просроченными и без изменений, с наступлением дня
+					fyne.Do(func() {
+						reminderHost.Check()
+						nudges.Check()
+						chats.Check()
+						mailer.Check()
+						homeAutomation.CheckCounts()
+						badge.Update()
+					})
+				}
+			}
+		}()
+		cleanups = append(cleanups, jira.Start(), homeAutomation.Start())
+		if prefs.Int(prefJiraRefresh) > 0 {
+			jira.Refresh()
+		}
+		checkConflictCopies()
+		if remote != nil && !prefs.Bool(prefE2EPhraseShown) {
+			showRecoveryPhraseDialog(w, prefs, keys, func() {})
+		}
+		if firstRun && addTitle == "" {
+			showWelcomeDialog(w, prefs, tm, tourSteps)
+		} else if reviewDue(prefs, time.Now()) && len(tm.Tasks()) > 0 {
+			showReviewReminder(w, reviewNotices, prefs, tm)
+		}
+		if !firstRun && prefs.BoolWithFallback(prefStartupSummary, true) {
+			if summary := buildStartupSummary(tm.Tasks(), tm.DueZone().Now()); !summary.Empty() {
+				showStartupSummary(w, prefs, summary, showDue)
+			}
+		}
+		if addTitle != "" {
+			if _, err := tm.AddTask(addTitle, "", 2, defaultDueDate(tm.DueZone())); err != nil {
+				dialog.ShowError(err, w)
+			}
+		}
+	}
+
+	if prefs.Bool(prefUpdateCheck) {
+		checkForUpdates(w, updateNotices, false)
+	}
+	refreshHolidayFeed(prefs, tm, tabs.RefreshViews)
+
+	// Зашифрованный файл открываем только после ввода пароля или фразы восстановления ключа
+	var handleLoad func(err error)
+	handleLoad = func(err error) {
+		switch {
+		case errors.Is(err, task.ErrPassphraseRequired):
+			showUnlockDialog(w, tm, onLoaded, w.Close)
+		case errors.Is(err, storage.ErrUnknownKey):
+			showImportPhraseDialog(w, prefs, keys, func() {
+				handleLoad(tm.LoadFromFile())
+			}, w.Close)
+		case err != nil:
+			// Не даем ошибке пройти незамеченной: сохранение перезапишет файл,
+			// но его предыдущая версия останется в резервной копии
+			dialog.ShowError(fmt.Errorf("failed to load tasks file: %w", err), w)
+			onLoaded()
+		default:
+			onLoaded()
+		}
+	}
+	handleLoad(loadErr)
+
+	w.Show()
+	return true
+}
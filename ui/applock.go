@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/storage"
+)
+
+// appLock блокирует окно приложения PIN-кодом по команде или после бездействия
+type appLock struct {
+	pinHash      string
+	idleTimeout  time.Duration
+	lastActivity time.Time
+	locked       bool
+	paused       int    // Touch не действует, пока окно обновляется само, а не по действию пользователя
+	lastInput    string // состояние поля ввода с фокусом при прошлой проверке
+}
+
+// newAppLock создает блокировку с хешем PIN-кода и временем бездействия (0 - без автоблокировки)
+func newAppLock(pinHash string, idleTimeout time.Duration) *appLock {
+	return &appLock{
+		pinHash:      pinHash,
+		idleTimeout:  idleTimeout,
+		lastActivity: time.Now(),
+	}
+}
+
+// Enabled сообщает, задан ли PIN-код
+func (l *appLock) Enabled() bool {
+	return l.pinHash != ""
+}
+
+// Locked сообщает, заблокировано ли приложение
+func (l *appLock) Locked() bool {
+	return l.locked
+}
+
+// Touch отмечает действие пользователя
+func (l *appLock) Touch() {
+	if l.paused == 0 {
+		l.lastActivity = time.Now()
+	}
+}
+
+// WithoutTouch выполняет update, не считая вызовы Touch действиями пользователя: так выбор
+// строки программой при перерисовке списка после изменения задач не откладывает блокировку
+func (l *appLock) WithoutTouch(update func()) {
+	l.paused++
+	defer func() { l.paused-- }()
+	update()
+}
+
+// NoteInput отмечает действие пользователя, если изменилось поле ввода с фокусом.
+// Поле забирает нажатия клавиш себе, и холст о них не узнает, поэтому набор текста
+// замечается по тексту и курсору поля при проверке бездействия
+func (l *appLock) NoteInput(state string) {
+	if state != "" && state != l.lastInput {
+		l.Touch()
+	}
+	l.lastInput = state
+}
+
+// inputState описывает поле ввода с фокусом: само поле, курсор и текст; пусто - фокуса в поле нет
+func inputState(c fyne.Canvas) string {
+	var e *widget.Entry
+	switch focused := c.Focused().(type) {
+	case *widget.Entry:
+		e = focused
+	case *widget.SelectEntry:
+		e = &focused.Entry
+	case *spellEntry:
+		e = &focused.Entry
+	case *paletteEntry:
+		e = &focused.Entry
+	default:
+		return ""
+	}
+	return fmt.Sprintf("%p %d:%d %s", e, e.CursorRow, e.CursorColumn, e.Text)
+}
+
+// watchInput отмечает действиями пользователя нажатия клавиш, когда фокуса нет ни в одном поле
+func (l *appLock) watchInput(c fyne.Canvas) {
+	c.SetOnTypedKey(func(*fyne.KeyEvent) { l.Touch() })
+	c.SetOnTypedRune(func(rune) { l.Touch() })
+}
+
+// IdleExpired сообщает, пора ли заблокировать приложение из-за бездействия
+func (l *appLock) IdleExpired(now time.Time) bool {
+	return l.Enabled() && !l.locked && l.idleTimeout > 0 && now.Sub(l.lastActivity) >= l.idleTimeout
+}
+
+// Lock блокирует приложение, если PIN-код задан
+func (l *appLock) Lock() bool {
+	if !l.Enabled() || l.locked {
+		return false
+	}
+	l.locked = true
+	return true
+}
+
+// Unlock снимает блокировку при правильном PIN-коде
+func (l *appLock) Unlock(pin string) bool {
+	if !verifyPIN(pin, l.pinHash) {
+		return false
+	}
+	l.locked = false
+	l.Touch()
+	return true
+}
+
+// hashPIN возвращает соль и хеш PIN-кода для хранения в настройках
+func hashPIN(pin string) string {
+	salt := make([]byte, storage.SaltLen)
+	rand.Read(salt)
+	return base64.StdEncoding.EncodeToString(salt) + "$" +
+		base64.StdEncoding.EncodeToString(storage.DeriveKey(pin, salt))
+}
+
+// verifyPIN проверяет PIN-код по сохраненному хешу
+func verifyPIN(pin, stored string) bool {
+	encodedSalt, encodedHash, ok := strings.Cut(stored, "$")
+	if !ok {
+		return false
+	}
+	salt, err := base64.StdEncoding.DecodeString(encodedSalt)
+	if err != nil {
+		return false
+	}
+	hash, err := base64.StdEncoding.DecodeString(encodedHash)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(storage.DeriveKey(pin, salt), hash) == 1
+}
+
+// newLockScreen создает экран блокировки, который заменяет содержимое окна
+func newLockScreen(lock *appLock, onUnlocked func()) fyne.CanvasObject {
+	pinEntry := widget.NewPasswordEntry()
+	pinEntry.SetPlaceHolder("PIN-код")
+	errorLabel := widget.NewLabel("")
+
+	unlock := func() {
+		if lock.Unlock(pinEntry.Text) {
+			onUnlocked()
+			return
+		}
+		pinEntry.SetText("")
+		errorLabel.SetText("Неверный PIN-код")
+	}
+	pinEntry.OnSubmitted = func(string) { unlock() }
+	unlockButton := widget.NewButton("Разблокировать", unlock)
+	unlockButton.Importance = widget.HighImportance
+
+	title := widget.NewLabelWithStyle("Task Manager заблокирован", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	form := container.NewVBox(title, pinEntry, unlockButton, errorLabel)
+	return container.NewCenter(container.NewGridWrap(fyne.NewSize(280, form.MinSize().Height), form))
+}
@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+func TestPINHash(t *testing.T) {
+	stored := hashPIN("1234")
+
+	assert.True(t, verifyPIN("1234", stored))
+	assert.False(t, verifyPIN("4321", stored))
+	assert.False(t, verifyPIN("1234", "garbage"))
+
+	// Каждый раз используется новая соль
+	assert.NotEqual(t, stored, hashPIN("1234"))
+}
+
+func TestAppLock(t *testing.T) {
+	lock := newAppLock(hashPIN("1234"), 5*time.Minute)
+	now := time.Now()
+
+	// До истечения времени бездействия блокировки нет
+	assert.False(t, lock.IdleExpired(now.Add(time.Minute)))
+	assert.True(t, lock.IdleExpired(now.Add(6*time.Minute)))
+
+	// Действие пользователя откладывает блокировку
+	lock.lastActivity = now.Add(4 * time.Minute)
+	assert.False(t, lock.IdleExpired(now.Add(6*time.Minute)))
+
+	assert.True(t, lock.Lock())
+	assert.True(t, lock.Locked())
+	assert.False(t, lock.Lock()) // Уже заблокировано
+	assert.False(t, lock.IdleExpired(now.Add(time.Hour)))
+
+	assert.False(t, lock.Unlock("0000"))
+	assert.True(t, lock.Locked())
+	assert.True(t, lock.Unlock("1234"))
+	assert.False(t, lock.Locked())
+}
+
+func TestAppLockDisabled(t *testing.T) {
+	// Без PIN-кода блокировать нечем
+	lock := newAppLock("", time.Minute)
+	assert.False(t, lock.Enabled())
+	assert.False(t, lock.IdleExpired(time.Now().Add(time.Hour)))
+	assert.False(t, lock.Lock())
+}
+
+func TestAppLockIgnoresTaskEvents(t *testing.T) {
+	test.NewTempApp(t)
+	tm := newTestManager(t)
+	lock := newAppLock(hashPIN("1234"), time.Minute)
+
+	// Как в окне: выбор строки пользователем - действие, а при изменении задач
+	// список перерисовывается и выделение переставляется программой
+	list := widget.NewList(
+		func() int { return len(tm.Tasks()) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(widget.ListItemID, fyne.CanvasObject) {},
+	)
+	list.OnSelected = func(widget.ListItemID) { lock.Touch() }
+	tm.Subscribe(func(task.Event) {
+		lock.WithoutTouch(func() { list.Select(len(tm.Tasks()) - 1) })
+	})
+
+	lock.lastActivity = time.Now().Add(-2 * time.Minute)
+	mustAddTask(t, tm, "Из API", "", 2, time.Time{})
+	mustAddTask(t, tm, "Из синхронизации", "", 2, time.Time{})
+	assert.True(t, lock.IdleExpired(time.Now()))
+
+	list.Select(0)
+	assert.False(t, lock.IdleExpired(time.Now()))
+}
+
+func TestAppLockNoteInput(t *testing.T) {
+	test.NewTempApp(t)
+	entry := newSpellEntry(nil, false)
+	w := test.NewWindow(entry)
+	defer w.Close()
+	lock := newAppLock(hashPIN("1234"), time.Minute)
+	lock.lastActivity = time.Now().Add(-2 * time.Minute)
+
+	// Без фокуса в поле нажатия клавиш приходят холсту
+	lock.watchInput(w.Canvas())
+	assert.Empty(t, inputState(w.Canvas()))
+	test.TypeOnCanvas(w.Canvas(), "a")
+	assert.False(t, lock.IdleExpired(time.Now()))
+
+	// Поле с фокусом забирает клавиши себе: набор замечается по изменению поля
+	w.Canvas().Focus(entry)
+	lock.NoteInput(inputState(w.Canvas()))
+	lock.lastActivity = time.Now().Add(-2 * time.Minute)
+	lock.NoteInput(inputState(w.Canvas()))
+	assert.True(t, lock.IdleExpired(time.Now()))
+	test.Type(entry, "Позвонить")
+	lock.NoteInput(inputState(w.Canvas()))
+	assert.False(t, lock.IdleExpired(time.Now()))
+}
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+
+	"taskmanager/task"
+)
+
+// overdueCount считает просроченные задачи не из архива
+func overdueCount(tasks []*task.Task, now time.Time) int {
+	count := 0
+	for _, t := range tasks {
+		if !t.Archived && isOverdue(t, now) {
+			count++
+		}
+	}
+	return count
+}
+
+// overdueTitle добавляет к заголовку окна число просроченных задач
+func overdueTitle(title string, overdue int) string {
+	if overdue == 0 {
+		return title
+	}
+	return fmt.Sprintf("%s — %d overdue", title, overdue)
+}
+
+// overdueBadge показывает число просроченных задач в заголовке окна и в меню значка
+// в системном трее, чтобы его было видно и при свернутом окне
+type overdueBadge struct {
+	w        fyne.Window
+	title    string
+	tm       *task.TaskManager
+	tray     desktop.App // nil, если системного трея нет
+	trayMenu *fyne.Menu
+}
+
+func newOverdueBadge(a fyne.App, w fyne.Window, title string, tm *task.TaskManager) *overdueBadge {
+	b := &overdueBadge{w: w, title: title, tm: tm}
+	if tray, ok := a.(desktop.App); ok {
+		status := fyne.NewMenuItem("", nil)
+		status.Disabled = true
+		b.tray = tray
+		b.trayMenu = fyne.NewMenu(title, status, fyne.NewMenuItem("Показать окно", func() {
+			w.Show()
+			w.RequestFocus()
+		}))
+		tray.SetSystemTrayWindow(w)
+	}
+	return b
+}
+
+// Update пересчитывает просроченные задачи
+func (b *overdueBadge) Update() {
+	overdue := overdueCount(b.tm.Tasks(), b.tm.DueZone().Now())
+	b.w.SetTitle(overdueTitle(b.title, overdue))
+	if b.tray == nil {
+		return
+	}
+	status := b.trayMenu.Items[0]
+	status.Label = "Просроченных задач нет"
+	if overdue > 0 {
+		status.Label = fmt.Sprintf("Просрочено задач: %d", overdue)
+	}
+	b.tray.SetSystemTrayMenu(b.trayMenu)
+}
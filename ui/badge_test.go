@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+func TestOverdueCount(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	yesterday := now.AddDate(0, 0, -1)
+	tasks := []*task.Task{
+		{DueDate: yesterday},
+		{DueDate: yesterday, Completed: true},
+		{DueDate: yesterday, Archived: true},
+		{DueDate: now.AddDate(0, 0, 1)},
+		{},
+	}
+	assert.Equal(t, 1, overdueCount(tasks, now))
+
+	assert.Equal(t, "Task Manager", overdueTitle("Task Manager", 0))
+	assert.Equal(t, "Task Manager — 3 overdue", overdueTitle("Task Manager", 3))
+}
+
+func TestOverdueBadge(t *testing.T) {
+	a := test.NewTempApp(t)
+	w := a.NewWindow("Task Manager")
+	tm := newTestManager(t)
+	mustAddTask(t, tm, "Отчет", "", 2, time.Now().AddDate(0, 0, -2))
+
+	badge := newOverdueBadge(a, w, "Task Manager", tm)
+	badge.Update()
+	assert.Equal(t, "Task Manager — 1 overdue", w.Title())
+}
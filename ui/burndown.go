@@ -0,0 +1,237 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/task"
+)
+
+// burndownDays - за сколько последних дней показывать диаграмму сгорания
+const burndownDays = 30
+
+// allProjectsTitle - выбор всех задач вместо одного проекта
+const allProjectsTitle = "Все задачи"
+
+// burndownMode - что считать оставшейся работой
+type burndownMode int
+
+const (
+	burndownTasks   burndownMode = iota // число открытых задач
+	burndownMinutes                     // сумма их оценок в минутах
+)
+
+// burndownModeTitles - подписи режимов в порядке burndownMode
+var burndownModeTitles = []string{"Задачи", "Минуты по оценке"}
+
+// burndownPoint - сколько работы оставалось к концу дня
+type burndownPoint struct {
+	Day       string // 2006-01-02
+	Remaining int
+}
+
+// burndown считает оставшуюся работу на конец каждого из days дней до today включительно:
+// задача открыта с дня создания до дня выполнения. Задачи из архива, брошенные невыполненными,
+// не учитываются. Дни считаются в поясе loc
+func burndown(tasks []*task.Task, today time.Time, days int, mode burndownMode, loc *time.Location) []burndownPoint {
+	points := make([]burndownPoint, days)
+	for i := range points {
+		points[i].Day = today.AddDate(0, 0, i-days+1).Format(task.DueDateLayout)
+	}
+	for _, t := range tasks {
+		if t.Archived && !t.Completed {
+			continue
+		}
+		weight := 1
+		if mode == burndownMinutes {
+			weight = t.EstimatedMinutes
+		}
+		created := t.CreatedAt.In(loc).Format(task.DueDateLayout)
+		done := ""
+		if t.Completed {
+			done = t.DoneAt().In(loc).Format(task.DueDateLayout)
+		}
+		for i, point := range points {
+			if created <= point.Day && (done == "" || done > point.Day) {
+				points[i].Remaining += weight
+			}
+		}
+	}
+	return points
+}
+
+// projectTasks возвращает задачи проекта - метки tag; allProjectsTitle - все задачи
+func projectTasks(tasks []*task.Task, project string) []*task.Task {
+	if project == allProjectsTitle {
+		return tasks
+	}
+	var filtered []*task.Task
+	for _, t := range tasks {
+		if slices.Contains(t.Tags, project) {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
+// burndownChart - линия оставшейся работы по дням с подписями первого и последнего дня
+type burndownChart struct {
+	widget.BaseWidget
+	points []burndownPoint
+}
+
+func newBurndownChart() *burndownChart {
+	c := &burndownChart{}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+// SetPoints показывает новые данные
+func (c *burndownChart) SetPoints(points []burndownPoint) {
+	c.points = points
+	c.Refresh()
+}
+
+func (c *burndownChart) CreateRenderer() fyne.WidgetRenderer {
+	r := &burndownRenderer{chart: c}
+	r.Refresh()
+	return r
+}
+
+type burndownRenderer struct {
+	chart   *burndownChart
+	axis    *canvas.Line
+	lines   []*canvas.Line
+	labels  []*canvas.Text // максимум, первый и последний день
+	objects []fyne.CanvasObject
+}
+
+func (r *burndownRenderer) Refresh() {
+	points := r.chart.points
+	r.axis = canvas.NewLine(theme.Color(theme.ColorNameSeparator))
+	r.lines = nil
+	for range max(len(points)-1, 0) {
+		line := canvas.NewLine(theme.Color(theme.ColorNamePrimary))
+		line.StrokeWidth = 2
+		r.lines = append(r.lines, line)
+	}
+	r.labels = nil
+	if len(points) > 0 {
+		caption := func(text string) *canvas.Text {
+			label := canvas.NewText(text, theme.Color(theme.ColorNamePlaceHolder))
+			label.TextSize = theme.CaptionTextSize()
+			return label
+		}
+		first, _ := time.Parse(task.DueDateLayout, points[0].Day)
+		last, _ := time.Parse(task.DueDateLayout, points[len(points)-1].Day)
+		r.labels = []*canvas.Text{
+			caption(fmt.Sprint(r.peak())),
+			caption(first.Format("02.01")),
+			caption(last.Format("02.01")),
+		}
+	}
+	r.objects = []fyne.CanvasObject{r.axis}
+	for _, line := range r.lines {
+		r.objects = append(r.objects, line)
+	}
+	for _, label := range r.labels {
+		r.objects = append(r.objects, label)
+	}
+	r.Layout(r.chart.Size())
+	canvas.Refresh(r.chart)
+}
+
+// peak - наибольшая оставшаяся работа, верх шкалы
+func (r *burndownRenderer) peak() int {
+	peak := 0
+	for _, point := range r.chart.points {
+		peak = max(peak, point.Remaining)
+	}
+	return peak
+}
+
+func (r *burndownRenderer) Layout(size fyne.Size) {
+	textHeight := theme.CaptionTextSize() + 4
+	plotHeight := size.Height - textHeight
+	r.axis.Position1 = fyne.NewPos(0, plotHeight)
+	r.axis.Position2 = fyne.NewPos(size.Width, plotHeight)
+
+	points := r.chart.points
+	peak := float32(max(r.peak(), 1))
+	step := size.Width / float32(max(len(points)-1, 1))
+	at := func(i int) fyne.Position {
+		return fyne.NewPos(float32(i)*step, plotHeight-plotHeight*float32(points[i].Remaining)/peak)
+	}
+	for i, line := range r.lines {
+		line.Position1, line.Position2 = at(i), at(i+1)
+	}
+	if len(r.labels) == 3 {
+		r.labels[0].Move(fyne.NewPos(2, 0))
+		r.labels[1].Move(fyne.NewPos(0, plotHeight+2))
+		r.labels[2].Move(fyne.NewPos(size.Width-r.labels[2].MinSize().Width, plotHeight+2))
+	}
+}
+
+func (r *burndownRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(300, 160)
+}
+
+func (r *burndownRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+func (r *burndownRenderer) Destroy() {}
+
+// newBurndownView создает диаграмму сгорания для вкладки статистики: проект и режим выбираются
+func newBurndownView(tm *task.TaskManager) (view fyne.CanvasObject, refresh func()) {
+	chart := newBurndownChart()
+	summary := widget.NewLabel("")
+	projectSelect := widget.NewSelect(nil, nil)
+	modeSelect := widget.NewSelect(burndownModeTitles, nil)
+	mode := burndownTasks
+
+	update := func() {
+		now := tm.DueZone().Now()
+		points := burndown(projectTasks(tm.Tasks(), projectSelect.Selected), now, burndownDays, mode, now.Location())
+		chart.SetPoints(points)
+		remaining := points[len(points)-1].Remaining
+		if mode == burndownMinutes {
+			summary.SetText("Осталось: " + formatMinutes(remaining))
+		} else {
+			summary.SetText(fmt.Sprintf("Осталось задач: %d", remaining))
+		}
+	}
+	refresh = func() {
+		selected := projectSelect.Selected
+		projectSelect.Options = append([]string{allProjectsTitle}, projectTags(tm.Tasks())...)
+		if selected == "" || !slices.Contains(projectSelect.Options, selected) {
+			selected = allProjectsTitle
+		}
+		projectSelect.SetSelected(selected)
+		projectSelect.Refresh()
+		update()
+	}
+	projectSelect.OnChanged = func(string) { update() }
+	modeSelect.OnChanged = func(title string) {
+		for i, t := range burndownModeTitles {
+			if t == title {
+				mode = burndownMode(i)
+			}
+		}
+		update()
+	}
+	modeSelect.SetSelected(burndownModeTitles[burndownTasks])
+	refresh()
+
+	header := container.NewHBox(
+		widget.NewLabelWithStyle(fmt.Sprintf("Сгорание за %d дней", burndownDays), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		projectSelect, modeSelect, summary)
+	return container.NewBorder(header, nil, nil, nil, chart), refresh
+}
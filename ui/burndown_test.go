@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+func TestBurndown(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 12, 0, 0, 0, time.UTC) }
+	tasks := []*task.Task{
+		{CreatedAt: day(10), EstimatedMinutes: 60, Tags: []string{"сайт"}},
+		{CreatedAt: day(11), EstimatedMinutes: 30, Completed: true, CompletedAt: day(13), Tags: []string{"сайт"}},
+		// Старый файл: время выполнения не записано, берется время изменения
+		{CreatedAt: day(12), EstimatedMinutes: 15, Completed: true, UpdatedAt: day(14)},
+		{CreatedAt: day(10), Archived: true},
+	}
+
+	points := burndown(tasks, day(15), 6, burndownTasks, time.UTC)
+	assert.Equal(t, []burndownPoint{
+		{"2026-10-10", 1}, {"2026-10-11", 2}, {"2026-10-12", 3},
+		{"2026-10-13", 2}, {"2026-10-14", 1}, {"2026-10-15", 1},
+	}, points)
+
+	var remaining []int
+	for _, point := range burndown(projectTasks(tasks, "сайт"), day(15), 4, burndownMinutes, time.UTC) {
+		remaining = append(remaining, point.Remaining)
+	}
+	assert.Equal(t, []int{90, 60, 60, 60}, remaining)
+	assert.Len(t, projectTasks(tasks, allProjectsTitle), 4)
+}
+
+func TestBurndownView(t *testing.T) {
+	test.NewTempApp(t)
+	tm := newTestManager(t)
+	mustAddTask(t, tm, "Сверстать страницу", "", 2, time.Now())
+	view, refresh := newBurndownView(tm)
+	refresh()
+	assert.NotNil(t, view)
+	test.NewWindow(view).Resize(view.MinSize())
+}
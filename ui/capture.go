@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/plugins"
+	"taskmanager/task"
+)
+
+// prefCaptureCommand - команда захвата, вывод которой становится задачами; пусто - не задана
+const prefCaptureCommand = "capture.command"
+
+// captureTimeout - сколько ждать команду захвата: распознавание речи ждет, пока пользователь договорит
+const captureTimeout = 2 * time.Minute
+
+// runCapture запускает команду захвата и показывает разобранные из ее вывода задачи.
+// Пока команда работает, окно предлагает говорить и позволяет отменить запуск
+func runCapture(w fyne.Window, prefs fyne.Preferences, tm *task.TaskManager) {
+	command := prefs.String(prefCaptureCommand)
+	if strings.TrimSpace(command) == "" {
+		showCaptureSettings(w, prefs)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), captureTimeout)
+	progress := widget.NewProgressBarInfinite()
+	waiting := dialog.NewCustom("Диктовка", "Отмена",
+		container.NewVBox(widget.NewLabel("Говорите: задача будет создана из вывода команды"), progress), w)
+	waiting.SetOnClosed(cancel)
+	waiting.Show()
+
+	go func() {
+		text, err := plugins.Capture(ctx, command)
+		canceled := errors.Is(ctx.Err(), context.Canceled)
+		cancel()
+		fyne.Do(func() {
+			waiting.Hide()
+			switch {
+			case canceled:
+			case err != nil:
+				slog.Error("capture command failed", "err", err)
+				dialog.ShowError(err, w)
+			default:
+				showQuickAddPreview(w, tm, text)
+			}
+		})
+	}()
+}
+
+// showCaptureSettings настраивает команду захвата, например программу распознавания речи
+func showCaptureSettings(w fyne.Window, prefs fyne.Preferences) {
+	commandEntry := widget.NewEntry()
+	commandEntry.SetPlaceHolder(`whisper-listen --lang ru`)
+	commandEntry.SetText(prefs.String(prefCaptureCommand))
+	commandEntry.Validator = func(text string) error {
+		if strings.TrimSpace(text) == "" {
+			return nil
+		}
+		_, err := plugins.SplitCommand(text)
+		return err
+	}
+
+	hint := widget.NewLabel("Команда печатает текст задачи, например распознанную речь. " +
+		"Каждая строка вывода разбирается как быстрый ввод: «Позвонить маме завтра !3 #дом @Петя»")
+	hint.Wrapping = fyne.TextWrapWord
+
+	form := dialog.NewForm("Команда диктовки", "Сохранить", "Отмена", []*widget.FormItem{
+		{Text: "Команда", Widget: commandEntry, HintText: "Аргументы с пробелами - в кавычках"},
+		{Text: "", Widget: hint},
+	}, func(confirmed bool) {
+		if confirmed {
+			prefs.SetString(prefCaptureCommand, strings.TrimSpace(commandEntry.Text))
+		}
+	}, w)
+	form.Resize(dialogSize(w, fyne.NewSize(560, 0)))
+	form.Show()
+}
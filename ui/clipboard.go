@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+
+	"taskmanager/plugins"
+	"taskmanager/task"
+)
+
+// clipboardFormat - формат, в котором задачи копируются в буфер обмена
+type clipboardFormat struct {
+	Title  string
+	format func(tasks []*task.Task) (string, error)
+}
+
+// clipboardFormats - форматы копирования: первый используется по Ctrl+C
+var clipboardFormats = []clipboardFormat{
+	{"Копировать как текст", formatTasksText},
+	{"Копировать как Markdown", formatTasksMarkdown},
+	{"Копировать как JSON", formatTasksJSON},
+}
+
+// formatTasksText записывает задачи простым текстом для писем и чатов
+func formatTasksText(tasks []*task.Task) (string, error) {
+	var parts []string
+	for _, t := range tasks {
+		var b strings.Builder
+		b.WriteString(t.Title)
+		if t.Completed {
+			b.WriteString(" (выполнена)")
+		}
+		fmt.Fprintf(&b, "\nСрок: %s, приоритет: %s", formatDueDate(t), task.PriorityText(t.Priority))
+		if t.Assignee != "" {
+			b.WriteString("\nИсполнитель: " + t.Assignee)
+		}
+		if t.Context != "" {
+			b.WriteString("\nКонтекст: " + t.Context)
+		}
+		if len(t.Tags) > 0 {
+			b.WriteString("\nМетки: " + strings.Join(t.Tags, ", "))
+		}
+		if t.Description != "" {
+			b.WriteString("\n" + t.Description)
+		}
+		parts = append(parts, b.String())
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
+
+// formatTasksMarkdown записывает задачи списком с флажками
+func formatTasksMarkdown(tasks []*task.Task) (string, error) {
+	var b strings.Builder
+	for _, t := range tasks {
+		b.WriteString(plugins.MarkdownItem(t))
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// formatTasksJSON записывает задачу объектом JSON, несколько задач - массивом
+func formatTasksJSON(tasks []*task.Task) (string, error) {
+	var value any = tasks
+	if len(tasks) == 1 {
+		value = tasks[0]
+	}
+	data, err := json.MarshalIndent(value, "", "  ")
+	return string(data), err
+}
+
+// copyTasks кладет задачи в буфер обмена в выбранном формате
+func copyTasks(clipboard fyne.Clipboard, tasks []*task.Task, format clipboardFormat) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	text, err := format.format(tasks)
+	if err != nil {
+		return err
+	}
+	clipboard.SetContent(text)
+	return nil
+}
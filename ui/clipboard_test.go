@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"taskmanager/task"
+)
+
+func clipboardTask() *task.Task {
+	return &task.Task{
+		ID: 1, UUID: "u1", Title: "Сдать отчет", Description: "До обеда", Priority: 3,
+		DueDate: time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC), Tags: []string{"работа"}, Assignee: "Маша",
+	}
+}
+
+func TestClipboardFormats(t *testing.T) {
+	tasks := []*task.Task{clipboardTask()}
+
+	text, err := formatTasksText(tasks)
+	require.NoError(t, err)
+	assert.Equal(t, "Сдать отчет\nСрок: 2026-10-20, приоритет: высокий\nИсполнитель: Маша\nМетки: работа\nДо обеда", text)
+
+	markdown, err := formatTasksMarkdown(tasks)
+	require.NoError(t, err)
+	assert.Equal(t, "- [ ] Сдать отчет (срок 2026-10-20, приоритет высокий, Маша) #работа\n  До обеда", markdown)
+
+	// Одна задача копируется объектом, несколько - массивом
+	data, err := formatTasksJSON(tasks)
+	require.NoError(t, err)
+	var decoded task.Task
+	require.NoError(t, json.Unmarshal([]byte(data), &decoded))
+	assert.Equal(t, "u1", decoded.UUID)
+
+	done := clipboardTask()
+	done.Completed, done.Description, done.Tags, done.Assignee = true, "", nil, ""
+	data, err = formatTasksJSON(append(tasks, done))
+	require.NoError(t, err)
+	var list []task.Task
+	require.NoError(t, json.Unmarshal([]byte(data), &list))
+	assert.Len(t, list, 2)
+
+	text, _ = formatTasksText(append(tasks, done))
+	assert.Contains(t, text, "До обеда\n\nСдать отчет (выполнена)\nСрок: 2026-10-20, приоритет: высокий")
+}
+
+func TestCopyTasks(t *testing.T) {
+	a := test.NewTempApp(t)
+	require.NoError(t, copyTasks(a.Clipboard(), []*task.Task{clipboardTask()}, clipboardFormats[1]))
+	assert.Contains(t, a.Clipboard().Content(), "- [ ] Сдать отчет")
+
+	// Без задач буфер обмена не меняется
+	require.NoError(t, copyTasks(a.Clipboard(), nil, clipboardFormats[0]))
+	assert.Contains(t, a.Clipboard().Content(), "- [ ] Сдать отчет")
+}
@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/task"
+)
+
+// prefContexts - контексты GTD, которые предлагаются при выборе
+const prefContexts = "contexts.list"
+
+// defaultContexts предлагаются, пока пользователь не задал свои
+var defaultContexts = []string{"@дом", "@работа", "@поручения"}
+
+// Варианты фильтра по контексту, кроме самих контекстов
+const (
+	contextAll  = "Все контексты"
+	contextNone = "Без контекста"
+)
+
+// contextChoices возвращает контексты для выбора: сначала из настроек,
+// затем те, что уже заданы задачам, но в список не входят
+func contextChoices(prefs fyne.Preferences, used []string) []string {
+	configured := prefs.StringListWithFallback(prefContexts, defaultContexts)
+	var choices []string
+	for _, context := range append(configured, used...) {
+		if context = task.NormalizeContext(context); context != "" && !slices.Contains(choices, context) {
+			choices = append(choices, context)
+		}
+	}
+	return choices
+}
+
+// contextGroup - следующие действия одного контекста
+type contextGroup struct {
+	Context string // пустая строка - задачи без контекста
+	Tasks   []*task.Task
+}
+
+// nextActions группирует активные задачи по контекстам. Контексты идут по алфавиту,
+// задачи без контекста - в конце. В группе задачи упорядочены по сроку, затем по приоритету
+func nextActions(tasks []*task.Task) []contextGroup {
+	byContext := make(map[string][]*task.Task)
+	for _, t := range tasks {
+		if !t.Completed && !t.Archived {
+			byContext[t.Context] = append(byContext[t.Context], t)
+		}
+	}
+
+	contexts := make([]string, 0, len(byContext))
+	for context := range byContext {
+		if context != "" {
+			contexts = append(contexts, context)
+		}
+	}
+	slices.Sort(contexts)
+	if _, ok := byContext[""]; ok {
+		contexts = append(contexts, "")
+	}
+
+	groups := make([]contextGroup, len(contexts))
+	for i, context := range contexts {
+		// По сроку, при одинаковом сроке - по приоритету; задачи без срока в конце
+		group := task.SortTasks(task.SortTasks(byContext[context], task.SortByPriority, false), task.SortByDueDate, false)
+		groups[i] = contextGroup{Context: context, Tasks: group}
+	}
+	return groups
+}
+
+// formatNextActions возвращает отчет о следующих действиях текстом
+func formatNextActions(groups []contextGroup) string {
+	if len(groups) == 0 {
+		return "Активных задач нет"
+	}
+	var b strings.Builder
+	for i, group := range groups {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		title := group.Context
+		if title == "" {
+			title = contextNone
+		}
+		fmt.Fprintf(&b, "%s (%d)\n", title, len(group.Tasks))
+		for _, t := range group.Tasks {
+			due := "без срока"
+			if !t.DueDate.IsZero() {
+				due = "до " + t.DueDate.Format("2006-01-02")
+			}
+			fmt.Fprintf(&b, "  • %s — %s, приоритет %s\n", t.Title, due, task.PriorityText(t.Priority))
+		}
+	}
+	return b.String()
+}
+
+// showNextActionsDialog показывает отчет «Следующие действия по контекстам»
+func showNextActionsDialog(a fyne.App, w fyne.Window, tm *task.TaskManager) {
+	report := formatNextActions(nextActions(tm.Tasks()))
+	copyButton := widget.NewButton("Копировать", func() {
+		a.Clipboard().SetContent(report)
+	})
+
+	content := container.NewBorder(nil, container.NewHBox(copyButton), nil, nil,
+		container.NewVScroll(widget.NewLabel(report)))
+	d := dialog.NewCustom("Следующие действия по контекстам", "Закрыть", content, w)
+	d.Resize(dialogSize(w, fyne.NewSize(560, 480)))
+	d.Show()
+}
@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+func TestContextChoices(t *testing.T) {
+	a := test.NewTempApp(t)
+	prefs := a.Preferences()
+	assert.Equal(t, []string{"@дом", "@работа", "@поручения", "@дача"}, contextChoices(prefs, []string{"@работа", "@дача"}))
+
+	// Контексты из настроек дополняются @
+	prefs.SetStringList(prefContexts, []string{"телефон", "@офис"})
+	assert.Equal(t, []string{"@телефон", "@офис", "@дача"}, contextChoices(prefs, []string{"@дача"}))
+}
+
+func TestNextActions(t *testing.T) {
+	day := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	tasks := []*task.Task{
+		{Title: "Позвонить в банк", Priority: 1, DueDate: day, Context: "@работа"},
+		{Title: "Написать отчет", Priority: 3, DueDate: day, Context: "@работа"},
+		{Title: "Купить хлеб", Priority: 2, DueDate: day.AddDate(0, 0, 1), Context: "@поручения"},
+		{Title: "Подумать об отпуске", Priority: 2, DueDate: day},
+		{Title: "Сдать анализы", Priority: 2, DueDate: day, Context: "@поручения", Completed: true},
+		{Title: "Старый проект", Priority: 2, DueDate: day, Context: "@работа", Archived: true},
+	}
+
+	groups := nextActions(tasks)
+	if assert.Len(t, groups, 3) {
+		assert.Equal(t, "@поручения", groups[0].Context)
+		assert.Len(t, groups[0].Tasks, 1)
+		// В группе задачи с одним сроком идут по приоритету
+		assert.Equal(t, "@работа", groups[1].Context)
+		assert.Equal(t, "Написать отчет", groups[1].Tasks[0].Title)
+		assert.Equal(t, "Позвонить в банк", groups[1].Tasks[1].Title)
+		assert.Equal(t, "", groups[2].Context)
+	}
+
+	report := formatNextActions(groups)
+	assert.Contains(t, report, "@работа (2)\n  • Написать отчет — до 2026-10-20, приоритет высокий\n")
+	assert.Contains(t, report, "Без контекста (1)")
+	assert.Equal(t, "Активных задач нет", formatNextActions(nil))
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"taskmanager/task"
+)
+
+// relativeDueInterval - как часто обновлять относительные сроки в списке задач
+const relativeDueInterval = time.Minute
+
+// pluralDays склоняет «день» после числа: 1 день, 3 дня, 5 дней
+func pluralDays(n int) string {
+	switch {
+	case n%100 >= 11 && n%100 <= 14:
+		return "дней"
+	case n%10 == 1:
+		return "день"
+	case n%10 >= 2 && n%10 <= 4:
+		return "дня"
+	}
+	return "дней"
+}
+
+// daysUntilDue возвращает, через сколько дней срок задачи; для прошедшего срока - отрицательное число.
+// now задается в поясе сроков
+func daysUntilDue(t *task.Task, now time.Time) int {
+	due, _ := time.Parse(task.DueDateLayout, t.DueDay())
+	today, _ := time.Parse(task.DueDateLayout, now.Format(task.DueDateLayout))
+	return int(due.Sub(today).Hours() / 24)
+}
+
+// formatRelativeDue показывает срок задачи относительно сегодняшнего дня: «завтра», «через 3 дня», «вчера»
+func formatRelativeDue(t *task.Task, now time.Time) string {
+	if t.DueDate.IsZero() {
+		return "без срока"
+	}
+	switch days := daysUntilDue(t, now); {
+	case days == 0:
+		return "сегодня"
+	case days == 1:
+		return "завтра"
+	case days == -1:
+		return "вчера"
+	case days > 0:
+		return fmt.Sprintf("через %d %s", days, pluralDays(days))
+	default:
+		return fmt.Sprintf("%d %s назад", -days, pluralDays(-days))
+	}
+}
+
+// formatDueDate показывает день срока задачи или «без срока»
+func formatDueDate(t *task.Task) string {
+	if t.DueDate.IsZero() {
+		return "без срока"
+	}
+	return t.DueDate.Format("2006-01-02")
+}
+
+// formatDueTooltip показывает точную дату срока для подсказки к относительному сроку
+func formatDueTooltip(t *task.Task) string {
+	if t.DueDate.IsZero() {
+		return ""
+	}
+	return "Срок: " + t.DueDate.Format("02.01.2006")
+}
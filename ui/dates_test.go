@@ -0,0 +1,29 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+func TestFormatRelativeDue(t *testing.T) {
+	now := time.Date(2026, 10, 16, 23, 30, 0, 0, time.UTC)
+	due := func(days int) *task.Task {
+		return &task.Task{DueDate: time.Date(2026, 10, 16+days, 0, 0, 0, 0, time.UTC)}
+	}
+	assert.Equal(t, "сегодня", formatRelativeDue(due(0), now))
+	assert.Equal(t, "завтра", formatRelativeDue(due(1), now))
+	assert.Equal(t, "вчера", formatRelativeDue(due(-1), now))
+	assert.Equal(t, "через 3 дня", formatRelativeDue(due(3), now))
+	assert.Equal(t, "через 5 дней", formatRelativeDue(due(5), now))
+	assert.Equal(t, "через 21 день", formatRelativeDue(due(21), now))
+	assert.Equal(t, "12 дней назад", formatRelativeDue(due(-12), now))
+	assert.Equal(t, "без срока", formatRelativeDue(&task.Task{}, now))
+
+	assert.Equal(t, "Срок: 19.10.2026", formatDueTooltip(due(3)))
+	assert.Equal(t, "2026-10-19", formatDueDate(due(3)))
+	assert.Equal(t, "без срока", formatDueDate(&task.Task{}))
+}
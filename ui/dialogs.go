@@ -0,0 +1,819 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/applog"
+	"taskmanager/goals"
+	"taskmanager/storage"
+	"taskmanager/task"
+)
+
+// formatTaskRow формирует строку задачи для списка. Срок показывается относительно дня now
+func formatTaskRow(t *task.Task, now time.Time) string {
+	status := " "
+	if t.Completed {
+		status = "✓"
+	}
+	row := fmt.Sprintf("[%s] %s (приоритет: %s, срок: %s",
+		status, t.Title, task.PriorityText(t.Priority), formatRelativeDue(t, now))
+	for _, detail := range taskDetails(t) {
+		row += ", " + detail
+	}
+	return row + ")"
+}
+
+// taskDetails возвращает подробности задачи для строки списка: исполнителя, контекст,
+// повторение, оценку, место, ссылку и архив
+func taskDetails(t *task.Task) []string {
+	var details []string
+	if t.Assignee != "" {
+		details = append(details, "исполнитель: "+t.Assignee)
+	}
+	if t.Context != "" {
+		details = append(details, t.Context)
+	}
+	if t.IsWaiting() {
+		details = append(details, "ждем: "+t.WaitingOn)
+	}
+	if t.Recurrence != task.RecurNone {
+		details = append(details, fmt.Sprintf("%s, серия: %d", t.Recurrence.Text(), t.Streak(time.Now())))
+	}
+	if t.EstimatedMinutes > 0 {
+		details = append(details, "~"+formatMinutes(t.EstimatedMinutes))
+	}
+	if t.Location != "" {
+		details = append(details, "📍 "+t.Location)
+	}
+	if t.LinkURL() != "" {
+		details = append(details, "🔗")
+	}
+	if t.Archived {
+		details = append(details, "в архиве")
+	}
+	return details
+}
+
+// defaultDueDate возвращает срок выполнения для новой задачи по умолчанию - завтрашний день в поясе сроков
+func defaultDueDate(zone task.DueZone) time.Time {
+	dueDate, _ := zone.ParseDueDate(zone.Now().AddDate(0, 0, 1).Format(task.DueDateLayout))
+	return dueDate
+}
+
+// newDueDateEntry создает поле срока с флажком «без срока»; флажок выключает поле.
+// У задачи без срока в поле подставляется завтрашний день на случай, если срок захотят задать
+func newDueDateEntry(zone task.DueZone, due time.Time) (*widget.Entry, *widget.Check, fyne.CanvasObject) {
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder("YYYY-MM-DD")
+	noDueCheck := widget.NewCheck("No due date", func(none bool) {
+		if none {
+			entry.Disable()
+		} else {
+			entry.Enable()
+		}
+	})
+	if due.IsZero() {
+		entry.SetText(defaultDueDate(zone).Format(task.DueDateLayout))
+		noDueCheck.SetChecked(true)
+	} else {
+		entry.SetText(due.Format(task.DueDateLayout))
+	}
+	return entry, noDueCheck, container.NewBorder(nil, nil, nil, noDueCheck, entry)
+}
+
+// parseDueDate разбирает срок из поля; noDue - задача без срока, она попадает в «Когда-нибудь»
+func parseDueDate(zone task.DueZone, text string, noDue bool) (time.Time, error) {
+	if noDue {
+		return time.Time{}, nil
+	}
+	due, err := zone.ParseDueDate(text)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date format, use YYYY-MM-DD")
+	}
+	return due, nil
+}
+
+// parseEstimate разбирает оценку трудоемкости в минутах; пустое поле - без оценки
+func parseEstimate(text string) (int, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, nil
+	}
+	minutes, err := strconv.Atoi(text)
+	if err != nil || minutes < 0 {
+		return 0, fmt.Errorf("invalid estimate, use a whole number of minutes")
+	}
+	return minutes, nil
+}
+
+// parseStartDate разбирает начало работы над задачей; пустое поле - без начала
+func parseStartDate(zone task.DueZone, text string) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return time.Time{}, nil
+	}
+	start, err := zone.ParseDueDate(text)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid start date format, use YYYY-MM-DD")
+	}
+	return start, nil
+}
+
+// formatStartDate показывает начало задачи для поля ввода
+func formatStartDate(t *task.Task) string {
+	if t.StartDate.IsZero() {
+		return ""
+	}
+	return t.StartDate.Format(task.DueDateLayout)
+}
+
+// Вспомогательные функции для диалоговых окон
+
+func showAddTaskDialog(w fyne.Window, tm *task.TaskManager, spelling *spellChecker, people, contexts []string, goalList []goals.Goal, due time.Time, openTask func(id int)) {
+	titleEntry := newSpellEntry(spelling, false)
+	// Похожая открытая задача, скорее всего, дубликат: ее можно открыть вместо добавления
+	var form dialog.Dialog
+	similarNotice, checkSimilar := newSimilarNotice(tm, func(id int) {
+		form.Hide()
+		openTask(id)
+	})
+	titleEntry.OnChanged = checkSimilar
+	descEntry := newSpellEntry(spelling, true)
+	prioritySelect := widget.NewSelect([]string{"Low (1)", "Medium (2)", "High (3)"}, nil)
+	prioritySelect.SetSelected("Medium (2)")
+
+	dueDateEntry, noDueCheck, dueDateRow := newDueDateEntry(tm.DueZone(), due)
+	startDateEntry := widget.NewEntry()
+	startDateEntry.SetPlaceHolder("YYYY-MM-DD")
+
+	tagsEntry := widget.NewEntry()
+	tagsEntry.SetPlaceHolder("work, home")
+
+	// Исполнителя можно выбрать из списка людей или вписать
+	assigneeEntry := widget.NewSelectEntry(people)
+
+	// Задача в ожидании: от кого ждем ответа
+	waitingEntry := widget.NewSelectEntry(people)
+
+	// Контекст GTD: где можно выполнить задачу
+	contextEntry := widget.NewSelectEntry(contexts)
+	contextEntry.SetPlaceHolder("@home")
+
+	// Цель на квартал или месяц, к которой ведет задача
+	goalSelect := newGoalSelect(goalList, "")
+
+	recurrenceSelect := newRecurrenceSelect(task.RecurNone)
+
+	urlEntry := widget.NewEntry()
+	urlEntry.SetPlaceHolder("https://")
+
+	// Место: адрес или координаты «55.75, 37.62»
+	locationEntry := widget.NewSelectEntry(tm.Locations())
+
+	reminderEntry := widget.NewEntry()
+	reminderEntry.SetPlaceHolder("YYYY-MM-DD HH:MM")
+	reminderOffsetsCheck := newReminderOffsetsCheck(nil)
+
+	estimateEntry := widget.NewEntry()
+	estimateEntry.SetPlaceHolder("30")
+
+	// Задачи, без которых нельзя начать эту: «#3, 7»
+	dependsEntry := widget.NewEntry()
+	dependsEntry.SetPlaceHolder("#3, 7")
+
+	formItems := []*widget.FormItem{
+		{Text: "Title", Widget: container.NewVBox(titleEntry, similarNotice)},
+		{Text: "Description", Widget: newMarkdownEditor(w, descEntry)},
+		{Text: "Priority", Widget: prioritySelect},
+		{Text: "Due Date (YYYY-MM-DD)", Widget: dueDateRow},
+		{Text: "Start Date", Widget: startDateEntry},
+		{Text: "Tags", Widget: tagsEntry},
+		{Text: "Assignee", Widget: assigneeEntry},
+		{Text: "Waiting on", Widget: waitingEntry},
+		{Text: "Context", Widget: contextEntry},
+		{Text: "Goal", Widget: goalSelect},
+		{Text: "Repeat", Widget: recurrenceSelect},
+		{Text: "Estimate (min)", Widget: estimateEntry},
+		{Text: "Depends on", Widget: dependsEntry},
+		{Text: "URL", Widget: urlEntry},
+		{Text: "Location", Widget: locationEntry},
+		{Text: "Remind at", Widget: reminderEntry},
+		{Text: "Reminders", Widget: reminderOffsetsCheck},
+	}
+
+	// Диалог открывается с фокусом на названии, Tab ведет по полям сверху вниз
+	form = dialog.NewForm("Add New Task", "Add", "Cancel", formItems, func(confirmed bool) {
+		if confirmed {
+			// Парсим приоритет
+			priority := 2
+			switch prioritySelect.Selected {
+			case "Low (1)":
+				priority = 1
+			case "Medium (2)":
+				priority = 2
+			case "High (3)":
+				priority = 3
+			}
+
+			// Парсим дату
+			dueDate, err := parseDueDate(tm.DueZone(), dueDateEntry.Text, noDueCheck.Checked)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			startDate, err := parseStartDate(tm.DueZone(), startDateEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			estimate, err := parseEstimate(estimateEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			dependsOn, err := parseTaskRefs(dependsEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			link := strings.TrimSpace(urlEntry.Text)
+			if link != "" {
+				if err := task.ValidateURL(link); err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+			}
+			remindAt, err := parseReminder(reminderEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			// Добавляем задачу
+			added, err := tm.AddTask(titleEntry.Text, descEntry.Text, priority, dueDate)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			tm.SetTaskTags(added.ID, task.ParseTags(tagsEntry.Text))
+			if !startDate.IsZero() {
+				if err := tm.SetTaskDates(added.ID, startDate, dueDate); err != nil {
+					dialog.ShowError(err, w)
+				}
+			}
+			if assigneeEntry.Text != "" {
+				tm.SetTaskAssignee(added.ID, assigneeEntry.Text)
+			}
+			if waitingEntry.Text != "" {
+				tm.SetTaskWaitingOn(added.ID, waitingEntry.Text)
+			}
+			if contextEntry.Text != "" {
+				tm.SetTaskContext(added.ID, contextEntry.Text)
+			}
+			if goal := selectedGoal(goalSelect, goalList); goal != "" {
+				tm.SetTaskGoal(added.ID, goal)
+			}
+			if recurrence := selectedRecurrence(recurrenceSelect); recurrence != task.RecurNone {
+				tm.SetTaskRecurrence(added.ID, recurrence)
+			}
+			if estimate > 0 {
+				tm.SetTaskEstimate(added.ID, estimate)
+			}
+			if len(dependsOn) > 0 {
+				if err := tm.SetTaskDependencies(added.ID, dependsOn); err != nil {
+					dialog.ShowError(err, w)
+				}
+			}
+			if link != "" {
+				tm.SetTaskURL(added.ID, link)
+			}
+			if locationEntry.Text != "" {
+				tm.SetTaskLocation(added.ID, locationEntry.Text)
+			}
+			if !remindAt.IsZero() {
+				tm.SetTaskReminder(added.ID, remindAt)
+			}
+			if offsets := selectedReminderOffsets(reminderOffsetsCheck, nil); len(offsets) > 0 {
+				tm.SetTaskReminderOffsets(added.ID, offsets)
+			}
+		}
+	}, w)
+	form.Resize(dialogSize(w, fyne.Size{}))
+	form.Show()
+	w.Canvas().Focus(titleEntry)
+}
+
+func showEditTaskDialog(w fyne.Window, tm *task.TaskManager, spelling *spellChecker, t *task.Task, people, contexts []string, goalList []goals.Goal) {
+	titleEntry := newSpellEntry(spelling, false)
+	titleEntry.SetText(t.Title)
+
+	descEntry := newSpellEntry(spelling, true)
+	descEntry.SetText(t.Description)
+
+	// Значок перед названием помогает найти задачу в длинном списке
+	iconPicker := newIconPicker(w, t.Icon)
+
+	prioritySelect := widget.NewSelect([]string{"Low (1)", "Medium (2)", "High (3)"}, nil)
+	switch t.Priority {
+	case 1:
+		prioritySelect.SetSelected("Low (1)")
+	case 2:
+		prioritySelect.SetSelected("Medium (2)")
+	case 3:
+		prioritySelect.SetSelected("High (3)")
+	}
+
+	dueDateEntry, noDueCheck, dueDateRow := newDueDateEntry(tm.DueZone(), t.DueDate)
+	startDateEntry := widget.NewEntry()
+	startDateEntry.SetPlaceHolder("YYYY-MM-DD")
+	startDateEntry.SetText(formatStartDate(t))
+
+	tagsEntry := widget.NewEntry()
+	tagsEntry.SetText(strings.Join(t.Tags, ", "))
+
+	assigneeEntry := widget.NewSelectEntry(people)
+	assigneeEntry.SetText(t.Assignee)
+
+	waitingEntry := widget.NewSelectEntry(people)
+	waitingEntry.SetText(t.WaitingOn)
+
+	contextEntry := widget.NewSelectEntry(contexts)
+	contextEntry.SetText(t.Context)
+
+	goalSelect := newGoalSelect(goalList, t.Goal)
+
+	recurrenceSelect := newRecurrenceSelect(t.Recurrence)
+
+	progressSlider, progressRow := newProgressSlider(t)
+
+	urlEntry := widget.NewEntry()
+	urlEntry.SetPlaceHolder("https://")
+	urlEntry.SetText(t.URL)
+
+	locationEntry := widget.NewSelectEntry(tm.Locations())
+	locationEntry.SetText(t.Location)
+
+	reminderEntry := widget.NewEntry()
+	reminderEntry.SetPlaceHolder("YYYY-MM-DD HH:MM")
+	reminderEntry.SetText(formatReminderTime(t.RemindAt))
+	reminderOffsetsCheck := newReminderOffsetsCheck(t.ReminderOffsets)
+	// Как напоминать именно об этой задаче: вместо настроек для ее приоритета
+	urgencySelect := newUrgencySelect(t.Urgency, true)
+	soundEntry := widget.NewEntry()
+	soundEntry.SetPlaceHolder("Звук из настроек")
+	soundEntry.SetText(t.Sound)
+
+	estimateEntry := widget.NewEntry()
+	if t.EstimatedMinutes > 0 {
+		estimateEntry.SetText(strconv.Itoa(t.EstimatedMinutes))
+	}
+
+	dependsEntry := widget.NewEntry()
+	dependsEntry.SetPlaceHolder("#3, 7")
+	currentDeps := formatTaskRefs(tm.Dependencies(t.ID))
+	dependsEntry.SetText(currentDeps)
+
+	completedCheck := widget.NewCheck("Completed", nil)
+	completedCheck.SetChecked(t.Completed)
+	archivedCheck := widget.NewCheck("Archived", nil)
+	archivedCheck.SetChecked(t.Archived)
+
+	formItems := []*widget.FormItem{
+		{Text: "Title", Widget: titleEntry},
+		{Text: "Icon", Widget: iconPicker},
+		{Text: "Description", Widget: newMarkdownEditor(w, descEntry)},
+		{Text: "Priority", Widget: prioritySelect},
+		{Text: "Due Date (YYYY-MM-DD)", Widget: dueDateRow},
+		{Text: "Start Date", Widget: startDateEntry},
+		{Text: "Tags", Widget: tagsEntry},
+		{Text: "Assignee", Widget: assigneeEntry},
+		{Text: "Waiting on", Widget: waitingEntry},
+		{Text: "Context", Widget: contextEntry},
+		{Text: "Goal", Widget: goalSelect},
+		{Text: "Repeat", Widget: recurrenceSelect},
+		{Text: "Estimate (min)", Widget: estimateEntry},
+		{Text: "Depends on", Widget: dependsEntry},
+		{Text: "Progress", Widget: progressRow},
+		{Text: "URL", Widget: urlEntry},
+		{Text: "Location", Widget: locationEntry},
+		{Text: "Remind at", Widget: reminderEntry},
+		{Text: "Reminders", Widget: reminderOffsetsCheck},
+		{Text: "Alert", Widget: container.NewGridWithColumns(2, urgencySelect, soundEntry)},
+		{Text: "Status", Widget: completedCheck},
+		{Text: "", Widget: archivedCheck},
+	}
+
+	form := dialog.NewForm("Edit Task", "Save", "Cancel", formItems, func(confirmed bool) {
+		if confirmed {
+			// Парсим приоритет
+			priority := 2
+			switch prioritySelect.Selected {
+			case "Low (1)":
+				priority = 1
+			case "Medium (2)":
+				priority = 2
+			case "High (3)":
+				priority = 3
+			}
+
+			// Парсим дату
+			dueDate, err := parseDueDate(tm.DueZone(), dueDateEntry.Text, noDueCheck.Checked)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			startDate, err := parseStartDate(tm.DueZone(), startDateEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			estimate, err := parseEstimate(estimateEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			dependsOn, err := parseTaskRefs(dependsEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			link := strings.TrimSpace(urlEntry.Text)
+			if link != "" {
+				if err := task.ValidateURL(link); err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+			}
+			remindAt, err := parseReminder(reminderEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			// Обновляем задачу
+			if err := tm.UpdateTask(t.ID, titleEntry.Text, descEntry.Text, priority, dueDate, completedCheck.Checked); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			tm.SetTaskTags(t.ID, task.ParseTags(tagsEntry.Text))
+			if !startDate.Equal(t.StartDate) {
+				if err := tm.SetTaskDates(t.ID, startDate, dueDate); err != nil {
+					dialog.ShowError(err, w)
+				}
+			}
+			tm.SetTaskAssignee(t.ID, assigneeEntry.Text)
+			tm.SetTaskWaitingOn(t.ID, waitingEntry.Text)
+			tm.SetTaskContext(t.ID, contextEntry.Text)
+			tm.SetTaskGoal(t.ID, selectedGoal(goalSelect, goalList))
+			tm.SetTaskIcon(t.ID, iconPicker.Icon)
+			if recurrence := selectedRecurrence(recurrenceSelect); recurrence != t.Recurrence {
+				tm.SetTaskRecurrence(t.ID, recurrence)
+			}
+			if estimate != t.EstimatedMinutes {
+				tm.SetTaskEstimate(t.ID, estimate)
+			}
+			if dependsEntry.Text != currentDeps {
+				if err := tm.SetTaskDependencies(t.ID, dependsOn); err != nil {
+					dialog.ShowError(err, w)
+				}
+			}
+			if link != t.URL {
+				tm.SetTaskURL(t.ID, link)
+			}
+			if locationEntry.Text != t.Location {
+				tm.SetTaskLocation(t.ID, locationEntry.Text)
+			}
+			if !remindAt.Equal(t.RemindAt) {
+				tm.SetTaskReminder(t.ID, remindAt)
+			}
+			if offsets := selectedReminderOffsets(reminderOffsetsCheck, t.ReminderOffsets); !slices.Equal(offsets, t.ReminderOffsets) {
+				tm.SetTaskReminderOffsets(t.ID, offsets)
+			}
+			if err := tm.SetTaskAlert(t.ID, selectedUrgency(urgencySelect), soundEntry.Text); err != nil {
+				dialog.ShowError(err, w)
+			}
+			if progress := int(progressSlider.Value); len(t.Checklist) == 0 && progress != t.Progress {
+				tm.SetTaskProgress(t.ID, progress)
+			}
+			if archivedCheck.Checked != t.Archived {
+				tm.SetTaskArchived(t.ID, archivedCheck.Checked)
+			}
+		}
+	}, w)
+	form.Resize(dialogSize(w, fyne.Size{}))
+	form.Show()
+	w.Canvas().Focus(titleEntry)
+}
+
+// showRestoreBackupDialog показывает резервные копии с предпросмотром и восстанавливает выбранную
+func showRestoreBackupDialog(w fyne.Window, tm *task.TaskManager) {
+	backups, err := tm.ListBackups()
+	if err != nil {
+		dialog.ShowError(err, w)
+		return
+	}
+	if len(backups) == 0 {
+		dialog.ShowInformation("Резервные копии", "Резервных копий пока нет", w)
+		return
+	}
+
+	var preview []*task.Task
+	selected := -1
+
+	previewList := widget.NewList(
+		func() int { return len(preview) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			item.(*widget.Label).SetText(formatTaskRow(preview[id], tm.DueZone().Now()))
+		},
+	)
+
+	backupList := widget.NewList(
+		func() int { return len(backups) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			backup := backups[id]
+			text := fmt.Sprintf("%s (задач: %d)", backup.Time.Format("2006-01-02 15:04:05"), backup.TaskCount)
+			if backup.TaskCount < 0 {
+				text = fmt.Sprintf("%s (файл поврежден)", backup.Time.Format("2006-01-02 15:04:05"))
+			}
+			item.(*widget.Label).SetText(text)
+		},
+	)
+	backupList.OnSelected = func(id widget.ListItemID) {
+		selected = id
+		preview, err = tm.LoadBackup(backups[id].Path)
+		if err != nil {
+			preview = nil
+		}
+		previewList.Refresh()
+	}
+
+	content := container.NewHSplit(backupList, previewList)
+	content.Offset = 0.35
+
+	d := dialog.NewCustomConfirm("Восстановление из резервной копии", "Восстановить", "Отмена", content, func(confirmed bool) {
+		if !confirmed || selected < 0 {
+			return
+		}
+
+		if err := tm.RestoreBackup(backups[selected].Path); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		dialog.ShowInformation("Успешно", "Задачи восстановлены. Сохраните их, чтобы перезаписать файл", w)
+	}, w)
+	d.Resize(dialogSize(w, fyne.NewSize(760, 420)))
+	d.Show()
+}
+
+// saveTasks сохраняет задачи в файл. Если файл тем временем изменила другая программа,
+// предлагает слить изменения, перезаписать файл или загрузить новую версию
+func saveTasks(w fyne.Window, tm *task.TaskManager, onSaved func()) {
+	err := tm.SaveToFile()
+	if err == nil {
+		onSaved()
+		return
+	}
+	if !errors.Is(err, task.ErrFileChanged) {
+		slog.Error("failed to save tasks", "err", err)
+		dialog.ShowError(err, w)
+		return
+	}
+	slog.Info("tasks file was changed by another program, asking before overwrite")
+
+	var d *dialog.CustomDialog
+	mergeButton := widget.NewButton("Слить", func() {
+		d.Hide()
+		result, err := tm.MergeFile()
+		if err != nil {
+			slog.Error("failed to merge changed tasks file", "err", err)
+			dialog.ShowError(err, w)
+			return
+		}
+		showMergeResult(w, tm, result, func() {
+			saveTasks(w, tm, onSaved)
+		})
+	})
+	mergeButton.Importance = widget.HighImportance
+	overwriteButton := widget.NewButton("Перезаписать", func() {
+		d.Hide()
+		if err := tm.ForceSaveToFile(); err != nil {
+			slog.Error("failed to save tasks", "err", err)
+			dialog.ShowError(err, w)
+			return
+		}
+		onSaved()
+	})
+	reloadButton := widget.NewButton("Загрузить с диска", func() {
+		d.Hide()
+		if err := tm.LoadFromFile(); err != nil {
+			dialog.ShowError(err, w)
+		}
+	})
+	cancelButton := widget.NewButton("Отмена", func() {
+		d.Hide()
+	})
+
+	d = dialog.NewCustomWithoutButtons("Файл изменен",
+		widget.NewLabel("Файл задач был изменен другой программой или компьютером после загрузки.\n"+
+			"Слить обе версии, перезаписать файл своими задачами (чужие изменения будут потеряны)\n"+
+			"или загрузить версию с диска (несохраненные изменения будут потеряны)?"), w)
+	d.SetButtons([]fyne.CanvasObject{cancelButton, reloadButton, overwriteButton, mergeButton})
+	d.Show()
+}
+
+// showFileChangedDialog предлагает загрузить версию файла задач, которую записала другая программа.
+// Если есть несохраненные изменения, их можно слить с этой версией. onClosed вызывается после ответа
+func showFileChangedDialog(w fyne.Window, tm *task.TaskManager, onClosed func()) {
+	reload := func() {
+		if err := tm.LoadFromFile(); err != nil {
+			slog.Error("failed to reload tasks", "err", err)
+			dialog.ShowError(err, w)
+		}
+	}
+	if !tm.IsDirty() {
+		dialog.ShowConfirm("Файл изменен", "Файл задач изменен другой программой. Загрузить новую версию?", func(confirmed bool) {
+			onClosed()
+			if confirmed {
+				reload()
+			}
+		}, w)
+		return
+	}
+
+	var d *dialog.CustomDialog
+	mergeButton := widget.NewButton("Слить", func() {
+		d.Hide()
+		onClosed()
+		result, err := tm.MergeFile()
+		if err != nil {
+			slog.Error("failed to merge changed tasks file", "err", err)
+			dialog.ShowError(err, w)
+			return
+		}
+		showMergeResult(w, tm, result, func() {})
+	})
+	mergeButton.Importance = widget.HighImportance
+	reloadButton := widget.NewButton("Загрузить", func() {
+		d.Hide()
+		onClosed()
+		reload()
+	})
+	laterButton := widget.NewButton("Позже", func() {
+		d.Hide()
+		onClosed()
+	})
+
+	d = dialog.NewCustomWithoutButtons("Файл изменен",
+		widget.NewLabel("Файл задач изменен другой программой или компьютером, а у вас есть несохраненные изменения.\n"+
+			"Слить обе версии или загрузить новую (несохраненные изменения будут потеряны)?"), w)
+	d.SetButtons([]fyne.CanvasObject{laterButton, reloadButton, mergeButton})
+	d.Show()
+}
+
+// showUnlockDialog запрашивает пароль зашифрованного файла задач до тех пор,
+// пока файл не будет открыт или пользователь не откажется
+func showUnlockDialog(w fyne.Window, tm *task.TaskManager, onUnlocked func(), onCancel func()) {
+	passphraseEntry := widget.NewPasswordEntry()
+	formItems := []*widget.FormItem{
+		{Text: "Пароль", Widget: passphraseEntry},
+	}
+
+	dialog.ShowForm("Файл задач зашифрован", "Открыть", "Выход", formItems, func(confirmed bool) {
+		if !confirmed {
+			onCancel()
+			return
+		}
+
+		if err := tm.Unlock(passphraseEntry.Text); err != nil {
+			errDialog := dialog.NewError(err, w)
+			errDialog.SetOnClosed(func() {
+				showUnlockDialog(w, tm, onUnlocked, onCancel)
+			})
+			errDialog.Show()
+			return
+		}
+		onUnlocked()
+	}, w)
+	w.Canvas().Focus(passphraseEntry)
+}
+
+// showEncryptionDialog включает, отключает шифрование файла задач или меняет пароль
+func showEncryptionDialog(w fyne.Window, tm *task.TaskManager) {
+	currentEntry := widget.NewPasswordEntry()
+	newEntry := widget.NewPasswordEntry()
+	confirmEntry := widget.NewPasswordEntry()
+
+	var formItems []*widget.FormItem
+	if tm.IsEncrypted() {
+		formItems = append(formItems, &widget.FormItem{Text: "Текущий пароль", Widget: currentEntry})
+	}
+	formItems = append(formItems,
+		&widget.FormItem{Text: "Новый пароль", Widget: newEntry, HintText: "Пустой пароль отключает шифрование"},
+		&widget.FormItem{Text: "Повтор пароля", Widget: confirmEntry},
+	)
+
+	// Фокус - на первом поле формы, чтобы пароль можно было вводить сразу
+	firstEntry := newEntry
+	if tm.IsEncrypted() {
+		firstEntry = currentEntry
+	}
+
+	dialog.ShowForm("Шифрование файла задач", "Применить", "Отмена", formItems, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		if tm.IsEncrypted() && !tm.CheckPassphrase(currentEntry.Text) {
+			dialog.ShowError(storage.ErrWrongPassphrase, w)
+			return
+		}
+		if newEntry.Text != confirmEntry.Text {
+			dialog.ShowError(fmt.Errorf("passphrases do not match"), w)
+			return
+		}
+		if !tm.IsEncrypted() && newEntry.Text == "" {
+			return // Шифрование и так отключено
+		}
+
+		if err := tm.SetPassphrase(newEntry.Text); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		saveTasks(w, tm, func() {
+			message := "Файл задач зашифрован"
+			if !tm.IsEncrypted() {
+				message = "Шифрование файла задач отключено"
+			}
+			dialog.ShowInformation("Шифрование", message, w)
+		})
+	}, w)
+	w.Canvas().Focus(firstEntry)
+}
+
+// showUnsavedChangesDialog предлагает сохранить изменения перед закрытием окна или сменой профиля.
+// onDone вызывается после сохранения или отказа от него
+func showUnsavedChangesDialog(w fyne.Window, tm *task.TaskManager, onDone func()) {
+	var d *dialog.CustomDialog
+
+	saveButton := widget.NewButton("Сохранить", func() {
+		d.Hide()
+		saveTasks(w, tm, onDone)
+	})
+	saveButton.Importance = widget.HighImportance
+	discardButton := widget.NewButton("Не сохранять", func() {
+		d.Hide()
+		onDone()
+	})
+	cancelButton := widget.NewButton("Отмена", func() {
+		d.Hide()
+	})
+
+	d = dialog.NewCustomWithoutButtons("Несохраненные изменения",
+		widget.NewLabel("Есть изменения, которые не сохранены в файл. Сохранить их?"), w)
+	d.SetButtons([]fyne.CanvasObject{cancelButton, discardButton, saveButton})
+	d.Show()
+}
+
+// logTailSize - сколько последних байт журнала показывать
+const logTailSize = 64 << 10
+
+// showLogDialog показывает конец файла журнала, чтобы его можно было скопировать в отчет об ошибке
+func showLogDialog(w fyne.Window, path string) {
+	text, err := applog.Tail(path, logTailSize)
+	if err != nil {
+		dialog.ShowError(err, w)
+		return
+	}
+
+	logEntry := widget.NewMultiLineEntry()
+	logEntry.SetText(text)
+	logEntry.Wrapping = fyne.TextWrapOff
+	logEntry.TextStyle = fyne.TextStyle{Monospace: true}
+	logEntry.CursorRow = len(strings.Split(text, "\n")) // Прокручиваем к последним записям
+
+	copyButton := widget.NewButton("Копировать", func() {
+		fyne.CurrentApp().Clipboard().SetContent(logEntry.Text)
+	})
+	pathLabel := widget.NewLabel(path)
+	pathLabel.Truncation = fyne.TextTruncateEllipsis
+
+	content := container.NewBorder(nil, container.NewBorder(nil, nil, nil, copyButton, pathLabel), nil, nil, logEntry)
+	d := dialog.NewCustom("Журнал", "Закрыть", content, w)
+	d.Resize(dialogSize(w, fyne.NewSize(860, 520)))
+	d.Show()
+}
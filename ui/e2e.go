@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"log/slog"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/storage"
+)
+
+// loadKeyring читает ключи сквозного шифрования профиля
+func loadKeyring(prefs fyne.Preferences) *storage.Keyring {
+	keys, err := storage.ParseKeyring(prefs.String(prefE2EKeyring))
+	if err != nil {
+		slog.Error("failed to read end-to-end encryption keys", "err", err)
+		return &storage.Keyring{}
+	}
+	return keys
+}
+
+// saveKeyring сохраняет ключи сквозного шифрования профиля
+func saveKeyring(prefs fyne.Preferences, keys *storage.Keyring) {
+	prefs.SetString(prefE2EKeyring, keys.Marshal())
+}
+
+// showRecoveryPhraseDialog показывает фразу восстановления текущего ключа сквозного шифрования
+func showRecoveryPhraseDialog(w fyne.Window, prefs fyne.Preferences, keys *storage.Keyring, onClosed func()) {
+	phraseLabel := widget.NewLabelWithStyle(keys.RecoveryPhrase(), fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
+	phraseLabel.Selectable = true
+	copyButton := widget.NewButton("Копировать", func() {
+		fyne.CurrentApp().Clipboard().SetContent(keys.RecoveryPhrase())
+	})
+
+	content := container.NewVBox(
+		widget.NewLabel("Задачи шифруются на этом устройстве, сервер не видит их содержимого.\n"+
+			"Запишите фразу восстановления и храните ее в надежном месте: она нужна,\n"+
+			"чтобы открыть задачи на другом устройстве или после переустановки.\n"+
+			"Без нее задачи на сервере восстановить нельзя."),
+		container.NewBorder(nil, nil, nil, copyButton, phraseLabel),
+	)
+	d := dialog.NewCustom("Фраза восстановления", "Фраза записана", content, w)
+	d.SetOnClosed(func() {
+		prefs.SetBool(prefE2EPhraseShown, true)
+		onClosed()
+	})
+	d.Resize(dialogSize(w, fyne.NewSize(560, 0)))
+	d.Show()
+}
+
+// showImportPhraseDialog запрашивает фразу восстановления ключа, созданного на другом устройстве,
+// до тех пор, пока фраза не будет введена без ошибок или пользователь не откажется
+func showImportPhraseDialog(w fyne.Window, prefs fyne.Preferences, keys *storage.Keyring, onImported func(), onCancel func()) {
+	phraseEntry := widget.NewEntry()
+	phraseEntry.SetPlaceHolder("XXXX-XXXX-…")
+	formItems := []*widget.FormItem{
+		{Text: "Фраза", Widget: phraseEntry, HintText: "Показывается на устройстве, где создан ключ: Файл → Сквозное шифрование"},
+	}
+
+	dialog.ShowForm("Задачи зашифрованы другим ключом", "Применить", "Отмена", formItems, func(confirmed bool) {
+		if !confirmed {
+			onCancel()
+			return
+		}
+		if err := keys.Import(phraseEntry.Text); err != nil {
+			errDialog := dialog.NewError(err, w)
+			errDialog.SetOnClosed(func() {
+				showImportPhraseDialog(w, prefs, keys, onImported, onCancel)
+			})
+			errDialog.Show()
+			return
+		}
+		saveKeyring(prefs, keys)
+		prefs.SetBool(prefE2EPhraseShown, true) // Фраза уже есть у пользователя
+		slog.Info("end-to-end encryption key imported", "key", keys.CurrentID())
+		onImported()
+	}, w)
+}
+
+// showKeySetupDialog настраивает сквозное шифрование перед первой синхронизацией:
+// создает новый ключ или принимает фразу ключа, уже созданного на другом устройстве
+func showKeySetupDialog(w fyne.Window, prefs fyne.Preferences, keys *storage.Keyring, onReady func()) {
+	message := widget.NewLabel("Задачи шифруются до отправки на сервер.\n" +
+		"Если синхронизация уже настроена на другом устройстве, введите фразу восстановления его ключа.\n" +
+		"Иначе создайте новый ключ.")
+	dialog.ShowCustomConfirm("Сквозное шифрование", "Создать ключ", "Ввести фразу", message, func(create bool) {
+		if !create {
+			showImportPhraseDialog(w, prefs, keys, onReady, func() {})
+			return
+		}
+		if err := keys.Generate(); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		saveKeyring(prefs, keys)
+		slog.Info("end-to-end encryption key created", "key", keys.CurrentID())
+		showRecoveryPhraseDialog(w, prefs, keys, onReady)
+	}, w)
+}
+
+// showEndToEndDialog показывает ключ сквозного шифрования и позволяет сменить его.
+// onRotated вызывается после смены или ввода ключа, чтобы перешифровать данные на сервере
+func showEndToEndDialog(w fyne.Window, prefs fyne.Preferences, keys *storage.Keyring, onRotated func()) {
+	var d dialog.Dialog
+	status := "Ключ еще не создан: он появится при первой синхронизации или подключении удаленного хранилища."
+	if !keys.Empty() {
+		status = "Текущий ключ: " + keys.CurrentID()
+	}
+
+	showPhraseButton := widget.NewButton("Показать фразу восстановления", func() {
+		d.Hide()
+		showRecoveryPhraseDialog(w, prefs, keys, func() {})
+	})
+	importButton := widget.NewButton("Ввести фразу…", func() {
+		d.Hide()
+		showImportPhraseDialog(w, prefs, keys, onRotated, func() {})
+	})
+	rotateButton := widget.NewButton("Сменить ключ…", func() {
+		d.Hide()
+		dialog.ShowConfirm("Сменить ключ",
+			"Задачи будут перешифрованы новым ключом при следующем сохранении и синхронизации.\n"+
+				"На остальных устройствах нужно будет ввести новую фразу восстановления. Продолжить?", func(confirmed bool) {
+				if !confirmed {
+					return
+				}
+				if err := keys.Generate(); err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				saveKeyring(prefs, keys)
+				slog.Info("end-to-end encryption key rotated", "key", keys.CurrentID())
+				showRecoveryPhraseDialog(w, prefs, keys, onRotated)
+			}, w)
+	})
+	if keys.Empty() {
+		showPhraseButton.Disable()
+		rotateButton.Disable()
+	}
+
+	d = dialog.NewCustom("Сквозное шифрование", "Закрыть", container.NewVBox(
+		widget.NewLabel(status),
+		showPhraseButton,
+		importButton,
+		rotateButton,
+	), w)
+	d.Show()
+}
@@ -0,0 +1,193 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/agenda"
+	"taskmanager/email"
+	"taskmanager/task"
+)
+
+// Настройки повестки на почту
+const (
+	prefEmailEnabled  = "email.enabled"
+	prefEmailHost     = "email.host"
+	prefEmailPort     = "email.port"
+	prefEmailUsername = "email.username"
+	prefEmailPassword = "email.password"
+	prefEmailFrom     = "email.from"
+	prefEmailTo       = "email.to"
+	prefEmailAt       = "email.at"
+	prefEmailSent     = "email.sent" // день последней отправленной повестки
+)
+
+// emailSendTimeout - сколько ждать сервер SMTP
+const emailSendTimeout = time.Minute
+
+// emailHost отправляет повестку дня письмом по утрам
+type emailHost struct {
+	prefs fyne.Preferences
+	tm    *task.TaskManager
+}
+
+func newEmailHost(prefs fyne.Preferences, tm *task.TaskManager) *emailHost {
+	return &emailHost{prefs: prefs, tm: tm}
+}
+
+// config читает сервер и адреса из настроек
+func (h *emailHost) config() email.Config {
+	return email.Config{
+		Host:     h.prefs.String(prefEmailHost),
+		Port:     h.prefs.IntWithFallback(prefEmailPort, 587),
+		Username: h.prefs.String(prefEmailUsername),
+		Password: h.prefs.String(prefEmailPassword),
+		From:     h.prefs.String(prefEmailFrom),
+		To:       email.ParseAddresses(h.prefs.String(prefEmailTo)),
+	}
+}
+
+// Check отправляет в фоне повестку, если пришло ее время. Неотправленное письмо
+// не повторяется, как и уведомления в чаты: ошибка остается в журнале
+func (h *emailHost) Check() {
+	if !h.prefs.Bool(prefEmailEnabled) {
+		return
+	}
+	now := h.tm.DueZone().Now()
+	if !email.Due(now, h.prefs.StringWithFallback(prefEmailAt, "07:00"), h.prefs.String(prefEmailSent)) {
+		return
+	}
+	h.prefs.SetString(prefEmailSent, now.Format(task.DueDateLayout))
+	cfg := h.config()
+	message, err := email.AgendaMessage(cfg, agenda.Build(h.tm.Tasks(), now, email.Sections), now)
+	if err != nil {
+		slog.Error("failed to compose agenda email", "err", err)
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), emailSendTimeout)
+		defer cancel()
+		if err := email.Send(ctx, cfg, message); err != nil {
+			slog.Error("failed to send agenda email", "server", cfg.Host, "err", err)
+			return
+		}
+		slog.Info("agenda email sent", "to", strings.Join(cfg.To, ", "))
+	}()
+}
+
+// showEmailDialog настраивает сервер SMTP и время утреннего письма с повесткой
+func showEmailDialog(w fyne.Window, prefs fyne.Preferences, tm *task.TaskManager, host *emailHost) {
+	cfg := host.config()
+	enabledCheck := widget.NewCheck("Отправлять повестку каждое утро", nil)
+	enabledCheck.SetChecked(prefs.Bool(prefEmailEnabled))
+	atEntry := widget.NewEntry()
+	atEntry.SetPlaceHolder("07:00")
+	atEntry.SetText(prefs.StringWithFallback(prefEmailAt, "07:00"))
+	atEntry.Validator = func(text string) error {
+		if _, err := time.Parse("15:04", strings.TrimSpace(text)); err != nil {
+			return errors.New("time must look like 07:00")
+		}
+		return nil
+	}
+	hostEntry := widget.NewEntry()
+	hostEntry.SetPlaceHolder("smtp.example.com")
+	hostEntry.SetText(cfg.Host)
+	portEntry := widget.NewEntry()
+	portEntry.SetText(strconv.Itoa(cfg.Port))
+	portEntry.Validator = func(text string) error {
+		if port, err := strconv.Atoi(strings.TrimSpace(text)); err != nil || port <= 0 || port > 65535 {
+			return errors.New("port must be a number from 1 to 65535")
+		}
+		return nil
+	}
+	usernameEntry := widget.NewEntry()
+	usernameEntry.SetText(cfg.Username)
+	passwordEntry := widget.NewPasswordEntry()
+	passwordEntry.SetText(cfg.Password)
+	fromEntry := widget.NewEntry()
+	fromEntry.SetPlaceHolder("Задачи <tasks@example.com>")
+	fromEntry.SetText(cfg.From)
+	toEntry := widget.NewEntry()
+	toEntry.SetPlaceHolder("me@example.com")
+	toEntry.SetText(strings.Join(cfg.To, ", "))
+
+	read := func() email.Config {
+		port, _ := strconv.Atoi(strings.TrimSpace(portEntry.Text))
+		return email.Config{
+			Host:     strings.TrimSpace(hostEntry.Text),
+			Port:     port,
+			Username: strings.TrimSpace(usernameEntry.Text),
+			Password: passwordEntry.Text,
+			From:     strings.TrimSpace(fromEntry.Text),
+			To:       email.ParseAddresses(toEntry.Text),
+		}
+	}
+
+	testButton := widget.NewButton("Отправить пробное письмо", func() {
+		cfg := read()
+		if err := cfg.Validate(); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		now := tm.DueZone().Now()
+		message, err := email.AgendaMessage(cfg, agenda.Build(tm.Tasks(), now, email.Sections), now)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), emailSendTimeout)
+			defer cancel()
+			err := email.Send(ctx, cfg, message)
+			fyne.Do(func() {
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				dialog.ShowInformation("Повестка на почту", "Письмо отправлено", w)
+			})
+		}()
+	})
+
+	form := dialog.NewForm("Повестка на почту", "Сохранить", "Отмена", []*widget.FormItem{
+		{Text: "", Widget: enabledCheck},
+		{Text: "Время", Widget: atEntry, HintText: "Просроченные задачи, задачи на сегодня и на неделю вперед"},
+		{Text: "Сервер SMTP", Widget: hostEntry},
+		{Text: "Порт", Widget: portEntry, HintText: "587 - STARTTLS, 465 - TLS"},
+		{Text: "Пользователь", Widget: usernameEntry},
+		{Text: "Пароль", Widget: passwordEntry},
+		{Text: "От кого", Widget: fromEntry},
+		{Text: "Кому", Widget: toEntry, HintText: "Несколько адресов - через запятую"},
+		{Text: "", Widget: testButton},
+	}, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		cfg := read()
+		if enabledCheck.Checked {
+			if err := cfg.Validate(); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+		}
+		prefs.SetBool(prefEmailEnabled, enabledCheck.Checked)
+		prefs.SetString(prefEmailAt, strings.TrimSpace(atEntry.Text))
+		prefs.SetString(prefEmailHost, cfg.Host)
+		prefs.SetInt(prefEmailPort, cfg.Port)
+		prefs.SetString(prefEmailUsername, cfg.Username)
+		prefs.SetString(prefEmailPassword, cfg.Password)
+		prefs.SetString(prefEmailFrom, cfg.From)
+		prefs.SetString(prefEmailTo, strings.Join(cfg.To, ", "))
+		host.Check()
+	}, w)
+	form.Resize(dialogSize(w, fyne.NewSize(560, 0)))
+	form.Show()
+}
@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+func TestEmailHostCheck(t *testing.T) {
+	a := test.NewTempApp(t)
+	prefs := a.Preferences()
+	tm := newTestManager(t)
+	host := newEmailHost(prefs, tm)
+
+	prefs.SetString(prefEmailAt, "00:00")
+	host.Check()
+	assert.Empty(t, prefs.String(prefEmailSent), "disabled")
+
+	// Письмо уходит раз в день; сервер недоступен, и ошибка остается в журнале
+	prefs.SetBool(prefEmailEnabled, true)
+	prefs.SetString(prefEmailHost, "127.0.0.1")
+	prefs.SetInt(prefEmailPort, 1)
+	prefs.SetString(prefEmailFrom, "tasks@example.com")
+	prefs.SetString(prefEmailTo, "me@example.com")
+	host.Check()
+	assert.Equal(t, tm.DueZone().Now().Format(task.DueDateLayout), prefs.String(prefEmailSent))
+	assert.Equal(t, 1, host.config().Port)
+	assert.Equal(t, []string{"me@example.com"}, host.config().To)
+}
@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/report"
+	"taskmanager/task"
+)
+
+// estimatePeriods - периоды отчета об оценках; 0 дней - все время
+var estimatePeriods = []struct {
+	Title string
+	Days  int
+}{
+	{"Неделя", 7},
+	{"Месяц", 30},
+	{"Квартал", 90},
+	{"Все время", 0},
+}
+
+// estimateGroups - группировка строк отчета об оценках
+var estimateGroups = []struct {
+	Title   string
+	GroupBy report.GroupBy
+}{
+	{"По задачам", report.GroupByTask},
+	{"По меткам и проектам", report.GroupByTag},
+}
+
+// formatEstimateRow описывает строку отчета: «#работа: оценка 1 ч 30 мин, учтено 1 ч 50 мин, +20 мин (122%)»
+func formatEstimateRow(r report.EstimateRow) string {
+	text := fmt.Sprintf("%s: оценка %s, учтено %s", r.Name, formatMinutes(r.Estimated), formatMinutes(r.Tracked))
+	switch diff := r.Diff(); {
+	case diff > 0:
+		text += ", +" + formatMinutes(diff)
+	case diff < 0:
+		text += ", −" + formatMinutes(-diff)
+	}
+	if r.Estimated > 0 {
+		text += fmt.Sprintf(" (%.0f%%)", r.Ratio()*100)
+	}
+	return text
+}
+
+// showEstimatesDialog сравнивает оценки задач с учтенным таймером временем за выбранный
+// период, чтобы точнее оценивать следующие задачи. Отчет можно сохранить в CSV
+func showEstimatesDialog(w fyne.Window, tm *task.TaskManager) {
+	var rows []report.EstimateRow
+	var total report.EstimateRow
+
+	list := widget.NewList(
+		func() int { return len(rows) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(i widget.ListItemID, item fyne.CanvasObject) {
+			item.(*widget.Label).SetText(formatEstimateRow(rows[i]))
+		},
+	)
+	totalLabel := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+
+	periodTitles := make([]string, len(estimatePeriods))
+	for i, p := range estimatePeriods {
+		periodTitles[i] = p.Title
+	}
+	groupTitles := make([]string, len(estimateGroups))
+	for i, g := range estimateGroups {
+		groupTitles[i] = g.Title
+	}
+	periodSelect := widget.NewSelect(periodTitles, nil)
+	groupSelect := widget.NewSelect(groupTitles, nil)
+	rebuild := func() {
+		var from time.Time
+		if days := estimatePeriods[periodSelect.SelectedIndex()].Days; days > 0 {
+			from = time.Now().AddDate(0, 0, -days)
+		}
+		rows, total = report.Estimates(tm.Tasks(), from, time.Time{}, estimateGroups[groupSelect.SelectedIndex()].GroupBy)
+		totalLabel.SetText(fmt.Sprintf("%s, задач: %d", formatEstimateRow(total), total.Tasks))
+		list.Refresh()
+	}
+	periodSelect.SetSelectedIndex(1)
+	groupSelect.SetSelectedIndex(0)
+	periodSelect.OnChanged = func(string) { rebuild() }
+	groupSelect.OnChanged = func(string) { rebuild() }
+	rebuild()
+
+	saveButton := widget.NewButton("Сохранить CSV…", func() {
+		save := dialog.NewFileSave(func(file fyne.URIWriteCloser, err error) {
+			if err != nil || file == nil {
+				return
+			}
+			defer file.Close()
+			if err := report.WriteEstimatesCSV(file, rows, total); err != nil {
+				slog.Error("failed to save estimates report", "file", file.URI().String(), "err", err)
+				dialog.ShowError(err, w)
+			}
+		}, w)
+		save.SetFileName("estimates-" + time.Now().Format("2006-01-02") + ".csv")
+		save.Show()
+	})
+
+	hint := widget.NewLabel("Задачи с оценкой или учтенным временем: выполненные за период и измененные за период невыполненные")
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
+	content := container.NewBorder(
+		container.NewVBox(container.NewGridWithColumns(2, periodSelect, groupSelect), hint),
+		container.NewVBox(widget.NewSeparator(), totalLabel, container.NewHBox(saveButton)),
+		nil, nil, list)
+	d := dialog.NewCustom("Оценка и учтенное время", "Закрыть", content, w)
+	d.Resize(dialogSize(w, fyne.NewSize(640, 480)))
+	d.Show()
+}
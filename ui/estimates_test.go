@@ -0,0 +1,20 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/report"
+)
+
+func TestFormatEstimateRow(t *testing.T) {
+	assert.Equal(t, "#работа: оценка 1 ч 30 мин, учтено 1 ч 50 мин, +20 мин (122%)",
+		formatEstimateRow(report.EstimateRow{Name: "#работа", Estimated: 90, Tracked: 110}))
+	assert.Equal(t, "#1 Созвон: оценка 30 мин, учтено 20 мин, −10 мин (67%)",
+		formatEstimateRow(report.EstimateRow{Name: "#1 Созвон", Estimated: 30, Tracked: 20}))
+	assert.Equal(t, "без метки: оценка 0 мин, учтено 15 мин, +15 мин",
+		formatEstimateRow(report.EstimateRow{Name: "без метки", Tracked: 15}))
+	assert.Equal(t, "Итого: оценка 1 ч, учтено 1 ч (100%)",
+		formatEstimateRow(report.EstimateRow{Name: "Итого", Estimated: 60, Tracked: 60}))
+}
@@ -0,0 +1,506 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/plugins"
+	"taskmanager/task"
+)
+
+// prefPluginsDir - папка с программами-расширениями экспорта; пусто - только встроенные
+const prefPluginsDir = "plugins.dir"
+
+// newPluginRegistry создает реестр со встроенными расширениями и в фоне добавляет
+// программы-расширения из папки в настройках
+func newPluginRegistry(prefs fyne.Preferences) *plugins.Registry {
+	registry := plugins.Builtin()
+	dir := strings.TrimSpace(prefs.String(prefPluginsDir))
+	if dir == "" {
+		return registry
+	}
+	go func() {
+		external, errs := plugins.LoadExternal(context.Background(), dir)
+		for _, err := range errs {
+			slog.Error("failed to load plugin", "dir", dir, "err", err)
+		}
+		fyne.Do(func() {
+			for _, p := range external {
+				if err := registry.Register(p); err != nil {
+					slog.Error("failed to register plugin", "dir", dir, "err", err)
+				}
+			}
+		})
+	}()
+	return registry
+}
+
+// pluginOptionKey - ключ настройки расширения в настройках приложения
+func pluginOptionKey(p plugins.Plugin, key string) string {
+	return "plugins." + p.Name() + "." + key
+}
+
+// exportScopeTitles - что выгружать: все задачи или только видимые в списке
+func exportScopeTitles(visible int) []string {
+	return []string{"Все задачи", fmt.Sprintf("Видимые в списке (%d)", visible)}
+}
+
+// showExportDialog предлагает выбрать расширение экспорта и задачи - все или видимые
+// в списке с текущими поиском и фильтрами, заполнить настройки расширения и, если
+// расширение пишет файл, выбрать файл. Затем выгружает задачи в фоне.
+// format - расширение, выбранное сразу; пусто - первое по алфавиту
+func showExportDialog(w fyne.Window, prefs fyne.Preferences, tm *task.TaskManager, registry *plugins.Registry, visible []*task.Task, format string) {
+	var names []string
+	for _, p := range registry.Plugins() {
+		names = append(names, p.Name())
+	}
+	pluginSelect := widget.NewSelect(names, nil)
+	pluginSelect.SetSelected(names[0])
+	if registry.Get(format) != nil {
+		pluginSelect.SetSelected(format)
+	}
+	scopes := exportScopeTitles(len(visible))
+	scopeRadio := widget.NewRadioGroup(scopes, nil)
+	scopeRadio.Required = true
+	scopeRadio.SetSelected(scopes[0])
+
+	dialog.ShowForm("Экспорт", "Далее", "Отмена", []*widget.FormItem{
+		{Text: "Формат", Widget: pluginSelect, HintText: "Куда выгрузить задачи"},
+		{Text: "Задачи", Widget: scopeRadio, HintText: "Видимые - с текущими поиском и фильтрами"},
+	}, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		tasks := tm.Tasks()
+		if scopeRadio.Selected == scopes[1] {
+			tasks = visible
+		}
+		p := registry.Get(pluginSelect.Selected)
+		configure := configurePlugin
+		switch p.(type) {
+		case *plugins.CSV:
+			configure = configureCSV
+		case *plugins.JSON:
+			configure = configureJSON
+		}
+		configure(w, prefs, p, func() {
+			if _, ok := plugins.WritesFile(p); !ok {
+				runPlugin(w, p, plugins.Snapshot(tasks), nil)
+				return
+			}
+			choosePluginFile(w, p, func(file fyne.URIWriteCloser) {
+				runPlugin(w, p, plugins.Snapshot(tasks), file)
+			})
+		})
+	}, w)
+}
+
+// configurePlugin показывает настройки расширения, сохраняет их и передает расширению
+func configurePlugin(w fyne.Window, prefs fyne.Preferences, p plugins.Plugin, onDone func()) {
+	var options []plugins.Option
+	if configurable, ok := p.(plugins.Configurable); ok {
+		options = configurable.Options()
+	}
+	values := make(map[string]string)
+	if len(options) == 0 {
+		if err := p.Configure(values); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		onDone()
+		return
+	}
+
+	entries := make([]*widget.Entry, len(options))
+	formItems := make([]*widget.FormItem, len(options))
+	for i, option := range options {
+		if option.Secret {
+			entries[i] = widget.NewPasswordEntry()
+		} else {
+			entries[i] = widget.NewEntry()
+		}
+		entries[i].SetText(prefs.String(pluginOptionKey(p, option.Key)))
+		formItems[i] = &widget.FormItem{Text: option.Title, Widget: entries[i]}
+	}
+	dialog.ShowForm(p.Name(), "Далее", "Отмена", formItems, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		for i, option := range options {
+			values[option.Key] = strings.TrimSpace(entries[i].Text)
+			prefs.SetString(pluginOptionKey(p, option.Key), values[option.Key])
+		}
+		if err := p.Configure(values); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		onDone()
+	}, w)
+}
+
+// csvDelimiterOptions - разделители CSV: подпись и значение настройки
+var csvDelimiterOptions = []struct{ Title, Value string }{
+	{"Запятая", ","},
+	{"Точка с запятой", ";"},
+	{"Табуляция", "tab"},
+}
+
+// csvFilterOptions - какие задачи выгружать: подпись и значение настройки
+var csvFilterOptions = []struct {
+	Title  string
+	Filter task.CSVFilter
+}{
+	{"Все задачи", task.CSVFilterAll},
+	{"Только невыполненные", task.CSVFilterOpen},
+	{"Со сроком в диапазоне", task.CSVFilterDueRange},
+}
+
+// csvBOMTitle - кодировка с меткой UTF-8, которую Excel узнает сам
+const csvBOMTitle = "UTF-8 с BOM (для Excel)"
+
+// configureCSV показывает настройки выгрузки в CSV: разделитель, кодировку, колонки и отбор
+// задач. Выбор сохраняется в настройках приложения, как у других расширений
+func configureCSV(w fyne.Window, prefs fyne.Preferences, p plugins.Plugin, onDone func()) {
+	value := func(key string) string { return prefs.String(pluginOptionKey(p, key)) }
+
+	delimiterTitles := make([]string, len(csvDelimiterOptions))
+	for i, option := range csvDelimiterOptions {
+		delimiterTitles[i] = option.Title
+	}
+	delimiterSelect := widget.NewSelect(delimiterTitles, nil)
+	delimiterSelect.SetSelected(delimiterTitles[0])
+	for _, option := range csvDelimiterOptions {
+		if option.Value == value(plugins.CSVDelimiter) {
+			delimiterSelect.SetSelected(option.Title)
+		}
+	}
+
+	encodingSelect := widget.NewSelect([]string{"UTF-8", csvBOMTitle}, nil)
+	encodingSelect.SetSelected("UTF-8")
+	if value(plugins.CSVBOM) == "yes" {
+		encodingSelect.SetSelected(csvBOMTitle)
+	}
+
+	columnTitles := make([]string, len(task.CSVColumns))
+	var selected []string
+	saved := strings.Split(value(plugins.CSVColumns), ",")
+	for i, column := range task.CSVColumns {
+		columnTitles[i] = column.Header
+		if value(plugins.CSVColumns) == "" || slices.Contains(saved, column.Key) {
+			selected = append(selected, column.Header)
+		}
+	}
+	columnsCheck := widget.NewCheckGroup(columnTitles, nil)
+	columnsCheck.Horizontal = true
+	columnsCheck.SetSelected(selected)
+
+	fromEntry, toEntry := widget.NewEntry(), widget.NewEntry()
+	fromEntry.SetPlaceHolder("YYYY-MM-DD")
+	toEntry.SetPlaceHolder("YYYY-MM-DD")
+	fromEntry.SetText(value(plugins.CSVFrom))
+	toEntry.SetText(value(plugins.CSVTo))
+	filterTitles := make([]string, len(csvFilterOptions))
+	for i, option := range csvFilterOptions {
+		filterTitles[i] = option.Title
+	}
+	// Диапазон сроков нужен только для отбора по сроку
+	filterSelect := widget.NewSelect(filterTitles, func(title string) {
+		if title == csvFilterOptions[2].Title {
+			fromEntry.Enable()
+			toEntry.Enable()
+		} else {
+			fromEntry.Disable()
+			toEntry.Disable()
+		}
+	})
+	filterSelect.SetSelected(filterTitles[0])
+	for _, option := range csvFilterOptions {
+		if string(option.Filter) == value(plugins.CSVFilter) {
+			filterSelect.SetSelected(option.Title)
+		}
+	}
+
+	dialog.ShowForm("CSV", "Далее", "Отмена", []*widget.FormItem{
+		{Text: "Разделитель", Widget: delimiterSelect},
+		{Text: "Кодировка", Widget: encodingSelect},
+		{Text: "Колонки", Widget: columnsCheck},
+		{Text: "Задачи", Widget: filterSelect},
+		{Text: "Срок с", Widget: fromEntry},
+		{Text: "по", Widget: toEntry},
+	}, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		if len(columnsCheck.Selected) == 0 {
+			dialog.ShowError(fmt.Errorf("select at least one column"), w)
+			return
+		}
+		values := map[string]string{plugins.CSVBOM: ""}
+		if encodingSelect.Selected == csvBOMTitle {
+			values[plugins.CSVBOM] = "yes"
+		}
+		for _, option := range csvDelimiterOptions {
+			if option.Title == delimiterSelect.Selected {
+				values[plugins.CSVDelimiter] = option.Value
+			}
+		}
+		var columns []string
+		if len(columnsCheck.Selected) < len(task.CSVColumns) {
+			for _, column := range task.CSVColumns {
+				if slices.Contains(columnsCheck.Selected, column.Header) {
+					columns = append(columns, column.Key)
+				}
+			}
+		}
+		values[plugins.CSVColumns] = strings.Join(columns, ",")
+		for _, option := range csvFilterOptions {
+			if option.Title == filterSelect.Selected {
+				values[plugins.CSVFilter] = string(option.Filter)
+			}
+		}
+		values[plugins.CSVFrom], values[plugins.CSVTo] = "", ""
+		if values[plugins.CSVFilter] == string(task.CSVFilterDueRange) {
+			values[plugins.CSVFrom] = strings.TrimSpace(fromEntry.Text)
+			values[plugins.CSVTo] = strings.TrimSpace(toEntry.Text)
+		}
+
+		if err := p.Configure(values); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		for key, v := range values {
+			prefs.SetString(pluginOptionKey(p, key), v)
+		}
+		onDone()
+	}, w)
+}
+
+// configureJSON показывает настройки выгрузки в JSON: с отступами или компактно,
+// с задачами из архива или без них
+func configureJSON(w fyne.Window, prefs fyne.Preferences, p plugins.Plugin, onDone func()) {
+	prettyCheck := widget.NewCheck("С отступами", nil)
+	prettyCheck.SetChecked(prefs.StringWithFallback(pluginOptionKey(p, plugins.JSONPretty), "yes") == "yes")
+	archivedCheck := widget.NewCheck("Включить задачи из архива", nil)
+	archivedCheck.SetChecked(prefs.String(pluginOptionKey(p, plugins.JSONArchived)) == "yes")
+	dialog.ShowForm("JSON", "Далее", "Отмена", []*widget.FormItem{
+		{Text: "Формат", Widget: prettyCheck, HintText: "Без отступов файл меньше"},
+		{Text: "Архив", Widget: archivedCheck},
+	}, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		flag := func(checked bool) string {
+			if checked {
+				return "yes"
+			}
+			return "no"
+		}
+		values := map[string]string{
+			plugins.JSONPretty:   flag(prettyCheck.Checked),
+			plugins.JSONArchived: flag(archivedCheck.Checked),
+		}
+		if err := p.Configure(values); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		for key, v := range values {
+			prefs.SetString(pluginOptionKey(p, key), v)
+		}
+		onDone()
+	}, w)
+}
+
+// choosePluginFile предлагает выбрать файл для выгрузки
+func choosePluginFile(w fyne.Window, p plugins.Plugin, onChosen func(file fyne.URIWriteCloser)) {
+	extension, _ := plugins.WritesFile(p)
+	save := dialog.NewFileSave(func(file fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if file != nil {
+			onChosen(file)
+		}
+	}, w)
+	save.SetFileName("tasks" + extension)
+	save.Show()
+}
+
+// runPlugin выгружает задачи в фоне, пока показывается индикатор. file закрывается после выгрузки
+func runPlugin(w fyne.Window, p plugins.Plugin, tasks []*task.Task, file fyne.URIWriteCloser) {
+	ctx, cancel := context.WithCancel(context.Background())
+	progress := dialog.NewCustomWithoutButtons("Экспорт", container.NewVBox(
+		widget.NewLabel("Выгрузка задач: "+p.Name()),
+		widget.NewProgressBarInfinite(),
+	), w)
+	progress.SetButtons([]fyne.CanvasObject{widget.NewButton("Отмена", cancel)})
+	progress.Show()
+
+	go func() {
+		var err error
+		if file != nil {
+			err = p.Run(ctx, tasks, file)
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		} else {
+			err = p.Run(ctx, tasks, nil)
+		}
+		canceled := ctx.Err() != nil
+		cancel()
+		fyne.Do(func() {
+			progress.Hide()
+			switch {
+			case canceled:
+			case err != nil:
+				slog.Error("export failed", "plugin", p.Name(), "err", err)
+				dialog.ShowError(err, w)
+			default:
+				slog.Info("tasks exported", "plugin", p.Name(), "tasks", len(tasks))
+				dialog.ShowInformation("Успешно", "Задачи выгружены: "+p.Name(), w)
+			}
+		})
+	}()
+}
+
+// importDuplicateOptions - что делать с задачами, которые уже есть в списке
+var importDuplicateOptions = []struct {
+	Title      string
+	Duplicates task.ImportDuplicates
+}{
+	{"Пропустить", task.ImportSkip},
+	{"Заменить из файла", task.ImportReplace},
+	{"Оставить обе", task.ImportCopy},
+}
+
+// duplicatesSummary описывает, сколько задач из файла похожи на задачи в списке и почему
+func duplicatesSummary(duplicates []task.ImportDuplicate) string {
+	sameUUID := 0
+	for _, duplicate := range duplicates {
+		if duplicate.SameUUID {
+			sameUUID++
+		}
+	}
+	return fmt.Sprintf("Похожих задач: %d (тот же UUID: %d, то же название и срок: %d)",
+		len(duplicates), sameUUID, len(duplicates)-sameUUID)
+}
+
+// duplicateLine описывает одну похожую задачу для списка в диалоге импорта
+func duplicateLine(duplicate task.ImportDuplicate) string {
+	if duplicate.SameUUID {
+		return duplicate.Imported.Title + " — тот же UUID"
+	}
+	return duplicate.Imported.Title + " — " + formatDueDate(duplicate.Existing)
+}
+
+// showJSONImportDialog добавляет задачи из выгрузки JSON. Задачи, которые уже есть
+// в списке, сопоставляются по UUID, а также по названию и сроку
+func showJSONImportDialog(w fyne.Window, tm *task.TaskManager) {
+	dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		envelope, err := task.ReadJSON(reader)
+		reader.Close()
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		info := fmt.Sprintf("Задач в файле: %d\nВыгружено: %s", len(envelope.Tasks), envelope.ExportedAt.In(time.Local).Format("02.01.2006 15:04"))
+		if envelope.AppVersion != "" {
+			info += ", версия " + envelope.AppVersion
+		}
+		confirmImport(w, tm, "Импорт из JSON", info, envelope.Tasks)
+	}, w)
+}
+
+// plaintextReaders - чтение задач из простого текста по расширению файла
+var plaintextReaders = map[string]func(io.Reader, time.Time) ([]*task.Task, error){
+	".org": task.ReadOrg,
+	".txt": task.ReadTodoTxt,
+}
+
+// showPlaintextImportDialog добавляет задачи из файла org-mode или todo.txt;
+// формат определяется по расширению, файлы с другим расширением читаются как todo.txt
+func showPlaintextImportDialog(w fyne.Window, tm *task.TaskManager) {
+	dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		read, ok := plaintextReaders[strings.ToLower(reader.URI().Extension())]
+		if !ok {
+			read = task.ReadTodoTxt
+		}
+		tasks, err := read(reader, tm.DueZone().Now())
+		reader.Close()
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		info := fmt.Sprintf("Задач в файле: %d", len(tasks))
+		confirmImport(w, tm, "Импорт из "+reader.URI().Name(), info, tasks)
+	}, w)
+}
+
+// confirmImport показывает, сколько задач из файла похожи на задачи в списке, спрашивает,
+// что с ними делать, и добавляет задачи в список
+func confirmImport(w fyne.Window, tm *task.TaskManager, title, info string, tasks []*task.Task) {
+	titles := make([]string, len(importDuplicateOptions))
+	for i, option := range importDuplicateOptions {
+		titles[i] = option.Title
+	}
+	duplicatesRadio := widget.NewRadioGroup(titles, nil)
+	duplicatesRadio.Required = true
+	duplicatesRadio.SetSelected(titles[0])
+	items := []*widget.FormItem{{Text: "", Widget: widget.NewLabel(info)}}
+	if found := tm.FindDuplicates(tasks); len(found) > 0 {
+		lines := make([]string, len(found))
+		for i, duplicate := range found {
+			lines[i] = duplicateLine(duplicate)
+		}
+		list := container.NewVScroll(widget.NewLabel(strings.Join(lines, "\n")))
+		list.SetMinSize(fyne.NewSize(0, 120))
+		items = append(items,
+			&widget.FormItem{Text: "", Widget: widget.NewLabel(duplicatesSummary(found))},
+			&widget.FormItem{Text: "", Widget: list},
+			&widget.FormItem{Text: "Похожие задачи", Widget: duplicatesRadio,
+				HintText: "Задачи похожи, если у них одинаковый UUID или одинаковые название и день срока"},
+		)
+	}
+	form := dialog.NewForm(title, "Импортировать", "Отмена", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		duplicates := task.ImportSkip
+		for _, option := range importDuplicateOptions {
+			if option.Title == duplicatesRadio.Selected {
+				duplicates = option.Duplicates
+			}
+		}
+		result := tm.Import(&task.ExportEnvelope{Tasks: tasks}, duplicates)
+		slog.Info("tasks imported", "added", result.Added, "replaced", result.Replaced, "skipped", result.Skipped)
+		dialog.ShowInformation("Импорт завершен", fmt.Sprintf("Добавлено задач: %d\nЗаменено: %d\nПропущено: %d",
+			result.Added, result.Replaced, result.Skipped), w)
+	}, w)
+	form.Resize(dialogSize(w, fyne.NewSize(480, 0)))
+	form.Show()
+}
@@ -0,0 +1,22 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+func TestDuplicatesSummary(t *testing.T) {
+	due := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	existing := &task.Task{Title: "Сдать отчет", DueDate: due}
+	duplicates := []task.ImportDuplicate{
+		{Imported: &task.Task{Title: "Сдать отчет"}, Existing: existing, SameUUID: true},
+		{Imported: &task.Task{Title: "сдать отчет"}, Existing: existing},
+	}
+	assert.Equal(t, "Похожих задач: 2 (тот же UUID: 1, то же название и срок: 1)", duplicatesSummary(duplicates))
+	assert.Equal(t, "Сдать отчет — тот же UUID", duplicateLine(duplicates[0]))
+	assert.Equal(t, "сдать отчет — "+formatDueDate(existing), duplicateLine(duplicates[1]))
+}
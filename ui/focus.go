@@ -0,0 +1,248 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/reminders"
+	"taskmanager/task"
+)
+
+// focusSession - очередь режима фокусировки: невыполненные задачи текущего вида в его порядке.
+// Задача, с которой что-то сделали, уходит в конец круга; отложенная скрыта до своего времени.
+// Сеанс живет, пока открыт режим, и ничего не меняет в самих задачах
+type focusSession struct {
+	tasks   func() []*task.Task // задачи текущего вида
+	passed  []string            // UUID задач в том порядке, в котором их прошли
+	snoozed map[string]time.Time
+}
+
+func newFocusSession(tasks func() []*task.Task) *focusSession {
+	return &focusSession{tasks: tasks, snoozed: make(map[string]time.Time)}
+}
+
+// queue возвращает задачи, которые можно показать в момент now
+func (s *focusSession) queue(now time.Time) []*task.Task {
+	var queue []*task.Task
+	for _, t := range s.tasks() {
+		if !t.Completed && !t.Archived && !s.snoozed[t.UUID].After(now) {
+			queue = append(queue, t)
+		}
+	}
+	return queue
+}
+
+// Current возвращает задачу, которую пора делать, и сколько задач в очереди. Сначала идут
+// задачи, которые еще не проходили, затем пройденные по кругу - раньше пройденные первыми
+func (s *focusSession) Current(now time.Time) (*task.Task, int) {
+	queue := s.queue(now)
+	var current *task.Task
+	best := -1
+	for _, t := range queue {
+		passed := slices.Index(s.passed, t.UUID)
+		if passed < 0 {
+			return t, len(queue)
+		}
+		if current == nil || passed < best {
+			current, best = t, passed
+		}
+	}
+	return current, len(queue)
+}
+
+// Pass отправляет задачу в конец круга
+func (s *focusSession) Pass(t *task.Task) {
+	s.passed = append(slices.DeleteFunc(s.passed, func(uuid string) bool { return uuid == t.UUID }), t.UUID)
+}
+
+// Snooze скрывает задачу до until
+func (s *focusSession) Snooze(t *task.Task, until time.Time) {
+	s.snoozed[t.UUID] = until
+	s.Pass(t)
+}
+
+// newFocusView создает режим фокусировки на все окно: одна задача текущего вида и кнопки
+// «Выполнено», «Пропустить», «Отложить». Таймер, если он запущен, добавляет время к задаче,
+// когда с ней закончили, и спрашивает о простое через idle. onExit вызывается по кнопке выхода
+// с итогами сеанса, подписки к этому моменту сняты
+func newFocusView(tm *task.TaskManager, tasks func() []*task.Task, timers *timerJournal, idle *idleTracker, showError func(error), onExit func(stats focusStats)) fyne.CanvasObject {
+	session := newFocusSession(tasks)
+	stats := focusStats{Start: time.Now()}
+	var shown *task.Task
+
+	counter := widget.NewLabel("")
+	counter.Importance = widget.LowImportance
+	title := widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	title.SizeName = theme.SizeNameHeadingText
+	title.Wrapping = fyne.TextWrapWord
+	details := widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{})
+	details.Importance = widget.LowImportance
+	details.Wrapping = fyne.TextWrapWord
+	description := widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{})
+	description.Wrapping = fyne.TextWrapWord
+
+	// Таймер идет для показанной задачи; время записывается, когда задачу сменили
+	var toggleTimer func(on bool)
+	timerStart := func() time.Time {
+		if shown == nil {
+			return time.Time{}
+		}
+		return timers.Running(shown.UUID)
+	}
+	timerLabel := widget.NewLabel("")
+	timerCheck := widget.NewCheck("Таймер", nil)
+	stopTimer := func() {
+		start := timerStart()
+		if start.IsZero() {
+			return
+		}
+		stats.trackTimer(time.Since(start))
+		showError(timers.Stop(shown.UUID, time.Now()))
+		idle.Reset()
+	}
+	showTimer := func() {
+		start := timerStart()
+		// Таймер могли остановить и без режима фокусировки: задачу выполнили или компьютер уснул
+		timerCheck.OnChanged = nil
+		timerCheck.SetChecked(!start.IsZero())
+		timerCheck.OnChanged = toggleTimer
+		if start.IsZero() {
+			timerLabel.SetText("")
+			return
+		}
+		timerLabel.SetText(formatTimeSpent(time.Since(start)))
+	}
+
+	var refresh func()
+	act := func(action func(t *task.Task)) {
+		if shown == nil {
+			return
+		}
+		t := shown
+		stopTimer()
+		session.Pass(t)
+		action(t)
+		refresh()
+	}
+	completeButton := widget.NewButtonWithIcon("Выполнено", theme.ConfirmIcon(), func() {
+		act(func(t *task.Task) {
+			if err := tm.ToggleTaskCompletion(t.ID); err != nil {
+				showError(err)
+				return
+			}
+			stats.Completed++
+		})
+	})
+	completeButton.Importance = widget.HighImportance
+	skipButton := widget.NewButtonWithIcon("Пропустить", theme.MediaSkipNextIcon(), func() {
+		act(func(*task.Task) { stats.Interruptions++ })
+	})
+	snoozeTitles := make([]string, len(reminders.Snoozes))
+	for i, snooze := range reminders.Snoozes {
+		snoozeTitles[i] = snooze.Title()
+	}
+	snoozeSelect := widget.NewSelect(snoozeTitles, nil)
+	snoozeSelect.PlaceHolder = "Отложить"
+	snooze := func(title string) {
+		for _, snooze := range reminders.Snoozes {
+			if snooze.Title() == title {
+				act(func(t *task.Task) {
+					session.Snooze(t, snooze.Until(time.Now()))
+					stats.Interruptions++
+				})
+			}
+		}
+	}
+	actions := container.NewHBox(layout.NewSpacer(), completeButton, skipButton, snoozeSelect, layout.NewSpacer())
+
+	refresh = func() {
+		current, left := session.Current(time.Now())
+		if current == nil || shown == nil || current.UUID != shown.UUID {
+			stopTimer()
+		}
+		shown = current
+		// Выбор в списке «Отложить» сбрасывается, чтобы его можно было выбрать снова
+		snoozeSelect.OnChanged = nil
+		snoozeSelect.ClearSelected()
+		snoozeSelect.OnChanged = snooze
+		if current == nil {
+			counter.SetText("")
+			title.SetText("Все задачи этого вида сделаны")
+			details.SetText("")
+			description.SetText("")
+			actions.Hide()
+			timerCheck.Hide()
+			showTimer()
+			return
+		}
+		counter.SetText(fmt.Sprintf("Задач в очереди: %d", left))
+		title.SetText(current.Title)
+		info := append([]string{task.PriorityText(current.Priority), formatRelativeDue(current, tm.DueZone().Now())}, taskDetails(current)...)
+		details.SetText(strings.Join(info, " · "))
+		description.SetText(current.Description)
+		actions.Show()
+		timerCheck.Show()
+		showTimer()
+	}
+	toggleTimer = func(on bool) {
+		if on && shown != nil {
+			timers.Start(shown.UUID, time.Now())
+		} else if !on {
+			stopTimer()
+		}
+		showTimer()
+	}
+	timerCheck.OnChanged = toggleTimer
+
+	// Задачи меняются и в других окнах; отложенные возвращаются в очередь по времени
+	unsubscribe := tm.Subscribe(func(task.Event) { refresh() })
+	stopTicker := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopTicker:
+				return
+			case <-ticker.C:
+				fyne.Do(func() {
+					if shown == nil || !timerStart().IsZero() {
+						refresh()
+					}
+					if start := timerStart(); !start.IsZero() {
+						uuid := shown.UUID
+						idle.Check(start, time.Now(), func(stretch time.Duration) {
+							timers.Shift(uuid, stretch)
+							showTimer()
+						})
+					}
+				})
+			}
+		}
+	}()
+
+	exitButton := widget.NewButtonWithIcon("Выйти из фокуса", theme.CancelIcon(), func() {
+		stopTimer()
+		unsubscribe()
+		close(stopTicker)
+		stats.finish(time.Now())
+		onExit(stats)
+	})
+	exitButton.Importance = widget.LowImportance
+
+	refresh()
+	card := container.NewVBox(counter, title, details, description, actions,
+		container.NewHBox(layout.NewSpacer(), timerCheck, timerLabel, layout.NewSpacer()))
+	return newReadingBorder(
+		container.NewHBox(layout.NewSpacer(), exitButton), nil, nil, nil,
+		container.NewVBox(layout.NewSpacer(), card, layout.NewSpacer()),
+	)
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"taskmanager/task"
+)
+
+func TestFocusSession(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	report := &task.Task{UUID: "a", Title: "Отчет"}
+	call := &task.Task{UUID: "b", Title: "Звонок"}
+	done := &task.Task{UUID: "c", Title: "Сделано", Completed: true}
+	mail := &task.Task{UUID: "d", Title: "Почта"}
+	s := newFocusSession(func() []*task.Task { return []*task.Task{report, call, done, mail} })
+
+	current, left := s.Current(now)
+	assert.Same(t, report, current)
+	assert.Equal(t, 3, left)
+
+	// Пропущенная задача уходит в конец круга, а круг повторяется
+	s.Pass(report)
+	current, _ = s.Current(now)
+	assert.Same(t, call, current)
+	s.Pass(call)
+	s.Pass(mail)
+	current, _ = s.Current(now)
+	assert.Same(t, report, current)
+
+	// Отложенная задача возвращается в очередь в свое время
+	s.Snooze(report, now.Add(time.Hour))
+	current, left = s.Current(now)
+	assert.Same(t, call, current)
+	assert.Equal(t, 2, left)
+	_, left = s.Current(now.Add(2 * time.Hour))
+	assert.Equal(t, 3, left)
+}
+
+// findText возвращает видимую кнопку или надпись с текстом text
+func findText(root fyne.CanvasObject, text string) fyne.CanvasObject {
+	for _, object := range test.LaidOutObjects(root) {
+		switch o := object.(type) {
+		case *widget.Button:
+			if o.Text == text && o.Visible() {
+				return o
+			}
+		case *widget.Label:
+			if o.Text == text && o.Visible() {
+				return o
+			}
+		}
+	}
+	return nil
+}
+
+func TestFocusView(t *testing.T) {
+	a := test.NewTempApp(t)
+	tm := newTestManager(t)
+	first := mustAddTask(t, tm, "Написать план", "", 3, time.Now())
+	mustAddTask(t, tm, "Разобрать почту", "", 1, time.Now())
+
+	var exited *focusStats
+	view := newFocusView(tm, tm.Tasks, newTimerJournal(a.Preferences(), tm), &idleTracker{}, func(err error) { require.NoError(t, err) }, func(stats focusStats) { exited = &stats })
+	w := test.NewWindow(view)
+	defer w.Close()
+
+	assert.NotNil(t, findText(view, "Написать план"))
+	test.Tap(findText(view, "Выполнено").(*widget.Button))
+	assert.True(t, tm.GetTask(first.ID).Completed)
+	assert.NotNil(t, findText(view, "Разобрать почту"))
+
+	test.Tap(findText(view, "Пропустить").(*widget.Button))
+	assert.NotNil(t, findText(view, "Разобрать почту"), "the only task left comes round again")
+
+	test.Tap(findText(view, "Выйти из фокуса").(*widget.Button))
+	require.NotNil(t, exited)
+	assert.Equal(t, 1, exited.Completed)
+	assert.Equal(t, 1, exited.Interruptions)
+	assert.False(t, exited.End.IsZero())
+}
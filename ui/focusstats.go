@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// prefFocusSessions - итоги прошлых сеансов фокусировки, из них складывается статистика
+const prefFocusSessions = "focus.sessions"
+
+// maxFocusSessions - сколько последних сеансов хранится в настройках
+const maxFocusSessions = 200
+
+// pomodoroLength - длина «помидора»: непрерывной работы над задачей по таймеру
+const pomodoroLength = 25 * time.Minute
+
+// focusStats - итоги сеанса фокусировки или нескольких сеансов вместе
+type focusStats struct {
+	Start time.Time     `json:"start"`
+	End   time.Time     `json:"end"`
+	Focus time.Duration `json:"focus"` // сколько длился режим фокусировки
+	// Tracked - время работы над задачами по таймеру
+	Tracked   time.Duration `json:"tracked,omitempty"`
+	Completed int           `json:"completed,omitempty"` // выполнено задач
+	Pomodoros int           `json:"pomodoros,omitempty"` // полных помидоров по таймеру
+	// Interruptions - сколько раз задачу бросили, не выполнив: пропустили или отложили
+	Interruptions int `json:"interruptions,omitempty"`
+	Sessions      int `json:"-"` // сколько сеансов сложено в итоги
+}
+
+// trackTimer учитывает время, которое шел таймер задачи: целые помидоры считаются
+// в каждом отрезке отдельно, потому что перерыв прерывает помидор
+func (s *focusStats) trackTimer(elapsed time.Duration) {
+	s.Tracked += elapsed
+	s.Pomodoros += int(elapsed / pomodoroLength)
+}
+
+// finish закрывает сеанс в момент now
+func (s *focusStats) finish(now time.Time) {
+	s.End = now
+	s.Focus = now.Sub(s.Start)
+}
+
+// Empty сообщает, что в сеансе ничего не сделали и хранить его незачем
+func (s focusStats) Empty() bool {
+	return s.Completed == 0 && s.Tracked == 0 && s.Interruptions == 0 && s.Focus < time.Minute
+}
+
+// add прибавляет итоги другого сеанса
+func (s *focusStats) add(other focusStats) {
+	s.Focus += other.Focus
+	s.Tracked += other.Tracked
+	s.Completed += other.Completed
+	s.Pomodoros += other.Pomodoros
+	s.Interruptions += other.Interruptions
+	s.Sessions++
+}
+
+// String описывает итоги по строкам
+func (s focusStats) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "В фокусе: %s\n", formatTimeSpent(s.Focus))
+	fmt.Fprintf(&b, "Выполнено задач: %d\n", s.Completed)
+	fmt.Fprintf(&b, "По таймеру: %s, помидоров: %d\n", formatTimeSpent(s.Tracked), s.Pomodoros)
+	fmt.Fprintf(&b, "Прерываний: %d", s.Interruptions)
+	return b.String()
+}
+
+// loadFocusSessions читает прошлые сеансы фокусировки профиля
+func loadFocusSessions(prefs fyne.Preferences) []focusStats {
+	var sessions []focusStats
+	if data := prefs.String(prefFocusSessions); data != "" {
+		if err := json.Unmarshal([]byte(data), &sessions); err != nil {
+			slog.Warn("failed to read focus sessions", "err", err)
+		}
+	}
+	return sessions
+}
+
+// saveFocusSession добавляет сеанс к прошлым; пустые сеансы не сохраняются
+func saveFocusSession(prefs fyne.Preferences, session focusStats) {
+	if session.Empty() {
+		return
+	}
+	sessions := append(loadFocusSessions(prefs), session)
+	if extra := len(sessions) - maxFocusSessions; extra > 0 {
+		sessions = sessions[extra:]
+	}
+	data, _ := json.Marshal(sessions)
+	prefs.SetString(prefFocusSessions, string(data))
+}
+
+// focusTotals складывает итоги сеансов с момента since; нулевое since - за все время
+func focusTotals(sessions []focusStats, since time.Time) focusStats {
+	var total focusStats
+	for _, s := range sessions {
+		if !s.Start.Before(since) {
+			total.add(s)
+		}
+	}
+	return total
+}
+
+// formatFocusStats описывает фокусировку для вкладки статистики: за неделю и за все время
+func formatFocusStats(sessions []focusStats, now time.Time) string {
+	if len(sessions) == 0 {
+		return "Режим фокусировки еще не использовался"
+	}
+	week := focusTotals(sessions, now.AddDate(0, 0, -7))
+	total := focusTotals(sessions, time.Time{})
+	return fmt.Sprintf("Фокусировка за 7 дней: сеансов %d, в фокусе %s, выполнено %d, помидоров %d, прерываний %d\n"+
+		"За все время: сеансов %d, в фокусе %s, выполнено %d, помидоров %d",
+		week.Sessions, formatTimeSpent(week.Focus), week.Completed, week.Pomodoros, week.Interruptions,
+		total.Sessions, formatTimeSpent(total.Focus), total.Completed, total.Pomodoros)
+}
+
+// showFocusSummary показывает итоги сеанса фокусировки, когда из него вышли
+func showFocusSummary(w fyne.Window, session focusStats) {
+	dialog.ShowCustom("Итоги фокусировки", "Закрыть", widget.NewLabel(session.String()), w)
+}
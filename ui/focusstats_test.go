@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFocusStats(t *testing.T) {
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	s := focusStats{Start: start}
+	s.trackTimer(55 * time.Minute)
+	s.trackTimer(20 * time.Minute)
+	s.Completed = 2
+	s.finish(start.Add(90 * time.Minute))
+	assert.Equal(t, 2, s.Pomodoros, "a break interrupts a pomodoro")
+	assert.Equal(t, 75*time.Minute, s.Tracked)
+	assert.Equal(t, 90*time.Minute, s.Focus)
+	assert.Contains(t, s.String(), "В фокусе: 1:30:00")
+	assert.False(t, s.Empty())
+
+	idle := focusStats{Start: start}
+	idle.finish(start.Add(20 * time.Second))
+	assert.True(t, idle.Empty())
+}
+
+func TestFocusSessionsStore(t *testing.T) {
+	prefs := test.NewTempApp(t).Preferences()
+	now := time.Date(2026, 10, 16, 18, 0, 0, 0, time.UTC)
+	assert.Equal(t, "Режим фокусировки еще не использовался", formatFocusStats(loadFocusSessions(prefs), now))
+
+	saveFocusSession(prefs, focusStats{Start: now.AddDate(0, 0, -30), Focus: time.Hour, Completed: 3, Pomodoros: 2})
+	saveFocusSession(prefs, focusStats{Start: now.Add(-time.Hour), Focus: 30 * time.Minute, Completed: 1, Interruptions: 2})
+	saveFocusSession(prefs, focusStats{Start: now, Focus: 10 * time.Second})
+	sessions := loadFocusSessions(prefs)
+	require.Len(t, sessions, 2, "empty sessions are not kept")
+
+	week := focusTotals(sessions, now.AddDate(0, 0, -7))
+	assert.Equal(t, 1, week.Sessions)
+	assert.Equal(t, 2, week.Interruptions)
+	total := focusTotals(sessions, time.Time{})
+	assert.Equal(t, 2, total.Sessions)
+	assert.Equal(t, 4, total.Completed)
+	assert.Equal(t, 90*time.Minute, total.Focus)
+	assert.Contains(t, formatFocusStats(sessions, now), "За все время: сеансов 2, в фокусе 1:30:00, выполнено 4, помидоров 2")
+}
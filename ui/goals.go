@@ -0,0 +1,256 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/goals"
+	"taskmanager/task"
+)
+
+// prefGoals - цели профиля; задачи хранят только идентификатор своей цели
+const prefGoals = "goals.list"
+
+// goalNone - вариант «без цели» в диалогах задачи
+const goalNone = "None"
+
+// loadGoals читает цели из настроек по порядку периодов
+func loadGoals(prefs fyne.Preferences) []goals.Goal {
+	list, err := goals.Parse(prefs.String(prefGoals))
+	if err != nil {
+		slog.Error("failed to read goals", "err", err)
+	}
+	goals.Sort(list)
+	return list
+}
+
+// saveGoals сохраняет цели в настройках
+func saveGoals(prefs fyne.Preferences, list []goals.Goal) {
+	prefs.SetString(prefGoals, goals.Marshal(list))
+}
+
+// goalTitle описывает цель для выбора: «Запустить сайт (IV квартал 2026)»
+func goalTitle(g goals.Goal) string {
+	return fmt.Sprintf("%s (%s)", g.Title, g.PeriodTitle())
+}
+
+// newGoalSelect создает выбор цели задачи в диалогах; selected - идентификатор текущей цели
+func newGoalSelect(list []goals.Goal, selected string) *widget.Select {
+	options := []string{goalNone}
+	for _, g := range list {
+		options = append(options, goalTitle(g))
+	}
+	s := widget.NewSelect(options, nil)
+	s.SetSelected(goalNone)
+	for i, g := range list {
+		if g.ID == selected {
+			s.SetSelectedIndex(i + 1)
+		}
+	}
+	return s
+}
+
+// selectedGoal возвращает идентификатор цели, выбранной в newGoalSelect
+func selectedGoal(s *widget.Select, list []goals.Goal) string {
+	if i := s.SelectedIndex() - 1; i >= 0 && i < len(list) {
+		return list[i].ID
+	}
+	return ""
+}
+
+// formatGoalProgress описывает прогресс цели: «Выполнено 1 из 3 (33%) · просрочено: 1 · под угрозой»
+func formatGoalProgress(p goals.Progress) string {
+	parts := []string{fmt.Sprintf("Выполнено %d из %d (%d%%)", p.Done, p.Total, p.Percent())}
+	if p.Overdue > 0 {
+		parts = append(parts, fmt.Sprintf("просрочено: %d", p.Overdue))
+	}
+	if p.Late > 0 {
+		parts = append(parts, fmt.Sprintf("срок после периода: %d", p.Late))
+	}
+	if risk := p.Risk.String(); risk != "" {
+		parts = append(parts, risk)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// goalPeriodOptions возвращает периоды, на которые можно поставить цель: текущий и следующие
+func goalPeriodOptions(period goals.Period, now time.Time) []goals.Goal {
+	count, months := 6, 1
+	if period == goals.PeriodQuarter {
+		count, months = 4, 3
+	}
+	start := goals.PeriodStart(period, now)
+	options := make([]goals.Goal, count)
+	for i := range options {
+		options[i] = goals.Goal{Period: period, Start: start.AddDate(0, i*months, 0).Format(task.DueDateLayout)}
+	}
+	return options
+}
+
+// showGoalForm запрашивает название и период новой цели
+func showGoalForm(w fyne.Window, now time.Time, onAdd func(goals.Goal)) {
+	titleEntry := widget.NewEntry()
+	titleEntry.SetPlaceHolder("Запустить новый сайт")
+	var periods []goals.Goal
+	periodSelect := widget.NewSelect(nil, nil)
+	kindSelect := widget.NewSelect([]string{"Квартал", "Месяц"}, func(kind string) {
+		period := goals.PeriodQuarter
+		if kind == "Месяц" {
+			period = goals.PeriodMonth
+		}
+		periods = goalPeriodOptions(period, now)
+		titles := make([]string, len(periods))
+		for i, p := range periods {
+			titles[i] = p.PeriodTitle()
+		}
+		periodSelect.SetOptions(titles)
+		periodSelect.SetSelectedIndex(0)
+	})
+	kindSelect.SetSelectedIndex(0)
+
+	dialog.ShowForm("Новая цель", "Добавить", "Отмена", []*widget.FormItem{
+		{Text: "Цель", Widget: titleEntry},
+		{Text: "Срок", Widget: kindSelect},
+		{Text: "Период", Widget: periodSelect},
+	}, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		period := periods[periodSelect.SelectedIndex()]
+		start, _ := period.Bounds(now.Location())
+		goal := goals.NewGoal(titleEntry.Text, period.Period, start)
+		if err := goal.Validate(); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		onAdd(goal)
+	}, w)
+}
+
+// newGoalsView создает вкладку целей: прогресс каждой цели по связанным задачам и риск
+// не успеть к концу периода, а справа - задачи выбранной цели
+func newGoalsView(w fyne.Window, prefs fyne.Preferences, tm *task.TaskManager, openTask func(id int)) (view fyne.CanvasObject, refresh func()) {
+	var list []goals.Goal
+	var progress []goals.Progress
+	var tasks []*task.Task
+	selected := -1
+	now := func() time.Time { return tm.DueZone().Now() }
+
+	goalList := widget.NewList(
+		func() int { return len(list) },
+		func() fyne.CanvasObject {
+			title := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+			summary := widget.NewLabel("")
+			summary.Importance = widget.LowImportance
+			return container.NewVBox(title, widget.NewProgressBar(), summary)
+		},
+		func(i widget.ListItemID, item fyne.CanvasObject) {
+			box := item.(*fyne.Container)
+			box.Objects[0].(*widget.Label).SetText(goalTitle(list[i]))
+			box.Objects[1].(*widget.ProgressBar).SetValue(float64(progress[i].Percent()) / 100)
+			summary := box.Objects[2].(*widget.Label)
+			summary.Importance = widget.LowImportance
+			if progress[i].Risk == goals.RiskOffTrack {
+				summary.Importance = widget.DangerImportance
+			}
+			summary.SetText(formatGoalProgress(progress[i]))
+		},
+	)
+
+	taskList := widget.NewList(
+		func() int { return len(tasks) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(i widget.ListItemID, item fyne.CanvasObject) {
+			item.(*widget.Label).SetText(formatTaskRow(tasks[i], now()))
+		},
+	)
+	taskList.OnSelected = func(i widget.ListItemID) {
+		taskList.UnselectAll()
+		openTask(tasks[i].ID)
+	}
+	tasksTitle := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	empty := widget.NewLabel("Целей пока нет. Добавьте цель на квартал или месяц и свяжите с ней задачи в окне задачи")
+	empty.Wrapping = fyne.TextWrapWord
+
+	showTasks := func() {
+		tasks = nil
+		if selected < 0 || selected >= len(list) {
+			tasksTitle.SetText("Выберите цель")
+			taskList.Refresh()
+			return
+		}
+		for _, t := range tm.Tasks() {
+			if t.Goal == list[selected].ID && (!t.Archived || t.Completed) {
+				tasks = append(tasks, t)
+			}
+		}
+		tasks = task.SortTasks(tasks, task.SortByDueDate, false)
+		tasksTitle.SetText(fmt.Sprintf("%s: задач %d", list[selected].Title, len(tasks)))
+		taskList.Refresh()
+	}
+	goalList.OnSelected = func(i widget.ListItemID) {
+		selected = i
+		showTasks()
+	}
+
+	refresh = func() {
+		list = loadGoals(prefs)
+		progress = make([]goals.Progress, len(list))
+		for i, g := range list {
+			progress[i] = g.Progress(tm.Tasks(), now())
+		}
+		empty.Hidden = len(list) > 0
+		empty.Refresh()
+		goalList.Refresh()
+		showTasks()
+	}
+
+	addButton := widget.NewButtonWithIcon("Новая цель", theme.ContentAddIcon(), func() {
+		showGoalForm(w, now(), func(goal goals.Goal) {
+			saveGoals(prefs, append(loadGoals(prefs), goal))
+			// Цели идут по порядку периодов, и новая цель сдвигает выбранную
+			selected = -1
+			goalList.UnselectAll()
+			refresh()
+		})
+	})
+	removeButton := widget.NewButtonWithIcon("Удалить цель", theme.DeleteIcon(), func() {
+		if selected < 0 || selected >= len(list) {
+			return
+		}
+		goal := list[selected]
+		dialog.ShowConfirm("Удалить цель", fmt.Sprintf("Удалить цель «%s»? Задачи останутся, но будут отвязаны от нее", goal.Title), func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			kept := loadGoals(prefs)
+			for i, g := range kept {
+				if g.ID == goal.ID {
+					kept = append(kept[:i], kept[i+1:]...)
+					break
+				}
+			}
+			saveGoals(prefs, kept)
+			selected = -1
+			goalList.UnselectAll()
+			tm.ClearGoal(goal.ID)
+			refresh()
+		}, w)
+	})
+	refresh()
+
+	split := container.NewHSplit(
+		container.NewBorder(container.NewHBox(addButton, removeButton), nil, nil, nil, container.NewStack(goalList, empty)),
+		container.NewBorder(tasksTitle, nil, nil, nil, taskList),
+	)
+	split.Offset = 0.5
+	return split, refresh
+}
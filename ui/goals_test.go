@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"taskmanager/goals"
+)
+
+func TestGoalSelect(t *testing.T) {
+	test.NewTempApp(t)
+	list := []goals.Goal{
+		{ID: "q4", Title: "Запуск", Period: goals.PeriodQuarter, Start: "2026-10-01"},
+		{ID: "oct", Title: "Книга", Period: goals.PeriodMonth, Start: "2026-10-01"},
+	}
+	s := newGoalSelect(list, "oct")
+	assert.Equal(t, []string{goalNone, "Запуск (IV квартал 2026)", "Книга (октябрь 2026)"}, s.Options)
+	assert.Equal(t, "oct", selectedGoal(s, list))
+	s.SetSelected(goalNone)
+	assert.Empty(t, selectedGoal(s, list))
+	assert.Equal(t, goalNone, newGoalSelect(list, "удалена").Selected)
+}
+
+func TestFormatGoalProgress(t *testing.T) {
+	assert.Equal(t, "Выполнено 0 из 0 (0%)", formatGoalProgress(goals.Progress{}))
+	assert.Equal(t, "Выполнено 1 из 4 (25%) · просрочено: 1 · срок после периода: 2 · под угрозой",
+		formatGoalProgress(goals.Progress{Done: 1, Total: 4, Overdue: 1, Late: 2, Risk: goals.RiskOffTrack}))
+}
+
+func TestGoalPeriodOptions(t *testing.T) {
+	now := time.Date(2026, 11, 20, 0, 0, 0, 0, time.UTC)
+	quarters := goalPeriodOptions(goals.PeriodQuarter, now)
+	require.Len(t, quarters, 4)
+	assert.Equal(t, "IV квартал 2026", quarters[0].PeriodTitle())
+	assert.Equal(t, "I квартал 2027", quarters[1].PeriodTitle())
+	months := goalPeriodOptions(goals.PeriodMonth, now)
+	require.Len(t, months, 6)
+	assert.Equal(t, "2027-01-01", months[2].Start)
+}
+
+func TestGoalsView(t *testing.T) {
+	a := test.NewTempApp(t)
+	tm := newTestManager(t)
+	today := tm.DueZone().Now()
+	goal := goals.NewGoal("Запуск", goals.PeriodMonth, today)
+	saveGoals(a.Preferences(), []goals.Goal{goal})
+	done := mustAddTask(t, tm, "Макет", "", 2, today)
+	mustAddTask(t, tm, "Тексты", "", 2, today)
+	require.NoError(t, tm.SetTaskGoal(done.ID, goal.ID))
+	require.NoError(t, tm.ToggleTaskCompletion(done.ID))
+
+	view, refresh := newGoalsView(test.NewWindow(nil), a.Preferences(), tm, func(int) {})
+	w := test.NewWindow(view)
+	defer w.Close()
+	assert.NotNil(t, findText(view, "Запуск ("+goal.PeriodTitle()+")"))
+	assert.NotNil(t, findText(view, "Выполнено 1 из 1 (100%)"))
+	assert.Nil(t, findText(view, "Целей пока нет. Добавьте цель на квартал или месяц и свяжите с ней задачи в окне задачи"))
+
+	saveGoals(a.Preferences(), nil)
+	refresh()
+	assert.NotNil(t, findText(view, "Целей пока нет. Добавьте цель на квартал или месяц и свяжите с ней задачи в окне задачи"))
+}
@@ -0,0 +1,157 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/depgraph"
+	"taskmanager/task"
+)
+
+// Размеры графа зависимостей
+const (
+	graphNodeWidth  float32 = 180
+	graphNodeHeight float32 = 36
+	graphColumnGap  float32 = 60
+	graphRowGap     float32 = 16
+	graphTitleRunes         = 22 // длиннее название обрезается
+)
+
+// graphNodeTitle подписывает задачу на графе: номер и название, обрезанное по ширине узла
+func graphNodeTitle(t *task.Task) string {
+	title := []rune(t.Title)
+	if len(title) > graphTitleRunes {
+		title = append(title[:graphTitleRunes-1], '…')
+	}
+	return fmt.Sprintf("#%d %s", t.ID, string(title))
+}
+
+// graphLegend описывает выделение на графе и критическую цепочку
+func graphLegend(g depgraph.Graph) string {
+	lines := []string{"Стрелка ведет от задачи к задачам, которые ее ждут"}
+	if len(g.Critical) > 0 {
+		refs := make([]string, len(g.Critical))
+		for i, t := range g.Critical {
+			refs[i] = fmt.Sprintf("#%d", t.ID)
+		}
+		lines = append(lines, "Критическая цепочка (жирная рамка): "+strings.Join(refs, " → "))
+	}
+	if len(g.Cycle) > 0 {
+		lines = append(lines, fmt.Sprintf("⚠ Задач в цикле зависимостей (красные): %d", len(g.Cycle)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// graphNode - задача на графе, нажатие открывает ее
+type graphNode struct {
+	widget.BaseWidget
+	t        *task.Task
+	stroke   fyne.ThemeColorName
+	critical bool
+	onTap    func()
+}
+
+func newGraphNode(t *task.Task, stroke fyne.ThemeColorName, critical bool, onTap func()) *graphNode {
+	node := &graphNode{t: t, stroke: stroke, critical: critical, onTap: onTap}
+	node.ExtendBaseWidget(node)
+	return node
+}
+
+func (n *graphNode) CreateRenderer() fyne.WidgetRenderer {
+	rect := canvas.NewRectangle(theme.Color(theme.ColorNameInputBackground))
+	rect.CornerRadius = 4
+	rect.StrokeColor = theme.Color(n.stroke)
+	rect.StrokeWidth = 1
+	if n.critical {
+		rect.StrokeWidth = 3
+	}
+	textColor := theme.Color(theme.ColorNameForeground)
+	if n.t.Completed {
+		textColor = theme.Color(theme.ColorNameDisabled)
+	}
+	title := canvas.NewText(" "+graphNodeTitle(n.t), textColor)
+	title.TextSize = theme.CaptionTextSize()
+	return widget.NewSimpleRenderer(container.NewStack(rect, container.NewCenter(title)))
+}
+
+func (n *graphNode) Tapped(*fyne.PointEvent) {
+	n.onTap()
+}
+
+// newDependencyGraph рисует граф: слои - столбцы слева направо, зависимости - линии между узлами
+func newDependencyGraph(g depgraph.Graph, openTask func(id int)) fyne.CanvasObject {
+	pos := make(map[*task.Task]fyne.Position)
+	rows := 0
+	for column, layer := range g.Layers {
+		for row, t := range layer {
+			pos[t] = fyne.NewPos(float32(column)*(graphNodeWidth+graphColumnGap), float32(row)*(graphNodeHeight+graphRowGap))
+		}
+		rows = max(rows, len(layer))
+	}
+	critical := make(map[*task.Task]bool, len(g.Critical))
+	for _, t := range g.Critical {
+		critical[t] = true
+	}
+
+	// Линии рисуются первыми, чтобы узлы были поверх них
+	var objects []fyne.CanvasObject
+	for _, e := range g.Edges {
+		line := canvas.NewLine(theme.Color(theme.ColorNamePlaceHolder))
+		switch {
+		case g.Cycle[e.From] && g.Cycle[e.To]:
+			line.StrokeColor = theme.Color(theme.ColorNameError)
+		case g.OnCritical(e):
+			line.StrokeColor = theme.Color(theme.ColorNamePrimary)
+			line.StrokeWidth = 3
+		}
+		from, to := pos[e.From], pos[e.To]
+		line.Position1 = from.AddXY(graphNodeWidth, graphNodeHeight/2)
+		line.Position2 = to.AddXY(0, graphNodeHeight/2)
+		objects = append(objects, line)
+	}
+	for t, p := range pos {
+		stroke := theme.ColorNameSeparator
+		switch {
+		case g.Cycle[t]:
+			stroke = theme.ColorNameError
+		case critical[t]:
+			stroke = theme.ColorNamePrimary
+		}
+		id := t.ID
+		node := newGraphNode(t, stroke, critical[t], func() { openTask(id) })
+		node.Move(p)
+		node.Resize(fyne.NewSize(graphNodeWidth, graphNodeHeight))
+		objects = append(objects, node)
+	}
+
+	size := fyne.NewSize(
+		float32(len(g.Layers))*(graphNodeWidth+graphColumnGap)-graphColumnGap,
+		float32(rows)*(graphNodeHeight+graphRowGap)-graphRowGap)
+	return container.New(&fixedLayout{size: size}, objects...)
+}
+
+// showDependencyGraphDialog показывает граф зависимостей задач не из архива
+func showDependencyGraphDialog(w fyne.Window, tm *task.TaskManager, openTask func(id int)) {
+	g := depgraph.Build(visibleTasks(tm))
+	if g.Empty() {
+		dialog.ShowInformation("Граф зависимостей", "Зависимостей нет. Их можно задать при редактировании задачи", w)
+		return
+	}
+	var d dialog.Dialog
+	open := func(id int) {
+		d.Hide()
+		openTask(id)
+	}
+	content := container.NewBorder(nil, widget.NewLabel(graphLegend(g)), nil, nil,
+		container.NewScroll(container.NewPadded(newDependencyGraph(g, open))))
+	d = dialog.NewCustom("Граф зависимостей", "Закрыть", content, w)
+	d.Resize(dialogSize(w, fyne.NewSize(900, 600)))
+	d.Show()
+}
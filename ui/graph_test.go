@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/depgraph"
+	"taskmanager/task"
+)
+
+func TestGraphNodeTitle(t *testing.T) {
+	assert.Equal(t, "#3 Купить билеты", graphNodeTitle(&task.Task{ID: 3, Title: "Купить билеты"}))
+	assert.Equal(t, "#4 Согласовать бюджет по…", graphNodeTitle(&task.Task{ID: 4, Title: "Согласовать бюджет поездки с отделом"}))
+}
+
+func TestGraphLegend(t *testing.T) {
+	plan := &task.Task{ID: 1, UUID: "plan"}
+	trip := &task.Task{ID: 2, UUID: "trip", DependsOn: []string{"plan"}}
+	legend := graphLegend(depgraph.Build([]*task.Task{plan, trip}))
+	assert.Contains(t, legend, "#1 → #2")
+	assert.NotContains(t, legend, "цикл")
+}
+
+func TestShowDependencyGraphDialog(t *testing.T) {
+	test.NewTempApp(t)
+	tm := newTestManager(t)
+	plan := mustAddTask(t, tm, "Составить план", "", 2, time.Now())
+	trip := mustAddTask(t, tm, "Купить билеты", "", 2, time.Now())
+	w := test.NewWindow(nil)
+	defer w.Close()
+
+	showDependencyGraphDialog(w, tm, func(int) {})
+	assert.NoError(t, tm.SetTaskDependencies(trip.ID, []int{plan.ID}))
+	showDependencyGraphDialog(w, tm, func(int) {})
+
+	view := newDependencyGraph(depgraph.Build(tm.Tasks()), func(int) {})
+	assert.Equal(t, 2*graphNodeWidth+graphColumnGap, view.MinSize().Width)
+}
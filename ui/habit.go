@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/task"
+)
+
+// Варианты повторения в диалогах задачи
+var recurrenceOptions = map[task.Recurrence]string{
+	task.RecurNone:   "No",
+	task.RecurDaily:  "Daily",
+	task.RecurWeekly: "Weekly",
+}
+
+// newRecurrenceSelect создает выбор повторения задачи
+func newRecurrenceSelect(selected task.Recurrence) *widget.Select {
+	options := make([]string, len(task.Recurrences))
+	for i, r := range task.Recurrences {
+		options[i] = recurrenceOptions[r]
+	}
+	s := widget.NewSelect(options, nil)
+	s.SetSelected(recurrenceOptions[selected])
+	return s
+}
+
+// selectedRecurrence возвращает повторение, выбранное в newRecurrenceSelect
+func selectedRecurrence(s *widget.Select) task.Recurrence {
+	for r, text := range recurrenceOptions {
+		if text == s.Selected {
+			return r
+		}
+	}
+	return task.RecurNone
+}
+
+// formatStreak показывает серию выполнений повторяющейся задачи
+func formatStreak(t *task.Task, now time.Time) string {
+	unit := "дн."
+	if t.Recurrence == task.RecurWeekly {
+		unit = "нед."
+	}
+	return fmt.Sprintf("🔥 Серия: %d %s (рекорд: %d)", t.Streak(now), unit, t.BestStreak())
+}
+
+// heatmapDay - клетка карты месяца; Day 0 - пустая клетка до первого числа
+type heatmapDay struct {
+	Day  int
+	Done bool
+}
+
+// monthHeatmap возвращает дни месяца, в который попадает now, по неделям с понедельника
+func monthHeatmap(t *task.Task, now time.Time) []heatmapDay {
+	first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	cells := make([]heatmapDay, (int(first.Weekday())+6)%7)
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		cells = append(cells, heatmapDay{Day: day.Day(), Done: t.CompletedOn(day)})
+	}
+	return cells
+}
+
+// habitView - серия и карта выполнений задачи-привычки за текущий месяц
+type habitView struct {
+	streak *widget.Label
+	month  *widget.Label
+	grid   *fyne.Container
+	box    *fyne.Container
+}
+
+func newHabitView() *habitView {
+	v := &habitView{
+		streak: widget.NewLabel(""),
+		month:  widget.NewLabel(""),
+		grid:   container.NewGridWithColumns(7),
+	}
+	v.box = container.NewVBox(container.NewBorder(nil, nil, v.streak, v.month), v.grid)
+	return v
+}
+
+// Update показывает историю задачи; для задачи без повторения вид скрывается
+func (v *habitView) Update(t *task.Task, now time.Time) {
+	if t.Recurrence == task.RecurNone {
+		v.box.Hide()
+		return
+	}
+	v.streak.SetText(formatStreak(t, now))
+	v.month.SetText(now.Format("01.2006"))
+
+	v.grid.RemoveAll()
+	for _, name := range []string{"Пн", "Вт", "Ср", "Чт", "Пт", "Сб", "Вс"} {
+		v.grid.Add(widget.NewLabelWithStyle(name, fyne.TextAlignCenter, fyne.TextStyle{}))
+	}
+	for _, cell := range monthHeatmap(t, now) {
+		if cell.Day == 0 {
+			v.grid.Add(canvas.NewRectangle(color.Transparent))
+			continue
+		}
+		fill := theme.Color(theme.ColorNameInputBackground)
+		if cell.Done {
+			fill = theme.Color(theme.ColorNameSuccess)
+		}
+		rect := canvas.NewRectangle(fill)
+		rect.CornerRadius = 3
+		rect.SetMinSize(fyne.NewSize(24, 24))
+		day := canvas.NewText(strconv.Itoa(cell.Day), theme.Color(theme.ColorNameForeground))
+		day.Alignment = fyne.TextAlignCenter
+		day.TextSize = theme.CaptionTextSize()
+		v.grid.Add(container.NewStack(rect, container.NewCenter(day)))
+	}
+	v.box.Show()
+}
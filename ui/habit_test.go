@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+func TestMonthHeatmap(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 8, 0, 0, 0, time.UTC) }
+	habit := &task.Task{Recurrence: task.RecurDaily, Completions: []time.Time{day(1), day(15), day(16)}}
+
+	cells := monthHeatmap(habit, day(16))
+	// 1 октября 2026 - четверг: три пустые клетки с понедельника
+	assert.Len(t, cells, 3+31)
+	assert.Equal(t, heatmapDay{}, cells[0])
+	assert.Equal(t, heatmapDay{Day: 1, Done: true}, cells[3])
+	assert.Equal(t, heatmapDay{Day: 2}, cells[4])
+	assert.Equal(t, heatmapDay{Day: 16, Done: true}, cells[3+15])
+
+	assert.Equal(t, "🔥 Серия: 2 дн. (рекорд: 2)", formatStreak(habit, day(16)))
+}
+
+func TestRecurrenceSelect(t *testing.T) {
+	test.NewTempApp(t)
+	for _, r := range task.Recurrences {
+		assert.Equal(t, r, selectedRecurrence(newRecurrenceSelect(r)))
+	}
+}
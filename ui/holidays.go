@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	"taskmanager/holidays"
+	"taskmanager/task"
+)
+
+// Настройки праздников
+const (
+	prefHolidaySource  = "holidays.source"        // код страны встроенного календаря или holidaySourceICS, пусто - без праздников
+	prefHolidayICSURL  = "holidays.ics_url"       // ссылка на календарь ICS
+	prefHolidayICSData = "holidays.ics_data"      // последний загруженный календарь ICS, чтобы праздники работали без сети
+	prefSkipDaysOff    = "holidays.skip_days_off" // повторение и перенос срока пропускают выходные и праздники
+)
+
+// holidaySourceICS - праздники берутся из календаря ICS по ссылке
+const holidaySourceICS = "ics"
+
+// holidaySourceNone - название варианта «без праздников» в настройках
+const holidaySourceNone = "Нет"
+
+// holidaySourceICSTitle - название варианта календаря ICS в настройках
+const holidaySourceICSTitle = "Календарь ICS по ссылке"
+
+// holidaySourceTitles возвращает варианты праздничного календаря для настроек
+func holidaySourceTitles() []string {
+	titles := []string{holidaySourceNone}
+	for _, country := range holidays.Countries {
+		titles = append(titles, country.Name)
+	}
+	return append(titles, holidaySourceICSTitle)
+}
+
+// holidaySourceTitle возвращает название варианта по значению настройки
+func holidaySourceTitle(source string) string {
+	if source == holidaySourceICS {
+		return holidaySourceICSTitle
+	}
+	for _, country := range holidays.Countries {
+		if country.Code == source {
+			return country.Name
+		}
+	}
+	return holidaySourceNone
+}
+
+// holidaySourceByTitle возвращает значение настройки по названию варианта
+func holidaySourceByTitle(title string) string {
+	if title == holidaySourceICSTitle {
+		return holidaySourceICS
+	}
+	for _, country := range holidays.Countries {
+		if country.Name == title {
+			return country.Code
+		}
+	}
+	return ""
+}
+
+// loadHolidays возвращает праздники из настроек: встроенные праздники страны на несколько
+// лет вокруг now или последний загруженный календарь ICS. Без праздников возвращает nil
+func loadHolidays(prefs fyne.Preferences, now time.Time) *holidays.Calendar {
+	switch source := prefs.String(prefHolidaySource); source {
+	case "":
+		return nil
+	case holidaySourceICS:
+		data := prefs.String(prefHolidayICSData)
+		if data == "" {
+			return nil
+		}
+		calendar, err := holidays.ParseICS(strings.NewReader(data))
+		if err != nil {
+			slog.Error("failed to read holiday calendar", "err", err)
+			return nil
+		}
+		return calendar
+	default:
+		calendar, err := holidays.Builtin(source, now.Year()-1, now.Year()+2)
+		if err != nil {
+			slog.Error("failed to build holiday calendar", "country", source, "err", err)
+			return nil
+		}
+		return calendar
+	}
+}
+
+// applyHolidays передает менеджеру задач нерабочие дни, если их нужно пропускать
+func applyHolidays(prefs fyne.Preferences, tm *task.TaskManager) {
+	if !prefs.Bool(prefSkipDaysOff) {
+		tm.SetDaysOff(nil)
+		return
+	}
+	tm.SetDaysOff(loadHolidays(prefs, time.Now()).DayOff)
+}
+
+// refreshHolidayFeed загружает календарь ICS в фоне и сохраняет его в настройках.
+// onUpdated вызывается, когда праздники обновились
+func refreshHolidayFeed(prefs fyne.Preferences, tm *task.TaskManager, onUpdated func()) {
+	url := strings.TrimSpace(prefs.String(prefHolidayICSURL))
+	if prefs.String(prefHolidaySource) != holidaySourceICS || url == "" {
+		return
+	}
+	go func() {
+		data, err := holidays.FetchICS(context.Background(), url)
+		fyne.Do(func() {
+			if err != nil {
+				slog.Warn("failed to load holiday calendar", "err", err)
+				return
+			}
+			prefs.SetString(prefHolidayICSData, data)
+			applyHolidays(prefs, tm)
+			onUpdated()
+		})
+	}()
+}
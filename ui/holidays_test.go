@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHolidaySource(t *testing.T) {
+	for _, source := range []string{"", "RU", "DE", holidaySourceICS} {
+		assert.Equal(t, source, holidaySourceByTitle(holidaySourceTitle(source)))
+	}
+	assert.Len(t, holidaySourceTitles(), 6)
+	assert.Equal(t, holidaySourceNone, holidaySourceTitle("XX"))
+}
+
+func TestLoadHolidays(t *testing.T) {
+	prefs := test.NewTempApp(t).Preferences()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	assert.Nil(t, loadHolidays(prefs, now))
+
+	prefs.SetString(prefHolidaySource, "RU")
+	name, ok := loadHolidays(prefs, now).Holiday(time.Date(2027, 1, 7, 0, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, "Рождество Христово", name)
+
+	// Календарь ICS еще не загружен
+	prefs.SetString(prefHolidaySource, holidaySourceICS)
+	assert.Nil(t, loadHolidays(prefs, now))
+	prefs.SetString(prefHolidayICSData, "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART;VALUE=DATE:20261020\nSUMMARY:Корпоратив\nEND:VEVENT\nEND:VCALENDAR\n")
+	name, _ = loadHolidays(prefs, now).Holiday(time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, "Корпоратив", name)
+}
+
+func TestApplyHolidays(t *testing.T) {
+	prefs := test.NewTempApp(t).Preferences()
+	tm := newTestManager(t)
+	// 2026-11-04 - среда и праздник в России
+	holiday := time.Date(2026, 11, 4, 0, 0, 0, 0, time.UTC)
+	prefs.SetString(prefHolidaySource, "RU")
+	applyHolidays(prefs, tm)
+	assert.Equal(t, holiday, tm.NextWorkday(holiday))
+
+	prefs.SetBool(prefSkipDaysOff, true)
+	applyHolidays(prefs, tm)
+	assert.Equal(t, holiday.AddDate(0, 0, 1), tm.NextWorkday(holiday))
+	// Суббота переносится на понедельник
+	assert.Equal(t, time.Monday, tm.NextWorkday(time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)).Weekday())
+}
@@ -0,0 +1,173 @@
+package ui
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// taskIcon - значок задачи для выбора: эмодзи и слова, по которым его можно найти
+type taskIcon struct {
+	Emoji    string
+	Keywords string
+}
+
+// taskIcons - значки, из которых выбирают в диалоге задачи. Слова поиска на русском и английском
+var taskIcons = []taskIcon{
+	{"⭐", "звезда важно избранное star"},
+	{"🔥", "огонь срочно горит fire urgent"},
+	{"✅", "галочка готово проверка check done"},
+	{"❗", "восклицание внимание важно alert"},
+	{"❓", "вопрос узнать question"},
+	{"💡", "идея лампа idea"},
+	{"📌", "булавка закрепить pin"},
+	{"🎯", "цель мишень target goal"},
+	{"🚀", "ракета запуск релиз launch rocket"},
+	{"🐛", "жук баг ошибка bug"},
+	{"🔧", "ключ ремонт починить fix tool"},
+	{"⚙️", "шестеренка настройка settings"},
+	{"💻", "ноутбук компьютер код работа laptop code"},
+	{"📧", "письмо почта email mail"},
+	{"📞", "телефон звонок позвонить phone call"},
+	{"💬", "сообщение чат написать chat message"},
+	{"📅", "календарь встреча дата calendar meeting"},
+	{"⏰", "будильник время напоминание alarm time"},
+	{"📝", "заметка записать написать note write"},
+	{"📄", "документ бумага document"},
+	{"📊", "отчет график диаграмма report chart"},
+	{"📚", "книги учеба читать books study"},
+	{"🎓", "учеба экзамен курс study exam"},
+	{"💰", "деньги оплата финансы money"},
+	{"💳", "карта оплата счет payment card"},
+	{"🧾", "чек квитанция счет receipt invoice"},
+	{"🛒", "покупки магазин купить shopping"},
+	{"🎁", "подарок праздник gift"},
+	{"🎂", "торт день рождения birthday"},
+	{"🏠", "дом квартира home"},
+	{"🧹", "уборка убрать cleaning"},
+	{"🧺", "стирка белье laundry"},
+	{"🍳", "готовка еда кухня cooking food"},
+	{"🌱", "растение сад полить plant garden"},
+	{"🐶", "собака питомец dog pet"},
+	{"🐱", "кошка питомец cat pet"},
+	{"🚗", "машина авто поездка car"},
+	{"✈️", "самолет поездка путешествие отпуск plane travel"},
+	{"🧳", "чемодан командировка поездка luggage trip"},
+	{"🏥", "больница врач здоровье hospital doctor"},
+	{"💊", "лекарство таблетки здоровье pill medicine"},
+	{"🏃", "бег спорт тренировка run sport"},
+	{"🏋️", "спортзал тренировка gym"},
+	{"🧘", "йога отдых медитация yoga"},
+	{"🎵", "музыка песня music"},
+	{"🎮", "игра игры game"},
+	{"🎨", "рисунок дизайн творчество art design"},
+	{"📷", "фото камера photo camera"},
+	{"🔒", "замок безопасность пароль lock security"},
+	{"👥", "люди команда встреча team people"},
+	{"👶", "ребенок дети baby kids"},
+	{"❤️", "сердце любовь семья heart love"},
+}
+
+// iconMatches возвращает значки, подходящие под запрос: по словам или по самому эмодзи.
+// Пустой запрос показывает все значки
+func iconMatches(query string) []taskIcon {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return taskIcons
+	}
+	var matches []taskIcon
+	for _, icon := range taskIcons {
+		if icon.Emoji == query || strings.Contains(icon.Keywords, query) {
+			matches = append(matches, icon)
+		}
+	}
+	return matches
+}
+
+// iconPicker - поле значка задачи: кнопка показывает выбранный эмодзи и открывает поиск значков
+type iconPicker struct {
+	widget.BaseWidget
+	Icon   string
+	w      fyne.Window
+	button *widget.Button
+}
+
+func newIconPicker(w fyne.Window, icon string) *iconPicker {
+	p := &iconPicker{Icon: icon, w: w}
+	p.button = widget.NewButton("", p.showPicker)
+	p.button.Alignment = widget.ButtonAlignLeading
+	p.SetIcon(icon)
+	p.ExtendBaseWidget(p)
+	return p
+}
+
+func (p *iconPicker) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(p.button)
+}
+
+// SetIcon выбирает значок; пустая строка - без значка
+func (p *iconPicker) SetIcon(icon string) {
+	p.Icon = icon
+	if icon == "" {
+		p.button.SetText("Без значка")
+		return
+	}
+	p.button.SetText(icon)
+}
+
+// showPicker показывает поиск значков: щелчок по значку выбирает его
+func (p *iconPicker) showPicker() {
+	var popup *widget.PopUp
+	var matches []taskIcon
+	grid := widget.NewGridWrap(
+		func() int { return len(matches) },
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("⭐")
+			label.Alignment = fyne.TextAlignCenter
+			return label
+		},
+		func(id widget.GridWrapItemID, item fyne.CanvasObject) {
+			item.(*widget.Label).SetText(matches[id].Emoji)
+		},
+	)
+	grid.OnSelected = func(id widget.GridWrapItemID) {
+		p.SetIcon(matches[id].Emoji)
+		popup.Hide()
+	}
+
+	search := newPaletteEntry()
+	search.SetPlaceHolder("Поиск: звонок, покупки, bug…")
+	search.OnChanged = func(query string) {
+		matches = iconMatches(query)
+		grid.UnselectAll()
+		grid.Refresh()
+	}
+	search.onKey = func(key fyne.KeyName) bool {
+		if key == fyne.KeyEscape {
+			popup.Hide()
+			return true
+		}
+		return false
+	}
+	search.OnSubmitted = func(string) {
+		if len(matches) > 0 {
+			p.SetIcon(matches[0].Emoji)
+			popup.Hide()
+		}
+	}
+	noIcon := widget.NewButtonWithIcon("Без значка", theme.ContentClearIcon(), func() {
+		p.SetIcon("")
+		popup.Hide()
+	})
+	cancel := widget.NewButton("Отмена", func() { popup.Hide() })
+
+	content := container.NewBorder(search, container.NewHBox(noIcon, cancel), nil, nil, grid)
+	popup = widget.NewModalPopUp(content, p.w.Canvas())
+	popup.Resize(fyne.NewSize(360, 320))
+	matches = iconMatches("")
+	popup.Show()
+	p.w.Canvas().Focus(search)
+}
@@ -0,0 +1,28 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIconMatches(t *testing.T) {
+	assert.Len(t, iconMatches(" "), len(taskIcons))
+	assert.Equal(t, []taskIcon{{"📞", "телефон звонок позвонить phone call"}}, iconMatches("Звонок"))
+	assert.Equal(t, "🐛", iconMatches("bug")[0].Emoji)
+	assert.Equal(t, "🔥", iconMatches("🔥")[0].Emoji)
+	assert.Empty(t, iconMatches("нет такого"))
+}
+
+func TestIconPicker(t *testing.T) {
+	test.NewTempApp(t)
+	w := test.NewWindow(nil)
+	defer w.Close()
+
+	picker := newIconPicker(w, "")
+	assert.Equal(t, "Без значка", picker.button.Text)
+	picker.SetIcon("🚀")
+	assert.Equal(t, "🚀", picker.Icon)
+	assert.Equal(t, "🚀", picker.button.Text)
+}
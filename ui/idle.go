@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/idle"
+)
+
+// prefTimerIdleMinutes - после скольких минут простоя спросить, учитывать ли его в таймере, 0 - не спрашивать
+const prefTimerIdleMinutes = "timer.idle_minutes"
+
+// defaultTimerIdleMinutes - простой по умолчанию, о котором спрашивает таймер
+const defaultTimerIdleMinutes = 5
+
+// idlePollInterval - как часто узнавать простой у системы, пока идет таймер
+const idlePollInterval = 15 * time.Second
+
+// timerIdleMinutes читает из настроек, после скольких минут простоя спрашивать о нем
+func timerIdleMinutes(prefs fyne.Preferences) int {
+	return prefs.IntWithFallback(prefTimerIdleMinutes, defaultTimerIdleMinutes)
+}
+
+// idleTracker замечает, что пользователь отошел от компьютера, пока шел таймер, и, когда
+// он вернулся, спрашивает, отбросить ли время простоя. Простой узнается у системы
+type idleTracker struct {
+	w         fyne.Window
+	threshold time.Duration
+	idle      func() (time.Duration, error)
+	lastPoll  time.Time
+	since     time.Time // начало простоя, о котором еще не спросили
+	asking    bool
+}
+
+func newIdleTracker(w fyne.Window, prefs fyne.Preferences) *idleTracker {
+	return &idleTracker{w: w, threshold: time.Duration(timerIdleMinutes(prefs)) * time.Minute, idle: idle.Duration}
+}
+
+// Poll узнает простой не чаще idlePollInterval и возвращает его начало, когда пользователь
+// вернулся после простоя не короче порога
+func (t *idleTracker) Poll(now time.Time) (from time.Time, back bool) {
+	if t.threshold <= 0 || t.asking || now.Sub(t.lastPoll) < idlePollInterval {
+		return time.Time{}, false
+	}
+	t.lastPoll = now
+	d, err := t.idle()
+	if err != nil {
+		if !errors.Is(err, idle.ErrUnsupported) {
+			slog.Debug("failed to get idle time", "err", err)
+		}
+		return time.Time{}, false
+	}
+	if d >= t.threshold {
+		if t.since.IsZero() {
+			t.since = now.Add(-d)
+		}
+		return time.Time{}, false
+	}
+	if t.since.IsZero() {
+		return time.Time{}, false
+	}
+	from, t.since = t.since, time.Time{}
+	return from, true
+}
+
+// Reset забывает простой, например когда таймер остановили
+func (t *idleTracker) Reset() {
+	t.since = time.Time{}
+}
+
+// Check вызывается каждую секунду, пока идет таймер, запущенный в start. Если пользователь
+// вернулся после простоя, Check спрашивает, учитывать ли простой; discard получает простой,
+// который нужно вычесть из таймера
+func (t *idleTracker) Check(start, now time.Time, discard func(idle time.Duration)) {
+	from, back := t.Poll(now)
+	if !back || start.IsZero() {
+		return
+	}
+	if from.Before(start) {
+		from = start
+	}
+	stretch := now.Sub(from)
+	if stretch < t.threshold {
+		return
+	}
+	t.asking = true
+	message := widget.NewLabel(fmt.Sprintf("С %s вас не было за компьютером (%s), а таймер шел. Учесть это время?",
+		from.Format("15:04"), formatMinutes(int(stretch.Minutes()))))
+	message.Wrapping = fyne.TextWrapWord
+	d := dialog.NewCustomConfirm("Таймер: простой", "Учесть", "Отбросить", message, func(keep bool) {
+		t.asking = false
+		if !keep {
+			discard(stretch)
+		}
+	}, t.w)
+	d.Resize(dialogSize(t.w, fyne.NewSize(420, 0)))
+	d.Show()
+}
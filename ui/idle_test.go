@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/idle"
+)
+
+func TestIdleTrackerPoll(t *testing.T) {
+	var idleFor time.Duration
+	tracker := &idleTracker{threshold: 5 * time.Minute, idle: func() (time.Duration, error) { return idleFor, nil }}
+	now := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+
+	_, back := tracker.Poll(now)
+	assert.False(t, back)
+
+	// Пользователь отошел в 9:52; между опросами простой не узнается заново
+	idleFor = 8 * time.Minute
+	_, back = tracker.Poll(now.Add(idlePollInterval))
+	assert.False(t, back)
+	idleFor = 0
+	_, back = tracker.Poll(now.Add(idlePollInterval + time.Second))
+	assert.False(t, back)
+
+	from, back := tracker.Poll(now.Add(2 * idlePollInterval))
+	assert.True(t, back)
+	assert.Equal(t, now.Add(idlePollInterval-8*time.Minute), from)
+	_, back = tracker.Poll(now.Add(3 * idlePollInterval))
+	assert.False(t, back)
+
+	// Короткий простой и платформа без счетчика не в счет
+	idleFor = time.Minute
+	_, back = tracker.Poll(now.Add(4 * idlePollInterval))
+	assert.False(t, back)
+	tracker.idle = func() (time.Duration, error) { return 0, idle.ErrUnsupported }
+	_, back = tracker.Poll(now.Add(5 * idlePollInterval))
+	assert.False(t, back)
+}
+
+func TestIdleTrackerCheck(t *testing.T) {
+	test.NewTempApp(t)
+	w := test.NewWindow(nil)
+	defer w.Close()
+	idleFor := 20 * time.Minute
+	tracker := &idleTracker{w: w, threshold: 5 * time.Minute, idle: func() (time.Duration, error) { return idleFor, nil }}
+	now := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+	var discarded time.Duration
+	discard := func(stretch time.Duration) { discarded = stretch }
+
+	// Таймер запустили в 9:50, когда пользователь уже отошел: простой считается от запуска
+	start := now.Add(-10 * time.Minute)
+	tracker.Check(start, now, discard)
+	idleFor = 0
+	tracker.Check(start, now.Add(idlePollInterval), discard)
+	assert.True(t, tracker.asking)
+	assert.Zero(t, discarded)
+	test.Tap(findText(w.Canvas().Overlays().Top(), "Отбросить").(*widget.Button))
+	assert.False(t, tracker.asking)
+	assert.Equal(t, 10*time.Minute+idlePollInterval, discarded)
+}
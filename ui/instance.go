@@ -0,0 +1,176 @@
+package ui
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// Команды, которые второй экземпляр передает уже запущенному
+const (
+	instanceCmdShow = "show"
+	instanceCmdAdd  = "add"
+)
+
+// errInstanceRunning означает, что с этим файлом задач уже работает другой экземпляр
+var errInstanceRunning = errors.New("another instance is already running")
+
+// instanceLock - блокировка файла задач одним экземпляром приложения.
+// В файле блокировки хранятся адрес локального сокета, через который
+// второй экземпляр передает команды первому, и ключ для подключения к нему.
+// Файл доступен только владельцу, поэтому другие пользователи и программы на компьютере
+// не могут передавать команды, даже подключившись к сокету
+type instanceLock struct {
+	path     string
+	token    string
+	listener net.Listener
+}
+
+// newInstanceToken создает случайный ключ для подключения к экземпляру
+func newInstanceToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// readInstanceLock читает из файла блокировки адрес экземпляра и ключ подключения
+func readInstanceLock(path string) (addr, token string, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", err
+	}
+	addr, token, _ = strings.Cut(strings.TrimSpace(string(data)), " ")
+	return addr, token, nil
+}
+
+// lockPath возвращает путь к файлу блокировки для файла задач.
+// В имени есть имя компьютера: в блокировке записан локальный адрес, и если файл задач
+// лежит в сетевой папке, экземпляры на разных компьютерах не должны мешать друг другу
+func lockPath(filename string) string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		return filename + ".lock"
+	}
+	return filename + "." + host + ".lock"
+}
+
+// acquireInstanceLock захватывает файл задач для текущего процесса.
+// Если другой экземпляр уже работает, возвращает errInstanceRunning.
+// handler вызывается из отдельной горутины для каждой полученной команды
+func acquireInstanceLock(path string, handler func(cmd, arg string)) (*instanceLock, error) {
+	token, err := newInstanceToken()
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			_, err = file.WriteString(listener.Addr().String() + " " + token)
+			file.Close()
+			if err != nil {
+				listener.Close()
+				os.Remove(path)
+				return nil, err
+			}
+			break
+		}
+		if !os.IsExist(err) || attempt > 0 {
+			listener.Close()
+			return nil, err
+		}
+
+		// Блокировка уже есть: проверяем, жив ли ее владелец
+		if instanceAlive(path) {
+			listener.Close()
+			return nil, errInstanceRunning
+		}
+		// Предыдущий экземпляр завершился аварийно, забираем блокировку
+		os.Remove(path)
+	}
+
+	lock := &instanceLock{path: path, token: token, listener: listener}
+	go lock.serve(handler)
+	return lock, nil
+}
+
+// instanceAlive проверяет, отвечает ли экземпляр, записанный в файле блокировки
+func instanceAlive(path string) bool {
+	addr, _, err := readInstanceLock(path)
+	if err != nil {
+		return false
+	}
+	conn, err := net.DialTimeout("tcp", addr, time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+func (l *instanceLock) serve(handler func(cmd, arg string)) {
+	for {
+		conn, err := l.listener.Accept()
+		if err != nil {
+			return // Блокировка освобождена
+		}
+
+		go func(conn net.Conn) {
+			defer conn.Close()
+			conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+			// Первая строка - ключ из файла блокировки; без него команды не принимаются
+			scanner := bufio.NewScanner(conn)
+			if !scanner.Scan() || subtle.ConstantTimeCompare(scanner.Bytes(), []byte(l.token)) != 1 {
+				return
+			}
+			for scanner.Scan() {
+				cmd, arg, _ := strings.Cut(scanner.Text(), " ")
+				if cmd != "" {
+					handler(cmd, arg)
+				}
+			}
+		}(conn)
+	}
+}
+
+// Release освобождает файл задач для других экземпляров
+func (l *instanceLock) Release() error {
+	err := l.listener.Close()
+	if removeErr := os.Remove(l.path); removeErr != nil && !os.IsNotExist(removeErr) {
+		return removeErr
+	}
+	return err
+}
+
+// sendToInstance передает команду экземпляру, который держит блокировку
+func sendToInstance(path, cmd, arg string) error {
+	addr, token, err := readInstanceLock(path)
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.DialTimeout("tcp", addr, time.Second)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Перевод строки разделяет команды, поэтому в аргументе его быть не должно
+	arg = strings.ReplaceAll(arg, "\n", " ")
+	_, err = fmt.Fprintf(conn, "%s\n%s %s\n", token, cmd, arg)
+	return err
+}
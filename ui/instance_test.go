@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstanceLock(t *testing.T) {
+	path := lockPath(filepath.Join(t.TempDir(), "tasks.json"))
+	defer os.Remove(path)
+	os.Remove(path)
+
+	commands := make(chan string, 10)
+	lock, err := acquireInstanceLock(path, func(cmd, arg string) {
+		commands <- cmd + ":" + arg
+	})
+	assert.NoError(t, err)
+
+	// Второй экземпляр не может захватить тот же файл
+	_, err = acquireInstanceLock(path, func(cmd, arg string) {})
+	assert.ErrorIs(t, err, errInstanceRunning)
+
+	// Команды второго экземпляра доходят до первого
+	assert.NoError(t, sendToInstance(path, instanceCmdAdd, "Купить молоко"))
+	select {
+	case cmd := <-commands:
+		assert.Equal(t, "add:Купить молоко", cmd)
+	case <-time.After(3 * time.Second):
+		t.Fatal("команда не получена")
+	}
+
+	// Файл блокировки с ключом доступен только владельцу, а команды без ключа не принимаются
+	if runtime.GOOS != "windows" {
+		stat, err := os.Stat(path)
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), stat.Mode().Perm())
+	}
+	addr, token, err := readInstanceLock(path)
+	assert.NoError(t, err)
+	assert.Len(t, token, 64)
+	conn, err := net.Dial("tcp", addr)
+	assert.NoError(t, err)
+	fmt.Fprintf(conn, "wrong\n%s чужая команда\n", instanceCmdAdd)
+	conn.Close()
+	assert.NoError(t, sendToInstance(path, instanceCmdShow, ""))
+	select {
+	case cmd := <-commands:
+		assert.Equal(t, "show:", cmd)
+	case <-time.After(3 * time.Second):
+		t.Fatal("команда не получена")
+	}
+
+	// После освобождения блокировку можно захватить снова
+	assert.NoError(t, lock.Release())
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	lock, err = acquireInstanceLock(path, func(cmd, arg string) {})
+	assert.NoError(t, err)
+	assert.NoError(t, lock.Release())
+}
+
+func TestInstanceLockStale(t *testing.T) {
+	path := lockPath(filepath.Join(t.TempDir(), "tasks.json"))
+	defer os.Remove(path)
+
+	// Блокировка осталась после аварийного завершения: по адресу никто не отвечает
+	assert.NoError(t, os.WriteFile(path, []byte("127.0.0.1:1"), 0644))
+
+	lock, err := acquireInstanceLock(path, func(cmd, arg string) {})
+	assert.NoError(t, err)
+	assert.NoError(t, lock.Release())
+}
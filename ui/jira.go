@@ -0,0 +1,387 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/plugins"
+	"taskmanager/task"
+)
+
+// Настройки интеграции с Jira
+const (
+	prefJiraURL     = "jira.url"
+	prefJiraEmail   = "jira.email"
+	prefJiraToken   = "jira.token"
+	prefJiraRefresh = "jira.refresh_minutes" // 0 - обновлять только вручную
+)
+
+// jiraRefreshOptions - как часто обновлять задачи Jira, в минутах
+var jiraRefreshOptions = []struct {
+	Title   string
+	Minutes int
+}{
+	{"Вручную", 0},
+	{"Каждые 15 минут", 15},
+	{"Каждые 30 минут", 30},
+	{"Каждый час", 60},
+}
+
+// jiraSync загружает задачи Jira в список. Загрузка идет в фоне, одновременно - не больше одной
+type jiraSync struct {
+	tm    *task.TaskManager
+	prefs fyne.Preferences
+
+	running   bool
+	last      time.Time
+	lastErr   error
+	listeners map[int]func()
+	nextID    int
+}
+
+// newJiraSync создает загрузку задач Jira по настройкам профиля
+func newJiraSync(tm *task.TaskManager, prefs fyne.Preferences) *jiraSync {
+	return &jiraSync{tm: tm, prefs: prefs, listeners: make(map[int]func())}
+}
+
+// client возвращает подключение к Jira или nil, если Jira не настроена
+func (s *jiraSync) client() *plugins.Jira {
+	if s.prefs.String(prefJiraURL) == "" || s.prefs.String(prefJiraToken) == "" {
+		return nil
+	}
+	return plugins.NewJira(s.prefs.String(prefJiraURL), s.prefs.String(prefJiraEmail), s.prefs.String(prefJiraToken))
+}
+
+// OnChange подписывает на начало и конец загрузки. Возвращает функцию отписки
+func (s *jiraSync) OnChange(fn func()) (unsubscribe func()) {
+	id := s.nextID
+	s.nextID++
+	s.listeners[id] = fn
+	return func() { delete(s.listeners, id) }
+}
+
+func (s *jiraSync) changed() {
+	for _, fn := range s.listeners {
+		fn()
+	}
+}
+
+// Refresh загружает задачи в фоне. Ошибка запоминается и показывается в панели Jira
+func (s *jiraSync) Refresh() {
+	jira := s.client()
+	if jira == nil || s.running {
+		return
+	}
+	s.running = true
+	s.changed()
+	today := s.tm.DueZone().Now()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		issues, err := jira.Assigned(ctx, today)
+		fyne.Do(func() {
+			s.running = false
+			s.lastErr = err
+			if err != nil {
+				slog.Warn("jira refresh failed", "err", err)
+			} else {
+				s.last = time.Now()
+				added, updated, closed := applyJiraIssues(s.tm, jira, issues)
+				slog.Info("jira issues refreshed", "issues", len(issues), "added", added, "updated", updated, "closed", closed)
+			}
+			s.changed()
+		})
+	}()
+}
+
+// Status описывает последнюю загрузку для панели Jira
+func (s *jiraSync) Status() string {
+	switch {
+	case s.client() == nil:
+		return "Jira не настроена"
+	case s.running:
+		return "Обновление…"
+	case s.lastErr != nil:
+		return "Ошибка: " + s.lastErr.Error()
+	case s.last.IsZero():
+		return "Еще не обновлялось"
+	}
+	return "Обновлено в " + s.last.Format("15:04")
+}
+
+// Start обновляет задачи по таймеру из настроек. Возвращает функцию остановки
+func (s *jiraSync) Start() func() {
+	stop := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				fyne.Do(func() {
+					interval := time.Duration(s.prefs.Int(prefJiraRefresh)) * time.Minute
+					if interval > 0 && time.Since(s.last) >= interval {
+						s.Refresh()
+					}
+				})
+			}
+		}
+	}()
+	return func() { close(stop) }
+}
+
+// applyJiraIssues переносит задачи Jira в список. Поля из Jira важнее изменений в приложении;
+// учтенное время, чек-лист и напоминания остаются свои. Задачи, которых больше нет среди
+// назначенных (их решили или передали другому), отмечаются выполненными
+func applyJiraIssues(tm *task.TaskManager, jira *plugins.Jira, issues []*task.Task) (added, updated, closed int) {
+	seen := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		seen[issue.UUID] = true
+		local := tm.GetTaskByUUID(issue.UUID)
+		if local == nil {
+			tm.ApplyRemote(issue)
+			added++
+			continue
+		}
+		if local.Title == issue.Title && local.Description == issue.Description && local.Priority == issue.Priority &&
+			local.DueDate.Equal(issue.DueDate) && local.URL == issue.URL && !local.Completed && !local.Archived {
+			continue
+		}
+		merged := local.Clone()
+		merged.Title, merged.Description, merged.Priority = issue.Title, issue.Description, issue.Priority
+		merged.DueDate, merged.URL = issue.DueDate, issue.URL
+		merged.Completed, merged.CompletedAt, merged.Archived = false, time.Time{}, false
+		merged.UpdatedAt = time.Now()
+		tm.ApplyRemote(merged)
+		updated++
+	}
+	for _, t := range tm.Tasks() {
+		if !t.Completed && !seen[t.UUID] && jira.IssueKey(t) != "" {
+			if err := tm.ToggleTaskCompletion(t.ID); err == nil {
+				closed++
+			}
+		}
+	}
+	return added, updated, closed
+}
+
+// jiraTasks возвращает невыполненные задачи из Jira по сроку
+func jiraTasks(tm *task.TaskManager, jira *plugins.Jira) []*task.Task {
+	var list []*task.Task
+	for _, t := range tm.SortTasksByDueDate() {
+		if !t.Completed && !t.Archived && jira.IssueKey(t) != "" {
+			list = append(list, t)
+		}
+	}
+	return list
+}
+
+// showJiraPanel показывает назначенные пользователю задачи Jira: открыть в браузере,
+// учесть время и отметить выполненной можно прямо отсюда, изменения уходят в Jira
+func showJiraPanel(w fyne.Window, tm *task.TaskManager, prefs fyne.Preferences, sync *jiraSync) {
+	var issues []*task.Task
+	status := widget.NewLabel("")
+	status.Wrapping = fyne.TextWrapWord
+	refreshButton := widget.NewButton("Обновить", sync.Refresh)
+
+	list := widget.NewList(
+		func() int { return len(issues) },
+		func() fyne.CanvasObject {
+			title := widget.NewLabel("")
+			title.Truncation = fyne.TextTruncateEllipsis
+			return container.NewBorder(nil, nil, nil, container.NewHBox(
+				widget.NewButton("Открыть", nil),
+				widget.NewButton("Учесть время…", nil),
+				widget.NewButton("Выполнено", nil),
+			), title)
+		},
+		nil,
+	)
+
+	var update func()
+	list.UpdateItem = func(i widget.ListItemID, item fyne.CanvasObject) {
+		t := issues[i]
+		row := item.(*fyne.Container)
+		row.Objects[0].(*widget.Label).SetText(fmt.Sprintf("%s (срок %s)", t.Title, t.DueDate.Format("02.01.2006")))
+		buttons := row.Objects[1].(*fyne.Container).Objects
+		buttons[0].(*widget.Button).OnTapped = func() {
+			if link, err := url.Parse(t.URL); err == nil {
+				fyne.CurrentApp().OpenURL(link)
+			}
+		}
+		buttons[1].(*widget.Button).OnTapped = func() {
+			showJiraLogWork(w, tm, sync.client(), t)
+		}
+		buttons[2].(*widget.Button).OnTapped = func() {
+			jiraMarkDone(w, tm, sync.client(), t, update)
+		}
+	}
+
+	update = func() {
+		status.SetText(sync.Status())
+		if sync.running {
+			refreshButton.Disable()
+		} else {
+			refreshButton.Enable()
+		}
+		issues = nil
+		if jira := sync.client(); jira != nil {
+			issues = jiraTasks(tm, jira)
+		}
+		list.Refresh()
+	}
+	unsubscribe := sync.OnChange(update)
+
+	settingsButton := widget.NewButton("Настройки…", func() {
+		showJiraSettings(w, prefs, func() {
+			update()
+			sync.Refresh()
+		})
+	})
+	content := container.NewBorder(
+		container.NewBorder(nil, nil, nil, container.NewHBox(refreshButton, settingsButton), status),
+		nil, nil, nil, list)
+	d := dialog.NewCustom("Jira", "Закрыть", content, w)
+	d.SetOnClosed(unsubscribe)
+	d.Resize(dialogSize(w, fyne.NewSize(800, 500)))
+	update()
+	d.Show()
+}
+
+// showJiraSettings задает адрес Jira, учетные данные и частоту обновления
+func showJiraSettings(w fyne.Window, prefs fyne.Preferences, onSaved func()) {
+	urlEntry := widget.NewEntry()
+	urlEntry.SetPlaceHolder("https://company.atlassian.net")
+	urlEntry.SetText(prefs.String(prefJiraURL))
+	urlEntry.Validator = func(text string) error {
+		if text = strings.TrimSpace(text); text == "" {
+			return nil
+		}
+		if u, err := url.Parse(text); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return errors.New("enter the jira address, e.g. https://company.atlassian.net")
+		}
+		return nil
+	}
+	emailEntry := widget.NewEntry()
+	emailEntry.SetText(prefs.String(prefJiraEmail))
+	tokenEntry := widget.NewPasswordEntry()
+	tokenEntry.SetText(prefs.String(prefJiraToken))
+
+	titles := make([]string, len(jiraRefreshOptions))
+	for i, option := range jiraRefreshOptions {
+		titles[i] = option.Title
+	}
+	refreshSelect := widget.NewSelect(titles, nil)
+	refreshSelect.SetSelected(titles[0])
+	for _, option := range jiraRefreshOptions {
+		if option.Minutes == prefs.Int(prefJiraRefresh) {
+			refreshSelect.SetSelected(option.Title)
+		}
+	}
+
+	dialog.ShowForm("Настройки Jira", "Сохранить", "Отмена", []*widget.FormItem{
+		{Text: "Адрес", Widget: urlEntry},
+		{Text: "Почта", Widget: emailEntry, HintText: "Для Jira Cloud; для Jira Server оставьте пустым"},
+		{Text: "Токен API", Widget: tokenEntry, HintText: "Токен API Atlassian или персональный токен Jira Server"},
+		{Text: "Обновление", Widget: refreshSelect},
+	}, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		prefs.SetString(prefJiraURL, strings.TrimSpace(urlEntry.Text))
+		prefs.SetString(prefJiraEmail, strings.TrimSpace(emailEntry.Text))
+		prefs.SetString(prefJiraToken, strings.TrimSpace(tokenEntry.Text))
+		for _, option := range jiraRefreshOptions {
+			if option.Title == refreshSelect.Selected {
+				prefs.SetInt(prefJiraRefresh, option.Minutes)
+			}
+		}
+		onSaved()
+	}, w)
+}
+
+// showJiraLogWork учитывает время в задаче Jira и в задаче приложения
+func showJiraLogWork(w fyne.Window, tm *task.TaskManager, jira *plugins.Jira, t *task.Task) {
+	key := jira.IssueKey(t)
+	minutesEntry := widget.NewEntry()
+	minutesEntry.SetPlaceHolder("30")
+	minutesEntry.Validator = func(text string) error {
+		if minutes, err := strconv.Atoi(strings.TrimSpace(text)); err != nil || minutes <= 0 {
+			return errors.New("enter a whole number of minutes")
+		}
+		return nil
+	}
+	dialog.ShowForm("Учесть время: "+key, "Учесть", "Отмена", []*widget.FormItem{
+		{Text: "Минут", Widget: minutesEntry},
+	}, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		minutes, _ := strconv.Atoi(strings.TrimSpace(minutesEntry.Text))
+		spent := time.Duration(minutes) * time.Minute
+		runJiraAction(w, "Учет времени в "+key, func(ctx context.Context) error {
+			return jira.LogWork(ctx, key, spent)
+		}, func() {
+			if err := tm.AddTaskTime(t.ID, spent); err != nil {
+				dialog.ShowError(err, w)
+			}
+		})
+	}, w)
+}
+
+// jiraMarkDone переводит задачу Jira в выполненные и отмечает выполненной задачу приложения
+func jiraMarkDone(w fyne.Window, tm *task.TaskManager, jira *plugins.Jira, t *task.Task, onDone func()) {
+	key := jira.IssueKey(t)
+	runJiraAction(w, "Закрытие "+key, func(ctx context.Context) error {
+		return jira.MarkDone(ctx, key)
+	}, func() {
+		if !t.Completed {
+			if err := tm.ToggleTaskCompletion(t.ID); err != nil {
+				dialog.ShowError(err, w)
+			}
+		}
+		onDone()
+	})
+}
+
+// runJiraAction выполняет запрос к Jira в фоне, пока показывается индикатор.
+// onSuccess вызывается в потоке интерфейса
+func runJiraAction(w fyne.Window, title string, action func(ctx context.Context) error, onSuccess func()) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	progress := dialog.NewCustomWithoutButtons("Jira", container.NewVBox(
+		widget.NewLabel(title),
+		widget.NewProgressBarInfinite(),
+	), w)
+	progress.SetButtons([]fyne.CanvasObject{widget.NewButton("Отмена", cancel)})
+	progress.Show()
+
+	go func() {
+		err := action(ctx)
+		fyne.Do(func() {
+			cancel()
+			progress.Hide()
+			switch {
+			case errors.Is(err, context.Canceled):
+			case err != nil:
+				slog.Error("jira request failed", "action", title, "err", err)
+				dialog.ShowError(err, w)
+			default:
+				onSuccess()
+			}
+		})
+	}()
+}
@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"taskmanager/plugins"
+	"taskmanager/task"
+)
+
+func TestApplyJiraIssues(t *testing.T) {
+	tm := newTestManager(t)
+	own := mustAddTask(t, tm, "Своя задача", "", 2, time.Now())
+	jira := plugins.NewJira("https://jira.example.com", "", "token")
+	issue := func(key, title string, priority int) *task.Task {
+		return &task.Task{UUID: jira.IssueUUID(key), Title: key + " " + title, Priority: priority,
+			URL: jira.IssueURL(key), Tags: []string{plugins.JiraTag}, DueDate: time.Now().Truncate(24 * time.Hour),
+			CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	}
+
+	added, updated, closed := applyJiraIssues(tm, jira, []*task.Task{issue("APP-1", "Отчет", 2), issue("APP-2", "Релиз", 3)})
+	assert.Equal(t, []int{2, 0, 0}, []int{added, updated, closed})
+	assert.Len(t, jiraTasks(tm, jira), 2)
+
+	// Учтенное в приложении время сохраняется, поля из Jira обновляются,
+	// а задача, которой больше нет среди назначенных, считается выполненной
+	first := tm.GetTaskByUUID(jira.IssueUUID("APP-1"))
+	require.NoError(t, tm.AddTaskTime(first.ID, time.Hour))
+	added, updated, closed = applyJiraIssues(tm, jira, []*task.Task{issue("APP-1", "Квартальный отчет", 3)})
+	assert.Equal(t, []int{0, 1, 1}, []int{added, updated, closed})
+	first = tm.GetTaskByUUID(jira.IssueUUID("APP-1"))
+	assert.Equal(t, "APP-1 Квартальный отчет", first.Title)
+	assert.Equal(t, 3, first.Priority)
+	assert.Equal(t, time.Hour, first.TimeSpent)
+	assert.True(t, tm.GetTaskByUUID(jira.IssueUUID("APP-2")).Completed)
+	assert.False(t, tm.GetTask(own.ID).Completed, "задачи не из Jira не меняются")
+
+	// Без изменений в Jira повторное обновление ничего не трогает
+	added, updated, closed = applyJiraIssues(tm, jira, []*task.Task{issue("APP-1", "Квартальный отчет", 3)})
+	assert.Equal(t, []int{0, 0, 0}, []int{added, updated, closed})
+}
@@ -0,0 +1,162 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/task"
+)
+
+// mentionSegments разбивает описание на текст и ссылки на упомянутые задачи (#42).
+// Упоминание несуществующей задачи остается текстом
+func mentionSegments(text string, tm *task.TaskManager, openTask func(id int)) []widget.RichTextSegment {
+	var segments []widget.RichTextSegment
+	addText := func(s string) {
+		if s != "" {
+			segments = append(segments, &widget.TextSegment{Text: s, Style: widget.RichTextStyleInline})
+		}
+	}
+	last := 0
+	for _, mention := range task.FindMentions(text) {
+		linked := tm.GetTask(mention.ID)
+		if linked == nil {
+			continue
+		}
+		addText(text[last:mention.Start])
+		id := mention.ID
+		segments = append(segments, &widget.HyperlinkSegment{
+			Text:      text[mention.Start:mention.End] + " " + linked.Title,
+			OnTapped:  func() { openTask(id) },
+			Alignment: fyne.TextAlignLeading,
+		})
+		last = mention.End
+	}
+	if last == 0 {
+		return nil
+	}
+	addText(text[last:])
+	return segments
+}
+
+// errNoLink возвращается, если у задачи нет ссылки
+var errNoLink = errors.New("task has no link")
+
+// openTaskLink открывает ссылку задачи в браузере
+func openTaskLink(a fyne.App, t *task.Task) error {
+	link := t.LinkURL()
+	if link == "" {
+		return errNoLink
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return err
+	}
+	return a.OpenURL(u)
+}
+
+// errNoLocation возвращается, если у задачи не указано место
+var errNoLocation = errors.New("task has no location")
+
+// Варианты фильтра по месту, кроме самих мест
+const (
+	locationAll  = "Все места"
+	locationNone = "Без места"
+)
+
+// openTaskMap открывает место задачи на карте
+func openTaskMap(a fyne.App, t *task.Task) error {
+	if t.Location == "" {
+		return errNoLocation
+	}
+	u, err := url.Parse(task.MapsURL(t.Location))
+	if err != nil {
+		return err
+	}
+	return a.OpenURL(u)
+}
+
+// linkedTasksView - раздел «Связанные задачи» окна задачи
+type linkedTasksView struct {
+	tm       *task.TaskManager
+	openTask func(id int)
+	showErr  func(error)
+	list     *fyne.Container
+	idEntry  *widget.Entry
+	box      *fyne.Container
+}
+
+func newLinkedTasksView(tm *task.TaskManager, openTask func(id int), showErr func(error)) *linkedTasksView {
+	v := &linkedTasksView{tm: tm, openTask: openTask, showErr: showErr, list: container.NewVBox(), idEntry: widget.NewEntry()}
+	v.idEntry.SetPlaceHolder("#42")
+	v.box = container.NewVBox(
+		widget.NewLabelWithStyle("Связанные задачи", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		v.list,
+	)
+	return v
+}
+
+// parseTaskRef разбирает номер задачи, введенный как 42 или #42
+func parseTaskRef(text string) (int, error) {
+	id, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(text), "#"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid task number %q", text)
+	}
+	return id, nil
+}
+
+// parseTaskRefs разбирает список номеров задач через запятую: «#3, 7»
+func parseTaskRefs(text string) ([]int, error) {
+	var ids []int
+	for _, ref := range strings.Split(text, ",") {
+		if strings.TrimSpace(ref) == "" {
+			continue
+		}
+		id, err := parseTaskRef(ref)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// formatTaskRefs показывает задачи списком номеров для поля ввода
+func formatTaskRefs(tasks []*task.Task) string {
+	refs := make([]string, len(tasks))
+	for i, t := range tasks {
+		refs[i] = fmt.Sprintf("#%d", t.ID)
+	}
+	return strings.Join(refs, ", ")
+}
+
+// Update показывает связи задачи t
+func (v *linkedTasksView) Update(t *task.Task) {
+	v.list.RemoveAll()
+	for _, linked := range v.tm.LinkedTasks(t.ID) {
+		id := linked.ID
+		open := widget.NewButton(fmt.Sprintf("#%d %s", linked.ID, linked.Title), func() { v.openTask(id) })
+		open.Alignment = widget.ButtonAlignLeading
+		unlink := widget.NewButtonWithIcon("Отвязать", theme.ContentRemoveIcon(), func() { v.showErr(v.tm.UnlinkTasks(t.ID, id)) })
+		v.list.Add(container.NewBorder(nil, nil, nil, unlink, open))
+	}
+	link := func() {
+		id, err := parseTaskRef(v.idEntry.Text)
+		if err == nil {
+			err = v.tm.LinkTasks(t.ID, id)
+		}
+		if err == nil {
+			v.idEntry.SetText("")
+		}
+		v.showErr(err)
+	}
+	v.idEntry.OnSubmitted = func(string) { link() }
+	v.list.Add(container.NewBorder(nil, nil, nil, widget.NewButton("Связать", link), v.idEntry))
+}
@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+func TestMentionSegments(t *testing.T) {
+	tm := newTestManager(t)
+	plan := mustAddTask(t, tm, "Составить план", "", 2, time.Now())
+
+	var opened int
+	segments := mentionSegments("См. #1 и #99.", tm, func(id int) { opened = id })
+	if assert.Len(t, segments, 3) {
+		assert.Equal(t, "См. ", segments[0].(*widget.TextSegment).Text)
+		link := segments[1].(*widget.HyperlinkSegment)
+		assert.Equal(t, "#1 Составить план", link.Text)
+		link.OnTapped()
+		assert.Equal(t, plan.ID, opened)
+		// Несуществующая задача остается текстом
+		assert.Equal(t, " и #99.", segments[2].(*widget.TextSegment).Text)
+	}
+	assert.Nil(t, mentionSegments("Без упоминаний", tm, nil))
+}
+
+func TestParseTaskRef(t *testing.T) {
+	id, err := parseTaskRef(" #42 ")
+	assert.NoError(t, err)
+	assert.Equal(t, 42, id)
+	id, _ = parseTaskRef("7")
+	assert.Equal(t, 7, id)
+	_, err = parseTaskRef("сорок два")
+	assert.Error(t, err)
+}
+
+func TestParseTaskRefs(t *testing.T) {
+	ids, err := parseTaskRefs("#3, 7,")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 7}, ids)
+	ids, err = parseTaskRefs(" ")
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+	_, err = parseTaskRefs("#3, три")
+	assert.Error(t, err)
+
+	assert.Equal(t, "#3, #7", formatTaskRefs([]*task.Task{{ID: 3}, {ID: 7}}))
+}
+
+func TestOpenTaskLink(t *testing.T) {
+	a := test.NewTempApp(t)
+	assert.ErrorIs(t, openTaskLink(a, &task.Task{Description: "Без ссылки"}), errNoLink)
+	assert.NoError(t, openTaskLink(a, &task.Task{Description: "Прочитать https://go.dev/blog"}))
+}
+
+func TestOpenTaskMap(t *testing.T) {
+	a := test.NewTempApp(t)
+	assert.ErrorIs(t, openTaskMap(a, &task.Task{}), errNoLocation)
+	assert.NoError(t, openTaskMap(a, &task.Task{Location: "55.75, 37.62"}))
+}
@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Панель форматирования описания вставляет разметку Markdown, поэтому форматировать заметки
+// можно, не зная ее. Правки считаются в символах, а не байтах
+
+// wrapMarkdown обрамляет выделенный текст [start, end) разметкой before и after: «**жирный**».
+// Без выделения вставляется placeholder. Возвращает новый текст и положение курсора
+// после вставленного текста, перед закрывающей разметкой
+func wrapMarkdown(text string, start, end int, before, after, placeholder string) (string, int) {
+	runes := []rune(text)
+	selected := string(runes[start:end])
+	if selected == "" {
+		selected = placeholder
+	}
+	inserted := before + selected
+	result := string(runes[:start]) + inserted + after + string(runes[end:])
+	return result, start + len([]rune(inserted))
+}
+
+// linkMarkdown превращает выделенный текст в ссылку. Выделенный адрес становится адресом
+// ссылки, другой текст - ее подписью
+func linkMarkdown(text string, start, end int) (string, int) {
+	selected := string([]rune(text)[start:end])
+	if strings.Contains(selected, "://") {
+		return wrapMarkdown(text, start, end, "[ссылка](", ")", "")
+	}
+	result, cursor := wrapMarkdown(text, start, end, "[", "](https://)", "ссылка")
+	// Курсор ставится в адрес, который осталось вписать
+	return result, cursor + len("](https://")
+}
+
+// prefixMarkdown добавляет prefix в начало строк, на которых лежит выделение [start, end):
+// «- » делает их списком, «## » - заголовком. Строки, где prefix уже есть, не меняются.
+// Возвращает новый текст и положение курсора в конце последней строки выделения
+func prefixMarkdown(text string, start, end int, prefix string) (string, int) {
+	runes := []rune(text)
+	lineStart := start
+	for lineStart > 0 && runes[lineStart-1] != '\n' {
+		lineStart--
+	}
+	lineEnd := end
+	for lineEnd < len(runes) && runes[lineEnd] != '\n' {
+		lineEnd++
+	}
+	lines := strings.Split(string(runes[lineStart:lineEnd]), "\n")
+	for i, line := range lines {
+		if !strings.HasPrefix(line, prefix) {
+			lines[i] = prefix + line
+		}
+	}
+	block := strings.Join(lines, "\n")
+	result := string(runes[:lineStart]) + block + string(runes[lineEnd:])
+	return result, lineStart + len([]rune(block))
+}
+
+// entrySelection возвращает выделение поля в символах текста. Без выделения начало и конец
+// совпадают с курсором. Строки поля должны совпадать со строками текста, то есть поле
+// не переносит строки
+func entrySelection(e *widget.Entry) (start, end int) {
+	runes := []rune(e.Text)
+	cursor := 0
+	for row := 0; row < e.CursorRow && cursor < len(runes); cursor++ {
+		if runes[cursor] == '\n' {
+			row++
+		}
+	}
+	cursor = min(cursor+e.CursorColumn, len(runes))
+	selected := []rune(e.SelectedText())
+	// Курсор стоит на одном из концов выделения
+	if n := len(selected); n > 0 {
+		if cursor >= n && string(runes[cursor-n:cursor]) == string(selected) {
+			return cursor - n, cursor
+		}
+		if cursor+n <= len(runes) && string(runes[cursor:cursor+n]) == string(selected) {
+			return cursor, cursor + n
+		}
+	}
+	return cursor, cursor
+}
+
+// setEntryCursor ставит курсор поля на символ offset текста
+func setEntryCursor(e *widget.Entry, offset int) {
+	runes := []rune(e.Text)
+	row, col := 0, 0
+	for _, r := range runes[:min(offset, len(runes))] {
+		if r == '\n' {
+			row, col = row+1, 0
+		} else {
+			col++
+		}
+	}
+	e.CursorRow, e.CursorColumn = row, col
+	e.Refresh()
+}
+
+// newMarkdownEditor собирает поле описания с панелью форматирования и вкладкой просмотра,
+// где разметка Markdown показана так, как она будет выглядеть
+func newMarkdownEditor(w fyne.Window, entry *spellEntry) fyne.CanvasObject {
+	apply := func(edit func(text string, start, end int) (string, int)) {
+		start, end := entrySelection(&entry.Entry)
+		text, cursor := edit(entry.Text, start, end)
+		entry.SetText(text)
+		setEntryCursor(&entry.Entry, cursor)
+		w.Canvas().Focus(entry)
+	}
+	wrap := func(before, after, placeholder string) func() {
+		return func() {
+			apply(func(text string, start, end int) (string, int) {
+				return wrapMarkdown(text, start, end, before, after, placeholder)
+			})
+		}
+	}
+	prefix := func(prefix string) func() {
+		return func() {
+			apply(func(text string, start, end int) (string, int) {
+				return prefixMarkdown(text, start, end, prefix)
+			})
+		}
+	}
+	button := func(label string, tapped func()) *widget.Button {
+		b := widget.NewButton(label, tapped)
+		b.Importance = widget.LowImportance
+		return b
+	}
+	toolbar := container.NewHBox(
+		button("Ж", wrap("**", "**", "жирный")),
+		button("К", wrap("*", "*", "курсив")),
+		button("Заголовок", prefix("## ")),
+		button("Список", prefix("- ")),
+		button("Ссылка", func() { apply(linkMarkdown) }),
+	)
+
+	preview := widget.NewRichTextFromMarkdown("")
+	preview.Wrapping = fyne.TextWrapWord
+	tabs := container.NewAppTabs(
+		container.NewTabItem("Текст", container.NewBorder(toolbar, nil, nil, nil, entry)),
+		container.NewTabItem("Просмотр", container.NewVScroll(preview)),
+	)
+	tabs.OnSelected = func(*container.TabItem) {
+		preview.ParseMarkdown(entry.Text)
+	}
+	return tabs
+}
@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapMarkdown(t *testing.T) {
+	text, cursor := wrapMarkdown("Купить молоко", 7, 13, "**", "**", "жирный")
+	assert.Equal(t, "Купить **молоко**", text)
+	assert.Equal(t, 15, cursor)
+
+	text, cursor = wrapMarkdown("Купить ", 7, 7, "*", "*", "курсив")
+	assert.Equal(t, "Купить *курсив*", text)
+	assert.Equal(t, 14, cursor)
+}
+
+func TestLinkMarkdown(t *testing.T) {
+	text, cursor := linkMarkdown("См. отчет", 4, 9)
+	assert.Equal(t, "См. [отчет](https://)", text)
+	assert.Equal(t, 20, cursor)
+
+	text, _ = linkMarkdown("См. https://x.ru", 4, 16)
+	assert.Equal(t, "См. [ссылка](https://x.ru)", text)
+}
+
+func TestPrefixMarkdown(t *testing.T) {
+	text, cursor := prefixMarkdown("План\nхлеб\n- молоко\nсыр", 7, 12, "- ")
+	assert.Equal(t, "План\n- хлеб\n- молоко\nсыр", text)
+	assert.Equal(t, 20, cursor)
+
+	text, _ = prefixMarkdown("Итоги", 0, 0, "## ")
+	assert.Equal(t, "## Итоги", text)
+}
+
+func TestEntrySelection(t *testing.T) {
+	test.NewTempApp(t)
+	entry := widget.NewMultiLineEntry()
+	entry.SetText("План\nхлеб")
+	setEntryCursor(entry, 7)
+	assert.Equal(t, 1, entry.CursorRow)
+	assert.Equal(t, 2, entry.CursorColumn)
+	start, end := entrySelection(entry)
+	assert.Equal(t, 7, start)
+	assert.Equal(t, 7, end)
+}
@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/storage"
+	"taskmanager/task"
+)
+
+// showMergeDialog выбирает другой файл задач и сливает его с текущим списком
+func showMergeDialog(w fyne.Window, tm *task.TaskManager) {
+	dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		path := reader.URI().Path()
+		reader.Close()
+
+		mergeFile(w, tm, path, func() {})
+	}, w)
+}
+
+// mergeFile загружает файл задач path, запрашивая пароль, если он зашифрован,
+// и сливает его с текущим списком. onDone вызывается, когда все конфликты разрешены
+func mergeFile(w fyne.Window, tm *task.TaskManager, path string, onDone func()) {
+	// Копии конфликтов и файлы с других компьютеров могут быть в формате из настроек
+	other := task.NewTaskManager(storage.NewEncoded(storage.NewFile(path), storageCodec(fyne.CurrentApp().Preferences())))
+	merge := func() {
+		showMergeResult(w, tm, tm.MergeFrom(other), onDone)
+	}
+
+	err := other.LoadFromFile()
+	switch {
+	case errors.Is(err, task.ErrPassphraseRequired):
+		showUnlockDialog(w, other, merge, func() {})
+	case err != nil:
+		dialog.ShowError(err, w)
+	default:
+		merge()
+	}
+}
+
+// showMergeResult помогает разрешить конфликты слияния: оставить более новые версии задач
+// или выбрать поля вручную. onDone вызывается, когда все конфликты разрешены
+func showMergeResult(w fyne.Window, tm *task.TaskManager, result task.MergeResult, onDone func()) {
+	if len(result.Conflicts) == 0 {
+		showMergeConflicts(w, tm, result, 0, onDone)
+		return
+	}
+
+	message := fmt.Sprintf("Задач, измененных в обоих файлах: %d.\n"+
+		"Оставить более новую версию каждой задачи или выбрать поля вручную?", len(result.Conflicts))
+	dialog.ShowCustomConfirm("Конфликты при слиянии", "Более новые", "Вручную", widget.NewLabel(message), func(newest bool) {
+		if !newest {
+			showMergeConflicts(w, tm, result, 0, onDone)
+			return
+		}
+		taken := tm.ResolveNewest(result.Conflicts)
+		info := dialog.NewInformation("Слияние завершено",
+			fmt.Sprintf("Добавлено задач: %d\nОбновлено из файла: %d\nОставлено своих: %d",
+				result.Added, taken, len(result.Conflicts)-taken), w)
+		info.SetOnClosed(onDone)
+		info.Show()
+	}, w)
+}
+
+// showMergeConflicts по очереди показывает конфликты слияния, начиная с index
+func showMergeConflicts(w fyne.Window, tm *task.TaskManager, result task.MergeResult, index int, onDone func()) {
+	if index >= len(result.Conflicts) {
+		info := dialog.NewInformation("Слияние завершено",
+			fmt.Sprintf("Добавлено задач: %d\nКонфликтов: %d", result.Added, len(result.Conflicts)), w)
+		info.SetOnClosed(onDone)
+		info.Show()
+		return
+	}
+	conflict := result.Conflicts[index]
+
+	content := container.NewVBox(widget.NewLabelWithStyle(
+		fmt.Sprintf("Задача «%s» изменена в обоих файлах (%d из %d).", conflict.Local.Title, index+1, len(result.Conflicts)),
+		fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+	content.Add(widget.NewLabel(fmt.Sprintf("Изменена здесь: %s, в другом файле: %s",
+		conflict.Local.UpdatedAt.Format("2006-01-02 15:04"), conflict.Remote.UpdatedAt.Format("2006-01-02 15:04"))))
+	content.Add(widget.NewLabel("Отметьте поля, которые нужно взять из другого файла:"))
+
+	checks := make(map[string]*widget.Check)
+	for _, field := range task.MergeFields {
+		if !containsString(conflict.Fields, field.Key) {
+			continue
+		}
+		check := widget.NewCheck(fmt.Sprintf("%s: «%s» → «%s»",
+			field.Title, field.Value(conflict.Local), field.Value(conflict.Remote)), nil)
+		checks[field.Key] = check
+		content.Add(check)
+	}
+
+	next := func() {
+		showMergeConflicts(w, tm, result, index+1, onDone)
+	}
+	d := dialog.NewCustomConfirm("Конфликт при слиянии", "Применить", "Оставить мои", content, func(confirmed bool) {
+		if confirmed {
+			var fields []string
+			for key, check := range checks {
+				if check.Checked {
+					fields = append(fields, key)
+				}
+			}
+			if err := tm.ResolveConflict(conflict, fields); err != nil {
+				dialog.ShowError(err, w)
+			}
+		}
+		next()
+	}, w)
+	d.Show()
+}
+
+// offerConflictCopies предлагает слить копии файла задач, которые Syncthing создает,
+// когда файл одновременно изменили на двух компьютерах. После слияния задачи сохраняются,
+// а копия удаляется. onDone вызывается, когда копий не осталось или пользователь отказался
+func offerConflictCopies(w fyne.Window, tm *task.TaskManager, store *storage.File, onDone func()) {
+	copies, err := store.ConflictCopies()
+	if err != nil || len(copies) == 0 {
+		onDone()
+		return
+	}
+	path := copies[0]
+
+	message := fmt.Sprintf("Файл задач одновременно изменили на двух компьютерах,\n"+
+		"вторая версия сохранена в %s. Слить ее с текущим списком?", filepath.Base(path))
+	dialog.ShowConfirm("Конфликт синхронизации", message, func(confirmed bool) {
+		if !confirmed {
+			onDone()
+			return
+		}
+		mergeFile(w, tm, path, func() {
+			saveTasks(w, tm, func() {
+				if err := os.Remove(path); err != nil {
+					slog.Warn("failed to remove merged conflict copy", "file", path, "err", err)
+					onDone()
+					return
+				}
+				offerConflictCopies(w, tm, store, onDone)
+			})
+		})
+	}, w)
+}
@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// isCompactScreen сообщает, что приложение запущено на телефоне или планшете: тогда окно
+// собирается в одну колонку, а диалоги занимают весь экран
+func isCompactScreen() bool {
+	return fyne.CurrentDevice().IsMobile()
+}
+
+// dialogSize возвращает размер диалога: size на компьютере и весь экран окна w на телефоне
+func dialogSize(w fyne.Window, size fyne.Size) fyne.Size {
+	if isCompactScreen() {
+		return w.Canvas().Size()
+	}
+	return size
+}
+
+// newFloatingButton размещает круглую кнопку «+» в правом нижнем углу поверх content
+func newFloatingButton(content fyne.CanvasObject, tapped func()) *fyne.Container {
+	button := widget.NewButtonWithIcon("", theme.ContentAddIcon(), tapped)
+	button.Importance = widget.HighImportance
+	corner := container.NewVBox(layout.NewSpacer(), container.NewHBox(layout.NewSpacer(), button))
+	return container.NewStack(content, container.NewPadded(corner))
+}
@@ -0,0 +1,219 @@
+package ui
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/mqtt"
+	"taskmanager/task"
+)
+
+// Настройки публикации в MQTT; пустой адрес брокера - публикация выключена
+const (
+	prefMQTTBroker    = "mqtt.broker"
+	prefMQTTUsername  = "mqtt.username"
+	prefMQTTPassword  = "mqtt.password"
+	prefMQTTTopic     = "mqtt.topic"
+	prefMQTTDiscovery = "mqtt.discovery"
+	prefMQTTClientID  = "mqtt.client_id"
+)
+
+// mqttQueueSize - сколько пачек сообщений ждут отправки, пока брокер недоступен
+const mqttQueueSize = 32
+
+// mqttBatch - сообщения, которые отправляются за одно подключение
+type mqttBatch struct {
+	cfg      mqtt.Config
+	messages []mqtt.Message
+}
+
+// mqttHost публикует события задач и счетчики в брокер из настроек профиля.
+// Сообщения собираются в потоке интерфейса, а отправляются по очереди в фоне
+type mqttHost struct {
+	prefs fyne.Preferences
+	tm    *task.TaskManager
+
+	batches       chan mqttBatch
+	lastCounts    string
+	discoverySent bool
+}
+
+// newMQTTHost создает публикацию и запускает отправку в фоне
+func newMQTTHost(prefs fyne.Preferences, tm *task.TaskManager) *mqttHost {
+	h := &mqttHost{prefs: prefs, tm: tm, batches: make(chan mqttBatch, mqttQueueSize)}
+	go func() {
+		for batch := range h.batches {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			if err := mqtt.Publish(ctx, batch.cfg, batch.messages); err != nil {
+				slog.Warn("failed to publish to mqtt", "broker", batch.cfg.Broker, "err", err)
+			}
+			cancel()
+		}
+	}()
+	return h
+}
+
+// Start подписывается на изменения задач. Возвращает функцию отписки
+func (h *mqttHost) Start() func() {
+	unsubscribe := h.tm.Subscribe(h.onEvent)
+	h.CheckCounts()
+	return unsubscribe
+}
+
+// config возвращает подключение и тему из настроек; ok - публикация включена
+func (h *mqttHost) config() (cfg mqtt.Config, topic string, ok bool) {
+	broker := h.prefs.String(prefMQTTBroker)
+	if broker == "" {
+		return mqtt.Config{}, "", false
+	}
+	clientID := h.prefs.String(prefMQTTClientID)
+	if clientID == "" {
+		id := make([]byte, 4)
+		rand.Read(id)
+		clientID = "taskmanager-" + hex.EncodeToString(id)
+		h.prefs.SetString(prefMQTTClientID, clientID)
+	}
+	cfg = mqtt.Config{
+		Broker:   broker,
+		ClientID: clientID,
+		Username: h.prefs.String(prefMQTTUsername),
+		Password: h.prefs.String(prefMQTTPassword),
+	}
+	return cfg, h.prefs.StringWithFallback(prefMQTTTopic, mqtt.DefaultTopic), true
+}
+
+func (h *mqttHost) onEvent(event task.Event) {
+	_, topic, ok := h.config()
+	if !ok {
+		return
+	}
+	var messages []mqtt.Message
+	if event.Op != task.EventReloaded {
+		messages = mqtt.EventMessage(topic, event)
+	}
+	h.send(append(messages, h.changedCounts(topic)...))
+}
+
+// CheckCounts публикует счетчики, если они изменились: задачи становятся просроченными
+// и без изменений, с наступлением дня
+func (h *mqttHost) CheckCounts() {
+	if _, topic, ok := h.config(); ok {
+		h.send(h.changedCounts(topic))
+	}
+}
+
+// changedCounts возвращает сообщение со счетчиками, если они изменились с прошлой публикации
+func (h *mqttHost) changedCounts(topic string) []mqtt.Message {
+	message := mqtt.CountsMessage(topic, mqtt.CountTasks(h.tm.Tasks(), h.tm.DueZone().Now()))
+	if string(message.Payload) == h.lastCounts {
+		return nil
+	}
+	h.lastCounts = string(message.Payload)
+	return []mqtt.Message{message}
+}
+
+// send ставит сообщения в очередь. При первой отправке публикуются и настройки
+// обнаружения Home Assistant, если они включены
+func (h *mqttHost) send(messages []mqtt.Message) {
+	cfg, topic, ok := h.config()
+	if !ok || len(messages) == 0 {
+		return
+	}
+	if !h.discoverySent && h.prefs.Bool(prefMQTTDiscovery) {
+		messages = append(mqtt.DiscoveryMessages(topic), messages...)
+		h.discoverySent = true
+	}
+	select {
+	case h.batches <- mqttBatch{cfg: cfg, messages: messages}:
+	default:
+		slog.Warn("mqtt queue is full, dropping messages", "messages", len(messages))
+	}
+}
+
+// reset публикует счетчики и настройки обнаружения заново, например после смены брокера
+func (h *mqttHost) reset() {
+	h.lastCounts = ""
+	h.discoverySent = false
+	h.CheckCounts()
+}
+
+// showMQTTDialog настраивает публикацию событий и счетчиков задач в брокер MQTT
+func showMQTTDialog(w fyne.Window, prefs fyne.Preferences, host *mqttHost) {
+	brokerEntry := widget.NewEntry()
+	brokerEntry.SetPlaceHolder("tcp://homeassistant.local:1883")
+	brokerEntry.SetText(prefs.String(prefMQTTBroker))
+	brokerEntry.Validator = func(text string) error {
+		if text = strings.TrimSpace(text); text == "" {
+			return nil
+		}
+		return mqtt.ValidateBroker(text)
+	}
+	usernameEntry := widget.NewEntry()
+	usernameEntry.SetText(prefs.String(prefMQTTUsername))
+	passwordEntry := widget.NewPasswordEntry()
+	passwordEntry.SetText(prefs.String(prefMQTTPassword))
+	topicEntry := widget.NewEntry()
+	topicEntry.SetPlaceHolder(mqtt.DefaultTopic)
+	topicEntry.SetText(prefs.String(prefMQTTTopic))
+	topicEntry.Validator = func(text string) error {
+		if strings.ContainsAny(text, "+#") {
+			return errors.New("topic must not contain + or #")
+		}
+		return nil
+	}
+	discoveryCheck := widget.NewCheck("Обнаружение Home Assistant", nil)
+	discoveryCheck.SetChecked(prefs.Bool(prefMQTTDiscovery))
+
+	testButton := widget.NewButton("Проверить подключение", func() {
+		cfg := mqtt.Config{
+			Broker:   strings.TrimSpace(brokerEntry.Text),
+			ClientID: "taskmanager-test",
+			Username: strings.TrimSpace(usernameEntry.Text),
+			Password: passwordEntry.Text,
+		}
+		if err := mqtt.ValidateBroker(cfg.Broker); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			defer cancel()
+			err := mqtt.Publish(ctx, cfg, nil)
+			fyne.Do(func() {
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				dialog.ShowInformation("MQTT", "Брокер принял подключение", w)
+			})
+		}()
+	})
+
+	dialog.ShowForm("Публикация в MQTT", "Сохранить", "Отмена", []*widget.FormItem{
+		{Text: "Брокер", Widget: brokerEntry, HintText: "Пусто - не публиковать; mqtts:// - с TLS"},
+		{Text: "Пользователь", Widget: usernameEntry},
+		{Text: "Пароль", Widget: passwordEntry},
+		{Text: "Тема", Widget: topicEntry, HintText: "События - в <тема>/event, счетчики - в <тема>/counts"},
+		{Text: "", Widget: discoveryCheck},
+		{Text: "", Widget: testButton},
+	}, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		prefs.SetString(prefMQTTBroker, strings.TrimSpace(brokerEntry.Text))
+		prefs.SetString(prefMQTTUsername, strings.TrimSpace(usernameEntry.Text))
+		prefs.SetString(prefMQTTPassword, passwordEntry.Text)
+		prefs.SetString(prefMQTTTopic, strings.Trim(strings.TrimSpace(topicEntry.Text), "/"))
+		prefs.SetBool(prefMQTTDiscovery, discoveryCheck.Checked)
+		host.reset()
+	}, w)
+}
@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"taskmanager/mqtt"
+)
+
+func TestMQTTHostQueuesChanges(t *testing.T) {
+	a := test.NewTempApp(t)
+	prefs := a.Preferences()
+	tm := newTestManager(t)
+	// Без фоновой отправки: пачки остаются в очереди для проверки
+	h := &mqttHost{prefs: prefs, tm: tm, batches: make(chan mqttBatch, mqttQueueSize)}
+	unsubscribe := h.Start()
+	defer unsubscribe()
+
+	mustAddTask(t, tm, "Полить цветы", "", 2, time.Now())
+	assert.Empty(t, h.batches, "без брокера ничего не публикуется")
+
+	prefs.SetString(prefMQTTBroker, "tcp://localhost:1883")
+	prefs.SetBool(prefMQTTDiscovery, true)
+	mustAddTask(t, tm, "Вынести мусор", "", 2, time.Now())
+	require.Len(t, h.batches, 1)
+	batch := <-h.batches
+	assert.NotEmpty(t, batch.cfg.ClientID)
+	topics := make([]string, len(batch.messages))
+	for i, message := range batch.messages {
+		topics[i] = message.Topic
+	}
+	assert.Contains(t, topics, "homeassistant/sensor/taskmanager/open/config")
+	assert.Contains(t, topics, "taskmanager/event/added")
+	assert.Equal(t, "taskmanager/counts", topics[len(topics)-1])
+	assert.JSONEq(t, `{"open":2,"overdue":0,"today":2,"completed_today":0}`, string(batch.messages[len(batch.messages)-1].Payload))
+
+	// Счетчики не изменились - публикуются только события, без повторного обнаружения
+	h.CheckCounts()
+	assert.Empty(t, h.batches)
+	first := tm.Tasks()[0]
+	require.NoError(t, tm.UpdateTask(first.ID, "Полить все цветы", "", first.Priority, first.DueDate, false))
+	require.Len(t, h.batches, 1)
+	batch = <-h.batches
+	assert.Equal(t, []string{"taskmanager/event", "taskmanager/event/updated"},
+		[]string{batch.messages[0].Topic, batch.messages[1].Topic})
+	assert.Len(t, batch.messages, 2)
+
+	prefs.SetString(prefMQTTTopic, "home/tasks")
+	h.reset()
+	require.Len(t, h.batches, 1)
+	batch = <-h.batches
+	assert.Len(t, batch.messages, len(mqtt.DiscoveryMessages("home/tasks"))+1)
+}
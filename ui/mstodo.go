@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/plugins"
+	"taskmanager/storage"
+	"taskmanager/task"
+)
+
+// prefMicrosoftClientID - приложение Azure для входа в Microsoft To Do
+const prefMicrosoftClientID = "mstodo.client_id"
+
+// showMicrosoftToDoImport загружает задачи из Microsoft To Do: спрашивает приложение Azure,
+// открывает вход в браузере и добавляет задачи всех списков. Токен не сохраняется:
+// импорт нужен один раз, при переезде из приложения Microsoft
+func showMicrosoftToDoImport(w fyne.Window, tm *task.TaskManager, prefs fyne.Preferences) {
+	clientIDEntry := widget.NewEntry()
+	clientIDEntry.SetText(prefs.String(prefMicrosoftClientID))
+	clientIDEntry.Validator = func(text string) error {
+		if strings.TrimSpace(text) == "" {
+			return errors.New("client id is required")
+		}
+		return nil
+	}
+	dialog.ShowForm("Импорт из Microsoft To Do", "Войти", "Отмена", []*widget.FormItem{
+		{Text: "ID приложения", Widget: clientIDEntry,
+			HintText: "Приложение Azure с адресом возврата http://127.0.0.1 и разрешением Tasks.Read"},
+	}, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		clientID := strings.TrimSpace(clientIDEntry.Text)
+		prefs.SetString(prefMicrosoftClientID, clientID)
+		fetchMicrosoftToDo(w, tm, plugins.MicrosoftOAuth(clientID))
+	}, w)
+}
+
+// fetchMicrosoftToDo входит в учетную запись и загружает задачи, пока показывается индикатор
+func fetchMicrosoftToDo(w fyne.Window, tm *task.TaskManager, cfg storage.OAuthConfig) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	status := widget.NewLabel("Разрешите доступ в открывшемся окне браузера")
+	progress := dialog.NewCustomWithoutButtons("Microsoft To Do", container.NewVBox(
+		status,
+		widget.NewProgressBarInfinite(),
+	), w)
+	progress.SetButtons([]fyne.CanvasObject{widget.NewButton("Отмена", cancel)})
+	progress.Show()
+	today := tm.DueZone().Now()
+
+	go func() {
+		var tasks []*task.Task
+		token, err := storage.Authorize(ctx, cfg, func(u *url.URL) error {
+			var err error
+			fyne.DoAndWait(func() { err = fyne.CurrentApp().OpenURL(u) })
+			return err
+		})
+		if err == nil {
+			fyne.Do(func() { status.SetText("Загрузка задач…") })
+			tasks, err = plugins.NewMicrosoftToDo(storage.NewOAuthClient(cfg, token, nil)).Fetch(ctx, today)
+		}
+		fyne.Do(func() {
+			cancel()
+			progress.Hide()
+			switch {
+			case errors.Is(err, context.Canceled):
+			case err != nil:
+				slog.Error("microsoft to do import failed", "err", err)
+				dialog.ShowError(err, w)
+			default:
+				confirmImport(w, tm, "Импорт из Microsoft To Do", fmt.Sprintf("Задач в To Do: %d", len(tasks)), tasks)
+			}
+		})
+	}()
+}
@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/task"
+)
+
+// weekdayFullNames - полные названия дней недели с понедельника
+var weekdayFullNames = []string{"понедельник", "вторник", "среда", "четверг", "пятница", "суббота", "воскресенье"}
+
+// monthGenitive - названия месяцев для дат: «16 октября»
+var monthGenitive = []string{"января", "февраля", "марта", "апреля", "мая", "июня",
+	"июля", "августа", "сентября", "октября", "ноября", "декабря"}
+
+// myDayTitle возвращает заголовок «Моего дня»: «Мой день, пятница, 16 октября»
+func myDayTitle(now time.Time) string {
+	weekday := weekdayFullNames[(int(now.Weekday())+6)%7]
+	return fmt.Sprintf("Мой день, %s, %d %s", weekday, now.Day(), monthGenitive[now.Month()-1])
+}
+
+// newMyDayView создает вкладку «Мой день»: задачи, которые пользователь сам выбрал на сегодня,
+// и рядом подсказки - просроченные задачи и задачи со сроком сегодня. Каждое утро список пуст
+func newMyDayView(tm *task.TaskManager, showError func(error), openTask func(id int)) (view fyne.CanvasObject, refresh func()) {
+	var planned, suggested []*task.Task
+	now := func() time.Time { return tm.DueZone().Now() }
+
+	plannedList := widget.NewList(
+		func() int { return len(planned) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, widget.NewCheck("", nil),
+				widget.NewButtonWithIcon("Убрать", theme.ContentRemoveIcon(), nil), widget.NewLabel(""))
+		},
+		func(i widget.ListItemID, item fyne.CanvasObject) {
+			t := planned[i]
+			row := item.(*fyne.Container)
+			label := row.Objects[0].(*widget.Label)
+			label.Importance = widget.MediumImportance
+			if t.Completed {
+				label.Importance = widget.LowImportance
+			}
+			label.SetText(t.Title)
+			check := row.Objects[1].(*widget.Check)
+			check.OnChanged = nil
+			check.SetChecked(t.Completed)
+			check.OnChanged = func(bool) { showError(tm.ToggleTaskCompletion(t.ID)) }
+			row.Objects[2].(*widget.Button).OnTapped = func() { showError(tm.SetMyDay(t.ID, false, now())) }
+		},
+	)
+	plannedList.OnSelected = func(i widget.ListItemID) {
+		plannedList.UnselectAll()
+		openTask(planned[i].ID)
+	}
+
+	suggestedList := widget.NewList(
+		func() int { return len(suggested) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil,
+				widget.NewButtonWithIcon("В мой день", theme.ContentAddIcon(), nil), widget.NewLabel(""))
+		},
+		func(i widget.ListItemID, item fyne.CanvasObject) {
+			t := suggested[i]
+			row := item.(*fyne.Container)
+			row.Objects[0].(*widget.Label).SetText(t.Title + " · " + formatRelativeDue(t, now()))
+			row.Objects[1].(*widget.Button).OnTapped = func() { showError(tm.SetMyDay(t.ID, true, now())) }
+		},
+	)
+	suggestedList.OnSelected = func(i widget.ListItemID) {
+		suggestedList.UnselectAll()
+		openTask(suggested[i].ID)
+	}
+
+	title := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	summary := widget.NewLabel("")
+	empty := widget.NewLabel("Список пуст. Добавьте задачи из подсказок или из контекстного меню списка")
+	empty.Wrapping = fyne.TextWrapWord
+	suggestionsTitle := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+
+	refresh = func() {
+		day := now()
+		planned, suggested = tm.MyDay(day), tm.MyDaySuggestions(day)
+		done := 0
+		for _, t := range planned {
+			if t.Completed {
+				done++
+			}
+		}
+		title.SetText(myDayTitle(day))
+		summary.SetText(fmt.Sprintf("Выполнено %d из %d", done, len(planned)))
+		empty.Hidden = len(planned) > 0
+		suggestionsTitle.SetText(fmt.Sprintf("Подсказки (%d)", len(suggested)))
+		plannedList.Refresh()
+		suggestedList.Refresh()
+		empty.Refresh()
+	}
+	refresh()
+
+	split := container.NewHSplit(
+		container.NewBorder(container.NewVBox(title, summary), nil, nil, nil, container.NewStack(plannedList, empty)),
+		container.NewBorder(suggestionsTitle, nil, nil, nil, suggestedList),
+	)
+	split.Offset = 0.6
+	return split, refresh
+}
@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMyDayTitle(t *testing.T) {
+	assert.Equal(t, "Мой день, пятница, 16 октября", myDayTitle(time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "Мой день, воскресенье, 1 марта", myDayTitle(time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)))
+}
+
+func TestMyDayView(t *testing.T) {
+	test.NewTempApp(t)
+	tm := newTestManager(t)
+	today := tm.DueZone().Now()
+	call := mustAddTask(t, tm, "Позвонить", "", 3, today)
+	mustAddTask(t, tm, "Отпуск", "", 2, today.AddDate(0, 0, 10))
+
+	view, refresh := newMyDayView(tm, func(err error) { require.NoError(t, err) }, func(int) {})
+	w := test.NewWindow(view)
+	defer w.Close()
+
+	assert.NotNil(t, findText(view, "Подсказки (1)"))
+	assert.NotNil(t, findText(view, "Выполнено 0 из 0"))
+	test.Tap(findText(view, "В мой день").(*widget.Button))
+	assert.True(t, tm.GetTask(call.ID).InMyDay(today))
+
+	refresh()
+	assert.NotNil(t, findText(view, "Подсказки (0)"))
+	assert.NotNil(t, findText(view, "Выполнено 0 из 1"))
+	test.Tap(findText(view, "Убрать").(*widget.Button))
+	assert.False(t, tm.GetTask(call.ID).InMyDay(today))
+}
@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/sampledata"
+	"taskmanager/task"
+)
+
+// tourWidth - ширина подсказки в туре по интерфейсу
+const tourWidth = 340
+
+// tourStep - подсказка к одному элементу окна
+type tourStep struct {
+	target fyne.CanvasObject
+	text   string
+}
+
+// showWelcomeDialog встречает нового пользователя: предлагает добавить задачи-примеры,
+// а затем показать, где находятся основные кнопки
+func showWelcomeDialog(w fyne.Window, prefs fyne.Preferences, tm *task.TaskManager, steps []tourStep) {
+	message := widget.NewLabel("Добро пожаловать в менеджер задач!\n" +
+		"Добавить несколько задач-примеров, чтобы посмотреть, как все работает?\n" +
+		"Их можно удалить в любой момент.")
+
+	dialog.ShowCustomConfirm("Добро пожаловать", "Добавить примеры", "Начать с пустого списка", message, func(addExamples bool) {
+		prefs.SetBool(prefOnboardingDone, true)
+		if addExamples {
+			if err := sampledata.AddExamples(tm, time.Now()); err != nil {
+				dialog.ShowError(err, w)
+			}
+		}
+
+		dialog.ShowConfirm("Знакомство", "Показать, где находятся основные кнопки?", func(confirmed bool) {
+			if confirmed {
+				showTour(w, steps)
+			}
+		}, w)
+	}, w)
+}
+
+// showTour по очереди показывает подсказки к элементам окна, выделяя их
+func showTour(w fyne.Window, steps []tourStep) {
+	var show func(i int)
+	show = func(i int) {
+		if i >= len(steps) {
+			return
+		}
+		step := steps[i]
+		restore := highlight(step.target)
+
+		var popup *widget.PopUp
+		nextText := "Далее"
+		if i == len(steps)-1 {
+			nextText = "Готово"
+		}
+		nextButton := widget.NewButton(nextText, func() {
+			popup.Hide()
+			restore()
+			show(i + 1)
+		})
+		nextButton.Importance = widget.HighImportance
+		skipButton := widget.NewButton("Пропустить", func() {
+			popup.Hide()
+			restore()
+		})
+
+		text := widget.NewLabel(step.text)
+		text.Wrapping = fyne.TextWrapWord
+		text.Resize(fyne.NewSize(tourWidth, text.MinSize().Height))
+		buttons := container.NewHBox(widget.NewLabel(fmt.Sprintf("%d из %d", i+1, len(steps))), layout.NewSpacer(), skipButton, nextButton)
+		content := container.NewVBox(text, buttons)
+
+		popup = widget.NewModalPopUp(content, w.Canvas())
+		popup.Resize(fyne.NewSize(tourWidth, content.MinSize().Height))
+		popup.ShowAtPosition(tourPosition(w, step.target, popup.Size()))
+	}
+	show(0)
+}
+
+// tourPosition размещает подсказку под элементом так, чтобы она не выходила за окно
+func tourPosition(w fyne.Window, target fyne.CanvasObject, size fyne.Size) fyne.Position {
+	pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(target)
+	pos.Y += target.Size().Height + theme.Padding()
+
+	canvasSize := w.Canvas().Size()
+	if pos.X+size.Width > canvasSize.Width {
+		pos.X = max(canvasSize.Width-size.Width, 0)
+	}
+	if pos.Y+size.Height > canvasSize.Height {
+		pos.Y = max(canvasSize.Height-size.Height, 0)
+	}
+	return pos
+}
+
+// highlight выделяет кнопку на время подсказки и возвращает функцию, снимающую выделение
+func highlight(target fyne.CanvasObject) (restore func()) {
+	button, ok := target.(*widget.Button)
+	if !ok {
+		return func() {}
+	}
+	importance := button.Importance
+	button.Importance = widget.HighImportance
+	button.Refresh()
+	return func() {
+		button.Importance = importance
+		button.Refresh()
+	}
+}
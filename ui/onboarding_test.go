@@ -0,0 +1,22 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHighlight(t *testing.T) {
+	test.NewTempApp(t)
+	button := widget.NewButton("Добавить", nil)
+
+	restore := highlight(button)
+	assert.Equal(t, widget.HighImportance, button.Importance)
+	restore()
+	assert.Equal(t, widget.MediumImportance, button.Importance)
+
+	// Не кнопки не выделяются, но и не ломают тур
+	highlight(widget.NewEntry())()
+}
@@ -0,0 +1,87 @@
+package ui
+
+import "taskmanager/task"
+
+// defaultPageSize - размер страницы списка по умолчанию
+const defaultPageSize = 100
+
+// taskPager хранит текущую выборку задач и отдает интерфейсу только одну страницу,
+// чтобы при десятках тысяч задач не пересобирать весь список строк
+type taskPager struct {
+	tasks    []*task.Task
+	page     int
+	pageSize int // 0 - без разбиения на страницы
+}
+
+// newTaskPager создает пейджер с заданным размером страницы
+func newTaskPager(pageSize int) *taskPager {
+	return &taskPager{pageSize: pageSize}
+}
+
+// SetTasks задает новую выборку, сохраняя текущую страницу, если она еще существует
+func (p *taskPager) SetTasks(tasks []*task.Task) {
+	p.tasks = tasks
+	p.clampPage()
+}
+
+// SetPageSize меняет размер страницы и возвращает пейджер на первую страницу
+func (p *taskPager) SetPageSize(size int) {
+	if size < 0 {
+		size = 0
+	}
+	p.pageSize = size
+	p.page = 0
+}
+
+// PageCount возвращает количество страниц (минимум одна)
+func (p *taskPager) PageCount() int {
+	if p.pageSize == 0 || len(p.tasks) == 0 {
+		return 1
+	}
+	return (len(p.tasks) + p.pageSize - 1) / p.pageSize
+}
+
+// Page возвращает номер текущей страницы, начиная с нуля
+func (p *taskPager) Page() int {
+	return p.page
+}
+
+// PageTasks возвращает задачи текущей страницы без копирования всей выборки
+func (p *taskPager) PageTasks() []*task.Task {
+	if p.pageSize == 0 {
+		return p.tasks
+	}
+	start := p.page * p.pageSize
+	if start >= len(p.tasks) {
+		return nil
+	}
+	end := start + p.pageSize
+	if end > len(p.tasks) {
+		end = len(p.tasks)
+	}
+	return p.tasks[start:end]
+}
+
+// NextPage переходит на следующую страницу
+func (p *taskPager) NextPage() bool {
+	if p.page+1 >= p.PageCount() {
+		return false
+	}
+	p.page++
+	return true
+}
+
+// PrevPage переходит на предыдущую страницу
+func (p *taskPager) PrevPage() bool {
+	if p.page == 0 {
+		return false
+	}
+	p.page--
+	return true
+}
+
+func (p *taskPager) clampPage() {
+	if last := p.PageCount() - 1; p.page > last {
+		p.page = last
+	}
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskPager(t *testing.T) {
+	tm := newTestManager(t)
+
+	for i := 0; i < 25; i++ {
+		tm.AddTask("Task", "Description", 1, time.Now())
+	}
+
+	pager := newTaskPager(10)
+	pager.SetTasks(tm.Tasks())
+
+	// Проверяем разбиение на страницы
+	assert.Equal(t, 3, pager.PageCount())
+	assert.Equal(t, 10, len(pager.PageTasks()))
+	assert.Equal(t, 1, pager.PageTasks()[0].ID)
+
+	// Переходим на последнюю страницу
+	assert.True(t, pager.NextPage())
+	assert.True(t, pager.NextPage())
+	assert.False(t, pager.NextPage())
+	assert.Equal(t, 5, len(pager.PageTasks()))
+	assert.Equal(t, 21, pager.PageTasks()[0].ID)
+
+	// При уменьшении выборки страница не выходит за пределы
+	pager.SetTasks(tm.Tasks()[:12])
+	assert.Equal(t, 1, pager.Page())
+	assert.Equal(t, 2, len(pager.PageTasks()))
+
+	// Возвращаемся назад
+	assert.True(t, pager.PrevPage())
+	assert.False(t, pager.PrevPage())
+}
+
+func TestTaskPagerWithoutPaging(t *testing.T) {
+	tm := newTestManager(t)
+
+	tm.AddTask("Task 1", "Description", 1, time.Now())
+	tm.AddTask("Task 2", "Description", 2, time.Now())
+
+	pager := newTaskPager(10)
+	pager.SetPageSize(0)
+	pager.SetTasks(tm.Tasks())
+
+	// Без разбиения показываются все задачи на одной странице
+	assert.Equal(t, 1, pager.PageCount())
+	assert.Equal(t, 2, len(pager.PageTasks()))
+
+	// Пустая выборка тоже дает одну страницу
+	pager.SetTasks(nil)
+	assert.Equal(t, 1, pager.PageCount())
+	assert.Equal(t, 0, len(pager.PageTasks()))
+}
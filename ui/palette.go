@@ -0,0 +1,208 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/task"
+)
+
+// uiAction - действие интерфейса. Кнопки и пункты меню создаются из реестра действий,
+// поэтому палитра команд находит все, что можно сделать в окне
+type uiAction struct {
+	Title string
+	Run   func()
+}
+
+// actionRegistry - действия окна в порядке добавления
+type actionRegistry struct {
+	actions []*uiAction
+	onRun   func() // вызывается перед каждым действием: кнопкой, пунктом меню или из палитры
+}
+
+// Add регистрирует действие, доступное только из палитры команд
+func (r *actionRegistry) Add(title string, run func()) *uiAction {
+	action := &uiAction{Title: title, Run: func() {
+		if r.onRun != nil {
+			r.onRun()
+		}
+		run()
+	}}
+	r.actions = append(r.actions, action)
+	return action
+}
+
+// Button регистрирует действие и возвращает его кнопку
+func (r *actionRegistry) Button(title string, run func()) *widget.Button {
+	return widget.NewButton(title, r.Add(title, run).Run)
+}
+
+// MenuItem регистрирует действие и возвращает его пункт меню
+func (r *actionRegistry) MenuItem(title string, run func()) *fyne.MenuItem {
+	return fyne.NewMenuItem(title, r.Add(title, run).Run)
+}
+
+// paletteItem - строка палитры команд: действие или задача
+type paletteItem struct {
+	Title  string
+	Action *uiAction
+	TaskID int
+}
+
+// paletteMatches возвращает действия и задачи, подходящие под запрос, лучшие совпадения первыми.
+// Пустой запрос показывает все действия, задачи - только по запросу
+func paletteMatches(query string, actions []*uiAction, tasks []*task.Task) []paletteItem {
+	type scored struct {
+		item  paletteItem
+		score int
+	}
+	var matches []scored
+	for _, action := range actions {
+		if score, ok := fuzzyScore(query, action.Title); ok {
+			matches = append(matches, scored{paletteItem{Title: action.Title, Action: action}, score})
+		}
+	}
+	if strings.TrimSpace(query) != "" {
+		for _, t := range tasks {
+			if score, ok := fuzzyScore(query, t.Title); ok {
+				matches = append(matches, scored{paletteItem{Title: "Задача: " + t.Title, TaskID: t.ID}, score})
+			}
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	items := make([]paletteItem, len(matches))
+	for i, m := range matches {
+		items[i] = m.item
+	}
+	return items
+}
+
+// fuzzyScore проверяет, что буквы запроса идут в тексте по порядку, и оценивает совпадение:
+// выше за буквы подряд и за совпадение с началом слова. Регистр не учитывается
+func fuzzyScore(query, text string) (int, bool) {
+	q := []rune(strings.ToLower(strings.ReplaceAll(query, " ", "")))
+	t := []rune(strings.ToLower(text))
+	score, qi, prev := 0, 0, -2
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			continue
+		}
+		score++
+		if ti == prev+1 {
+			score += 3
+		}
+		if ti == 0 || !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]) {
+			score += 5
+		}
+		prev = ti
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	return score, true
+}
+
+// paletteEntry - поле запроса палитры: стрелки выбирают строку, Escape закрывает палитру
+type paletteEntry struct {
+	widget.Entry
+	onKey func(key fyne.KeyName) bool
+}
+
+func newPaletteEntry() *paletteEntry {
+	e := &paletteEntry{}
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+func (e *paletteEntry) TypedKey(event *fyne.KeyEvent) {
+	if e.onKey != nil && e.onKey(event.Name) {
+		return
+	}
+	e.Entry.TypedKey(event)
+}
+
+// showCommandPalette показывает палитру команд: действия окна и задачи по названию.
+// onTask вызывается для выбранной задачи
+func showCommandPalette(w fyne.Window, actions *actionRegistry, tm *task.TaskManager, onTask func(id int)) {
+	var items []paletteItem
+	selected := 0
+	moving := false // строку выбирают стрелками, а не щелчком
+
+	entry := newPaletteEntry()
+	entry.SetPlaceHolder("Команда или задача…")
+	list := widget.NewList(
+		func() int { return len(items) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			item.(*widget.Label).SetText(items[id].Title)
+		},
+	)
+
+	var popup *widget.PopUp
+	run := func(i int) {
+		if i < 0 || i >= len(items) {
+			return
+		}
+		item := items[i]
+		popup.Hide()
+		if item.Action != nil {
+			item.Action.Run()
+		} else {
+			onTask(item.TaskID)
+		}
+	}
+	move := func(i int) {
+		moving = true
+		list.Select(i)
+		moving = false
+	}
+	update := func(query string) {
+		items = paletteMatches(query, actions.actions, tm.Tasks())
+		selected = 0
+		list.Refresh()
+		if len(items) > 0 {
+			move(0)
+		}
+	}
+	entry.OnChanged = update
+	entry.OnSubmitted = func(string) { run(selected) }
+	entry.onKey = func(key fyne.KeyName) bool {
+		switch key {
+		case fyne.KeyEscape:
+			popup.Hide()
+		case fyne.KeyDown:
+			if selected+1 < len(items) {
+				move(selected + 1)
+			}
+		case fyne.KeyUp:
+			if selected > 0 {
+				move(selected - 1)
+			}
+		default:
+			return false
+		}
+		return true
+	}
+	list.OnSelected = func(id widget.ListItemID) {
+		if !moving {
+			run(id)
+			return
+		}
+		selected = id
+		list.ScrollTo(id)
+	}
+
+	content := container.NewBorder(entry, nil, nil, nil, list)
+	popup = widget.NewModalPopUp(content, w.Canvas())
+	popup.Resize(fyne.NewSize(500, 400))
+	update("")
+	popup.Show()
+	w.Canvas().Focus(entry)
+}
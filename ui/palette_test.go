@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	_, ok := fuzzyScore("нстр", "Настройки…")
+	assert.True(t, ok)
+	_, ok = fuzzyScore("стн", "Настройки…")
+	assert.False(t, ok)
+
+	// Пустой запрос подходит ко всему
+	_, ok = fuzzyScore("", "Удалить")
+	assert.True(t, ok)
+
+	// Начало слова и буквы подряд важнее разрозненных совпадений
+	prefix, _ := fuzzyScore("экс", "Экспорт…")
+	scattered, _ := fuzzyScore("экс", "Сквозное шифрование…")
+	assert.Greater(t, prefix, scattered)
+	word, _ := fuzzyScore("таб", "Вид: таблица")
+	inner, _ := fuzzyScore("таб", "Восстановить из резервной копии…")
+	assert.Greater(t, word, inner)
+}
+
+func TestPaletteMatches(t *testing.T) {
+	actions := &actionRegistry{}
+	ran := ""
+	actions.Button("Добавить задачу", func() { ran = "add" })
+	actions.MenuItem("Настройки…", func() { ran = "settings" })
+	actions.Add("Вид: таблица", func() { ran = "table" })
+	tasks := []*task.Task{{ID: 7, Title: "Настроить роутер"}, {ID: 8, Title: "Купить молоко"}}
+
+	// Без запроса - все действия по порядку, без задач
+	items := paletteMatches("", actions.actions, tasks)
+	var titles []string
+	for _, item := range items {
+		titles = append(titles, item.Title)
+	}
+	assert.Equal(t, []string{"Добавить задачу", "Настройки…", "Вид: таблица"}, titles)
+
+	items = paletteMatches("настр", actions.actions, tasks)
+	assert.Len(t, items, 2)
+	assert.Equal(t, "Настройки…", items[0].Title)
+	assert.Equal(t, "Задача: Настроить роутер", items[1].Title)
+	assert.Equal(t, 7, items[1].TaskID)
+
+	items[0].Action.Run()
+	assert.Equal(t, "settings", ran)
+
+	assert.Empty(t, paletteMatches("xyz", actions.actions, tasks))
+}
@@ -0,0 +1,143 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/task"
+)
+
+// formatQuickAdd показывает разобранную задачу в окне предпросмотра
+func formatQuickAdd(q task.QuickAdd) string {
+	status := " "
+	if q.Completed {
+		status = "✓"
+	}
+	row := fmt.Sprintf("[%s] %s (приоритет: %s, до: %s", status, q.Title, task.PriorityText(q.Priority), q.DueDate.Format("2006-01-02"))
+	if q.Assignee != "" {
+		row += ", исполнитель: " + q.Assignee
+	}
+	for _, tag := range q.Tags {
+		row += ", #" + tag
+	}
+	return row + ")"
+}
+
+// createQuickTasks добавляет разобранные задачи и возвращает, сколько добавлено.
+// Задачу, которую не удалось добавить, пропускает и продолжает со следующей
+func createQuickTasks(tm *task.TaskManager, items []task.QuickAdd) (int, error) {
+	var errs []error
+	added := 0
+	for _, q := range items {
+		t, err := tm.AddTask(q.Title, q.Description, q.Priority, q.DueDate)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%q: %w", q.Title, err))
+			continue
+		}
+		if len(q.Tags) > 0 {
+			errs = append(errs, tm.SetTaskTags(t.ID, q.Tags))
+		}
+		if q.Assignee != "" {
+			errs = append(errs, tm.SetTaskAssignee(t.ID, q.Assignee))
+		}
+		if q.Completed {
+			errs = append(errs, tm.ToggleTaskCompletion(t.ID))
+		}
+		added++
+	}
+	return added, errors.Join(errs...)
+}
+
+// showQuickAddPreview показывает задачи, разобранные из вставленного текста или файла,
+// и добавляет их после подтверждения
+func showQuickAddPreview(w fyne.Window, tm *task.TaskManager, text string) {
+	items := task.ParseTaskLines(text, tm.DueZone().Now())
+	if len(items) == 0 {
+		dialog.ShowInformation("Вставка задач", "В тексте нет строк, из которых можно создать задачи", w)
+		return
+	}
+
+	// Строки, похожие на открытые задачи, скорее всего, уже добавлены
+	similar := similarQuickTasks(tm, items)
+	duplicates := 0
+	for _, t := range similar {
+		if t != nil {
+			duplicates++
+		}
+	}
+	list := widget.NewList(
+		func() int { return len(items) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(i widget.ListItemID, item fyne.CanvasObject) {
+			text := formatQuickAdd(items[i])
+			if similar[i] != nil {
+				text = fmt.Sprintf("⚠ %s — похожа на #%d «%s»", text, similar[i].ID, similar[i].Title)
+			}
+			item.(*widget.Label).SetText(text)
+		},
+	)
+	skipCheck := widget.NewCheck(fmt.Sprintf("Не создавать похожие на открытые задачи (%d)", duplicates), nil)
+	skipCheck.SetChecked(true)
+	skipCheck.Hidden = duplicates == 0
+	content := container.NewBorder(nil, skipCheck, nil, nil, list)
+	preview := dialog.NewCustomConfirm(fmt.Sprintf("Создать задач: %d", len(items)), "Создать", "Отмена", content, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		create := items
+		if skipCheck.Checked && duplicates > 0 {
+			create = nil
+			for i, q := range items {
+				if similar[i] == nil {
+					create = append(create, q)
+				}
+			}
+			slog.Info("similar tasks skipped", "count", duplicates)
+		}
+		added, err := createQuickTasks(tm, create)
+		slog.Info("tasks created from text", "count", added)
+		if err != nil {
+			slog.Error("failed to create some tasks from text", "err", err)
+			dialog.ShowError(err, w)
+		}
+	}, w)
+	preview.Resize(fyne.NewSize(600, 400))
+	preview.Show()
+}
+
+// quickAddFile - можно ли создать задачи из перетащенного файла
+func quickAddFile(uri fyne.URI) bool {
+	ext := strings.ToLower(uri.Extension())
+	return uri.Scheme() == "file" && (ext == ".txt" || ext == ".md" || ext == ".markdown")
+}
+
+// dropTaskFiles создает задачи из перетащенных в окно файлов .txt и .md
+func dropTaskFiles(w fyne.Window, tm *task.TaskManager, uris []fyne.URI) {
+	var texts []string
+	for _, uri := range uris {
+		if !quickAddFile(uri) {
+			slog.Debug("dropped file ignored", "uri", uri.String())
+			continue
+		}
+		data, err := os.ReadFile(uri.Path())
+		if err != nil {
+			slog.Error("failed to read dropped file", "file", uri.Path(), "err", err)
+			dialog.ShowError(err, w)
+			return
+		}
+		texts = append(texts, string(data))
+	}
+	if len(texts) == 0 {
+		dialog.ShowInformation("Вставка задач", "Перетащите файл .txt или .md: каждая строка станет задачей", w)
+		return
+	}
+	showQuickAddPreview(w, tm, strings.Join(texts, "\n"))
+}
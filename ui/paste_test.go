@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/storage"
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+func TestCreateQuickTasks(t *testing.T) {
+	tm := newTestManager(t)
+	tm.SetRequireDueAfterCreated(true)
+	items := task.ParseTaskLines("- [x] Сдать отчет !3 #работа @Маша\n- [ ] Старое дело 2020-01-01\nКупить хлеб", time.Now())
+
+	// Задача с прошедшим сроком не проходит проверку, остальные добавляются
+	added, err := createQuickTasks(tm, items)
+	assert.Equal(t, 2, added)
+	assert.ErrorContains(t, err, "Старое дело")
+
+	tasks := tm.Tasks()
+	assert.Len(t, tasks, 2)
+	assert.Equal(t, "Сдать отчет", tasks[0].Title)
+	assert.True(t, tasks[0].Completed)
+	assert.Equal(t, []string{"работа"}, tasks[0].Tags)
+	assert.Equal(t, "Маша", tasks[0].Assignee)
+	assert.Equal(t, "Купить хлеб", tasks[1].Title)
+}
+
+func TestQuickAddFile(t *testing.T) {
+	assert.True(t, quickAddFile(storage.NewFileURI("/tmp/tasks.md")))
+	assert.True(t, quickAddFile(storage.NewFileURI("/tmp/TODO.TXT")))
+	assert.False(t, quickAddFile(storage.NewFileURI("/tmp/photo.png")))
+}
+
+func TestFormatQuickAdd(t *testing.T) {
+	q := task.QuickAdd{Title: "Отчет", Priority: 3, DueDate: time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC), Tags: []string{"работа"}, Assignee: "Маша", Completed: true}
+	assert.Equal(t, "[✓] Отчет (приоритет: высокий, до: 2026-10-20, исполнитель: Маша, #работа)", formatQuickAdd(q))
+}
@@ -0,0 +1,21 @@
+package ui
+
+import "slices"
+
+// Варианты фильтра по исполнителю, кроме имен людей
+const (
+	assigneeAll        = "Все исполнители"
+	assigneeUnassigned = "Без исполнителя"
+)
+
+// assigneeChoices возвращает исполнителей для выбора: сначала людей из настроек,
+// затем тех, кто уже назначен задачам, но в список не входит
+func assigneeChoices(people, assigned []string) []string {
+	choices := slices.Clone(people)
+	for _, name := range assigned {
+		if !slices.Contains(choices, name) {
+			choices = append(choices, name)
+		}
+	}
+	return choices
+}
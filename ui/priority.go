@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/task"
+)
+
+// priorityFlag - флажок приоритета; цвет флажку задает тема
+var priorityFlag = fyne.NewStaticResource("priority-flag.svg", []byte(`<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">`+
+	`<path fill="#000000" d="M5 3h2v18H5zM8 4h11l-3 4.5 3 4.5H8z"/></svg>`))
+
+// priorityColors - цвета приоритетов: высокий - красный, средний - оранжевый, низкий - серый.
+// Одни и те же цвета используются в списке, календаре и на доске
+var priorityColors = map[int]fyne.ThemeColorName{
+	3: theme.ColorNameError,
+	2: theme.ColorNameWarning,
+	1: theme.ColorNameDisabled,
+}
+
+// priorityColor возвращает цвет приоритета; неизвестный приоритет - как средний
+func priorityColor(priority int) fyne.ThemeColorName {
+	if name, ok := priorityColors[priority]; ok {
+		return name
+	}
+	return priorityColors[2]
+}
+
+// priorityIcon возвращает флажок приоритета цвета priorityColor
+func priorityIcon(priority int) fyne.Resource {
+	return theme.NewColoredResource(priorityFlag, priorityColor(priority))
+}
+
+// priorityLegend объясняет цвета флажков: для настроек
+func priorityLegend() fyne.CanvasObject {
+	legend := container.NewHBox()
+	for _, priority := range []int{3, 2, 1} {
+		legend.Add(widget.NewIcon(priorityIcon(priority)))
+		legend.Add(widget.NewLabel(task.PriorityText(priority)))
+	}
+	return legend
+}
@@ -0,0 +1,280 @@
+package ui
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Ключи списка профилей - общие для всех профилей
+const (
+	prefProfiles    = "profiles.names"
+	prefLastProfile = "profiles.last"
+)
+
+// defaultProfileTitle - название основного профиля, который был до появления профилей
+const defaultProfileTitle = "Основной"
+
+// profileStore хранит список профилей. У каждого профиля свой файл задач и свои настройки;
+// основной профиль (пустое имя) использует исходный файл задач и настройки без префикса
+type profileStore struct {
+	prefs       fyne.Preferences
+	defaultFile string
+}
+
+func newProfileStore(prefs fyne.Preferences, defaultFile string) *profileStore {
+	return &profileStore{prefs: prefs, defaultFile: defaultFile}
+}
+
+// Names возвращает имена профилей, начиная с основного
+func (s *profileStore) Names() []string {
+	return append([]string{""}, s.prefs.StringList(prefProfiles)...)
+}
+
+// Has сообщает, есть ли профиль с таким именем
+func (s *profileStore) Has(name string) bool {
+	return slices.Contains(s.Names(), name)
+}
+
+// Add добавляет профиль
+func (s *profileStore) Add(name string) error {
+	if err := validateProfileName(name); err != nil {
+		return err
+	}
+	if s.Has(name) {
+		return errors.New("profile already exists")
+	}
+	s.prefs.SetStringList(prefProfiles, append(s.prefs.StringList(prefProfiles), name))
+	return nil
+}
+
+// Last возвращает профиль, открытый в прошлый раз
+func (s *profileStore) Last() string {
+	if name := s.prefs.String(prefLastProfile); s.Has(name) {
+		return name
+	}
+	return ""
+}
+
+// SetLast запоминает открытый профиль
+func (s *profileStore) SetLast(name string) {
+	s.prefs.SetString(prefLastProfile, name)
+}
+
+// TasksFile возвращает файл задач профиля: рядом с основным, tasks-<имя>.json
+func (s *profileStore) TasksFile(name string) string {
+	if name == "" {
+		return s.defaultFile
+	}
+	return filepath.Join(filepath.Dir(s.defaultFile), "tasks-"+name+".json")
+}
+
+// Preferences возвращает настройки профиля
+func (s *profileStore) Preferences(name string) fyne.Preferences {
+	if name == "" {
+		return s.prefs
+	}
+	return &profilePreferences{Preferences: s.prefs, prefix: "profile." + name + "."}
+}
+
+// profileTitle возвращает название профиля для интерфейса
+func profileTitle(name string) string {
+	if name == "" {
+		return defaultProfileTitle
+	}
+	return name
+}
+
+// validateProfileName проверяет, что имя профиля годится для имени файла
+func validateProfileName(name string) error {
+	if strings.TrimSpace(name) != name || name == "" {
+		return errors.New("profile name must not be empty or start or end with spaces")
+	}
+	if len([]rune(name)) > 40 {
+		return errors.New("profile name is too long")
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != ' ' && r != '-' && r != '_' {
+			return errors.New("profile name may contain only letters, digits, spaces, '-' and '_'")
+		}
+	}
+	return nil
+}
+
+// newProfileChooser создает список профилей с кнопкой открытия и полем для нового профиля
+func newProfileChooser(profiles *profileStore, current string, onChoose func(name string), onError func(error)) fyne.CanvasObject {
+	names := profiles.Names()
+	titles := make([]string, len(names))
+	for i, name := range names {
+		titles[i] = profileTitle(name)
+	}
+
+	profileRadio := widget.NewRadioGroup(titles, nil)
+	profileRadio.Required = true
+	profileRadio.SetSelected(profileTitle(current))
+
+	openButton := widget.NewButton("Открыть", func() {
+		onChoose(names[slices.Index(titles, profileRadio.Selected)])
+	})
+	openButton.Importance = widget.HighImportance
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Новый профиль")
+	createButton := widget.NewButton("Создать и открыть", func() {
+		if err := profiles.Add(nameEntry.Text); err != nil {
+			onError(err)
+			return
+		}
+		onChoose(nameEntry.Text)
+	})
+
+	return container.NewVBox(
+		widget.NewLabel("Профиль"),
+		profileRadio,
+		openButton,
+		widget.NewSeparator(),
+		container.NewBorder(nil, nil, nil, createButton, nameEntry),
+	)
+}
+
+// showProfilePicker показывает окно выбора профиля при запуске. open открывает выбранный профиль
+func showProfilePicker(a fyne.App, profiles *profileStore, open func(profile string) bool) {
+	w := a.NewWindow("Task Manager - выбор профиля")
+	w.SetContent(container.NewPadded(newProfileChooser(profiles, profiles.Last(), func(profile string) {
+		// Окно профиля открывается раньше, чем закрывается окно выбора, чтобы приложение не завершилось
+		open(profile)
+		w.Close()
+	}, func(err error) {
+		dialog.ShowError(err, w)
+	})))
+	w.CenterOnScreen()
+	w.Show()
+}
+
+// showSwitchProfileDialog предлагает выбрать другой профиль вместо текущего current
+func showSwitchProfileDialog(w fyne.Window, profiles *profileStore, current string, onChoose func(profile string)) {
+	var d dialog.Dialog
+	chooser := newProfileChooser(profiles, current, func(profile string) {
+		d.Hide()
+		if profile != current {
+			onChoose(profile)
+		}
+	}, func(err error) {
+		dialog.ShowError(err, w)
+	})
+	d = dialog.NewCustom("Сменить профиль", "Отмена", chooser, w)
+	d.Show()
+}
+
+// profilePreferences - настройки профиля: те же настройки приложения, но с префиксом у ключей
+type profilePreferences struct {
+	fyne.Preferences
+	prefix string
+}
+
+func (p *profilePreferences) Bool(key string) bool {
+	return p.Preferences.Bool(p.prefix + key)
+}
+
+func (p *profilePreferences) BoolWithFallback(key string, fallback bool) bool {
+	return p.Preferences.BoolWithFallback(p.prefix+key, fallback)
+}
+
+func (p *profilePreferences) SetBool(key string, value bool) {
+	p.Preferences.SetBool(p.prefix+key, value)
+}
+
+func (p *profilePreferences) BoolList(key string) []bool {
+	return p.Preferences.BoolList(p.prefix + key)
+}
+
+func (p *profilePreferences) BoolListWithFallback(key string, fallback []bool) []bool {
+	return p.Preferences.BoolListWithFallback(p.prefix+key, fallback)
+}
+
+func (p *profilePreferences) SetBoolList(key string, value []bool) {
+	p.Preferences.SetBoolList(p.prefix+key, value)
+}
+
+func (p *profilePreferences) Float(key string) float64 {
+	return p.Preferences.Float(p.prefix + key)
+}
+
+func (p *profilePreferences) FloatWithFallback(key string, fallback float64) float64 {
+	return p.Preferences.FloatWithFallback(p.prefix+key, fallback)
+}
+
+func (p *profilePreferences) SetFloat(key string, value float64) {
+	p.Preferences.SetFloat(p.prefix+key, value)
+}
+
+func (p *profilePreferences) FloatList(key string) []float64 {
+	return p.Preferences.FloatList(p.prefix + key)
+}
+
+func (p *profilePreferences) FloatListWithFallback(key string, fallback []float64) []float64 {
+	return p.Preferences.FloatListWithFallback(p.prefix+key, fallback)
+}
+
+func (p *profilePreferences) SetFloatList(key string, value []float64) {
+	p.Preferences.SetFloatList(p.prefix+key, value)
+}
+
+func (p *profilePreferences) Int(key string) int {
+	return p.Preferences.Int(p.prefix + key)
+}
+
+func (p *profilePreferences) IntWithFallback(key string, fallback int) int {
+	return p.Preferences.IntWithFallback(p.prefix+key, fallback)
+}
+
+func (p *profilePreferences) SetInt(key string, value int) {
+	p.Preferences.SetInt(p.prefix+key, value)
+}
+
+func (p *profilePreferences) IntList(key string) []int {
+	return p.Preferences.IntList(p.prefix + key)
+}
+
+func (p *profilePreferences) IntListWithFallback(key string, fallback []int) []int {
+	return p.Preferences.IntListWithFallback(p.prefix+key, fallback)
+}
+
+func (p *profilePreferences) SetIntList(key string, value []int) {
+	p.Preferences.SetIntList(p.prefix+key, value)
+}
+
+func (p *profilePreferences) String(key string) string {
+	return p.Preferences.String(p.prefix + key)
+}
+
+func (p *profilePreferences) StringWithFallback(key, fallback string) string {
+	return p.Preferences.StringWithFallback(p.prefix+key, fallback)
+}
+
+func (p *profilePreferences) SetString(key string, value string) {
+	p.Preferences.SetString(p.prefix+key, value)
+}
+
+func (p *profilePreferences) StringList(key string) []string {
+	return p.Preferences.StringList(p.prefix + key)
+}
+
+func (p *profilePreferences) StringListWithFallback(key string, fallback []string) []string {
+	return p.Preferences.StringListWithFallback(p.prefix+key, fallback)
+}
+
+func (p *profilePreferences) SetStringList(key string, value []string) {
+	p.Preferences.SetStringList(p.prefix+key, value)
+}
+
+func (p *profilePreferences) RemoveValue(key string) {
+	p.Preferences.RemoveValue(p.prefix + key)
+}
@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"path/filepath"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfileStore(t *testing.T) {
+	a := test.NewTempApp(t)
+	profiles := newProfileStore(a.Preferences(), filepath.Join("data", "tasks.json"))
+
+	assert.Equal(t, []string{""}, profiles.Names())
+	assert.NoError(t, profiles.Add("Работа"))
+	assert.Error(t, profiles.Add("Работа"))
+	assert.Equal(t, []string{"", "Работа"}, profiles.Names())
+
+	assert.Equal(t, filepath.Join("data", "tasks.json"), profiles.TasksFile(""))
+	assert.Equal(t, filepath.Join("data", "tasks-Работа.json"), profiles.TasksFile("Работа"))
+
+	assert.Equal(t, "", profiles.Last())
+	profiles.SetLast("Работа")
+	assert.Equal(t, "Работа", profiles.Last())
+	profiles.SetLast("удаленный")
+	assert.Equal(t, "", profiles.Last())
+}
+
+func TestProfilePreferencesAreSeparate(t *testing.T) {
+	a := test.NewTempApp(t)
+	profiles := newProfileStore(a.Preferences(), "tasks.json")
+	assert.NoError(t, profiles.Add("home"))
+
+	home := profiles.Preferences("home")
+	home.SetInt(prefBackupKeep, 3)
+	home.SetStringList(prefHiddenColumns, []string{"tags"})
+
+	assert.Equal(t, 3, home.Int(prefBackupKeep))
+	assert.Equal(t, []string{"tags"}, home.StringList(prefHiddenColumns))
+	assert.Equal(t, 0, profiles.Preferences("").Int(prefBackupKeep))
+	assert.Empty(t, a.Preferences().StringList(prefHiddenColumns))
+
+	home.RemoveValue(prefBackupKeep)
+	assert.Equal(t, 7, home.IntWithFallback(prefBackupKeep, 7))
+}
+
+func TestValidateProfileName(t *testing.T) {
+	for _, name := range []string{"work", "Дом", "team-2", "my_tasks", "Личные дела"} {
+		assert.NoError(t, validateProfileName(name), name)
+	}
+	for _, name := range []string{"", " work", "work ", "../work", "a/b", `a\b`, "a:b", "tasks.json"} {
+		assert.Error(t, validateProfileName(name), name)
+	}
+}
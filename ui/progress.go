@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/task"
+)
+
+// hasProgress сообщает, есть ли у задачи готовность для показа: чек-лист или заданный процент
+func hasProgress(t *task.Task) bool {
+	return len(t.Checklist) > 0 || t.Progress > 0
+}
+
+// newProgressCell создает полосу готовности для строки списка задач
+func newProgressCell() *fyne.Container {
+	bar := widget.NewProgressBar()
+	bar.Max = 100
+	return container.NewGridWrap(fyne.NewSize(110, bar.MinSize().Height), bar)
+}
+
+// updateProgressCell показывает готовность задачи в строке списка; без готовности полоса скрыта
+func updateProgressCell(cell *fyne.Container, t *task.Task) {
+	bar := cell.Objects[0].(*widget.ProgressBar)
+	if !hasProgress(t) {
+		bar.Hide()
+		return
+	}
+	bar.SetValue(float64(t.ProgressPercent()))
+	bar.Show()
+}
+
+// newProgressSlider создает ползунок готовности для диалога задачи. Для задачи с чек-листом
+// готовность считается по пунктам, поэтому ползунок только показывает ее
+func newProgressSlider(t *task.Task) (*widget.Slider, fyne.CanvasObject) {
+	label := widget.NewLabel("")
+	slider := widget.NewSlider(0, 100)
+	slider.Step = 5
+	slider.OnChanged = func(value float64) {
+		label.SetText(fmt.Sprintf("%.0f%%", value))
+	}
+	slider.SetValue(float64(t.ProgressPercent()))
+	if len(t.Checklist) > 0 {
+		slider.Disable()
+		label.SetText(fmt.Sprintf("%d%% (checklist)", t.ProgressPercent()))
+	}
+	return slider, container.NewBorder(nil, nil, nil, label, slider)
+}
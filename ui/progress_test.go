@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+func TestProgressCell(t *testing.T) {
+	test.NewTempApp(t)
+	cell := newProgressCell()
+	bar := cell.Objects[0].(*widget.ProgressBar)
+
+	updateProgressCell(cell, &task.Task{Progress: 60})
+	assert.True(t, bar.Visible())
+	assert.Equal(t, 60.0, bar.Value)
+
+	updateProgressCell(cell, &task.Task{Checklist: []task.ChecklistItem{{Done: true}, {}}})
+	assert.Equal(t, 50.0, bar.Value)
+
+	// Без чек-листа и готовности полоса не показывается
+	updateProgressCell(cell, &task.Task{})
+	assert.False(t, bar.Visible())
+}
+
+func TestProgressSlider(t *testing.T) {
+	test.NewTempApp(t)
+	slider, _ := newProgressSlider(&task.Task{Progress: 25})
+	assert.Equal(t, 25.0, slider.Value)
+	assert.False(t, slider.Disabled())
+
+	slider, _ = newProgressSlider(&task.Task{Progress: 25, Checklist: []task.ChecklistItem{{Done: true}}})
+	assert.Equal(t, 100.0, slider.Value)
+	assert.True(t, slider.Disabled())
+}
@@ -0,0 +1,318 @@
+package ui
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/reminders"
+	"taskmanager/task"
+)
+
+// reminderInterval - как часто проверять, не пора ли напомнить о задачах
+const reminderInterval = 30 * time.Second
+
+// prefRemindersFired - напоминания, о которых уже сообщили, чтобы не повторять их после перезапуска
+const prefRemindersFired = "reminders.fired"
+
+// Тихое время: начало и конец «22:00», пусто - без тихих часов, и тишина по выходным
+const (
+	prefQuietFrom     = "notifications.quiet_from"
+	prefQuietTo       = "notifications.quiet_to"
+	prefQuietWeekends = "notifications.quiet_weekends"
+)
+
+// Как напоминать о задачах с высоким приоритетом и об остальных, и файл звука
+const (
+	prefAlertHigh  = "notifications.alert_high"
+	prefAlertOther = "notifications.alert_other"
+	prefAlertSound = "notifications.sound"
+)
+
+// loadAlerts читает настройки напоминаний; без них действуют reminders.DefaultAlerts
+func loadAlerts(prefs fyne.Preferences) reminders.Alerts {
+	return reminders.Alerts{
+		High:  task.Urgency(prefs.StringWithFallback(prefAlertHigh, string(reminders.DefaultAlerts.High))),
+		Other: task.Urgency(prefs.StringWithFallback(prefAlertOther, string(reminders.DefaultAlerts.Other))),
+		Sound: prefs.String(prefAlertSound),
+	}
+}
+
+// newUrgencySelect создает выбор вида напоминания. Вариант «по приоритету» есть только
+// у задачи: в настройках он не нужен
+func newUrgencySelect(selected task.Urgency, withDefault bool) *widget.Select {
+	var options []string
+	for _, u := range task.Urgencies {
+		if u != task.UrgencyDefault || withDefault {
+			options = append(options, u.Text())
+		}
+	}
+	s := widget.NewSelect(options, nil)
+	s.SetSelected(selected.Text())
+	return s
+}
+
+// selectedUrgency возвращает вид напоминания, выбранный в newUrgencySelect
+func selectedUrgency(s *widget.Select) task.Urgency {
+	for _, u := range task.Urgencies {
+		if u.Text() == s.Selected {
+			return u
+		}
+	}
+	return task.UrgencyDefault
+}
+
+// quietHours читает расписание «не беспокоить» из настроек
+func quietHours(prefs fyne.Preferences) reminders.QuietHours {
+	q := reminders.QuietHours{Weekends: prefs.Bool(prefQuietWeekends)}
+	from, errFrom := reminders.ParseClock(prefs.String(prefQuietFrom))
+	to, errTo := reminders.ParseClock(prefs.String(prefQuietTo))
+	if errFrom == nil && errTo == nil {
+		q.From, q.To = from, to
+	}
+	return q
+}
+
+// validateClock проверяет время суток в настройках; пустое поле допустимо
+func validateClock(text string) error {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	_, err := reminders.ParseClock(text)
+	return err
+}
+
+// reminderLayout - формат времени напоминания в диалогах задачи
+const reminderLayout = "2006-01-02 15:04"
+
+// parseReminder разбирает время напоминания; пустое поле - без напоминания
+func parseReminder(text string) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return time.Time{}, nil
+	}
+	at, err := time.ParseInLocation(reminderLayout, text, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid reminder time, use YYYY-MM-DD HH:MM")
+	}
+	return at, nil
+}
+
+// formatReminderTime показывает время напоминания для поля ввода
+func formatReminderTime(at time.Time) string {
+	if at.IsZero() {
+		return ""
+	}
+	return at.In(time.Local).Format(reminderLayout)
+}
+
+// reminderOffsetOption - вариант напоминания относительно срока
+type reminderOffsetOption struct {
+	Offset time.Duration
+	Text   string
+}
+
+// reminderOffsetOptions - напоминания относительно срока, которые можно выбрать в диалогах задачи
+var reminderOffsetOptions = []reminderOffsetOption{
+	{7 * 24 * time.Hour, "1 week before"},
+	{24 * time.Hour, "1 day before"},
+	{time.Hour, "1 hour before"},
+	{0, "At due time"},
+}
+
+// newReminderOffsetsCheck создает выбор напоминаний относительно срока
+func newReminderOffsetsCheck(offsets []time.Duration) *widget.CheckGroup {
+	options := make([]string, len(reminderOffsetOptions))
+	var selected []string
+	for i, option := range reminderOffsetOptions {
+		options[i] = option.Text
+		if slices.Contains(offsets, option.Offset) {
+			selected = append(selected, option.Text)
+		}
+	}
+	check := widget.NewCheckGroup(options, nil)
+	check.Horizontal = true
+	check.SetSelected(selected)
+	return check
+}
+
+// selectedReminderOffsets возвращает напоминания, выбранные в newReminderOffsetsCheck.
+// Напоминания из current, которых нет среди вариантов, сохраняются
+func selectedReminderOffsets(check *widget.CheckGroup, current []time.Duration) []time.Duration {
+	var offsets []time.Duration
+	for _, offset := range current {
+		if !slices.ContainsFunc(reminderOffsetOptions, func(option reminderOffsetOption) bool { return option.Offset == offset }) {
+			offsets = append(offsets, offset)
+		}
+	}
+	for _, option := range reminderOffsetOptions {
+		if slices.Contains(check.Selected, option.Text) {
+			offsets = append(offsets, option.Offset)
+		}
+	}
+	// Как в задаче: от самого раннего напоминания
+	slices.SortFunc(offsets, func(a, b time.Duration) int { return cmp.Compare(b, a) })
+	return offsets
+}
+
+// reminderHost показывает напоминания о задачах: системным уведомлением и в полосе
+// уведомлений главного окна, где напоминание можно отложить
+type reminderHost struct {
+	a         fyne.App
+	w         fyne.Window
+	prefs     fyne.Preferences
+	tm        *task.TaskManager
+	notices   *fyne.Container
+	scheduler *reminders.Scheduler
+}
+
+func newReminderHost(a fyne.App, w fyne.Window, prefs fyne.Preferences, tm *task.TaskManager) *reminderHost {
+	h := &reminderHost{a: a, w: w, prefs: prefs, tm: tm, notices: container.NewVBox()}
+	h.scheduler = reminders.NewScheduler(h.notify)
+	if data := prefs.String(prefRemindersFired); data != "" {
+		if err := json.Unmarshal([]byte(data), &h.scheduler.Fired); err != nil {
+			slog.Warn("failed to read fired reminders", "err", err)
+		}
+	}
+	return h
+}
+
+// Check напоминает о задачах, время которых подошло, если сейчас не тихое время
+func (h *reminderHost) Check() {
+	h.scheduler.Quiet = quietHours(h.prefs)
+	if len(h.scheduler.Check(h.tm.Tasks(), time.Now())) == 0 {
+		return
+	}
+	data, _ := json.Marshal(h.scheduler.Fired)
+	h.prefs.SetString(prefRemindersFired, string(data))
+}
+
+// notify напоминает о задаче так, как задано для нее или ее приоритета: тихое напоминание
+// видно только в полосе уведомлений окна, остальные приходят и системным уведомлением
+func (h *reminderHost) notify(r reminders.Reminder) {
+	urgency, sound := loadAlerts(h.prefs).For(r.Task)
+	slog.Info("task reminder", "key", r.Key, "urgency", urgency)
+	title := r.Task.Title + " (" + r.Describe() + ")"
+	if urgency != task.UrgencySilent {
+		h.a.SendNotification(fyne.NewNotification("Напоминание", title))
+	}
+	if urgency == task.UrgencyLoud {
+		reminders.PlaySound(runtime.GOOS, sound)
+	}
+
+	uuid := r.Task.UUID
+	var notice *fyne.Container
+	dismiss := func() { h.notices.Remove(notice) }
+	buttons := container.NewHBox()
+	for _, snooze := range reminders.Snoozes {
+		buttons.Add(widget.NewButton(snooze.Title(), func() {
+			dismiss()
+			if current := h.tm.GetTaskByUUID(uuid); current != nil {
+				h.showError(h.tm.SnoozeTask(current.ID, snooze.Until(time.Now())))
+			}
+		}))
+	}
+	buttons.Add(widget.NewButton("Выполнено", func() {
+		dismiss()
+		if current := h.tm.GetTaskByUUID(uuid); current != nil && !current.Completed {
+			h.showError(h.tm.ToggleTaskCompletion(current.ID))
+		}
+	}))
+	buttons.Add(newCloseButton(dismiss))
+
+	notice = container.NewBorder(nil, nil, nil, buttons, widget.NewLabel("⏰ "+title))
+	h.notices.Add(notice)
+}
+
+func (h *reminderHost) showError(err error) {
+	if err != nil {
+		slog.Error("failed to update reminder", "err", err)
+		dialog.ShowError(err, h.w)
+	}
+}
+
+// formatUpcomingReminder описывает напоминание для списка предстоящих
+func formatUpcomingReminder(r reminders.Reminder) string {
+	return fmt.Sprintf("%s — %s (%s)", r.At.In(time.Local).Format("02.01 15:04"), r.Task.Title, r.Describe())
+}
+
+// removeReminder убирает одно напоминание задачи, остальные остаются
+func removeReminder(tm *task.TaskManager, r reminders.Reminder) error {
+	switch {
+	case r.Snoozed:
+		return tm.SnoozeTask(r.Task.ID, time.Time{})
+	case r.Relative:
+		offsets := slices.DeleteFunc(slices.Clone(r.Task.ReminderOffsets), func(offset time.Duration) bool { return offset == r.Offset })
+		return tm.SetTaskReminderOffsets(r.Task.ID, offsets)
+	}
+	return tm.SetTaskReminder(r.Task.ID, time.Time{})
+}
+
+// showRemindersDialog показывает предстоящие напоминания по времени; их можно отложить или убрать
+func showRemindersDialog(w fyne.Window, tm *task.TaskManager) {
+	var upcoming []reminders.Reminder
+	var list *widget.List
+	reload := func() {
+		upcoming = reminders.Upcoming(tm.Tasks(), time.Now())
+		list.Refresh()
+	}
+	showError := func(err error) {
+		if err != nil {
+			dialog.ShowError(err, w)
+		}
+	}
+	snoozeTitles := make([]string, len(reminders.Snoozes))
+	for i, snooze := range reminders.Snoozes {
+		snoozeTitles[i] = snooze.Title()
+	}
+
+	list = widget.NewList(
+		func() int { return len(upcoming) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil,
+				container.NewHBox(widget.NewSelect(snoozeTitles, nil), widget.NewButton("Убрать", nil)),
+				widget.NewLabel(""))
+		},
+		func(i widget.ListItemID, item fyne.CanvasObject) {
+			r := upcoming[i]
+			row := item.(*fyne.Container)
+			row.Objects[0].(*widget.Label).SetText(formatUpcomingReminder(r))
+			controls := row.Objects[1].(*fyne.Container)
+			snoozeSelect := controls.Objects[0].(*widget.Select)
+			snoozeSelect.OnChanged = nil
+			snoozeSelect.ClearSelected()
+			snoozeSelect.PlaceHolder = "Отложить"
+			snoozeSelect.OnChanged = func(title string) {
+				for _, snooze := range reminders.Snoozes {
+					if snooze.Title() == title {
+						showError(tm.SnoozeTask(r.Task.ID, snooze.Until(time.Now())))
+					}
+				}
+				reload()
+			}
+			controls.Objects[1].(*widget.Button).OnTapped = func() {
+				showError(removeReminder(tm, r))
+				reload()
+			}
+		},
+	)
+	reload()
+
+	var content fyne.CanvasObject = list
+	if len(upcoming) == 0 {
+		content = widget.NewLabel("Напоминаний нет. Напоминания задаются при редактировании задачи")
+	}
+	d := dialog.NewCustom("Напоминания", "Закрыть", content, w)
+	d.Resize(dialogSize(w, fyne.NewSize(600, 420)))
+	d.Show()
+}
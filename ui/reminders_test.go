@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/reminders"
+	"taskmanager/task"
+)
+
+func TestParseReminder(t *testing.T) {
+	at, err := parseReminder("2026-10-16 09:30")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 16, 9, 30, 0, 0, time.Local), at)
+	assert.Equal(t, "2026-10-16 09:30", formatReminderTime(at))
+
+	at, err = parseReminder(" ")
+	assert.NoError(t, err)
+	assert.True(t, at.IsZero())
+
+	_, err = parseReminder("завтра")
+	assert.Error(t, err)
+}
+
+func TestReminderHost(t *testing.T) {
+	a := test.NewTempApp(t)
+	w := a.NewWindow("")
+	tm := newTestManager(t)
+	call := mustAddTask(t, tm, "Позвонить врачу", "", 2, time.Now())
+	tm.SetTaskReminder(call.ID, time.Now().Add(-time.Minute))
+
+	host := newReminderHost(a, w, a.Preferences(), tm)
+	host.Check()
+	assert.Len(t, host.notices.Objects, 1)
+	assert.Contains(t, a.Preferences().String(prefRemindersFired), call.UUID)
+
+	// Повторная проверка не показывает напоминание снова
+	host.Check()
+	assert.Len(t, host.notices.Objects, 1)
+
+	// «На час» откладывает напоминание и убирает его из полосы
+	buttons := host.notices.Objects[0].(*fyne.Container).Objects[1].(*fyne.Container)
+	test.Tap(buttons.Objects[1].(*widget.Button))
+	assert.Empty(t, host.notices.Objects)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), tm.GetTask(call.ID).SnoozedUntil, time.Minute)
+	upcoming := reminders.Upcoming(tm.Tasks(), time.Now())
+	if assert.Len(t, upcoming, 1) {
+		assert.Contains(t, formatUpcomingReminder(upcoming[0]), "Позвонить врачу (отложено)")
+	}
+}
+
+func TestReminderAlerts(t *testing.T) {
+	a := test.NewTempApp(t)
+	w := a.NewWindow("")
+	tm := newTestManager(t)
+	host := newReminderHost(a, w, a.Preferences(), tm)
+	assert.Equal(t, reminders.DefaultAlerts, loadAlerts(a.Preferences()))
+
+	// Обычные задачи по умолчанию напоминают только в окне
+	call := mustAddTask(t, tm, "Позвонить", "", 2, time.Now())
+	tm.SetTaskReminder(call.ID, time.Now().Add(-time.Minute))
+	test.AssertNotificationSent(t, nil, host.Check)
+	assert.Len(t, host.notices.Objects, 1)
+
+	// Задача может напоминать настойчивее, чем задано для ее приоритета
+	report := mustAddTask(t, tm, "Отчет", "", 1, time.Now())
+	tm.SetTaskAlert(report.ID, task.UrgencyNormal, "")
+	tm.SetTaskReminder(report.ID, time.Now().Add(-time.Minute))
+	test.AssertNotificationSent(t, fyne.NewNotification("Напоминание", "Отчет (в назначенное время)"), host.Check)
+	assert.Len(t, host.notices.Objects, 2)
+
+	selectTask := newUrgencySelect(task.UrgencyDefault, true)
+	assert.Len(t, selectTask.Options, 4)
+	selectTask.SetSelected(task.UrgencyLoud.Text())
+	assert.Equal(t, task.UrgencyLoud, selectedUrgency(selectTask))
+	assert.Len(t, newUrgencySelect(task.UrgencySilent, false).Options, 3)
+}
+
+func TestReminderOffsetsCheck(t *testing.T) {
+	day, week := 24*time.Hour, 7*24*time.Hour
+	check := newReminderOffsetsCheck([]time.Duration{week, 0})
+	assert.Equal(t, []string{"1 week before", "At due time"}, check.Selected)
+
+	check.SetSelected([]string{"At due time", "1 day before"})
+	assert.Equal(t, []time.Duration{day, 0}, selectedReminderOffsets(check, nil))
+	// Напоминания, которых нет среди вариантов, не теряются
+	assert.Equal(t, []time.Duration{3 * day, day, 0}, selectedReminderOffsets(check, []time.Duration{3 * day, week}))
+}
+
+func TestRemoveReminder(t *testing.T) {
+	tm := newTestManager(t)
+	report := mustAddTask(t, tm, "Отчет", "", 2, time.Now().AddDate(0, 0, 10))
+	tm.SetTaskReminderOffsets(report.ID, []time.Duration{7 * 24 * time.Hour, 24 * time.Hour})
+
+	upcoming := reminders.Upcoming(tm.Tasks(), time.Now())
+	if assert.Len(t, upcoming, 2) {
+		assert.NoError(t, removeReminder(tm, upcoming[0]))
+	}
+	assert.Equal(t, []time.Duration{24 * time.Hour}, tm.GetTask(report.ID).ReminderOffsets)
+}
+
+func TestQuietHoursPrefs(t *testing.T) {
+	a := test.NewTempApp(t)
+	prefs := a.Preferences()
+	assert.Equal(t, reminders.QuietHours{}, quietHours(prefs))
+
+	prefs.SetString(prefQuietFrom, "22:00")
+	prefs.SetString(prefQuietTo, "08:00")
+	prefs.SetBool(prefQuietWeekends, true)
+	assert.Equal(t, reminders.QuietHours{From: 22 * 60, To: 8 * 60, Weekends: true}, quietHours(prefs))
+
+	assert.NoError(t, validateClock(""))
+	assert.Error(t, validateClock("вечером"))
+}
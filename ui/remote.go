@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/url"
+	"path/filepath"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/storage"
+)
+
+// remotePollInterval - как часто проверять, не изменился ли файл задач в удаленном хранилище
+const remotePollInterval = time.Minute
+
+// Облачные хранилища файла задач
+const (
+	cloudDropbox     = "dropbox"
+	cloudGoogleDrive = "gdrive"
+)
+
+// newRemoteStorage возвращает удаленное хранилище из настроек профиля или nil, если задачи
+// хранятся локально. Облачное хранилище выбирается, только если вход в него уже выполнен
+func newRemoteStorage(prefs fyne.Preferences, tasksFile string) storage.Remote {
+	if provider := prefs.String(prefCloud); provider != "" {
+		var token storage.Token
+		if err := json.Unmarshal([]byte(prefs.String(prefCloudToken)), &token); err != nil {
+			slog.Warn("cloud storage is not signed in, using local tasks file", "provider", provider)
+		} else {
+			client := storage.NewOAuthClient(cloudOAuth(provider, prefs.String(prefCloudClientID), prefs.String(prefCloudClientSecret)), token, func(token storage.Token) {
+				fyne.Do(func() { saveCloudToken(prefs, token) })
+			})
+			name := filepath.Base(tasksFile)
+			if provider == cloudGoogleDrive {
+				return storage.NewGoogleDrive(client, name)
+			}
+			return storage.NewDropbox(client, name)
+		}
+	}
+
+	if url := prefs.String(prefWebDAVURL); url != "" {
+		return storage.NewWebDAV(url, prefs.String(prefWebDAVUser), prefs.String(prefWebDAVPassword))
+	}
+	return nil
+}
+
+// cloudOAuth возвращает настройки входа в облачное хранилище provider
+func cloudOAuth(provider, clientID, clientSecret string) storage.OAuthConfig {
+	if provider == cloudGoogleDrive {
+		return storage.GoogleDriveOAuth(clientID, clientSecret)
+	}
+	return storage.DropboxOAuth(clientID)
+}
+
+// saveCloudToken запоминает токены облачного хранилища в настройках профиля
+func saveCloudToken(prefs fyne.Preferences, token storage.Token) {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return
+	}
+	prefs.SetString(prefCloudToken, string(data))
+}
+
+// cloudLogin выполняет вход в облачное хранилище через браузер и сохраняет токены
+func cloudLogin(ctx context.Context, prefs fyne.Preferences, cfg storage.OAuthConfig) error {
+	token, err := storage.Authorize(ctx, cfg, func(u *url.URL) error {
+		var err error
+		fyne.DoAndWait(func() { err = fyne.CurrentApp().OpenURL(u) })
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	fyne.Do(func() { saveCloudToken(prefs, token) })
+	return nil
+}
+
+// pollRemoteStorage периодически проверяет версию файла задач в удаленном хранилище
+// и вызывает onChange, если файл изменился. Возвращает функцию остановки проверки
+func pollRemoteStorage(remote storage.Remote, onChange func()) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		ticker := time.NewTicker(remotePollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			modified, err := remote.Modified(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					slog.Warn("failed to check tasks file in remote storage", "url", remote.URL(), "err", err)
+				}
+				continue
+			}
+			if modified {
+				onChange()
+			}
+		}
+	}()
+	return cancel
+}
+
+// showCloudLogin открывает вход в облачное хранилище в браузере и ждет его завершения.
+// onDone вызывается после успешного входа
+func showCloudLogin(w fyne.Window, prefs fyne.Preferences, cfg storage.OAuthConfig, onDone func()) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	progress := dialog.NewCustomWithoutButtons("Облачное хранилище", container.NewVBox(
+		widget.NewLabel("Разрешите доступ в открывшемся окне браузера"),
+		widget.NewProgressBarInfinite(),
+	), w)
+	progress.SetButtons([]fyne.CanvasObject{widget.NewButton("Отмена", cancel)})
+	progress.Show()
+
+	go func() {
+		err := cloudLogin(ctx, prefs, cfg)
+		fyne.Do(func() {
+			cancel()
+			progress.Hide()
+			switch {
+			case errors.Is(err, context.Canceled):
+			case err != nil:
+				slog.Error("cloud storage sign in failed", "err", err)
+				dialog.ShowError(err, w)
+			default:
+				onDone()
+			}
+		})
+	}()
+}
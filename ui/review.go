@@ -0,0 +1,246 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/task"
+)
+
+// Ключи настроек еженедельного обзора
+const (
+	prefReviewLast     = "review.last"     // время последнего завершенного обзора, RFC 3339
+	prefReviewReminder = "review.reminder" // напоминать об обзоре раз в неделю
+)
+
+// reviewInterval - как часто напоминать об обзоре задач
+const reviewInterval = 7 * 24 * time.Hour
+
+// lastReview возвращает время последнего обзора; нулевое - обзора еще не было
+func lastReview(prefs fyne.Preferences) time.Time {
+	last, _ := time.Parse(time.RFC3339, prefs.String(prefReviewLast))
+	return last
+}
+
+// reviewDue проверяет, пора ли напомнить об обзоре
+func reviewDue(prefs fyne.Preferences, now time.Time) bool {
+	return prefs.BoolWithFallback(prefReviewReminder, true) && now.Sub(lastReview(prefs)) >= reviewInterval
+}
+
+// reviewSession - обзор невыполненных задач по одной: оставить, перенести, выполнить или удалить
+type reviewSession struct {
+	tm  *task.TaskManager
+	ids []int
+	pos int
+
+	Kept, Rescheduled, Completed, Deleted int
+}
+
+// newReviewSession начинает обзор невыполненных задач не из архива, от самого раннего срока
+func newReviewSession(tm *task.TaskManager) *reviewSession {
+	s := &reviewSession{tm: tm}
+	for _, t := range task.SortTasks(tm.Tasks(), task.SortByDueDate, false) {
+		if !t.Completed && !t.Archived {
+			s.ids = append(s.ids, t.ID)
+		}
+	}
+	return s
+}
+
+// Current возвращает задачу, которую нужно рассмотреть, или nil, если обзор закончен.
+// Задачи, которые тем временем выполнили или удалили в другом месте, пропускаются
+func (s *reviewSession) Current() *task.Task {
+	for s.pos < len(s.ids) {
+		if t := s.tm.GetTask(s.ids[s.pos]); t != nil && !t.Completed && !t.Archived {
+			return t
+		}
+		s.pos++
+	}
+	return nil
+}
+
+// Progress возвращает номер текущей задачи и число задач в обзоре
+func (s *reviewSession) Progress() (int, int) {
+	return s.pos + 1, len(s.ids)
+}
+
+// Keep оставляет задачу как есть
+func (s *reviewSession) Keep() {
+	s.Kept++
+	s.pos++
+}
+
+// Reschedule переносит срок задачи
+func (s *reviewSession) Reschedule(due time.Time) error {
+	t := s.Current()
+	if t == nil {
+		return nil
+	}
+	if err := s.tm.UpdateTask(t.ID, t.Title, t.Description, t.Priority, due, t.Completed); err != nil {
+		return err
+	}
+	s.Rescheduled++
+	s.pos++
+	return nil
+}
+
+// Complete отмечает задачу выполненной
+func (s *reviewSession) Complete() error {
+	t := s.Current()
+	if t == nil {
+		return nil
+	}
+	if err := s.tm.ToggleTaskCompletion(t.ID); err != nil {
+		return err
+	}
+	s.Completed++
+	s.pos++
+	return nil
+}
+
+// Delete удаляет задачу
+func (s *reviewSession) Delete() error {
+	t := s.Current()
+	if t == nil {
+		return nil
+	}
+	if err := s.tm.DeleteTask(t.ID); err != nil {
+		return err
+	}
+	s.Deleted++
+	s.pos++
+	return nil
+}
+
+// Summary описывает итог обзора
+func (s *reviewSession) Summary() string {
+	return fmt.Sprintf("Оставлено: %d\nПеренесено: %d\nВыполнено: %d\nУдалено: %d",
+		s.Kept, s.Rescheduled, s.Completed, s.Deleted)
+}
+
+// rescheduleOptions - варианты переноса срока в обзоре
+var rescheduleOptions = []struct {
+	Title string
+	Days  int
+}{{"На завтра", 1}, {"Через неделю", 7}, {"Через месяц", 30}}
+
+// showReviewDialog проводит еженедельный обзор задач. Когда все задачи рассмотрены,
+// время обзора запоминается, и напоминание откладывается на неделю
+func showReviewDialog(w fyne.Window, prefs fyne.Preferences, tm *task.TaskManager, onFinished func()) {
+	session := newReviewSession(tm)
+	if session.Current() == nil {
+		prefs.SetString(prefReviewLast, time.Now().Format(time.RFC3339))
+		onFinished()
+		dialog.ShowInformation("Обзор задач", "Невыполненных задач нет - обзор не нужен", w)
+		return
+	}
+
+	progress := widget.NewLabel("")
+	title := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	title.Wrapping = fyne.TextWrapWord
+	details := widget.NewLabel("")
+	details.Wrapping = fyne.TextWrapWord
+
+	var d *dialog.CustomDialog
+	var show func()
+	act := func(err error) {
+		if err != nil {
+			slog.Error("review action failed", "err", err)
+			dialog.ShowError(err, w)
+			return
+		}
+		show()
+	}
+	show = func() {
+		t := session.Current()
+		if t == nil {
+			d.Hide()
+			prefs.SetString(prefReviewLast, time.Now().Format(time.RFC3339))
+			slog.Info("weekly review finished", "kept", session.Kept, "rescheduled", session.Rescheduled,
+				"completed", session.Completed, "deleted", session.Deleted)
+			onFinished()
+			dialog.ShowInformation("Обзор завершен", session.Summary(), w)
+			return
+		}
+		current, total := session.Progress()
+		progress.SetText(fmt.Sprintf("Задача %d из %d", current, total))
+		title.SetText(t.Title)
+		text := fmt.Sprintf("Срок: %s, приоритет: %s", formatDueDate(t), task.PriorityText(t.Priority))
+		if t.Assignee != "" {
+			text += "\nИсполнитель: " + t.Assignee
+		}
+		if t.Description != "" {
+			text += "\n\n" + t.Description
+		}
+		details.SetText(text)
+	}
+
+	rescheduleSelect := widget.NewSelect(nil, nil)
+	rescheduleSelect.PlaceHolder = "Перенести…"
+	for _, option := range rescheduleOptions {
+		rescheduleSelect.Options = append(rescheduleSelect.Options, option.Title)
+	}
+	rescheduleSelect.OnChanged = func(value string) {
+		if value == "" {
+			return
+		}
+		rescheduleSelect.ClearSelected()
+		for _, option := range rescheduleOptions {
+			if option.Title == value {
+				zone := tm.DueZone()
+				due, _ := zone.ParseDueDate(zone.Now().AddDate(0, 0, option.Days).Format(task.DueDateLayout))
+				act(session.Reschedule(tm.NextWorkday(due)))
+			}
+		}
+	}
+
+	keepButton := widget.NewButtonWithIcon("Оставить", theme.NavigateNextIcon(), func() {
+		session.Keep()
+		show()
+	})
+	completeButton := widget.NewButtonWithIcon("Выполнить", theme.ConfirmIcon(), func() { act(session.Complete()) })
+	deleteButton := widget.NewButtonWithIcon("Удалить", theme.DeleteIcon(), func() {
+		dialog.ShowConfirm("Удаление", "Удалить задачу?", func(confirmed bool) {
+			if confirmed {
+				act(session.Delete())
+			}
+		}, w)
+	})
+	keepButton.Importance = widget.HighImportance
+
+	content := container.NewBorder(
+		container.NewVBox(progress, title),
+		container.NewGridWithColumns(4, keepButton, rescheduleSelect, completeButton, deleteButton),
+		nil, nil,
+		container.NewVScroll(details),
+	)
+	d = dialog.NewCustom("Еженедельный обзор", "Прервать", content, w)
+	d.Resize(dialogSize(w, fyne.NewSize(560, 360)))
+	show()
+	d.Show()
+}
+
+// showReviewReminder показывает в полосе уведомлений напоминание об обзоре
+func showReviewReminder(w fyne.Window, notices *fyne.Container, prefs fyne.Preferences, tm *task.TaskManager) {
+	notices.RemoveAll()
+	text := "Пора провести еженедельный обзор задач"
+	if last := lastReview(prefs); !last.IsZero() {
+		text += fmt.Sprintf(" (последний - %s)", last.Format("02.01.2006"))
+	}
+	var notice *fyne.Container
+	startButton := widget.NewButton("Начать обзор", func() {
+		showReviewDialog(w, prefs, tm, func() { notices.Remove(notice) })
+	})
+	closeButton := newCloseButton(func() {
+		notices.Remove(notice)
+	})
+	notice = container.NewBorder(nil, nil, nil, container.NewHBox(startButton, closeButton), widget.NewLabel(text))
+	notices.Add(notice)
+}
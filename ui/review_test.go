@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewDue(t *testing.T) {
+	a := test.NewTempApp(t)
+	prefs := a.Preferences()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	// Обзора еще не было
+	assert.True(t, reviewDue(prefs, now))
+
+	prefs.SetString(prefReviewLast, now.AddDate(0, 0, -3).Format(time.RFC3339))
+	assert.False(t, reviewDue(prefs, now))
+	assert.Equal(t, now.AddDate(0, 0, -3), lastReview(prefs).UTC())
+
+	prefs.SetString(prefReviewLast, now.AddDate(0, 0, -7).Format(time.RFC3339))
+	assert.True(t, reviewDue(prefs, now))
+
+	prefs.SetBool(prefReviewReminder, false)
+	assert.False(t, reviewDue(prefs, now))
+}
+
+func TestReviewSession(t *testing.T) {
+	tm := newTestManager(t)
+	late := mustAddTask(t, tm, "Поздняя", "", 2, time.Now().Add(72*time.Hour))
+	early := mustAddTask(t, tm, "Ранняя", "", 2, time.Now().Add(time.Hour))
+	middle := mustAddTask(t, tm, "Средняя", "", 2, time.Now().Add(24*time.Hour))
+	done := mustAddTask(t, tm, "Выполненная", "", 2, time.Now())
+	assert.NoError(t, tm.ToggleTaskCompletion(done.ID))
+	gone := mustAddTask(t, tm, "Удалится сама", "", 2, time.Now().Add(48*time.Hour))
+
+	session := newReviewSession(tm)
+	_, total := session.Progress()
+	assert.Equal(t, 4, total)
+
+	// Задачи идут от самого раннего срока
+	assert.Equal(t, early.ID, session.Current().ID)
+	session.Keep()
+
+	assert.Equal(t, middle.ID, session.Current().ID)
+	due := time.Now().AddDate(0, 0, 7).Truncate(24 * time.Hour)
+	assert.NoError(t, session.Reschedule(due))
+	assert.Equal(t, due, tm.GetTask(middle.ID).DueDate)
+
+	// Задача, удаленная вне обзора, пропускается
+	assert.NoError(t, tm.DeleteTask(gone.ID))
+	assert.Equal(t, late.ID, session.Current().ID)
+	current, _ := session.Progress()
+	assert.Equal(t, 4, current)
+	assert.NoError(t, session.Complete())
+	assert.True(t, tm.GetTask(late.ID).Completed)
+
+	assert.Nil(t, session.Current())
+	assert.Equal(t, "Оставлено: 1\nПеренесено: 1\nВыполнено: 1\nУдалено: 0", session.Summary())
+
+	session = newReviewSession(tm)
+	assert.NoError(t, session.Delete())
+	assert.Nil(t, tm.GetTask(early.ID))
+	assert.Equal(t, 1, session.Deleted)
+}
@@ -0,0 +1,176 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/task"
+)
+
+// Виды сравнения описаний
+const (
+	diffUnified    = "Построчно"
+	diffSideBySide = "Рядом"
+)
+
+// diffSegment - строка сравнения моноширинным шрифтом цветом color
+func diffSegment(text string, color fyne.ThemeColorName) *widget.TextSegment {
+	style := widget.RichTextStyleCodeBlock
+	style.ColorName = color
+	return &widget.TextSegment{Text: text, Style: style}
+}
+
+// unifiedDiffSegments показывает сравнение одной колонкой: удаленные строки с «-»,
+// добавленные с «+»
+func unifiedDiffSegments(diff []task.DiffLine) []widget.RichTextSegment {
+	segments := make([]widget.RichTextSegment, len(diff))
+	for i, line := range diff {
+		switch line.Op {
+		case task.DiffRemoved:
+			segments[i] = diffSegment("- "+line.Text, theme.ColorNameError)
+		case task.DiffAdded:
+			segments[i] = diffSegment("+ "+line.Text, theme.ColorNameSuccess)
+		default:
+			segments[i] = diffSegment("  "+line.Text, theme.ColorNameForeground)
+		}
+	}
+	return segments
+}
+
+// sideBySideDiffSegments показывает сравнение двумя колонками: слева прежний текст
+// с удаленными строками, справа новый с добавленными. Напротив измененной строки в другой
+// колонке пусто, поэтому общие строки стоят друг напротив друга
+func sideBySideDiffSegments(diff []task.DiffLine) (before, after []widget.RichTextSegment) {
+	blank := func() *widget.TextSegment { return diffSegment(" ", theme.ColorNameForeground) }
+	for _, line := range diff {
+		switch line.Op {
+		case task.DiffRemoved:
+			before = append(before, diffSegment(line.Text, theme.ColorNameError))
+			after = append(after, blank())
+		case task.DiffAdded:
+			before = append(before, blank())
+			after = append(after, diffSegment(line.Text, theme.ColorNameSuccess))
+		default:
+			before = append(before, diffSegment(line.Text, theme.ColorNameForeground))
+			after = append(after, diffSegment(line.Text, theme.ColorNameForeground))
+		}
+	}
+	return before, after
+}
+
+// revisionTitles подписывает прежние описания номером и временем замены, новые первыми.
+// Номер нужен, чтобы различить версии, замененные в одну минуту
+func revisionTitles(revisions []task.DescriptionRevision) []string {
+	titles := make([]string, len(revisions))
+	for i, r := range revisions {
+		titles[len(revisions)-1-i] = fmt.Sprintf("Версия %d, до %s", i+1, r.ReplacedAt.Local().Format("02.01.2006 15:04"))
+	}
+	return titles
+}
+
+// descriptionHistory - вкладка истории описания в окне задачи: выбранная прежняя версия
+// сравнивается с текущим описанием, и ее можно вернуть
+type descriptionHistory struct {
+	tm        *task.TaskManager
+	showErr   func(error)
+	task      *task.Task
+	versions  *widget.Select
+	mode      *widget.RadioGroup
+	unified   *widget.RichText
+	before    *widget.RichText
+	after     *widget.RichText
+	diff      *fyne.Container
+	restore   *widget.Button
+	empty     *widget.Label
+	box       *fyne.Container
+	revisions []task.DescriptionRevision
+}
+
+func newDescriptionHistory(tm *task.TaskManager, showErr func(error)) *descriptionHistory {
+	h := &descriptionHistory{
+		tm:      tm,
+		showErr: showErr,
+		unified: widget.NewRichText(),
+		before:  widget.NewRichText(),
+		after:   widget.NewRichText(),
+		diff:    container.NewStack(),
+		empty:   widget.NewLabel("Описание еще не менялось"),
+	}
+	h.versions = widget.NewSelect(nil, func(string) { h.showDiff() })
+	h.mode = widget.NewRadioGroup([]string{diffUnified, diffSideBySide}, func(string) { h.showDiff() })
+	h.mode.Horizontal = true
+	h.mode.Required = true
+	h.mode.SetSelected(diffUnified)
+	h.restore = widget.NewButtonWithIcon("Вернуть эту версию", theme.HistoryIcon(), h.restoreSelected)
+	h.box = container.NewBorder(
+		container.NewVBox(h.empty, h.versions, h.mode),
+		h.restore, nil, nil,
+		h.diff,
+	)
+	return h
+}
+
+// Update показывает историю описания задачи t; выбор версии сохраняется, пока она есть
+func (h *descriptionHistory) Update(t *task.Task) {
+	h.task = t
+	h.revisions = t.DescriptionRevisions
+	selected := h.versions.SelectedIndex()
+	h.versions.SetOptions(revisionTitles(h.revisions))
+	if len(h.revisions) == 0 {
+		h.empty.Show()
+		h.versions.Hide()
+		h.mode.Hide()
+		h.restore.Hide()
+		h.diff.Objects = nil
+		h.diff.Refresh()
+		return
+	}
+	h.empty.Hide()
+	h.versions.Show()
+	h.mode.Show()
+	h.restore.Show()
+	if selected < 0 || selected >= len(h.revisions) {
+		selected = 0
+	}
+	h.versions.SetSelectedIndex(selected)
+	h.showDiff()
+}
+
+// selectedRevision возвращает номер выбранной версии в истории задачи или -1
+func (h *descriptionHistory) selectedRevision() int {
+	i := h.versions.SelectedIndex()
+	if i < 0 || i >= len(h.revisions) {
+		return -1
+	}
+	return len(h.revisions) - 1 - i
+}
+
+// showDiff сравнивает выбранную версию с текущим описанием
+func (h *descriptionHistory) showDiff() {
+	revision := h.selectedRevision()
+	if h.task == nil || revision < 0 {
+		return
+	}
+	diff := task.DiffLines(h.revisions[revision].Text, h.task.Description)
+	if h.mode.Selected == diffSideBySide {
+		h.before.Segments, h.after.Segments = sideBySideDiffSegments(diff)
+		h.before.Refresh()
+		h.after.Refresh()
+		h.diff.Objects = []fyne.CanvasObject{container.NewScroll(container.NewGridWithColumns(2, h.before, h.after))}
+	} else {
+		h.unified.Segments = unifiedDiffSegments(diff)
+		h.unified.Refresh()
+		h.diff.Objects = []fyne.CanvasObject{container.NewScroll(h.unified)}
+	}
+	h.diff.Refresh()
+}
+
+func (h *descriptionHistory) restoreSelected() {
+	if revision := h.selectedRevision(); h.task != nil && revision >= 0 {
+		h.showErr(h.tm.RestoreDescription(h.task.ID, revision))
+	}
+}
@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"taskmanager/task"
+)
+
+func TestDiffSegments(t *testing.T) {
+	diff := task.DiffLines("хлеб\nмолоко", "хлеб\nкефир")
+	unified := unifiedDiffSegments(diff)
+	require.Len(t, unified, 3)
+	assert.Equal(t, "  хлеб", unified[0].(*widget.TextSegment).Text)
+	assert.Equal(t, "- молоко", unified[1].(*widget.TextSegment).Text)
+	assert.Equal(t, theme.ColorNameError, unified[1].(*widget.TextSegment).Style.ColorName)
+	assert.Equal(t, "+ кефир", unified[2].(*widget.TextSegment).Text)
+
+	before, after := sideBySideDiffSegments(diff)
+	require.Len(t, before, 3)
+	require.Len(t, after, 3)
+	assert.Equal(t, "молоко", before[1].(*widget.TextSegment).Text)
+	assert.Equal(t, " ", after[1].(*widget.TextSegment).Text)
+	assert.Equal(t, "кефир", after[2].(*widget.TextSegment).Text)
+}
+
+func TestDescriptionHistory(t *testing.T) {
+	test.NewTempApp(t)
+	tm := newTestManager(t)
+	notes, err := tm.AddTask("Заметки", "первая", 2, time.Now().AddDate(0, 0, 1))
+	require.NoError(t, err)
+
+	h := newDescriptionHistory(tm, func(err error) { require.NoError(t, err) })
+	h.Update(notes)
+	assert.True(t, h.empty.Visible())
+
+	require.NoError(t, tm.UpdateTask(notes.ID, notes.Title, "вторая", notes.Priority, notes.DueDate, false))
+	require.NoError(t, tm.UpdateTask(notes.ID, notes.Title, "третья", notes.Priority, notes.DueDate, false))
+	h.Update(notes)
+	assert.False(t, h.empty.Visible())
+	assert.Len(t, h.versions.Options, 2)
+	// Новая версия первой в списке
+	assert.Equal(t, 1, h.selectedRevision())
+
+	h.versions.SetSelectedIndex(1)
+	h.restoreSelected()
+	assert.Equal(t, "первая", notes.Description)
+	assert.Len(t, notes.DescriptionRevisions, 3)
+}
@@ -0,0 +1,189 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// prefTextDirection - направление письма в окне. Переводов интерфейса нет, настройка только
+// отражает раскладку: у Fyne нет направления письма, поэтому контейнеры раскладываются как
+// обычно и переносятся зеркально
+const prefTextDirection = "interface.direction"
+
+// textDirection - направление письма в окне
+type textDirection string
+
+const (
+	directionSystem textDirection = ""    // как у языка системы
+	directionLTR    textDirection = "ltr" // слева направо
+	directionRTL    textDirection = "rtl" // справа налево
+)
+
+// textDirections - направления для выбора в настройках
+var textDirections = []textDirection{directionSystem, directionLTR, directionRTL}
+
+// Title возвращает название направления для настроек
+func (d textDirection) Title() string {
+	return map[textDirection]string{
+		directionSystem: "Как в системе",
+		directionLTR:    "Слева направо",
+		directionRTL:    "Справа налево",
+	}[d]
+}
+
+// rtlLanguages - языки с письмом справа налево
+var rtlLanguages = map[string]bool{"he": true, "ar": true, "fa": true, "ur": true}
+
+// isRTL сообщает, пишут ли на языке справа налево; регион в коде не важен: «he_IL», «ar-EG»
+func isRTL(language string) bool {
+	code, _, _ := strings.Cut(strings.ToLower(language), "_")
+	code, _, _ = strings.Cut(code, "-")
+	return rtlLanguages[code]
+}
+
+// loadRTL сообщает, отражать ли окно справа налево; по умолчанию - по языку системы
+func loadRTL(prefs fyne.Preferences) bool {
+	switch textDirection(prefs.String(prefTextDirection)) {
+	case directionLTR:
+		return false
+	case directionRTL:
+		return true
+	}
+	return isRTL(string(lang.SystemLocale()))
+}
+
+// textAlign - выравнивание текста по началу строки в направлении письма
+func textAlign(rtl bool) fyne.TextAlign {
+	if rtl {
+		return fyne.TextAlignTrailing
+	}
+	return fyne.TextAlignLeading
+}
+
+// newDirectionalHBox создает ряд, в котором objects идут в направлении письма
+func newDirectionalHBox(rtl bool, objects ...fyne.CanvasObject) *fyne.Container {
+	if rtl {
+		objects = slices.Clone(objects)
+		slices.Reverse(objects)
+	}
+	return container.NewHBox(objects...)
+}
+
+// newDirectionalBorder - newReadingBorder, где start стоит в начале строки, а end - в конце
+// в направлении письма
+func newDirectionalBorder(rtl bool, top, bottom, start, end fyne.CanvasObject, center ...fyne.CanvasObject) *fyne.Container {
+	if rtl {
+		return newReadingBorder(top, bottom, end, start, center...)
+	}
+	return newReadingBorder(top, bottom, start, end, center...)
+}
+
+// mirroredLayout раскладывает объекты как inner и отражает их по горизонтали. Вложенные
+// контейнеры отражаются при раскладке, поэтому добавленные позже объекты тоже встают зеркально
+type mirroredLayout struct {
+	inner fyne.Layout
+}
+
+func (l mirroredLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	l.inner.Layout(objects, size)
+	for _, o := range objects {
+		pos := o.Position()
+		o.Move(fyne.NewPos(size.Width-pos.X-o.Size().Width, pos.Y))
+		mirror(o)
+	}
+}
+
+func (l mirroredLayout) MinSize(objects []fyne.CanvasObject) fyne.Size {
+	return l.inner.MinSize(objects)
+}
+
+// mirror отражает справа налево o и вложенные в него контейнеры и выравнивает подписи по
+// правому краю. Виджеты со своей раскладкой, как строка задачи, отражаются сами
+func mirror(o fyne.CanvasObject) {
+	switch o := o.(type) {
+	case *fyne.Container:
+		if _, ok := o.Layout.(mirroredLayout); ok || o.Layout == nil {
+			return
+		}
+		o.Layout = mirroredLayout{o.Layout}
+		o.Refresh()
+	case *widget.Label:
+		if o.Alignment == fyne.TextAlignLeading {
+			o.Alignment = fyne.TextAlignTrailing
+			o.Refresh()
+		}
+	case *widget.PopUp:
+		mirror(o.Content)
+	case *widget.Card:
+		if o.Content != nil {
+			mirror(o.Content)
+		}
+	case *widget.Form:
+		for _, item := range o.Items {
+			mirror(item.Widget)
+		}
+	case *container.Scroll:
+		mirror(o.Content)
+	case *container.Split:
+		mirror(o.Leading)
+		mirror(o.Trailing)
+	case *container.AppTabs:
+		for _, item := range o.Items {
+			mirror(item.Content)
+		}
+	case *mainTabs:
+		mirror(o.AppTabs)
+	}
+}
+
+// directionalWindow - окно, которое отражает справа налево свое содержимое, диалоги и
+// всплывающие меню
+type directionalWindow struct {
+	fyne.Window
+}
+
+// newDirectionalWindow возвращает w, отражающее содержимое при письме справа налево
+func newDirectionalWindow(rtl bool, w fyne.Window) fyne.Window {
+	if !rtl {
+		return w
+	}
+	return directionalWindow{w}
+}
+
+func (w directionalWindow) SetContent(content fyne.CanvasObject) {
+	mirror(content)
+	w.Window.SetContent(content)
+}
+
+func (w directionalWindow) Canvas() fyne.Canvas {
+	return directionalCanvas{w.Window.Canvas()}
+}
+
+// directionalCanvas - холст окна, отражающий справа налево все, что на него кладут
+type directionalCanvas struct {
+	fyne.Canvas
+}
+
+func (c directionalCanvas) SetContent(content fyne.CanvasObject) {
+	mirror(content)
+	c.Canvas.SetContent(content)
+}
+
+func (c directionalCanvas) Overlays() fyne.OverlayStack {
+	return directionalOverlays{c.Canvas.Overlays()}
+}
+
+// directionalOverlays - слои холста: диалоги и меню отражаются, когда их показывают
+type directionalOverlays struct {
+	fyne.OverlayStack
+}
+
+func (s directionalOverlays) Add(overlay fyne.CanvasObject) {
+	mirror(overlay)
+	s.OverlayStack.Add(overlay)
+}
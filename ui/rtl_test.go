@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+func TestIsRTL(t *testing.T) {
+	assert.True(t, isRTL("he"))
+	assert.True(t, isRTL("ar-EG"))
+	assert.True(t, isRTL("fa_IR"))
+	assert.False(t, isRTL("ru"))
+	assert.False(t, isRTL(""))
+
+	prefs := test.NewTempApp(t).Preferences()
+	prefs.SetString(prefTextDirection, string(directionRTL))
+	assert.True(t, loadRTL(prefs))
+	prefs.SetString(prefTextDirection, string(directionLTR))
+	assert.False(t, loadRTL(prefs))
+}
+
+func TestDirectionalHBox(t *testing.T) {
+	first, second := widget.NewLabel("1"), widget.NewLabel("2")
+	assert.Equal(t, []fyne.CanvasObject{first, second}, newDirectionalHBox(false, first, second).Objects)
+	assert.Equal(t, []fyne.CanvasObject{second, first}, newDirectionalHBox(true, first, second).Objects)
+}
+
+func TestTaskRowRTL(t *testing.T) {
+	test.NewTempApp(t)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	for _, rtl := range []bool{false, true} {
+		row := newTaskRow(densityComfortable, rtl)
+		w := test.NewWindow(row)
+		row.SetTask(&task.Task{Title: "דוח", Priority: 2, DueDate: now}, now, densityComfortable)
+		w.Resize(fyne.NewSize(400, 80))
+
+		// При письме справа налево флажок стоит справа от названия, а срок - слева
+		check := fyne.CurrentApp().Driver().AbsolutePositionForObject(row.check).X
+		title := fyne.CurrentApp().Driver().AbsolutePositionForObject(row.title).X
+		due := fyne.CurrentApp().Driver().AbsolutePositionForObject(row.due).X
+		assert.Equal(t, rtl, check > title, "rtl=%v", rtl)
+		assert.Equal(t, rtl, due < title, "rtl=%v", rtl)
+		assert.Equal(t, textAlign(rtl), row.title.Alignment)
+		w.Close()
+	}
+}
+
+func TestMirror(t *testing.T) {
+	test.NewTempApp(t)
+	start, end := widget.NewLabel("начало"), widget.NewLabel("конец")
+	side, main := widget.NewButton("сбоку", nil), widget.NewLabel("центр")
+	late := widget.NewLabel("позже")
+	row := container.NewHBox(start, end)
+	content := container.NewVBox(row, container.NewBorder(nil, nil, side, nil, main))
+	w := test.NewWindow(content)
+	defer w.Close()
+	mirror(content)
+	w.Resize(fyne.NewSize(400, 200))
+
+	// Ряд идет справа налево, левая часть рамки встает справа, подписи - по правому краю
+	assert.Greater(t, start.Position().X, end.Position().X)
+	assert.Greater(t, side.Position().X, main.Position().X)
+	assert.Equal(t, fyne.TextAlignTrailing, start.Alignment)
+
+	// Объекты, добавленные после отражения, тоже отражаются
+	row.Add(container.NewHBox(late))
+	assert.Equal(t, fyne.TextAlignTrailing, late.Alignment)
+	assert.Greater(t, end.Position().X, row.Objects[2].Position().X)
+}
+
+func TestDirectionalWindow(t *testing.T) {
+	test.NewTempApp(t)
+	plain := test.NewWindow(nil)
+	defer plain.Close()
+	assert.Equal(t, plain, newDirectionalWindow(false, plain))
+
+	w := newDirectionalWindow(true, plain)
+	content := container.NewHBox(widget.NewLabel("1"), widget.NewLabel("2"))
+	w.SetContent(content)
+	assert.IsType(t, mirroredLayout{}, content.Layout)
+
+	// Диалоги окна отражаются, когда их показывают
+	form := container.NewHBox(widget.NewLabel("Название"), widget.NewEntry())
+	dialog.ShowCustom("Задача", "OK", form, w)
+	assert.IsType(t, mirroredLayout{}, form.Layout)
+}
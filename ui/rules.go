@@ -0,0 +1,183 @@
+package ui
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/rules"
+	"taskmanager/task"
+)
+
+// rulesInterval - как часто проверять правила, зависящие от времени (просрочка, дни без изменений)
+const rulesInterval = time.Minute
+
+// Ключи настроек правил автоматизации
+const (
+	prefRules        = "rules.list"
+	prefRulesApplied = "rules.applied"
+)
+
+// newRulesEngine создает движок с правилами профиля. Уведомления показываются системой,
+// а отметки о срабатывании правил сохраняются, чтобы не повторять их после перезапуска
+func newRulesEngine(a fyne.App, prefs fyne.Preferences) *rules.Engine {
+	list, err := rules.Parse(prefs.String(prefRules))
+	if err != nil {
+		slog.Error("failed to read automation rules", "err", err)
+	}
+	engine := rules.NewEngine(list, nil)
+	if data := prefs.String(prefRulesApplied); data != "" {
+		if err := json.Unmarshal([]byte(data), &engine.Applied); err != nil {
+			slog.Warn("failed to read applied rules", "err", err)
+		}
+	}
+	engine.Notify = func(title, message string) {
+		a.SendNotification(fyne.NewNotification(title, message))
+	}
+	return engine
+}
+
+// runRules применяет правила ко всем задачам, пишет в журнал, что изменилось,
+// и запоминает срабатывания
+func runRules(prefs fyne.Preferences, engine *rules.Engine, tm *task.TaskManager) {
+	effects := engine.Run(tm, tm.DueZone().Now())
+	for _, effect := range effects {
+		slog.Info("automation rule applied", "rule", effect.Rule.Describe(), "task", effect.Task.UUID)
+	}
+	if len(effects) > 0 {
+		saveRulesApplied(prefs, engine)
+	}
+}
+
+// saveRulesApplied сохраняет отметки о срабатывании правил
+func saveRulesApplied(prefs fyne.Preferences, engine *rules.Engine) {
+	data, _ := json.Marshal(engine.Applied)
+	prefs.SetString(prefRulesApplied, string(data))
+}
+
+// Варианты условий и действий в окне правила
+var (
+	ruleConditions      = []rules.Condition{rules.CondOverdue, rules.CondTagged, rules.CondCompleted}
+	ruleConditionTitles = []string{"Задача просрочена", "У задачи метка", "Задача выполнена"}
+	ruleActions         = []rules.Action{rules.ActionSetPriority, rules.ActionNotify, rules.ActionArchive}
+	ruleActionTitles    = []string{"Задать приоритет", "Показать уведомление", "Убрать в архив"}
+)
+
+// showRulesDialog показывает правила автоматизации: их можно добавить, отключить, удалить
+// и проверить пробным запуском, который только показывает, что изменится
+func showRulesDialog(w fyne.Window, prefs fyne.Preferences, tm *task.TaskManager, engine *rules.Engine) {
+	edited := append([]rules.Rule(nil), engine.Rules...)
+
+	var list *widget.List
+	list = widget.NewList(
+		func() int { return len(edited) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, widget.NewCheck("", nil), widget.NewButton("Удалить", nil), widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			row := item.(*fyne.Container)
+			label := row.Objects[0].(*widget.Label)
+			check := row.Objects[1].(*widget.Check)
+			remove := row.Objects[2].(*widget.Button)
+
+			label.SetText(edited[id].Describe())
+			check.OnChanged = nil
+			check.SetChecked(edited[id].Enabled)
+			check.OnChanged = func(enabled bool) { edited[id].Enabled = enabled }
+			remove.OnTapped = func() {
+				edited = append(edited[:id:id], edited[id+1:]...)
+				list.Refresh()
+			}
+		},
+	)
+
+	addButton := widget.NewButton("Добавить…", func() {
+		showRuleForm(w, func(rule rules.Rule) {
+			edited = append(edited, rule)
+			list.Refresh()
+		})
+	})
+	previewButton := widget.NewButton("Проверить", func() {
+		preview := rules.NewEngine(edited, nil)
+		preview.Applied = engine.Applied
+		effects := preview.Plan(tm.Tasks(), tm.DueZone().Now())
+		if len(effects) == 0 {
+			dialog.ShowInformation("Пробный запуск", "Сейчас правила ничего не изменят", w)
+			return
+		}
+		lines := make([]string, len(effects))
+		for i, effect := range effects {
+			lines[i] = effect.Describe()
+		}
+		text := widget.NewLabel(strings.Join(lines, "\n"))
+		scroll := container.NewVScroll(text)
+		scroll.SetMinSize(fyne.NewSize(480, 240))
+		dialog.ShowCustom("Пробный запуск: что изменится", "Закрыть", scroll, w)
+	})
+
+	content := container.NewBorder(
+		widget.NewLabel("Правила проверяются при изменении задач и раз в минуту"),
+		container.NewHBox(addButton, previewButton),
+		nil, nil, list)
+	d := dialog.NewCustomConfirm("Правила автоматизации", "Сохранить", "Отмена", content, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		engine.Rules = edited
+		prefs.SetString(prefRules, rules.Marshal(edited))
+		runRules(prefs, engine, tm)
+	}, w)
+	d.Resize(dialogSize(w, fyne.NewSize(600, 420)))
+	d.Show()
+}
+
+// showRuleForm запрашивает условие и действие нового правила
+func showRuleForm(w fyne.Window, onAdd func(rules.Rule)) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("необязательно")
+	conditionSelect := widget.NewSelect(ruleConditionTitles, nil)
+	conditionSelect.SetSelectedIndex(0)
+	tagEntry := widget.NewEntry()
+	tagEntry.SetPlaceHolder("waiting")
+	daysEntry := widget.NewEntry()
+	daysEntry.SetText("0")
+	actionSelect := widget.NewSelect(ruleActionTitles, nil)
+	actionSelect.SetSelectedIndex(0)
+	prioritySelect := widget.NewSelect([]string{task.PriorityText(1), task.PriorityText(2), task.PriorityText(3)}, nil)
+	prioritySelect.SetSelectedIndex(2)
+
+	formItems := []*widget.FormItem{
+		{Text: "Название", Widget: nameEntry},
+		{Text: "Когда", Widget: conditionSelect},
+		{Text: "Метка", Widget: tagEntry, HintText: "Для условия «У задачи метка»"},
+		{Text: "Дней без изменений", Widget: daysEntry, HintText: "Для меток и выполненных задач, 0 - сразу"},
+		{Text: "Что сделать", Widget: actionSelect},
+		{Text: "Приоритет", Widget: prioritySelect, HintText: "Для действия «Задать приоритет»"},
+	}
+	dialog.ShowForm("Новое правило", "Добавить", "Отмена", formItems, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		rule := rules.NewRule(ruleConditions[conditionSelect.SelectedIndex()], ruleActions[actionSelect.SelectedIndex()])
+		rule.Name = strings.TrimSpace(nameEntry.Text)
+		rule.Tag = strings.TrimPrefix(strings.TrimSpace(tagEntry.Text), "#")
+		rule.Priority = prioritySelect.SelectedIndex() + 1
+		days, err := strconv.Atoi(strings.TrimSpace(daysEntry.Text))
+		if err != nil {
+			days = -1
+		}
+		rule.Days = days
+		if err := rule.Validate(); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		onAdd(rule)
+	}, w)
+}
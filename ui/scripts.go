@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+
+	"taskmanager/scripting"
+	"taskmanager/task"
+)
+
+// prefScriptsDir - папка со скриптами *.star; пусто - скрипты отключены
+const prefScriptsDir = "scripts.dir"
+
+// scriptHost загружает скрипты профиля и показывает их команды в меню «Скрипты»
+type scriptHost struct {
+	a      fyne.App
+	w      fyne.Window
+	prefs  fyne.Preferences
+	tm     *task.TaskManager
+	menu   *fyne.Menu
+	detach func()
+}
+
+func newScriptHost(a fyne.App, w fyne.Window, prefs fyne.Preferences, tm *task.TaskManager) *scriptHost {
+	h := &scriptHost{a: a, w: w, prefs: prefs, tm: tm, menu: fyne.NewMenu("Скрипты")}
+	h.setCommands(nil)
+	return h
+}
+
+// Reload заново загружает скрипты из папки в настройках. Ошибки скриптов пишутся в журнал,
+// а если showErrors - показываются пользователю
+func (h *scriptHost) Reload(showErrors bool) {
+	h.Close()
+	dir := strings.TrimSpace(h.prefs.String(prefScriptsDir))
+	if dir == "" {
+		h.setCommands(nil)
+		if showErrors {
+			dialog.ShowInformation("Скрипты", "Укажите папку скриптов в настройках", h.w)
+		}
+		return
+	}
+
+	runtime, errs := scripting.Load(dir, h.tm, func(message string) {
+		h.a.SendNotification(fyne.NewNotification("Скрипт", message))
+	})
+	for _, err := range errs {
+		slog.Error("failed to load script", "dir", dir, "err", err)
+	}
+	if showErrors && len(errs) > 0 {
+		dialog.ShowError(errors.Join(errs...), h.w)
+	}
+	h.detach = runtime.Attach()
+	h.setCommands(runtime)
+	slog.Info("scripts loaded", "dir", dir, "commands", len(runtime.Commands()))
+}
+
+// Close отключает обработчики событий скриптов
+func (h *scriptHost) Close() {
+	if h.detach != nil {
+		h.detach()
+		h.detach = nil
+	}
+}
+
+// setCommands перестраивает меню: команды скриптов и перезагрузка
+func (h *scriptHost) setCommands(runtime *scripting.Runtime) {
+	var items []*fyne.MenuItem
+	if runtime != nil {
+		for _, cmd := range runtime.Commands() {
+			items = append(items, fyne.NewMenuItem(cmd.Name, func() {
+				if err := runtime.RunCommand(cmd); err != nil {
+					slog.Error("script command failed", "script", cmd.Script, "command", cmd.Name, "err", err)
+					dialog.ShowError(err, h.w)
+				}
+			}))
+		}
+	}
+	if len(items) > 0 {
+		items = append(items, fyne.NewMenuItemSeparator())
+	}
+	items = append(items, fyne.NewMenuItem("Перезагрузить скрипты", func() { h.Reload(true) }))
+	h.menu.Items = items
+	// Пока окно заблокировано, меню скрыто
+	if menu := h.w.MainMenu(); menu != nil {
+		menu.Refresh()
+	}
+}
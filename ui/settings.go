@@ -0,0 +1,375 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/storage"
+	"taskmanager/task"
+)
+
+// Ключи общих настроек приложения
+const (
+	prefBackupKeep        = "backup.keep"
+	prefLockPIN           = "lock.pin"
+	prefLockIdleMinutes   = "lock.idle_minutes"
+	prefDueAfterCreated   = "validate.due_after_created"
+	prefDueZone           = "dates.zone"
+	prefGRPCAddr          = "api.grpc_addr"
+	prefHTTPAddr          = "api.http_addr"
+	prefAPIKeys           = "api.keys"
+	prefTLSCert           = "api.tls_cert"
+	prefTLSKey            = "api.tls_key"
+	prefUpdateCheck       = "update.check_on_startup"
+	prefOnboardingDone    = "onboarding.done"
+	prefPeople            = "people.names"
+	prefSyncURL           = "sync.url"
+	prefSyncKey           = "sync.key"
+	prefStorageFormat     = "storage.format"
+	prefWebDAVURL         = "storage.webdav_url"
+	prefWebDAVUser        = "storage.webdav_user"
+	prefWebDAVPassword    = "storage.webdav_password"
+	prefCloud             = "storage.cloud"
+	prefCloudClientID     = "storage.cloud_client_id"
+	prefCloudClientSecret = "storage.cloud_client_secret"
+	prefCloudToken        = "storage.cloud_token"
+	prefE2EKeyring        = "e2e.keyring"
+	prefE2EPhraseShown    = "e2e.phrase_shown"
+)
+
+// applySettings применяет сохраненные настройки к менеджеру задач и блокировке приложения
+func applySettings(prefs fyne.Preferences, tm *task.TaskManager, lock *appLock) {
+	tm.SetBackupKeep(prefs.IntWithFallback(prefBackupKeep, task.DefaultBackupKeep))
+	tm.SetRequireDueAfterCreated(prefs.Bool(prefDueAfterCreated))
+	tm.SetDueZone(task.DueZone(prefs.StringWithFallback(prefDueZone, string(task.DueZoneLocal))))
+	applyHolidays(prefs, tm)
+	lock.pinHash = prefs.String(prefLockPIN)
+	lock.idleTimeout = time.Duration(prefs.Int(prefLockIdleMinutes)) * time.Minute
+}
+
+// storageCodec возвращает формат локального файла задач из настроек; по умолчанию - JSON
+func storageCodec(prefs fyne.Preferences) storage.Codec {
+	if codec := storage.CodecByName(prefs.String(prefStorageFormat)); codec != nil {
+		return codec
+	}
+	return storage.JSONCodec{}
+}
+
+// storageFormatTitles - названия форматов файла задач для выбора в настройках
+func storageFormatTitles() []string {
+	titles := make([]string, len(storage.Codecs))
+	for i, codec := range storage.Codecs {
+		titles[i] = strings.ToUpper(codec.Name())
+	}
+	return titles
+}
+
+// showSettingsDialog показывает окно настроек приложения. onSaved вызывается после сохранения настроек
+func showSettingsDialog(w fyne.Window, prefs fyne.Preferences, tm *task.TaskManager, lock *appLock, onSaved func()) {
+	backupKeepSelect := widget.NewSelect([]string{"0", "3", "5", "10", "20"}, nil)
+	backupKeepSelect.SetSelected(strconv.Itoa(prefs.IntWithFallback(prefBackupKeep, task.DefaultBackupKeep)))
+
+	pinEntry := widget.NewPasswordEntry()
+	if lock.Enabled() {
+		pinEntry.SetPlaceHolder("не менять")
+	}
+	removePINCheck := widget.NewCheck("Отключить блокировку", nil)
+	idleSelect := widget.NewSelect([]string{"0", "1", "5", "10", "15", "30", "60"}, nil)
+	idleSelect.SetSelected(strconv.Itoa(prefs.Int(prefLockIdleMinutes)))
+
+	dueCheck := widget.NewCheck("Срок не раньше дня создания задачи", nil)
+	dueCheck.SetChecked(prefs.Bool(prefDueAfterCreated))
+
+	zoneNames := map[task.DueZone]string{task.DueZoneLocal: "Местное время", task.DueZoneUTC: "UTC"}
+	zoneSelect := widget.NewSelect([]string{zoneNames[task.DueZoneLocal], zoneNames[task.DueZoneUTC]}, nil)
+	zoneSelect.SetSelected(zoneNames[tm.DueZone()])
+
+	holidaySelect := widget.NewSelect(holidaySourceTitles(), nil)
+	holidaySelect.SetSelected(holidaySourceTitle(prefs.String(prefHolidaySource)))
+	holidayURLEntry := widget.NewEntry()
+	holidayURLEntry.SetPlaceHolder("https://calendar.example.com/holidays.ics")
+	holidayURLEntry.SetText(prefs.String(prefHolidayICSURL))
+	skipDaysOffCheck := widget.NewCheck("Пропускать выходные и праздники", nil)
+	skipDaysOffCheck.SetChecked(prefs.Bool(prefSkipDaysOff))
+
+	spellLanguagesEntry := widget.NewEntry()
+	spellLanguagesEntry.SetPlaceHolder("ru_RU, en_US")
+	spellLanguagesEntry.SetText(prefs.StringWithFallback(prefSpellLanguages, defaultSpellLanguages))
+	spellDirEntry := widget.NewEntry()
+	spellDirEntry.SetPlaceHolder("Только системные словари")
+	spellDirEntry.SetText(prefs.String(prefSpellDir))
+
+	grpcAddrEntry := widget.NewEntry()
+	grpcAddrEntry.SetPlaceHolder("127.0.0.1:50051")
+	grpcAddrEntry.SetText(prefs.String(prefGRPCAddr))
+
+	httpAddrEntry := widget.NewEntry()
+	httpAddrEntry.SetPlaceHolder("127.0.0.1:8080")
+	httpAddrEntry.SetText(prefs.String(prefHTTPAddr))
+
+	tlsCertEntry := widget.NewEntry()
+	tlsCertEntry.SetPlaceHolder("/path/to/cert.pem")
+	tlsCertEntry.SetText(prefs.String(prefTLSCert))
+	tlsKeyEntry := widget.NewEntry()
+	tlsKeyEntry.SetPlaceHolder("/path/to/key.pem")
+	tlsKeyEntry.SetText(prefs.String(prefTLSKey))
+
+	peopleEntry := widget.NewEntry()
+	peopleEntry.SetPlaceHolder("Мама, Папа, Петя")
+	peopleEntry.SetText(strings.Join(prefs.StringList(prefPeople), ", "))
+
+	contextsEntry := widget.NewEntry()
+	contextsEntry.SetText(strings.Join(prefs.StringListWithFallback(prefContexts, defaultContexts), ", "))
+
+	syncURLEntry := widget.NewEntry()
+	syncURLEntry.SetPlaceHolder("https://sync.example.com:8443")
+	syncURLEntry.SetText(prefs.String(prefSyncURL))
+	syncKeyEntry := widget.NewPasswordEntry()
+	syncKeyEntry.SetText(prefs.String(prefSyncKey))
+
+	webdavURLEntry := widget.NewEntry()
+	webdavURLEntry.SetPlaceHolder("https://cloud.example.com/remote.php/dav/files/user/tasks.json")
+	webdavURLEntry.SetText(prefs.String(prefWebDAVURL))
+	webdavUserEntry := widget.NewEntry()
+	webdavUserEntry.SetText(prefs.String(prefWebDAVUser))
+	webdavPasswordEntry := widget.NewPasswordEntry()
+	webdavPasswordEntry.SetText(prefs.String(prefWebDAVPassword))
+
+	formatSelect := widget.NewSelect(storageFormatTitles(), nil)
+	formatSelect.SetSelected(strings.ToUpper(storageCodec(prefs).Name()))
+
+	cloudNames := map[string]string{"": "Нет", cloudDropbox: "Dropbox", cloudGoogleDrive: "Google Диск"}
+	cloudSelect := widget.NewSelect([]string{"Нет", "Dropbox", "Google Диск"}, nil)
+	cloudSelect.SetSelected(cloudNames[prefs.String(prefCloud)])
+	cloudProvider := func() string {
+		for provider, name := range cloudNames {
+			if name == cloudSelect.Selected {
+				return provider
+			}
+		}
+		return ""
+	}
+	cloudClientIDEntry := widget.NewEntry()
+	cloudClientIDEntry.SetText(prefs.String(prefCloudClientID))
+	cloudClientSecretEntry := widget.NewPasswordEntry()
+	cloudClientSecretEntry.SetText(prefs.String(prefCloudClientSecret))
+	cloudStatus := widget.NewLabel("Вход не выполнен")
+	if prefs.String(prefCloudToken) != "" {
+		cloudStatus.SetText("Вход выполнен")
+	}
+	cloudLoginButton := widget.NewButton("Войти…", func() {
+		provider := cloudProvider()
+		if provider == "" || strings.TrimSpace(cloudClientIDEntry.Text) == "" {
+			dialog.ShowInformation("Облачное хранилище", "Выберите хранилище и укажите ключ приложения", w)
+			return
+		}
+		showCloudLogin(w, prefs, cloudOAuth(provider, strings.TrimSpace(cloudClientIDEntry.Text), cloudClientSecretEntry.Text), func() {
+			cloudStatus.SetText("Вход выполнен")
+		})
+	})
+
+	scriptsDirEntry := widget.NewEntry()
+	scriptsDirEntry.SetPlaceHolder("/path/to/scripts")
+	scriptsDirEntry.SetText(prefs.String(prefScriptsDir))
+
+	pluginsDirEntry := widget.NewEntry()
+	pluginsDirEntry.SetPlaceHolder("/path/to/plugins")
+	pluginsDirEntry.SetText(prefs.String(prefPluginsDir))
+
+	reviewCheck := widget.NewCheck("Напоминать о еженедельном обзоре задач", nil)
+	reviewCheck.SetChecked(prefs.BoolWithFallback(prefReviewReminder, true))
+
+	capacitySelect := widget.NewSelect([]string{"240", "360", "480", "600", "720"}, nil)
+	capacitySelect.SetSelected(strconv.Itoa(workloadCapacity(prefs)))
+
+	nudgeSelect := widget.NewSelect([]string{"0", "1", "2", "3", "5", "7", "14"}, nil)
+	nudgeSelect.SetSelected(strconv.Itoa(nudgeDays(prefs)))
+	timerIdleSelect := widget.NewSelect([]string{"0", "3", "5", "10", "15", "30"}, nil)
+	timerIdleSelect.SetSelected(strconv.Itoa(timerIdleMinutes(prefs)))
+
+	quietFromEntry := widget.NewEntry()
+	quietFromEntry.SetPlaceHolder("22:00")
+	quietFromEntry.SetText(prefs.String(prefQuietFrom))
+	quietFromEntry.Validator = validateClock
+	quietToEntry := widget.NewEntry()
+	quietToEntry.SetPlaceHolder("08:00")
+	quietToEntry.SetText(prefs.String(prefQuietTo))
+	quietToEntry.Validator = validateClock
+	quietWeekendsCheck := widget.NewCheck("Не беспокоить в выходные", nil)
+	quietWeekendsCheck.SetChecked(prefs.Bool(prefQuietWeekends))
+	alerts := loadAlerts(prefs)
+	alertHighSelect := newUrgencySelect(alerts.High, false)
+	alertOtherSelect := newUrgencySelect(alerts.Other, false)
+	alertSoundEntry := widget.NewEntry()
+	alertSoundEntry.SetPlaceHolder("Системный звук")
+	alertSoundEntry.SetText(alerts.Sound)
+
+	directionTitles := make([]string, len(textDirections))
+	for i, direction := range textDirections {
+		directionTitles[i] = direction.Title()
+	}
+	directionSelect := widget.NewSelect(directionTitles, nil)
+	directionSelect.SetSelected(textDirection(prefs.String(prefTextDirection)).Title())
+
+	densityTitles := make([]string, len(rowDensities))
+	for i, density := range rowDensities {
+		densityTitles[i] = density.Title()
+	}
+	densitySelect := widget.NewSelect(densityTitles, nil)
+	densitySelect.SetSelected(loadRowDensity(prefs).Title())
+
+	// Масштаб и контрастность - общие для всех профилей, как и тема приложения
+	appPrefs := fyne.CurrentApp().Preferences()
+	scaleLabel := widget.NewLabel("")
+	scaleSlider := widget.NewSlider(minUIScale, maxUIScale)
+	scaleSlider.Step = 0.05
+	scaleSlider.OnChanged = func(value float64) {
+		scaleLabel.SetText(strconv.Itoa(int(value*100+0.5)) + "%")
+	}
+	scaleSlider.SetValue(float64(loadAppTheme(appPrefs).scale))
+	contrastCheck := widget.NewCheck("Высокая контрастность", nil)
+	contrastCheck.SetChecked(appPrefs.Bool(prefHighContrast))
+
+	updateCheck := widget.NewCheck("Проверять обновления при запуске", nil)
+	updateCheck.SetChecked(prefs.Bool(prefUpdateCheck))
+
+	formItems := []*widget.FormItem{
+		{Text: "Резервных копий", Widget: backupKeepSelect, HintText: "Сколько копий хранить при сохранении, 0 - не создавать"},
+		{Text: "PIN-код блокировки", Widget: pinEntry, HintText: "Ctrl+L блокирует окно"},
+		{Text: "", Widget: removePINCheck},
+		{Text: "Блокировать через (мин)", Widget: idleSelect, HintText: "Время бездействия, 0 - только вручную"},
+		{Text: "Проверка", Widget: dueCheck},
+		{Text: "Сроки задач", Widget: zoneSelect, HintText: "В каком поясе начинается день: по нему срок наступает и становится просроченным"},
+		{Text: "Праздники", Widget: holidaySelect, HintText: "Праздничные дни отмечаются в календаре"},
+		{Text: "Календарь ICS", Widget: holidayURLEntry, HintText: "Ссылка на календарь праздников, например из Google Календаря"},
+		{Text: "", Widget: skipDaysOffCheck, HintText: "Повторяющиеся задачи и перенос срока в обзоре не попадают на нерабочие дни"},
+		{Text: "Орфография", Widget: spellLanguagesEntry, HintText: "Словари Hunspell через запятую, пусто - не проверять"},
+		{Text: "Папка словарей", Widget: spellDirEntry, HintText: "Где искать файлы .dic и .aff кроме системных папок"},
+		{Text: "Масштаб интерфейса", Widget: container.NewBorder(nil, nil, nil, scaleLabel, scaleSlider), HintText: "Размер текста, значков и отступов"},
+		{Text: "", Widget: contrastCheck},
+		{Text: "Направление письма", Widget: directionSelect, HintText: "Справа налево окно отражается, как для иврита и арабского; применяется после перезапуска"},
+		{Text: "Строки списка", Widget: densitySelect, HintText: "Компактно - только название, без подробностей"},
+		{Text: "Приоритеты", Widget: priorityLegend(), HintText: "Цвета флажков в списке, календаре и на доске"},
+		{Text: "Рабочий день (мин)", Widget: capacitySelect, HintText: "Сколько минут задач по оценке помещается в день"},
+		{Text: "Обзор", Widget: reviewCheck},
+		{Text: "Ожидание (дней)", Widget: nudgeSelect, HintText: "Напомнить о задаче в ожидании, если она столько дней не менялась, 0 - не напоминать"},
+		{Text: "Простой таймера (мин)", Widget: timerIdleSelect, HintText: "Спросить, учитывать ли время, когда вас не было за компьютером, 0 - не спрашивать"},
+		{Text: "Тихие часы с", Widget: quietFromEntry, HintText: "Напоминания в тихие часы придут после них. Пусто - без тихих часов"},
+		{Text: "Тихие часы до", Widget: quietToEntry},
+		{Text: "", Widget: quietWeekendsCheck},
+		{Text: "Важные задачи", Widget: alertHighSelect, HintText: "Как напоминать о задачах с высоким приоритетом"},
+		{Text: "Остальные задачи", Widget: alertOtherSelect, HintText: "Тихое напоминание видно только в окне приложения"},
+		{Text: "Звук напоминания", Widget: alertSoundEntry, HintText: "Файл WAV или OGG; у задачи можно выбрать свой"},
+		{Text: "Люди", Widget: peopleEntry, HintText: "Исполнители задач через запятую"},
+		{Text: "Контексты", Widget: contextsEntry, HintText: "Где выполнять задачи (GTD) через запятую: @дом, @работа"},
+		{Text: "Обновления", Widget: updateCheck, HintText: "Запрашивает последний релиз на GitHub"},
+		{Text: "Сервер синхронизации", Widget: syncURLEntry, HintText: "Пусто - синхронизация отключена"},
+		{Text: "Ключ синхронизации", Widget: syncKeyEntry, HintText: "Выдается командой syncserver -new-key"},
+		{Text: "Формат файла задач", Widget: formatSelect, HintText: "YAML и TOML удобно хранить в git. Файл переводится при следующем сохранении после перезапуска"},
+		{Text: "Файл задач WebDAV", Widget: webdavURLEntry, HintText: "Например, Nextcloud. Пусто - локальный файл. Применяется после перезапуска"},
+		{Text: "Пользователь WebDAV", Widget: webdavUserEntry},
+		{Text: "Пароль WebDAV", Widget: webdavPasswordEntry, HintText: "Для Nextcloud лучше создать пароль приложения"},
+		{Text: "Облачное хранилище", Widget: cloudSelect, HintText: "Файл задач в папке приложения. Применяется после перезапуска"},
+		{Text: "Ключ приложения", Widget: cloudClientIDEntry, HintText: "App key Dropbox или Client ID Google"},
+		{Text: "Секрет приложения", Widget: cloudClientSecretEntry, HintText: "Только для Google"},
+		{Text: "", Widget: container.NewHBox(cloudLoginButton, cloudStatus)},
+		{Text: "Папка скриптов", Widget: scriptsDirEntry, HintText: "Скрипты *.star на Starlark. Пусто - отключены. Меню «Скрипты» - перезагрузить"},
+		{Text: "Папка расширений", Widget: pluginsDirEntry, HintText: "Программы экспорта (describe/run). Применяется после перезапуска"},
+		{Text: "Адрес gRPC API", Widget: grpcAddrEntry, HintText: "Пусто - отключен. Применяется после перезапуска"},
+		{Text: "Адрес веб-интерфейса", Widget: httpAddrEntry, HintText: "Пусто - отключен, 0.0.0.0:8080 - доступ из локальной сети. Применяется после перезапуска"},
+		{Text: "Сертификат TLS", Widget: tlsCertEntry, HintText: "Файл PEM для HTTPS и gRPC, пусто - без шифрования"},
+		{Text: "Ключ TLS", Widget: tlsKeyEntry, HintText: "Файл PEM с закрытым ключом сертификата"},
+	}
+
+	dialog.ShowForm("Настройки", "Сохранить", "Отмена", formItems, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		keep, err := strconv.Atoi(backupKeepSelect.Selected)
+		if err != nil {
+			keep = task.DefaultBackupKeep
+		}
+		prefs.SetInt(prefBackupKeep, keep)
+
+		switch {
+		case removePINCheck.Checked:
+			prefs.SetString(prefLockPIN, "")
+		case pinEntry.Text != "":
+			prefs.SetString(prefLockPIN, hashPIN(pinEntry.Text))
+		}
+		idleMinutes, _ := strconv.Atoi(idleSelect.Selected)
+		prefs.SetInt(prefLockIdleMinutes, idleMinutes)
+		prefs.SetBool(prefDueAfterCreated, dueCheck.Checked)
+		for zone, name := range zoneNames {
+			if name == zoneSelect.Selected {
+				prefs.SetString(prefDueZone, string(zone))
+			}
+		}
+		if url := strings.TrimSpace(holidayURLEntry.Text); url != prefs.String(prefHolidayICSURL) {
+			// Календарь со старой ссылки больше не нужен; новый загрузится после сохранения
+			prefs.SetString(prefHolidayICSURL, url)
+			prefs.SetString(prefHolidayICSData, "")
+		}
+		prefs.SetString(prefHolidaySource, holidaySourceByTitle(holidaySelect.Selected))
+		prefs.SetBool(prefSkipDaysOff, skipDaysOffCheck.Checked)
+		prefs.SetString(prefSpellLanguages, strings.TrimSpace(spellLanguagesEntry.Text))
+		prefs.SetString(prefSpellDir, strings.TrimSpace(spellDirEntry.Text))
+		if capacity, err := strconv.Atoi(capacitySelect.Selected); err == nil {
+			prefs.SetInt(prefWorkloadCapacity, capacity)
+		}
+		if minutes, err := strconv.Atoi(timerIdleSelect.Selected); err == nil {
+			prefs.SetInt(prefTimerIdleMinutes, minutes)
+		}
+		if days, err := strconv.Atoi(nudgeSelect.Selected); err == nil {
+			prefs.SetInt(prefNudgeDays, days)
+		}
+		for _, direction := range textDirections {
+			if direction.Title() == directionSelect.Selected {
+				prefs.SetString(prefTextDirection, string(direction))
+			}
+		}
+		for _, density := range rowDensities {
+			if density.Title() == densitySelect.Selected {
+				prefs.SetString(prefRowDensity, string(density))
+			}
+		}
+		appPrefs.SetFloat(prefUIScale, scaleSlider.Value)
+		appPrefs.SetBool(prefHighContrast, contrastCheck.Checked)
+		applyAppTheme(fyne.CurrentApp())
+		prefs.SetBool(prefUpdateCheck, updateCheck.Checked)
+		prefs.SetBool(prefReviewReminder, reviewCheck.Checked)
+		prefs.SetString(prefQuietFrom, strings.TrimSpace(quietFromEntry.Text))
+		prefs.SetString(prefQuietTo, strings.TrimSpace(quietToEntry.Text))
+		prefs.SetBool(prefQuietWeekends, quietWeekendsCheck.Checked)
+		prefs.SetString(prefAlertHigh, string(selectedUrgency(alertHighSelect)))
+		prefs.SetString(prefAlertOther, string(selectedUrgency(alertOtherSelect)))
+		prefs.SetString(prefAlertSound, strings.TrimSpace(alertSoundEntry.Text))
+		prefs.SetStringList(prefPeople, task.ParseTags(peopleEntry.Text))
+		prefs.SetStringList(prefContexts, task.ParseTags(contextsEntry.Text))
+		prefs.SetString(prefSyncURL, strings.TrimSpace(syncURLEntry.Text))
+		prefs.SetString(prefSyncKey, strings.TrimSpace(syncKeyEntry.Text))
+		prefs.SetString(prefStorageFormat, strings.ToLower(formatSelect.Selected))
+		prefs.SetString(prefWebDAVURL, strings.TrimSpace(webdavURLEntry.Text))
+		prefs.SetString(prefWebDAVUser, strings.TrimSpace(webdavUserEntry.Text))
+		prefs.SetString(prefWebDAVPassword, webdavPasswordEntry.Text)
+		prefs.SetString(prefCloud, cloudProvider())
+		prefs.SetString(prefCloudClientID, strings.TrimSpace(cloudClientIDEntry.Text))
+		prefs.SetString(prefCloudClientSecret, cloudClientSecretEntry.Text)
+		prefs.SetString(prefScriptsDir, strings.TrimSpace(scriptsDirEntry.Text))
+		prefs.SetString(prefPluginsDir, strings.TrimSpace(pluginsDirEntry.Text))
+		prefs.SetString(prefGRPCAddr, strings.TrimSpace(grpcAddrEntry.Text))
+		prefs.SetString(prefHTTPAddr, strings.TrimSpace(httpAddrEntry.Text))
+		prefs.SetString(prefTLSCert, strings.TrimSpace(tlsCertEntry.Text))
+		prefs.SetString(prefTLSKey, strings.TrimSpace(tlsKeyEntry.Text))
+
+		applySettings(prefs, tm, lock)
+		onSaved()
+	}, w)
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/task"
+)
+
+// formatSimilar описывает похожие открытые задачи в диалоге добавления
+func formatSimilar(similar []*task.Task) string {
+	text := fmt.Sprintf("Similar open task: #%d %q", similar[0].ID, similar[0].Title)
+	if len(similar) > 1 {
+		text += fmt.Sprintf(" and %d more", len(similar)-1)
+	}
+	return text
+}
+
+// newSimilarNotice создает предупреждение диалога добавления о похожей открытой задаче.
+// check ищет похожие задачи по названию и показывает или прячет предупреждение,
+// open открывает самую похожую задачу
+func newSimilarNotice(tm *task.TaskManager, open func(id int)) (notice *fyne.Container, check func(title string)) {
+	similarID := 0
+	label := widget.NewLabel("")
+	label.Wrapping = fyne.TextWrapWord
+	label.Importance = widget.WarningImportance
+	openButton := widget.NewButton("Open", func() { open(similarID) })
+	notice = container.NewBorder(nil, nil, nil, openButton, label)
+	notice.Hide()
+	check = func(title string) {
+		similar := tm.FindSimilar(title)
+		if len(similar) == 0 {
+			notice.Hide()
+			return
+		}
+		similarID = similar[0].ID
+		label.SetText(formatSimilar(similar))
+		notice.Show()
+	}
+	return notice, check
+}
+
+// similarQuickTasks находит для каждой разобранной задачи похожую открытую задачу
+// или nil, чтобы не создать дубликат из вставленного текста
+func similarQuickTasks(tm *task.TaskManager, items []task.QuickAdd) []*task.Task {
+	similar := make([]*task.Task, len(items))
+	for i, q := range items {
+		if matches := tm.FindSimilar(q.Title); len(matches) > 0 {
+			similar[i] = matches[0]
+		}
+	}
+	return similar
+}
@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+func TestSimilarNotice(t *testing.T) {
+	test.NewTempApp(t)
+	tm := newTestManager(t)
+	bank := mustAddTask(t, tm, "Позвонить в банк", "", 2, time.Now().Add(24*time.Hour))
+	mustAddTask(t, tm, "Позвонить в банки", "", 2, time.Now().Add(24*time.Hour))
+
+	opened := 0
+	notice, check := newSimilarNotice(tm, func(id int) { opened = id })
+	assert.False(t, notice.Visible())
+
+	check("позвонить в БАНК")
+	assert.True(t, notice.Visible())
+	assert.NotNil(t, findText(notice, `Similar open task: #1 "Позвонить в банк" and 1 more`))
+	test.Tap(findText(notice, "Open").(*widget.Button))
+	assert.Equal(t, bank.ID, opened)
+
+	check("Купить хлеб")
+	assert.False(t, notice.Visible())
+}
+
+func TestSimilarQuickTasks(t *testing.T) {
+	tm := newTestManager(t)
+	bank := mustAddTask(t, tm, "Позвонить в банк", "", 2, time.Now().Add(24*time.Hour))
+	items := task.ParseTaskLines("Позвонить в банк!\nКупить хлеб", time.Now())
+
+	similar := similarQuickTasks(tm, items)
+	if assert.Len(t, similar, 2) {
+		assert.Equal(t, bank.ID, similar[0].ID)
+		assert.Nil(t, similar[1])
+	}
+}
@@ -0,0 +1,273 @@
+package ui
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/spell"
+	"taskmanager/task"
+)
+
+// Настройки проверки орфографии
+const (
+	prefSpellLanguages = "spell.languages" // языки словарей через запятую: ru_RU, en_US; пусто - проверка отключена
+	prefSpellDir       = "spell.dir"       // папка со словарями Hunspell кроме системных
+	prefSpellWords     = "spell.words"     // личный словарь: слова, которые не считаются ошибками
+)
+
+// defaultSpellLanguages - словари, которые ищутся, пока пользователь их не выбрал
+const defaultSpellLanguages = "ru_RU, en_US"
+
+// spellChecker - проверка орфографии профиля. Словари читаются в фоне: пока они не прочитаны,
+// поля ввода работают без проверки
+type spellChecker struct {
+	prefs      fyne.Preferences
+	checker    *spell.Checker
+	generation int // номер последней загрузки, чтобы устаревшая загрузка не затерла новую
+}
+
+func newSpellChecker(prefs fyne.Preferences) *spellChecker {
+	s := &spellChecker{prefs: prefs}
+	s.Reload()
+	return s
+}
+
+// Reload заново читает словари из настроек
+func (s *spellChecker) Reload() {
+	languages := task.ParseTags(s.prefs.StringWithFallback(prefSpellLanguages, defaultSpellLanguages))
+	dirs := append([]string{strings.TrimSpace(s.prefs.String(prefSpellDir))}, spell.SearchDirs()...)
+	personal := s.prefs.StringList(prefSpellWords)
+	s.generation++
+	generation := s.generation
+	go func() {
+		checker := loadSpellChecker(languages, dirs, personal)
+		fyne.Do(func() {
+			if generation == s.generation {
+				s.checker = checker
+			}
+		})
+	}()
+}
+
+// loadSpellChecker читает словари языков languages из папок dirs. Ненайденные словари
+// пропускаются: без словарей проверка просто не работает
+func loadSpellChecker(languages, dirs, personal []string) *spell.Checker {
+	var dicts []*spell.Dictionary
+	for _, lang := range languages {
+		path, ok := spell.FindDictionary(lang, dirs)
+		if !ok {
+			slog.Info("spelling dictionary not found", "lang", lang)
+			continue
+		}
+		dict, err := spell.LoadDictionary(path)
+		if err != nil {
+			slog.Error("failed to load spelling dictionary", "path", path, "err", err)
+			continue
+		}
+		dicts = append(dicts, dict)
+	}
+	return spell.NewChecker(dicts, personal)
+}
+
+// Misspelled возвращает слова текста с ошибками
+func (s *spellChecker) Misspelled(text string) []spell.Word {
+	if s == nil {
+		return nil
+	}
+	return s.checker.Misspelled(text)
+}
+
+// Suggest предлагает исправления слова
+func (s *spellChecker) Suggest(word string) []string {
+	if s == nil {
+		return nil
+	}
+	return s.checker.Suggest(word)
+}
+
+// AddWord добавляет слово в личный словарь профиля
+func (s *spellChecker) AddWord(word string) {
+	if s == nil || s.checker == nil {
+		return
+	}
+	s.checker.AddWord(word)
+	words := s.prefs.StringList(prefSpellWords)
+	if !slices.Contains(words, word) {
+		s.prefs.SetStringList(prefSpellWords, append(words, word))
+	}
+}
+
+// spellEntry - поле ввода с проверкой орфографии: слова с ошибками подчеркнуты, а правый щелчок
+// по такому слову предлагает исправления. Многострочное поле, как tabOutEntry, отдает Tab
+// следующему полю
+type spellEntry struct {
+	widget.Entry
+	spelling   *spellChecker
+	misspelled []spell.Word
+	underlines *fyne.Container
+}
+
+func newSpellEntry(spelling *spellChecker, multiLine bool) *spellEntry {
+	e := &spellEntry{spelling: spelling, underlines: container.NewWithoutLayout()}
+	if multiLine {
+		e.MultiLine = true
+		e.Wrapping = fyne.TextWrap(fyne.TextTruncateClip)
+	}
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+func (e *spellEntry) AcceptsTab() bool {
+	return false
+}
+
+func (e *spellEntry) CreateRenderer() fyne.WidgetRenderer {
+	return &spellEntryRenderer{WidgetRenderer: e.Entry.CreateRenderer(), entry: e}
+}
+
+func (e *spellEntry) SetText(text string) {
+	e.Entry.SetText(text)
+	e.checkSpelling()
+}
+
+func (e *spellEntry) TypedRune(r rune) {
+	e.Entry.TypedRune(r)
+	e.checkSpelling()
+}
+
+func (e *spellEntry) TypedKey(key *fyne.KeyEvent) {
+	e.Entry.TypedKey(key)
+	e.checkSpelling()
+}
+
+func (e *spellEntry) TypedShortcut(shortcut fyne.Shortcut) {
+	e.Entry.TypedShortcut(shortcut)
+	e.checkSpelling()
+}
+
+// TappedSecondary показывает исправления слова с ошибкой, а в остальном тексте - обычное меню поля
+func (e *spellEntry) TappedSecondary(pe *fyne.PointEvent) {
+	if word, ok := e.misspelledAt(pe.Position); ok {
+		c := fyne.CurrentApp().Driver().CanvasForObject(e)
+		widget.ShowPopUpMenuAtPosition(e.suggestionMenu(word), c, pe.AbsolutePosition)
+		return
+	}
+	e.Entry.TappedSecondary(pe)
+}
+
+// suggestionMenu - меню исправлений слова и добавления его в личный словарь
+func (e *spellEntry) suggestionMenu(word spell.Word) *fyne.Menu {
+	var items []*fyne.MenuItem
+	for _, suggestion := range e.spelling.Suggest(word.Text) {
+		items = append(items, fyne.NewMenuItem(suggestion, func() {
+			e.replaceWord(word, suggestion)
+		}))
+	}
+	if len(items) == 0 {
+		none := fyne.NewMenuItem("Нет вариантов", nil)
+		none.Disabled = true
+		items = append(items, none)
+	}
+	items = append(items, fyne.NewMenuItemSeparator(), fyne.NewMenuItem("Добавить в словарь", func() {
+		e.spelling.AddWord(word.Text)
+		e.checkSpelling()
+	}))
+	return fyne.NewMenu("", items...)
+}
+
+// replaceWord заменяет слово текста на text
+func (e *spellEntry) replaceWord(word spell.Word, text string) {
+	runes := []rune(e.Text)
+	e.SetText(string(runes[:word.Start]) + text + string(runes[word.End:]))
+}
+
+// misspelledAt возвращает слово с ошибкой в точке pos поля
+func (e *spellEntry) misspelledAt(pos fyne.Position) (spell.Word, bool) {
+	for _, word := range e.misspelled {
+		left, right, top, bottom := e.wordBounds(word)
+		if pos.X >= left && pos.X <= right && pos.Y >= top && pos.Y <= bottom {
+			return word, true
+		}
+	}
+	return spell.Word{}, false
+}
+
+// checkSpelling заново ищет ошибки в тексте и подчеркивает их
+func (e *spellEntry) checkSpelling() {
+	e.misspelled = nil
+	if !e.Password {
+		e.misspelled = e.spelling.Misspelled(e.Text)
+	}
+	e.layoutUnderlines()
+}
+
+// layoutUnderlines подчеркивает слова с ошибками. Слова за краем поля не подчеркиваются:
+// прокрутка многострочного поля не учитывается
+func (e *spellEntry) layoutUnderlines() {
+	th := e.Theme()
+	errorColor := th.Color(theme.ColorNameError, fyne.CurrentApp().Settings().ThemeVariant())
+	pad := th.Size(theme.SizeNameInnerPadding)
+	size := e.Size()
+	var lines []fyne.CanvasObject
+	for _, word := range e.misspelled {
+		left, right, _, bottom := e.wordBounds(word)
+		if right > size.Width-pad || bottom > size.Height {
+			continue
+		}
+		line := canvas.NewLine(errorColor)
+		line.StrokeWidth = 1
+		line.Position1 = fyne.NewPos(left, bottom-1)
+		line.Position2 = fyne.NewPos(right, bottom-1)
+		lines = append(lines, line)
+	}
+	e.underlines.Objects = lines
+	e.underlines.Refresh()
+}
+
+// wordBounds возвращает границы слова в поле. Многострочное поле не переносит строки,
+// поэтому строка слова - число переводов строки перед ним
+func (e *spellEntry) wordBounds(word spell.Word) (left, right, top, bottom float32) {
+	th := e.Theme()
+	textSize := th.Size(theme.SizeNameText)
+	pad := th.Size(theme.SizeNameInnerPadding)
+	runes := []rune(e.Text)
+	row, lineStart := 0, 0
+	for i := 0; i < word.Start; i++ {
+		if runes[i] == '\n' {
+			row, lineStart = row+1, i+1
+		}
+	}
+	left = pad + fyne.MeasureText(string(runes[lineStart:word.Start]), textSize, e.TextStyle).Width
+	right = pad + fyne.MeasureText(string(runes[lineStart:word.End]), textSize, e.TextStyle).Width
+	height := fyne.MeasureText("M", textSize, e.TextStyle).Height
+	top = pad + float32(row)*height
+	return left, right, top, top + height
+}
+
+// spellEntryRenderer рисует поле ввода и подчеркивания поверх него
+type spellEntryRenderer struct {
+	fyne.WidgetRenderer
+	entry *spellEntry
+}
+
+func (r *spellEntryRenderer) Layout(size fyne.Size) {
+	r.WidgetRenderer.Layout(size)
+	r.entry.underlines.Resize(size)
+	r.entry.layoutUnderlines()
+}
+
+func (r *spellEntryRenderer) Objects() []fyne.CanvasObject {
+	return append(slices.Clip(r.WidgetRenderer.Objects()), r.entry.underlines)
+}
+
+func (r *spellEntryRenderer) Refresh() {
+	r.WidgetRenderer.Refresh()
+	r.entry.checkSpelling()
+}
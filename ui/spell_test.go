@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"taskmanager/spell"
+)
+
+func TestLoadSpellChecker(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ru_TEST.dic"), []byte("2\nпозвонить\nврачу\n"), 0o644))
+
+	checker := loadSpellChecker([]string{"xx_XX", "ru_TEST"}, []string{dir}, []string{"Маше"})
+	assert.True(t, checker.Enabled())
+	assert.Empty(t, checker.Misspelled("Позвонить врачу и Маше"))
+	assert.False(t, loadSpellChecker([]string{"xx_XX"}, []string{dir}, nil).Enabled())
+}
+
+func TestSpellEntry(t *testing.T) {
+	prefs := test.NewTempApp(t).Preferences()
+	spelling := &spellChecker{prefs: prefs, checker: spell.NewChecker([]*spell.Dictionary{testSpellDictionary(t)}, nil)}
+
+	entry := newSpellEntry(spelling, false)
+	test.WidgetRenderer(entry)
+	entry.SetText("Позвонить врачю")
+	require.Len(t, entry.misspelled, 1)
+	assert.Equal(t, "врачю", entry.misspelled[0].Text)
+
+	menu := entry.suggestionMenu(entry.misspelled[0])
+	assert.Equal(t, "врачу", menu.Items[0].Label)
+	menu.Items[0].Action()
+	assert.Equal(t, "Позвонить врачу", entry.Text)
+	assert.Empty(t, entry.misspelled)
+
+	// Слово из личного словаря больше не подчеркивается и сохраняется в настройках
+	entry.SetText("Позвонить Маше")
+	require.Len(t, entry.misspelled, 1)
+	spelling.AddWord("Маше")
+	entry.checkSpelling()
+	assert.Empty(t, entry.misspelled)
+	assert.Equal(t, []string{"Маше"}, prefs.StringList(prefSpellWords))
+
+	// Без словарей поле работает как обычное
+	plain := newSpellEntry(nil, true)
+	plain.SetText("врачю")
+	assert.Empty(t, plain.misspelled)
+}
+
+func testSpellDictionary(t *testing.T) *spell.Dictionary {
+	d, err := spell.ParseDictionary([]byte("TRY оеаинтсрвлкмдпуя\n"), []byte("2\nпозвонить\nврачу\n"))
+	require.NoError(t, err)
+	return d
+}
@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/task"
+)
+
+// prefStartupSummary - показывать ли сводку на день при запуске
+const prefStartupSummary = "summary.on_startup"
+
+// dueFilter - быстрый фильтр списка задач по сроку
+type dueFilter string
+
+const (
+	dueFilterAll           dueFilter = ""
+	dueFilterToday         dueFilter = "today"          // невыполненные задачи на сегодня
+	dueFilterOverdue       dueFilter = "overdue"        // невыполненные задачи со сроком до сегодня
+	dueFilterDoneYesterday dueFilter = "done_yesterday" // выполненные вчера
+	dueFilterSomeday       dueFilter = "someday"        // невыполненные задачи без срока
+)
+
+// dueFilters - фильтры по сроку в порядке показа
+var dueFilters = []dueFilter{dueFilterAll, dueFilterToday, dueFilterOverdue, dueFilterDoneYesterday, dueFilterSomeday}
+
+// summaryFilters - разделы сводки на день
+var summaryFilters = []dueFilter{dueFilterToday, dueFilterOverdue, dueFilterDoneYesterday}
+
+// Title возвращает название фильтра для интерфейса
+func (f dueFilter) Title() string {
+	return map[dueFilter]string{
+		dueFilterAll:           "Любой срок",
+		dueFilterToday:         "На сегодня",
+		dueFilterOverdue:       "Просроченные",
+		dueFilterDoneYesterday: "Выполненные вчера",
+		dueFilterSomeday:       "Когда-нибудь",
+	}[f]
+}
+
+// dueFilterTitles возвращает названия фильтров по сроку для выбора
+func dueFilterTitles() []string {
+	titles := make([]string, len(dueFilters))
+	for i, f := range dueFilters {
+		titles[i] = f.Title()
+	}
+	return titles
+}
+
+// dueFilterByTitle возвращает фильтр по его названию
+func dueFilterByTitle(title string) dueFilter {
+	for _, f := range dueFilters {
+		if f.Title() == title {
+			return f
+		}
+	}
+	return dueFilterAll
+}
+
+// matches проверяет, подходит ли задача под фильтр в день now. Как и в повестке дня,
+// выполненной вчера считается задача, которая выполнена и последний раз менялась вчера
+func (f dueFilter) matches(t *task.Task, now time.Time) bool {
+	today := now.Format("2006-01-02")
+	due := t.DueDate.Format("2006-01-02")
+	switch f {
+	case dueFilterToday:
+		return !t.Completed && due == today
+	case dueFilterOverdue:
+		return !t.Completed && !t.DueDate.IsZero() && due < today
+	case dueFilterDoneYesterday:
+		return t.Completed && t.DoneAt().Format("2006-01-02") == now.AddDate(0, 0, -1).Format("2006-01-02")
+	case dueFilterSomeday:
+		return !t.Completed && t.DueDate.IsZero()
+	}
+	return true
+}
+
+// startupSummary - сводка на день: сколько задач на сегодня, просрочено и выполнено вчера
+type startupSummary map[dueFilter]int
+
+// buildStartupSummary считает задачи не из архива для сводки на день now
+func buildStartupSummary(tasks []*task.Task, now time.Time) startupSummary {
+	summary := make(startupSummary)
+	for _, t := range tasks {
+		if t.Archived {
+			continue
+		}
+		for _, f := range summaryFilters {
+			if f.matches(t, now) {
+				summary[f]++
+			}
+		}
+	}
+	return summary
+}
+
+// Empty сообщает, что в сводке нет ни одной задачи
+func (s startupSummary) Empty() bool {
+	return len(s) == 0
+}
+
+// showStartupSummary показывает сводку на день. Кнопка раздела показывает его задачи
+// в списке через jump
+func showStartupSummary(w fyne.Window, prefs fyne.Preferences, summary startupSummary, jump func(dueFilter)) {
+	var d dialog.Dialog
+	rows := container.NewVBox()
+	for _, f := range summaryFilters {
+		button := widget.NewButton("Показать", func() {
+			d.Hide()
+			jump(f)
+		})
+		if summary[f] == 0 {
+			button.Disable()
+		}
+		rows.Add(container.NewBorder(nil, nil, nil, button, widget.NewLabel(fmt.Sprintf("%s: %d", f.Title(), summary[f]))))
+	}
+	showCheck := widget.NewCheck("Показывать при запуске", func(show bool) {
+		prefs.SetBool(prefStartupSummary, show)
+	})
+	showCheck.SetChecked(prefs.BoolWithFallback(prefStartupSummary, true))
+	rows.Add(widget.NewSeparator())
+	rows.Add(showCheck)
+
+	d = dialog.NewCustom("План на день", "Закрыть", rows, w)
+	d.Resize(dialogSize(w, fyne.NewSize(360, 0)))
+	d.Show()
+}
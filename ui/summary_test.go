@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+func TestStartupSummary(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	today := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	yesterday := now.AddDate(0, 0, -1)
+	tasks := []*task.Task{
+		{DueDate: today},
+		{DueDate: today, Completed: true, UpdatedAt: now},
+		{DueDate: yesterday},
+		{DueDate: yesterday, Archived: true},
+		{DueDate: yesterday, Completed: true, UpdatedAt: yesterday},
+		{DueDate: today.AddDate(0, 0, 3)},
+		{},
+	}
+	summary := buildStartupSummary(tasks, now)
+	assert.Equal(t, startupSummary{dueFilterToday: 1, dueFilterOverdue: 1, dueFilterDoneYesterday: 1}, summary)
+	assert.False(t, summary.Empty())
+	assert.True(t, buildStartupSummary(nil, now).Empty())
+
+	assert.True(t, dueFilterAll.matches(tasks[5], now))
+	assert.Equal(t, dueFilterOverdue, dueFilterByTitle(dueFilterOverdue.Title()))
+
+	// Задача без срока не просрочена, она видна только в «Когда-нибудь»
+	assert.False(t, dueFilterOverdue.matches(tasks[6], now))
+	assert.True(t, dueFilterSomeday.matches(tasks[6], now))
+	assert.False(t, dueFilterSomeday.matches(tasks[5], now))
+}
+
+func TestTaskListModelDueFilter(t *testing.T) {
+	tm := newTestManager(t)
+	mustAddTask(t, tm, "Сегодня", "", 2, time.Now())
+	mustAddTask(t, tm, "Через неделю", "", 2, time.Now().AddDate(0, 0, 7))
+	mustAddTask(t, tm, "Когда-нибудь", "", 2, time.Time{})
+
+	model := newTaskListModel(tm, 0)
+	model.SetDueFilter(dueFilterToday)
+	if assert.Equal(t, 1, model.Len()) {
+		assert.Equal(t, "Сегодня", model.TaskAt(0).Title)
+	}
+	model.SetDueFilter(dueFilterSomeday)
+	if assert.Equal(t, 1, model.Len()) {
+		assert.Equal(t, "Когда-нибудь", model.TaskAt(0).Title)
+	}
+	model.SetDueFilter(dueFilterAll)
+	assert.Equal(t, 3, model.Len())
+}
@@ -0,0 +1,175 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/task"
+)
+
+// swipeThreshold - доля ширины строки, на которую ее нужно сдвинуть, чтобы сработал жест
+const swipeThreshold = 0.35
+
+// swipeAxis - направление, которое жест выбрал в начале движения
+type swipeAxis int
+
+const (
+	swipeUndecided swipeAxis = iota
+	swipeHorizontal
+	swipeVertical
+)
+
+// swipeRow - строка списка с жестами для сенсорных экранов ноутбуков, планшетов и телефонов:
+// сдвиг вправо выполняет задачу, влево - удаляет. Вертикальное движение строка не забирает
+// себе, а прокручивает им список через onScroll
+type swipeRow struct {
+	widget.BaseWidget
+	row *taskRow
+
+	onComplete func()
+	onDelete   func()
+	onScroll   func(dy float32)
+
+	axis   swipeAxis
+	offset float32
+}
+
+func newSwipeRow(row *taskRow) *swipeRow {
+	s := &swipeRow{row: row}
+	s.ExtendBaseWidget(s)
+	return s
+}
+
+func (s *swipeRow) Dragged(event *fyne.DragEvent) {
+	if s.axis == swipeUndecided {
+		s.axis = swipeVertical
+		if abs32(event.Dragged.DX) > abs32(event.Dragged.DY) {
+			s.axis = swipeHorizontal
+		}
+	}
+	if s.axis == swipeVertical {
+		if s.onScroll != nil {
+			s.onScroll(event.Dragged.DY)
+		}
+		return
+	}
+	s.offset += event.Dragged.DX
+	s.Refresh()
+}
+
+func (s *swipeRow) DragEnd() {
+	threshold := s.Size().Width * swipeThreshold
+	switch {
+	case s.axis != swipeHorizontal:
+	case s.offset > threshold && s.onComplete != nil:
+		s.onComplete()
+	case s.offset < -threshold && s.onDelete != nil:
+		s.onDelete()
+	}
+	s.axis, s.offset = swipeUndecided, 0
+	s.Refresh()
+}
+
+func (s *swipeRow) CreateRenderer() fyne.WidgetRenderer {
+	r := &swipeRowRenderer{
+		row:        s,
+		background: canvas.NewRectangle(nil),
+		complete:   widget.NewIcon(theme.ConfirmIcon()),
+		remove:     widget.NewIcon(theme.DeleteIcon()),
+	}
+	r.Refresh()
+	return r
+}
+
+// swipeRowRenderer рисует под сдвинутой строкой подложку с действием жеста
+type swipeRowRenderer struct {
+	row        *swipeRow
+	background *canvas.Rectangle
+	complete   *widget.Icon
+	remove     *widget.Icon
+}
+
+func (r *swipeRowRenderer) Layout(size fyne.Size) {
+	r.background.Resize(size)
+	iconSize := fyne.NewSquareSize(theme.IconInlineSize())
+	top := (size.Height - iconSize.Height) / 2
+	r.complete.Resize(iconSize)
+	r.complete.Move(fyne.NewPos(theme.Padding()*2, top))
+	r.remove.Resize(iconSize)
+	r.remove.Move(fyne.NewPos(size.Width-iconSize.Width-theme.Padding()*2, top))
+	r.row.row.Resize(size)
+	r.row.row.Move(fyne.NewPos(r.row.offset, 0))
+}
+
+func (r *swipeRowRenderer) MinSize() fyne.Size {
+	return r.row.row.MinSize()
+}
+
+func (r *swipeRowRenderer) Refresh() {
+	offset := r.row.offset
+	r.complete.Hidden, r.remove.Hidden = offset <= 0, offset >= 0
+	switch {
+	case offset > 0:
+		r.background.FillColor = theme.Color(theme.ColorNameSuccess)
+	case offset < 0:
+		r.background.FillColor = theme.Color(theme.ColorNameError)
+	default:
+		r.background.FillColor = theme.Color(theme.ColorNameBackground)
+	}
+	r.background.Refresh()
+	r.Layout(r.row.Size())
+	r.row.row.Refresh()
+}
+
+func (r *swipeRowRenderer) Objects() []fyne.CanvasObject {
+	return []fyne.CanvasObject{r.background, r.complete, r.remove, r.row.row}
+}
+
+func (r *swipeRowRenderer) Destroy() {}
+
+func abs32(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// undoTimeout - сколько висит уведомление, из которого можно отменить действие
+const undoTimeout = 10 * time.Second
+
+// showUndoDelete показывает в полосе уведомлений, что задача удалена, с кнопкой отмены
+func showUndoDelete(w fyne.Window, notices *fyne.Container, tm *task.TaskManager, deleted *task.DeletedTask) {
+	showUndoNotice(w, notices, fmt.Sprintf("Задача «%s» удалена", deleted.Task.Title), func() error {
+		return tm.RestoreTask(deleted)
+	})
+}
+
+// showUndoNotice показывает в полосе уведомлений сообщение text с кнопкой отмены.
+// Видно только последнее действие; уведомление исчезает через undoTimeout
+func showUndoNotice(w fyne.Window, notices *fyne.Container, text string, undo func() error) {
+	notices.RemoveAll()
+	var notice *fyne.Container
+	undoButton := widget.NewButtonWithIcon("Отменить", theme.ContentUndoIcon(), func() {
+		notices.Remove(notice)
+		if err := undo(); err != nil {
+			dialog.ShowError(err, w)
+		}
+	})
+	closeButton := newCloseButton(func() {
+		notices.Remove(notice)
+	})
+	label := widget.NewLabel(text)
+	label.Truncation = fyne.TextTruncateEllipsis
+	notice = container.NewBorder(nil, nil, nil, container.NewHBox(undoButton, closeButton), label)
+	notices.Add(notice)
+	time.AfterFunc(undoTimeout, func() {
+		fyne.Do(func() { notices.Remove(notice) })
+	})
+}
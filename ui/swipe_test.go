@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwipeRow(t *testing.T) {
+	test.NewTempApp(t)
+
+	var completed, deleted int
+	var scrolled float32
+	s := newSwipeRow(newTaskRow(densityComfortable, false))
+	s.onComplete = func() { completed++ }
+	s.onDelete = func() { deleted++ }
+	s.onScroll = func(dy float32) { scrolled += dy }
+	test.WidgetRenderer(s)
+	s.Resize(fyne.NewSize(300, 60))
+
+	drag := func(dx, dy float32) {
+		s.Dragged(&fyne.DragEvent{Dragged: fyne.Delta{DX: dx, DY: dy}})
+	}
+
+	// Короткий сдвиг ничего не делает, строка возвращается на место
+	drag(40, 0)
+	s.DragEnd()
+	assert.Zero(t, completed)
+	assert.Zero(t, s.offset)
+
+	drag(80, 2)
+	drag(80, 0)
+	assert.Equal(t, float32(160), s.row.Position().X)
+	s.DragEnd()
+	assert.Equal(t, 1, completed)
+	assert.Zero(t, s.row.Position().X)
+
+	drag(-200, 0)
+	s.DragEnd()
+	assert.Equal(t, 1, deleted)
+
+	// Вертикальный жест прокручивает список, даже если потом уходит вбок
+	drag(2, 30)
+	drag(200, 0)
+	s.DragEnd()
+	assert.Equal(t, float32(30), scrolled)
+	assert.Equal(t, 1, completed)
+}
+
+func TestUndoDelete(t *testing.T) {
+	test.NewTempApp(t)
+	tm := newTestManager(t)
+	w := test.NewWindow(nil)
+	defer w.Close()
+
+	added := mustAddTask(t, tm, "Позвонить", "", 2, time.Now())
+	deleted, err := tm.DeleteTaskForUndo(added.ID)
+	require.NoError(t, err)
+
+	notices := container.NewVBox()
+	showUndoDelete(w, notices, tm, deleted)
+	require.Len(t, notices.Objects, 1)
+	notice := notices.Objects[0].(*fyne.Container)
+	assert.Equal(t, "Задача «Позвонить» удалена", notice.Objects[0].(*widget.Label).Text)
+
+	test.Tap(notice.Objects[1].(*fyne.Container).Objects[0].(*widget.Button))
+	assert.Empty(t, notices.Objects)
+	assert.NotNil(t, tm.GetTask(added.ID))
+}
@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/storage"
+	"taskmanager/task"
+	"taskmanager/tasksync"
+)
+
+// syncSession синхронизирует задачи профиля с сервером и помнит состояние между синхронизациями
+type syncSession struct {
+	prefs     fyne.Preferences
+	tm        *task.TaskManager
+	keys      *storage.Keyring
+	statePath string
+	state     *tasksync.State // nil - прочитать из файла при следующей синхронизации
+	running   bool
+
+	// status показывает время последней синхронизации
+	status *widget.Label
+}
+
+func newSyncSession(prefs fyne.Preferences, tm *task.TaskManager, tasksFile string, keys *storage.Keyring) *syncSession {
+	return &syncSession{
+		prefs:     prefs,
+		tm:        tm,
+		keys:      keys,
+		statePath: tasksync.StatePath(tasksFile),
+		status:    widget.NewLabel(""),
+	}
+}
+
+// Run отправляет изменения на сервер и получает изменения других устройств.
+// Пока идет обмен, показывается индикатор, затем - итог и конфликты
+func (s *syncSession) Run(w fyne.Window) {
+	url := strings.TrimSpace(s.prefs.String(prefSyncURL))
+	if url == "" {
+		dialog.ShowInformation("Синхронизация", "Укажите адрес сервера синхронизации в настройках", w)
+		return
+	}
+	if s.running {
+		return
+	}
+	// Задачи уходят на сервер только зашифрованными
+	if s.keys.Empty() {
+		showKeySetupDialog(w, s.prefs, s.keys, func() { s.Run(w) })
+		return
+	}
+	if s.state == nil {
+		state, err := tasksync.LoadState(s.statePath)
+		if err != nil {
+			slog.Error("failed to read sync state", "file", s.statePath, "err", err)
+			dialog.ShowError(err, w)
+			return
+		}
+		s.state = state
+	}
+	s.state.UseServer(url)
+	s.state.UseKey(s.keys.CurrentID())
+
+	req := tasksync.Prepare(s.tm, s.state, time.Now())
+	client := tasksync.NewClient(url, s.prefs.String(prefSyncKey))
+	client.Keys = s.keys
+	ctx, cancel := context.WithCancel(context.Background())
+
+	progress := dialog.NewCustomWithoutButtons("Синхронизация", container.NewVBox(
+		widget.NewLabel(fmt.Sprintf("Обмен изменениями с %s (отправляется: %d)", url, len(req.Changes))),
+		widget.NewProgressBarInfinite(),
+	), w)
+	progress.SetButtons([]fyne.CanvasObject{widget.NewButton("Отмена", cancel)})
+	progress.Show()
+	s.running = true
+
+	go func() {
+		resp, err := client.Exchange(ctx, req)
+		fyne.Do(func() {
+			cancel()
+			progress.Hide()
+			s.running = false
+			if errors.Is(err, storage.ErrUnknownKey) {
+				// Другое устройство шифрует задачи ключом, которого здесь нет
+				slog.Warn("sync data is encrypted with an unknown key", "server", url)
+				showImportPhraseDialog(w, s.prefs, s.keys, func() { s.Run(w) }, func() {})
+				return
+			}
+			if err != nil {
+				if !errors.Is(err, context.Canceled) {
+					slog.Error("sync failed", "server", url, "err", err)
+					dialog.ShowError(err, w)
+				}
+				return
+			}
+
+			result := tasksync.Apply(s.tm, s.state, req, resp)
+			slog.Info("sync finished", "server", url, "sent", result.Sent, "received", result.Received,
+				"restored", result.Restored, "conflicts", len(result.Conflicts))
+			s.finish(w, result)
+		})
+	}()
+}
+
+// finish сохраняет задачи, затем состояние синхронизации, и показывает итог.
+// Пока задачи не сохранены, состояние в памяти не используется: иначе задачи,
+// полученные с сервера, но потерянные при загрузке файла, сочлись бы удаленными
+func (s *syncSession) finish(w fyne.Window, result tasksync.Result) {
+	state := s.state
+	s.state = nil
+
+	saveTasks(w, s.tm, func() {
+		if err := state.Save(s.statePath); err != nil {
+			slog.Error("failed to save sync state", "file", s.statePath, "err", err)
+			dialog.ShowError(err, w)
+			return
+		}
+		s.state = state
+		s.status.SetText("Синхронизировано в " + time.Now().Format("15:04"))
+
+		message := fmt.Sprintf("Отправлено изменений: %d\nПолучено: %d", result.Sent, result.Received)
+		if result.Restored > 0 {
+			message += fmt.Sprintf("\nВосстановлено задач, удаленных здесь, но измененных на другом устройстве: %d", result.Restored)
+		}
+		if len(result.Conflicts) > 0 {
+			message += fmt.Sprintf("\nИзменены и здесь, и на другом устройстве: %d", len(result.Conflicts))
+		}
+
+		info := dialog.NewInformation("Синхронизация завершена", message, w)
+		if len(result.Conflicts) > 0 {
+			// Выбранные версии уйдут на сервер при следующей синхронизации
+			info.SetOnClosed(func() {
+				showMergeResult(w, s.tm, task.MergeResult{Conflicts: result.Conflicts}, func() {
+					saveTasks(w, s.tm, func() {})
+				})
+			})
+		}
+		info.Show()
+	})
+}
@@ -0,0 +1,196 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/task"
+)
+
+// prefHiddenColumns - ключ настроек со списком скрытых колонок таблицы
+const prefHiddenColumns = "table.hidden_columns"
+
+// tableColumn описывает колонку табличного вида
+type tableColumn struct {
+	key   string
+	title string
+	width float32
+	sort  task.SortMode
+	value func(task *task.Task) string
+}
+
+// tableColumns - все колонки таблицы в порядке отображения
+var tableColumns = []tableColumn{
+	{key: "id", title: "ID", width: 60, sort: task.SortByID, value: func(t *task.Task) string {
+		return strconv.Itoa(t.ID)
+	}},
+	{key: "title", title: "Название", width: 260, sort: task.SortByTitle, value: func(t *task.Task) string {
+		return t.IconTitle()
+	}},
+	{key: "priority", title: "Приоритет", width: 100, sort: task.SortByPriority, value: func(t *task.Task) string {
+		return task.PriorityText(t.Priority)
+	}},
+	{key: "due", title: "Срок", width: 110, sort: task.SortByDueDate, value: func(t *task.Task) string {
+		return formatDueDate(t)
+	}},
+	{key: "tags", title: "Метки", width: 160, sort: task.SortByTags, value: func(t *task.Task) string {
+		return strings.Join(t.Tags, ", ")
+	}},
+	{key: "assignee", title: "Исполнитель", width: 120, sort: task.SortByAssignee, value: func(t *task.Task) string {
+		return t.Assignee
+	}},
+	{key: "context", title: "Контекст", width: 110, sort: task.SortByContext, value: func(t *task.Task) string {
+		return t.Context
+	}},
+	{key: "progress", title: "Готовность", width: 100, sort: task.SortByProgress, value: func(t *task.Task) string {
+		return fmt.Sprintf("%d%%", t.ProgressPercent())
+	}},
+	{key: "status", title: "Статус", width: 100, sort: task.SortByStatus, value: func(t *task.Task) string {
+		if t.Completed {
+			return "выполнена"
+		}
+		return "активна"
+	}},
+	{key: "updated", title: "Изменена", width: 140, sort: task.SortByUpdated, value: func(t *task.Task) string {
+		return t.UpdatedAt.Format("2006-01-02 15:04")
+	}},
+}
+
+// visibleColumns возвращает колонки, которые не скрыты в настройках
+func visibleColumns(hidden []string) []tableColumn {
+	var columns []tableColumn
+	for _, column := range tableColumns {
+		if !containsString(hidden, column.key) {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// taskTable - таблица задач текущего вида с сортировкой по заголовкам колонок
+// и открытием задачи по двойному щелчку
+type taskTable struct {
+	widget.Table
+
+	model   *taskListModel
+	columns []tableColumn
+
+	// OnSortChanged вызывается после смены сортировки щелчком по заголовку
+	OnSortChanged func()
+	// OnDoubleTapped вызывается при двойном щелчке по выбранной строке
+	OnDoubleTapped func()
+}
+
+// newTaskTable создает таблицу поверх модели представления
+func newTaskTable(model *taskListModel, columns []tableColumn) *taskTable {
+	t := &taskTable{model: model}
+	t.Length = func() (int, int) {
+		return model.Len(), len(t.columns)
+	}
+	t.CreateCell = func() fyne.CanvasObject {
+		label := widget.NewLabel("")
+		label.Truncation = fyne.TextTruncateEllipsis
+		return label
+	}
+	t.UpdateCell = func(id widget.TableCellID, cell fyne.CanvasObject) {
+		task := model.TaskAt(id.Row)
+		if task == nil || id.Col >= len(t.columns) {
+			return
+		}
+		cell.(*widget.Label).SetText(t.columns[id.Col].value(task))
+	}
+
+	t.ShowHeaderRow = true
+	t.CreateHeader = func() fyne.CanvasObject {
+		return widget.NewButton("", nil)
+	}
+	t.UpdateHeader = func(id widget.TableCellID, header fyne.CanvasObject) {
+		if id.Col < 0 || id.Col >= len(t.columns) {
+			return
+		}
+		column := t.columns[id.Col]
+		button := header.(*widget.Button)
+
+		title := column.title
+		if model.sort == column.sort {
+			if model.reverse {
+				title += " ▲"
+			} else {
+				title += " ▼"
+			}
+		}
+		button.SetText(title)
+		button.OnTapped = func() {
+			model.ToggleSort(column.sort)
+			t.Refresh()
+			if t.OnSortChanged != nil {
+				t.OnSortChanged()
+			}
+		}
+	}
+
+	t.ExtendBaseWidget(t)
+	t.SetColumns(columns)
+	return t
+}
+
+// SetColumns задает набор отображаемых колонок
+func (t *taskTable) SetColumns(columns []tableColumn) {
+	t.columns = columns
+	for i, column := range columns {
+		t.SetColumnWidth(i, column.width)
+	}
+	t.Refresh()
+}
+
+// DoubleTapped открывает выбранную задачу
+func (t *taskTable) DoubleTapped(*fyne.PointEvent) {
+	if t.OnDoubleTapped != nil {
+		t.OnDoubleTapped()
+	}
+}
+
+// showColumnsDialog позволяет скрыть или показать колонки таблицы
+func showColumnsDialog(w fyne.Window, prefs fyne.Preferences, onChange func(columns []tableColumn)) {
+	hidden := prefs.StringList(prefHiddenColumns)
+
+	var checks []fyne.CanvasObject
+	for _, column := range tableColumns {
+		key := column.key
+		check := widget.NewCheck(column.title, nil)
+		check.SetChecked(!containsString(hidden, key))
+		check.OnChanged = func(checked bool) {
+			var updated []string
+			for _, h := range hidden {
+				if h != key {
+					updated = append(updated, h)
+				}
+			}
+			if !checked {
+				updated = append(updated, key)
+			}
+			hidden = updated
+			prefs.SetStringList(prefHiddenColumns, hidden)
+			onChange(visibleColumns(hidden))
+		}
+		checks = append(checks, check)
+	}
+
+	dialog.ShowCustom("Колонки таблицы", "Закрыть", container.NewVBox(checks...), w)
+}
+
+// containsString проверяет наличие строки в списке
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
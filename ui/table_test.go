@@ -0,0 +1,19 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVisibleColumns(t *testing.T) {
+	// Без скрытых колонок показываются все
+	assert.Equal(t, len(tableColumns), len(visibleColumns(nil)))
+
+	columns := visibleColumns([]string{"tags", "id"})
+	assert.Equal(t, len(tableColumns)-2, len(columns))
+	assert.Equal(t, "title", columns[0].key)
+	for _, column := range columns {
+		assert.NotEqual(t, "tags", column.key)
+	}
+}
@@ -0,0 +1,476 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/holidays"
+	"taskmanager/task"
+)
+
+// Названия постоянных вкладок главного окна
+const (
+	tabList     = "Список"
+	tabMyDay    = "Мой день"
+	tabGoals    = "Цели"
+	tabCalendar = "Календарь"
+	tabWeek     = "Неделя"
+	tabTimeline = "Таймлайн"
+	tabBoard    = "Доска"
+	tabStats    = "Статистика"
+)
+
+// projectTabPrefix - начало названия вкладки проекта: проект - это метка задач
+const projectTabPrefix = "#"
+
+// monthNames - названия месяцев для заголовка календаря
+var monthNames = []string{"Январь", "Февраль", "Март", "Апрель", "Май", "Июнь",
+	"Июль", "Август", "Сентябрь", "Октябрь", "Ноябрь", "Декабрь"}
+
+// isOverdue проверяет, что день срока прошел, а задача не выполнена; now - в поясе сроков
+func isOverdue(t *task.Task, now time.Time) bool {
+	return t.IsOverdue(now)
+}
+
+// visibleTasks возвращает задачи не из архива
+func visibleTasks(tm *task.TaskManager) []*task.Task {
+	var tasks []*task.Task
+	for _, t := range tm.Tasks() {
+		if !t.Archived {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks
+}
+
+// calendarDays возвращает дни сетки месяца с понедельника первой недели по воскресенье
+// последней: дни соседних месяцев заполняют неполные недели
+func calendarDays(year int, month time.Month) []time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	offset := (int(first.Weekday()) + 6) % 7 // понедельник - 0
+	start := first.AddDate(0, 0, -offset)
+	last := first.AddDate(0, 1, -1)
+	end := last.AddDate(0, 0, 6-(int(last.Weekday())+6)%7)
+
+	var days []time.Time
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	return days
+}
+
+// tasksByDay группирует задачи по дню срока. Задач без срока в календаре нет
+func tasksByDay(tasks []*task.Task) map[string][]*task.Task {
+	days := make(map[string][]*task.Task)
+	for _, t := range tasks {
+		if t.DueDate.IsZero() {
+			continue
+		}
+		key := t.DueDate.Format("2006-01-02")
+		days[key] = append(days[key], t)
+	}
+	return days
+}
+
+// boardColumn - колонка доски задач
+type boardColumn struct {
+	Title string
+	Tasks []*task.Task
+}
+
+// boardColumns раскладывает задачи по колонкам доски: просроченные, запланированные и выполненные.
+// В колонке сначала задачи с более высоким приоритетом, затем с более ранним сроком, задачи без срока - в конце
+func boardColumns(tasks []*task.Task, now time.Time) []boardColumn {
+	columns := []boardColumn{{Title: "Просрочено"}, {Title: "Запланировано"}, {Title: "Выполнено"}}
+	for _, t := range tasks {
+		switch {
+		case t.Completed:
+			columns[2].Tasks = append(columns[2].Tasks, t)
+		case isOverdue(t, now):
+			columns[0].Tasks = append(columns[0].Tasks, t)
+		default:
+			columns[1].Tasks = append(columns[1].Tasks, t)
+		}
+	}
+	for i := range columns {
+		columns[i].Tasks = task.SortTasks(task.SortTasks(columns[i].Tasks, task.SortByDueDate, false), task.SortByPriority, false)
+	}
+	return columns
+}
+
+// taskStats - сводка по задачам для вкладки статистики
+type taskStats struct {
+	Total, Active, Completed, Overdue, Archived int
+	ByPriority                                  map[int]int // невыполненные задачи по приоритету
+	ByAssignee                                  map[string]int
+	TimeSpent                                   time.Duration
+}
+
+// computeStats считает задачи по статусам, приоритетам и исполнителям
+func computeStats(tasks []*task.Task, now time.Time) taskStats {
+	stats := taskStats{ByPriority: make(map[int]int), ByAssignee: make(map[string]int)}
+	for _, t := range tasks {
+		stats.Total++
+		stats.TimeSpent += t.TimeSpent
+		if t.Archived {
+			stats.Archived++
+			continue
+		}
+		if t.Completed {
+			stats.Completed++
+		} else {
+			stats.Active++
+			stats.ByPriority[t.Priority]++
+			if isOverdue(t, now) {
+				stats.Overdue++
+			}
+		}
+		if t.Assignee != "" {
+			stats.ByAssignee[t.Assignee]++
+		}
+	}
+	return stats
+}
+
+// String показывает сводку текстом
+func (s taskStats) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Всего задач: %d\n", s.Total)
+	fmt.Fprintf(&b, "В работе: %d, из них просрочено: %d\n", s.Active, s.Overdue)
+	fmt.Fprintf(&b, "Выполнено: %d\n", s.Completed)
+	if done := s.Active + s.Completed; done > 0 {
+		fmt.Fprintf(&b, "Доля выполненных: %d%%\n", s.Completed*100/done)
+	}
+	fmt.Fprintf(&b, "В архиве: %d\n", s.Archived)
+	fmt.Fprintf(&b, "Затрачено времени: %s\n", formatTimeSpent(s.TimeSpent))
+
+	b.WriteString("\nВ работе по приоритету:\n")
+	for _, priority := range []int{3, 2, 1} {
+		fmt.Fprintf(&b, "  %s: %d\n", task.PriorityText(priority), s.ByPriority[priority])
+	}
+	if len(s.ByAssignee) > 0 {
+		b.WriteString("\nПо исполнителям:\n")
+		names := make([]string, 0, len(s.ByAssignee))
+		for name := range s.ByAssignee {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(&b, "  %s: %d\n", name, s.ByAssignee[name])
+		}
+	}
+	return b.String()
+}
+
+// projectTags возвращает метки задач - возможные проекты - по алфавиту
+func projectTags(tasks []*task.Task) []string {
+	var tags []string
+	for _, t := range tasks {
+		for _, tag := range t.Tags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// taskButton - карточка задачи с флажком приоритета: нажатие открывает задачу.
+// Выполненные задачи показываются без подложки
+func taskButton(t *task.Task, openTask func(id int)) *widget.Button {
+	id := t.ID
+	button := widget.NewButtonWithIcon(t.IconTitle(), priorityIcon(t.Priority), func() { openTask(id) })
+	button.Alignment = widget.ButtonAlignLeading
+	if t.Completed {
+		button.Importance = widget.LowImportance
+	}
+	return button
+}
+
+// newCalendarView создает вкладку календаря: задачи по дням срока в сетке месяца с номерами
+// недель ISO и загрузка дня по оценкам задач. Задачу можно перетащить в другой день,
+// а двойное нажатие по свободному месту дня добавляет задачу на этот день.
+// refresh перестраивает сетку после изменения задач
+func newCalendarView(w fyne.Window, tm *task.TaskManager, notices *fyne.Container, holidayCalendar func() *holidays.Calendar, capacity func() int, openTask func(id int), addTask func(day time.Time)) (view fyne.CanvasObject, refresh func()) {
+	now := time.Now()
+	year, month := now.Year(), now.Month()
+	title := widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	grid := container.NewGridWithColumns(8)
+	// Клетки дней отдельно от заголовков и номеров недель, чтобы найти день под карточкой
+	var dayCells []fyne.CanvasObject
+	var cellDays []time.Time
+
+	drop := func(id int, pos fyne.Position) {
+		if i := dropIndex(dayCells, pos); i >= 0 {
+			moveTask(w, notices, tm, id, cellDays[i])
+		}
+	}
+
+	refresh = func() {
+		title.SetText(fmt.Sprintf("%s %d", monthNames[month-1], year))
+		tasks := visibleTasks(tm)
+		days := tasksByDay(tasks)
+		calendar := holidayCalendar()
+		var cells []fyne.CanvasObject
+		dayCells, cellDays = nil, nil
+		for _, name := range append([]string{"Нед."}, weekdayNames...) {
+			cells = append(cells, widget.NewLabelWithStyle(name, fyne.TextAlignCenter, fyne.TextStyle{Bold: true}))
+		}
+		for _, day := range calendarDays(year, month) {
+			if day.Weekday() == time.Monday {
+				_, week := day.ISOWeek()
+				weekLabel := widget.NewLabelWithStyle(fmt.Sprint(week), fyne.TextAlignCenter, fyne.TextStyle{})
+				weekLabel.Importance = widget.LowImportance
+				cells = append(cells, weekLabel)
+			}
+			label := widget.NewLabel(fmt.Sprint(day.Day()))
+			if day.Month() != month {
+				label.Importance = widget.LowImportance
+			}
+			cell := container.NewVBox(label)
+			if minutes := dayWorkload(tasks, day.Format("2006-01-02")); minutes > 0 {
+				cell.Add(newWorkloadBar(minutes, capacity()))
+			}
+			for _, t := range days[day.Format("2006-01-02")] {
+				card := newDayCard(t, openTask, drop)
+				card.Icon = priorityIcon(t.Priority)
+				card.Importance = widget.MediumImportance
+				if t.Completed {
+					card.Importance = widget.LowImportance
+				}
+				cell.Add(card)
+			}
+			// Праздник подписан рядом с числом, а клетка закрашена
+			var content fyne.CanvasObject = cell
+			if name, ok := calendar.Holiday(day); ok {
+				label.SetText(fmt.Sprintf("%d %s", day.Day(), name))
+				label.Truncation = fyne.TextTruncateEllipsis
+				content = container.NewStack(canvas.NewRectangle(theme.Color(theme.ColorNameInputBackground)), cell)
+			}
+			dayCell := newDayCell(content, func() { addTask(day) })
+			dayCells = append(dayCells, dayCell)
+			cellDays = append(cellDays, day)
+			cells = append(cells, dayCell)
+		}
+		grid.Objects = cells
+		grid.Refresh()
+	}
+	shift := func(months int) {
+		first := time.Date(year, month+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+		year, month = first.Year(), first.Month()
+		refresh()
+	}
+	backButton, nextButton := newStepButtons(func() { shift(-1) }, func() { shift(1) })
+	header := newReadingBorder(nil, nil, backButton, nextButton, title)
+	refresh()
+	return container.NewBorder(header, nil, nil, nil, container.NewVScroll(grid)), refresh
+}
+
+// newBoardView создает вкладку доски: колонки просроченных, запланированных и выполненных задач
+func newBoardView(tm *task.TaskManager, openTask func(id int)) (view fyne.CanvasObject, refresh func()) {
+	board := container.NewGridWithColumns(3)
+	refresh = func() {
+		var columns []fyne.CanvasObject
+		for _, column := range boardColumns(visibleTasks(tm), tm.DueZone().Now()) {
+			cards := container.NewVBox()
+			for _, t := range column.Tasks {
+				cards.Add(taskButton(t, openTask))
+			}
+			header := widget.NewLabelWithStyle(fmt.Sprintf("%s (%d)", column.Title, len(column.Tasks)), fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+			columns = append(columns, container.NewBorder(header, nil, nil, nil, container.NewVScroll(cards)))
+		}
+		board.Objects = columns
+		board.Refresh()
+	}
+	refresh()
+	return board, refresh
+}
+
+// newStatsView создает вкладку статистики: сводка, итоги режима фокусировки, карта выполнений
+// за год и диаграмма сгорания по проектам
+func newStatsView(tm *task.TaskManager, prefs fyne.Preferences) (view fyne.CanvasObject, refresh func()) {
+	label := widget.NewLabel("")
+	activityView, refreshActivity := newActivityView(tm)
+	burndownView, refreshBurndown := newBurndownView(tm)
+	refresh = func() {
+		now := tm.DueZone().Now()
+		label.SetText(computeStats(tm.Tasks(), now).String() + "\n" + formatFocusStats(loadFocusSessions(prefs), now))
+		refreshActivity()
+		refreshBurndown()
+	}
+	refresh()
+	return container.NewBorder(nil, container.NewVBox(activityView, burndownView), nil, nil, container.NewVScroll(label)), refresh
+}
+
+// newProjectView создает вкладку проекта: задачи с меткой tag
+func newProjectView(tm *task.TaskManager, tag string, openTask func(id int)) (view fyne.CanvasObject, refresh func()) {
+	var tasks []*task.Task
+	list := widget.NewList(
+		func() int { return len(tasks) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(i widget.ListItemID, item fyne.CanvasObject) {
+			item.(*widget.Label).SetText(formatTaskRow(tasks[i], tm.DueZone().Now()))
+		},
+	)
+	list.OnSelected = func(i widget.ListItemID) {
+		list.UnselectAll()
+		openTask(tasks[i].ID)
+	}
+	summary := widget.NewLabel("")
+	refresh = func() {
+		tasks = nil
+		active := 0
+		for _, t := range visibleTasks(tm) {
+			if slices.Contains(t.Tags, tag) {
+				tasks = append(tasks, t)
+				if !t.Completed {
+					active++
+				}
+			}
+		}
+		summary.SetText(fmt.Sprintf("Задач: %d, в работе: %d", len(tasks), active))
+		list.Refresh()
+	}
+	refresh()
+	return container.NewBorder(summary, nil, nil, nil, list), refresh
+}
+
+// mainTabs - вкладки главного окна: список, «Мой день», цели, календарь, неделя, таймлайн, доска,
+// статистика и проекты
+type mainTabs struct {
+	*container.AppTabs
+	tm        *task.TaskManager
+	openTask  func(id int)
+	refreshes map[*container.TabItem]func()
+}
+
+func newMainTabs(w fyne.Window, prefs fyne.Preferences, tm *task.TaskManager, list fyne.CanvasObject, notices *fyne.Container, capacity func() int, openTask func(id int), addTask func(day time.Time)) *mainTabs {
+	tabs := &mainTabs{AppTabs: container.NewAppTabs(), tm: tm, openTask: openTask, refreshes: make(map[*container.TabItem]func())}
+	tabs.Append(container.NewTabItem(tabList, list))
+	myDay, refreshMyDay := newMyDayView(tm, func(err error) {
+		if err != nil {
+			dialog.ShowError(err, w)
+		}
+	}, openTask)
+	tabs.add(tabMyDay, myDay, refreshMyDay)
+	goalsView, refreshGoals := newGoalsView(w, prefs, tm, openTask)
+	tabs.add(tabGoals, goalsView, refreshGoals)
+	holidayCalendar := func() *holidays.Calendar { return loadHolidays(prefs, time.Now()) }
+	calendar, refreshCalendar := newCalendarView(w, tm, notices, holidayCalendar, capacity, openTask, addTask)
+	tabs.add(tabCalendar, calendar, refreshCalendar)
+	week, refreshWeek := newWeekView(w, tm, notices, capacity, openTask, addTask)
+	tabs.add(tabWeek, week, refreshWeek)
+	timeline, refreshTimeline := newTimelineView(w, tm, openTask)
+	tabs.add(tabTimeline, timeline, refreshTimeline)
+	board, refreshBoard := newBoardView(tm, openTask)
+	tabs.add(tabBoard, board, refreshBoard)
+	stats, refreshStats := newStatsView(tm, prefs)
+	tabs.add(tabStats, stats, refreshStats)
+
+	// Вкладка обновляется, когда ее открывают, а открытая - при изменении задач
+	tabs.OnSelected = func(item *container.TabItem) {
+		if refresh := tabs.refreshes[item]; refresh != nil {
+			refresh()
+		}
+	}
+	tm.Subscribe(func(task.Event) { tabs.RefreshViews() })
+	return tabs
+}
+
+// RefreshViews обновляет открытую вкладку, например после изменения настроек
+func (t *mainTabs) RefreshViews() {
+	if refresh := t.refreshes[t.Selected()]; refresh != nil {
+		refresh()
+	}
+}
+
+func (t *mainTabs) add(title string, view fyne.CanvasObject, refresh func()) *container.TabItem {
+	item := container.NewTabItem(title, view)
+	t.refreshes[item] = refresh
+	t.Append(item)
+	return item
+}
+
+// Projects возвращает метки открытых вкладок проектов
+func (t *mainTabs) Projects() []string {
+	var tags []string
+	for _, item := range t.Items {
+		if tag, ok := strings.CutPrefix(item.Text, projectTabPrefix); ok {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// OpenProject открывает вкладку проекта или переходит к уже открытой
+func (t *mainTabs) OpenProject(tag string) {
+	for _, item := range t.Items {
+		if item.Text == projectTabPrefix+tag {
+			t.Select(item)
+			return
+		}
+	}
+	view, refresh := newProjectView(t.tm, tag, t.openTask)
+	t.Select(t.add(projectTabPrefix+tag, view, refresh))
+}
+
+// CloseProject закрывает вкладку проекта
+func (t *mainTabs) CloseProject(tag string) {
+	for _, item := range t.Items {
+		if item.Text == projectTabPrefix+tag {
+			delete(t.refreshes, item)
+			t.Remove(item)
+			return
+		}
+	}
+}
+
+// SelectTitle переходит на вкладку с названием title, если она есть
+func (t *mainTabs) SelectTitle(title string) {
+	for _, item := range t.Items {
+		if item.Text == title {
+			t.Select(item)
+			return
+		}
+	}
+}
+
+// showProjectTabsDialog предлагает открыть вкладку проекта по метке или закрыть открытую
+func showProjectTabsDialog(w fyne.Window, tabs *mainTabs) {
+	tags := projectTags(visibleTasks(tabs.tm))
+	if len(tags) == 0 {
+		dialog.ShowInformation("Проекты", "Проекты - это метки задач. Добавьте задачам метки, чтобы открыть их во вкладках", w)
+		return
+	}
+	open := tabs.Projects()
+	checks := widget.NewCheckGroup(tags, nil)
+	checks.SetSelected(open)
+	dialog.ShowForm("Вкладки проектов", "Применить", "Отмена", []*widget.FormItem{
+		{Text: "Метки", Widget: checks, HintText: "Отмеченные метки показываются во вкладках"},
+	}, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		for _, tag := range open {
+			if !slices.Contains(checks.Selected, tag) {
+				tabs.CloseProject(tag)
+			}
+		}
+		for _, tag := range checks.Selected {
+			if !slices.Contains(open, tag) {
+				tabs.OpenProject(tag)
+			}
+		}
+	}, w)
+}
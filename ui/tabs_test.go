@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+func TestCalendarDays(t *testing.T) {
+	// Октябрь 2026 начинается в четверг и заканчивается в субботу
+	days := calendarDays(2026, time.October)
+	assert.Len(t, days, 35)
+	assert.Equal(t, "2026-09-28", days[0].Format("2006-01-02"))
+	assert.Equal(t, time.Monday, days[0].Weekday())
+	assert.Equal(t, "2026-11-01", days[len(days)-1].Format("2006-01-02"))
+	assert.Equal(t, time.Sunday, days[len(days)-1].Weekday())
+
+	// Февраль 2027 - ровно четыре недели с понедельника
+	assert.Len(t, calendarDays(2027, time.February), 28)
+}
+
+func TestBoardColumns(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tasks := []*task.Task{
+		{ID: 1, Priority: 1, DueDate: now.AddDate(0, 0, 2)},
+		{ID: 2, Priority: 3, DueDate: now.AddDate(0, 0, 5)},
+		{ID: 3, Priority: 2, DueDate: now.AddDate(0, 0, -1)},
+		{ID: 4, Priority: 2, DueDate: now.AddDate(0, 0, -1), Completed: true},
+		{ID: 5, Priority: 1},
+	}
+	columns := boardColumns(tasks, now)
+	ids := func(column boardColumn) []int {
+		var ids []int
+		for _, t := range column.Tasks {
+			ids = append(ids, t.ID)
+		}
+		return ids
+	}
+	assert.Equal(t, []int{3}, ids(columns[0]))
+	// Задача без срока запланирована и стоит после задач того же приоритета со сроком
+	assert.Equal(t, []int{2, 1, 5}, ids(columns[1]))
+	assert.Equal(t, []int{4}, ids(columns[2]))
+}
+
+func TestTasksByDay(t *testing.T) {
+	due := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	days := tasksByDay([]*task.Task{{ID: 1, DueDate: due}, {ID: 2, DueDate: due}, {ID: 3}})
+	assert.Len(t, days, 1)
+	assert.Len(t, days["2026-10-16"], 2)
+}
+
+func TestComputeStats(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	stats := computeStats([]*task.Task{
+		{Priority: 3, DueDate: now.AddDate(0, 0, -1), Assignee: "Маша", TimeSpent: time.Hour},
+		{Priority: 1, DueDate: now.AddDate(0, 0, 1)},
+		{Priority: 2, Completed: true, Assignee: "Маша"},
+		{Priority: 2, Archived: true},
+	}, now)
+	assert.Equal(t, 4, stats.Total)
+	assert.Equal(t, 2, stats.Active)
+	assert.Equal(t, 1, stats.Completed)
+	assert.Equal(t, 1, stats.Overdue)
+	assert.Equal(t, 1, stats.Archived)
+	assert.Equal(t, map[int]int{3: 1, 1: 1}, stats.ByPriority)
+	assert.Equal(t, map[string]int{"Маша": 2}, stats.ByAssignee)
+	assert.Contains(t, stats.String(), "Доля выполненных: 33%")
+	assert.Contains(t, stats.String(), "Затрачено времени: 1:00:00")
+}
+
+func TestMainTabsProjects(t *testing.T) {
+	test.NewTempApp(t)
+	tm := newTestManager(t)
+	added := mustAddTask(t, tm, "Отчет", "", 2, time.Now().Add(time.Hour))
+	assert.NoError(t, tm.SetTaskTags(added.ID, []string{"работа"}))
+	assert.Equal(t, []string{"работа"}, projectTags(tm.Tasks()))
+
+	tabs := newMainTabs(test.NewWindow(nil), test.NewTempApp(t).Preferences(), tm, widget.NewLabel("список"), container.NewVBox(), func() int { return defaultWorkloadCapacity }, func(int) {}, func(time.Time) {})
+	assert.Len(t, tabs.Items, 8)
+	assert.Empty(t, tabs.Projects())
+
+	tabs.OpenProject("работа")
+	tabs.OpenProject("работа")
+	assert.Equal(t, []string{"работа"}, tabs.Projects())
+	assert.Equal(t, "#работа", tabs.Selected().Text)
+
+	tabs.SelectTitle(tabStats)
+	assert.Equal(t, tabStats, tabs.Selected().Text)
+
+	tabs.CloseProject("работа")
+	assert.Empty(t, tabs.Projects())
+	assert.Len(t, tabs.Items, 8)
+}
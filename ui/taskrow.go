@@ -0,0 +1,278 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/task"
+)
+
+// prefRowDensity - плотность строк списка задач
+const prefRowDensity = "list.density"
+
+// rowDensity - плотность строк списка задач
+type rowDensity string
+
+const (
+	densityComfortable rowDensity = "comfortable" // название и подробности в две строки
+	densityCompact     rowDensity = "compact"     // только название, в одну строку
+)
+
+// rowDensities - плотности для выбора в настройках
+var rowDensities = []rowDensity{densityComfortable, densityCompact}
+
+// Title возвращает название плотности для настроек
+func (d rowDensity) Title() string {
+	return map[rowDensity]string{densityComfortable: "Просторно", densityCompact: "Компактно"}[d]
+}
+
+// loadRowDensity читает плотность строк из настроек; по умолчанию - просторно
+func loadRowDensity(prefs fyne.Preferences) rowDensity {
+	if rowDensity(prefs.String(prefRowDensity)) == densityCompact {
+		return densityCompact
+	}
+	return densityComfortable
+}
+
+// rowTagLimit - сколько меток помещается в строке; об остальных говорит счетчик «+N»
+const rowTagLimit = 3
+
+// rowBadge - короткая надпись на цветной подложке: срок задачи или метка.
+// Цвета задаются именами темы, чтобы подложка следовала за светлой и темной темой
+type rowBadge struct {
+	widget.BaseWidget
+	text     *canvas.Text
+	fill     *canvas.Rectangle
+	textName fyne.ThemeColorName
+	fillName fyne.ThemeColorName
+}
+
+func newRowBadge() *rowBadge {
+	b := &rowBadge{}
+	b.init()
+	b.ExtendBaseWidget(b)
+	return b
+}
+
+func (b *rowBadge) init() {
+	b.text = canvas.NewText("", nil)
+	b.text.TextSize = theme.CaptionTextSize()
+	b.fill = canvas.NewRectangle(nil)
+	b.fill.CornerRadius = theme.InputRadiusSize()
+	b.textName, b.fillName = theme.ColorNameForeground, theme.ColorNameInputBackground
+}
+
+// Set показывает текст text цветом textName на подложке цвета fillName
+func (b *rowBadge) Set(text string, textName, fillName fyne.ThemeColorName) {
+	b.text.Text = text
+	b.textName, b.fillName = textName, fillName
+	b.Refresh()
+}
+
+// applyColors переводит имена цветов в цвета текущей темы
+func (b *rowBadge) applyColors() {
+	b.text.Color = theme.Color(b.textName)
+	b.fill.FillColor = theme.Color(b.fillName)
+}
+
+func (b *rowBadge) Refresh() {
+	b.applyColors()
+	b.BaseWidget.Refresh()
+}
+
+func (b *rowBadge) CreateRenderer() fyne.WidgetRenderer {
+	b.applyColors()
+	padded := container.New(layout.NewCustomPaddedLayout(2, 2, 6, 6), b.text)
+	return widget.NewSimpleRenderer(container.NewStack(b.fill, padded))
+}
+
+// tagChip - метка в строке списка; нажатие открывает вкладку проекта с этой меткой
+type tagChip struct {
+	rowBadge
+	tag      string
+	onTapped func(tag string)
+}
+
+func newTagChip() *tagChip {
+	c := &tagChip{}
+	c.init()
+	c.fillName = theme.ColorNameHover
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+func (c *tagChip) Tapped(*fyne.PointEvent) {
+	if c.onTapped != nil {
+		c.onTapped(c.tag)
+	}
+}
+
+// taskRow - строка списка задач: флажок выполнения, значок приоритета, название с подробностями,
+// метки, срок и готовность. Правая кнопка мыши открывает контекстное меню, при наведении
+// срок показывается точной датой: у Fyne нет всплывающих подсказок
+type taskRow struct {
+	widget.BaseWidget
+	check    *widget.Check
+	priority *widget.Icon
+	title    *widget.Label
+	details  *widget.Label
+	tags     *fyne.Container
+	moreTags *rowBadge
+	due      *rowBadge
+	progress *fyne.Container
+
+	onToggle func()
+	onMenu   func(pos fyne.Position)
+	onTag    func(tag string)
+
+	dueText    string
+	dueTooltip string
+	hovered    bool
+	rtl        bool // письмо справа налево: флажок справа, метки и срок слева
+}
+
+// newTaskRow создает строку; плотность density задает ее высоту, поэтому
+// шаблон строки для списка создается с текущей плотностью. rtl отражает строку
+// для письма справа налево
+func newTaskRow(density rowDensity, rtl bool) *taskRow {
+	r := &taskRow{
+		rtl:      rtl,
+		priority: widget.NewIcon(nil),
+		title:    widget.NewLabel(""),
+		details:  widget.NewLabel(""),
+		tags:     container.NewHBox(),
+		moreTags: newRowBadge(),
+		due:      newRowBadge(),
+		progress: newProgressCell(),
+	}
+	r.check = widget.NewCheck("", r.toggled)
+	r.title.Truncation = fyne.TextTruncateEllipsis
+	r.details.Truncation = fyne.TextTruncateEllipsis
+	r.title.Alignment = textAlign(rtl)
+	r.details.Alignment = textAlign(rtl)
+	r.details.Importance = widget.LowImportance
+	r.details.SizeName = theme.SizeNameCaptionText
+	r.moreTags.Hide()
+	r.setDensity(density)
+	r.ExtendBaseWidget(r)
+	return r
+}
+
+func (r *taskRow) toggled(bool) {
+	if r.onToggle != nil {
+		r.onToggle()
+	}
+}
+
+func (r *taskRow) setDensity(density rowDensity) {
+	if density == densityCompact {
+		r.details.Hide()
+	} else {
+		r.details.Show()
+	}
+}
+
+// SetTask показывает задачу t; срок считается относительно дня now
+func (r *taskRow) SetTask(t *task.Task, now time.Time, density rowDensity) {
+	r.setDensity(density)
+
+	// Флажок отражает состояние задачи и не должен при этом сообщать о нажатии
+	r.check.OnChanged = nil
+	r.check.SetChecked(t.Completed)
+	r.check.OnChanged = r.toggled
+
+	r.priority.SetResource(priorityIcon(t.Priority))
+	r.title.Importance = widget.MediumImportance
+	if t.Completed {
+		r.title.Importance = widget.LowImportance
+	}
+	r.title.SetText(t.IconTitle())
+	r.details.SetText(strings.Join(taskDetails(t), " · "))
+
+	r.setTags(t.Tags)
+	r.dueText, r.dueTooltip = formatRelativeDue(t, now), formatDueTooltip(t)
+	switch {
+	case t.Completed:
+		r.due.textName, r.due.fillName = theme.ColorNameDisabled, theme.ColorNameInputBackground
+	case isOverdue(t, now):
+		r.due.textName, r.due.fillName = theme.ColorNameForegroundOnError, theme.ColorNameError
+	case t.DueDay() == now.Format(task.DueDateLayout):
+		r.due.textName, r.due.fillName = theme.ColorNameForegroundOnWarning, theme.ColorNameWarning
+	default:
+		r.due.textName, r.due.fillName = theme.ColorNameForeground, theme.ColorNameInputBackground
+	}
+	r.updateDue()
+	updateProgressCell(r.progress, t)
+}
+
+// setTags показывает первые метки задачи, переиспользуя созданные ранее
+func (r *taskRow) setTags(tags []string) {
+	shown := min(len(tags), rowTagLimit)
+	for len(r.tags.Objects) < shown {
+		chip := newTagChip()
+		chip.onTapped = func(tag string) {
+			if r.onTag != nil {
+				r.onTag(tag)
+			}
+		}
+		r.tags.Add(chip)
+	}
+	for i, object := range r.tags.Objects {
+		chip := object.(*tagChip)
+		if i >= shown {
+			chip.Hide()
+			continue
+		}
+		chip.tag = tags[i]
+		chip.Set("#"+tags[i], theme.ColorNameForeground, theme.ColorNameHover)
+		chip.Show()
+	}
+	if extra := len(tags) - shown; extra > 0 {
+		r.moreTags.Set(fmt.Sprintf("+%d", extra), theme.ColorNamePlaceHolder, theme.ColorNameInputBackground)
+		r.moreTags.Show()
+	} else {
+		r.moreTags.Hide()
+	}
+}
+
+func (r *taskRow) updateDue() {
+	text := r.dueText
+	if r.hovered && r.dueTooltip != "" {
+		text = r.dueTooltip
+	}
+	r.due.Set(text, r.due.textName, r.due.fillName)
+}
+
+func (r *taskRow) CreateRenderer() fyne.WidgetRenderer {
+	start := newDirectionalHBox(r.rtl, container.NewThemeOverride(r.check, rowCheckTheme{}), container.NewCenter(r.priority))
+	end := newDirectionalHBox(r.rtl, r.tags, r.moreTags, container.NewCenter(r.due), r.progress)
+	center := container.New(layout.NewCustomPaddedVBoxLayout(0), r.title, r.details)
+	return widget.NewSimpleRenderer(newDirectionalBorder(r.rtl, nil, nil, start, end, center))
+}
+
+func (r *taskRow) TappedSecondary(event *fyne.PointEvent) {
+	if r.onMenu != nil {
+		r.onMenu(event.AbsolutePosition)
+	}
+}
+
+func (r *taskRow) MouseIn(*desktop.MouseEvent) {
+	r.hovered = true
+	r.updateDue()
+}
+
+func (r *taskRow) MouseMoved(*desktop.MouseEvent) {}
+
+func (r *taskRow) MouseOut() {
+	r.hovered = false
+	r.updateDue()
+}
@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+func TestTaskRow(t *testing.T) {
+	test.NewTempApp(t)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	overdue := &task.Task{
+		Title:    "Отчет",
+		Priority: 3,
+		DueDate:  time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC),
+		Tags:     []string{"работа", "квартал", "финансы", "срочно"},
+		Assignee: "Петя",
+	}
+
+	row := newTaskRow(densityComfortable, false)
+	test.WidgetRenderer(row)
+	row.SetTask(overdue, now, densityComfortable)
+	assert.Equal(t, "Отчет", row.title.Text)
+	assert.Equal(t, "исполнитель: Петя", row.details.Text)
+	assert.True(t, row.details.Visible())
+	assert.False(t, row.check.Checked)
+	assert.Equal(t, "вчера", row.due.text.Text)
+	assert.Equal(t, theme.ColorNameError, row.due.fillName)
+
+	// Метки: первые три - кнопками, остальные - счетчиком
+	assert.Len(t, row.tags.Objects, rowTagLimit)
+	assert.True(t, row.moreTags.Visible())
+	assert.Equal(t, "+1", row.moreTags.text.Text)
+	var opened string
+	row.onTag = func(tag string) { opened = tag }
+	test.Tap(row.tags.Objects[1].(*tagChip))
+	assert.Equal(t, "квартал", opened)
+
+	// Флажок сообщает о нажатии, но не об обновлении строки
+	toggles := 0
+	row.onToggle = func() { toggles++ }
+	test.Tap(row.check)
+	assert.Equal(t, 1, toggles)
+	done := *overdue
+	done.Completed, done.Tags = true, []string{"дом"}
+	row.SetTask(&done, now, densityCompact)
+	assert.Equal(t, 1, toggles)
+	assert.True(t, row.check.Checked)
+	assert.Equal(t, widget.LowImportance, row.title.Importance)
+	assert.False(t, row.details.Visible())
+	assert.False(t, row.moreTags.Visible())
+	assert.False(t, row.tags.Objects[1].Visible())
+
+	// При наведении срок показывается точной датой
+	row.MouseIn(nil)
+	assert.Equal(t, "Срок: 15.10.2026", row.due.text.Text)
+	row.MouseOut()
+	assert.Equal(t, "вчера", row.due.text.Text)
+
+	var menuAt fyne.Position
+	row.onMenu = func(pos fyne.Position) { menuAt = pos }
+	test.TapSecondaryAt(row, fyne.NewPos(5, 5))
+	assert.NotEqual(t, fyne.Position{}, menuAt)
+}
+
+func TestRowDensityHeight(t *testing.T) {
+	a := test.NewTempApp(t)
+	assert.Equal(t, densityComfortable, loadRowDensity(a.Preferences()))
+	a.Preferences().SetString(prefRowDensity, string(densityCompact))
+	assert.Equal(t, densityCompact, loadRowDensity(a.Preferences()))
+
+	comfortable, compact := newTaskRow(densityComfortable, false), newTaskRow(densityCompact, false)
+	assert.Less(t, compact.MinSize().Height, comfortable.MinSize().Height)
+}
+
+func TestPriorityColors(t *testing.T) {
+	test.NewTempApp(t)
+	assert.Equal(t, theme.ColorNameError, priorityColor(3))
+	assert.Equal(t, theme.ColorNameWarning, priorityColor(2))
+	assert.Equal(t, theme.ColorNameDisabled, priorityColor(1))
+	assert.Equal(t, theme.ColorNameWarning, priorityColor(0))
+
+	// Карточки календаря и доски помечены тем же флажком, что и строки списка
+	high := &task.Task{ID: 1, Title: "Отчет", Priority: 3}
+	button := taskButton(high, func(int) {})
+	assert.Equal(t, priorityIcon(3).Name(), button.Icon.Name())
+	assert.Equal(t, widget.MediumImportance, button.Importance)
+	high.Completed = true
+	assert.Equal(t, widget.LowImportance, taskButton(high, func(int) {}).Importance)
+}
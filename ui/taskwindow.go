@@ -0,0 +1,280 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/task"
+)
+
+// taskWindows - открытые окна отдельных задач профиля, не больше одного на задачу
+type taskWindows struct {
+	a       fyne.App
+	prefs   fyne.Preferences
+	tm      *task.TaskManager
+	timers  *timerJournal
+	windows map[string]fyne.Window // по UUID задачи
+}
+
+func newTaskWindows(a fyne.App, prefs fyne.Preferences, tm *task.TaskManager, timers *timerJournal) *taskWindows {
+	return &taskWindows{a: a, prefs: prefs, tm: tm, timers: timers, windows: make(map[string]fyne.Window)}
+}
+
+// Open показывает задачу в отдельном окне или поднимает уже открытое
+func (tw *taskWindows) Open(id int) {
+	t := tw.tm.GetTask(id)
+	if t == nil {
+		return
+	}
+	if w, ok := tw.windows[t.UUID]; ok {
+		w.RequestFocus()
+		return
+	}
+	uuid := t.UUID
+	w := newTaskWindow(tw.a, tw.prefs, tw.tm, tw.timers, uuid, tw.Open, func() { delete(tw.windows, uuid) })
+	tw.windows[uuid] = w
+	w.Show()
+}
+
+// CloseAll закрывает окна задач: при блокировке приложения и закрытии профиля
+func (tw *taskWindows) CloseAll() {
+	for _, w := range tw.windows {
+		w.Close()
+	}
+}
+
+// formatTimeSpent показывает учтенное время как 1:05:09
+func formatTimeSpent(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
+
+// newTaskWindow создает окно задачи: название, описание со ссылками на упомянутые задачи,
+// чек-лист, таймер, который спрашивает о простое, связанные задачи и серию выполнений,
+// если задача повторяется. На вкладке истории прежние описания сравниваются с текущим.
+// Изменения сразу уходят в менеджер задач, а изменения из главного окна, API
+// и синхронизации приходят через события и показываются в окне
+func newTaskWindow(a fyne.App, prefs fyne.Preferences, tm *task.TaskManager, timers *timerJournal, uuid string, openTask func(id int), onClosed func()) fyne.Window {
+	t := tm.GetTaskByUUID(uuid)
+	w := newDirectionalWindow(loadRTL(prefs), a.NewWindow(t.Title))
+	w.Resize(fyne.NewSize(420, 480))
+
+	titleEntry := widget.NewEntry()
+	descEntry := newTabOutEntry()
+	descEntry.Wrapping = fyne.TextWrapWord
+	// Текст, который пользователь видел последним: если поле не менялось, его можно обновить
+	var shownTitle, shownDesc string
+
+	completedCheck := widget.NewCheck("Выполнена", nil)
+	habit := newHabitView()
+	var checklist []task.ChecklistItem
+	var checklistView *widget.List
+
+	timeLabel := widget.NewLabel("")
+	timerButton := widget.NewButton("", nil)
+	idle := newIdleTracker(w, prefs)
+
+	current := func() *task.Task { return tm.GetTaskByUUID(uuid) }
+	showError := func(err error) {
+		if err != nil {
+			slog.Error("failed to update task from its window", "uuid", uuid, "err", err)
+			dialog.ShowError(err, w)
+		}
+	}
+
+	// Упоминания #42 в описании открывают задачи, связи видны с обеих сторон
+	mentions := widget.NewRichText()
+	mentions.Wrapping = fyne.TextWrapWord
+	linked := newLinkedTasksView(tm, openTask, showError)
+	history := newDescriptionHistory(tm, showError)
+	toggleCompleted := func(bool) {
+		if t := current(); t != nil {
+			showError(tm.ToggleTaskCompletion(t.ID))
+		}
+	}
+
+	// refresh показывает задачу, не затирая несохраненный текст названия и описания
+	refresh := func() {
+		t := current()
+		if t == nil {
+			return
+		}
+		w.SetTitle(t.Title)
+		if titleEntry.Text == shownTitle {
+			titleEntry.SetText(t.Title)
+		}
+		if descEntry.Text == shownDesc {
+			descEntry.SetText(t.Description)
+		}
+		shownTitle, shownDesc = t.Title, t.Description
+		// Повторяющаяся задача отмечается выполненной в текущем дне или неделе
+		completedCheck.OnChanged = nil
+		switch {
+		case t.Completed || t.Recurrence == task.RecurNone:
+			completedCheck.SetText("Выполнена")
+			completedCheck.SetChecked(t.Completed)
+		case t.Recurrence == task.RecurWeekly:
+			completedCheck.SetText("Выполнена на этой неделе")
+			completedCheck.SetChecked(t.DoneInPeriod(time.Now()))
+		default:
+			completedCheck.SetText("Выполнена сегодня")
+			completedCheck.SetChecked(t.DoneInPeriod(time.Now()))
+		}
+		completedCheck.OnChanged = toggleCompleted
+		habit.Update(t, time.Now())
+		mentions.Segments = mentionSegments(t.Description, tm, openTask)
+		mentions.Refresh()
+		linked.Update(t)
+		history.Update(t)
+		checklist = append([]task.ChecklistItem(nil), t.Checklist...)
+		checklistView.Refresh()
+
+		// Таймер останавливается и без окна: когда задачу выполнили или компьютер уснул
+		spent := t.TimeSpent
+		if start := timers.Running(uuid); start.IsZero() {
+			timerButton.SetText("▶ Запустить таймер")
+		} else {
+			spent += time.Since(start)
+			timerButton.SetText("■ Остановить")
+		}
+		timeLabel.SetText("Затрачено: " + formatTimeSpent(spent))
+	}
+
+	saveText := func() {
+		t := current()
+		if t == nil || titleEntry.Text == t.Title && descEntry.Text == t.Description {
+			return
+		}
+		shownTitle, shownDesc = titleEntry.Text, descEntry.Text
+		showError(tm.UpdateTask(t.ID, titleEntry.Text, descEntry.Text, t.Priority, t.DueDate, t.Completed))
+	}
+	saveButton := widget.NewButton("Сохранить", saveText)
+	titleEntry.OnSubmitted = func(string) { saveText() }
+
+	setChecklist := func(items []task.ChecklistItem) {
+		if t := current(); t != nil {
+			showError(tm.SetTaskChecklist(t.ID, items))
+		}
+	}
+	checklistView = widget.NewList(
+		func() int { return len(checklist) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil, widget.NewButtonWithIcon("Удалить", theme.DeleteIcon(), nil), widget.NewCheck("", nil))
+		},
+		func(i widget.ListItemID, item fyne.CanvasObject) {
+			row := item.(*fyne.Container)
+			check := row.Objects[0].(*widget.Check)
+			remove := row.Objects[1].(*widget.Button)
+			check.OnChanged = nil
+			check.SetText(checklist[i].Text)
+			check.SetChecked(checklist[i].Done)
+			check.OnChanged = func(done bool) {
+				items := append([]task.ChecklistItem(nil), checklist...)
+				items[i].Done = done
+				setChecklist(items)
+			}
+			remove.OnTapped = func() {
+				items := append([]task.ChecklistItem(nil), checklist[:i]...)
+				setChecklist(append(items, checklist[i+1:]...))
+			}
+		},
+	)
+	newItemEntry := widget.NewEntry()
+	newItemEntry.SetPlaceHolder("Новый пункт")
+	addItem := func() {
+		if newItemEntry.Text == "" {
+			return
+		}
+		setChecklist(append(append([]task.ChecklistItem(nil), checklist...), task.ChecklistItem{Text: newItemEntry.Text}))
+		newItemEntry.SetText("")
+	}
+	newItemEntry.OnSubmitted = func(string) { addItem() }
+
+	// Таймер: время добавляется к задаче при остановке и при закрытии окна
+	stopTicker := make(chan struct{})
+	stopTimer := func() {
+		if timers.Running(uuid).IsZero() {
+			return
+		}
+		idle.Reset()
+		showError(timers.Stop(uuid, time.Now()))
+	}
+	timerButton.OnTapped = func() {
+		if timers.Running(uuid).IsZero() {
+			timers.Start(uuid, time.Now())
+		} else {
+			stopTimer()
+		}
+		refresh()
+	}
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopTicker:
+				return
+			case <-ticker.C:
+				fyne.Do(func() {
+					if start := timers.Running(uuid); !start.IsZero() {
+						refresh()
+						// Простой, который пользователь отбросил, сдвигает начало таймера
+						idle.Check(start, time.Now(), func(stretch time.Duration) {
+							timers.Shift(uuid, stretch)
+							refresh()
+						})
+					}
+				})
+			}
+		}
+	}()
+
+	unsubscribe := tm.Subscribe(func(event task.Event) {
+		switch {
+		case event.Op == task.EventReloaded, event.Task != nil && event.Task.UUID == uuid:
+		default:
+			return
+		}
+		if current() == nil {
+			// Задачу удалили - окно больше не нужно
+			slog.Debug("task window closed, task was deleted", "uuid", uuid)
+			w.Close()
+			return
+		}
+		refresh()
+	})
+	// Окно закрывает пользователь, блокировка приложения или удаление задачи:
+	// несохраненный текст и время таймера записываются в любом случае
+	w.SetOnClosed(func() {
+		unsubscribe()
+		close(stopTicker)
+		stopTimer()
+		saveText()
+		onClosed()
+	})
+
+	w.SetContent(container.NewBorder(
+		container.NewVBox(titleEntry, completedCheck, habit.box),
+		container.NewVBox(
+			widget.NewSeparator(),
+			container.NewBorder(nil, nil, nil, widget.NewButton("Добавить", addItem), newItemEntry),
+			linked.box,
+			container.NewBorder(nil, nil, timeLabel, timerButton),
+			saveButton,
+		),
+		nil, nil,
+		container.NewVSplit(container.NewAppTabs(
+			container.NewTabItem("Описание", container.NewBorder(nil, mentions, nil, nil, descEntry)),
+			container.NewTabItem("История", history.box),
+		), checklistView),
+	))
+	refresh()
+	return w
+}
@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskWindows(t *testing.T) {
+	a := test.NewTempApp(t)
+	tm := newTestManager(t)
+	added := mustAddTask(t, tm, "Купить молоко", "", 2, time.Now().Add(time.Hour))
+	windows := newTaskWindows(a, a.Preferences(), tm, newTimerJournal(a.Preferences(), tm))
+
+	// Повторное открытие не создает второе окно
+	windows.Open(added.ID)
+	windows.Open(added.ID)
+	assert.Len(t, windows.windows, 1)
+	w := windows.windows[added.UUID]
+	assert.Equal(t, "Купить молоко", w.Title())
+
+	// Изменение из главного окна видно в окне задачи
+	assert.NoError(t, tm.UpdateTask(added.ID, "Купить кефир", "", 2, added.DueDate, false))
+	assert.Equal(t, "Купить кефир", w.Title())
+
+	// Удаленная задача закрывает свое окно
+	assert.NoError(t, tm.DeleteTask(added.ID))
+	assert.Empty(t, windows.windows)
+
+	other := mustAddTask(t, tm, "Позвонить", "", 2, time.Now().Add(time.Hour))
+	windows.Open(other.ID)
+	windows.CloseAll()
+	assert.Empty(t, windows.windows)
+}
+
+func TestFormatTimeSpent(t *testing.T) {
+	assert.Equal(t, "0:00:00", formatTimeSpent(0))
+	assert.Equal(t, "1:05:09", formatTimeSpent(time.Hour+5*time.Minute+9*time.Second+300*time.Millisecond))
+}
@@ -0,0 +1,145 @@
+package ui
+
+import (
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+// Настройки оформления. Тема общая для всех окон, поэтому настройки хранятся
+// в общих настройках приложения, а не в настройках профиля
+const (
+	prefUIScale      = "ui.scale"         // масштаб интерфейса, 1 - как задумано темой
+	prefHighContrast = "ui.high_contrast" // контрастная тема для слабовидящих
+)
+
+// Пределы масштаба интерфейса для ползунка в настройках
+const (
+	minUIScale = 0.8
+	maxUIScale = 2.0
+)
+
+// rowCheckScale - во сколько раз флажок выполнения в строке списка больше обычного,
+// чтобы в него было проще попасть
+const rowCheckScale = 1.4
+
+// highContrastColors - цвета контрастной темы для темного и светлого вариантов
+var highContrastColors = map[fyne.ThemeVariant]map[fyne.ThemeColorName]color.Color{
+	theme.VariantDark: {
+		theme.ColorNameBackground:          color.Black,
+		theme.ColorNameOverlayBackground:   color.Black,
+		theme.ColorNameMenuBackground:      color.Black,
+		theme.ColorNameHeaderBackground:    color.NRGBA{R: 0x1a, G: 0x1a, B: 0x1a, A: 0xff},
+		theme.ColorNameInputBackground:     color.Black,
+		theme.ColorNameButton:              color.NRGBA{R: 0x1a, G: 0x1a, B: 0x1a, A: 0xff},
+		theme.ColorNameForeground:          color.White,
+		theme.ColorNameInputBorder:         color.White,
+		theme.ColorNameSeparator:           color.White,
+		theme.ColorNamePlaceHolder:         color.NRGBA{R: 0xcc, G: 0xcc, B: 0xcc, A: 0xff},
+		theme.ColorNameDisabled:            color.NRGBA{R: 0xb0, G: 0xb0, B: 0xb0, A: 0xff},
+		theme.ColorNamePrimary:             color.NRGBA{R: 0xff, G: 0xd6, B: 0x00, A: 0xff},
+		theme.ColorNameForegroundOnPrimary: color.Black,
+		theme.ColorNameFocus:               color.NRGBA{R: 0xff, G: 0xd6, B: 0x00, A: 0xff},
+		theme.ColorNameSelection:           color.NRGBA{R: 0xff, G: 0xd6, B: 0x00, A: 0x66},
+		theme.ColorNameHover:               color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0x33},
+		theme.ColorNameHyperlink:           color.NRGBA{R: 0x7f, G: 0xdb, B: 0xff, A: 0xff},
+		theme.ColorNameError:               color.NRGBA{R: 0xff, G: 0x6b, B: 0x6b, A: 0xff},
+		theme.ColorNameWarning:             color.NRGBA{R: 0xff, G: 0xb0, B: 0x00, A: 0xff},
+		theme.ColorNameSuccess:             color.NRGBA{R: 0x3d, G: 0xdc, B: 0x84, A: 0xff},
+		theme.ColorNameForegroundOnError:   color.Black,
+		theme.ColorNameForegroundOnWarning: color.Black,
+		theme.ColorNameForegroundOnSuccess: color.Black,
+	},
+	theme.VariantLight: {
+		theme.ColorNameBackground:          color.White,
+		theme.ColorNameOverlayBackground:   color.White,
+		theme.ColorNameMenuBackground:      color.White,
+		theme.ColorNameHeaderBackground:    color.NRGBA{R: 0xe6, G: 0xe6, B: 0xe6, A: 0xff},
+		theme.ColorNameInputBackground:     color.White,
+		theme.ColorNameButton:              color.NRGBA{R: 0xe6, G: 0xe6, B: 0xe6, A: 0xff},
+		theme.ColorNameForeground:          color.Black,
+		theme.ColorNameInputBorder:         color.Black,
+		theme.ColorNameSeparator:           color.Black,
+		theme.ColorNamePlaceHolder:         color.NRGBA{R: 0x40, G: 0x40, B: 0x40, A: 0xff},
+		theme.ColorNameDisabled:            color.NRGBA{R: 0x50, G: 0x50, B: 0x50, A: 0xff},
+		theme.ColorNamePrimary:             color.NRGBA{R: 0x00, G: 0x37, B: 0xa0, A: 0xff},
+		theme.ColorNameForegroundOnPrimary: color.White,
+		theme.ColorNameFocus:               color.NRGBA{R: 0x00, G: 0x37, B: 0xa0, A: 0xff},
+		theme.ColorNameSelection:           color.NRGBA{R: 0x00, G: 0x37, B: 0xa0, A: 0x40},
+		theme.ColorNameHover:               color.NRGBA{R: 0x00, G: 0x00, B: 0x00, A: 0x1f},
+		theme.ColorNameHyperlink:           color.NRGBA{R: 0x00, G: 0x37, B: 0xa0, A: 0xff},
+		theme.ColorNameError:               color.NRGBA{R: 0xb0, G: 0x00, B: 0x20, A: 0xff},
+		theme.ColorNameWarning:             color.NRGBA{R: 0xa3, G: 0x52, B: 0x00, A: 0xff},
+		theme.ColorNameSuccess:             color.NRGBA{R: 0x00, G: 0x6b, B: 0x2e, A: 0xff},
+		theme.ColorNameForegroundOnError:   color.White,
+		theme.ColorNameForegroundOnWarning: color.White,
+		theme.ColorNameForegroundOnSuccess: color.White,
+	},
+}
+
+// appTheme - тема приложения: стандартная тема Fyne с масштабом и контрастными цветами
+type appTheme struct {
+	fyne.Theme
+	scale        float32
+	highContrast bool
+}
+
+// newAppTheme создает тему с масштабом scale; масштаб вне пределов ползунка ограничивается ими
+func newAppTheme(scale float64, highContrast bool) *appTheme {
+	scale = min(max(scale, minUIScale), maxUIScale)
+	return &appTheme{Theme: theme.DefaultTheme(), scale: float32(scale), highContrast: highContrast}
+}
+
+// loadAppTheme создает тему по общим настройкам приложения prefs
+func loadAppTheme(prefs fyne.Preferences) *appTheme {
+	return newAppTheme(prefs.FloatWithFallback(prefUIScale, 1), prefs.Bool(prefHighContrast))
+}
+
+// applyAppTheme применяет к приложению тему из его общих настроек
+func applyAppTheme(a fyne.App) {
+	a.Settings().SetTheme(loadAppTheme(a.Preferences()))
+}
+
+func (t *appTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	if t.highContrast {
+		if c, ok := highContrastColors[variant][name]; ok {
+			return c
+		}
+	}
+	return t.Theme.Color(name, variant)
+}
+
+func (t *appTheme) Size(name fyne.ThemeSizeName) float32 {
+	return t.Theme.Size(name) * t.scale
+}
+
+// currentAppTheme возвращает тему приложения. theme.Current внутри переопределенной темы
+// вернул бы саму переопределенную тему
+func currentAppTheme() fyne.Theme {
+	return fyne.CurrentApp().Settings().Theme()
+}
+
+// rowCheckTheme увеличивает флажок выполнения в строке списка. Остальное берется из текущей
+// темы при каждом обращении, поэтому смена темы в настройках применяется и к флажку
+type rowCheckTheme struct{}
+
+func (rowCheckTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	return currentAppTheme().Color(name, variant)
+}
+
+func (rowCheckTheme) Font(style fyne.TextStyle) fyne.Resource {
+	return currentAppTheme().Font(style)
+}
+
+func (rowCheckTheme) Icon(name fyne.ThemeIconName) fyne.Resource {
+	return currentAppTheme().Icon(name)
+}
+
+func (rowCheckTheme) Size(name fyne.ThemeSizeName) float32 {
+	size := currentAppTheme().Size(name)
+	if name == theme.SizeNameInlineIcon {
+		return size * rowCheckScale
+	}
+	return size
+}
@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"image/color"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/theme"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppTheme(t *testing.T) {
+	a := test.NewTempApp(t)
+	base := theme.DefaultTheme()
+
+	plain := loadAppTheme(a.Preferences())
+	assert.Equal(t, base.Size(theme.SizeNameText), plain.Size(theme.SizeNameText))
+	assert.Equal(t, base.Color(theme.ColorNameBackground, theme.VariantDark), plain.Color(theme.ColorNameBackground, theme.VariantDark))
+
+	a.Preferences().SetFloat(prefUIScale, 1.5)
+	a.Preferences().SetBool(prefHighContrast, true)
+	applyAppTheme(a)
+	scaled := a.Settings().Theme()
+	assert.Equal(t, base.Size(theme.SizeNameText)*1.5, scaled.Size(theme.SizeNameText))
+	assert.Equal(t, base.Size(theme.SizeNamePadding)*1.5, scaled.Size(theme.SizeNamePadding))
+	assert.Equal(t, color.Black, scaled.Color(theme.ColorNameBackground, theme.VariantDark))
+	assert.Equal(t, color.White, scaled.Color(theme.ColorNameBackground, theme.VariantLight))
+	// Цвета без контрастной замены берутся из стандартной темы
+	assert.Equal(t, base.Color(theme.ColorNameShadow, theme.VariantDark), scaled.Color(theme.ColorNameShadow, theme.VariantDark))
+
+	// Масштаб ограничен пределами ползунка
+	assert.Equal(t, float32(maxUIScale), newAppTheme(5, false).scale)
+	assert.Equal(t, float32(minUIScale), newAppTheme(0, false).scale)
+
+	// Флажок в строке списка крупнее обычного и следует за темой приложения
+	assert.Equal(t, scaled.Size(theme.SizeNameInlineIcon)*rowCheckScale, rowCheckTheme{}.Size(theme.SizeNameInlineIcon))
+	assert.Equal(t, scaled.Size(theme.SizeNameText), rowCheckTheme{}.Size(theme.SizeNameText))
+}
@@ -0,0 +1,299 @@
+package ui
+
+import (
+	"image/color"
+	"math"
+	"sort"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/task"
+)
+
+// timelineZooms - ширина дня на таймлайне в точках, от мелкого масштаба к крупному
+var timelineZooms = []float32{8, 16, 32, 64}
+
+// Размеры таймлайна
+const (
+	timelineRowHeight float32 = 28
+	timelineHandle    float32 = 8 // ширина края полосы, за который меняется начало или срок
+	noProjectTitle            = "Без проекта"
+)
+
+// timelineGroup - проект на таймлайне: метка и ее задачи
+type timelineGroup struct {
+	Title string
+	Tasks []*task.Task
+}
+
+// timelineGroups раскладывает задачи по проектам - меткам; задача с несколькими метками
+// есть в каждом проекте, задачи без меток идут последними. В проекте задачи идут по началу
+func timelineGroups(tasks []*task.Task) []timelineGroup {
+	byTag := make(map[string][]*task.Task)
+	for _, t := range tasks {
+		if len(t.Tags) == 0 {
+			byTag[noProjectTitle] = append(byTag[noProjectTitle], t)
+		}
+		for _, tag := range t.Tags {
+			byTag[projectTabPrefix+tag] = append(byTag[projectTabPrefix+tag], t)
+		}
+	}
+	var groups []timelineGroup
+	for _, tag := range projectTags(tasks) {
+		groups = append(groups, timelineGroup{Title: projectTabPrefix + tag, Tasks: byTag[projectTabPrefix+tag]})
+	}
+	if len(byTag[noProjectTitle]) > 0 {
+		groups = append(groups, timelineGroup{Title: noProjectTitle, Tasks: byTag[noProjectTitle]})
+	}
+	for _, group := range groups {
+		sort.SliceStable(group.Tasks, func(i, j int) bool {
+			return group.Tasks[i].StartDay() < group.Tasks[j].StartDay()
+		})
+	}
+	return groups
+}
+
+// parseDay разбирает день «2006-01-02» в полночь UTC: на таймлайне считаются только дни
+func parseDay(day string) time.Time {
+	t, _ := time.Parse(task.DueDateLayout, day)
+	return t
+}
+
+// timelineRange возвращает первый день таймлайна и число дней в нем: от самого раннего начала
+// до самого позднего срока с запасом в неделю, сегодняшний день всегда виден
+func timelineRange(tasks []*task.Task, today string) (first time.Time, days int) {
+	from, to := today, today
+	for _, t := range tasks {
+		from = min(from, t.StartDay())
+		to = max(to, t.DueDay())
+	}
+	first = parseDay(from).AddDate(0, 0, -7)
+	last := parseDay(to).AddDate(0, 0, 7)
+	return first, int(last.Sub(first).Hours()/24) + 1
+}
+
+// dayOffset возвращает номер дня day от первого дня таймлайна
+func dayOffset(first time.Time, day string) int {
+	return int(parseDay(day).Sub(first).Hours() / 24)
+}
+
+// barDrag - что меняет перетаскивание полосы задачи
+type barDrag int
+
+const (
+	dragMove  barDrag = iota // сдвигает начало и срок
+	dragStart                // левый край - начало
+	dragDue                  // правый край - срок
+)
+
+// draggedDates возвращает начало и срок задачи после перетаскивания на days дней.
+// Край не заходит за другой край: полоса занимает хотя бы один день
+func draggedDates(t *task.Task, drag barDrag, days int) (start, due time.Time) {
+	start = t.StartDate
+	if start.IsZero() {
+		start = t.DueDate
+	}
+	due = t.DueDate
+	switch drag {
+	case dragMove:
+		start, due = start.AddDate(0, 0, days), due.AddDate(0, 0, days)
+	case dragStart:
+		start = start.AddDate(0, 0, days)
+		if start.Format(task.DueDateLayout) > due.Format(task.DueDateLayout) {
+			start = due
+		}
+	case dragDue:
+		due = due.AddDate(0, 0, days)
+		if due.Format(task.DueDateLayout) < start.Format(task.DueDateLayout) {
+			due = start
+		}
+	}
+	if start.Format(task.DueDateLayout) == due.Format(task.DueDateLayout) && t.StartDate.IsZero() {
+		start = time.Time{} // однодневная задача без начала остается без него
+	}
+	return start, due
+}
+
+// timelineBar - полоса задачи: нажатие открывает задачу, перетаскивание за середину сдвигает ее,
+// за края - меняет начало или срок
+type timelineBar struct {
+	widget.BaseWidget
+	t      *task.Task
+	fill   color.Color
+	drag   barDrag
+	dx     float32
+	moving bool
+	onTap  func()
+	onDrag func(drag barDrag, dx float32)
+}
+
+func newTimelineBar(t *task.Task, fill color.Color, onTap func(), onDrag func(drag barDrag, dx float32)) *timelineBar {
+	bar := &timelineBar{t: t, fill: fill, onTap: onTap, onDrag: onDrag}
+	bar.ExtendBaseWidget(bar)
+	return bar
+}
+
+func (b *timelineBar) CreateRenderer() fyne.WidgetRenderer {
+	rect := canvas.NewRectangle(b.fill)
+	rect.CornerRadius = 4
+	handleColor := theme.Color(theme.ColorNameShadow)
+	left, right := canvas.NewRectangle(handleColor), canvas.NewRectangle(handleColor)
+	left.SetMinSize(fyne.NewSize(timelineHandle/2, 0))
+	right.SetMinSize(fyne.NewSize(timelineHandle/2, 0))
+	title := canvas.NewText(" "+b.t.Title, theme.Color(theme.ColorNameForeground))
+	title.TextSize = theme.CaptionTextSize()
+	return widget.NewSimpleRenderer(container.NewStack(rect, container.NewBorder(nil, nil, left, right, title)))
+}
+
+func (b *timelineBar) Tapped(*fyne.PointEvent) {
+	b.onTap()
+}
+
+func (b *timelineBar) Dragged(event *fyne.DragEvent) {
+	if !b.moving {
+		b.moving, b.dx = true, 0
+		startX := event.Position.X - event.Dragged.DX
+		switch {
+		case startX < timelineHandle:
+			b.drag = dragStart
+		case startX > b.Size().Width-timelineHandle:
+			b.drag = dragDue
+		default:
+			b.drag = dragMove
+		}
+	}
+	b.dx += event.Dragged.DX
+	// Полоса следует за мышью, даты меняются, когда ее отпустят
+	switch b.drag {
+	case dragMove:
+		b.Move(b.Position().AddXY(event.Dragged.DX, 0))
+	case dragStart:
+		b.Move(b.Position().AddXY(event.Dragged.DX, 0))
+		b.Resize(fyne.NewSize(max(b.Size().Width-event.Dragged.DX, timelineHandle*2), b.Size().Height))
+	case dragDue:
+		b.Resize(fyne.NewSize(max(b.Size().Width+event.Dragged.DX, timelineHandle*2), b.Size().Height))
+	}
+}
+
+func (b *timelineBar) DragEnd() {
+	b.moving = false
+	b.onDrag(b.drag, b.dx)
+}
+
+// fixedLayout не двигает объекты: они расставлены вручную. Размер задается, чтобы работала прокрутка
+type fixedLayout struct {
+	size fyne.Size
+}
+
+func (l *fixedLayout) Layout([]fyne.CanvasObject, fyne.Size) {}
+
+func (l *fixedLayout) MinSize([]fyne.CanvasObject) fyne.Size {
+	return l.size
+}
+
+// newTimelineView создает вкладку таймлайна: задачи полосами от начала до срока по проектам
+func newTimelineView(w fyne.Window, tm *task.TaskManager, openTask func(id int)) (view fyne.CanvasObject, refresh func()) {
+	zoom := 2
+	layout := &fixedLayout{}
+	chart := container.New(layout)
+	scroll := container.NewScroll(chart)
+
+	// Сдвиг полосы на dx точек переносит даты на целое число дней
+	applyDrag := func(t *task.Task, drag barDrag, dx float32) {
+		days := int(math.Round(float64(dx / timelineZooms[zoom])))
+		if days != 0 {
+			start, due := draggedDates(t, drag, days)
+			if err := tm.SetTaskDates(t.ID, start, due); err != nil {
+				dialog.ShowError(err, w)
+			}
+		}
+		refresh()
+	}
+
+	refresh = func() {
+		dayWidth := timelineZooms[zoom]
+		now := tm.DueZone().Now()
+		today := now.Format(task.DueDateLayout)
+		var tasks []*task.Task
+		for _, t := range visibleTasks(tm) {
+			if !t.DueDate.IsZero() {
+				tasks = append(tasks, t)
+			}
+		}
+		first, days := timelineRange(tasks, today)
+		groups := timelineGroups(tasks)
+		height := timelineRowHeight
+		for _, group := range groups {
+			height += timelineRowHeight * float32(1+len(group.Tasks))
+		}
+
+		var objects []fyne.CanvasObject
+		place := func(obj fyne.CanvasObject, x, y, width, height float32) {
+			obj.Move(fyne.NewPos(x, y))
+			obj.Resize(fyne.NewSize(width, height))
+			objects = append(objects, obj)
+		}
+
+		// Шкала дней: подписи понедельников, а в крупном масштабе - каждого дня; выходные светлее
+		for i := range days {
+			day := first.AddDate(0, 0, i)
+			if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+				place(canvas.NewRectangle(theme.Color(theme.ColorNameHover)), float32(i)*dayWidth, 0, dayWidth, height)
+			}
+			if day.Weekday() == time.Monday || dayWidth >= 32 {
+				label := canvas.NewText(day.Format("02.01"), theme.Color(theme.ColorNamePlaceHolder))
+				label.TextSize = theme.CaptionTextSize()
+				place(label, float32(i)*dayWidth+2, 0, dayWidth*7, timelineRowHeight)
+			}
+		}
+
+		y := timelineRowHeight
+		for _, group := range groups {
+			title := canvas.NewText(group.Title, theme.Color(theme.ColorNameForeground))
+			title.TextStyle.Bold = true
+			place(title, 4, y, float32(days)*dayWidth, timelineRowHeight)
+			y += timelineRowHeight
+			for _, t := range group.Tasks {
+				fill := theme.Color(theme.ColorNamePrimary)
+				switch {
+				case t.Completed:
+					fill = theme.Color(theme.ColorNameDisabled)
+				case t.IsOverdue(now):
+					fill = theme.Color(theme.ColorNameError)
+				}
+				id := t.ID
+				bar := newTimelineBar(t, fill, func() { openTask(id) }, func(drag barDrag, dx float32) { applyDrag(t, drag, dx) })
+				start, end := dayOffset(first, t.StartDay()), dayOffset(first, t.DueDay())
+				place(bar, float32(start)*dayWidth, y+3, float32(end-start+1)*dayWidth, timelineRowHeight-6)
+				y += timelineRowHeight
+			}
+		}
+
+		todayLine := canvas.NewRectangle(theme.Color(theme.ColorNameError))
+		place(todayLine, float32(dayOffset(first, today))*dayWidth+dayWidth/2, 0, 1, height)
+
+		layout.size = fyne.NewSize(float32(days)*dayWidth, height)
+		chart.Objects = objects
+		chart.Refresh()
+		scroll.Refresh()
+	}
+
+	zoomBy := func(step int) {
+		zoom = min(max(zoom+step, 0), len(timelineZooms)-1)
+		refresh()
+	}
+	toolbar := container.NewHBox(
+		widget.NewLabel("Масштаб:"),
+		widget.NewButtonWithIcon("Мельче", theme.ZoomOutIcon(), func() { zoomBy(-1) }),
+		widget.NewButtonWithIcon("Крупнее", theme.ZoomInIcon(), func() { zoomBy(1) }),
+		widget.NewLabel("Перетащите полосу, чтобы сдвинуть задачу, или ее край, чтобы изменить начало или срок"),
+	)
+	refresh()
+	return container.NewBorder(toolbar, nil, nil, nil, scroll), refresh
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+func TestTimelineGroups(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC) }
+	site := &task.Task{ID: 1, Tags: []string{"сайт"}, StartDate: day(12), DueDate: day(20)}
+	design := &task.Task{ID: 2, Tags: []string{"сайт", "дизайн"}, DueDate: day(10)}
+	milk := &task.Task{ID: 3, DueDate: day(16)}
+
+	groups := timelineGroups([]*task.Task{site, design, milk})
+	if assert.Len(t, groups, 3) {
+		assert.Equal(t, "#дизайн", groups[0].Title)
+		assert.Equal(t, "#сайт", groups[1].Title)
+		assert.Equal(t, []*task.Task{design, site}, groups[1].Tasks)
+		assert.Equal(t, noProjectTitle, groups[2].Title)
+	}
+
+	first, days := timelineRange([]*task.Task{site, design, milk}, "2026-10-16")
+	assert.Equal(t, day(3), first)
+	assert.Equal(t, 25, days)
+	assert.Equal(t, 9, dayOffset(first, site.StartDay()))
+}
+
+func TestDraggedDates(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC) }
+	site := &task.Task{StartDate: day(12), DueDate: day(20)}
+
+	start, due := draggedDates(site, dragMove, 2)
+	assert.Equal(t, []time.Time{day(14), day(22)}, []time.Time{start, due})
+	start, due = draggedDates(site, dragStart, -3)
+	assert.Equal(t, []time.Time{day(9), day(20)}, []time.Time{start, due})
+	// Край не заходит за другой
+	start, due = draggedDates(site, dragDue, -10)
+	assert.Equal(t, []time.Time{day(12), day(12)}, []time.Time{start, due})
+
+	// У задачи без начала правый край продлевает ее от срока
+	milk := &task.Task{DueDate: day(16)}
+	start, due = draggedDates(milk, dragDue, 2)
+	assert.Equal(t, []time.Time{day(16), day(18)}, []time.Time{start, due})
+	start, _ = draggedDates(milk, dragMove, 1)
+	assert.True(t, start.IsZero())
+}
+
+func TestTimelineBarDrag(t *testing.T) {
+	test.NewTempApp(t)
+	var drag barDrag
+	var dx float32
+	bar := newTimelineBar(&task.Task{Title: "Сайт"}, nil, func() {}, func(d barDrag, x float32) { drag, dx = d, x })
+	bar.Resize(fyne.NewSize(100, 20))
+
+	// Перетаскивание за правый край меняет срок
+	bar.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{Position: fyne.NewPos(101, 10)}, Dragged: fyne.NewDelta(5, 0)})
+	bar.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{Position: fyne.NewPos(131, 10)}, Dragged: fyne.NewDelta(30, 0)})
+	bar.DragEnd()
+	assert.Equal(t, dragDue, drag)
+	assert.Equal(t, float32(35), dx)
+
+	// За середину - сдвигает задачу
+	bar.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{Position: fyne.NewPos(40, 10)}, Dragged: fyne.NewDelta(-10, 0)})
+	bar.DragEnd()
+	assert.Equal(t, dragMove, drag)
+	assert.Equal(t, float32(-10), dx)
+}
+
+func TestTimelineView(t *testing.T) {
+	test.NewTempApp(t)
+	tm := newTestManager(t)
+	added := mustAddTask(t, tm, "Сайт", "", 2, time.Now().AddDate(0, 0, 3))
+	assert.NoError(t, tm.SetTaskTags(added.ID, []string{"сайт"}))
+
+	view, refresh := newTimelineView(test.NewWindow(nil), tm, func(int) {})
+	assert.NotNil(t, view)
+	refresh()
+}
@@ -0,0 +1,177 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/task"
+)
+
+// Запущенные таймеры хранятся в настройках профиля, чтобы время не потерялось, если
+// приложение упало или его закрыли принудительно
+const (
+	prefTimersRunning   = "timer.running"   // UUID задачи - когда запустили таймер
+	prefTimersHeartbeat = "timer.heartbeat" // когда приложение последний раз видело таймеры идущими
+)
+
+// timerSleepGap - перерыв между отметками, после которого считается, что компьютер спал.
+// Отметки ставятся раз в 15 секунд
+const timerSleepGap = 2 * time.Minute
+
+// timerJournal - запущенные таймеры профиля. Таймер останавливается и время записывается
+// в задачу, когда ее выполнили, когда закрывают приложение и когда компьютер засыпает
+type timerJournal struct {
+	prefs     fyne.Preferences
+	tm        *task.TaskManager
+	running   map[string]time.Time
+	heartbeat time.Time
+}
+
+// newTimerJournal читает таймеры, оставшиеся с прошлого запуска, и следит за задачами
+func newTimerJournal(prefs fyne.Preferences, tm *task.TaskManager) *timerJournal {
+	j := &timerJournal{prefs: prefs, tm: tm, running: make(map[string]time.Time)}
+	if data := prefs.String(prefTimersRunning); data != "" {
+		if err := json.Unmarshal([]byte(data), &j.running); err != nil {
+			slog.Warn("failed to read running timers", "err", err)
+		}
+	}
+	if heartbeat := prefs.String(prefTimersHeartbeat); heartbeat != "" {
+		j.heartbeat, _ = time.Parse(time.RFC3339, heartbeat)
+	}
+	tm.Subscribe(func(event task.Event) {
+		switch {
+		case event.Task == nil:
+		case event.Op == task.EventDeleted:
+			j.forget(event.Task.UUID)
+		case event.Op == task.EventCompleted && event.Task.Completed,
+			event.Op == task.EventOccurrence && event.Task.DoneInPeriod(tm.DueZone().Now()):
+			j.Stop(event.Task.UUID, time.Now())
+		}
+	})
+	return j
+}
+
+// save записывает таймеры в настройки
+func (j *timerJournal) save() {
+	data, _ := json.Marshal(j.running)
+	j.prefs.SetString(prefTimersRunning, string(data))
+}
+
+// Running возвращает, когда запустили таймер задачи; нулевое время - таймер не идет
+func (j *timerJournal) Running(uuid string) time.Time {
+	return j.running[uuid]
+}
+
+// Start запускает таймер задачи
+func (j *timerJournal) Start(uuid string, now time.Time) {
+	if _, ok := j.running[uuid]; ok {
+		return
+	}
+	// Время без монотонных часов: они не идут, пока компьютер спит, а таймер должен это видеть
+	j.running[uuid] = now.Round(0)
+	j.heartbeat = now.Round(0)
+	j.save()
+}
+
+// Stop останавливает таймер задачи и добавляет к ней время до now
+func (j *timerJournal) Stop(uuid string, now time.Time) error {
+	start, ok := j.running[uuid]
+	if !ok {
+		return nil
+	}
+	j.forget(uuid)
+	t := j.tm.GetTaskByUUID(uuid)
+	if t == nil {
+		return nil
+	}
+	return j.tm.AddTaskTime(t.ID, now.Round(0).Sub(start))
+}
+
+// Shift сдвигает начало таймера на d, например чтобы не учитывать простой
+func (j *timerJournal) Shift(uuid string, d time.Duration) {
+	if start, ok := j.running[uuid]; ok {
+		j.running[uuid] = start.Add(d)
+		j.save()
+	}
+}
+
+// forget убирает таймер, не записывая время
+func (j *timerJournal) forget(uuid string) {
+	if _, ok := j.running[uuid]; !ok {
+		return
+	}
+	delete(j.running, uuid)
+	j.save()
+}
+
+// StopAll останавливает все таймеры, например перед закрытием приложения
+func (j *timerJournal) StopAll(now time.Time) {
+	for uuid := range j.running {
+		if err := j.Stop(uuid, now); err != nil {
+			slog.Error("failed to record timer", "uuid", uuid, "err", err)
+		}
+	}
+}
+
+// Heartbeat вызывается раз в 15 секунд и отмечает, что таймеры идут. Если с прошлой
+// отметки прошло больше timerSleepGap, компьютер спал: таймеры останавливаются на прошлой отметке
+func (j *timerJournal) Heartbeat(now time.Time) {
+	if len(j.running) == 0 {
+		return
+	}
+	now = now.Round(0)
+	if !j.heartbeat.IsZero() && now.Sub(j.heartbeat) > timerSleepGap {
+		slog.Info("timers stopped after sleep", "count", len(j.running), "slept", now.Sub(j.heartbeat).Round(time.Second))
+		j.StopAll(j.heartbeat)
+		return
+	}
+	j.heartbeat = now
+	j.prefs.SetString(prefTimersHeartbeat, now.Format(time.RFC3339))
+}
+
+// Recover спрашивает, что делать с таймерами, которые шли, когда приложение закрылось
+// неожиданно. Время можно учесть до последней отметки или отбросить
+func (j *timerJournal) Recover(w fyne.Window) {
+	if len(j.running) == 0 {
+		return
+	}
+	end := j.heartbeat
+	var lines []string
+	for uuid, start := range j.running {
+		t := j.tm.GetTaskByUUID(uuid)
+		if t == nil {
+			j.forget(uuid)
+			continue
+		}
+		if end.Before(start) {
+			end = start
+		}
+		lines = append(lines, fmt.Sprintf("%s: с %s", t.Title, start.Local().Format("02.01 15:04")))
+	}
+	if len(lines) == 0 {
+		return
+	}
+	message := widget.NewLabel(fmt.Sprintf("Когда приложение закрылось неожиданно, шли таймеры:\n%s\n\nУчесть время до %s?",
+		strings.Join(lines, "\n"), end.Local().Format("02.01 15:04")))
+	message.Wrapping = fyne.TextWrapWord
+	d := dialog.NewCustomConfirm("Незавершенные таймеры", "Учесть", "Отбросить", message, func(keep bool) {
+		if keep {
+			slog.Info("recovered timers recorded", "count", len(j.running))
+			j.StopAll(end)
+			return
+		}
+		slog.Info("recovered timers discarded", "count", len(j.running))
+		for uuid := range j.running {
+			j.forget(uuid)
+		}
+	}, w)
+	d.Resize(dialogSize(w, fyne.NewSize(460, 0)))
+	d.Show()
+}
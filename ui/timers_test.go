@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimerJournal(t *testing.T) {
+	a := test.NewTempApp(t)
+	tm := newTestManager(t)
+	report := mustAddTask(t, tm, "Отчет", "", 2, time.Now())
+	call := mustAddTask(t, tm, "Созвон", "", 2, time.Now())
+	timers := newTimerJournal(a.Preferences(), tm)
+	start := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+
+	timers.Start(report.UUID, start)
+	timers.Start(report.UUID, start.Add(time.Minute))
+	assert.Equal(t, start, timers.Running(report.UUID))
+	timers.Shift(report.UUID, 5*time.Minute)
+	require.NoError(t, timers.Stop(report.UUID, start.Add(30*time.Minute)))
+	assert.Equal(t, 25*time.Minute, tm.GetTask(report.ID).TimeSpent)
+	assert.True(t, timers.Running(report.UUID).IsZero())
+
+	// Выполненная задача останавливает свой таймер, удаленная - забывает
+	timers.Start(report.UUID, time.Now().Add(-10*time.Minute))
+	require.NoError(t, tm.ToggleTaskCompletion(report.ID))
+	assert.True(t, timers.Running(report.UUID).IsZero())
+	assert.InDelta(t, 35*time.Minute, tm.GetTask(report.ID).TimeSpent, float64(time.Second))
+	timers.Start(call.UUID, start)
+	require.NoError(t, tm.DeleteTask(call.ID))
+	assert.Empty(t, timers.running)
+}
+
+func TestTimerJournalSleep(t *testing.T) {
+	a := test.NewTempApp(t)
+	tm := newTestManager(t)
+	report := mustAddTask(t, tm, "Отчет", "", 2, time.Now())
+	timers := newTimerJournal(a.Preferences(), tm)
+	start := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+
+	timers.Start(report.UUID, start)
+	timers.Heartbeat(start.Add(15 * time.Second))
+	timers.Heartbeat(start.Add(30 * time.Second))
+	assert.False(t, timers.Running(report.UUID).IsZero())
+
+	// Компьютер спал час: время учитывается до последней отметки
+	timers.Heartbeat(start.Add(time.Hour))
+	assert.True(t, timers.Running(report.UUID).IsZero())
+	assert.Equal(t, 30*time.Second, tm.GetTask(report.ID).TimeSpent)
+}
+
+func TestTimerJournalRecover(t *testing.T) {
+	a := test.NewTempApp(t)
+	tm := newTestManager(t)
+	report := mustAddTask(t, tm, "Отчет", "", 2, time.Now())
+	start := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+
+	// Приложение упало, пока шел таймер
+	crashed := newTimerJournal(a.Preferences(), tm)
+	crashed.Start(report.UUID, start)
+	crashed.Heartbeat(start.Add(45 * time.Second))
+
+	w := test.NewWindow(nil)
+	defer w.Close()
+	timers := newTimerJournal(a.Preferences(), tm)
+	assert.Equal(t, start, timers.Running(report.UUID))
+	timers.Recover(w)
+	test.Tap(findText(w.Canvas().Overlays().Top(), "Учесть").(*widget.Button))
+	assert.Equal(t, 45*time.Second, tm.GetTask(report.ID).TimeSpent)
+	assert.Empty(t, newTimerJournal(a.Preferences(), tm).running)
+}
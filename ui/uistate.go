@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+
+	"taskmanager/task"
+)
+
+// Ключи раздела настроек с состоянием интерфейса
+const (
+	prefUIWidth       = "ui.width"
+	prefUIHeight      = "ui.height"
+	prefUIView        = "ui.view"
+	prefUIOnlyActive  = "ui.only_active"
+	prefUISearch      = "ui.search"
+	prefUISort        = "ui.sort"
+	prefUISortReverse = "ui.sort_reverse"
+	prefUIPageSize    = "ui.page_size"
+	prefUIAssignee    = "ui.assignee"
+	prefUIByAssignee  = "ui.filter_assignee"
+	prefUIContext     = "ui.context"
+	prefUIByContext   = "ui.filter_context"
+	prefUILocation    = "ui.location"
+	prefUIByLocation  = "ui.filter_location"
+	prefUIWaiting     = "ui.waiting"
+	prefUIByWaiting   = "ui.filter_waiting"
+	prefUIDueFilter   = "ui.due_filter"
+	prefUITab         = "ui.tab"
+	prefUIProjects    = "ui.projects"
+)
+
+// uiState - состояние интерфейса, которое сохраняется при закрытии
+// и восстанавливается при следующем запуске
+type uiState struct {
+	Width       float32
+	Height      float32
+	View        string
+	OnlyActive  bool
+	Search      string
+	Sort        task.SortMode
+	SortReverse bool
+	PageSize    int
+	// Assignee - исполнитель в фильтре, если FilterAssignee включен
+	Assignee       string
+	FilterAssignee bool
+	// Context - контекст в фильтре, если FilterContext включен
+	Context       string
+	FilterContext bool
+	// Location - место в фильтре, если FilterLocation включен
+	Location       string
+	FilterLocation bool
+	// Waiting - человек, ответа которого ждут задачи, если FilterWaiting включен
+	Waiting       string
+	FilterWaiting bool
+	// DueFilter - фильтр по сроку
+	DueFilter dueFilter
+	// Tab - открытая вкладка, Projects - метки открытых вкладок проектов
+	Tab      string
+	Projects []string
+}
+
+// loadUIState читает состояние интерфейса из настроек
+func loadUIState(prefs fyne.Preferences) uiState {
+	return uiState{
+		Width:       float32(prefs.FloatWithFallback(prefUIWidth, 800)),
+		Height:      float32(prefs.FloatWithFallback(prefUIHeight, 600)),
+		View:        prefs.StringWithFallback(prefUIView, "Список"),
+		OnlyActive:  prefs.Bool(prefUIOnlyActive),
+		Search:      prefs.String(prefUISearch),
+		Sort:        task.SortMode(prefs.Int(prefUISort)),
+		SortReverse: prefs.Bool(prefUISortReverse),
+		PageSize:    prefs.IntWithFallback(prefUIPageSize, defaultPageSize),
+
+		Assignee:       prefs.String(prefUIAssignee),
+		FilterAssignee: prefs.Bool(prefUIByAssignee),
+		Context:        prefs.String(prefUIContext),
+		FilterContext:  prefs.Bool(prefUIByContext),
+		Location:       prefs.String(prefUILocation),
+		FilterLocation: prefs.Bool(prefUIByLocation),
+		Waiting:        prefs.String(prefUIWaiting),
+		FilterWaiting:  prefs.Bool(prefUIByWaiting),
+		DueFilter:      dueFilter(prefs.String(prefUIDueFilter)),
+
+		Tab:      prefs.StringWithFallback(prefUITab, tabList),
+		Projects: prefs.StringList(prefUIProjects),
+	}
+}
+
+// save записывает состояние интерфейса в настройки
+func (s uiState) save(prefs fyne.Preferences) {
+	prefs.SetFloat(prefUIWidth, float64(s.Width))
+	prefs.SetFloat(prefUIHeight, float64(s.Height))
+	prefs.SetString(prefUIView, s.View)
+	prefs.SetBool(prefUIOnlyActive, s.OnlyActive)
+	prefs.SetString(prefUISearch, s.Search)
+	prefs.SetInt(prefUISort, int(s.Sort))
+	prefs.SetBool(prefUISortReverse, s.SortReverse)
+	prefs.SetInt(prefUIPageSize, s.PageSize)
+	prefs.SetString(prefUIAssignee, s.Assignee)
+	prefs.SetBool(prefUIByAssignee, s.FilterAssignee)
+	prefs.SetString(prefUIContext, s.Context)
+	prefs.SetBool(prefUIByContext, s.FilterContext)
+	prefs.SetString(prefUILocation, s.Location)
+	prefs.SetBool(prefUIByLocation, s.FilterLocation)
+	prefs.SetString(prefUIWaiting, s.Waiting)
+	prefs.SetBool(prefUIByWaiting, s.FilterWaiting)
+	prefs.SetString(prefUIDueFilter, string(s.DueFilter))
+	prefs.SetString(prefUITab, s.Tab)
+	prefs.SetStringList(prefUIProjects, s.Projects)
+}
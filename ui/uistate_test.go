@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+func TestUIStateDefaults(t *testing.T) {
+	a := test.NewTempApp(t)
+
+	// Без сохраненного состояния используются значения по умолчанию
+	state := loadUIState(a.Preferences())
+	assert.Equal(t, float32(800), state.Width)
+	assert.Equal(t, float32(600), state.Height)
+	assert.Equal(t, "Список", state.View)
+	assert.Equal(t, task.SortNone, state.Sort)
+	assert.Equal(t, defaultPageSize, state.PageSize)
+	assert.Equal(t, tabList, state.Tab)
+}
+
+func TestUIStateSaveAndLoad(t *testing.T) {
+	a := test.NewTempApp(t)
+
+	state := uiState{
+		Width:       1024,
+		Height:      768,
+		View:        "Таблица",
+		OnlyActive:  true,
+		Search:      "отчет",
+		Sort:        task.SortByDueDate,
+		SortReverse: true,
+		PageSize:    0,
+
+		Assignee:       "Маша",
+		FilterAssignee: true,
+		Context:        "@дом",
+		FilterContext:  true,
+		Location:       "Почта",
+		FilterLocation: true,
+		Waiting:        "Юрист",
+		FilterWaiting:  true,
+		DueFilter:      dueFilterOverdue,
+
+		Tab:      "#работа",
+		Projects: []string{"работа", "дом"},
+	}
+	state.save(a.Preferences())
+
+	// Состояние восстанавливается полностью, включая "без страниц"
+	assert.Equal(t, state, loadUIState(a.Preferences()))
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"runtime"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/update"
+)
+
+// checkForUpdates проверяет обновления в фоне и показывает новую версию в полосе уведомлений notices.
+// При ручной проверке (manual) также сообщает, что обновлений нет или проверить их не удалось
+func checkForUpdates(w fyne.Window, notices *fyne.Container, manual bool) {
+	go func() {
+		release, err := update.Latest(context.Background(), update.LatestReleaseURL)
+		fyne.Do(func() {
+			switch {
+			case err != nil:
+				slog.Warn("update check failed", "err", err)
+				if manual {
+					dialog.ShowError(err, w)
+				}
+			case update.Newer(update.Version, release.Version):
+				slog.Info("new version available", "current", update.Version, "latest", release.Version)
+				showUpdateNotice(notices, release)
+			case manual:
+				dialog.ShowInformation("Обновления", "Установлена последняя версия ("+update.Version+")", w)
+			}
+		})
+	}()
+}
+
+// showUpdateNotice показывает над списком задач сообщение о новой версии,
+// не мешая работать с задачами
+func showUpdateNotice(notices *fyne.Container, release *update.Release) {
+	changelogURL, _ := url.Parse(release.URL)
+	downloadURL, _ := url.Parse(release.DownloadURL(runtime.GOOS))
+
+	var notice *fyne.Container
+	downloadButton := widget.NewButton("Скачать", func() {
+		if err := fyne.CurrentApp().OpenURL(downloadURL); err != nil {
+			slog.Warn("failed to open download link", "err", err)
+		}
+	})
+	closeButton := newCloseButton(func() {
+		notices.Remove(notice)
+	})
+
+	notice = container.NewBorder(nil, nil, nil,
+		container.NewHBox(widget.NewHyperlink("Что нового", changelogURL), downloadButton, closeButton),
+		widget.NewLabel("Доступна новая версия "+release.Version),
+	)
+	notices.RemoveAll()
+	notices.Add(notice)
+}
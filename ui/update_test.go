@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/update"
+)
+
+func TestUpdateNotice(t *testing.T) {
+	test.NewTempApp(t)
+	notices := container.NewVBox()
+	release := &update.Release{Version: "v1.3.0", URL: "https://example.com/releases/v1.3.0"}
+
+	// Повторная проверка не дублирует сообщение
+	showUpdateNotice(notices, release)
+	showUpdateNotice(notices, release)
+	if !assert.Equal(t, 1, len(notices.Objects)) {
+		t.FailNow()
+	}
+
+	notice := notices.Objects[0].(*fyne.Container)
+	assert.Equal(t, "Доступна новая версия v1.3.0", notice.Objects[0].(*widget.Label).Text)
+
+	buttons := notice.Objects[1].(*fyne.Container).Objects
+	test.Tap(buttons[len(buttons)-1].(*widget.Button))
+	assert.Empty(t, notices.Objects)
+}
@@ -0,0 +1,246 @@
+package ui
+
+import "taskmanager/task"
+
+// taskListModel - модель представления списка задач. Хранит условия выборки
+// и задачи текущего вида, чтобы строка списка всегда однозначно соответствовала задаче
+type taskListModel struct {
+	tm         *task.TaskManager
+	search     string
+	onlyActive bool
+	// showArchived - показывать задачи из архива вместе с остальными
+	showArchived bool
+	// assignee - исполнитель, задачи которого показываются, если filterAssignee включен;
+	// пустая строка - задачи без исполнителя
+	assignee       string
+	filterAssignee bool
+	// context - контекст GTD, задачи которого показываются, если filterContext включен;
+	// пустая строка - задачи без контекста
+	context       string
+	filterContext bool
+	// location - место, задачи которого показываются, если filterLocation включен;
+	// пустая строка - задачи без места
+	location       string
+	filterLocation bool
+	// waiting - человек, ответа которого ждут показанные задачи, если filterWaiting включен;
+	// пустая строка - все задачи в ожидании
+	waiting       string
+	filterWaiting bool
+	// due - фильтр по сроку
+	due     dueFilter
+	sort    task.SortMode
+	reverse bool
+	pager   *taskPager
+}
+
+// newTaskListModel создает модель представления поверх менеджера задач
+func newTaskListModel(tm *task.TaskManager, pageSize int) *taskListModel {
+	m := &taskListModel{
+		tm:    tm,
+		pager: newTaskPager(pageSize),
+	}
+	m.Refresh()
+	return m
+}
+
+// Refresh пересчитывает задачи текущего вида: поиск, фильтр, сортировка
+func (m *taskListModel) Refresh() {
+	tasks := m.tm.Tasks()
+	if m.search != "" {
+		tasks = m.tm.SearchTasks(m.search)
+	}
+
+	if m.onlyActive || !m.showArchived {
+		var shown []*task.Task
+		for _, task := range tasks {
+			if (!m.onlyActive || !task.Completed) && (m.showArchived || !task.Archived) {
+				shown = append(shown, task)
+			}
+		}
+		tasks = shown
+	}
+
+	if m.filterAssignee {
+		var assigned []*task.Task
+		for _, task := range tasks {
+			if task.Assignee == m.assignee {
+				assigned = append(assigned, task)
+			}
+		}
+		tasks = assigned
+	}
+
+	if m.filterContext {
+		var inContext []*task.Task
+		for _, task := range tasks {
+			if task.Context == m.context {
+				inContext = append(inContext, task)
+			}
+		}
+		tasks = inContext
+	}
+
+	if m.filterLocation {
+		var atLocation []*task.Task
+		for _, task := range tasks {
+			if task.Location == m.location {
+				atLocation = append(atLocation, task)
+			}
+		}
+		tasks = atLocation
+	}
+
+	if m.filterWaiting {
+		var waiting []*task.Task
+		for _, task := range tasks {
+			if task.IsWaiting() && (m.waiting == "" || task.WaitingOn == m.waiting) {
+				waiting = append(waiting, task)
+			}
+		}
+		tasks = waiting
+	}
+
+	if m.due != dueFilterAll {
+		now := m.tm.DueZone().Now()
+		var matched []*task.Task
+		for _, task := range tasks {
+			if m.due.matches(task, now) {
+				matched = append(matched, task)
+			}
+		}
+		tasks = matched
+	}
+
+	if m.sort != task.SortNone {
+		tasks = task.SortTasks(tasks, m.sort, m.reverse)
+	}
+
+	m.pager.SetTasks(tasks)
+}
+
+// SetSearch задает строку поиска
+func (m *taskListModel) SetSearch(text string) {
+	m.search = text
+	m.Refresh()
+}
+
+// SetOnlyActive включает показ только невыполненных задач
+func (m *taskListModel) SetOnlyActive(onlyActive bool) {
+	m.onlyActive = onlyActive
+	m.Refresh()
+}
+
+// SetShowArchived включает показ задач из архива
+func (m *taskListModel) SetShowArchived(show bool) {
+	m.showArchived = show
+	m.Refresh()
+}
+
+// SetAssignee показывает только задачи исполнителя assignee; пустая строка - задачи без исполнителя
+func (m *taskListModel) SetAssignee(assignee string) {
+	m.assignee = assignee
+	m.filterAssignee = true
+	m.Refresh()
+}
+
+// ClearAssignee показывает задачи всех исполнителей
+func (m *taskListModel) ClearAssignee() {
+	m.assignee = ""
+	m.filterAssignee = false
+	m.Refresh()
+}
+
+// SetContext показывает только задачи контекста context; пустая строка - задачи без контекста
+func (m *taskListModel) SetContext(context string) {
+	m.context = context
+	m.filterContext = true
+	m.Refresh()
+}
+
+// ClearContext показывает задачи всех контекстов
+func (m *taskListModel) ClearContext() {
+	m.context = ""
+	m.filterContext = false
+	m.Refresh()
+}
+
+// SetLocation показывает только задачи в месте location; пустая строка - задачи без места
+func (m *taskListModel) SetLocation(location string) {
+	m.location = location
+	m.filterLocation = true
+	m.Refresh()
+}
+
+// ClearLocation показывает задачи во всех местах
+func (m *taskListModel) ClearLocation() {
+	m.location = ""
+	m.filterLocation = false
+	m.Refresh()
+}
+
+// SetWaiting показывает только задачи, которые ждут ответа от who; пустая строка - все задачи в ожидании
+func (m *taskListModel) SetWaiting(who string) {
+	m.waiting = who
+	m.filterWaiting = true
+	m.Refresh()
+}
+
+// ClearWaiting показывает задачи независимо от ожидания
+func (m *taskListModel) ClearWaiting() {
+	m.waiting = ""
+	m.filterWaiting = false
+	m.Refresh()
+}
+
+// SetDueFilter показывает только задачи, подходящие под фильтр по сроку
+func (m *taskListModel) SetDueFilter(f dueFilter) {
+	m.due = f
+	m.Refresh()
+}
+
+// SetSort задает порядок сортировки
+func (m *taskListModel) SetSort(mode task.SortMode) {
+	m.sort = mode
+	m.reverse = false
+	m.Refresh()
+}
+
+// ToggleSort включает сортировку, а при повторном выборе того же режима меняет направление
+func (m *taskListModel) ToggleSort(mode task.SortMode) {
+	if m.sort == mode {
+		m.reverse = !m.reverse
+	} else {
+		m.sort = mode
+		m.reverse = false
+	}
+	m.Refresh()
+}
+
+// Tasks возвращает все задачи текущего вида со всех страниц
+func (m *taskListModel) Tasks() []*task.Task {
+	return m.pager.tasks
+}
+
+// Len возвращает количество строк на текущей странице
+func (m *taskListModel) Len() int {
+	return len(m.pager.PageTasks())
+}
+
+// TaskAt возвращает задачу, отображаемую в строке row, или nil
+func (m *taskListModel) TaskAt(row int) *task.Task {
+	tasks := m.pager.PageTasks()
+	if row < 0 || row >= len(tasks) {
+		return nil
+	}
+	return tasks[row]
+}
+
+// RowOf возвращает номер строки задачи с указанным ID или -1, если ее нет на странице
+func (m *taskListModel) RowOf(id int) int {
+	for row, task := range m.pager.PageTasks() {
+		if task.ID == id {
+			return row
+		}
+	}
+	return -1
+}
@@ -0,0 +1,194 @@
+package ui
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/storage"
+	"taskmanager/task"
+)
+
+// newTestManager создает менеджер задач с файлом во временном каталоге теста
+func newTestManager(t *testing.T) *task.TaskManager {
+	return task.NewTaskManager(storage.NewFile(filepath.Join(t.TempDir(), "tasks.json")))
+}
+
+// mustAddTask добавляет задачу и завершает тест при ошибке
+func mustAddTask(t *testing.T, tm *task.TaskManager, title, description string, priority int, dueDate time.Time) *task.Task {
+	t.Helper()
+	added, err := tm.AddTask(title, description, priority, dueDate)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return added
+}
+
+func TestTaskListModelFilters(t *testing.T) {
+	tm := newTestManager(t)
+
+	tm.AddTask("Buy milk", "Groceries", 1, time.Now())
+	done := mustAddTask(t, tm, "Buy bread", "Groceries", 2, time.Now())
+	tm.AddTask("Call Mom", "Birthday", 3, time.Now())
+	tm.ToggleTaskCompletion(done.ID)
+
+	model := newTaskListModel(tm, defaultPageSize)
+	assert.Equal(t, 3, model.Len())
+
+	// Поиск и фильтр по статусу работают вместе
+	model.SetSearch("buy")
+	assert.Equal(t, 2, model.Len())
+	model.SetOnlyActive(true)
+	assert.Equal(t, 1, model.Len())
+	assert.Equal(t, "Buy milk", model.TaskAt(0).Title)
+
+	// Сброс поиска оставляет только фильтр
+	model.SetSearch("")
+	assert.Equal(t, 2, model.Len())
+	assert.Nil(t, model.TaskAt(5))
+
+	// Видимые задачи - выборка целиком, а не только текущая страница
+	model.pager.SetPageSize(1)
+	assert.Len(t, model.Tasks(), 2)
+}
+
+func TestTaskListModelArchive(t *testing.T) {
+	tm := newTestManager(t)
+	mustAddTask(t, tm, "Buy milk", "", 1, time.Now())
+	archived := mustAddTask(t, tm, "Old task", "", 1, time.Now())
+	assert.NoError(t, tm.SetTaskArchived(archived.ID, true))
+
+	// Задачи из архива скрыты, пока их не покажут явно
+	model := newTaskListModel(tm, defaultPageSize)
+	assert.Equal(t, 1, model.Len())
+	model.SetShowArchived(true)
+	assert.Equal(t, 2, model.Len())
+	model.SetOnlyActive(true)
+	assert.Equal(t, 2, model.Len())
+}
+
+func TestTaskListModelAssigneeFilter(t *testing.T) {
+	tm := newTestManager(t)
+
+	dishes := mustAddTask(t, tm, "Помыть посуду", "", 2, time.Now())
+	trash := mustAddTask(t, tm, "Вынести мусор", "", 2, time.Now())
+	mustAddTask(t, tm, "Купить хлеб", "", 2, time.Now())
+	tm.SetTaskAssignee(dishes.ID, "Маша")
+	tm.SetTaskAssignee(trash.ID, "Петя")
+
+	model := newTaskListModel(tm, defaultPageSize)
+	model.SetAssignee("Маша")
+	assert.Equal(t, 1, model.Len())
+	assert.Equal(t, dishes.ID, model.TaskAt(0).ID)
+
+	// Пустой исполнитель - задачи, которые никому не назначены
+	model.SetAssignee("")
+	assert.Equal(t, 1, model.Len())
+	assert.Equal(t, "Купить хлеб", model.TaskAt(0).Title)
+
+	model.ClearAssignee()
+	assert.Equal(t, 3, model.Len())
+
+	// Сначала люди из настроек, затем остальные назначенные
+	assert.Equal(t, []string{"Петя", "Мама", "Маша"}, assigneeChoices([]string{"Петя", "Мама"}, tm.Assignees()))
+}
+
+func TestTaskListModelContextFilter(t *testing.T) {
+	tm := newTestManager(t)
+
+	report := mustAddTask(t, tm, "Написать отчет", "", 2, time.Now())
+	bread := mustAddTask(t, tm, "Купить хлеб", "", 2, time.Now())
+	mustAddTask(t, tm, "Подумать об отпуске", "", 2, time.Now())
+	tm.SetTaskContext(report.ID, "@работа")
+	tm.SetTaskContext(bread.ID, "@поручения")
+
+	model := newTaskListModel(tm, defaultPageSize)
+	model.SetContext("@работа")
+	assert.Equal(t, 1, model.Len())
+	assert.Equal(t, report.ID, model.TaskAt(0).ID)
+
+	// Пустой контекст - задачи, для которых он не задан
+	model.SetContext("")
+	assert.Equal(t, 1, model.Len())
+	assert.Equal(t, "Подумать об отпуске", model.TaskAt(0).Title)
+
+	model.ClearContext()
+	assert.Equal(t, 3, model.Len())
+}
+
+func TestTaskListModelLocationFilter(t *testing.T) {
+	tm := newTestManager(t)
+
+	bread := mustAddTask(t, tm, "Купить хлеб", "", 2, time.Now())
+	mustAddTask(t, tm, "Позвонить маме", "", 2, time.Now())
+	tm.SetTaskLocation(bread.ID, "Пекарня")
+
+	model := newTaskListModel(tm, defaultPageSize)
+	model.SetLocation("Пекарня")
+	assert.Equal(t, 1, model.Len())
+	assert.Equal(t, bread.ID, model.TaskAt(0).ID)
+
+	model.SetLocation("")
+	assert.Equal(t, "Позвонить маме", model.TaskAt(0).Title)
+
+	model.ClearLocation()
+	assert.Equal(t, 2, model.Len())
+}
+
+func TestTaskListModelRowMapping(t *testing.T) {
+	tm := newTestManager(t)
+
+	now := time.Now()
+	low := mustAddTask(t, tm, "Low", "Description", 1, now.Add(48*time.Hour))
+	high := mustAddTask(t, tm, "High", "Description", 3, now.Add(24*time.Hour))
+	medium := mustAddTask(t, tm, "Medium", "Description", 2, now)
+
+	model := newTaskListModel(tm, defaultPageSize)
+
+	// После сортировки строки указывают на правильные задачи
+	model.SetSort(task.SortByPriority)
+	assert.Equal(t, high.ID, model.TaskAt(0).ID)
+	assert.Equal(t, 2, model.RowOf(low.ID))
+
+	model.SetSort(task.SortByDueDate)
+	assert.Equal(t, medium.ID, model.TaskAt(0).ID)
+	assert.Equal(t, 1, model.RowOf(high.ID))
+
+	// Сортировка представления не меняет порядок задач в менеджере
+	assert.Equal(t, low.ID, tm.Tasks()[0].ID)
+
+	// Задачи нет в текущем виде
+	model.SetSearch("High")
+	assert.Equal(t, -1, model.RowOf(low.ID))
+}
+
+func TestTaskListModelToggleSort(t *testing.T) {
+	tm := newTestManager(t)
+
+	tm.AddTask("beta", "Description", 1, time.Now())
+	tm.AddTask("Alpha", "Description", 2, time.Now())
+	done := mustAddTask(t, tm, "gamma", "Description", 3, time.Now())
+	tm.ToggleTaskCompletion(done.ID)
+
+	model := newTaskListModel(tm, defaultPageSize)
+
+	// Сортировка по названию без учета регистра
+	model.ToggleSort(task.SortByTitle)
+	assert.Equal(t, "Alpha", model.TaskAt(0).Title)
+	assert.Equal(t, "gamma", model.TaskAt(2).Title)
+
+	// Повторный выбор меняет направление
+	model.ToggleSort(task.SortByTitle)
+	assert.Equal(t, "gamma", model.TaskAt(0).Title)
+
+	// Новый режим начинается с прямого порядка: активные задачи первыми
+	model.ToggleSort(task.SortByStatus)
+	assert.False(t, model.TaskAt(0).Completed)
+	assert.True(t, model.TaskAt(2).Completed)
+
+	// Недавно измененные задачи первыми
+	model.ToggleSort(task.SortByUpdated)
+	assert.Equal(t, "gamma", model.TaskAt(0).Title)
+}
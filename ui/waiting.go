@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/task"
+)
+
+// Варианты фильтра по ожиданию, кроме людей
+const (
+	waitingAll = "Ожидание: все"
+	waitingAny = "Ждем ответа"
+)
+
+// waitingPrefix - начало варианта фильтра по человеку: «Ждем: Маша»
+const waitingPrefix = "Ждем: "
+
+// Напоминания о задачах в ожидании
+const (
+	prefNudgeDays = "waiting.nudge_days" // через сколько дней без изменений напомнить, 0 - не напоминать
+	prefNudged    = "waiting.nudged"     // задачи, о которых уже напомнили: UUID и время их изменения
+)
+
+// defaultNudgeDays - через сколько дней без изменений напомнить о задаче в ожидании
+const defaultNudgeDays = 3
+
+// nudgeDays читает из настроек, через сколько дней напоминать о задачах в ожидании
+func nudgeDays(prefs fyne.Preferences) int {
+	return prefs.IntWithFallback(prefNudgeDays, defaultNudgeDays)
+}
+
+// waitingOptions возвращает варианты фильтра по ожиданию: все задачи, все ждущие задачи
+// и задачи, ждущие каждого из людей contacts
+func waitingOptions(contacts []string) []string {
+	options := []string{waitingAll, waitingAny}
+	for _, who := range contacts {
+		options = append(options, waitingPrefix+who)
+	}
+	return options
+}
+
+// formatNudge описывает задачу, которая долго ждет ответа
+func formatNudge(t *task.Task, now time.Time) string {
+	days := int(now.Sub(t.UpdatedAt).Hours() / 24)
+	return fmt.Sprintf("%s — ждем: %s, без изменений %d %s", t.Title, t.WaitingOn, days, pluralDays(days))
+}
+
+// nudgeHost напоминает о задачах в ожидании, которые долго не меняются: пора спросить
+// человека, как дела. О задаче напоминается один раз, пока она снова не изменится
+type nudgeHost struct {
+	a       fyne.App
+	w       fyne.Window
+	prefs   fyne.Preferences
+	tm      *task.TaskManager
+	notices *fyne.Container
+	nudged  map[string]time.Time // UUID задачи - время ее изменения, когда о ней напомнили
+}
+
+func newNudgeHost(a fyne.App, w fyne.Window, prefs fyne.Preferences, tm *task.TaskManager) *nudgeHost {
+	h := &nudgeHost{a: a, w: w, prefs: prefs, tm: tm, notices: container.NewVBox(), nudged: make(map[string]time.Time)}
+	if data := prefs.String(prefNudged); data != "" {
+		if err := json.Unmarshal([]byte(data), &h.nudged); err != nil {
+			slog.Warn("failed to read waiting nudges", "err", err)
+		}
+	}
+	return h
+}
+
+// Check напоминает о задачах, которые ждут и не менялись заданное число дней.
+// В тихие часы напоминание ждет их конца
+func (h *nudgeHost) Check() {
+	days := nudgeDays(h.prefs)
+	now := time.Now()
+	if days <= 0 || quietHours(h.prefs).Active(now) {
+		return
+	}
+	var due []*task.Task
+	nudged := make(map[string]time.Time)
+	for _, t := range h.tm.Tasks() {
+		if !t.NeedsNudge(now, time.Duration(days)*24*time.Hour) {
+			continue
+		}
+		if !h.nudged[t.UUID].Equal(t.UpdatedAt) {
+			due = append(due, t)
+		}
+		nudged[t.UUID] = t.UpdatedAt
+	}
+	// Задачи, которые дождались ответа или изменились, забываются
+	changed := len(nudged) != len(h.nudged)
+	h.nudged = nudged
+	if changed || len(due) > 0 {
+		data, _ := json.Marshal(h.nudged)
+		h.prefs.SetString(prefNudged, string(data))
+	}
+	if len(due) == 0 {
+		return
+	}
+
+	lines := make([]string, len(due))
+	for i, t := range due {
+		lines[i] = formatNudge(t, now)
+		h.addNotice(t, lines[i])
+	}
+	slog.Info("waiting tasks nudge", "count", len(due))
+	h.a.SendNotification(fyne.NewNotification("Пора напомнить", strings.Join(lines, "\n")))
+}
+
+// addNotice показывает задачу в полосе уведомлений; ответ можно отметить сразу
+func (h *nudgeHost) addNotice(t *task.Task, text string) {
+	uuid := t.UUID
+	var notice *fyne.Container
+	dismiss := func() { h.notices.Remove(notice) }
+	answered := widget.NewButton("Ответ получен", func() {
+		dismiss()
+		if current := h.tm.GetTaskByUUID(uuid); current != nil {
+			if err := h.tm.SetTaskWaitingOn(current.ID, ""); err != nil {
+				slog.Error("failed to update waiting task", "err", err)
+				dialog.ShowError(err, h.w)
+			}
+		}
+	})
+	notice = container.NewBorder(nil, nil, nil, container.NewHBox(answered, newCloseButton(dismiss)), widget.NewLabel("🔔 "+text))
+	h.notices.Add(notice)
+}
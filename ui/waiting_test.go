@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNudgeHost(t *testing.T) {
+	a := test.NewTempApp(t)
+	w := a.NewWindow("")
+	tm := newTestManager(t)
+	contract := mustAddTask(t, tm, "Договор", "", 2, time.Now())
+	require.NoError(t, tm.SetTaskWaitingOn(contract.ID, "Юрист"))
+	fresh := mustAddTask(t, tm, "Счет", "", 2, time.Now())
+	require.NoError(t, tm.SetTaskWaitingOn(fresh.ID, "Бухгалтерия"))
+	contract.UpdatedAt = time.Now().Add(-4 * 24 * time.Hour)
+
+	host := newNudgeHost(a, w, a.Preferences(), tm)
+	host.Check()
+	if assert.Len(t, host.notices.Objects, 1) {
+		label := host.notices.Objects[0].(*fyne.Container).Objects[0].(*widget.Label)
+		assert.Equal(t, "🔔 Договор — ждем: Юрист, без изменений 4 дня", label.Text)
+	}
+	assert.Contains(t, a.Preferences().String(prefNudged), contract.UUID)
+
+	// О той же задаче не напоминается снова, пока она не изменится
+	host = newNudgeHost(a, w, a.Preferences(), tm)
+	host.Check()
+	assert.Empty(t, host.notices.Objects)
+
+	// «Ответ получен» снимает ожидание
+	contract.UpdatedAt = time.Now().Add(-5 * 24 * time.Hour)
+	host.Check()
+	require.Len(t, host.notices.Objects, 1)
+	buttons := host.notices.Objects[0].(*fyne.Container).Objects[1].(*fyne.Container)
+	test.Tap(buttons.Objects[0].(*widget.Button))
+	assert.Empty(t, host.notices.Objects)
+	assert.False(t, tm.GetTask(contract.ID).IsWaiting())
+
+	// 0 дней - напоминания выключены
+	a.Preferences().SetInt(prefNudgeDays, 0)
+	require.NoError(t, tm.SetTaskWaitingOn(contract.ID, "Юрист"))
+	contract.UpdatedAt = time.Now().Add(-30 * 24 * time.Hour)
+	host.Check()
+	assert.Empty(t, host.notices.Objects)
+}
+
+func TestTaskListModelWaiting(t *testing.T) {
+	tm := newTestManager(t)
+	contract := mustAddTask(t, tm, "Договор", "", 2, time.Now())
+	invoice := mustAddTask(t, tm, "Счет", "", 2, time.Now())
+	mustAddTask(t, tm, "Отчет", "", 2, time.Now())
+	require.NoError(t, tm.SetTaskWaitingOn(contract.ID, "Юрист"))
+	require.NoError(t, tm.SetTaskWaitingOn(invoice.ID, "Бухгалтерия"))
+
+	assert.Equal(t, []string{waitingAll, waitingAny, "Ждем: Бухгалтерия", "Ждем: Юрист"}, waitingOptions(tm.WaitingContacts()))
+
+	model := newTaskListModel(tm, 0)
+	model.SetWaiting("")
+	assert.Equal(t, 2, model.Len())
+	model.SetWaiting("Юрист")
+	if assert.Equal(t, 1, model.Len()) {
+		assert.Equal(t, "Договор", model.TaskAt(0).Title)
+	}
+	model.ClearWaiting()
+	assert.Equal(t, 3, model.Len())
+}
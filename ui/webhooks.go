@@ -0,0 +1,227 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/agenda"
+	"taskmanager/task"
+	"taskmanager/webhooks"
+)
+
+// Настройки уведомлений в чаты
+const (
+	prefWebhooks      = "webhooks.targets"
+	prefWebhooksState = "webhooks.state"
+)
+
+// webhookHost отправляет повестку и сообщения о просрочках в чаты из настроек профиля
+type webhookHost struct {
+	prefs  fyne.Preferences
+	tm     *task.TaskManager
+	client *http.Client
+	state  webhooks.State
+}
+
+// newWebhookHost создает отправку уведомлений и читает, что уже отправлено
+func newWebhookHost(prefs fyne.Preferences, tm *task.TaskManager) *webhookHost {
+	h := &webhookHost{prefs: prefs, tm: tm, client: &http.Client{Timeout: 30 * time.Second}}
+	if data := prefs.String(prefWebhooksState); data != "" {
+		if err := json.Unmarshal([]byte(data), &h.state); err != nil {
+			slog.Warn("failed to read sent webhook notifications", "err", err)
+		}
+	}
+	return h
+}
+
+// targets возвращает получателей из настроек
+func (h *webhookHost) targets() []webhooks.Target {
+	list, err := webhooks.Parse(h.prefs.String(prefWebhooks))
+	if err != nil {
+		slog.Warn("failed to read webhook targets", "err", err)
+	}
+	return list
+}
+
+// Check отправляет в фоне сообщения, которым пришло время. Сообщение, которое не удалось
+// отправить, не повторяется, как и уведомления о напоминаниях
+func (h *webhookHost) Check() {
+	targets := h.targets()
+	if len(targets) == 0 {
+		return
+	}
+	messages, err := h.state.Due(targets, h.tm.Tasks(), h.tm.DueZone().Now(), agendaSections(h.prefs))
+	if err != nil {
+		slog.Warn("failed to render webhook message", "err", err)
+	}
+	if data, _ := json.Marshal(h.state); string(data) != h.prefs.String(prefWebhooksState) {
+		h.prefs.SetString(prefWebhooksState, string(data))
+	}
+
+	for _, message := range messages {
+		go func() {
+			if err := webhooks.Post(context.Background(), h.client, message.Target, message.Text); err != nil {
+				slog.Error("failed to send webhook notification", "target", message.Target.Describe(), "err", err)
+				return
+			}
+			slog.Info("webhook notification sent", "target", message.Target.Describe())
+		}()
+	}
+}
+
+// showWebhooksDialog настраивает чаты, куда отправляются повестка и сообщения о просрочках
+func showWebhooksDialog(w fyne.Window, prefs fyne.Preferences, tm *task.TaskManager, host *webhookHost) {
+	edited := host.targets()
+
+	var list *widget.List
+	list = widget.NewList(
+		func() int { return len(edited) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil,
+				container.NewHBox(widget.NewButton("Изменить…", nil), widget.NewButton("Удалить", nil)),
+				widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			row := item.(*fyne.Container)
+			row.Objects[0].(*widget.Label).SetText(edited[id].Describe())
+			buttons := row.Objects[1].(*fyne.Container).Objects
+			buttons[0].(*widget.Button).OnTapped = func() {
+				showWebhookForm(w, tm, prefs, host, edited[id], func(target webhooks.Target) {
+					edited[id] = target
+					list.Refresh()
+				})
+			}
+			buttons[1].(*widget.Button).OnTapped = func() {
+				edited = append(edited[:id:id], edited[id+1:]...)
+				list.Refresh()
+			}
+		},
+	)
+
+	addButton := widget.NewButton("Добавить…", func() {
+		showWebhookForm(w, tm, prefs, host, webhooks.NewTarget(webhooks.Slack, ""), func(target webhooks.Target) {
+			edited = append(edited, target)
+			list.Refresh()
+		})
+	})
+	content := container.NewBorder(
+		widget.NewLabel("Повестка дня и сообщения о просроченных задачах во входящие вебхуки Slack и Discord"),
+		addButton, nil, nil, list)
+	d := dialog.NewCustomConfirm("Уведомления в чаты", "Сохранить", "Отмена", content, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		prefs.SetString(prefWebhooks, webhooks.Marshal(edited))
+		host.Check()
+	}, w)
+	d.Resize(dialogSize(w, fyne.NewSize(640, 400)))
+	d.Show()
+}
+
+// showWebhookForm изменяет получателя target; onSave получает проверенного получателя
+func showWebhookForm(w fyne.Window, tm *task.TaskManager, prefs fyne.Preferences, host *webhookHost, target webhooks.Target, onSave func(webhooks.Target)) {
+	serviceTitles := make([]string, len(webhooks.Services))
+	for i, service := range webhooks.Services {
+		serviceTitles[i] = service.Title()
+	}
+	serviceSelect := widget.NewSelect(serviceTitles, nil)
+	serviceSelect.SetSelected(target.Service.Title())
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("необязательно")
+	nameEntry.SetText(target.Name)
+	urlEntry := widget.NewEntry()
+	urlEntry.SetPlaceHolder("https://hooks.slack.com/services/…")
+	urlEntry.SetText(target.URL)
+	urlEntry.Validator = func(text string) error {
+		return webhooks.Target{Service: webhooks.Slack, URL: strings.TrimSpace(text)}.Validate()
+	}
+
+	agendaCheck := widget.NewCheck("Повестка дня", nil)
+	agendaCheck.SetChecked(target.Agenda)
+	agendaAtEntry := widget.NewEntry()
+	agendaAtEntry.SetPlaceHolder("09:00")
+	agendaAtEntry.SetText(target.AgendaAt)
+	agendaTemplateEntry := widget.NewMultiLineEntry()
+	agendaTemplateEntry.SetPlaceHolder(webhooks.DefaultAgendaTemplate)
+	agendaTemplateEntry.SetText(target.AgendaTemplate)
+
+	overdueCheck := widget.NewCheck("Сообщать о просроченных задачах", nil)
+	overdueCheck.SetChecked(target.Overdue)
+	overdueTemplateEntry := widget.NewMultiLineEntry()
+	overdueTemplateEntry.SetPlaceHolder(webhooks.DefaultOverdueTemplate)
+	overdueTemplateEntry.SetText(target.OverdueTemplate)
+
+	read := func() webhooks.Target {
+		edited := target
+		for _, service := range webhooks.Services {
+			if service.Title() == serviceSelect.Selected {
+				edited.Service = service
+			}
+		}
+		edited.Name = strings.TrimSpace(nameEntry.Text)
+		edited.URL = strings.TrimSpace(urlEntry.Text)
+		edited.Agenda = agendaCheck.Checked
+		edited.AgendaAt = strings.TrimSpace(agendaAtEntry.Text)
+		edited.AgendaTemplate = agendaTemplateEntry.Text
+		edited.Overdue = overdueCheck.Checked
+		edited.OverdueTemplate = overdueTemplateEntry.Text
+		return edited
+	}
+
+	testButton := widget.NewButton("Отправить пробное сообщение", func() {
+		edited := read()
+		if err := edited.Validate(); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		text, err := edited.AgendaMessage(agenda.Build(tm.Tasks(), tm.DueZone().Now(), agendaSections(prefs)))
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		go func() {
+			err := webhooks.Post(context.Background(), host.client, edited, text)
+			fyne.Do(func() {
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				dialog.ShowInformation("Уведомления в чаты", "Сообщение отправлено", w)
+			})
+		}()
+	})
+
+	items := []*widget.FormItem{
+		{Text: "Чат", Widget: serviceSelect},
+		{Text: "Название", Widget: nameEntry},
+		{Text: "Адрес вебхука", Widget: urlEntry},
+		{Text: "", Widget: agendaCheck},
+		{Text: "Время повестки", Widget: agendaAtEntry},
+		{Text: "Шаблон повестки", Widget: agendaTemplateEntry, HintText: "{{.Title}}, {{.Text}}, {{.Len}}, {{range .Groups}}…{{end}}"},
+		{Text: "", Widget: overdueCheck},
+		{Text: "Шаблон просрочки", Widget: overdueTemplateEntry, HintText: "{{.Task.Title}}, {{.Due}}, {{.Days}}, {{priority .Task.Priority}}"},
+		{Text: "", Widget: testButton},
+	}
+	form := dialog.NewForm("Чат для уведомлений", "Готово", "Отмена", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		edited := read()
+		if err := edited.Validate(); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		onSave(edited)
+	}, w)
+	form.Resize(dialogSize(w, fyne.NewSize(560, 0)))
+	form.Show()
+}
@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"taskmanager/webhooks"
+)
+
+func TestWebhookHostCheck(t *testing.T) {
+	posted := make(chan string, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		posted <- string(body)
+	}))
+	defer server.Close()
+
+	a := test.NewTempApp(t)
+	prefs := a.Preferences()
+	tm := newTestManager(t)
+	mustAddTask(t, tm, "Сдать отчет", "", 2, time.Now())
+	overdue := tm.Tasks()[0]
+	overdue.DueDate = time.Now().AddDate(0, 0, -2)
+
+	target := webhooks.NewTarget(webhooks.Slack, server.URL)
+	target.Overdue = true
+	prefs.SetString(prefWebhooks, webhooks.Marshal([]webhooks.Target{target}))
+
+	newWebhookHost(prefs, tm).Check()
+	select {
+	case body := <-posted:
+		assert.Contains(t, body, "Сдать отчет")
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "webhook was not called")
+	}
+
+	// Отправленное запоминается в настройках и после перезапуска не повторяется
+	newWebhookHost(prefs, tm).Check()
+	select {
+	case body := <-posted:
+		assert.Fail(t, "repeated notification", body)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
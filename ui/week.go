@@ -0,0 +1,198 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/task"
+)
+
+// weekdayNames - короткие названия дней недели с понедельника
+var weekdayNames = []string{"Пн", "Вт", "Ср", "Чт", "Пт", "Сб", "Вс"}
+
+// weekStart возвращает понедельник недели дня day
+func weekStart(day time.Time) time.Time {
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	return start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+}
+
+// weekDays возвращает семь дней недели, начинающейся в понедельник start
+func weekDays(start time.Time) []time.Time {
+	days := make([]time.Time, 7)
+	for i := range days {
+		days[i] = start.AddDate(0, 0, i)
+	}
+	return days
+}
+
+// weekTitle подписывает неделю номером ISO и датами: «Неделя 42: 12.10 – 18.10.2026»
+func weekTitle(start time.Time) string {
+	_, week := start.ISOWeek()
+	end := start.AddDate(0, 0, 6)
+	return fmt.Sprintf("Неделя %d: %s – %s", week, start.Format("02.01"), end.Format("02.01.2006"))
+}
+
+// rescheduleTask переносит срок задачи на день day в поясе сроков
+func rescheduleTask(tm *task.TaskManager, id int, day time.Time) error {
+	t := tm.GetTask(id)
+	if t == nil {
+		return fmt.Errorf("%w: id %d", task.ErrNotFound, id)
+	}
+	due, err := tm.DueZone().ParseDueDate(day.Format(task.DueDateLayout))
+	if err != nil {
+		return err
+	}
+	if due.Format(task.DueDateLayout) == t.DueDay() {
+		return nil
+	}
+	return tm.UpdateTask(t.ID, t.Title, t.Description, t.Priority, due, t.Completed)
+}
+
+// moveTask переносит срок задачи на день day и предлагает отменить перенос в полосе уведомлений
+func moveTask(w fyne.Window, notices *fyne.Container, tm *task.TaskManager, id int, day time.Time) {
+	t := tm.GetTask(id)
+	if t == nil {
+		return
+	}
+	// GetTask отдает саму задачу, поэтому прежние даты запоминаются до переноса
+	title, start, due := t.Title, t.StartDate, t.DueDate
+	if err := rescheduleTask(tm, id, day); err != nil {
+		dialog.ShowError(err, w)
+		return
+	}
+	if t.DueDate.Equal(due) {
+		return
+	}
+	showUndoNotice(w, notices, fmt.Sprintf("Срок задачи «%s» перенесен на %s", title, day.Format("02.01")), func() error {
+		return tm.SetTaskDates(id, start, due)
+	})
+}
+
+// dropIndex возвращает номер объекта из objects, над которым отпустили перетаскивание, или -1
+func dropIndex(objects []fyne.CanvasObject, pos fyne.Position) int {
+	driver := fyne.CurrentApp().Driver()
+	for i, object := range objects {
+		origin := driver.AbsolutePositionForObject(object)
+		size := object.Size()
+		if pos.X >= origin.X && pos.X < origin.X+size.Width && pos.Y >= origin.Y && pos.Y < origin.Y+size.Height {
+			return i
+		}
+	}
+	return -1
+}
+
+// dayCard - карточка задачи в дне календаря или недели: нажатие открывает задачу,
+// перетаскивание в другой день переносит срок
+type dayCard struct {
+	widget.Button
+	pos    fyne.Position
+	onDrop func(pos fyne.Position)
+}
+
+func newDayCard(t *task.Task, openTask func(id int), onDrop func(id int, pos fyne.Position)) *dayCard {
+	id := t.ID
+	card := &dayCard{onDrop: func(pos fyne.Position) { onDrop(id, pos) }}
+	card.Text = t.IconTitle()
+	card.OnTapped = func() { openTask(id) }
+	card.Alignment = widget.ButtonAlignLeading
+	if t.Priority == 3 && !t.Completed {
+		card.Importance = widget.HighImportance
+	}
+	card.ExtendBaseWidget(card)
+	return card
+}
+
+func (c *dayCard) Dragged(event *fyne.DragEvent) {
+	c.pos = event.AbsolutePosition
+}
+
+func (c *dayCard) DragEnd() {
+	c.onDrop(c.pos)
+}
+
+// dayCell - клетка дня в календаре или колонка недели: двойное нажатие по свободному
+// месту добавляет задачу со сроком в этот день
+type dayCell struct {
+	widget.BaseWidget
+	content     fyne.CanvasObject
+	onDoubleTap func()
+}
+
+func newDayCell(content fyne.CanvasObject, onDoubleTap func()) *dayCell {
+	cell := &dayCell{content: content, onDoubleTap: onDoubleTap}
+	cell.ExtendBaseWidget(cell)
+	return cell
+}
+
+func (c *dayCell) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(c.content)
+}
+
+func (c *dayCell) DoubleTapped(*fyne.PointEvent) {
+	c.onDoubleTap()
+}
+
+// newWeekView создает вкладку недели: семь колонок с задачами по дням срока.
+// Задачу можно перетащить в другой день, чтобы перенести срок, а двойное нажатие
+// по свободному месту дня добавляет задачу на этот день
+func newWeekView(w fyne.Window, tm *task.TaskManager, notices *fyne.Container, capacity func() int, openTask func(id int), addTask func(day time.Time)) (view fyne.CanvasObject, refresh func()) {
+	start := weekStart(tm.DueZone().Now())
+	title := widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	columns := container.NewGridWithColumns(7)
+	var days []time.Time
+
+	// Карточка, отпущенная над колонкой дня, переносит задачу на этот день
+	drop := func(id int, pos fyne.Position) {
+		if i := dropIndex(columns.Objects, pos); i >= 0 {
+			moveTask(w, notices, tm, id, days[i])
+		}
+	}
+
+	refresh = func() {
+		title.SetText(weekTitle(start))
+		tasks := visibleTasks(tm)
+		byDay := tasksByDay(tasks)
+		today := tm.DueZone().Now().Format(task.DueDateLayout)
+		days = weekDays(start)
+		var cells []fyne.CanvasObject
+		for i, day := range days {
+			key := day.Format(task.DueDateLayout)
+			header := widget.NewLabelWithStyle(fmt.Sprintf("%s %s", weekdayNames[i], day.Format("02.01")), fyne.TextAlignCenter, fyne.TextStyle{Bold: key == today})
+			if key == today {
+				header.Importance = widget.HighImportance
+			}
+			cards := container.NewVBox()
+			if minutes := dayWorkload(tasks, key); minutes > 0 {
+				cards.Add(newWorkloadBar(minutes, capacity()))
+			}
+			for _, t := range byDay[key] {
+				cards.Add(newDayCard(t, openTask, drop))
+			}
+			cells = append(cells, newDayCell(container.NewBorder(header, nil, nil, nil, container.NewVScroll(cards)), func() { addTask(day) }))
+		}
+		columns.Objects = cells
+		columns.Refresh()
+	}
+	shift := func(weeks int) {
+		start = start.AddDate(0, 0, 7*weeks)
+		refresh()
+	}
+	backButton, nextButton := newStepButtons(func() { shift(-1) }, func() { shift(1) })
+	header := newReadingBorder(nil, nil,
+		backButton,
+		container.NewHBox(
+			widget.NewButton("Сегодня", func() {
+				start = weekStart(tm.DueZone().Now())
+				refresh()
+			}),
+			nextButton,
+		),
+		title)
+	refresh()
+	return container.NewBorder(header, nil, nil, nil, columns), refresh
+}
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeekDays(t *testing.T) {
+	// 16.10.2026 - пятница 42-й недели
+	start := weekStart(time.Date(2026, 10, 16, 22, 0, 0, 0, time.Local))
+	assert.Equal(t, "2026-10-12", start.Format("2006-01-02"))
+	assert.Equal(t, start, weekStart(start))
+
+	days := weekDays(start)
+	assert.Len(t, days, 7)
+	assert.Equal(t, time.Sunday, days[6].Weekday())
+	assert.Equal(t, "Неделя 42: 12.10 – 18.10.2026", weekTitle(start))
+
+	// Неделя на стыке лет относится к году по ISO
+	assert.Equal(t, "Неделя 53: 28.12 – 03.01.2027", weekTitle(weekStart(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC))))
+}
+
+func TestRescheduleTask(t *testing.T) {
+	test.NewTempApp(t)
+	tm := newTestManager(t)
+	report := mustAddTask(t, tm, "Отчет", "", 2, time.Now())
+	monday := weekStart(time.Now()).AddDate(0, 0, 7)
+
+	assert.NoError(t, rescheduleTask(tm, report.ID, monday))
+	assert.Equal(t, monday.Format("2006-01-02"), tm.GetTask(report.ID).DueDay())
+	assert.Equal(t, "Отчет", tm.GetTask(report.ID).Title)
+	assert.Error(t, rescheduleTask(tm, 999, monday))
+
+	view, refresh := newWeekView(test.NewWindow(nil), tm, container.NewVBox(), func() int { return defaultWorkloadCapacity }, func(int) {}, func(time.Time) {})
+	assert.NotNil(t, view)
+	refresh()
+}
+
+func TestMoveTaskUndo(t *testing.T) {
+	test.NewTempApp(t)
+	tm := newTestManager(t)
+	w := test.NewWindow(nil)
+	notices := container.NewVBox()
+	report := mustAddTask(t, tm, "Отчет", "", 2, time.Now())
+	before := tm.GetTask(report.ID).DueDay()
+	monday := weekStart(time.Now()).AddDate(0, 0, 7)
+
+	moveTask(w, notices, tm, report.ID, monday)
+	assert.Equal(t, monday.Format("2006-01-02"), tm.GetTask(report.ID).DueDay())
+	assert.Len(t, notices.Objects, 1)
+
+	test.Tap(findText(notices, "Отменить").(*widget.Button))
+	assert.Equal(t, before, tm.GetTask(report.ID).DueDay())
+	assert.Empty(t, notices.Objects)
+
+	// Перенос на тот же день ничего не меняет, и отменять нечего
+	moveTask(w, notices, tm, report.ID, tm.GetTask(report.ID).DueDate)
+	assert.Empty(t, notices.Objects)
+}
+
+func TestDayCellDoubleTap(t *testing.T) {
+	test.NewTempApp(t)
+	var added time.Time
+	day := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	cell := newDayCell(widget.NewLabel("20"), func() { added = day })
+	test.DoubleTap(cell)
+	assert.Equal(t, day, added)
+}
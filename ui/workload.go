@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"taskmanager/task"
+)
+
+// prefWorkloadCapacity - сколько минут работы помещается в день
+const prefWorkloadCapacity = "workload.capacity_minutes"
+
+// defaultWorkloadCapacity - рабочий день по умолчанию, 8 часов
+const defaultWorkloadCapacity = 8 * 60
+
+// workloadCapacity читает вместимость дня из настроек
+func workloadCapacity(prefs fyne.Preferences) int {
+	return prefs.IntWithFallback(prefWorkloadCapacity, defaultWorkloadCapacity)
+}
+
+// dayWorkload суммирует оценки невыполненных задач со сроком в день date (2006-01-02)
+func dayWorkload(tasks []*task.Task, date string) int {
+	minutes := 0
+	for _, t := range tasks {
+		if !t.Completed && !t.Archived && t.DueDate.Format("2006-01-02") == date {
+			minutes += t.EstimatedMinutes
+		}
+	}
+	return minutes
+}
+
+// formatMinutes показывает длительность как «1 ч 30 мин»
+func formatMinutes(minutes int) string {
+	switch {
+	case minutes < 60:
+		return fmt.Sprintf("%d мин", minutes)
+	case minutes%60 == 0:
+		return fmt.Sprintf("%d ч", minutes/60)
+	}
+	return fmt.Sprintf("%d ч %d мин", minutes/60, minutes%60)
+}
+
+// workloadText подписывает загрузку дня и предупреждает о перегрузке
+func workloadText(minutes, capacity int) string {
+	text := formatMinutes(minutes) + " из " + formatMinutes(capacity)
+	if capacity > 0 && minutes > capacity {
+		text = "⚠ " + text + ", перегрузка"
+	}
+	return text
+}
+
+// newWorkloadBar показывает загрузку дня полосой; перегруженный день выделяется цветом
+func newWorkloadBar(minutes, capacity int) fyne.CanvasObject {
+	bar := widget.NewProgressBar()
+	bar.Max = float64(max(capacity, 1))
+	bar.SetValue(float64(min(minutes, capacity)))
+	bar.TextFormatter = func() string { return formatMinutes(minutes) }
+	if capacity > 0 && minutes > capacity {
+		warning := widget.NewLabel("⚠ перегрузка")
+		warning.Importance = widget.DangerImportance
+		return container.NewVBox(bar, warning)
+	}
+	return bar
+}
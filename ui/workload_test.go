@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+
+	"taskmanager/task"
+)
+
+func TestDayWorkload(t *testing.T) {
+	day := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	tasks := []*task.Task{
+		{DueDate: day, EstimatedMinutes: 240},
+		{DueDate: day, EstimatedMinutes: 300},
+		{DueDate: day, EstimatedMinutes: 60, Completed: true},
+		{DueDate: day, EstimatedMinutes: 60, Archived: true},
+		{DueDate: day.AddDate(0, 0, 1), EstimatedMinutes: 30},
+	}
+	assert.Equal(t, 540, dayWorkload(tasks, "2026-10-20"))
+	assert.Equal(t, 30, dayWorkload(tasks, "2026-10-21"))
+
+	assert.Equal(t, "⚠ 9 ч из 8 ч, перегрузка", workloadText(540, 480))
+	assert.Equal(t, "45 мин из 8 ч", workloadText(45, 480))
+	assert.Equal(t, "1 ч 30 мин", formatMinutes(90))
+}
+
+func TestWorkloadCapacity(t *testing.T) {
+	a := test.NewTempApp(t)
+	assert.Equal(t, defaultWorkloadCapacity, workloadCapacity(a.Preferences()))
+	a.Preferences().SetInt(prefWorkloadCapacity, 360)
+	assert.Equal(t, 360, workloadCapacity(a.Preferences()))
+}
+
+func TestParseEstimate(t *testing.T) {
+	minutes, err := parseEstimate(" 45 ")
+	assert.NoError(t, err)
+	assert.Equal(t, 45, minutes)
+
+	minutes, err = parseEstimate("")
+	assert.NoError(t, err)
+	assert.Zero(t, minutes)
+
+	_, err = parseEstimate("-5")
+	assert.Error(t, err)
+}
@@ -0,0 +1,114 @@
+// Package update проверяет, не вышла ли новая версия приложения на GitHub
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version - версия приложения. Задается при сборке релиза:
+// go build -ldflags "-X taskmanager/update.Version=v1.2.0"
+var Version = "dev"
+
+// LatestReleaseURL - адрес GitHub API с последним релизом приложения
+const LatestReleaseURL = "https://api.github.com/repos/Zhumarradriga/GUITaskManager/releases/latest"
+
+// checkTimeout - сколько ждать ответа GitHub
+const checkTimeout = 10 * time.Second
+
+// Release описывает релиз на GitHub
+type Release struct {
+	Version string  `json:"tag_name"`
+	Name    string  `json:"name"`
+	Notes   string  `json:"body"`     // список изменений в Markdown
+	URL     string  `json:"html_url"` // страница релиза
+	Assets  []Asset `json:"assets"`
+}
+
+// Asset - файл, приложенный к релизу
+type Asset struct {
+	Name        string `json:"name"`
+	DownloadURL string `json:"browser_download_url"`
+}
+
+// DownloadURL возвращает ссылку на сборку для системы goos,
+// а если такой нет - на страницу релиза
+func (r *Release) DownloadURL(goos string) string {
+	for _, asset := range r.Assets {
+		if strings.Contains(strings.ToLower(asset.Name), goos) {
+			return asset.DownloadURL
+		}
+	}
+	return r.URL
+}
+
+// Latest запрашивает последний релиз по адресу url (обычно LatestReleaseURL)
+func Latest(ctx context.Context, url string) (*Release, error) {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response from release server: %s", resp.Status)
+	}
+
+	var release Release
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
+
+// Newer сообщает, новее ли версия latest, чем current. Версии сравниваются
+// как v1.2.3; если current не такого вида (сборка для разработки), обновлений нет
+func Newer(current, latest string) bool {
+	cur, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+	lat, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+	for i := range cur {
+		if lat[i] != cur[i] {
+			return lat[i] > cur[i]
+		}
+	}
+	return false
+}
+
+// parseVersion разбирает версию вида v1.2.3 (v и недостающие части необязательны,
+// суффикс вроде -rc1 отбрасывается)
+func parseVersion(s string) ([3]int, bool) {
+	var version [3]int
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	s, _, _ = strings.Cut(s, "-")
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return version, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return version, false
+		}
+		version[i] = n
+	}
+	return version, true
+}
@@ -0,0 +1,61 @@
+package update
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewer(t *testing.T) {
+	assert.True(t, Newer("v1.2.3", "v1.2.4"))
+	assert.True(t, Newer("v1.2.3", "v1.10.0"))
+	assert.True(t, Newer("1.2", "v2"))
+	assert.False(t, Newer("v1.2.3", "v1.2.3"))
+	assert.False(t, Newer("v1.2.3", "v1.2.2"))
+	assert.False(t, Newer("v1.2.3-rc1", "v1.2.3"))
+
+	// Сборки для разработки и непонятные версии не обновляются
+	assert.False(t, Newer("dev", "v9.9.9"))
+	assert.False(t, Newer("v1.2.3", "nightly"))
+}
+
+func TestLatest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/vnd.github+json", r.Header.Get("Accept"))
+		w.Write([]byte(`{
+			"tag_name": "v1.3.0",
+			"name": "Task Manager 1.3",
+			"body": "- Новое",
+			"html_url": "https://example.com/releases/v1.3.0",
+			"assets": [
+				{"name": "taskmanager-linux-amd64.tar.gz", "browser_download_url": "https://example.com/linux"},
+				{"name": "TaskManager-Windows.zip", "browser_download_url": "https://example.com/windows"}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	release, err := Latest(context.Background(), server.URL)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, "v1.3.0", release.Version)
+	assert.Equal(t, "- Новое", release.Notes)
+	assert.Equal(t, "https://example.com/linux", release.DownloadURL("linux"))
+	assert.Equal(t, "https://example.com/windows", release.DownloadURL("windows"))
+	// Для системы без сборки - страница релиза
+	assert.Equal(t, "https://example.com/releases/v1.3.0", release.DownloadURL("darwin"))
+}
+
+func TestLatestError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	_, err := Latest(context.Background(), server.URL)
+	assert.Error(t, err)
+}
@@ -0,0 +1,275 @@
+// Package webhooks отправляет уведомления о задачах в чаты Slack и Discord через входящие
+// вебхуки: повестку дня в заданное время и сообщения о задачах, которые стали просроченными.
+// Текст сообщений задается шаблонами text/template; пакет не зависит от интерфейса
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"taskmanager/agenda"
+	"taskmanager/task"
+)
+
+// Service - чат, в который ведет вебхук
+type Service string
+
+const (
+	Slack   Service = "slack"
+	Discord Service = "discord"
+)
+
+// Services - поддерживаемые чаты
+var Services = []Service{Slack, Discord}
+
+// Title возвращает название чата
+func (s Service) Title() string {
+	return map[Service]string{Slack: "Slack", Discord: "Discord"}[s]
+}
+
+// discordLimit - наибольшая длина сообщения Discord в символах
+const discordLimit = 2000
+
+// Шаблоны сообщений по умолчанию. Повестке передается agenda.Agenda, сообщению
+// о просрочке - OverdueData
+const (
+	DefaultAgendaTemplate  = "{{.Text}}"
+	DefaultOverdueTemplate = "⚠ Просрочена задача «{{.Task.Title}}»: срок {{.Due}}, приоритет {{priority .Task.Priority}}"
+)
+
+// ErrInvalidTarget возвращается для получателя без адреса, времени повестки или с неверным шаблоном
+var ErrInvalidTarget = errors.New("invalid webhook target")
+
+// Target - чат, куда отправляются уведомления
+type Target struct {
+	ID      string  `json:"id"`
+	Name    string  `json:"name,omitempty"`
+	Service Service `json:"service"`
+	URL     string  `json:"url"`
+
+	Agenda         bool   `json:"agenda,omitempty"`          // отправлять повестку дня
+	AgendaAt       string `json:"agenda_at,omitempty"`       // во сколько, «09:00»
+	AgendaTemplate string `json:"agenda_template,omitempty"` // пусто - DefaultAgendaTemplate
+
+	Overdue         bool   `json:"overdue,omitempty"`          // сообщать о просроченных задачах
+	OverdueTemplate string `json:"overdue_template,omitempty"` // пусто - DefaultOverdueTemplate
+}
+
+// NewTarget создает получателя с новым идентификатором
+func NewTarget(service Service, url string) Target {
+	id := make([]byte, 8)
+	rand.Read(id)
+	return Target{ID: hex.EncodeToString(id), Service: service, URL: url, AgendaAt: "09:00"}
+}
+
+// OverdueData - данные шаблона сообщения о просроченной задаче
+type OverdueData struct {
+	Task *task.Task
+	Due  string // срок задачи, 02.01.2006
+	Days int    // на сколько дней просрочена
+}
+
+var templateFuncs = template.FuncMap{"priority": task.PriorityText}
+
+// Validate проверяет адрес вебхука, время повестки и шаблоны
+func (t Target) Validate() error {
+	if t.Service.Title() == "" {
+		return fmt.Errorf("%w: unknown service %q", ErrInvalidTarget, t.Service)
+	}
+	if u, err := url.Parse(t.URL); err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("%w: webhook url must be an https address", ErrInvalidTarget)
+	}
+	if t.Agenda {
+		if _, err := time.Parse("15:04", t.AgendaAt); err != nil {
+			return fmt.Errorf("%w: agenda time must look like 09:00", ErrInvalidTarget)
+		}
+	}
+	for _, text := range []string{t.AgendaTemplate, t.OverdueTemplate} {
+		if _, err := template.New("").Funcs(templateFuncs).Parse(text); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidTarget, err)
+		}
+	}
+	return nil
+}
+
+// Describe описывает получателя для списка
+func (t Target) Describe() string {
+	var what []string
+	if t.Agenda {
+		what = append(what, "повестка в "+t.AgendaAt)
+	}
+	if t.Overdue {
+		what = append(what, "просрочки")
+	}
+	if len(what) == 0 {
+		what = append(what, "выключено")
+	}
+	name := t.Name
+	if name == "" {
+		name = t.Service.Title()
+	}
+	return name + ": " + strings.Join(what, ", ")
+}
+
+// render выполняет шаблон text или шаблон по умолчанию fallback
+func render(text, fallback string, data any) (string, error) {
+	if strings.TrimSpace(text) == "" {
+		text = fallback
+	}
+	tmpl, err := template.New("").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// AgendaMessage возвращает текст повестки по шаблону получателя
+func (t Target) AgendaMessage(day agenda.Agenda) (string, error) {
+	return render(t.AgendaTemplate, DefaultAgendaTemplate, day)
+}
+
+// OverdueMessage возвращает текст сообщения о просроченной задаче по шаблону получателя
+func (t Target) OverdueMessage(overdue *task.Task, now time.Time) (string, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	due := time.Date(overdue.DueDate.Year(), overdue.DueDate.Month(), overdue.DueDate.Day(), 0, 0, 0, 0, time.UTC)
+	return render(t.OverdueTemplate, DefaultOverdueTemplate, OverdueData{
+		Task: overdue,
+		Due:  overdue.DueDate.Format("02.01.2006"),
+		Days: int(today.Sub(due).Hours() / 24),
+	})
+}
+
+// Post отправляет сообщение text в чат получателя. Слишком длинные для Discord
+// сообщения обрезаются
+func Post(ctx context.Context, client *http.Client, target Target, text string) error {
+	var payload any
+	switch target.Service {
+	case Discord:
+		if runes := []rune(text); len(runes) > discordLimit {
+			text = string(runes[:discordLimit-1]) + "…"
+		}
+		payload = map[string]string{"content": text}
+	default:
+		payload = map[string]string{"text": text}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s webhook: %s: %s", target.Service, resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// Message - сообщение, которое пора отправить
+type Message struct {
+	Target Target
+	Text   string
+}
+
+// State - что уже отправлено: повестки по дням и сообщения о просрочках.
+// Хранится в настройках, чтобы после перезапуска сообщения не повторялись
+type State struct {
+	AgendaSent  map[string]string `json:"agenda_sent,omitempty"`  // ID получателя → день последней повестки
+	OverdueSent map[string]bool   `json:"overdue_sent,omitempty"` // получатель/UUID/срок
+}
+
+// Due возвращает сообщения, которые пора отправить в момент now, и отмечает их отправленными.
+// Повестка отправляется раз в день начиная со своего времени; о задаче, ставшей просроченной,
+// сообщается один раз для каждого срока, все новые просрочки - одним сообщением
+func (s *State) Due(targets []Target, tasks []*task.Task, now time.Time, sections []agenda.Section) ([]Message, error) {
+	if s.AgendaSent == nil {
+		s.AgendaSent = make(map[string]string)
+	}
+	today := now.Format(task.DueDateLayout)
+	overdueSent := make(map[string]bool)
+	var messages []Message
+	var errs []error
+
+	for _, target := range targets {
+		if target.Agenda && s.AgendaSent[target.ID] != today {
+			at, err := time.ParseInLocation("15:04", target.AgendaAt, now.Location())
+			if err == nil && now.Hour()*60+now.Minute() >= at.Hour()*60+at.Minute() {
+				text, err := target.AgendaMessage(agenda.Build(tasks, now, sections))
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", target.Describe(), err))
+				} else {
+					messages = append(messages, Message{Target: target, Text: text})
+				}
+				s.AgendaSent[target.ID] = today
+			}
+		}
+
+		if !target.Overdue {
+			continue
+		}
+		var lines []string
+		for _, t := range tasks {
+			if t.Archived || !t.IsOverdue(now) {
+				continue
+			}
+			key := target.ID + "/" + t.UUID + "/" + t.DueDate.Format(task.DueDateLayout)
+			overdueSent[key] = true
+			if s.OverdueSent[key] {
+				continue
+			}
+			text, err := target.OverdueMessage(t, now)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", target.Describe(), err))
+				continue
+			}
+			lines = append(lines, text)
+		}
+		if len(lines) > 0 {
+			messages = append(messages, Message{Target: target, Text: strings.Join(lines, "\n")})
+		}
+	}
+	// Задачи, которые выполнили или перенесли, больше не храним
+	s.OverdueSent = overdueSent
+	return messages, errors.Join(errs...)
+}
+
+// Parse читает получателей, записанных Marshal; пустая строка - получателей нет
+func Parse(data string) ([]Target, error) {
+	if data == "" {
+		return nil, nil
+	}
+	var list []Target
+	if err := json.Unmarshal([]byte(data), &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Marshal записывает получателей для хранения в настройках
+func Marshal(list []Target) string {
+	data, _ := json.Marshal(list)
+	return string(data)
+}
@@ -0,0 +1,141 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"taskmanager/agenda"
+	"taskmanager/task"
+)
+
+func day(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC) }
+
+func TestValidate(t *testing.T) {
+	target := NewTarget(Slack, "https://hooks.slack.com/services/T/B/X")
+	assert.NoError(t, target.Validate())
+
+	invalid := target
+	invalid.URL = "http://hooks.slack.com"
+	assert.ErrorIs(t, invalid.Validate(), ErrInvalidTarget)
+
+	invalid = target
+	invalid.Agenda, invalid.AgendaAt = true, "9 утра"
+	assert.ErrorIs(t, invalid.Validate(), ErrInvalidTarget)
+
+	invalid = target
+	invalid.OverdueTemplate = "{{.Task.Title"
+	assert.ErrorIs(t, invalid.Validate(), ErrInvalidTarget)
+}
+
+func TestMessages(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	overdue := &task.Task{Title: "Отчет", Priority: 3, DueDate: day(13)}
+
+	target := NewTarget(Discord, "https://discord.com/api/webhooks/1/x")
+	text, err := target.OverdueMessage(overdue, now)
+	require.NoError(t, err)
+	assert.Equal(t, "⚠ Просрочена задача «Отчет»: срок 13.10.2026, приоритет высокий", text)
+
+	target.OverdueTemplate = "{{.Task.Title}} просрочена на {{.Days}} дн."
+	text, err = target.OverdueMessage(overdue, now)
+	require.NoError(t, err)
+	assert.Equal(t, "Отчет просрочена на 3 дн.", text)
+
+	day := agenda.Build([]*task.Task{overdue}, now, agenda.DefaultSections)
+	text, err = target.AgendaMessage(day)
+	require.NoError(t, err)
+	assert.Equal(t, day.Text(), text)
+
+	target.AgendaTemplate = "{{.Title}}: {{.Len}}"
+	text, err = target.AgendaMessage(day)
+	require.NoError(t, err)
+	assert.Equal(t, "Повестка на 16.10.2026: 1", text)
+}
+
+func TestStateDue(t *testing.T) {
+	tasks := []*task.Task{
+		{UUID: "a", Title: "Отчет", Priority: 2, DueDate: day(14)},
+		{UUID: "b", Title: "Счет", Priority: 2, DueDate: day(15)},
+		{UUID: "c", Title: "Старое", Priority: 2, DueDate: day(1), Archived: true},
+		{UUID: "d", Title: "Сегодня", Priority: 2, DueDate: day(16)},
+	}
+	slack := NewTarget(Slack, "https://hooks.slack.com/x")
+	slack.Agenda, slack.AgendaAt = true, "09:00"
+	discord := NewTarget(Discord, "https://discord.com/x")
+	discord.Overdue = true
+	targets := []Target{slack, discord}
+
+	var state State
+	at := func(hour int) time.Time { return time.Date(2026, 10, 16, hour, 0, 0, 0, time.UTC) }
+
+	// До времени повестки - только просрочки, все новые одним сообщением
+	messages, err := state.Due(targets, tasks, at(8), agenda.DefaultSections)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, discord.ID, messages[0].Target.ID)
+	assert.Equal(t, 2, strings.Count(messages[0].Text, "\n")+1)
+
+	messages, err = state.Due(targets, tasks, at(10), agenda.DefaultSections)
+	require.NoError(t, err)
+	require.Len(t, messages, 1, "повестка в свое время, просрочки не повторяются")
+	assert.Equal(t, slack.ID, messages[0].Target.ID)
+	assert.Contains(t, messages[0].Text, "Повестка на 16.10.2026")
+
+	messages, err = state.Due(targets, tasks, at(11), agenda.DefaultSections)
+	require.NoError(t, err)
+	assert.Empty(t, messages, "повестка - раз в день")
+
+	// Перенесенная задача снова просрочилась - о ней сообщается еще раз
+	tasks[0].DueDate = day(15)
+	messages, err = state.Due(targets, tasks, at(12), agenda.DefaultSections)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Contains(t, messages[0].Text, "Отчет")
+	assert.NotContains(t, messages[0].Text, "Счет")
+	assert.Len(t, state.OverdueSent, 2)
+}
+
+func TestPost(t *testing.T) {
+	var payloads []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var payload map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		payloads = append(payloads, payload)
+		if strings.HasSuffix(r.URL.Path, "/gone") {
+			http.Error(w, "no_service", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	require.NoError(t, Post(ctx, server.Client(), Target{Service: Slack, URL: server.URL}, "Привет"))
+	require.NoError(t, Post(ctx, server.Client(), Target{Service: Discord, URL: server.URL}, strings.Repeat("я", 2500)))
+	require.Len(t, payloads, 2)
+	assert.Equal(t, map[string]string{"text": "Привет"}, payloads[0])
+	assert.Len(t, []rune(payloads[1]["content"]), discordLimit)
+
+	err := Post(ctx, server.Client(), Target{Service: Slack, URL: server.URL + "/gone"}, "Привет")
+	assert.ErrorContains(t, err, "no_service")
+}
+
+func TestParseMarshal(t *testing.T) {
+	list, err := Parse("")
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	target := NewTarget(Slack, "https://hooks.slack.com/x")
+	target.Overdue = true
+	list, err = Parse(Marshal([]Target{target}))
+	require.NoError(t, err)
+	assert.Equal(t, []Target{target}, list)
+	assert.Equal(t, "Slack: просрочки", target.Describe())
+}